@@ -1,9 +1,15 @@
 module tools
 
-go 1.14
+go 1.19
 
 require (
 	golang.org/x/lint v0.0.0-20200302205851-738671d3881b
 	golang.org/x/tools v0.0.0-20210101214203-2dba1e4ea05c
 	mvdan.cc/gofumpt v0.1.0
 )
+
+require (
+	github.com/google/go-cmp v0.5.4 // indirect
+	golang.org/x/mod v0.4.0 // indirect
+	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
+)
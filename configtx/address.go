@@ -0,0 +1,132 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// ParseAddress parses an endpoint of the form host:port, as used by orderer
+// endpoints, into a normalized address. IPv6 literals must be bracketed, as in
+// [::1]:7050. Endpoints with a scheme, such as grpcs://orderer:7050, are
+// rejected, since Fabric expects bare host:port endpoints.
+func ParseAddress(endpoint string) (Address, error) {
+	endpoint = strings.TrimSpace(endpoint)
+	if strings.Contains(endpoint, "://") {
+		return Address{}, fmt.Errorf("endpoint %s must not contain a scheme", endpoint)
+	}
+
+	host, portStr, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return Address{}, fmt.Errorf("unable to parse host and port from %s: %v", endpoint, err)
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid port '%s' of endpoint %s", portStr, endpoint)
+	}
+
+	address := Address{Host: host, Port: port}.Normalized()
+	err = address.Validate()
+	if err != nil {
+		return Address{}, err
+	}
+
+	return address, nil
+}
+
+// Validate checks that the address has a host and a port in the valid range
+// and that the host is a bare hostname or IP address. IPv6 literals may be
+// bracketed. Hosts with a scheme, a port or a path are rejected.
+func (a Address) Validate() error {
+	host := a.Host
+	if host == "" {
+		return errors.New("host is required")
+	}
+
+	if a.Port <= 0 || a.Port > 65535 {
+		return fmt.Errorf("invalid port %d for host %s", a.Port, host)
+	}
+
+	if strings.Contains(host, "://") {
+		return fmt.Errorf("host %s must not contain a scheme", host)
+	}
+
+	if strings.HasPrefix(host, "[") || strings.HasSuffix(host, "]") {
+		ip := net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]"))
+		if !strings.HasPrefix(host, "[") || !strings.HasSuffix(host, "]") || ip == nil || ip.To4() != nil {
+			return fmt.Errorf("host %s is not a valid bracketed IPv6 address", host)
+		}
+		return nil
+	}
+
+	if net.ParseIP(host) != nil {
+		return nil
+	}
+
+	if strings.Contains(host, ":") {
+		return fmt.Errorf("host %s must not contain a port", host)
+	}
+
+	for _, r := range host {
+		if !validHostnameRune(r) {
+			return fmt.Errorf("host %s contains invalid character '%c'", host, r)
+		}
+	}
+
+	for _, label := range strings.Split(host, ".") {
+		if label == "" {
+			return fmt.Errorf("host %s contains an empty label", host)
+		}
+	}
+
+	return nil
+}
+
+// Normalized returns the address with surrounding whitespace trimmed from the
+// host, the brackets of an IPv6 literal removed and a hostname lowercased, the
+// form in which hosts are stored in the config.
+func (a Address) Normalized() Address {
+	host := strings.TrimSpace(a.Host)
+
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		unbracketed := host[1 : len(host)-1]
+		if ip := net.ParseIP(unbracketed); ip != nil && ip.To4() == nil {
+			host = unbracketed
+		}
+	}
+
+	return Address{
+		Host: strings.ToLower(host),
+		Port: a.Port,
+	}
+}
+
+// hostPort returns the endpoint of the host and port in host:port form,
+// bracketing IPv6 literals.
+func hostPort(host string, port int) string {
+	return net.JoinHostPort(host, strconv.Itoa(port))
+}
+
+// normalizedEndpoint returns the endpoint in normalized host:port form, or
+// the endpoint as is if it cannot be parsed.
+func normalizedEndpoint(endpoint string) string {
+	address, err := ParseAddress(endpoint)
+	if err != nil {
+		return endpoint
+	}
+
+	return hostPort(address.Host, address.Port)
+}
+
+func validHostnameRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '.' || r == '_'
+}
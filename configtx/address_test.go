@@ -0,0 +1,267 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	pb "github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestParseAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		endpoint        string
+		expectedAddress Address
+		expectedErr     string
+	}{
+		{
+			endpoint:        "orderer.example.com:7050",
+			expectedAddress: Address{Host: "orderer.example.com", Port: 7050},
+		},
+		{
+			endpoint:        " Orderer.Example.com:7050 ",
+			expectedAddress: Address{Host: "orderer.example.com", Port: 7050},
+		},
+		{
+			endpoint:        "127.0.0.1:7050",
+			expectedAddress: Address{Host: "127.0.0.1", Port: 7050},
+		},
+		{
+			endpoint:        "[2001:DB8::1]:7050",
+			expectedAddress: Address{Host: "2001:db8::1", Port: 7050},
+		},
+		{
+			endpoint:    "grpcs://orderer.example.com:7050",
+			expectedErr: "endpoint grpcs://orderer.example.com:7050 must not contain a scheme",
+		},
+		{
+			endpoint:    "orderer.example.com",
+			expectedErr: "unable to parse host and port from orderer.example.com: address orderer.example.com: missing port in address",
+		},
+		{
+			endpoint:    "2001:db8::1:7050",
+			expectedErr: "unable to parse host and port from 2001:db8::1:7050: address 2001:db8::1:7050: too many colons in address",
+		},
+		{
+			endpoint:    "orderer.example.com:grpc",
+			expectedErr: "invalid port 'grpc' of endpoint orderer.example.com:grpc",
+		},
+		{
+			endpoint:    "orderer.example.com:70500",
+			expectedErr: "invalid port 70500 for host orderer.example.com",
+		},
+		{
+			endpoint:    "orderer/example:7050",
+			expectedErr: "host orderer/example contains invalid character '/'",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.endpoint, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			address, err := ParseAddress(tt.endpoint)
+			if tt.expectedErr != "" {
+				gt.Expect(err).To(MatchError(tt.expectedErr))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(address).To(Equal(tt.expectedAddress))
+		})
+	}
+}
+
+func TestAddressValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		address     Address
+		expectedErr string
+	}{
+		{
+			testName: "When the host is a hostname",
+			address:  Address{Host: "peer0.org1.example.com", Port: 7051},
+		},
+		{
+			testName: "When the host is a bare IPv6 address",
+			address:  Address{Host: "::1", Port: 7051},
+		},
+		{
+			testName: "When the host is a bracketed IPv6 address",
+			address:  Address{Host: "[::1]", Port: 7051},
+		},
+		{
+			testName:    "When the host is missing",
+			address:     Address{Port: 7051},
+			expectedErr: "host is required",
+		},
+		{
+			testName:    "When the port is out of range",
+			address:     Address{Host: "peer0", Port: 0},
+			expectedErr: "invalid port 0 for host peer0",
+		},
+		{
+			testName:    "When the host contains a scheme",
+			address:     Address{Host: "grpcs://peer0", Port: 7051},
+			expectedErr: "host grpcs://peer0 must not contain a scheme",
+		},
+		{
+			testName:    "When the host contains a port",
+			address:     Address{Host: "peer0:7051", Port: 7051},
+			expectedErr: "host peer0:7051 must not contain a port",
+		},
+		{
+			testName:    "When a bracketed host is not an IPv6 address",
+			address:     Address{Host: "[127.0.0.1]", Port: 7051},
+			expectedErr: "host [127.0.0.1] is not a valid bracketed IPv6 address",
+		},
+		{
+			testName:    "When the brackets of a host are unbalanced",
+			address:     Address{Host: "[::1", Port: 7051},
+			expectedErr: "host [::1 is not a valid bracketed IPv6 address",
+		},
+		{
+			testName:    "When the host contains whitespace",
+			address:     Address{Host: "peer0 org1", Port: 7051},
+			expectedErr: "host peer0 org1 contains invalid character ' '",
+		},
+		{
+			testName:    "When the host contains an empty label",
+			address:     Address{Host: "peer0..org1", Port: 7051},
+			expectedErr: "host peer0..org1 contains an empty label",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			err := tt.address.Validate()
+			if tt.expectedErr != "" {
+				gt.Expect(err).To(MatchError(tt.expectedErr))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestAddressNormalized(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	gt.Expect(Address{Host: " Peer0.Org1 ", Port: 7051}.Normalized()).To(Equal(Address{Host: "peer0.org1", Port: 7051}))
+	gt.Expect(Address{Host: "[2001:DB8::1]", Port: 7051}.Normalized()).To(Equal(Address{Host: "2001:db8::1", Port: 7051}))
+	gt.Expect(Address{Host: "[127.0.0.1]", Port: 7051}.Normalized()).To(Equal(Address{Host: "[127.0.0.1]", Port: 7051}))
+}
+
+func TestEndpointValidation(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	c := ordererConfigTx(t, etcdRaftOrderer)
+	ordererOrg := c.Orderer().Organization("OrdererOrg")
+
+	err := ordererOrg.SetEndpoint(Address{Host: "grpcs://orderer.example.com", Port: 7050})
+	gt.Expect(err).To(MatchError("invalid endpoint for orderer org OrdererOrg: host grpcs://orderer.example.com must not contain a scheme"))
+
+	err = ordererOrg.SetEndpoint(Address{Host: "[2001:DB8::1]", Port: 7050})
+	gt.Expect(err).NotTo(HaveOccurred())
+	org, err := ordererOrg.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.OrdererEndpoints).To(ContainElement("[2001:db8::1]:7050"))
+
+	err = ordererOrg.RemoveEndpoint(Address{Host: "2001:db8::1", Port: 7050})
+	gt.Expect(err).NotTo(HaveOccurred())
+	org, err = ordererOrg.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.OrdererEndpoints).NotTo(ContainElement("[2001:db8::1]:7050"))
+
+	err = c.Orderer().AddConsenter(orderer.Consenter{
+		Address: orderer.EtcdAddress{Host: "node-4.example.com:7050", Port: 7050},
+	})
+	gt.Expect(err).To(MatchError("invalid consenter address: host node-4.example.com:7050 must not contain a port"))
+
+	app := applyConfigTx(t)
+	err = app.Application().Organization("Org1").AddAnchorPeer(Address{Host: "https://peer0.org1", Port: 7051})
+	gt.Expect(err).To(MatchError("invalid anchor peer for org Org1: host https://peer0.org1 must not contain a scheme"))
+
+	err = app.Application().Organization("Org1").AddAnchorPeer(Address{Host: "Peer0.Org1", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	anchorPeers, err := app.Application().Organization("Org1").AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(anchorPeers).To(ContainElement(Address{Host: "peer0.org1", Port: 7051}))
+
+	org.OrdererEndpoints = []string{"grpcs://orderer.example.com:7050"}
+	err = org.Validate()
+	gt.Expect(err).To(MatchError("org OrdererOrg: invalid orderer endpoint: endpoint grpcs://orderer.example.com:7050 must not contain a scheme"))
+
+	org.OrdererEndpoints = nil
+	org.AnchorPeers = []Address{{Host: "peer0.org1:7051", Port: 7051}}
+	err = org.Validate()
+	gt.Expect(err).To(MatchError("org OrdererOrg: invalid anchor peer: host peer0.org1:7051 must not contain a port"))
+}
+
+func TestMixedCaseAddresses(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	c := ordererConfigTx(t, etcdRaftOrderer)
+	ordererOrgGroup := c.updated.ChannelGroup.Groups[OrdererGroupKey].Groups["OrdererOrg"]
+	err := setValue(ordererOrgGroup, endpointsValue([]string{"Orderer0.Example.com:7050", "orderer1.example.com:7050"}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Orderer().Organization("OrdererOrg").SetEndpoint(Address{Host: "orderer0.example.com", Port: 7050})
+	gt.Expect(err).NotTo(HaveOccurred())
+	org, err := c.Orderer().Organization("OrdererOrg").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.OrdererEndpoints).To(Equal([]string{"Orderer0.Example.com:7050", "orderer1.example.com:7050"}))
+
+	err = c.Orderer().Organization("OrdererOrg").RemoveEndpoint(Address{Host: "ORDERER0.example.com", Port: 7050})
+	gt.Expect(err).NotTo(HaveOccurred())
+	org, err = c.Orderer().Organization("OrdererOrg").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.OrdererEndpoints).To(Equal([]string{"orderer1.example.com:7050"}))
+
+	consenter := etcdRaftOrderer.EtcdRaft.Consenters[0]
+	consenter.Address = orderer.EtcdAddress{Host: "Node-4.Example.com", Port: 7050}
+	err = c.Orderer().AddConsenter(consenter)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().AddConsenter(consenter)
+	gt.Expect(err).NotTo(HaveOccurred())
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.EtcdRaft.Consenters).To(HaveLen(len(etcdRaftOrderer.EtcdRaft.Consenters) + 1))
+
+	err = c.Orderer().RemoveConsenter(consenter)
+	gt.Expect(err).NotTo(HaveOccurred())
+	ordererConfig, err = c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.EtcdRaft.Consenters).To(Equal(etcdRaftOrderer.EtcdRaft.Consenters))
+
+	app := applyConfigTx(t)
+	orgGroup := app.updated.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"]
+	err = setValue(orgGroup, anchorPeersValue([]*pb.AnchorPeer{{Host: "Peer0.Org1.example.com", Port: 7051}}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = app.Application().Organization("Org1").RemoveAnchorPeer(Address{Host: "PEER0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	anchorPeers, err := app.Application().Organization("Org1").AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(anchorPeers).To(BeEmpty())
+}
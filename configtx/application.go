@@ -9,6 +9,9 @@ package configtx
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 	pb "github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
@@ -28,7 +31,12 @@ type Application struct {
 // ApplicationGroup encapsulates the part of the config that controls
 // application channels.
 type ApplicationGroup struct {
+	channelGroup     *cb.ConfigGroup
 	applicationGroup *cb.ConfigGroup
+	// skipSubPolicyChecks disables the checks of the ImplicitMeta
+	// sub-policies, see WithoutSubPolicyChecks.
+	skipSubPolicyChecks bool
+	observed            observed
 }
 
 // ApplicationOrg encapsulates the parts of the config that control
@@ -36,19 +44,104 @@ type ApplicationGroup struct {
 type ApplicationOrg struct {
 	orgGroup *cb.ConfigGroup
 	name     string
+	observed observed
 }
 
 // MSP returns an OrganizationMSP object that can be used to configure the organization's MSP.
 func (a *ApplicationOrg) MSP() *OrganizationMSP {
 	return &OrganizationMSP{
 		configGroup: a.orgGroup,
+		observed:    a.observed.child("Values/" + MSPKey),
 	}
 }
 
+// Validate checks that the application configuration defines the standard
+// policies, that its organizations are valid and unique, and that every ACL
+// references a policy.
+func (a Application) Validate() error {
+	if err := validatePolicies(a.Policies); err != nil {
+		return err
+	}
+
+	names := map[string]struct{}{}
+	for _, org := range a.Organizations {
+		if err := org.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := names[org.Name]; ok {
+			return fmt.Errorf("duplicate application org %s", org.Name)
+		}
+		names[org.Name] = struct{}{}
+	}
+
+	for apiResource, policyRef := range a.ACLs {
+		if policyRef == "" {
+			return fmt.Errorf("policy reference for ACL '%s' is required", apiResource)
+		}
+	}
+
+	return nil
+}
+
 // Application returns the application group the updated config.
 func (c *ConfigTx) Application() *ApplicationGroup {
-	applicationGroup := c.updated.ChannelGroup.Groups[ApplicationGroupKey]
-	return &ApplicationGroup{applicationGroup: applicationGroup}
+	channelGroup := c.updated.ChannelGroup
+	applicationGroup := channelGroup.Groups[ApplicationGroupKey]
+	return &ApplicationGroup{
+		channelGroup:     channelGroup,
+		applicationGroup: applicationGroup,
+		observed:         c.observed().child(ApplicationGroupKey),
+	}
+}
+
+// SetApplication adds an application group built from the given
+// application configuration, including its organizations, to an updated
+// config which does not contain one.
+func (c *ConfigTx) SetApplication(application Application) (err error) {
+	defer c.observe("ConfigTx.SetApplication", time.Now(), &err)
+
+	if _, ok := c.updated.ChannelGroup.Groups[ApplicationGroupKey]; ok {
+		return errors.New("application group already exists")
+	}
+
+	err = application.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid application: %v", err)
+	}
+
+	applicationGroup, err := newApplicationGroupTemplate(application)
+	if err != nil {
+		return fmt.Errorf("failed to create application group: %v", err)
+	}
+
+	for _, org := range application.Organizations {
+		applicationGroup.Groups[org.Name], err = newApplicationOrgConfigGroup(org)
+		if err != nil {
+			return fmt.Errorf("failed to create application org %s: %v", org.Name, err)
+		}
+	}
+
+	if c.updated.ChannelGroup.Groups == nil {
+		c.updated.ChannelGroup.Groups = map[string]*cb.ConfigGroup{}
+	}
+	c.updated.ChannelGroup.Groups[ApplicationGroupKey] = applicationGroup
+
+	return nil
+}
+
+// RemoveApplication removes the application group, including all application
+// orgs, from the updated config.
+func (c *ConfigTx) RemoveApplication() (err error) {
+	defer c.observe("ConfigTx.RemoveApplication", time.Now(), &err)
+
+	if _, ok := c.updated.ChannelGroup.Groups[ApplicationGroupKey]; !ok {
+		return errors.New("application group does not exist")
+	}
+
+	delete(c.updated.ChannelGroup.Groups, ApplicationGroupKey)
+
+	return nil
 }
 
 // Organization returns the application org from the updated config.
@@ -57,13 +150,15 @@ func (a *ApplicationGroup) Organization(name string) *ApplicationOrg {
 	if !ok {
 		return nil
 	}
-	return &ApplicationOrg{name: name, orgGroup: organizationGroup}
+	return &ApplicationOrg{name: name, orgGroup: organizationGroup, observed: a.observed.child(name)}
 }
 
 // SetOrganization sets the organization config group for the given application
 // org key in an existing Application configuration's Groups map.
 // If the application org already exists in the current configuration, its value will be overwritten.
-func (a *ApplicationGroup) SetOrganization(org Organization) error {
+func (a *ApplicationGroup) SetOrganization(org Organization) (err error) {
+	defer a.observe("ApplicationGroup.SetOrganization", time.Now(), &err)
+
 	orgGroup, err := newApplicationOrgConfigGroup(org)
 	if err != nil {
 		return fmt.Errorf("failed to create application org %s: %v", org.Name, err)
@@ -76,7 +171,11 @@ func (a *ApplicationGroup) SetOrganization(org Organization) error {
 
 // RemoveOrganization removes an org from the Application group.
 // Removal will panic if the application group does not exist.
+// A warning is logged for every policy the removal leaves unsatisfiable.
 func (a *ApplicationGroup) RemoveOrganization(orgName string) {
+	defer a.observe("ApplicationGroup.RemoveOrganization", time.Now(), nil)
+	defer a.observed.watchSatisfiability(a.channelGroup)()
+
 	delete(a.applicationGroup.Groups, orgName)
 }
 
@@ -85,7 +184,7 @@ func (a *ApplicationGroup) RemoveOrganization(orgName string) {
 // prior to updating the application configuration.
 func (a *ApplicationGroup) Configuration() (Application, error) {
 	var applicationOrgs []Organization
-	for orgName := range a.applicationGroup.Groups {
+	for _, orgName := range sortedGroupKeys(a.applicationGroup) {
 		orgConfig, err := a.Organization(orgName).Configuration()
 		if err != nil {
 			return Application{}, fmt.Errorf("retrieving application org %s: %v", orgName, err)
@@ -114,6 +213,7 @@ func (a *ApplicationGroup) Configuration() (Application, error) {
 		Capabilities:  capabilities,
 		Policies:      policies,
 		ACLs:          acls,
+		ModPolicy:     a.applicationGroup.GetModPolicy(),
 	}, nil
 }
 
@@ -141,7 +241,9 @@ func (a *ApplicationGroup) Capabilities() ([]string, error) {
 // AddCapability sets capability to the provided channel config.
 // If the provided capability already exists in current configuration, this action
 // will be a no-op.
-func (a *ApplicationGroup) AddCapability(capability string) error {
+func (a *ApplicationGroup) AddCapability(capability string) (err error) {
+	defer a.observe("ApplicationGroup.AddCapability", time.Now(), &err)
+
 	capabilities, err := a.Capabilities()
 	if err != nil {
 		return err
@@ -156,7 +258,9 @@ func (a *ApplicationGroup) AddCapability(capability string) error {
 }
 
 // RemoveCapability removes capability to the provided channel config.
-func (a *ApplicationGroup) RemoveCapability(capability string) error {
+func (a *ApplicationGroup) RemoveCapability(capability string) (err error) {
+	defer a.observe("ApplicationGroup.RemoveCapability", time.Now(), &err)
+
 	capabilities, err := a.Capabilities()
 	if err != nil {
 		return err
@@ -177,7 +281,9 @@ func (a *ApplicationGroup) Policies() (map[string]Policy, error) {
 }
 
 // SetModPolicy sets the specified modification policy for the application group.
-func (a *ApplicationGroup) SetModPolicy(modPolicy string) error {
+func (a *ApplicationGroup) SetModPolicy(modPolicy string) (err error) {
+	defer a.observe("ApplicationGroup.SetModPolicy", time.Now(), &err)
+
 	if modPolicy == "" {
 		return errors.New("non empty mod policy is required")
 	}
@@ -187,10 +293,29 @@ func (a *ApplicationGroup) SetModPolicy(modPolicy string) error {
 	return nil
 }
 
+// WithoutSubPolicyChecks returns a copy of the application group whose
+// SetPolicy and SetPolicies methods accept ImplicitMeta policies whose
+// sub-policy is missing in the application orgs. This is needed to set such a
+// policy before the policy it refers to is added to the orgs.
+func (a *ApplicationGroup) WithoutSubPolicyChecks() *ApplicationGroup {
+	unchecked := *a
+	unchecked.skipSubPolicyChecks = true
+
+	return &unchecked
+}
+
 // SetPolicy sets the specified policy in the application group's config policy map.
 // If the policy already exists in current configuration, its value will be overwritten.
-func (a *ApplicationGroup) SetPolicy(policyName string, policy Policy) error {
-	err := setPolicy(a.applicationGroup, policyName, policy)
+// The sub-policy of an ImplicitMeta policy must be defined by the application
+// orgs: by one of them for an ANY rule and by every org defining policies for
+// an ALL or MAJORITY rule.
+func (a *ApplicationGroup) SetPolicy(policyName string, policy Policy) (err error) {
+	defer a.observe("ApplicationGroup.SetPolicy", time.Now(), &err)
+
+	err = a.checkSubPolicies(map[string]Policy{policyName: policy})
+	if err == nil {
+		err = setPolicy(a.applicationGroup, policyName, policy)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to set policy '%s': %v", policyName, err)
 	}
@@ -200,8 +325,14 @@ func (a *ApplicationGroup) SetPolicy(policyName string, policy Policy) error {
 
 // SetPolicies sets the specified policies in the application group's config policy map.
 // If the policies already exist in current configuration, the values will be replaced with new policies.
-func (a *ApplicationGroup) SetPolicies(policies map[string]Policy) error {
-	err := setPolicies(a.applicationGroup, policies)
+// ImplicitMeta policies are checked as by SetPolicy.
+func (a *ApplicationGroup) SetPolicies(policies map[string]Policy) (err error) {
+	defer a.observe("ApplicationGroup.SetPolicies", time.Now(), &err)
+
+	err = a.checkSubPolicies(policies)
+	if err == nil {
+		err = setPolicies(a.applicationGroup, policies)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to set policies: %v", err)
 	}
@@ -209,9 +340,19 @@ func (a *ApplicationGroup) SetPolicies(policies map[string]Policy) error {
 	return nil
 }
 
+func (a *ApplicationGroup) checkSubPolicies(policies map[string]Policy) error {
+	if a.skipSubPolicyChecks {
+		return nil
+	}
+
+	return checkSubPolicies(a.applicationGroup, policies)
+}
+
 // RemovePolicy removes an existing policy from an application's configuration.
 // Removal will panic if the application group does not exist.
-func (a *ApplicationGroup) RemovePolicy(policyName string) error {
+func (a *ApplicationGroup) RemovePolicy(policyName string) (err error) {
+	defer a.observe("ApplicationGroup.RemovePolicy", time.Now(), &err)
+
 	policies, err := a.Policies()
 	if err != nil {
 		return err
@@ -228,7 +369,9 @@ func (a *ApplicationOrg) Policies() (map[string]Policy, error) {
 }
 
 // SetModPolicy sets the specified modification policy for the application organization group.
-func (a *ApplicationOrg) SetModPolicy(modPolicy string) error {
+func (a *ApplicationOrg) SetModPolicy(modPolicy string) (err error) {
+	defer a.observe("ApplicationOrg.SetModPolicy", time.Now(), &err)
+
 	if modPolicy == "" {
 		return errors.New("non empty mod policy is required")
 	}
@@ -240,8 +383,26 @@ func (a *ApplicationOrg) SetModPolicy(modPolicy string) error {
 
 // SetPolicy sets the specified policy in the application org group's config policy map.
 // If an Organization policy already exists in current configuration, its value will be overwritten.
-func (a *ApplicationOrg) SetPolicy(policyName string, policy Policy) error {
-	err := setPolicy(a.orgGroup, policyName, policy)
+func (a *ApplicationOrg) SetPolicy(policyName string, policy Policy) (err error) {
+	defer a.observe("ApplicationOrg.SetPolicy", time.Now(), &err)
+
+	err = setPolicy(a.orgGroup, policyName, policy)
+	if err != nil {
+		return fmt.Errorf("failed to set policy '%s': %v", policyName, err)
+	}
+
+	return nil
+}
+
+// SetSignaturePolicy sets the signature policy of the envelope in the
+// application org group's config policy map, overwriting an existing policy
+// of that name. Unlike SetPolicy it accepts principals that the policy DSL
+// cannot express, such as the OU and identity principals of an envelope built
+// with SignatureRule.Envelope. An empty mod policy defaults to Admins.
+func (a *ApplicationOrg) SetSignaturePolicy(policyName string, envelope *cb.SignaturePolicyEnvelope, modPolicy string) (err error) {
+	defer a.observe("ApplicationOrg.SetSignaturePolicy", time.Now(), &err)
+
+	err = setSignaturePolicyEnvelope(a.orgGroup, policyName, envelope, modPolicy)
 	if err != nil {
 		return fmt.Errorf("failed to set policy '%s': %v", policyName, err)
 	}
@@ -251,8 +412,10 @@ func (a *ApplicationOrg) SetPolicy(policyName string, policy Policy) error {
 
 // SetPolicies sets the specified policies in the application org group's config policy map.
 // If the policies already exist in current configuration, the values will be replaced with new policies.
-func (a *ApplicationOrg) SetPolicies(policies map[string]Policy) error {
-	err := setPolicies(a.orgGroup, policies)
+func (a *ApplicationOrg) SetPolicies(policies map[string]Policy) (err error) {
+	defer a.observe("ApplicationOrg.SetPolicies", time.Now(), &err)
+
+	err = setPolicies(a.orgGroup, policies)
 	if err != nil {
 		return fmt.Errorf("failed to set policies: %v", err)
 	}
@@ -260,8 +423,28 @@ func (a *ApplicationOrg) SetPolicies(policies map[string]Policy) error {
 	return nil
 }
 
+// SetDefaultPolicies sets the Readers, Writers, Admins and Endorsement
+// policies of the application org to the signature policies configtxgen's
+// sample configuration defines for the org's MSP. With NodeOUs enabled,
+// Readers are admins, peers and clients, Writers are admins and clients and
+// Endorsement requires a peer. Otherwise Readers, Writers and Endorsement
+// accept any member. Admins always require an admin. NodeOUs must be enabled
+// in the MSP of the org to use the NodeOU roles.
+func (a *ApplicationOrg) SetDefaultPolicies(nodeOUsEnabled bool) (err error) {
+	defer a.observe("ApplicationOrg.SetDefaultPolicies", time.Now(), &err)
+
+	err = setDefaultOrgPolicies(a.orgGroup, nodeOUsEnabled, applicationOrgPolicies)
+	if err != nil {
+		return fmt.Errorf("failed to set default policies: %v", err)
+	}
+
+	return nil
+}
+
 // RemovePolicy removes an existing policy from an application organization.
-func (a *ApplicationOrg) RemovePolicy(policyName string) error {
+func (a *ApplicationOrg) RemovePolicy(policyName string) (err error) {
+	defer a.observe("ApplicationOrg.RemovePolicy", time.Now(), &err)
+
 	policies, err := a.Policies()
 	if err != nil {
 		return err
@@ -303,7 +486,15 @@ func (a *ApplicationOrg) AnchorPeers() ([]Address, error) {
 
 // AddAnchorPeer adds an anchor peer to an application org's configuration
 // in the updated config.
-func (a *ApplicationOrg) AddAnchorPeer(newAnchorPeer Address) error {
+func (a *ApplicationOrg) AddAnchorPeer(newAnchorPeer Address) (err error) {
+	defer a.observe("ApplicationOrg.AddAnchorPeer", time.Now(), &err)
+
+	newAnchorPeer = newAnchorPeer.Normalized()
+	err = newAnchorPeer.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid anchor peer for org %s: %v", a.name, err)
+	}
+
 	anchorPeersProto := &pb.AnchorPeers{}
 
 	if anchorPeerConfigValue, ok := a.orgGroup.Values[AnchorPeersKey]; ok {
@@ -330,7 +521,7 @@ func (a *ApplicationOrg) AddAnchorPeer(newAnchorPeer Address) error {
 	})
 
 	// Add anchor peers config value back to application org
-	err := setValue(a.orgGroup, anchorPeersValue(anchorProtos), AdminsPolicyKey)
+	err = setValue(a.orgGroup, anchorPeersValue(anchorProtos), AdminsPolicyKey)
 	if err != nil {
 		return err
 	}
@@ -339,7 +530,11 @@ func (a *ApplicationOrg) AddAnchorPeer(newAnchorPeer Address) error {
 
 // RemoveAnchorPeer removes an anchor peer from an application org's configuration
 // in the updated config.
-func (a *ApplicationOrg) RemoveAnchorPeer(anchorPeerToRemove Address) error {
+func (a *ApplicationOrg) RemoveAnchorPeer(anchorPeerToRemove Address) (err error) {
+	defer a.observe("ApplicationOrg.RemoveAnchorPeer", time.Now(), &err)
+
+	anchorPeerToRemove = anchorPeerToRemove.Normalized()
+
 	anchorPeersProto := &pb.AnchorPeers{}
 
 	if anchorPeerConfigValue, ok := a.orgGroup.Values[AnchorPeersKey]; ok {
@@ -352,7 +547,7 @@ func (a *ApplicationOrg) RemoveAnchorPeer(anchorPeerToRemove Address) error {
 
 	existingAnchorPeers := anchorPeersProto.AnchorPeers[:0]
 	for _, anchorPeer := range anchorPeersProto.AnchorPeers {
-		if anchorPeer.Host != anchorPeerToRemove.Host || anchorPeer.Port != int32(anchorPeerToRemove.Port) {
+		if (Address{Host: anchorPeer.Host, Port: int(anchorPeer.Port)}).Normalized() != anchorPeerToRemove {
 			existingAnchorPeers = append(existingAnchorPeers, anchorPeer)
 
 			// Add anchor peers config value back to application org
@@ -370,7 +565,7 @@ func (a *ApplicationOrg) RemoveAnchorPeer(anchorPeerToRemove Address) error {
 	}
 
 	// Add anchor peers config value back to application org
-	err := setValue(a.orgGroup, anchorPeersValue(existingAnchorPeers), AdminsPolicyKey)
+	err = setValue(a.orgGroup, anchorPeersValue(existingAnchorPeers), AdminsPolicyKey)
 	if err != nil {
 		return fmt.Errorf("failed to remove anchor peer %v from org %s: %v", anchorPeerToRemove, a.name, err)
 	}
@@ -402,8 +597,25 @@ func (a *ApplicationGroup) ACLs() (map[string]string, error) {
 
 // SetACLs sets ACLS to an existing channel config application.
 // If an ACL already exists in current configuration, it will be replaced with new ACL.
-func (a *ApplicationGroup) SetACLs(acls map[string]string) error {
-	err := setValue(a.applicationGroup, aclValues(acls), AdminsPolicyKey)
+// Every policy reference must resolve to a policy of the channel config, see
+// SetACL.
+func (a *ApplicationGroup) SetACLs(acls map[string]string) (err error) {
+	defer a.observe("ApplicationGroup.SetACLs", time.Now(), &err)
+
+	apiResources := make([]string, 0, len(acls))
+	for apiResource := range acls {
+		apiResources = append(apiResources, apiResource)
+	}
+	sort.Strings(apiResources)
+
+	for _, apiResource := range apiResources {
+		err = a.checkPolicyRef(apiResource, acls[apiResource])
+		if err != nil {
+			return err
+		}
+	}
+
+	err = setValue(a.applicationGroup, aclValues(acls), AdminsPolicyKey)
 	if err != nil {
 		return err
 	}
@@ -411,10 +623,76 @@ func (a *ApplicationGroup) SetACLs(acls map[string]string) error {
 	return nil
 }
 
+// SetACL sets the policy reference of a single API resource, keeping the other
+// ACLs of the application. The policy reference must resolve to a policy of
+// the channel config, such as /Channel/Application/Readers; references not
+// starting with a slash are relative to the application group, as resolved
+// by peers. Broken references would otherwise only surface at runtime as
+// denied requests.
+func (a *ApplicationGroup) SetACL(apiResource, policyRef string) (err error) {
+	defer a.observe("ApplicationGroup.SetACL", time.Now(), &err)
+
+	err = a.checkPolicyRef(apiResource, policyRef)
+	if err != nil {
+		return err
+	}
+
+	acls, err := a.ACLs()
+	if err != nil {
+		return err
+	}
+	if acls == nil {
+		acls = map[string]string{}
+	}
+
+	acls[apiResource] = policyRef
+
+	err = setValue(a.applicationGroup, aclValues(acls), AdminsPolicyKey)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// checkPolicyRef checks that the policy reference of the ACL of the API
+// resource resolves to a policy of the channel config.
+func (a *ApplicationGroup) checkPolicyRef(apiResource, policyRef string) error {
+	if policyRef == "" {
+		return fmt.Errorf("policy reference for ACL '%s' is required", apiResource)
+	}
+
+	path := policyRef
+	if !strings.HasPrefix(path, "/") {
+		path = fmt.Sprintf("/%s/%s/%s", ChannelGroupKey, ApplicationGroupKey, path)
+	}
+
+	elements := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(elements) < 2 || elements[0] != ChannelGroupKey {
+		return fmt.Errorf("policy reference '%s' for ACL '%s' is not a path below /%s", policyRef, apiResource, ChannelGroupKey)
+	}
+
+	group := a.channelGroup
+	for _, name := range elements[1 : len(elements)-1] {
+		group = group.Groups[name]
+		if group == nil {
+			return fmt.Errorf("policy reference '%s' for ACL '%s' does not resolve: group %s does not exist", policyRef, apiResource, name)
+		}
+	}
+
+	if _, ok := group.Policies[elements[len(elements)-1]]; !ok {
+		return fmt.Errorf("policy reference '%s' for ACL '%s' does not resolve: policy %s does not exist", policyRef, apiResource, elements[len(elements)-1])
+	}
+
+	return nil
+}
+
 // RemoveACLs a list of ACLs from given channel config application.
 // Specifying acls that do not exist in the application ConfigGroup of the channel config will not return a error.
 // Removal will panic if application group does not exist.
-func (a *ApplicationGroup) RemoveACLs(acls []string) error {
+func (a *ApplicationGroup) RemoveACLs(acls []string) (err error) {
+	defer a.observe("ApplicationGroup.RemoveACLs", time.Now(), &err)
+
 	configACLs, err := a.ACLs()
 	if err != nil {
 		return err
@@ -434,7 +712,9 @@ func (a *ApplicationGroup) RemoveACLs(acls []string) error {
 
 // SetMSP updates the MSP config for the specified application
 // org group.
-func (a *ApplicationOrg) SetMSP(updatedMSP MSP) error {
+func (a *ApplicationOrg) SetMSP(updatedMSP MSP) (err error) {
+	defer a.observe("ApplicationOrg.SetMSP", time.Now(), &err)
+
 	currentMSP, err := a.MSP().Configuration()
 	if err != nil {
 		return fmt.Errorf("retrieving msp: %v", err)
@@ -605,20 +605,40 @@ func TestSetACL(t *testing.T) {
 	}{
 		{
 			testName: "success",
-			newACL:   map[string]string{"acl2": "newACL"},
+			newACL:   map[string]string{"acl2": "/Channel/Application/Readers"},
 			expectedACL: map[string]string{
-				"acl2": "newACL",
+				"acl2": "/Channel/Application/Readers",
 			},
 			expectedErr: "",
 		},
 		{
 			testName: "ACL overwrite",
-			newACL:   map[string]string{"acl1": "overwrite acl"},
+			newACL:   map[string]string{"acl1": "Writers"},
 			expectedACL: map[string]string{
-				"acl1": "overwrite acl",
+				"acl1": "Writers",
 			},
 			expectedErr: "",
 		},
+		{
+			testName:    "when the referenced policy does not exist",
+			newACL:      map[string]string{"acl1": "/Channel/Application/Endorsement"},
+			expectedErr: "policy reference '/Channel/Application/Endorsement' for ACL 'acl1' does not resolve: policy Endorsement does not exist",
+		},
+		{
+			testName:    "when the referenced group does not exist",
+			newACL:      map[string]string{"acl1": "/Channel/Application/Org3/Readers"},
+			expectedErr: "policy reference '/Channel/Application/Org3/Readers' for ACL 'acl1' does not resolve: group Org3 does not exist",
+		},
+		{
+			testName:    "when the reference is not a channel path",
+			newACL:      map[string]string{"acl1": "/Readers"},
+			expectedErr: "policy reference '/Readers' for ACL 'acl1' is not a path below /Channel",
+		},
+		{
+			testName:    "when the reference is empty",
+			newACL:      map[string]string{"acl1": ""},
+			expectedErr: "policy reference for ACL 'acl1' is required",
+		},
 	}
 
 	for _, tt := range tests {
@@ -653,6 +673,31 @@ func TestSetACL(t *testing.T) {
 	}
 }
 
+func TestSetSingleACL(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup := newConfigGroup()
+	baseApplication, _ := baseApplication(t)
+	applicationGroup, err := newApplicationGroupTemplate(baseApplication)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup.Groups[ApplicationGroupKey] = applicationGroup
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	err = c.Application().SetACL("acl2", "Readers")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Application().SetACL("acl3", "/Channel/Readers")
+	gt.Expect(err).To(MatchError("policy reference '/Channel/Readers' for ACL 'acl3' does not resolve: policy Readers does not exist"))
+
+	acls, err := c.Application().ACLs()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(acls).To(Equal(map[string]string{
+		"acl1": "hi",
+		"acl2": "Readers",
+	}))
+}
+
 func TestAppOrgRemoveACL(t *testing.T) {
 	t.Parallel()
 
@@ -925,6 +970,44 @@ func TestSetApplicationOrgFailures(t *testing.T) {
 	gt.Expect(err).To(MatchError("failed to create application org Org3: no policies defined"))
 }
 
+func TestSetAndRemoveApplication(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	c := ordererConfigTx(t, etcdRaftOrderer)
+
+	err := c.RemoveApplication()
+	gt.Expect(err).To(MatchError("application group does not exist"))
+
+	application, _ := baseApplication(t)
+	application.Organizations[0].AnchorPeers = []Address{{Host: "peer0.org1.example.com", Port: 7051}}
+	err = c.SetApplication(application)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	applicationConfig, err := c.Application().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(applicationConfig.Organizations).To(HaveLen(2))
+	gt.Expect(applicationConfig.Capabilities).To(Equal(application.Capabilities))
+	gt.Expect(applicationConfig.ACLs).To(Equal(application.ACLs))
+	org1, err := c.Application().Organization("Org1").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org1.AnchorPeers).To(Equal([]Address{{Host: "peer0.org1.example.com", Port: 7051}}))
+
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.SetApplication(application)
+	gt.Expect(err).To(MatchError("application group already exists"))
+
+	err = c.RemoveApplication()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Groups).NotTo(HaveKey(ApplicationGroupKey))
+
+	err = c.SetApplication(Application{})
+	gt.Expect(err).To(MatchError(HavePrefix("invalid application: ")))
+}
+
 func TestApplicationConfiguration(t *testing.T) {
 	t.Parallel()
 	gt := NewGomegaWithT(t)
@@ -1958,6 +2041,52 @@ func TestSetApplicationOrgPoliciesFailures(t *testing.T) {
 	gt.Expect(err).To(MatchError("failed to set policies: unknown policy type: "))
 }
 
+func TestSetApplicationOrgDefaultPolicies(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	applicationOrg1 := c.Application().Organization("Org1")
+
+	err = applicationOrg1.SetPolicy("Custom", Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.admin')"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = applicationOrg1.SetDefaultPolicies(false)
+	gt.Expect(err).NotTo(HaveOccurred())
+	policies, err := applicationOrg1.Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(Equal(map[string]Policy{
+		ReadersPolicyKey:     {Type: SignaturePolicyType, Rule: "AND('MSPID.member')", ModPolicy: AdminsPolicyKey},
+		WritersPolicyKey:     {Type: SignaturePolicyType, Rule: "AND('MSPID.member')", ModPolicy: AdminsPolicyKey},
+		AdminsPolicyKey:      {Type: SignaturePolicyType, Rule: "AND('MSPID.admin')", ModPolicy: AdminsPolicyKey},
+		EndorsementPolicyKey: {Type: SignaturePolicyType, Rule: "AND('MSPID.member')", ModPolicy: AdminsPolicyKey},
+		"Custom":             {Type: SignaturePolicyType, Rule: "AND('MSPID.admin')", ModPolicy: AdminsPolicyKey},
+		// policies other than the defaults are left untouched
+		LifecycleEndorsementPolicyKey: {Type: ImplicitMetaPolicyType, Rule: "MAJORITY Endorsement", ModPolicy: AdminsPolicyKey},
+	}))
+
+	err = applicationOrg1.SetDefaultPolicies(true)
+	gt.Expect(err).To(MatchError("failed to set default policies: NodeOUs are not enabled in MSP MSPID"))
+
+	err = applicationOrg1.MSP().SetEnableNodeOUs(true)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = applicationOrg1.SetDefaultPolicies(true)
+	gt.Expect(err).NotTo(HaveOccurred())
+	policies, err = applicationOrg1.Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(Equal(map[string]Policy{
+		ReadersPolicyKey:     {Type: SignaturePolicyType, Rule: "OR('MSPID.admin', 'MSPID.peer', 'MSPID.client')", ModPolicy: AdminsPolicyKey},
+		WritersPolicyKey:     {Type: SignaturePolicyType, Rule: "OR('MSPID.admin', 'MSPID.client')", ModPolicy: AdminsPolicyKey},
+		AdminsPolicyKey:      {Type: SignaturePolicyType, Rule: "AND('MSPID.admin')", ModPolicy: AdminsPolicyKey},
+		EndorsementPolicyKey: {Type: SignaturePolicyType, Rule: "AND('MSPID.peer')", ModPolicy: AdminsPolicyKey},
+		"Custom":             {Type: SignaturePolicyType, Rule: "AND('MSPID.admin')", ModPolicy: AdminsPolicyKey},
+		// policies other than the defaults are left untouched
+		LifecycleEndorsementPolicyKey: {Type: ImplicitMetaPolicyType, Rule: "MAJORITY Endorsement", ModPolicy: AdminsPolicyKey},
+	}))
+}
+
 func TestSetApplicationModPolicy(t *testing.T) {
 	t.Parallel()
 	gt := NewGomegaWithT(t)
@@ -2797,3 +2926,64 @@ func baseApplication(t *testing.T) (Application, []*ecdsa.PrivateKey) {
 		ModPolicy: AdminsPolicyKey,
 	}, []*ecdsa.PrivateKey{org1PrivKey, org2PrivKey}
 }
+
+func TestApplicationValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName       string
+		applicationMod func(*Application)
+		err            string
+	}{
+		{
+			testName:       "When the application is valid",
+			applicationMod: func(a *Application) {},
+		},
+		{
+			testName: "When the Writers policy is missing",
+			applicationMod: func(a *Application) {
+				delete(a.Policies, WritersPolicyKey)
+			},
+			err: "no Writers policy defined",
+		},
+		{
+			testName: "When an application org is invalid",
+			applicationMod: func(a *Application) {
+				a.Organizations[1].MSP.Name = ""
+			},
+			err: "org Org2: MSP name is required",
+		},
+		{
+			testName: "When application orgs are duplicated",
+			applicationMod: func(a *Application) {
+				a.Organizations[1].Name = "Org1"
+			},
+			err: "duplicate application org Org1",
+		},
+		{
+			testName: "When an ACL has no policy reference",
+			applicationMod: func(a *Application) {
+				a.ACLs["acl2"] = ""
+			},
+			err: "policy reference for ACL 'acl2' is required",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			application, _ := baseApplication(t)
+			tt.applicationMod(&application)
+
+			err := application.Validate()
+			if tt.err == "" {
+				gt.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			gt.Expect(err).To(MatchError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,614 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// Change is a declarative modification of a config which can be applied as
+// part of a batch by ConfigTx.Apply.
+type Change interface {
+	// Validate checks the parameters of the change without looking at the
+	// config it is applied to.
+	Validate() error
+	// Apply performs the change on the updated config of the ConfigTx.
+	Apply(c *ConfigTx) error
+}
+
+// Apply validates every change and then applies the changes in order. Either
+// all changes are applied or, if a change is invalid or fails, the updated
+// config is left untouched.
+func (c *ConfigTx) Apply(changes []Change) (err error) {
+	defer c.observe("ConfigTx.Apply", time.Now(), &err)
+
+	for i, change := range changes {
+		if change == nil {
+			return fmt.Errorf("change %d is nil", i)
+		}
+		err := change.Validate()
+		if err != nil {
+			return fmt.Errorf("invalid change %d (%s): %v", i, changeName(change), err)
+		}
+	}
+
+	// the changes are applied to the live groups, so that the groups, orgs and
+	// MSPs retrieved before remain attached to the updated config
+	backup := proto.Clone(c.updated.ChannelGroup).(*cb.ConfigGroup)
+	groups := groupTree(c.updated.ChannelGroup)
+
+	for i, change := range changes {
+		err := change.Apply(c)
+		if err != nil {
+			groups.restore(backup)
+			return fmt.Errorf("applying change %d (%s): %v", i, changeName(change), err)
+		}
+	}
+
+	return nil
+}
+
+// configGroupTree records the config groups of a channel group, so that their
+// content can be restored in place.
+type configGroupTree struct {
+	group  *cb.ConfigGroup
+	groups map[string]*configGroupTree
+}
+
+func groupTree(cg *cb.ConfigGroup) *configGroupTree {
+	tree := &configGroupTree{
+		group:  cg,
+		groups: map[string]*configGroupTree{},
+	}
+	for name, child := range cg.Groups {
+		tree.groups[name] = groupTree(child)
+	}
+
+	return tree
+}
+
+// restore copies the content of backup into the recorded groups, reattaching
+// recorded groups which were replaced or removed.
+func (t *configGroupTree) restore(backup *cb.ConfigGroup) {
+	groups := make(map[string]*cb.ConfigGroup, len(backup.Groups))
+	for name, child := range backup.Groups {
+		if childTree, ok := t.groups[name]; ok {
+			childTree.restore(child)
+			child = childTree.group
+		}
+		groups[name] = child
+	}
+
+	*t.group = *backup
+	t.group.Groups = groups
+}
+
+// changeName returns the name of the type of the change for error messages.
+func changeName(change Change) string {
+	name := fmt.Sprintf("%T", change)
+	return strings.TrimPrefix(name[strings.LastIndex(name, ".")+1:], "*")
+}
+
+// requireGroup returns an error if the updated channel group does not contain
+// the group with the given key.
+func (c *ConfigTx) requireGroup(key string) error {
+	if _, ok := c.updated.ChannelGroup.Groups[key]; !ok {
+		return fmt.Errorf("%s group does not exist", strings.ToLower(key))
+	}
+
+	return nil
+}
+
+// AddApplicationOrg adds an organization to the application group, replacing
+// an existing organization with the same name.
+type AddApplicationOrg struct {
+	Org Organization
+}
+
+// Validate checks the organization.
+func (a AddApplicationOrg) Validate() error {
+	return a.Org.Validate()
+}
+
+// Apply sets the organization in the application group.
+func (a AddApplicationOrg) Apply(c *ConfigTx) error {
+	if err := c.requireGroup(ApplicationGroupKey); err != nil {
+		return err
+	}
+
+	return c.Application().SetOrganization(a.Org)
+}
+
+// RemoveApplicationOrg removes an organization from the application group.
+type RemoveApplicationOrg struct {
+	Name string
+}
+
+// Validate checks that the organization is named.
+func (r RemoveApplicationOrg) Validate() error {
+	if r.Name == "" {
+		return errors.New("organization name is required")
+	}
+
+	return nil
+}
+
+// Apply removes the organization from the application group.
+func (r RemoveApplicationOrg) Apply(c *ConfigTx) error {
+	if err := c.requireGroup(ApplicationGroupKey); err != nil {
+		return err
+	}
+
+	if c.Application().Organization(r.Name) == nil {
+		return fmt.Errorf("application org %s does not exist", r.Name)
+	}
+
+	c.Application().RemoveOrganization(r.Name)
+
+	return nil
+}
+
+// AddOrdererOrg adds an organization to the orderer group, replacing an
+// existing organization with the same name.
+type AddOrdererOrg struct {
+	Org Organization
+}
+
+// Validate checks the organization.
+func (a AddOrdererOrg) Validate() error {
+	return a.Org.Validate()
+}
+
+// Apply sets the organization in the orderer group.
+func (a AddOrdererOrg) Apply(c *ConfigTx) error {
+	if err := c.requireGroup(OrdererGroupKey); err != nil {
+		return err
+	}
+
+	return c.Orderer().SetOrganization(a.Org)
+}
+
+// RemoveOrdererOrg removes an organization from the orderer group. If
+// RemoveConsenters is set, the consenters and legacy orderer addresses of the
+// organization are removed as well, see
+// OrdererGroup.RemoveOrganizationAndConsenters.
+type RemoveOrdererOrg struct {
+	Name             string
+	RemoveConsenters bool
+}
+
+// Validate checks that the organization is named.
+func (r RemoveOrdererOrg) Validate() error {
+	if r.Name == "" {
+		return errors.New("organization name is required")
+	}
+
+	return nil
+}
+
+// Apply removes the organization from the orderer group.
+func (r RemoveOrdererOrg) Apply(c *ConfigTx) error {
+	if err := c.requireGroup(OrdererGroupKey); err != nil {
+		return err
+	}
+
+	if c.Orderer().Organization(r.Name) == nil {
+		return fmt.Errorf("orderer org %s does not exist", r.Name)
+	}
+
+	if r.RemoveConsenters {
+		return c.Orderer().RemoveOrganizationAndConsenters(r.Name)
+	}
+
+	c.Orderer().RemoveOrganization(r.Name)
+
+	return nil
+}
+
+// SetPolicy sets a policy of a group of the config. Group is the path of the
+// group below the channel group, i.e. "" for the channel group itself,
+// "Application", "Orderer", "Application/<org>" or "Orderer/<org>".
+type SetPolicy struct {
+	Group  string
+	Name   string
+	Policy Policy
+}
+
+// Validate checks the group path, the policy name and the policy.
+func (s SetPolicy) Validate() error {
+	if _, _, err := splitGroupPath(s.Group); err != nil {
+		return err
+	}
+
+	if s.Name == "" {
+		return errors.New("policy name is required")
+	}
+
+	if err := s.Policy.Validate(); err != nil {
+		return fmt.Errorf("policy %s: %v", s.Name, err)
+	}
+
+	return nil
+}
+
+// Apply sets the policy in the group.
+func (s SetPolicy) Apply(c *ConfigTx) error {
+	groupKey, orgName, err := splitGroupPath(s.Group)
+	if err != nil {
+		return err
+	}
+
+	if groupKey == "" {
+		return c.Channel().SetPolicy(s.Name, s.Policy)
+	}
+
+	if err := c.requireGroup(groupKey); err != nil {
+		return err
+	}
+
+	switch {
+	case groupKey == ApplicationGroupKey && orgName == "":
+		return c.Application().SetPolicy(s.Name, s.Policy)
+	case groupKey == ApplicationGroupKey:
+		org := c.Application().Organization(orgName)
+		if org == nil {
+			return fmt.Errorf("application org %s does not exist", orgName)
+		}
+		return org.SetPolicy(s.Name, s.Policy)
+	case orgName == "":
+		return c.Orderer().SetPolicy(s.Name, s.Policy)
+	default:
+		org := c.Orderer().Organization(orgName)
+		if org == nil {
+			return fmt.Errorf("orderer org %s does not exist", orgName)
+		}
+		return org.SetPolicy(s.Name, s.Policy)
+	}
+}
+
+// splitGroupPath splits the path of a group below the channel group into the
+// key of the application or orderer group and the name of an org within it.
+func splitGroupPath(group string) (string, string, error) {
+	if group == "" {
+		return "", "", nil
+	}
+
+	parts := strings.Split(group, "/")
+	if len(parts) > 2 || (parts[0] != ApplicationGroupKey && parts[0] != OrdererGroupKey) {
+		return "", "", fmt.Errorf("invalid group '%s'", group)
+	}
+
+	if len(parts) == 1 {
+		return parts[0], "", nil
+	}
+
+	if parts[1] == "" {
+		return "", "", fmt.Errorf("invalid group '%s'", group)
+	}
+
+	return parts[0], parts[1], nil
+}
+
+// SetBatchTimeout sets the batch timeout of the orderer.
+type SetBatchTimeout struct {
+	Timeout time.Duration
+}
+
+// Validate checks that the timeout is positive.
+func (s SetBatchTimeout) Validate() error {
+	if s.Timeout <= 0 {
+		return fmt.Errorf("batch timeout must be positive, got %s", s.Timeout)
+	}
+
+	return nil
+}
+
+// Apply sets the batch timeout.
+func (s SetBatchTimeout) Apply(c *ConfigTx) error {
+	if err := c.requireGroup(OrdererGroupKey); err != nil {
+		return err
+	}
+
+	return c.Orderer().SetBatchTimeout(s.Timeout)
+}
+
+// SetBatchSize sets the batch size of the orderer.
+type SetBatchSize struct {
+	BatchSize orderer.BatchSize
+}
+
+// Validate checks the batch size.
+func (s SetBatchSize) Validate() error {
+	return s.BatchSize.Validate()
+}
+
+// Apply sets the batch size.
+func (s SetBatchSize) Apply(c *ConfigTx) error {
+	if err := c.requireGroup(OrdererGroupKey); err != nil {
+		return err
+	}
+
+	batchSize := c.Orderer().BatchSize()
+	if err := batchSize.SetMaxMessageCount(s.BatchSize.MaxMessageCount); err != nil {
+		return err
+	}
+	if err := batchSize.SetAbsoluteMaxBytes(s.BatchSize.AbsoluteMaxBytes); err != nil {
+		return err
+	}
+
+	return batchSize.SetPreferredMaxBytes(s.BatchSize.PreferredMaxBytes)
+}
+
+// AddConsenter adds a consenter to an etcdraft orderer.
+type AddConsenter struct {
+	Consenter orderer.Consenter
+}
+
+// Validate checks the consenter.
+func (a AddConsenter) Validate() error {
+	return a.Consenter.Validate()
+}
+
+// Apply adds the consenter.
+func (a AddConsenter) Apply(c *ConfigTx) error {
+	if err := c.requireGroup(OrdererGroupKey); err != nil {
+		return err
+	}
+
+	return c.Orderer().AddConsenter(a.Consenter)
+}
+
+// RemoveConsenter removes a consenter from an etcdraft orderer.
+type RemoveConsenter struct {
+	Consenter orderer.Consenter
+}
+
+// Validate checks the consenter.
+func (r RemoveConsenter) Validate() error {
+	return r.Consenter.Validate()
+}
+
+// Apply removes the consenter.
+func (r RemoveConsenter) Apply(c *ConfigTx) error {
+	if err := c.requireGroup(OrdererGroupKey); err != nil {
+		return err
+	}
+
+	return c.Orderer().RemoveConsenter(r.Consenter)
+}
+
+// AddSmartBFTConsenter adds a consenter to a SmartBFT orderer and regenerates
+// its BlockValidation policy.
+type AddSmartBFTConsenter struct {
+	Consenter orderer.SmartBFTConsenter
+}
+
+// Validate checks the consenter.
+func (a AddSmartBFTConsenter) Validate() error {
+	return a.Consenter.Validate()
+}
+
+// Apply adds the consenter.
+func (a AddSmartBFTConsenter) Apply(c *ConfigTx) error {
+	if err := c.requireGroup(OrdererGroupKey); err != nil {
+		return err
+	}
+
+	return c.Orderer().AddSmartBFTConsenter(a.Consenter)
+}
+
+// RemoveSmartBFTConsenter removes the consenter with the given id from a
+// SmartBFT orderer and regenerates its BlockValidation policy.
+type RemoveSmartBFTConsenter struct {
+	ID uint64
+}
+
+// Validate checks that the consenter id is set.
+func (r RemoveSmartBFTConsenter) Validate() error {
+	if r.ID == 0 {
+		return errors.New("consenter id is required")
+	}
+
+	return nil
+}
+
+// Apply removes the consenter.
+func (r RemoveSmartBFTConsenter) Apply(c *ConfigTx) error {
+	if err := c.requireGroup(OrdererGroupKey); err != nil {
+		return err
+	}
+
+	return c.Orderer().RemoveSmartBFTConsenter(r.ID)
+}
+
+// RotateRootCA replaces a root cert of the MSP of an org. Group is the path of
+// the org below the channel group, i.e. "Application/<org>" or
+// "Orderer/<org>". The new root cert is added before the old one is removed,
+// so intermediate certs must already chain to the new root cert.
+type RotateRootCA struct {
+	Group   string
+	OldCert *x509.Certificate
+	NewCert *x509.Certificate
+}
+
+// Validate checks the org path and that both certs are given.
+func (r RotateRootCA) Validate() error {
+	_, orgName, err := splitGroupPath(r.Group)
+	if err != nil {
+		return err
+	}
+
+	if orgName == "" {
+		return fmt.Errorf("group '%s' is not an org", r.Group)
+	}
+
+	if r.OldCert == nil || r.NewCert == nil {
+		return errors.New("old and new root certs are required")
+	}
+
+	return nil
+}
+
+// Apply adds the new root cert to the MSP of the org and removes the old one.
+func (r RotateRootCA) Apply(c *ConfigTx) error {
+	groupKey, orgName, err := splitGroupPath(r.Group)
+	if err != nil {
+		return err
+	}
+
+	if err := c.requireGroup(groupKey); err != nil {
+		return err
+	}
+
+	var msp *OrganizationMSP
+	if groupKey == ApplicationGroupKey {
+		org := c.Application().Organization(orgName)
+		if org == nil {
+			return fmt.Errorf("application org %s does not exist", orgName)
+		}
+		msp = org.MSP()
+	} else {
+		org := c.Orderer().Organization(orgName)
+		if org == nil {
+			return fmt.Errorf("orderer org %s does not exist", orgName)
+		}
+		msp = org.MSP()
+	}
+
+	config, err := msp.Configuration()
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	for _, cert := range config.RootCerts {
+		if cert.Equal(r.OldCert) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("root cert with serial number %d not found in org %s", r.OldCert.SerialNumber, orgName)
+	}
+
+	if err := msp.AddRootCert(r.NewCert); err != nil {
+		return err
+	}
+
+	return msp.RemoveRootCert(r.OldCert)
+}
+
+// SetCapabilities replaces the capabilities of the channel, application or
+// orderer group. Group is "" for the channel group, "Application" or
+// "Orderer". The new capabilities are added before the others are removed, so
+// that the consensus type requirements hold throughout.
+type SetCapabilities struct {
+	Group        string
+	Capabilities []string
+}
+
+// Validate checks the group path and that capabilities are given.
+func (s SetCapabilities) Validate() error {
+	_, orgName, err := splitGroupPath(s.Group)
+	if err != nil {
+		return err
+	}
+
+	if orgName != "" {
+		return fmt.Errorf("group '%s' has no capabilities", s.Group)
+	}
+
+	if len(s.Capabilities) == 0 {
+		return errors.New("capabilities are required")
+	}
+
+	return nil
+}
+
+// Apply sets the capabilities of the group.
+func (s SetCapabilities) Apply(c *ConfigTx) error {
+	groupKey, _, err := splitGroupPath(s.Group)
+	if err != nil {
+		return err
+	}
+
+	var group capabilityGroup
+	switch groupKey {
+	case "":
+		group = c.Channel()
+	case ApplicationGroupKey:
+		if err := c.requireGroup(groupKey); err != nil {
+			return err
+		}
+		group = c.Application()
+	default:
+		if err := c.requireGroup(groupKey); err != nil {
+			return err
+		}
+		group = c.Orderer()
+	}
+
+	current, err := group.Capabilities()
+	if err != nil {
+		return err
+	}
+
+	for _, capability := range s.Capabilities {
+		if err := group.AddCapability(capability); err != nil {
+			return err
+		}
+	}
+
+	for _, capability := range current {
+		if containsString(s.Capabilities, capability) {
+			continue
+		}
+		if err := group.RemoveCapability(capability); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// capabilityGroup is a config group holding capabilities.
+type capabilityGroup interface {
+	Capabilities() ([]string, error)
+	AddCapability(capability string) error
+	RemoveCapability(capability string) error
+}
+
+// MigrateOrdererAddresses moves the deprecated channel level orderer
+// addresses to the endpoints of the orderer orgs, as done by
+// ConfigTx.MigrateLegacyOrdererAddresses.
+type MigrateOrdererAddresses struct {
+	OrgByAddress map[string]string
+}
+
+// Validate checks that the explicitly mapped addresses name an org.
+func (m MigrateOrdererAddresses) Validate() error {
+	for address, org := range m.OrgByAddress {
+		if org == "" {
+			return fmt.Errorf("address %s is not mapped to an org", address)
+		}
+	}
+
+	return nil
+}
+
+// Apply migrates the legacy orderer addresses.
+func (m MigrateOrdererAddresses) Apply(c *ConfigTx) error {
+	return c.MigrateLegacyOrdererAddresses(m.OrgByAddress)
+}
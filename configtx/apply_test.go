@@ -0,0 +1,203 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func applyConfigTx(t *testing.T) ConfigTx {
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	ordererGroup, err := newOrdererGroup(etcdRaftOrderer)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup.Groups[OrdererGroupKey] = ordererGroup
+
+	return New(&cb.Config{ChannelGroup: channelGroup})
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	cert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", caCert, caPrivKey)
+	consenter := orderer.Consenter{
+		Address:       orderer.EtcdAddress{Host: "node-4.example.com", Port: 7050},
+		ClientTLSCert: cert,
+		ServerTLSCert: cert,
+	}
+
+	org3 := baseApplicationOrg(t)
+	org3.Name = "Org3"
+	policy := Policy{
+		Type:      SignaturePolicyType,
+		Rule:      "OR('MSPID.admin')",
+		ModPolicy: AdminsPolicyKey,
+	}
+
+	err := c.Apply([]Change{
+		AddApplicationOrg{Org: org3},
+		RemoveApplicationOrg{Name: "Org2"},
+		SetPolicy{Group: "Application/Org3", Name: "Custom", Policy: policy},
+		SetPolicy{Name: "Custom", Policy: policy},
+		SetBatchTimeout{Timeout: 5 * time.Second},
+		AddConsenter{Consenter: consenter},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(c.Application().Organization("Org3")).NotTo(BeNil())
+	gt.Expect(c.Application().Organization("Org2")).To(BeNil())
+	policies, err := c.Application().Organization("Org3").Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(HaveKey("Custom"))
+	policies, err = c.Channel().Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(HaveKey("Custom"))
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.BatchTimeout).To(Equal(5 * time.Second))
+	gt.Expect(ordererConfig.EtcdRaft.Consenters).To(HaveLen(4))
+}
+
+func TestApplyFailures(t *testing.T) {
+	t.Parallel()
+
+	policy := Policy{
+		Type:      ImplicitMetaPolicyType,
+		Rule:      "ANY Readers",
+		ModPolicy: AdminsPolicyKey,
+	}
+
+	tests := []struct {
+		testName    string
+		changes     []Change
+		expectedErr string
+	}{
+		{
+			testName: "when a change is nil",
+			changes: []Change{
+				SetBatchTimeout{Timeout: time.Second},
+				nil,
+			},
+			expectedErr: "change 1 is nil",
+		},
+		{
+			testName: "when a change is invalid",
+			changes: []Change{
+				SetBatchTimeout{Timeout: time.Second},
+				SetPolicy{Group: "Consortiums", Name: "Custom", Policy: policy},
+			},
+			expectedErr: "invalid change 1 (SetPolicy): invalid group 'Consortiums'",
+		},
+		{
+			testName: "when a change fails",
+			changes: []Change{
+				SetBatchTimeout{Timeout: time.Second},
+				RemoveApplicationOrg{Name: "Org2"},
+				SetPolicy{Group: "Orderer/UnknownOrg", Name: "Custom", Policy: policy},
+			},
+			expectedErr: "applying change 2 (SetPolicy): orderer org UnknownOrg does not exist",
+		},
+		{
+			testName: "when the orderer type does not match",
+			changes: []Change{
+				RemoveSmartBFTConsenter{ID: 1},
+			},
+			expectedErr: "applying change 0 (RemoveSmartBFTConsenter): consensus type etcdraft is not smartbft",
+		},
+		{
+			testName: "when setting the capabilities of an org",
+			changes: []Change{
+				SetCapabilities{Group: "Application/Org1", Capabilities: []string{"V2_0"}},
+			},
+			expectedErr: "invalid change 0 (SetCapabilities): group 'Application/Org1' has no capabilities",
+		},
+		{
+			testName: "when the capabilities would not support the consensus type",
+			changes: []Change{
+				SetCapabilities{Group: OrdererGroupKey, Capabilities: []string{"V2_0"}},
+				SetCapabilities{Group: OrdererGroupKey, Capabilities: []string{"V1_4"}},
+			},
+			expectedErr: "applying change 1 (SetCapabilities): removing capability V2_0: consensus type etcdraft requires orderer capability V1_4_2 or later",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := applyConfigTx(t)
+			expected := proto.Clone(c.updated.ChannelGroup)
+
+			err := c.Apply(tt.changes)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+			gt.Expect(proto.Equal(c.updated.ChannelGroup, expected)).To(BeTrue())
+		})
+	}
+}
+
+func TestApplyKeepsGroupsAttached(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	o := c.Orderer()
+	org2 := c.Application().Organization("Org2")
+
+	err := c.Apply([]Change{
+		SetBatchTimeout{Timeout: time.Second},
+		RemoveApplicationOrg{Name: "Org2"},
+		RemoveApplicationOrg{Name: "Org2"},
+	})
+	gt.Expect(err).To(MatchError("applying change 2 (RemoveApplicationOrg): application org Org2 does not exist"))
+
+	err = o.SetBatchTimeout(7 * time.Second)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = org2.AddAnchorPeer(Address{Host: "peer0.org2.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.BatchTimeout).To(Equal(7 * time.Second))
+	anchorPeers, err := c.Application().Organization("Org2").AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(anchorPeers).To(ContainElement(Address{Host: "peer0.org2.example.com", Port: 7051}))
+
+	err = c.Apply([]Change{SetBatchTimeout{Timeout: time.Second}})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = o.SetBatchTimeout(9 * time.Second)
+	gt.Expect(err).NotTo(HaveOccurred())
+	ordererConfig, err = c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.BatchTimeout).To(Equal(9 * time.Second))
+}
+
+func TestApplyMissingGroup(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	err = c.Apply([]Change{SetBatchTimeout{Timeout: time.Second}})
+	gt.Expect(err).To(MatchError("applying change 0 (SetBatchTimeout): orderer group does not exist"))
+}
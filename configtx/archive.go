@@ -0,0 +1,124 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+)
+
+// ConfigArchiveFormat identifies the format of the config archives created by
+// ConfigTx.Archive.
+const ConfigArchiveFormat = "fabric-config-archive/v1"
+
+// ConfigArchive is a channel config together with the metadata needed to
+// back it up, restore it or promote it to another environment. It is stored
+// as a single JSON document, see JSON and ParseConfigArchive.
+type ConfigArchive struct {
+	// Format is always ConfigArchiveFormat.
+	Format    string `json:"format"`
+	ChannelID string `json:"channel_id"`
+	Sequence  uint64 `json:"sequence"`
+	// Fingerprint is the fingerprint of the config, see ConfigTx.Fingerprint.
+	Fingerprint string    `json:"fingerprint"`
+	ExportedAt  time.Time `json:"exported_at"`
+	// Config is the marshaled Config protobuf.
+	Config []byte `json:"config"`
+}
+
+// Archive returns an archive of the original config of the channel with the
+// given ID, exported at the current time.
+func (c *ConfigTx) Archive(channelID string) (ConfigArchive, error) {
+	if channelID == "" {
+		return ConfigArchive{}, errors.New("channel ID is required")
+	}
+
+	fingerprint, err := configFingerprint(c.original)
+	if err != nil {
+		return ConfigArchive{}, err
+	}
+
+	configBytes, err := proto.Marshal(c.original)
+	if err != nil {
+		return ConfigArchive{}, fmt.Errorf("marshaling config: %v", err)
+	}
+
+	return ConfigArchive{
+		Format:      ConfigArchiveFormat,
+		ChannelID:   channelID,
+		Sequence:    c.original.Sequence,
+		Fingerprint: fingerprint,
+		ExportedAt:  time.Now().UTC(),
+		Config:      configBytes,
+	}, nil
+}
+
+// JSON returns the archive encoded as JSON.
+func (a ConfigArchive) JSON() ([]byte, error) {
+	return json.MarshalIndent(a, "", "\t")
+}
+
+// ParseConfigArchive parses a config archive encoded as JSON and verifies
+// that its sequence and fingerprint match the archived config.
+func ParseConfigArchive(archiveJSON []byte) (ConfigArchive, error) {
+	archive := ConfigArchive{}
+	err := json.Unmarshal(archiveJSON, &archive)
+	if err != nil {
+		return ConfigArchive{}, fmt.Errorf("unmarshaling config archive: %v", err)
+	}
+
+	if archive.Format != ConfigArchiveFormat {
+		return ConfigArchive{}, fmt.Errorf("unsupported config archive format '%s'", archive.Format)
+	}
+
+	config, err := archive.config()
+	if err != nil {
+		return ConfigArchive{}, err
+	}
+
+	if config.Sequence != archive.Sequence {
+		return ConfigArchive{}, fmt.Errorf("archived config has sequence %d, expected %d", config.Sequence, archive.Sequence)
+	}
+
+	fingerprint, err := configFingerprint(config)
+	if err != nil {
+		return ConfigArchive{}, err
+	}
+	if fingerprint != archive.Fingerprint {
+		return ConfigArchive{}, fmt.Errorf("archived config has fingerprint %s, expected %s", fingerprint, archive.Fingerprint)
+	}
+
+	return archive, nil
+}
+
+// ConfigTx returns a ConfigTx for the archived config.
+func (a ConfigArchive) ConfigTx() (ConfigTx, error) {
+	config, err := a.config()
+	if err != nil {
+		return ConfigTx{}, err
+	}
+
+	return New(config), nil
+}
+
+func (a ConfigArchive) config() (*cb.Config, error) {
+	config := &cb.Config{}
+	err := proto.Unmarshal(a.Config, config)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling archived config: %v", err)
+	}
+	if config.ChannelGroup == nil {
+		return nil, errors.New("archived config does not contain a channel group")
+	}
+
+	return config, nil
+}
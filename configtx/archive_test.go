@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigArchive(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{Sequence: 7, ChannelGroup: channelGroup})
+	expectedFingerprint, err := c.Fingerprint()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// the archive holds the original config, not the pending update
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	archive, err := c.Archive("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(archive.Format).To(Equal(ConfigArchiveFormat))
+	gt.Expect(archive.ChannelID).To(Equal("testchannel"))
+	gt.Expect(archive.Sequence).To(Equal(uint64(7)))
+	gt.Expect(archive.Fingerprint).To(Equal(expectedFingerprint))
+	gt.Expect(archive.ExportedAt).To(BeTemporally("~", time.Now(), time.Minute))
+
+	archiveJSON, err := archive.JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	parsed, err := ParseConfigArchive(archiveJSON)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(parsed.ChannelID).To(Equal("testchannel"))
+	gt.Expect(parsed.Sequence).To(Equal(uint64(7)))
+	gt.Expect(parsed.ExportedAt.Equal(archive.ExportedAt)).To(BeTrue())
+
+	restored, err := parsed.ConfigTx()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(restored.OriginalConfig(), c.OriginalConfig())).To(BeTrue())
+}
+
+func TestConfigArchiveFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{Sequence: 7, ChannelGroup: channelGroup})
+
+	_, err = c.Archive("")
+	gt.Expect(err).To(MatchError("channel ID is required"))
+
+	archive, err := c.Archive("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	tests := []struct {
+		testName    string
+		archiveMod  func(*ConfigArchive)
+		expectedErr string
+	}{
+		{
+			testName:    "When the format is not supported",
+			archiveMod:  func(a *ConfigArchive) { a.Format = "fabric-config-archive/v0" },
+			expectedErr: "unsupported config archive format 'fabric-config-archive/v0'",
+		},
+		{
+			testName:    "When the config cannot be unmarshaled",
+			archiveMod:  func(a *ConfigArchive) { a.Config = []byte("garbage") },
+			expectedErr: "unmarshaling archived config: ",
+		},
+		{
+			testName:    "When the config has no channel group",
+			archiveMod:  func(a *ConfigArchive) { a.Config = nil },
+			expectedErr: "archived config does not contain a channel group",
+		},
+		{
+			testName:    "When the sequence does not match",
+			archiveMod:  func(a *ConfigArchive) { a.Sequence = 8 },
+			expectedErr: "archived config has sequence 7, expected 8",
+		},
+		{
+			testName:    "When the fingerprint does not match",
+			archiveMod:  func(a *ConfigArchive) { a.Fingerprint = "0123" },
+			expectedErr: "expected 0123",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			modified := archive
+			tt.archiveMod(&modified)
+			archiveJSON, err := modified.JSON()
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			_, err = ParseConfigArchive(archiveJSON)
+			gt.Expect(err).To(MatchError(ContainSubstring(tt.expectedErr)))
+		})
+	}
+
+	_, err = ParseConfigArchive([]byte("{"))
+	gt.Expect(err).To(MatchError("unmarshaling config archive: unexpected end of JSON input"))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+)
+
+// VerifyBlocks checks the integrity of a sequence of blocks, such as an
+// exported channel history. The data hash of every block must match its data
+// and the block numbers must be increasing. When two blocks have consecutive
+// numbers, the previous hash of the latter must be the header hash of the
+// former. Blocks which are not adjacent, e.g. config blocks with ordinary
+// blocks in between, are only checked individually.
+func VerifyBlocks(blocks []*cb.Block) error {
+	var previous *cb.Block
+
+	for i, block := range blocks {
+		if block == nil || block.Header == nil || block.Data == nil {
+			return fmt.Errorf("block at index %d is empty", i)
+		}
+
+		if !bytes.Equal(block.Header.DataHash, blockDataHash(block.Data)) {
+			return fmt.Errorf("block %d: data hash does not match block data", block.Header.Number)
+		}
+
+		if previous != nil {
+			if block.Header.Number <= previous.Header.Number {
+				return fmt.Errorf("block %d: block number does not follow block %d", block.Header.Number, previous.Header.Number)
+			}
+
+			if block.Header.Number == previous.Header.Number+1 {
+				previousHash, err := blockHeaderHash(previous.Header)
+				if err != nil {
+					return fmt.Errorf("block %d: %v", previous.Header.Number, err)
+				}
+				if !bytes.Equal(block.Header.PreviousHash, previousHash) {
+					return fmt.Errorf("block %d: previous hash does not match header hash of block %d", block.Header.Number, previous.Header.Number)
+				}
+			}
+		}
+
+		previous = block
+	}
+
+	return nil
+}
+
+// blockHeaderHash returns the hash of the ASN.1 encoding of the block header,
+// as computed by the ordering service.
+func blockHeaderHash(header *cb.BlockHeader) ([]byte, error) {
+	if header == nil {
+		return nil, errors.New("block header is missing")
+	}
+
+	asn1Header := struct {
+		Number       *big.Int
+		PreviousHash []byte
+		DataHash     []byte
+	}{
+		Number:       new(big.Int).SetUint64(header.Number),
+		PreviousHash: header.PreviousHash,
+		DataHash:     header.DataHash,
+	}
+
+	encoded, err := asn1.Marshal(asn1Header)
+	if err != nil {
+		return nil, fmt.Errorf("encoding block header: %v", err)
+	}
+
+	sum := sha256.Sum256(encoded)
+	return sum[:], nil
+}
@@ -0,0 +1,109 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestVerifyBlocks(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	genesisBlock, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	configEnvelope, _, err := unmarshalConfigBlock(genesisBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	block1 := newTestConfigBlock(t, genesisBlock, configEnvelope.Config, nil)
+	block2 := newTestConfigBlock(t, block1, configEnvelope.Config, nil)
+	block3 := newTestConfigBlock(t, block2, configEnvelope.Config, nil)
+
+	err = VerifyBlocks([]*cb.Block{genesisBlock, block1, block2, block3})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = VerifyBlocks([]*cb.Block{genesisBlock, block3})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = VerifyBlocks(nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestVerifyBlocksFailures(t *testing.T) {
+	t.Parallel()
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	genesisBlock, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+	configEnvelope, _, err := unmarshalConfigBlock(genesisBlock)
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+	block1 := newTestConfigBlock(t, genesisBlock, configEnvelope.Config, nil)
+
+	tests := []struct {
+		testName    string
+		blocks      func() []*cb.Block
+		expectedErr string
+	}{
+		{
+			testName: "when a block is empty",
+			blocks: func() []*cb.Block {
+				return []*cb.Block{genesisBlock, {}}
+			},
+			expectedErr: "block at index 1 is empty",
+		},
+		{
+			testName: "when the block data is tampered with",
+			blocks: func() []*cb.Block {
+				tampered := proto.Clone(block1).(*cb.Block)
+				tampered.Data.Data[0] = append(tampered.Data.Data[0], 0)
+				return []*cb.Block{genesisBlock, tampered}
+			},
+			expectedErr: "block 1: data hash does not match block data",
+		},
+		{
+			testName: "when the previous hash does not link to the previous block",
+			blocks: func() []*cb.Block {
+				tampered := proto.Clone(block1).(*cb.Block)
+				tampered.Header.PreviousHash = []byte("bad-hash")
+				return []*cb.Block{genesisBlock, tampered}
+			},
+			expectedErr: "block 1: previous hash does not match header hash of block 0",
+		},
+		{
+			testName: "when the previous block header is tampered with",
+			blocks: func() []*cb.Block {
+				tampered := proto.Clone(genesisBlock).(*cb.Block)
+				tampered.Header.PreviousHash = []byte("bad-hash")
+				return []*cb.Block{tampered, block1}
+			},
+			expectedErr: "block 1: previous hash does not match header hash of block 0",
+		},
+		{
+			testName: "when the block numbers are not increasing",
+			blocks: func() []*cb.Block {
+				return []*cb.Block{block1, genesisBlock}
+			},
+			expectedErr: "block 0: block number does not follow block 1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			err := VerifyBlocks(tt.blocks())
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
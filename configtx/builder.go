@@ -0,0 +1,290 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+const (
+	// defaultCapability is the capability set by the ChannelBuilder on the
+	// channel, orderer and application groups when none is specified.
+	defaultCapability = "V2_0"
+	// defaultSmartBFTChannelCapability is the channel capability set by the
+	// ChannelBuilder for SmartBFT channels when none is specified.
+	defaultSmartBFTChannelCapability = "V3_0"
+
+	defaultBatchTimeout        = 2 * time.Second
+	defaultMaxMessageCount     = 500
+	defaultAbsoluteMaxBytes    = 10 * 1024 * 1024
+	defaultPreferredMaxBytes   = 2 * 1024 * 1024
+	defaultReadersRule         = "ANY Readers"
+	defaultWritersRule         = "ANY Writers"
+	defaultAdminsRule          = "MAJORITY Admins"
+	defaultEndorsementRule     = "MAJORITY Endorsement"
+	defaultBlockValidationRule = "ANY Writers"
+)
+
+// ChannelBuilder assembles a Channel with the policies, capabilities and
+// orderer parameters of the sample configtx.yaml profiles, so that only the
+// organizations and consensus specific settings need to be provided.
+type ChannelBuilder struct {
+	channelID string
+	channel   Channel
+	// channelCapabilities is set once the channel capabilities were
+	// specified, so that SmartBFT channels are not given their default.
+	channelCapabilities bool
+	// err is the first error encountered while configuring the channel; it
+	// is returned by Build.
+	err error
+}
+
+// NewChannelBuilder returns a ChannelBuilder for the channel with the given
+// ID. Unless overridden, the channel is an application channel ordered by
+// etcdraft with V2_0 capabilities and the default implicit meta policies.
+// SmartBFT channels default to the V3_0 channel capability it requires.
+func NewChannelBuilder(channelID string) *ChannelBuilder {
+	return &ChannelBuilder{
+		channelID: channelID,
+		channel: Channel{
+			Capabilities: []string{defaultCapability},
+			Policies:     defaultImplicitMetaPolicies(),
+			Orderer: Orderer{
+				OrdererType:  orderer.ConsensusTypeEtcdRaft,
+				BatchTimeout: defaultBatchTimeout,
+				BatchSize: orderer.BatchSize{
+					MaxMessageCount:   defaultMaxMessageCount,
+					AbsoluteMaxBytes:  defaultAbsoluteMaxBytes,
+					PreferredMaxBytes: defaultPreferredMaxBytes,
+				},
+				Capabilities: []string{defaultCapability},
+				Policies:     defaultOrdererPolicies(),
+				State:        orderer.ConsensusStateNormal,
+			},
+			Application: Application{
+				Capabilities: []string{defaultCapability},
+				Policies:     defaultApplicationPolicies(),
+			},
+		},
+	}
+}
+
+// WithOrdererType sets the consensus type of the ordering service.
+func (b *ChannelBuilder) WithOrdererType(ordererType string) *ChannelBuilder {
+	b.channel.Orderer.OrdererType = ordererType
+	return b
+}
+
+// WithEtcdRaft sets the etcdraft consenters and options and the consensus
+// type to etcdraft.
+func (b *ChannelBuilder) WithEtcdRaft(etcdRaft orderer.EtcdRaft) *ChannelBuilder {
+	b.channel.Orderer.OrdererType = orderer.ConsensusTypeEtcdRaft
+	b.channel.Orderer.EtcdRaft = etcdRaft
+	return b
+}
+
+// WithSmartBFT sets the SmartBFT consenters and options and the consensus
+// type to smartbft.
+func (b *ChannelBuilder) WithSmartBFT(smartBFT orderer.SmartBFT) *ChannelBuilder {
+	b.channel.Orderer.OrdererType = orderer.ConsensusTypeSmartBFT
+	b.channel.Orderer.SmartBFT = smartBFT
+	return b
+}
+
+// WithBatchTimeout sets the wait time between transactions.
+func (b *ChannelBuilder) WithBatchTimeout(timeout time.Duration) *ChannelBuilder {
+	b.channel.Orderer.BatchTimeout = timeout
+	return b
+}
+
+// WithBatchSize sets the size limits of blocks.
+func (b *ChannelBuilder) WithBatchSize(batchSize orderer.BatchSize) *ChannelBuilder {
+	b.channel.Orderer.BatchSize = batchSize
+	return b
+}
+
+// WithOrdererOrg adds an orderer organization. If the organization does not
+// define any policies, the default member and admin signature policies of
+// its MSP are used.
+func (b *ChannelBuilder) WithOrdererOrg(org Organization) *ChannelBuilder {
+	if org.Policies == nil {
+		org.Policies = defaultOrdererOrgPolicies(org.MSP.Name)
+	}
+	b.channel.Orderer.Organizations = append(b.channel.Orderer.Organizations, org)
+	return b
+}
+
+// WithApplicationOrg adds an application organization. If the organization
+// does not define any policies, the default role based signature policies of
+// its MSP are used.
+func (b *ChannelBuilder) WithApplicationOrg(org Organization) *ChannelBuilder {
+	if org.Policies == nil {
+		org.Policies = defaultApplicationOrgPolicies(org.MSP.Name)
+	}
+	b.channel.Application.Organizations = append(b.channel.Application.Organizations, org)
+	return b
+}
+
+// WithConsortium sets the consortium the channel is created by. It is only
+// required to build channel creation transactions.
+func (b *ChannelBuilder) WithConsortium(consortium string) *ChannelBuilder {
+	b.channel.Consortium = consortium
+	return b
+}
+
+// WithFabricVersion replaces the capabilities of the channel, orderer and
+// application groups with those enabled by the sample configtx.yaml of the
+// given Fabric version.
+func (b *ChannelBuilder) WithFabricVersion(version FabricVersion) *ChannelBuilder {
+	capabilities, err := CapabilitiesFor(version)
+	if err != nil {
+		if b.err == nil {
+			b.err = err
+		}
+		return b
+	}
+
+	b.channel.Capabilities = capabilities.Channel
+	b.channelCapabilities = true
+	b.channel.Orderer.Capabilities = capabilities.Orderer
+	b.channel.Application.Capabilities = capabilities.Application
+	return b
+}
+
+// WithChannelCapabilities replaces the capabilities of the channel group.
+func (b *ChannelBuilder) WithChannelCapabilities(capabilities ...string) *ChannelBuilder {
+	b.channel.Capabilities = capabilities
+	b.channelCapabilities = true
+	return b
+}
+
+// WithOrdererCapabilities replaces the capabilities of the orderer group.
+func (b *ChannelBuilder) WithOrdererCapabilities(capabilities ...string) *ChannelBuilder {
+	b.channel.Orderer.Capabilities = capabilities
+	return b
+}
+
+// WithApplicationCapabilities replaces the capabilities of the application group.
+func (b *ChannelBuilder) WithApplicationCapabilities(capabilities ...string) *ChannelBuilder {
+	b.channel.Application.Capabilities = capabilities
+	return b
+}
+
+// WithChannelPolicy sets a policy of the channel group.
+func (b *ChannelBuilder) WithChannelPolicy(name string, policy Policy) *ChannelBuilder {
+	b.channel.Policies[name] = policy
+	return b
+}
+
+// WithOrdererPolicy sets a policy of the orderer group.
+func (b *ChannelBuilder) WithOrdererPolicy(name string, policy Policy) *ChannelBuilder {
+	b.channel.Orderer.Policies[name] = policy
+	return b
+}
+
+// WithApplicationPolicy sets a policy of the application group.
+func (b *ChannelBuilder) WithApplicationPolicy(name string, policy Policy) *ChannelBuilder {
+	b.channel.Application.Policies[name] = policy
+	return b
+}
+
+// WithACLs sets the ACLs of the application group.
+func (b *ChannelBuilder) WithACLs(acls map[string]string) *ChannelBuilder {
+	b.channel.Application.ACLs = acls
+	return b
+}
+
+// ChannelID returns the ID of the channel being built.
+func (b *ChannelBuilder) ChannelID() string {
+	return b.channelID
+}
+
+// Build returns the assembled Channel after validating it.
+func (b *ChannelBuilder) Build() (Channel, error) {
+	if b.channelID == "" {
+		return Channel{}, errors.New("channel ID is required")
+	}
+
+	channel, err := b.buildChannel()
+	if err != nil {
+		return Channel{}, fmt.Errorf("invalid channel %s: %v", b.channelID, err)
+	}
+
+	return channel, nil
+}
+
+// buildChannel returns the assembled Channel after validating it, without
+// requiring a channel ID.
+func (b *ChannelBuilder) buildChannel() (Channel, error) {
+	if b.err != nil {
+		return Channel{}, b.err
+	}
+
+	if b.channel.Orderer.OrdererType == orderer.ConsensusTypeSmartBFT && !b.channelCapabilities {
+		b.channel.Capabilities = []string{defaultSmartBFTChannelCapability}
+	}
+
+	if err := b.channel.Validate(); err != nil {
+		return Channel{}, err
+	}
+
+	return b.channel, nil
+}
+
+// GenesisBlock builds the channel and returns its application channel
+// genesis block.
+func (b *ChannelBuilder) GenesisBlock() (*cb.Block, error) {
+	channel, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return NewApplicationChannelGenesisBlock(channel, b.channelID)
+}
+
+func defaultImplicitMetaPolicies() map[string]Policy {
+	return map[string]Policy{
+		ReadersPolicyKey: {Type: ImplicitMetaPolicyType, Rule: defaultReadersRule},
+		WritersPolicyKey: {Type: ImplicitMetaPolicyType, Rule: defaultWritersRule},
+		AdminsPolicyKey:  {Type: ImplicitMetaPolicyType, Rule: defaultAdminsRule},
+	}
+}
+
+func defaultOrdererPolicies() map[string]Policy {
+	policies := defaultImplicitMetaPolicies()
+	policies[BlockValidationPolicyKey] = Policy{Type: ImplicitMetaPolicyType, Rule: defaultBlockValidationRule}
+	return policies
+}
+
+func defaultApplicationPolicies() map[string]Policy {
+	policies := defaultImplicitMetaPolicies()
+	policies[EndorsementPolicyKey] = Policy{Type: ImplicitMetaPolicyType, Rule: defaultEndorsementRule}
+	policies[LifecycleEndorsementPolicyKey] = Policy{Type: ImplicitMetaPolicyType, Rule: defaultEndorsementRule}
+	return policies
+}
+
+func defaultOrdererOrgPolicies(mspID string) map[string]Policy {
+	return map[string]Policy{
+		ReadersPolicyKey: {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.member')", mspID)},
+		WritersPolicyKey: {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.member')", mspID)},
+		AdminsPolicyKey:  {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.admin')", mspID)},
+	}
+}
+
+func defaultApplicationOrgPolicies(mspID string) map[string]Policy {
+	return map[string]Policy{
+		ReadersPolicyKey:     {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%[1]s.admin', '%[1]s.peer', '%[1]s.client')", mspID)},
+		WritersPolicyKey:     {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%[1]s.admin', '%[1]s.client')", mspID)},
+		AdminsPolicyKey:      {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.admin')", mspID)},
+		EndorsementPolicyKey: {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.peer')", mspID)},
+	}
+}
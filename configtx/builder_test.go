@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestChannelBuilder(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+	ordererOrg := smartBFTOrderer.Organizations[0]
+	ordererOrg.Policies = nil
+	appMSP, _ := baseMSP(t)
+	appMSP.Name = "Org1MSP"
+
+	builder := NewChannelBuilder("testchannel").
+		WithOrdererType(orderer.ConsensusTypeSmartBFT).
+		WithSmartBFT(smartBFTOrderer.SmartBFT).
+		WithOrdererOrg(ordererOrg).
+		WithApplicationOrg(Organization{Name: "Org1", MSP: appMSP}).
+		WithBatchTimeout(time.Second).
+		WithACLs(map[string]string{"peer/Propose": "/Channel/Application/Writers"})
+
+	channel, err := builder.Build()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(builder.ChannelID()).To(Equal("testchannel"))
+	gt.Expect(channel.Capabilities).To(Equal([]string{"V3_0"}))
+	gt.Expect(channel.Orderer.OrdererType).To(Equal(orderer.ConsensusTypeSmartBFT))
+	gt.Expect(channel.Orderer.BatchTimeout).To(Equal(time.Second))
+	gt.Expect(channel.Orderer.Organizations[0].Policies[AdminsPolicyKey]).To(Equal(Policy{
+		Type: SignaturePolicyType,
+		Rule: "OR('MSPID.admin')",
+	}))
+	gt.Expect(channel.Application.Organizations[0].Policies[EndorsementPolicyKey]).To(Equal(Policy{
+		Type: SignaturePolicyType,
+		Rule: "OR('Org1MSP.peer')",
+	}))
+
+	block, err := builder.GenesisBlock()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	envelope := &cb.Envelope{}
+	err = proto.Unmarshal(block.Data.Data[0], envelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(envelope.Payload, payload)
+	gt.Expect(err).NotTo(HaveOccurred())
+	configEnvelope := &cb.ConfigEnvelope{}
+	err = proto.Unmarshal(payload.Data, configEnvelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(configEnvelope.Config)
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.OrdererType).To(Equal(orderer.ConsensusTypeSmartBFT))
+	gt.Expect(ordererConfig.SmartBFT).To(Equal(smartBFTOrderer.SmartBFT))
+}
+
+func TestChannelBuilderFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	_, err := NewChannelBuilder("").Build()
+	gt.Expect(err).To(MatchError("channel ID is required"))
+
+	_, err = NewChannelBuilder("testchannel").Build()
+	gt.Expect(err).To(MatchError("invalid channel testchannel: invalid orderer: invalid etcdraft configuration: consenters are required"))
+
+	_, err = NewChannelBuilder("testchannel").WithOrdererType("pbft").GenesisBlock()
+	gt.Expect(err).To(MatchError("invalid channel testchannel: invalid orderer: unknown orderer type 'pbft'"))
+}
@@ -0,0 +1,141 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"fmt"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+)
+
+// BulkResult is the outcome of a bulk edit for one channel.
+type BulkResult struct {
+	ChannelID string `json:"channel_id"`
+	// Changes are the changes of the config of the channel, sorted by path.
+	// They are empty if the channel already matched the edit.
+	Changes []ConfigChange `json:"changes,omitempty"`
+	// Envelope is the marshaled envelope of the config update of the channel,
+	// signed by the signing identity of the bulk edit if there is one. It is
+	// nil if the edit failed or did not change the channel.
+	Envelope []byte `json:"envelope,omitempty"`
+	// Error describes why the edit failed for the channel.
+	Error string `json:"error,omitempty"`
+}
+
+// BulkReport is the consolidated report of a bulk edit, with one result per
+// channel in the order of the config blocks.
+type BulkReport []BulkResult
+
+// JSON returns the report encoded as JSON.
+func (r BulkReport) JSON() ([]byte, error) {
+	if r == nil {
+		r = BulkReport{}
+	}
+
+	return json.MarshalIndent(r, "", "\t")
+}
+
+// Failed returns the IDs of the channels for which the edit failed.
+func (r BulkReport) Failed() []string {
+	var failed []string
+	for _, result := range r {
+		if result.Error != "" {
+			failed = append(failed, result.ChannelID)
+		}
+	}
+
+	return failed
+}
+
+// BulkEdit applies the same changes, such as adding an org, rotating a CA or
+// bumping capabilities, to the config of each channel given by its latest
+// config block, and wraps the config update of each changed channel in an
+// envelope. If signer is not nil, it signs the updates and envelopes as for
+// NewSignedUpdateBundle. The changes are validated once up front; a channel
+// for which they fail to apply is recorded in the report and does not stop
+// the edit of the other channels.
+func BulkEdit(configBlocks []*cb.Block, changes []Change, signer *SigningIdentity) (BulkReport, error) {
+	for i, change := range changes {
+		if change == nil {
+			return nil, fmt.Errorf("change %d is nil", i)
+		}
+		err := change.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("invalid change %d (%s): %v", i, changeName(change), err)
+		}
+	}
+
+	channelIDs := map[string]bool{}
+	report := make(BulkReport, len(configBlocks))
+	for i, block := range configBlocks {
+		configEnvelope, channelHeader, err := unmarshalConfigBlock(block)
+		if err != nil {
+			return nil, fmt.Errorf("config block %d: %v", i, err)
+		}
+		if configEnvelope.Config == nil {
+			return nil, fmt.Errorf("config block %d: config envelope does not contain a config", i)
+		}
+		if channelIDs[channelHeader.ChannelId] {
+			return nil, fmt.Errorf("config block %d: duplicate config block of channel %s", i, channelHeader.ChannelId)
+		}
+		channelIDs[channelHeader.ChannelId] = true
+
+		result, err := bulkEditChannel(channelHeader.ChannelId, configEnvelope.Config, changes, signer)
+		if err != nil {
+			result.Error = err.Error()
+		}
+		report[i] = result
+	}
+
+	return report, nil
+}
+
+// bulkEditChannel applies the changes to the config of the channel. The
+// result is returned along with the error so that it names the channel.
+func bulkEditChannel(channelID string, config *cb.Config, changes []Change, signer *SigningIdentity) (BulkResult, error) {
+	result := BulkResult{ChannelID: channelID}
+
+	c := New(config)
+	err := c.Apply(changes)
+	if err != nil {
+		return result, err
+	}
+
+	result.Changes = diffConfigGroup("/"+ChannelGroupKey, c.original.ChannelGroup, c.updated.ChannelGroup)
+	if len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	if signer != nil {
+		bundle, err := c.NewSignedUpdateBundle(channelID, signer)
+		if err != nil {
+			return result, err
+		}
+		result.Envelope = bundle.Envelope
+
+		return result, nil
+	}
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate(channelID)
+	if err != nil {
+		return result, err
+	}
+
+	envelope, err := NewEnvelope(marshaledUpdate)
+	if err != nil {
+		return result, err
+	}
+
+	result.Envelope, err = proto.Marshal(envelope)
+	if err != nil {
+		return result, fmt.Errorf("marshaling envelope: %v", err)
+	}
+
+	return result, nil
+}
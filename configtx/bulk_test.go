@@ -0,0 +1,125 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestBulkEdit(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	channel1Block, err := NewApplicationChannelGenesisBlock(channel, "channel1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	channel.Application.Organizations = channel.Application.Organizations[:1]
+	channel2Block, err := NewApplicationChannelGenesisBlock(channel, "channel2")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signingMSP, signingKey := baseMSP(t)
+	signingIdentity := &SigningIdentity{
+		Certificate: signingMSP.Admins[0],
+		PrivateKey:  signingKey,
+		MSPID:       "MSPID",
+	}
+
+	report, err := BulkEdit([]*cb.Block{channel1Block, channel2Block}, []Change{RemoveApplicationOrg{Name: "Org2"}}, signingIdentity)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(report).To(HaveLen(2))
+	gt.Expect(report.Failed()).To(Equal([]string{"channel2"}))
+
+	gt.Expect(report[0].ChannelID).To(Equal("channel1"))
+	gt.Expect(report[0].Error).To(BeEmpty())
+	gt.Expect(report[0].Changes).To(ContainElement(ConfigChange{Path: "/Channel/Application/Org2", Kind: ConfigChangeRemoved}))
+	envelope := &cb.Envelope{}
+	err = proto.Unmarshal(report[0].Envelope, envelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(envelope.Signature).NotTo(BeEmpty())
+	info, err := Classify(envelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(info.HeaderType).To(Equal(cb.HeaderType_CONFIG_UPDATE))
+	gt.Expect(info.ChannelID).To(Equal("channel1"))
+
+	gt.Expect(report[1]).To(Equal(BulkResult{
+		ChannelID: "channel2",
+		Error:     "applying change 0 (RemoveApplicationOrg): application org Org2 does not exist",
+	}))
+
+	reportJSON, err := report.JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	parsed := BulkReport{}
+	err = json.Unmarshal(reportJSON, &parsed)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(parsed).To(Equal(report))
+}
+
+func TestBulkEditUnsigned(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	report, err := BulkEdit([]*cb.Block{block}, []Change{SetCapabilities{Group: ApplicationGroupKey, Capabilities: []string{"V2_0"}}}, nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(report).To(HaveLen(1))
+	gt.Expect(report.Failed()).To(BeEmpty())
+	gt.Expect(report[0].Changes).To(Equal([]ConfigChange{
+		{Path: "/Channel/Application/Values/Capabilities", Kind: ConfigChangeModified},
+	}))
+
+	envelope := &cb.Envelope{}
+	err = proto.Unmarshal(report[0].Envelope, envelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(envelope.Signature).To(BeEmpty())
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(envelope.Payload, payload)
+	gt.Expect(err).NotTo(HaveOccurred())
+	configUpdateEnvelope := &cb.ConfigUpdateEnvelope{}
+	err = proto.Unmarshal(payload.Data, configUpdateEnvelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(configUpdateEnvelope.Signatures).To(BeEmpty())
+
+	report, err = BulkEdit([]*cb.Block{block}, []Change{SetCapabilities{Group: ApplicationGroupKey, Capabilities: channel.Application.Capabilities}}, nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(report).To(Equal(BulkReport{{ChannelID: "testchannel"}}))
+
+	report, err = BulkEdit(nil, []Change{SetCapabilities{Group: ApplicationGroupKey, Capabilities: []string{"V2_0"}}}, nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	reportJSON, err := report.JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(reportJSON).To(MatchJSON("[]"))
+}
+
+func TestBulkEditFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	block, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	changes := []Change{RemoveApplicationOrg{Name: "Org2"}}
+
+	_, err = BulkEdit([]*cb.Block{block}, []Change{RemoveApplicationOrg{}}, nil)
+	gt.Expect(err).To(MatchError("invalid change 0 (RemoveApplicationOrg): organization name is required"))
+
+	_, err = BulkEdit([]*cb.Block{block}, []Change{nil}, nil)
+	gt.Expect(err).To(MatchError("change 0 is nil"))
+
+	_, err = BulkEdit([]*cb.Block{block, {}}, changes, nil)
+	gt.Expect(err).To(MatchError("config block 1: block is empty"))
+
+	_, err = BulkEdit([]*cb.Block{block, block}, changes, nil)
+	gt.Expect(err).To(MatchError("config block 1: duplicate config block of channel testchannel"))
+}
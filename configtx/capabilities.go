@@ -9,9 +9,13 @@ package configtx
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	ob "github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
 )
 
 // capabilitiesValue returns the config definition for a set of capabilities.
@@ -91,3 +95,143 @@ func getCapabilities(configGroup *cb.ConfigGroup) ([]string, error) {
 
 	return capabilities, nil
 }
+
+// withoutString returns the values other than value.
+func withoutString(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+// validateConsensusCapabilities checks that the channel and orderer
+// capabilities are recent enough for the consensus type. Orderers refuse to
+// activate a config enabling SmartBFT without the V3_0 channel capability or
+// etcdraft without the V1_4_2 orderer capability.
+func validateConsensusCapabilities(consensusType string, channelCapabilities, ordererCapabilities []string) error {
+	err := validateChannelConsensusCapabilities(consensusType, channelCapabilities)
+	if err != nil {
+		return err
+	}
+
+	return validateOrdererConsensusCapabilities(consensusType, ordererCapabilities)
+}
+
+// validateChannelConsensusCapabilities checks the channel capabilities
+// required by the consensus type.
+func validateChannelConsensusCapabilities(consensusType string, channelCapabilities []string) error {
+	if consensusType == orderer.ConsensusTypeSmartBFT && !hasCapabilityAtLeast(channelCapabilities, "V3_0") {
+		return errors.New("consensus type smartbft requires channel capability V3_0 or later")
+	}
+
+	return nil
+}
+
+// validateOrdererConsensusCapabilities checks the orderer capabilities
+// required by the consensus type.
+func validateOrdererConsensusCapabilities(consensusType string, ordererCapabilities []string) error {
+	if consensusType == orderer.ConsensusTypeEtcdRaft && !hasCapabilityAtLeast(ordererCapabilities, "V1_4_2") {
+		return errors.New("consensus type etcdraft requires orderer capability V1_4_2 or later")
+	}
+
+	return nil
+}
+
+// hasCapabilityAtLeast reports whether the capabilities contain a version
+// capability, such as V2_0, at least as recent as the minimum.
+func hasCapabilityAtLeast(capabilities []string, minimum string) bool {
+	minimumVersion, _ := capabilityVersion(minimum)
+	for _, capability := range capabilities {
+		version, ok := capabilityVersion(capability)
+		if ok && compareVersions(version, minimumVersion) >= 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// capabilityVersion parses a version capability of the form V<major>_<minor>
+// with an optional _<patch> suffix.
+func capabilityVersion(capability string) ([]int, bool) {
+	if !strings.HasPrefix(capability, "V") {
+		return nil, false
+	}
+
+	var version []int
+	for _, part := range strings.Split(capability[1:], "_") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, false
+		}
+		version = append(version, n)
+	}
+
+	return version, true
+}
+
+// compareVersions compares two versions, treating missing trailing
+// components as zero.
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// ordererConsensusType returns the consensus type of the orderer group, or
+// the empty string if it is not set.
+func ordererConsensusType(ordererGroup *cb.ConfigGroup) (string, error) {
+	if _, ok := ordererGroup.Values[orderer.ConsensusTypeKey]; !ok {
+		return "", nil
+	}
+
+	consensusType := &ob.ConsensusType{}
+	err := unmarshalConfigValueAtKey(ordererGroup, orderer.ConsensusTypeKey, consensusType)
+	if err != nil {
+		return "", err
+	}
+
+	return consensusType.Type, nil
+}
+
+// checkConsensusCapabilities checks the capabilities of the channel for a
+// change of the consensus type of the orderer group. Consenter changes of an
+// orderer already running the consensus type are not checked, as the
+// capabilities were accepted when the consensus type was enabled.
+func (o *OrdererGroup) checkConsensusCapabilities(consensusType string, ordererCapabilities []string) error {
+	current, err := ordererConsensusType(o.ordererGroup)
+	if err != nil {
+		return err
+	}
+	if current == consensusType {
+		return nil
+	}
+
+	var channelCapabilities []string
+	if o.channelGroup != nil {
+		channelCapabilities, err = getCapabilities(o.channelGroup)
+		if err != nil {
+			return err
+		}
+	}
+
+	return validateConsensusCapabilities(consensusType, channelCapabilities, ordererCapabilities)
+}
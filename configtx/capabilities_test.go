@@ -0,0 +1,186 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestConsensusCapabilities(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+	c := ordererConfigTx(t, etcdRaftOrderer)
+
+	err := c.Orderer().SetSmartBFTConsensusType(smartBFTOrderer.SmartBFT, orderer.ConsensusStateMaintenance)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Channel().RemoveCapability("V3_0")
+	gt.Expect(err).To(MatchError("removing capability V3_0: consensus type smartbft requires channel capability V3_0 or later"))
+
+	channelCapabilities, err := c.Channel().Capabilities()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channelCapabilities).To(Equal([]string{"V3_0"}))
+}
+
+func TestSetConfigurationKeepsConsensusCapabilities(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	soloOrderer, _ := baseSoloOrderer(t)
+	c := ordererConfigTx(t, soloOrderer)
+	err := c.Orderer().AddCapability("V2_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// without capabilities, the existing ones are kept and checked
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	etcdRaftOrderer.Capabilities = nil
+	etcdRaftOrderer.State = orderer.ConsensusStateMaintenance
+	err = c.Orderer().SetConfiguration(etcdRaftOrderer)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.OrdererType).To(Equal(orderer.ConsensusTypeEtcdRaft))
+	gt.Expect(ordererConfig.Capabilities).To(ConsistOf("V1_3", "V2_0"))
+
+	c = ordererConfigTx(t, soloOrderer)
+	err = c.Orderer().SetConfiguration(etcdRaftOrderer)
+	gt.Expect(err).To(MatchError("consensus type etcdraft requires orderer capability V1_4_2 or later"))
+}
+
+func TestConsensusCapabilitiesFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		change      func(c *ConfigTx) error
+		expectedErr string
+	}{
+		{
+			testName: "When setting smartbft without the V3_0 channel capability",
+			change: func(c *ConfigTx) error {
+				err := c.Channel().RemoveCapability("V3_0")
+				if err != nil {
+					return err
+				}
+				smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+				return c.Orderer().SetSmartBFTConsensusType(smartBFTOrderer.SmartBFT, orderer.ConsensusStateMaintenance)
+			},
+			expectedErr: "consensus type smartbft requires channel capability V3_0 or later",
+		},
+		{
+			testName: "When setting smartbft through the orderer configuration without the V3_0 channel capability",
+			change: func(c *ConfigTx) error {
+				err := c.Channel().AddCapability("V2_0")
+				if err != nil {
+					return err
+				}
+				err = c.Channel().RemoveCapability("V3_0")
+				if err != nil {
+					return err
+				}
+				smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+				return c.Orderer().SetConfiguration(smartBFTOrderer)
+			},
+			expectedErr: "consensus type smartbft requires channel capability V3_0 or later",
+		},
+		{
+			testName: "When removing the V3_0 channel capability of a smartbft orderer",
+			change: func(c *ConfigTx) error {
+				smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+				err := c.Orderer().SetSmartBFTConsensusType(smartBFTOrderer.SmartBFT, orderer.ConsensusStateMaintenance)
+				if err != nil {
+					return err
+				}
+				return c.Channel().RemoveCapability("V3_0")
+			},
+			expectedErr: "removing capability V3_0: consensus type smartbft requires channel capability V3_0 or later",
+		},
+		{
+			testName: "When setting etcdraft without the V1_4_2 orderer capability",
+			change: func(c *ConfigTx) error {
+				smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+				err := c.Orderer().SetSmartBFTConsensusType(smartBFTOrderer.SmartBFT, orderer.ConsensusStateMaintenance)
+				if err != nil {
+					return err
+				}
+				etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+				return c.Orderer().SetEtcdRaftConsensusType(etcdRaftOrderer.EtcdRaft, orderer.ConsensusStateMaintenance)
+			},
+			expectedErr: "consensus type etcdraft requires orderer capability V1_4_2 or later",
+		},
+		{
+			testName: "When removing the last orderer capability of an etcdraft orderer",
+			change: func(c *ConfigTx) error {
+				return c.Orderer().RemoveCapability("V1_3")
+			},
+			expectedErr: "removing capability V1_3: consensus type etcdraft requires orderer capability V1_4_2 or later",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+			c := ordererConfigTx(t, etcdRaftOrderer)
+
+			err := tt.change(&c)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
+
+func TestChannelConsensusCapabilitiesFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		builder     func() *ChannelBuilder
+		expectedErr string
+	}{
+		{
+			testName: "When building a smartbft channel with V2_0 channel capabilities",
+			builder: func() *ChannelBuilder {
+				smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+				return NewChannelBuilder("testchannel").
+					WithSmartBFT(smartBFTOrderer.SmartBFT).
+					WithChannelCapabilities("V2_0")
+			},
+			expectedErr: "invalid channel testchannel: consensus type smartbft requires channel capability V3_0 or later",
+		},
+		{
+			testName: "When building an etcdraft channel with V1_4 orderer capabilities",
+			builder: func() *ChannelBuilder {
+				etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+				return NewChannelBuilder("testchannel").
+					WithEtcdRaft(etcdRaftOrderer.EtcdRaft).
+					WithOrdererCapabilities("V1_4")
+			},
+			expectedErr: "invalid channel testchannel: consensus type etcdraft requires orderer capability V1_4_2 or later",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := tt.builder().Build()
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
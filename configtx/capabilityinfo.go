@@ -0,0 +1,290 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+)
+
+// CapabilityGroup is the config group a capability is set in.
+type CapabilityGroup string
+
+const (
+	// ChannelCapabilities are the capabilities of the /Channel group, which
+	// apply to orderers and peers.
+	ChannelCapabilities CapabilityGroup = "Channel"
+
+	// OrdererCapabilities are the capabilities of the /Channel/Orderer group,
+	// which only apply to orderers.
+	OrdererCapabilities CapabilityGroup = "Orderer"
+
+	// ApplicationCapabilities are the capabilities of the
+	// /Channel/Application group, which only apply to peers.
+	ApplicationCapabilities CapabilityGroup = "Application"
+)
+
+// CapabilityInfo describes a capability of a config group.
+type CapabilityInfo struct {
+	Group CapabilityGroup `json:"group"`
+	// Name is the capability string, such as V2_0.
+	Name string `json:"name"`
+	// MinFabricVersion is the oldest Fabric release whose nodes understand
+	// the capability, such as 2.0. Enabling the capability halts older nodes.
+	MinFabricVersion string `json:"min_fabric_version"`
+	// Features describe the features introduced by the capability. A version
+	// capability also enables the features of the earlier versions of its
+	// group.
+	Features []string `json:"features"`
+}
+
+// Feature is a feature enabled by a capability.
+type Feature struct {
+	Group CapabilityGroup `json:"group"`
+	// Capability is the capability introducing the feature.
+	Capability  string `json:"capability"`
+	Description string `json:"description"`
+}
+
+// capabilityCatalog lists the known capabilities of each group in version
+// order.
+var capabilityCatalog = []CapabilityInfo{
+	{
+		Group:            ChannelCapabilities,
+		Name:             "V1_1",
+		MinFabricVersion: "1.1",
+		Features:         []string{"MSP version 1.1"},
+	},
+	{
+		Group:            ChannelCapabilities,
+		Name:             "V1_3",
+		MinFabricVersion: "1.3",
+		Features:         []string{"MSP version 1.3 with identity classification by node OUs"},
+	},
+	{
+		Group:            ChannelCapabilities,
+		Name:             "V1_4_2",
+		MinFabricVersion: "1.4.2",
+		Features: []string{
+			"orderer endpoints set per orderer org",
+			"migration of the consensus type",
+		},
+	},
+	{
+		Group:            ChannelCapabilities,
+		Name:             "V1_4_3",
+		MinFabricVersion: "1.4.3",
+		Features:         []string{"MSP version 1.4.3 with admin and orderer node OUs"},
+	},
+	{
+		Group:            ChannelCapabilities,
+		Name:             "V2_0",
+		MinFabricVersion: "2.0",
+		Features:         []string{"MSP version 2.0"},
+	},
+	{
+		Group:            ChannelCapabilities,
+		Name:             "V3_0",
+		MinFabricVersion: "3.0",
+		Features: []string{
+			"SmartBFT consensus",
+			"orderer endpoints only set per orderer org",
+		},
+	},
+	{
+		Group:            OrdererCapabilities,
+		Name:             "V1_1",
+		MinFabricVersion: "1.1",
+		Features: []string{
+			"resubmission of transactions invalidated by a config change",
+			"rejection of transactions signed by expired certificates",
+			"predictable channel templates",
+		},
+	},
+	{
+		Group:            OrdererCapabilities,
+		Name:             "V1_4_2",
+		MinFabricVersion: "1.4.2",
+		Features: []string{
+			"etcdraft consensus",
+			"migration of the consensus type",
+			"channel creation policy as channel admins policy",
+		},
+	},
+	{
+		Group:            OrdererCapabilities,
+		Name:             "V2_0",
+		MinFabricVersion: "2.0",
+		Features:         []string{"Fabric 2.0 orderers"},
+	},
+	{
+		Group:            ApplicationCapabilities,
+		Name:             "V1_1",
+		MinFabricVersion: "1.1",
+		Features: []string{
+			"rejection of duplicate transaction IDs within a block",
+			"validation of chaincode versions",
+		},
+	},
+	{
+		Group:            ApplicationCapabilities,
+		Name:             "V1_2",
+		MinFabricVersion: "1.2",
+		Features: []string{
+			"channel ACLs",
+			"private data collections",
+			"collection upgrades",
+		},
+	},
+	{
+		Group:            ApplicationCapabilities,
+		Name:             "V1_3",
+		MinFabricVersion: "1.3",
+		Features:         []string{"key level endorsement policies"},
+	},
+	{
+		Group:            ApplicationCapabilities,
+		Name:             "V1_4_2",
+		MinFabricVersion: "1.4.2",
+		Features:         []string{"private data of invalid transactions is not stored"},
+	},
+	{
+		Group:            ApplicationCapabilities,
+		Name:             "V2_0",
+		MinFabricVersion: "2.0",
+		Features: []string{
+			"chaincode lifecycle approved by channel members",
+			"implicit private data collections per org",
+		},
+	},
+	{
+		Group:            ApplicationCapabilities,
+		Name:             "V2_5",
+		MinFabricVersion: "2.5",
+		Features:         []string{"purge of private data"},
+	},
+}
+
+// KnownCapabilities returns the capabilities known by this package, ordered
+// by group and version.
+func KnownCapabilities() []CapabilityInfo {
+	capabilities := make([]CapabilityInfo, len(capabilityCatalog))
+	for i, info := range capabilityCatalog {
+		capabilities[i] = copyCapabilityInfo(info)
+	}
+
+	return capabilities
+}
+
+// LookupCapability returns the description of the capability of the group.
+// It returns false for capabilities unknown to this package, such as vendor
+// specific ones.
+func LookupCapability(group CapabilityGroup, capability string) (CapabilityInfo, bool) {
+	for _, info := range capabilityCatalog {
+		if info.Group == group && info.Name == capability {
+			return copyCapabilityInfo(info), true
+		}
+	}
+
+	return CapabilityInfo{}, false
+}
+
+// EnabledFeatures returns the features enabled by the capabilities of the
+// channel, orderer and application groups of the updated config, including
+// the features of the versions preceding the enabled version capabilities.
+func (c *ConfigTx) EnabledFeatures() ([]Feature, error) {
+	var features []Feature
+	err := c.forEachCapabilityGroup(func(group CapabilityGroup, capabilities []string) {
+		for _, info := range capabilityCatalog {
+			if info.Group != group || !hasCapabilityAtLeast(capabilities, info.Name) {
+				continue
+			}
+			for _, description := range info.Features {
+				features = append(features, Feature{
+					Group:       group,
+					Capability:  info.Name,
+					Description: description,
+				})
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return features, nil
+}
+
+// MinFabricVersion returns the oldest Fabric release whose nodes understand
+// every known capability enabled in the updated config, or the empty string
+// if no known capability is enabled.
+func (c *ConfigTx) MinFabricVersion() (string, error) {
+	var minimum string
+	var minimumVersion []int
+	err := c.forEachCapabilityGroup(func(group CapabilityGroup, capabilities []string) {
+		for _, capability := range capabilities {
+			info, ok := LookupCapability(group, capability)
+			if !ok {
+				continue
+			}
+			version := fabricVersionComponents(info.MinFabricVersion)
+			if compareVersions(version, minimumVersion) > 0 {
+				minimum, minimumVersion = info.MinFabricVersion, version
+			}
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return minimum, nil
+}
+
+// forEachCapabilityGroup calls f with the capabilities of each config group
+// of the updated config able to hold capabilities.
+func (c *ConfigTx) forEachCapabilityGroup(f func(CapabilityGroup, []string)) error {
+	groups := []struct {
+		group       CapabilityGroup
+		configGroup *cb.ConfigGroup
+	}{
+		{group: ChannelCapabilities, configGroup: c.updated.ChannelGroup},
+		{group: OrdererCapabilities, configGroup: c.updated.ChannelGroup.Groups[OrdererGroupKey]},
+		{group: ApplicationCapabilities, configGroup: c.updated.ChannelGroup.Groups[ApplicationGroupKey]},
+	}
+
+	for _, g := range groups {
+		if g.configGroup == nil {
+			continue
+		}
+		capabilities, err := getCapabilities(g.configGroup)
+		if err != nil {
+			return fmt.Errorf("retrieving %s capabilities: %v", strings.ToLower(string(g.group)), err)
+		}
+		f(g.group, capabilities)
+	}
+
+	return nil
+}
+
+// fabricVersionComponents parses a Fabric release version such as 1.4.2.
+func fabricVersionComponents(version string) []int {
+	var components []int
+	for _, part := range strings.Split(version, ".") {
+		n, _ := strconv.Atoi(part)
+		components = append(components, n)
+	}
+
+	return components
+}
+
+func copyCapabilityInfo(info CapabilityInfo) CapabilityInfo {
+	info.Features = append([]string(nil), info.Features...)
+	return info
+}
@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestLookupCapability(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	info, ok := LookupCapability(ApplicationCapabilities, "V2_5")
+	gt.Expect(ok).To(BeTrue())
+	gt.Expect(info).To(Equal(CapabilityInfo{
+		Group:            ApplicationCapabilities,
+		Name:             "V2_5",
+		MinFabricVersion: "2.5",
+		Features:         []string{"purge of private data"},
+	}))
+
+	info.Features[0] = "modified"
+	info, _ = LookupCapability(ApplicationCapabilities, "V2_5")
+	gt.Expect(info.Features).To(Equal([]string{"purge of private data"}))
+
+	_, ok = LookupCapability(OrdererCapabilities, "V2_5")
+	gt.Expect(ok).To(BeFalse())
+	_, ok = LookupCapability(ChannelCapabilities, "VendorFeature")
+	gt.Expect(ok).To(BeFalse())
+
+	known := KnownCapabilities()
+	gt.Expect(known).To(HaveLen(len(capabilityCatalog)))
+	for _, info := range known {
+		_, ok := capabilityVersion(info.Name)
+		gt.Expect(ok).To(BeTrue())
+		gt.Expect(info.Features).NotTo(BeEmpty())
+	}
+}
+
+func TestEnabledFeatures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(channelGroup, capabilitiesValue([]string{"V1_4_3", "VendorFeature"}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(channelGroup.Groups[ApplicationGroupKey], capabilitiesValue([]string{"V1_3"}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	features, err := c.EnabledFeatures()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(features).To(Equal([]Feature{
+		{Group: ChannelCapabilities, Capability: "V1_1", Description: "MSP version 1.1"},
+		{Group: ChannelCapabilities, Capability: "V1_3", Description: "MSP version 1.3 with identity classification by node OUs"},
+		{Group: ChannelCapabilities, Capability: "V1_4_2", Description: "orderer endpoints set per orderer org"},
+		{Group: ChannelCapabilities, Capability: "V1_4_2", Description: "migration of the consensus type"},
+		{Group: ChannelCapabilities, Capability: "V1_4_3", Description: "MSP version 1.4.3 with admin and orderer node OUs"},
+		{Group: ApplicationCapabilities, Capability: "V1_1", Description: "rejection of duplicate transaction IDs within a block"},
+		{Group: ApplicationCapabilities, Capability: "V1_1", Description: "validation of chaincode versions"},
+		{Group: ApplicationCapabilities, Capability: "V1_2", Description: "channel ACLs"},
+		{Group: ApplicationCapabilities, Capability: "V1_2", Description: "private data collections"},
+		{Group: ApplicationCapabilities, Capability: "V1_2", Description: "collection upgrades"},
+		{Group: ApplicationCapabilities, Capability: "V1_3", Description: "key level endorsement policies"},
+	}))
+
+	version, err := c.MinFabricVersion()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(version).To(Equal("1.4.3"))
+
+	err = c.Application().AddCapability("V2_5")
+	gt.Expect(err).NotTo(HaveOccurred())
+	version, err = c.MinFabricVersion()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(version).To(Equal("2.5"))
+}
+
+func TestEnabledFeaturesWithoutCapabilities(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	delete(channelGroup.Groups[ApplicationGroupKey].Values, CapabilitiesKey)
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	features, err := c.EnabledFeatures()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(features).To(BeEmpty())
+
+	version, err := c.MinFabricVersion()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(version).To(BeEmpty())
+}
+
+func TestEnabledFeaturesFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup.Groups[ApplicationGroupKey].Values[CapabilitiesKey] = &cb.ConfigValue{Value: []byte("garbage")}
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	_, err = c.EnabledFeatures()
+	gt.Expect(err).To(MatchError(ContainSubstring("retrieving application capabilities: unmarshaling capabilities: ")))
+
+	_, err = c.MinFabricVersion()
+	gt.Expect(err).To(MatchError(ContainSubstring("retrieving application capabilities: unmarshaling capabilities: ")))
+}
@@ -0,0 +1,360 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// CertificateLocation is a place in the config where a certificate appears.
+type CertificateLocation struct {
+	// Org is the name of the organization the certificate belongs to. It is
+	// empty for consenters whose organization cannot be determined.
+	Org string
+	// Role describes the purpose of the certificate, e.g. "root cert" or
+	// "consenter server tls cert".
+	Role string
+	// Path identifies the certificate within the config, e.g.
+	// /Channel/Application/Org1/Values/MSP/RootCerts[0].
+	Path string
+}
+
+// ConfiguredCertificate is a certificate of the config along with its
+// location.
+type ConfiguredCertificate struct {
+	CertificateLocation
+	Certificate *x509.Certificate
+}
+
+// Certificates returns every certificate of the updated config along with its
+// role and owning org, in the order of FindCertificate: the MSP certificates
+// of the application, orderer and consortium orgs followed by the consenter
+// certificates. A certificate appearing in several places is returned once
+// per location.
+func (c *ConfigTx) Certificates() ([]ConfiguredCertificate, error) {
+	var certs []ConfiguredCertificate
+
+	err := walkCertificates(c.updated.ChannelGroup, func(location CertificateLocation, cert **x509.Certificate) bool {
+		if *cert != nil {
+			certs = append(certs, ConfiguredCertificate{
+				CertificateLocation: location,
+				Certificate:         *cert,
+			})
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return certs, nil
+}
+
+// FindCertificate returns every location in the updated config where a
+// certificate with the given subject key identifier appears. This covers
+// the MSPs of application, orderer and consortium organizations as well as
+// the etcdraft and SmartBFT consenters. Certificates without a subject key
+// identifier are never matched.
+func (c *ConfigTx) FindCertificate(ski []byte) ([]CertificateLocation, error) {
+	var locations []CertificateLocation
+
+	err := walkCertificates(c.updated.ChannelGroup, func(location CertificateLocation, cert **x509.Certificate) bool {
+		if *cert != nil && len((*cert).SubjectKeyId) > 0 && bytes.Equal((*cert).SubjectKeyId, ski) {
+			locations = append(locations, location)
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return locations, nil
+}
+
+// ReplaceCertificateEverywhere replaces every occurrence of the old
+// certificate in the updated config with the new certificate. This covers
+// the same locations as FindCertificate. Either all occurrences are replaced
+// or, if an error occurs, the updated config is left untouched.
+func (c *ConfigTx) ReplaceCertificateEverywhere(old, new *x509.Certificate) (err error) {
+	defer c.observe("ConfigTx.ReplaceCertificateEverywhere", time.Now(), &err)
+
+	if old == nil || new == nil {
+		return errors.New("old and new certificates are required")
+	}
+
+	channelGroup := proto.Clone(c.updated.ChannelGroup).(*cb.ConfigGroup)
+
+	var replaced bool
+	err = walkCertificates(channelGroup, func(location CertificateLocation, cert **x509.Certificate) bool {
+		if *cert == nil || !(*cert).Equal(old) {
+			return false
+		}
+		*cert = new
+		replaced = true
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	if !replaced {
+		return fmt.Errorf("certificate with serial number %d not found", old.SerialNumber)
+	}
+
+	c.updated.ChannelGroup = channelGroup
+
+	return nil
+}
+
+// certificateVisitor is called with the location of every certificate slot
+// in the config. Slots for optional certificates may hold nil. The visitor
+// may replace the certificate in the slot, in which case it must return true
+// so that the change is written back to the config.
+type certificateVisitor func(location CertificateLocation, cert **x509.Certificate) bool
+
+// walkCertificates calls visit for every certificate of the channel group.
+func walkCertificates(channelGroup *cb.ConfigGroup, visit certificateVisitor) error {
+	err := walkOrgMSPs(channelGroup, func(orgName, path string, msp *MSP) bool {
+		return msp.walkCertificates(orgName, path, visit)
+	})
+	if err != nil {
+		return err
+	}
+
+	if ordererGroup, ok := channelGroup.Groups[OrdererGroupKey]; ok {
+		err := walkConsenterCertificates(&OrdererGroup{channelGroup: channelGroup, ordererGroup: ordererGroup}, visit)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mspVisitor is called with the MSP of an org and the path of its MSP value.
+// The visitor may modify the MSP, in which case it must return true so that
+// the change is written back to the config.
+type mspVisitor func(orgName, path string, msp *MSP) bool
+
+// walkOrgMSPs calls visit for the MSP of every application, orderer and
+// consortium org of the channel group.
+func walkOrgMSPs(channelGroup *cb.ConfigGroup, visit mspVisitor) error {
+	for _, groupKey := range []string{ApplicationGroupKey, OrdererGroupKey} {
+		group, ok := channelGroup.Groups[groupKey]
+		if !ok {
+			continue
+		}
+		err := walkOrgGroupMSPs("/"+ChannelGroupKey+"/"+groupKey, group, visit)
+		if err != nil {
+			return err
+		}
+	}
+
+	if consortiumsGroup, ok := channelGroup.Groups[ConsortiumsGroupKey]; ok {
+		for _, consortiumName := range sortedGroupKeys(consortiumsGroup) {
+			path := fmt.Sprintf("/%s/%s/%s", ChannelGroupKey, ConsortiumsGroupKey, consortiumName)
+			err := walkOrgGroupMSPs(path, consortiumsGroup.Groups[consortiumName], visit)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// walkOrgGroupMSPs visits the MSP of every org group under the group at path.
+func walkOrgGroupMSPs(path string, group *cb.ConfigGroup, visit mspVisitor) error {
+	for _, orgName := range sortedGroupKeys(group) {
+		orgGroup := group.Groups[orgName]
+		if _, ok := orgGroup.Values[MSPKey]; !ok {
+			continue
+		}
+
+		msp, err := getMSPConfig(orgGroup)
+		if err != nil {
+			return fmt.Errorf("retrieving msp of org %s: %v", orgName, err)
+		}
+
+		mspPath := fmt.Sprintf("%s/%s/Values/%s", path, orgName, MSPKey)
+		if !visit(orgName, mspPath, &msp) {
+			continue
+		}
+
+		err = msp.setConfig(orgGroup)
+		if err != nil {
+			return fmt.Errorf("updating msp of org %s: %v", orgName, err)
+		}
+	}
+
+	return nil
+}
+
+// walkCertificates visits the certificates of the MSP and reports whether
+// any of them was replaced.
+func (m *MSP) walkCertificates(orgName, path string, visit certificateVisitor) bool {
+	var modified bool
+
+	lists := []struct {
+		role  string
+		field string
+		certs []*x509.Certificate
+	}{
+		{role: "root cert", field: "RootCerts", certs: m.RootCerts},
+		{role: "intermediate cert", field: "IntermediateCerts", certs: m.IntermediateCerts},
+		{role: "admin cert", field: "Admins", certs: m.Admins},
+		{role: "tls root cert", field: "TLSRootCerts", certs: m.TLSRootCerts},
+		{role: "tls intermediate cert", field: "TLSIntermediateCerts", certs: m.TLSIntermediateCerts},
+	}
+	for _, list := range lists {
+		for i := range list.certs {
+			modified = visit(CertificateLocation{
+				Org:  orgName,
+				Role: list.role,
+				Path: fmt.Sprintf("%s/%s[%d]", path, list.field, i),
+			}, &list.certs[i]) || modified
+		}
+	}
+
+	for i := range m.OrganizationalUnitIdentifiers {
+		modified = visit(CertificateLocation{
+			Org:  orgName,
+			Role: "ou identifier cert",
+			Path: fmt.Sprintf("%s/OrganizationalUnitIdentifiers[%d]", path, i),
+		}, &m.OrganizationalUnitIdentifiers[i].Certificate) || modified
+	}
+
+	nodeOUs := []struct {
+		role  string
+		field string
+		cert  **x509.Certificate
+	}{
+		{role: "client ou identifier cert", field: "ClientOUIdentifier", cert: &m.NodeOUs.ClientOUIdentifier.Certificate},
+		{role: "peer ou identifier cert", field: "PeerOUIdentifier", cert: &m.NodeOUs.PeerOUIdentifier.Certificate},
+		{role: "admin ou identifier cert", field: "AdminOUIdentifier", cert: &m.NodeOUs.AdminOUIdentifier.Certificate},
+		{role: "orderer ou identifier cert", field: "OrdererOUIdentifier", cert: &m.NodeOUs.OrdererOUIdentifier.Certificate},
+	}
+	for _, nodeOU := range nodeOUs {
+		modified = visit(CertificateLocation{
+			Org:  orgName,
+			Role: nodeOU.role,
+			Path: fmt.Sprintf("%s/NodeOUs/%s", path, nodeOU.field),
+		}, nodeOU.cert) || modified
+	}
+
+	return modified
+}
+
+// walkConsenterCertificates visits the certificates of the etcdraft or
+// SmartBFT consenters of the orderer group and writes replaced certificates
+// back to the consensus metadata.
+func walkConsenterCertificates(o *OrdererGroup, visit certificateVisitor) error {
+	ordererConfig, err := o.Configuration()
+	if err != nil {
+		return fmt.Errorf("retrieving orderer config: %v", err)
+	}
+
+	path := fmt.Sprintf("/%s/%s/Values/%s/Consenters", ChannelGroupKey, OrdererGroupKey, orderer.ConsensusTypeKey)
+	var modified bool
+
+	switch ordererConfig.OrdererType {
+	case orderer.ConsensusTypeEtcdRaft:
+		for i := range ordererConfig.EtcdRaft.Consenters {
+			consenter := &ordererConfig.EtcdRaft.Consenters[i]
+			org := tlsCertOrg(ordererConfig.Organizations, consenter.ServerTLSCert)
+			modified = visit(CertificateLocation{
+				Org:  org,
+				Role: "consenter client tls cert",
+				Path: fmt.Sprintf("%s[%d]/ClientTLSCert", path, i),
+			}, &consenter.ClientTLSCert) || modified
+			modified = visit(CertificateLocation{
+				Org:  org,
+				Role: "consenter server tls cert",
+				Path: fmt.Sprintf("%s[%d]/ServerTLSCert", path, i),
+			}, &consenter.ServerTLSCert) || modified
+		}
+		if modified {
+			return o.SetEtcdRaftConsensusType(ordererConfig.EtcdRaft, ordererConfig.State)
+		}
+	case orderer.ConsensusTypeSmartBFT:
+		for i := range ordererConfig.SmartBFT.Consenters {
+			consenter := &ordererConfig.SmartBFT.Consenters[i]
+			org := mspIDOrg(ordererConfig.Organizations, consenter.MSPID)
+			modified = visit(CertificateLocation{
+				Org:  org,
+				Role: "consenter identity cert",
+				Path: fmt.Sprintf("%s[%d]/Identity", path, i),
+			}, &consenter.Identity) || modified
+			modified = visit(CertificateLocation{
+				Org:  org,
+				Role: "consenter client tls cert",
+				Path: fmt.Sprintf("%s[%d]/ClientTLSCert", path, i),
+			}, &consenter.ClientTLSCert) || modified
+			modified = visit(CertificateLocation{
+				Org:  org,
+				Role: "consenter server tls cert",
+				Path: fmt.Sprintf("%s[%d]/ServerTLSCert", path, i),
+			}, &consenter.ServerTLSCert) || modified
+		}
+		if modified {
+			return o.SetSmartBFTConsensusType(ordererConfig.SmartBFT, ordererConfig.State)
+		}
+	}
+
+	return nil
+}
+
+// sortedGroupKeys returns the names of the groups of the config group in
+// sorted order.
+func sortedGroupKeys(group *cb.ConfigGroup) []string {
+	keys := make([]string, 0, len(group.Groups))
+	for key := range group.Groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// verifyChain returns a function checking that a certificate chains to one of
+// the root certs through the intermediate certs. If ignoreExpiry is set, the
+// chain is verified as of the time the certificate became valid, as done for
+// the certificates of a config which may outlive them; otherwise it is
+// verified as of now, as done for the certificate presented by an endpoint.
+func verifyChain(rootCerts, intermediateCerts []*x509.Certificate, ignoreExpiry bool) func(*x509.Certificate) error {
+	roots := x509.NewCertPool()
+	for _, cert := range rootCerts {
+		roots.AddCert(cert)
+	}
+	intermediates := x509.NewCertPool()
+	for _, cert := range intermediateCerts {
+		intermediates.AddCert(cert)
+	}
+
+	return func(cert *x509.Certificate) error {
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}
+		if ignoreExpiry {
+			opts.CurrentTime = cert.NotBefore
+		}
+
+		_, err := cert.Verify(opts)
+		return err
+	}
+}
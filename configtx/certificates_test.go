@@ -0,0 +1,242 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"fmt"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestFindCertificate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	consenterCert, _ := generateCACertAndPrivateKey(t, "consenter")
+	etcdRaftOrderer.EtcdRaft.Consenters[1].ServerTLSCert = consenterCert
+
+	ordererGroup, err := newOrdererGroup(etcdRaftOrderer)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup := newConfigGroup()
+	channelGroup.Groups[OrdererGroupKey] = ordererGroup
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	locations, err := c.FindCertificate(consenterCert.SubjectKeyId)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(locations).To(Equal([]CertificateLocation{
+		{
+			Role: "consenter server tls cert",
+			Path: "/Channel/Orderer/Values/ConsensusType/Consenters[1]/ServerTLSCert",
+		},
+	}))
+
+	rootCert := etcdRaftOrderer.Organizations[0].MSP.RootCerts[0]
+	locations, err = c.FindCertificate(rootCert.SubjectKeyId)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(locations).To(ContainElement(CertificateLocation{
+		Org:  "OrdererOrg",
+		Role: "root cert",
+		Path: "/Channel/Orderer/OrdererOrg/Values/MSP/RootCerts[0]",
+	}))
+	gt.Expect(locations).To(ContainElement(CertificateLocation{
+		Org:  "OrdererOrg",
+		Role: "orderer ou identifier cert",
+		Path: "/Channel/Orderer/OrdererOrg/Values/MSP/NodeOUs/OrdererOUIdentifier",
+	}))
+
+	locations, err = c.FindCertificate([]byte("unknown"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(locations).To(BeEmpty())
+
+	locations, err = c.FindCertificate(nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(locations).To(BeEmpty())
+}
+
+func TestCertificates(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSmartBFT)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	certs, err := c.Certificates()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(certs).To(ContainElement(ConfiguredCertificate{
+		CertificateLocation: CertificateLocation{
+			Org:  "OrdererOrg",
+			Role: "root cert",
+			Path: "/Channel/Orderer/OrdererOrg/Values/MSP/RootCerts[0]",
+		},
+		Certificate: ordererConfig.Organizations[0].MSP.RootCerts[0],
+	}))
+	gt.Expect(certs).To(ContainElement(ConfiguredCertificate{
+		CertificateLocation: CertificateLocation{
+			Org:  "OrdererOrg",
+			Role: "consenter identity cert",
+			Path: "/Channel/Orderer/Values/ConsensusType/Consenters[0]/Identity",
+		},
+		Certificate: ordererConfig.SmartBFT.Consenters[0].Identity,
+	}))
+
+	for _, cert := range certs {
+		gt.Expect(cert.Certificate).NotTo(BeNil())
+		gt.Expect(cert.Certificate.SubjectKeyId).NotTo(BeEmpty())
+		locations, err := c.FindCertificate(cert.Certificate.SubjectKeyId)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(locations).To(ContainElement(cert.CertificateLocation))
+	}
+}
+
+func TestFindCertificateAcrossGroups(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	msp, err := c.Application().Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	admin, _ := generateCACertAndPrivateKey(t, "org1-admin")
+	msp.Admins = append(msp.Admins, admin)
+	err = c.Application().Organization("Org1").SetMSP(msp)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	consortiumsChannelGroup, _, err := baseConsortiumChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c.updated.ChannelGroup.Groups[ConsortiumsGroupKey] = consortiumsChannelGroup.Groups[ConsortiumsGroupKey]
+	err = c.Consortium("Consortium1").Organization("Org1").SetMSP(msp)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	locations, err := c.FindCertificate(admin.SubjectKeyId)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(locations).To(Equal([]CertificateLocation{
+		{
+			Org:  "Org1",
+			Role: "admin cert",
+			Path: "/Channel/Application/Org1/Values/MSP/Admins[1]",
+		},
+		{
+			Org:  "Org1",
+			Role: "admin cert",
+			Path: "/Channel/Consortiums/Consortium1/Org1/Values/MSP/Admins[1]",
+		},
+	}))
+}
+
+func TestReplaceCertificateEverywhere(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSmartBFT)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	oldCert := ordererConfig.Organizations[0].MSP.RootCerts[0]
+	oldLocations, err := c.FindCertificate(oldCert.SubjectKeyId)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(oldLocations).NotTo(BeEmpty())
+
+	consenterCert := ordererConfig.SmartBFT.Consenters[0].Identity
+	newConsenterCert, _ := generateCACertAndPrivateKey(t, "consenter")
+	err = c.ReplaceCertificateEverywhere(consenterCert, newConsenterCert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	newCert, _ := generateCACertAndPrivateKey(t, "orderer-org")
+	err = c.ReplaceCertificateEverywhere(oldCert, newCert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	locations, err := c.FindCertificate(oldCert.SubjectKeyId)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(locations).To(BeEmpty())
+
+	locations, err = c.FindCertificate(newCert.SubjectKeyId)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(locations).To(Equal(oldLocations))
+
+	ordererConfig, err = c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.Organizations[0].MSP.RootCerts).To(Equal([]*x509.Certificate{newCert}))
+	for _, consenter := range ordererConfig.SmartBFT.Consenters {
+		gt.Expect(consenter.Identity).NotTo(Equal(consenterCert))
+	}
+	gt.Expect(ordererConfig.SmartBFT.Consenters[0].Identity).To(Equal(newConsenterCert))
+}
+
+func TestReplaceCertificateEverywhereFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeEtcdRaft)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	cert, _ := generateCACertAndPrivateKey(t, "unknown")
+	err = c.ReplaceCertificateEverywhere(cert, cert)
+	gt.Expect(err).To(MatchError(fmt.Sprintf("certificate with serial number %d not found", cert.SerialNumber)))
+
+	err = c.ReplaceCertificateEverywhere(nil, cert)
+	gt.Expect(err).To(MatchError("old and new certificates are required"))
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	rootCert := ordererConfig.Organizations[0].MSP.RootCerts[0]
+	c.updated.ChannelGroup.Groups[OrdererGroupKey].Values[orderer.ConsensusTypeKey].Value = []byte("garbage")
+	original := proto.Clone(c.updated).(*cb.Config)
+
+	err = c.ReplaceCertificateEverywhere(rootCert, cert)
+	gt.Expect(err).To(MatchError(ContainSubstring("retrieving orderer config")))
+	gt.Expect(proto.Equal(c.updated, original)).To(BeTrue())
+}
+
+func TestVerifyChain(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	rootCert, rootPrivKey := generateCACertAndPrivateKey(t, "org1.example.com")
+	intermediateCert, intermediatePrivKey := generateIntermediateCACertAndPrivateKey(t, "org1.example.com", rootCert, rootPrivKey)
+	cert, _ := generateCertAndPrivateKeyFromCACert(t, "org1.example.com", intermediateCert, intermediatePrivKey)
+	otherRootCert, _ := generateCACertAndPrivateKey(t, "org2.example.com")
+
+	oldRootTemplate := &x509.Certificate{
+		SerialNumber:          generateSerialNumber(t),
+		NotBefore:             time.Now().Add(-3 * time.Hour),
+		NotAfter:              time.Now().Add(YEAR),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	oldRootCert, oldRootPrivKey := generateCertAndPrivateKey(t, oldRootTemplate, oldRootTemplate, nil)
+	expiredCert, _ := generateCertAndPrivateKey(t, &x509.Certificate{
+		SerialNumber: generateSerialNumber(t),
+		NotBefore:    time.Now().Add(-2 * time.Hour),
+		NotAfter:     time.Now().Add(-time.Hour),
+	}, oldRootCert, oldRootPrivKey)
+
+	gt.Expect(verifyChain([]*x509.Certificate{rootCert}, []*x509.Certificate{intermediateCert}, false)(cert)).To(Succeed())
+	gt.Expect(verifyChain([]*x509.Certificate{rootCert}, []*x509.Certificate{intermediateCert}, true)(cert)).To(Succeed())
+	gt.Expect(verifyChain([]*x509.Certificate{rootCert}, nil, true)(cert)).NotTo(Succeed())
+	gt.Expect(verifyChain([]*x509.Certificate{otherRootCert}, []*x509.Certificate{intermediateCert}, true)(cert)).NotTo(Succeed())
+	gt.Expect(verifyChain(nil, nil, true)(cert)).NotTo(Succeed())
+
+	gt.Expect(verifyChain([]*x509.Certificate{oldRootCert}, nil, true)(expiredCert)).To(Succeed())
+	gt.Expect(verifyChain([]*x509.Certificate{oldRootCert}, nil, false)(expiredCert)).To(MatchError(ContainSubstring("certificate has expired")))
+}
@@ -0,0 +1,465 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/hyperledger/fabric-config/configtx/membership"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
+	"gopkg.in/yaml.v2"
+)
+
+// ChangeOp names the operation of a ChangeSpec.
+type ChangeOp string
+
+const (
+	// OpAddApplicationOrg adds the org of the spec to the application group.
+	OpAddApplicationOrg ChangeOp = "add-application-org"
+	// OpRemoveApplicationOrg removes the org named by the spec from the
+	// application group.
+	OpRemoveApplicationOrg ChangeOp = "remove-application-org"
+	// OpAddOrdererOrg adds the org of the spec to the orderer group.
+	OpAddOrdererOrg ChangeOp = "add-orderer-org"
+	// OpRemoveOrdererOrg removes the org named by the spec from the orderer
+	// group, along with its consenters and legacy orderer addresses if
+	// remove_consenters is set.
+	OpRemoveOrdererOrg ChangeOp = "remove-orderer-org"
+	// OpSetPolicy sets the policy of the spec in the group of the spec.
+	OpSetPolicy ChangeOp = "set-policy"
+	// OpSetBatchTimeout sets the batch timeout of the orderer.
+	OpSetBatchTimeout ChangeOp = "set-batch-timeout"
+	// OpSetBatchSize sets the batch size of the orderer.
+	OpSetBatchSize ChangeOp = "set-batch-size"
+	// OpAddConsenter adds the consenter of the spec to an etcdraft orderer.
+	OpAddConsenter ChangeOp = "add-consenter"
+	// OpAddSmartBFTConsenter adds the consenter of the spec to a SmartBFT
+	// orderer.
+	OpAddSmartBFTConsenter ChangeOp = "add-smartbft-consenter"
+	// OpRemoveSmartBFTConsenter removes the consenter with the id of the spec
+	// from a SmartBFT orderer.
+	OpRemoveSmartBFTConsenter ChangeOp = "remove-smartbft-consenter"
+	// OpRotateCA replaces the old root cert of the spec with the new root
+	// cert in the MSP of the org in the group of the spec.
+	OpRotateCA ChangeOp = "rotate-ca"
+)
+
+// ChangeDocument is the serializable form of a list of changes, meant to be
+// kept in version control and applied with ApplyDocument.
+//
+// An example in YAML:
+//
+//	changes:
+//	- op: set-batch-timeout
+//	  timeout: 2s
+//	- op: set-batch-size
+//	  max_message_count: 500
+//	  absolute_max_bytes: 10 MB
+//	  preferred_max_bytes: 2 MB
+//	- op: set-policy
+//	  group: Application/Org1
+//	  name: Endorsement
+//	  policy:
+//	    type: Signature
+//	    rule: OR('Org1MSP.peer')
+//	- op: rotate-ca
+//	  group: Orderer/OrdererOrg
+//	  old_cert: |
+//	    -----BEGIN CERTIFICATE-----
+//	    ...
+//	  new_cert: |
+//	    -----BEGIN CERTIFICATE-----
+//	    ...
+type ChangeDocument struct {
+	Changes []ChangeSpec `json:"changes" yaml:"changes"`
+}
+
+// ChangeSpec is the serializable form of a Change. Op names the operation and
+// the other fields hold its parameters; fields which are not parameters of the
+// operation must be empty. Certificates are PEM encoded.
+type ChangeSpec struct {
+	Op ChangeOp `json:"op" yaml:"op"`
+	// Group is the path of a group below the channel group, e.g.
+	// Application/Org1, for set-policy and rotate-ca.
+	Group string `json:"group,omitempty" yaml:"group,omitempty"`
+	// Name is the name of the policy for set-policy and of the org for
+	// remove-application-org and remove-orderer-org.
+	Name   string      `json:"name,omitempty" yaml:"name,omitempty"`
+	Org    *OrgSpec    `json:"org,omitempty" yaml:"org,omitempty"`
+	Policy *PolicySpec `json:"policy,omitempty" yaml:"policy,omitempty"`
+	// Timeout is the batch timeout for set-batch-timeout, such as 2s.
+	Timeout string `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// MaxMessageCount, AbsoluteMaxBytes and PreferredMaxBytes are the batch
+	// size for set-batch-size. The sizes are parsed by
+	// orderer.ParseByteSize, such as 10 MB.
+	MaxMessageCount   uint32         `json:"max_message_count,omitempty" yaml:"max_message_count,omitempty"`
+	AbsoluteMaxBytes  string         `json:"absolute_max_bytes,omitempty" yaml:"absolute_max_bytes,omitempty"`
+	PreferredMaxBytes string         `json:"preferred_max_bytes,omitempty" yaml:"preferred_max_bytes,omitempty"`
+	Consenter         *ConsenterSpec `json:"consenter,omitempty" yaml:"consenter,omitempty"`
+	// ID is the id of the consenter for remove-smartbft-consenter.
+	ID uint64 `json:"id,omitempty" yaml:"id,omitempty"`
+	// RemoveConsenters removes the consenters and legacy orderer addresses
+	// of the org for remove-orderer-org.
+	RemoveConsenters bool   `json:"remove_consenters,omitempty" yaml:"remove_consenters,omitempty"`
+	OldCert          string `json:"old_cert,omitempty" yaml:"old_cert,omitempty"`
+	NewCert          string `json:"new_cert,omitempty" yaml:"new_cert,omitempty"`
+}
+
+// OrgSpec is the serializable form of an Organization. The MSP of the org
+// uses SHA2 with SHA256 identity identifiers. If NodeOUs is set, node OU
+// classification is enabled with the client, peer, admin and orderer OUs
+// certified by the first root cert. Instead of PEM encoded certs, MSPDir may
+// name an MSP directory the MSP is loaded from, see LoadMSPDir.
+type OrgSpec struct {
+	Name                 string                `json:"name" yaml:"name"`
+	MSPID                string                `json:"msp_id" yaml:"msp_id"`
+	MSPDir               string                `json:"msp_dir,omitempty" yaml:"msp_dir,omitempty"`
+	RootCerts            string                `json:"root_certs,omitempty" yaml:"root_certs,omitempty"`
+	IntermediateCerts    string                `json:"intermediate_certs,omitempty" yaml:"intermediate_certs,omitempty"`
+	Admins               string                `json:"admins,omitempty" yaml:"admins,omitempty"`
+	TLSRootCerts         string                `json:"tls_root_certs,omitempty" yaml:"tls_root_certs,omitempty"`
+	TLSIntermediateCerts string                `json:"tls_intermediate_certs,omitempty" yaml:"tls_intermediate_certs,omitempty"`
+	NodeOUs              bool                  `json:"node_ous,omitempty" yaml:"node_ous,omitempty"`
+	Policies             map[string]PolicySpec `json:"policies" yaml:"policies"`
+	// AnchorPeers and OrdererEndpoints are in host:port form.
+	AnchorPeers      []string `json:"anchor_peers,omitempty" yaml:"anchor_peers,omitempty"`
+	OrdererEndpoints []string `json:"orderer_endpoints,omitempty" yaml:"orderer_endpoints,omitempty"`
+}
+
+// PolicySpec is the serializable form of a Policy. ModPolicy defaults to
+// Admins.
+type PolicySpec struct {
+	Type      string `json:"type" yaml:"type"`
+	Rule      string `json:"rule" yaml:"rule"`
+	ModPolicy string `json:"mod_policy,omitempty" yaml:"mod_policy,omitempty"`
+}
+
+// ConsenterSpec is the serializable form of an etcdraft or SmartBFT
+// consenter. ID, MSPID and Identity are only used by SmartBFT consenters.
+// Instead of a PEM encoded cert, the fields ending in File may name a file
+// the PEM encoded cert is read from.
+type ConsenterSpec struct {
+	ID                uint64 `json:"id,omitempty" yaml:"id,omitempty"`
+	Host              string `json:"host" yaml:"host"`
+	Port              int    `json:"port" yaml:"port"`
+	MSPID             string `json:"msp_id,omitempty" yaml:"msp_id,omitempty"`
+	Identity          string `json:"identity,omitempty" yaml:"identity,omitempty"`
+	IdentityFile      string `json:"identity_file,omitempty" yaml:"identity_file,omitempty"`
+	ClientTLSCert     string `json:"client_tls_cert,omitempty" yaml:"client_tls_cert,omitempty"`
+	ClientTLSCertFile string `json:"client_tls_cert_file,omitempty" yaml:"client_tls_cert_file,omitempty"`
+	ServerTLSCert     string `json:"server_tls_cert,omitempty" yaml:"server_tls_cert,omitempty"`
+	ServerTLSCertFile string `json:"server_tls_cert_file,omitempty" yaml:"server_tls_cert_file,omitempty"`
+}
+
+// ParseChangeDocument parses a change document in YAML or JSON form. Unknown
+// fields are rejected so that misspelled parameters are not silently ignored.
+func ParseChangeDocument(data []byte) (ChangeDocument, error) {
+	var doc ChangeDocument
+	err := yaml.UnmarshalStrict(data, &doc)
+	if err != nil {
+		return ChangeDocument{}, fmt.Errorf("parsing change document: %v", err)
+	}
+
+	return doc, nil
+}
+
+// ToChanges converts the specs of the document into changes.
+func (d ChangeDocument) ToChanges() ([]Change, error) {
+	changes := make([]Change, 0, len(d.Changes))
+	for i, spec := range d.Changes {
+		change, err := spec.Change()
+		if err != nil {
+			return nil, fmt.Errorf("change %d (%s): %v", i, spec.Op, err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// ApplyDocument parses a change document in YAML or JSON form and applies its
+// changes with Apply.
+func (c *ConfigTx) ApplyDocument(data []byte) error {
+	doc, err := ParseChangeDocument(data)
+	if err != nil {
+		return err
+	}
+
+	changes, err := doc.ToChanges()
+	if err != nil {
+		return err
+	}
+
+	return c.Apply(changes)
+}
+
+// Change converts the spec into the change performing its operation.
+func (s ChangeSpec) Change() (Change, error) {
+	switch s.Op {
+	case OpAddApplicationOrg, OpAddOrdererOrg:
+		if s.Org == nil {
+			return nil, errors.New("org is required")
+		}
+		org, err := s.Org.Organization()
+		if err != nil {
+			return nil, err
+		}
+		if s.Op == OpAddApplicationOrg {
+			return AddApplicationOrg{Org: org}, nil
+		}
+		return AddOrdererOrg{Org: org}, nil
+	case OpRemoveApplicationOrg:
+		return RemoveApplicationOrg{Name: s.Name}, nil
+	case OpRemoveOrdererOrg:
+		return RemoveOrdererOrg{Name: s.Name, RemoveConsenters: s.RemoveConsenters}, nil
+	case OpSetPolicy:
+		if s.Policy == nil {
+			return nil, errors.New("policy is required")
+		}
+		return SetPolicy{Group: s.Group, Name: s.Name, Policy: s.Policy.Policy()}, nil
+	case OpSetBatchTimeout:
+		timeout, err := orderer.ParseBatchTimeout(s.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout '%s': %v", s.Timeout, err)
+		}
+		return SetBatchTimeout{Timeout: timeout}, nil
+	case OpSetBatchSize:
+		absoluteMaxBytes, err := orderer.ParseByteSize(s.AbsoluteMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("absolute max bytes: %v", err)
+		}
+		preferredMaxBytes, err := orderer.ParseByteSize(s.PreferredMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("preferred max bytes: %v", err)
+		}
+		return SetBatchSize{BatchSize: orderer.BatchSize{
+			MaxMessageCount:   s.MaxMessageCount,
+			AbsoluteMaxBytes:  absoluteMaxBytes,
+			PreferredMaxBytes: preferredMaxBytes,
+		}}, nil
+	case OpAddConsenter:
+		if s.Consenter == nil {
+			return nil, errors.New("consenter is required")
+		}
+		consenter, err := s.Consenter.EtcdRaftConsenter()
+		if err != nil {
+			return nil, err
+		}
+		return AddConsenter{Consenter: consenter}, nil
+	case OpAddSmartBFTConsenter:
+		if s.Consenter == nil {
+			return nil, errors.New("consenter is required")
+		}
+		consenter, err := s.Consenter.SmartBFTConsenter()
+		if err != nil {
+			return nil, err
+		}
+		return AddSmartBFTConsenter{Consenter: consenter}, nil
+	case OpRemoveSmartBFTConsenter:
+		return RemoveSmartBFTConsenter{ID: s.ID}, nil
+	case OpRotateCA:
+		oldCert, err := parseOptionalCertificate("old cert", s.OldCert)
+		if err != nil {
+			return nil, err
+		}
+		newCert, err := parseOptionalCertificate("new cert", s.NewCert)
+		if err != nil {
+			return nil, err
+		}
+		return RotateRootCA{Group: s.Group, OldCert: oldCert, NewCert: newCert}, nil
+	case "":
+		return nil, errors.New("op is required")
+	default:
+		return nil, fmt.Errorf("unknown op '%s'", s.Op)
+	}
+}
+
+// Organization converts the spec into an Organization.
+func (o OrgSpec) Organization() (Organization, error) {
+	msp, err := o.msp()
+	if err != nil {
+		return Organization{}, err
+	}
+
+	var policies map[string]Policy
+	if o.Policies != nil {
+		policies = map[string]Policy{}
+		for name, policy := range o.Policies {
+			policies[name] = policy.Policy()
+		}
+	}
+
+	var anchorPeers []Address
+	for _, anchorPeer := range o.AnchorPeers {
+		host, port, err := splitEndpoint(anchorPeer)
+		if err != nil {
+			return Organization{}, fmt.Errorf("org %s: invalid anchor peer '%s': %v", o.Name, anchorPeer, err)
+		}
+		anchorPeers = append(anchorPeers, Address{Host: host, Port: port})
+	}
+
+	return Organization{
+		Name:             o.Name,
+		Policies:         policies,
+		MSP:              msp,
+		AnchorPeers:      anchorPeers,
+		OrdererEndpoints: o.OrdererEndpoints,
+	}, nil
+}
+
+// msp returns the MSP of the spec, loaded from its MSP directory if set.
+func (o OrgSpec) msp() (MSP, error) {
+	if o.MSPDir != "" {
+		if o.RootCerts != "" || o.IntermediateCerts != "" || o.Admins != "" || o.TLSRootCerts != "" || o.TLSIntermediateCerts != "" || o.NodeOUs {
+			return MSP{}, fmt.Errorf("org %s: msp_dir cannot be combined with certs or node_ous", o.Name)
+		}
+
+		msp, err := LoadMSPDir(o.MSPDir, o.MSPID)
+		if err != nil {
+			return MSP{}, fmt.Errorf("org %s: loading msp dir: %v", o.Name, err)
+		}
+
+		return msp, nil
+	}
+
+	msp := MSP{
+		Name: o.MSPID,
+		CryptoConfig: membership.CryptoConfig{
+			SignatureHashFamily:            "SHA2",
+			IdentityIdentifierHashFunction: "SHA256",
+		},
+	}
+
+	bundles := []struct {
+		name  string
+		pem   string
+		certs *[]*x509.Certificate
+	}{
+		{name: "root certs", pem: o.RootCerts, certs: &msp.RootCerts},
+		{name: "intermediate certs", pem: o.IntermediateCerts, certs: &msp.IntermediateCerts},
+		{name: "admins", pem: o.Admins, certs: &msp.Admins},
+		{name: "tls root certs", pem: o.TLSRootCerts, certs: &msp.TLSRootCerts},
+		{name: "tls intermediate certs", pem: o.TLSIntermediateCerts, certs: &msp.TLSIntermediateCerts},
+	}
+	for _, bundle := range bundles {
+		if bundle.pem == "" {
+			continue
+		}
+		certs, err := pemutil.ParseCertificates([]byte(bundle.pem))
+		if err != nil {
+			return MSP{}, fmt.Errorf("org %s: parsing %s: %v", o.Name, bundle.name, err)
+		}
+		*bundle.certs = certs
+	}
+
+	if o.NodeOUs && len(msp.RootCerts) > 0 {
+		ca := msp.RootCerts[0]
+		msp.NodeOUs = membership.NodeOUs{
+			Enable:              true,
+			ClientOUIdentifier:  membership.OUIdentifier{Certificate: ca, OrganizationalUnitIdentifier: "client"},
+			PeerOUIdentifier:    membership.OUIdentifier{Certificate: ca, OrganizationalUnitIdentifier: "peer"},
+			AdminOUIdentifier:   membership.OUIdentifier{Certificate: ca, OrganizationalUnitIdentifier: "admin"},
+			OrdererOUIdentifier: membership.OUIdentifier{Certificate: ca, OrganizationalUnitIdentifier: "orderer"},
+		}
+	}
+
+	return msp, nil
+}
+
+// Policy converts the spec into a Policy.
+func (p PolicySpec) Policy() Policy {
+	modPolicy := p.ModPolicy
+	if modPolicy == "" {
+		modPolicy = AdminsPolicyKey
+	}
+
+	return Policy{
+		Type:      p.Type,
+		Rule:      p.Rule,
+		ModPolicy: modPolicy,
+	}
+}
+
+// EtcdRaftConsenter converts the spec into an etcdraft consenter.
+func (c ConsenterSpec) EtcdRaftConsenter() (orderer.Consenter, error) {
+	clientTLSCert, err := readOptionalCertificate("client tls cert", c.ClientTLSCert, c.ClientTLSCertFile)
+	if err != nil {
+		return orderer.Consenter{}, err
+	}
+
+	serverTLSCert, err := readOptionalCertificate("server tls cert", c.ServerTLSCert, c.ServerTLSCertFile)
+	if err != nil {
+		return orderer.Consenter{}, err
+	}
+
+	return orderer.Consenter{
+		Address:       orderer.EtcdAddress{Host: c.Host, Port: c.Port},
+		ClientTLSCert: clientTLSCert,
+		ServerTLSCert: serverTLSCert,
+	}, nil
+}
+
+// SmartBFTConsenter converts the spec into a SmartBFT consenter.
+func (c ConsenterSpec) SmartBFTConsenter() (orderer.SmartBFTConsenter, error) {
+	consenter, err := c.EtcdRaftConsenter()
+	if err != nil {
+		return orderer.SmartBFTConsenter{}, err
+	}
+
+	identity, err := readOptionalCertificate("identity", c.Identity, c.IdentityFile)
+	if err != nil {
+		return orderer.SmartBFTConsenter{}, err
+	}
+
+	return orderer.SmartBFTConsenter{
+		ID:            c.ID,
+		Address:       consenter.Address,
+		MSPID:         c.MSPID,
+		Identity:      identity,
+		ClientTLSCert: consenter.ClientTLSCert,
+		ServerTLSCert: consenter.ServerTLSCert,
+	}, nil
+}
+
+// parseOptionalCertificate parses a PEM encoded certificate. An empty string
+// yields a nil certificate, which the validation of the change reports if the
+// certificate is required.
+func parseOptionalCertificate(name, pemCert string) (*x509.Certificate, error) {
+	if pemCert == "" {
+		return nil, nil
+	}
+
+	cert, err := pemutil.ParseCertificate([]byte(pemCert))
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %v", name, err)
+	}
+
+	return cert, nil
+}
+
+// readOptionalCertificate parses a PEM encoded certificate, which is read
+// from the file if one is named, see parseOptionalCertificate.
+func readOptionalCertificate(name, pemCert, file string) (*x509.Certificate, error) {
+	if file == "" {
+		return parseOptionalCertificate(name, pemCert)
+	}
+
+	if pemCert != "" {
+		return nil, fmt.Errorf("%s and %s file cannot both be set", name, name)
+	}
+
+	pemBytes, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %v", name, err)
+	}
+
+	return parseOptionalCertificate(name, string(pemBytes))
+}
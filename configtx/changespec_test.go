@@ -0,0 +1,250 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
+	. "github.com/onsi/gomega"
+)
+
+func TestApplyDocumentYAML(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+
+	err := c.ApplyDocument([]byte(`
+changes:
+- op: set-batch-timeout
+  timeout: 3s
+- op: set-batch-size
+  max_message_count: 100
+  absolute_max_bytes: 10 MB
+  preferred_max_bytes: 512 KiB
+- op: set-policy
+  group: Application/Org1
+  name: Endorsement
+  policy:
+    type: Signature
+    rule: OR('MSPID.peer')
+- op: remove-application-org
+  name: Org2
+`))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.BatchTimeout).To(Equal(3 * time.Second))
+	gt.Expect(ordererConfig.BatchSize).To(Equal(orderer.BatchSize{
+		MaxMessageCount:   100,
+		AbsoluteMaxBytes:  10 * 1024 * 1024,
+		PreferredMaxBytes: 512 * 1024,
+	}))
+	policies, err := c.Application().Organization("Org1").Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies[EndorsementPolicyKey].Type).To(Equal(SignaturePolicyType))
+	gt.Expect(policies[EndorsementPolicyKey].ModPolicy).To(Equal(AdminsPolicyKey))
+	gt.Expect(c.Application().Organization("Org2")).To(BeNil())
+}
+
+func TestApplyDocumentJSON(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+
+	oldCA, _ := generateCACertAndPrivateKey(t, "org3.example.com")
+	newCA, _ := generateCACertAndPrivateKey(t, "org3.example.com")
+	tlsCA, tlsCAPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	tlsCert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", tlsCA, tlsCAPrivKey)
+
+	doc := ChangeDocument{
+		Changes: []ChangeSpec{
+			{
+				Op: OpAddApplicationOrg,
+				Org: &OrgSpec{
+					Name:      "Org3",
+					MSPID:     "Org3MSP",
+					RootCerts: string(pemutil.EncodeCertificate(oldCA)),
+					NodeOUs:   true,
+					Policies: map[string]PolicySpec{
+						AdminsPolicyKey:  {Type: SignaturePolicyType, Rule: "OR('Org3MSP.admin')"},
+						ReadersPolicyKey: {Type: SignaturePolicyType, Rule: "OR('Org3MSP.member')"},
+						WritersPolicyKey: {Type: SignaturePolicyType, Rule: "OR('Org3MSP.member')"},
+					},
+					AnchorPeers: []string{"peer0.org3.example.com:7051"},
+				},
+			},
+			{
+				Op:      OpRotateCA,
+				Group:   "Application/Org3",
+				OldCert: string(pemutil.EncodeCertificate(oldCA)),
+				NewCert: string(pemutil.EncodeCertificate(newCA)),
+			},
+			{
+				Op: OpAddConsenter,
+				Consenter: &ConsenterSpec{
+					Host:          "node-4.example.com",
+					Port:          7050,
+					ClientTLSCert: string(pemutil.EncodeCertificate(tlsCert)),
+					ServerTLSCert: string(pemutil.EncodeCertificate(tlsCert)),
+				},
+			},
+		},
+	}
+	data, err := json.Marshal(doc)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.ApplyDocument(data)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	org, err := c.Application().Organization("Org3").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.MSP.Name).To(Equal("Org3MSP"))
+	gt.Expect(org.MSP.RootCerts).To(HaveLen(1))
+	gt.Expect(org.MSP.RootCerts[0].Equal(newCA)).To(BeTrue())
+	gt.Expect(org.MSP.NodeOUs.Enable).To(BeTrue())
+	gt.Expect(org.MSP.NodeOUs.PeerOUIdentifier.OrganizationalUnitIdentifier).To(Equal("peer"))
+	gt.Expect(org.AnchorPeers).To(Equal([]Address{{Host: "peer0.org3.example.com", Port: 7051}}))
+	gt.Expect(org.Policies[AdminsPolicyKey].ModPolicy).To(Equal(AdminsPolicyKey))
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.EtcdRaft.Consenters).To(HaveLen(4))
+}
+
+func TestApplyDocumentFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		doc         string
+		expectedErr string
+	}{
+		{
+			testName:    "when the document is malformed",
+			doc:         "changes: [",
+			expectedErr: "parsing change document: yaml: line 1: did not find expected node content",
+		},
+		{
+			testName: "when a field is unknown",
+			doc: `
+changes:
+- op: set-batch-timeout
+  timout: 3s
+`,
+			expectedErr: "parsing change document: yaml: unmarshal errors:\n  line 4: field timout not found in type configtx.ChangeSpec",
+		},
+		{
+			testName: "when the op is missing",
+			doc: `
+changes:
+- name: Org2
+`,
+			expectedErr: "change 0 (): op is required",
+		},
+		{
+			testName: "when the op is unknown",
+			doc: `
+changes:
+- op: add-peer
+`,
+			expectedErr: "change 0 (add-peer): unknown op 'add-peer'",
+		},
+		{
+			testName: "when the timeout is invalid",
+			doc: `
+changes:
+- op: set-batch-timeout
+  timeout: soon
+`,
+			expectedErr: `change 0 (set-batch-timeout): invalid timeout 'soon': time: invalid duration "soon"`,
+		},
+		{
+			testName: "when the timeout is not positive",
+			doc: `
+changes:
+- op: set-batch-timeout
+  timeout: -2s
+`,
+			expectedErr: "change 0 (set-batch-timeout): invalid timeout '-2s': batch timeout must be positive, got -2s",
+		},
+		{
+			testName: "when a batch size is invalid",
+			doc: `
+changes:
+- op: set-batch-size
+  max_message_count: 100
+  absolute_max_bytes: 10 mb
+  preferred_max_bytes: 512 KiB
+`,
+			expectedErr: "change 0 (set-batch-size): absolute max bytes: invalid size '10 mb', unknown unit 'mb', expected B, KB, KiB, MB, MiB, GB or GiB",
+		},
+		{
+			testName: "when the batch size is inconsistent",
+			doc: `
+changes:
+- op: set-batch-size
+  max_message_count: 100
+  absolute_max_bytes: 1 MB
+  preferred_max_bytes: 2 MB
+`,
+			expectedErr: "invalid change 0 (SetBatchSize): batch size preferred max bytes (2097152) must not exceed absolute max bytes (1048576)",
+		},
+		{
+			testName: "when a certificate is invalid",
+			doc: `
+changes:
+- op: rotate-ca
+  group: Orderer/OrdererOrg
+  old_cert: garbage
+`,
+			expectedErr: "change 0 (rotate-ca): parsing old cert: no PEM data found in cert[67 61 72 62 61 67 65]",
+		},
+		{
+			testName: "when a change is invalid",
+			doc: `
+changes:
+- op: rotate-ca
+  group: Orderer
+`,
+			expectedErr: "invalid change 0 (RotateRootCA): group 'Orderer' is not an org",
+		},
+		{
+			testName: "when a change fails",
+			doc: `
+changes:
+- op: set-batch-timeout
+  timeout: 3s
+- op: remove-orderer-org
+  name: UnknownOrg
+`,
+			expectedErr: "applying change 1 (RemoveOrdererOrg): orderer org UnknownOrg does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := applyConfigTx(t)
+			expected := proto.Clone(c.updated.ChannelGroup)
+
+			err := c.ApplyDocument([]byte(tt.doc))
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+			gt.Expect(proto.Equal(c.updated.ChannelGroup, expected)).To(BeTrue())
+		})
+	}
+}
@@ -9,6 +9,7 @@ package configtx
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 )
@@ -17,14 +18,32 @@ import (
 // This type implements retrieval of the various channel config values.
 type ChannelGroup struct {
 	channelGroup *cb.ConfigGroup
+	// skipSubPolicyChecks disables the checks of the ImplicitMeta
+	// sub-policies, see WithoutSubPolicyChecks.
+	skipSubPolicyChecks bool
+	observed            observed
 }
 
 // Channel returns the channel group from the updated config.
 func (c *ConfigTx) Channel() *ChannelGroup {
-	return &ChannelGroup{channelGroup: c.updated.ChannelGroup}
+	return &ChannelGroup{channelGroup: c.updated.ChannelGroup, observed: c.observed()}
 }
 
-// Configuration returns a channel configuration value from a config transaction.
+// WithoutSubPolicyChecks returns a copy of the channel group whose SetPolicy
+// and SetPolicies methods accept ImplicitMeta policies whose sub-policy is
+// missing in the application, orderer or consortiums group. By default such
+// policies are rejected, see SetPolicy.
+func (c *ChannelGroup) WithoutSubPolicyChecks() *ChannelGroup {
+	unchecked := *c
+	unchecked.skipSubPolicyChecks = true
+
+	return &unchecked
+}
+
+// Configuration returns a channel configuration value from a config
+// transaction. The returned channel is complete, so that building a channel
+// from it, such as with NewApplicationChannelGenesisBlock, reproduces the
+// channel group. Organizations are returned in the order of their names.
 func (c *ChannelGroup) Configuration() (Channel, error) {
 	var (
 		config Channel
@@ -76,6 +95,13 @@ func (c *ChannelGroup) Configuration() (Channel, error) {
 		return Channel{}, err
 	}
 
+	config.CustomValues, err = getCustomValues(c.channelGroup)
+	if err != nil {
+		return Channel{}, err
+	}
+
+	config.ModPolicy = c.channelGroup.GetModPolicy()
+
 	return config, nil
 }
 
@@ -85,7 +111,9 @@ func (c *ChannelGroup) Policies() (map[string]Policy, error) {
 }
 
 // SetModPolicy sets the specified modification policy for the channel group.
-func (c *ChannelGroup) SetModPolicy(modPolicy string) error {
+func (c *ChannelGroup) SetModPolicy(modPolicy string) (err error) {
+	defer c.observe("ChannelGroup.SetModPolicy", time.Now(), &err)
+
 	if modPolicy == "" {
 		return errors.New("non empty mod policy is required")
 	}
@@ -97,18 +125,46 @@ func (c *ChannelGroup) SetModPolicy(modPolicy string) error {
 
 // SetPolicy sets the specified policy in the channel group's config policy map.
 // If the policy already exists in current configuration, its value will be overwritten.
-func (c *ChannelGroup) SetPolicy(policyName string, policy Policy) error {
+// The sub-policy of an ImplicitMeta policy must be defined in the child groups
+// of the channel: in one of them for an ANY rule and in every child group
+// defining policies for an ALL or MAJORITY rule.
+func (c *ChannelGroup) SetPolicy(policyName string, policy Policy) (err error) {
+	defer c.observe("ChannelGroup.SetPolicy", time.Now(), &err)
+
+	err = c.checkSubPolicies(map[string]Policy{policyName: policy})
+	if err != nil {
+		return err
+	}
+
 	return setPolicy(c.channelGroup, policyName, policy)
 }
 
 // SetPolicies sets the specified policies in the channel group's config policy map.
 // If the policies already exist in current configuration, the values will be replaced with new policies.
-func (c *ChannelGroup) SetPolicies(policies map[string]Policy) error {
+// ImplicitMeta policies are checked as by SetPolicy.
+func (c *ChannelGroup) SetPolicies(policies map[string]Policy) (err error) {
+	defer c.observe("ChannelGroup.SetPolicies", time.Now(), &err)
+
+	err = c.checkSubPolicies(policies)
+	if err != nil {
+		return err
+	}
+
 	return setPolicies(c.channelGroup, policies)
 }
 
+func (c *ChannelGroup) checkSubPolicies(policies map[string]Policy) error {
+	if c.skipSubPolicyChecks {
+		return nil
+	}
+
+	return checkSubPolicies(c.channelGroup, policies)
+}
+
 // RemovePolicy removes an existing channel level policy.
-func (c *ChannelGroup) RemovePolicy(policyName string) error {
+func (c *ChannelGroup) RemovePolicy(policyName string) (err error) {
+	defer c.observe("ChannelGroup.RemovePolicy", time.Now(), &err)
+
 	policies, err := c.Policies()
 	if err != nil {
 		return err
@@ -132,7 +188,9 @@ func (c *ChannelGroup) Capabilities() ([]string, error) {
 // AddCapability adds capability to the provided channel config.
 // If the provided capability already exists in current configuration, this action
 // will be a no-op.
-func (c *ChannelGroup) AddCapability(capability string) error {
+func (c *ChannelGroup) AddCapability(capability string) (err error) {
+	defer c.observe("ChannelGroup.AddCapability", time.Now(), &err)
+
 	capabilities, err := c.Capabilities()
 	if err != nil {
 		return err
@@ -147,12 +205,26 @@ func (c *ChannelGroup) AddCapability(capability string) error {
 }
 
 // RemoveCapability removes capability to the provided channel config.
-func (c *ChannelGroup) RemoveCapability(capability string) error {
+func (c *ChannelGroup) RemoveCapability(capability string) (err error) {
+	defer c.observe("ChannelGroup.RemoveCapability", time.Now(), &err)
+
 	capabilities, err := c.Capabilities()
 	if err != nil {
 		return err
 	}
 
+	if ordererGroup, ok := c.channelGroup.Groups[OrdererGroupKey]; ok {
+		consensusType, err := ordererConsensusType(ordererGroup)
+		if err != nil {
+			return err
+		}
+
+		err = validateChannelConsensusCapabilities(consensusType, withoutString(capabilities, capability))
+		if err != nil {
+			return fmt.Errorf("removing capability %s: %v", capability, err)
+		}
+	}
+
 	err = removeCapability(c.channelGroup, capabilities, AdminsPolicyKey, capability)
 	if err != nil {
 		return err
@@ -166,5 +238,7 @@ func (c *ChannelGroup) RemoveCapability(capability string) error {
 // In fabric 1.4, top level orderer addresses were migrated to the org level orderer endpoints
 // While top-level orderer addresses are still supported, the organization value is preferred.
 func (c *ChannelGroup) RemoveLegacyOrdererAddresses() {
+	defer c.observe("ChannelGroup.RemoveLegacyOrdererAddresses", time.Now(), nil)
+
 	delete(c.channelGroup.Values, OrdererAddressesKey)
 }
@@ -11,11 +11,75 @@ import (
 	"testing"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
 	"github.com/hyperledger/fabric-config/protolator"
 	"github.com/hyperledger/fabric-config/protolator/protoext/commonext"
 	. "github.com/onsi/gomega"
 )
 
+func TestChannelConfigurationRoundTrip(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	err := RegisterCustomValue("ChannelVendorSettings", func() proto.Message { return &any.Any{} })
+	gt.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() { UnregisterCustomValue("ChannelVendorSettings") })
+	vendorSettings, err := ptypes.MarshalAny(&cb.Capabilities{
+		Capabilities: map[string]*cb.Capability{"VendorFeature": {}},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	profile, _, _ := baseApplicationChannelProfile(t)
+	profile.ModPolicy = "/Channel/Orderer/Admins"
+	profile.Application.ModPolicy = WritersPolicyKey
+	profile.CustomValues = map[string]proto.Message{"ChannelVendorSettings": vendorSettings}
+	// organizations are returned in the order of their names
+	for _, name := range []string{"OrdererOrg3", "OrdererOrg2"} {
+		org := profile.Orderer.Organizations[0]
+		org.Name = name
+		profile.Orderer.Organizations = append(profile.Orderer.Organizations, org)
+	}
+	for _, name := range []string{"Org3", "Org2"} {
+		org := profile.Application.Organizations[0]
+		org.Name = name
+		profile.Application.Organizations = append(profile.Application.Organizations, org)
+	}
+
+	channelGroup, err := newApplicationChannelGroup(profile)
+	gt.Expect(err).NotTo(HaveOccurred())
+	// values which are not registered are carried through as is
+	unregistered := &cb.ConfigValue{Value: []byte("vendor limits"), ModPolicy: "/Channel/Orderer/Admins"}
+	channelGroup.Values["ChannelVendorLimits"] = unregistered
+	channelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Values["OrgVendorLimits"] = unregistered
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	channel, err := c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channel.ModPolicy).To(Equal("/Channel/Orderer/Admins"))
+	gt.Expect(channel.Application.ModPolicy).To(Equal(WritersPolicyKey))
+	gt.Expect(channel.Application.ACLs).To(Equal(profile.Application.ACLs))
+	gt.Expect(organizationNames(channel.Orderer.Organizations)).To(Equal([]string{"OrdererOrg", "OrdererOrg2", "OrdererOrg3"}))
+	gt.Expect(organizationNames(channel.Application.Organizations)).To(Equal([]string{"Org1", "Org2", "Org3"}))
+	gt.Expect(channel.CustomValues).To(HaveLen(2))
+	gt.Expect(proto.Equal(channel.CustomValues["ChannelVendorSettings"], vendorSettings)).To(BeTrue())
+	gt.Expect(proto.Equal(channel.CustomValues["ChannelVendorLimits"], unregistered)).To(BeTrue())
+
+	rebuilt, err := newApplicationChannelGroup(channel)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(rebuilt, channelGroup)).To(BeTrue())
+}
+
+func organizationNames(orgs []Organization) []string {
+	var names []string
+	for _, org := range orgs {
+		names = append(names, org.Name)
+	}
+
+	return names
+}
+
 func TestChannelCapabilities(t *testing.T) {
 	t.Parallel()
 
@@ -547,7 +611,7 @@ func TestConfigurationFailures(t *testing.T) {
 					},
 				},
 			},
-			expectedErr: "failed to retrieve organization Org1 from consortium Consortium1: ",
+			expectedErr: "failed to retrieve organization Org1 from consortium Consortium1: config does not contain value for MSP",
 		},
 		{
 			testName: "when retrieving existing policies",
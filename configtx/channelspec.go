@@ -0,0 +1,176 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	"gopkg.in/yaml.v2"
+)
+
+// ChannelSpec is the serializable form of an application channel assembled
+// by a ChannelBuilder, meant to be kept in version control. Along with
+// ParseChannelTemplate it lets one channel definition stamp out the channels
+// of several environments.
+//
+// An example template in YAML:
+//
+//	channel_id: ${channel}
+//	fabric_version: "3.0"
+//	orderer_type: smartbft
+//	consenters:
+//	- id: 1
+//	  host: orderer0.${domain}
+//	  port: 7050
+//	  msp_id: OrdererMSP
+//	  identity_file: ${crypto}/orderer0/msp/signcerts/cert.pem
+//	  client_tls_cert_file: ${crypto}/orderer0/tls/server.crt
+//	  server_tls_cert_file: ${crypto}/orderer0/tls/server.crt
+//	orderer_orgs:
+//	- name: OrdererOrg
+//	  msp_id: OrdererMSP
+//	  msp_dir: ${crypto}/ordererOrganizations/${domain}/msp
+//	  orderer_endpoints:
+//	  - orderer0.${domain}:7050
+//	application_orgs:
+//	- name: Org1
+//	  msp_id: Org1MSP
+//	  msp_dir: ${crypto}/peerOrganizations/org1.${domain}/msp
+//	  anchor_peers:
+//	  - peer0.org1.${domain}:7051
+type ChannelSpec struct {
+	ChannelID string `json:"channel_id" yaml:"channel_id"`
+	// FabricVersion selects the capabilities of the channel, see
+	// CapabilitiesFor.
+	FabricVersion FabricVersion `json:"fabric_version,omitempty" yaml:"fabric_version,omitempty"`
+	// OrdererType is etcdraft, the default, or smartbft. The consenters use
+	// the options of the sample configtx.yaml.
+	OrdererType string          `json:"orderer_type,omitempty" yaml:"orderer_type,omitempty"`
+	Consenters  []ConsenterSpec `json:"consenters" yaml:"consenters"`
+	// BatchTimeout is the batch timeout, such as 2s.
+	BatchTimeout string `json:"batch_timeout,omitempty" yaml:"batch_timeout,omitempty"`
+	// MaxMessageCount, AbsoluteMaxBytes and PreferredMaxBytes override the
+	// default batch size. The sizes are parsed by orderer.ParseByteSize, such
+	// as 10 MB.
+	MaxMessageCount   uint32 `json:"max_message_count,omitempty" yaml:"max_message_count,omitempty"`
+	AbsoluteMaxBytes  string `json:"absolute_max_bytes,omitempty" yaml:"absolute_max_bytes,omitempty"`
+	PreferredMaxBytes string `json:"preferred_max_bytes,omitempty" yaml:"preferred_max_bytes,omitempty"`
+	// Consortium is only required to build channel creation transactions.
+	Consortium      string    `json:"consortium,omitempty" yaml:"consortium,omitempty"`
+	OrdererOrgs     []OrgSpec `json:"orderer_orgs" yaml:"orderer_orgs"`
+	ApplicationOrgs []OrgSpec `json:"application_orgs" yaml:"application_orgs"`
+}
+
+// ParseChannelSpec parses a channel spec in YAML or JSON form. Unknown fields
+// are rejected so that misspelled parameters are not silently ignored.
+func ParseChannelSpec(data []byte) (ChannelSpec, error) {
+	var spec ChannelSpec
+	err := yaml.UnmarshalStrict(data, &spec)
+	if err != nil {
+		return ChannelSpec{}, fmt.Errorf("parsing channel spec: %v", err)
+	}
+
+	return spec, nil
+}
+
+// Builder returns a ChannelBuilder configured by the spec. Orgs without
+// policies are given the defaults of the ChannelBuilder.
+func (s ChannelSpec) Builder() (*ChannelBuilder, error) {
+	b := NewChannelBuilder(s.ChannelID)
+	if s.FabricVersion != "" {
+		b.WithFabricVersion(s.FabricVersion)
+	}
+	if s.Consortium != "" {
+		b.WithConsortium(s.Consortium)
+	}
+
+	switch s.OrdererType {
+	case "", orderer.ConsensusTypeEtcdRaft:
+		consenters := make([]orderer.Consenter, len(s.Consenters))
+		for i, spec := range s.Consenters {
+			consenter, err := spec.EtcdRaftConsenter()
+			if err != nil {
+				return nil, fmt.Errorf("consenter %d: %v", i, err)
+			}
+			consenters[i] = consenter
+		}
+		b.WithEtcdRaft(orderer.EtcdRaft{Consenters: consenters, Options: DefaultEtcdRaftOptions()})
+	case orderer.ConsensusTypeSmartBFT:
+		consenters := make([]orderer.SmartBFTConsenter, len(s.Consenters))
+		for i, spec := range s.Consenters {
+			consenter, err := spec.SmartBFTConsenter()
+			if err != nil {
+				return nil, fmt.Errorf("consenter %d: %v", i, err)
+			}
+			consenters[i] = consenter
+		}
+		b.WithSmartBFT(orderer.SmartBFT{Consenters: consenters, Options: DefaultSmartBFTOptions()})
+	default:
+		return nil, fmt.Errorf("unsupported orderer type '%s'", s.OrdererType)
+	}
+
+	if s.BatchTimeout != "" {
+		timeout, err := orderer.ParseBatchTimeout(s.BatchTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid batch timeout '%s': %v", s.BatchTimeout, err)
+		}
+		b.WithBatchTimeout(timeout)
+	}
+
+	batchSize := orderer.BatchSize{
+		MaxMessageCount:   defaultMaxMessageCount,
+		AbsoluteMaxBytes:  defaultAbsoluteMaxBytes,
+		PreferredMaxBytes: defaultPreferredMaxBytes,
+	}
+	if s.MaxMessageCount != 0 {
+		batchSize.MaxMessageCount = s.MaxMessageCount
+	}
+	if s.AbsoluteMaxBytes != "" {
+		absoluteMaxBytes, err := orderer.ParseByteSize(s.AbsoluteMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("absolute max bytes: %v", err)
+		}
+		batchSize.AbsoluteMaxBytes = absoluteMaxBytes
+	}
+	if s.PreferredMaxBytes != "" {
+		preferredMaxBytes, err := orderer.ParseByteSize(s.PreferredMaxBytes)
+		if err != nil {
+			return nil, fmt.Errorf("preferred max bytes: %v", err)
+		}
+		batchSize.PreferredMaxBytes = preferredMaxBytes
+	}
+	b.WithBatchSize(batchSize)
+
+	for _, spec := range s.OrdererOrgs {
+		org, err := spec.Organization()
+		if err != nil {
+			return nil, err
+		}
+		b.WithOrdererOrg(org)
+	}
+
+	for _, spec := range s.ApplicationOrgs {
+		org, err := spec.Organization()
+		if err != nil {
+			return nil, err
+		}
+		b.WithApplicationOrg(org)
+	}
+
+	return b, nil
+}
+
+// Channel returns the channel of the spec, see ChannelBuilder.Build.
+func (s ChannelSpec) Channel() (Channel, error) {
+	b, err := s.Builder()
+	if err != nil {
+		return Channel{}, err
+	}
+
+	return b.Build()
+}
@@ -0,0 +1,210 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
+	. "github.com/onsi/gomega"
+)
+
+const channelTemplate = `
+channel_id: ${channel}
+fabric_version: "2.5"
+consenters:
+- host: orderer0.${domain}
+  port: 7050
+  client_tls_cert_file: ${orderer_msp}/tls/server.crt
+  server_tls_cert_file: ${orderer_msp}/tls/server.crt
+batch_timeout: 1s
+max_message_count: 100
+orderer_orgs:
+- name: OrdererOrg
+  msp_id: OrdererMSP
+  msp_dir: ${orderer_msp}
+  orderer_endpoints:
+  - orderer0.${domain}:7050
+application_orgs:
+- name: Org1
+  msp_id: Org1MSP
+  msp_dir: ${org1_msp}
+  anchor_peers:
+  - peer0.org1.${domain}:7051
+`
+
+// writeEnvironmentMSPDirs writes the MSP directories of the orderer org,
+// along with the TLS cert of its consenter, and of the application org of
+// the channel template, which the caller must remove.
+func writeEnvironmentMSPDirs(t *testing.T, domain string) (ordererMSPDir, org1MSPDir string, tlsCert *x509.Certificate) {
+	ordererCA, _ := generateCACertAndPrivateKey(t, domain)
+	tlsCA, tlsCAPrivKey := generateCACertAndPrivateKey(t, "tls."+domain)
+	tlsCert, _ = generateCertAndPrivateKeyFromCACert(t, "orderer0."+domain, tlsCA, tlsCAPrivKey)
+	ordererMSPDir = writeMSPDir(t, map[string][]byte{
+		"cacerts/ca.pem":       pemutil.EncodeCertificate(ordererCA),
+		"tlscacerts/tlsca.pem": pemutil.EncodeCertificate(tlsCA),
+		"tls/server.crt":       pemutil.EncodeCertificate(tlsCert),
+	})
+
+	org1CA, _ := generateCACertAndPrivateKey(t, "org1."+domain)
+	org1MSPDir = writeMSPDir(t, map[string][]byte{
+		"cacerts/ca.pem": pemutil.EncodeCertificate(org1CA),
+		"config.yaml":    []byte(nodeOUsConfigYAML),
+	})
+
+	return ordererMSPDir, org1MSPDir, tlsCert
+}
+
+func TestParseChannelTemplate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	for _, env := range []string{"dev", "prod"} {
+		domain := env + ".example.com"
+		ordererMSPDir, org1MSPDir, tlsCert := writeEnvironmentMSPDirs(t, domain)
+		defer os.RemoveAll(ordererMSPDir)
+		defer os.RemoveAll(org1MSPDir)
+
+		spec, err := ParseChannelTemplate([]byte(channelTemplate), TemplateVars{
+			"channel":     env + "-channel",
+			"domain":      domain,
+			"orderer_msp": ordererMSPDir,
+			"org1_msp":    org1MSPDir,
+		})
+		gt.Expect(err).NotTo(HaveOccurred(), env)
+		gt.Expect(spec.ChannelID).To(Equal(env+"-channel"), env)
+
+		builder, err := spec.Builder()
+		gt.Expect(err).NotTo(HaveOccurred(), env)
+		gt.Expect(builder.ChannelID()).To(Equal(env+"-channel"), env)
+
+		channel, err := spec.Channel()
+		gt.Expect(err).NotTo(HaveOccurred(), env)
+		gt.Expect(channel.Orderer.OrdererType).To(Equal(orderer.ConsensusTypeEtcdRaft), env)
+		gt.Expect(channel.Orderer.EtcdRaft.Options).To(Equal(DefaultEtcdRaftOptions()), env)
+		gt.Expect(channel.Orderer.EtcdRaft.Consenters).To(Equal([]orderer.Consenter{
+			{
+				Address:       orderer.EtcdAddress{Host: "orderer0." + domain, Port: 7050},
+				ClientTLSCert: tlsCert,
+				ServerTLSCert: tlsCert,
+			},
+		}), env)
+		gt.Expect(channel.Orderer.BatchTimeout).To(Equal(time.Second), env)
+		gt.Expect(channel.Orderer.BatchSize).To(Equal(orderer.BatchSize{
+			MaxMessageCount:   100,
+			AbsoluteMaxBytes:  10 * 1024 * 1024,
+			PreferredMaxBytes: 2 * 1024 * 1024,
+		}), env)
+		gt.Expect(channel.Application.Capabilities).To(Equal([]string{"V2_5"}), env)
+
+		ordererMSP, err := LoadMSPDir(ordererMSPDir, "OrdererMSP")
+		gt.Expect(err).NotTo(HaveOccurred(), env)
+		gt.Expect(channel.Orderer.Organizations).To(HaveLen(1), env)
+		gt.Expect(channel.Orderer.Organizations[0].MSP).To(Equal(ordererMSP), env)
+		gt.Expect(channel.Orderer.Organizations[0].OrdererEndpoints).To(Equal([]string{"orderer0." + domain + ":7050"}), env)
+		gt.Expect(channel.Orderer.Organizations[0].Policies).To(Equal(defaultOrdererOrgPolicies("OrdererMSP")), env)
+
+		org1MSP, err := LoadMSPDir(org1MSPDir, "Org1MSP")
+		gt.Expect(err).NotTo(HaveOccurred(), env)
+		gt.Expect(channel.Application.Organizations).To(HaveLen(1), env)
+		gt.Expect(channel.Application.Organizations[0].MSP).To(Equal(org1MSP), env)
+		gt.Expect(channel.Application.Organizations[0].AnchorPeers).To(Equal([]Address{{Host: "peer0.org1." + domain, Port: 7051}}), env)
+
+		_, err = builder.GenesisBlock()
+		gt.Expect(err).NotTo(HaveOccurred(), env)
+	}
+}
+
+func TestChannelSpecFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	ordererMSPDir, org1MSPDir, _ := writeEnvironmentMSPDirs(t, "example.com")
+	defer os.RemoveAll(ordererMSPDir)
+	defer os.RemoveAll(org1MSPDir)
+	vars := TemplateVars{
+		"channel":     "testchannel",
+		"domain":      "example.com",
+		"orderer_msp": ordererMSPDir,
+		"org1_msp":    org1MSPDir,
+	}
+	validSpec, err := ParseChannelTemplate([]byte(channelTemplate), vars)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = ParseChannelTemplate([]byte(channelTemplate), TemplateVars{"channel": "testchannel"})
+	gt.Expect(err).To(MatchError("undefined template variables: domain, orderer_msp, org1_msp"))
+
+	_, err = ParseChannelSpec([]byte("channel_id: testchannel\nunknown: field\n"))
+	gt.Expect(err).To(MatchError(ContainSubstring("parsing channel spec: ")))
+
+	tests := []struct {
+		testName    string
+		modify      func(spec *ChannelSpec)
+		expectedErr string
+	}{
+		{
+			testName:    "when the orderer type is unsupported",
+			modify:      func(spec *ChannelSpec) { spec.OrdererType = "kafka" },
+			expectedErr: "unsupported orderer type 'kafka'",
+		},
+		{
+			testName:    "when the batch timeout is invalid",
+			modify:      func(spec *ChannelSpec) { spec.BatchTimeout = "soon" },
+			expectedErr: "invalid batch timeout 'soon': ",
+		},
+		{
+			testName:    "when the absolute max bytes are invalid",
+			modify:      func(spec *ChannelSpec) { spec.AbsoluteMaxBytes = "lots" },
+			expectedErr: "absolute max bytes: ",
+		},
+		{
+			testName: "when a consenter cert file does not exist",
+			modify: func(spec *ChannelSpec) {
+				spec.Consenters[0].ServerTLSCertFile = filepath.Join(ordererMSPDir, "missing.crt")
+			},
+			expectedErr: "consenter 0: reading server tls cert: ",
+		},
+		{
+			testName: "when a consenter cert is given inline and by file",
+			modify: func(spec *ChannelSpec) {
+				spec.Consenters[0].ClientTLSCert = "-----BEGIN CERTIFICATE-----"
+			},
+			expectedErr: "consenter 0: client tls cert and client tls cert file cannot both be set",
+		},
+		{
+			testName:    "when an MSP directory does not exist",
+			modify:      func(spec *ChannelSpec) { spec.ApplicationOrgs[0].MSPDir = filepath.Join(org1MSPDir, "missing") },
+			expectedErr: "org Org1: loading msp dir: ",
+		},
+		{
+			testName:    "when an MSP directory is combined with certs",
+			modify:      func(spec *ChannelSpec) { spec.OrdererOrgs[0].NodeOUs = true },
+			expectedErr: "org OrdererOrg: msp_dir cannot be combined with certs or node_ous",
+		},
+		{
+			testName:    "when the channel is invalid",
+			modify:      func(spec *ChannelSpec) { spec.Consenters = nil },
+			expectedErr: "invalid channel testchannel: ",
+		},
+	}
+
+	for _, tt := range tests {
+		spec := validSpec
+		spec.Consenters = append([]ConsenterSpec{}, validSpec.Consenters...)
+		spec.OrdererOrgs = append([]OrgSpec{}, validSpec.OrdererOrgs...)
+		spec.ApplicationOrgs = append([]OrgSpec{}, validSpec.ApplicationOrgs...)
+		tt.modify(&spec)
+
+		_, err := spec.Channel()
+		gt.Expect(err).To(MatchError(ContainSubstring(tt.expectedErr)), tt.testName)
+	}
+}
@@ -18,11 +18,11 @@ package configtx
 import (
 	"bytes"
 	"crypto/sha256"
+	"crypto/x509"
 	"encoding/hex"
 	"errors"
 	"fmt"
-	"strconv"
-	"strings"
+	"time"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
@@ -40,6 +40,123 @@ type Channel struct {
 	Capabilities []string
 	Policies     map[string]Policy
 	ModPolicy    string
+
+	// CustomValues contains the config values of the channel group which
+	// are not part of the standard Fabric config, keyed by value key. Values
+	// whose key was registered with RegisterCustomValue are decoded, other
+	// values are read as the raw *cb.ConfigValue, which is set as is.
+	CustomValues map[string]proto.Message
+}
+
+// Validate checks that the channel configuration is complete. A channel with an
+// orderer defines a genesis block, which requires the channel capabilities and
+// policies. Otherwise the channel defines a channel creation transaction,
+// which requires a consortium and an application.
+func (c Channel) Validate() error {
+	if c.Orderer.OrdererType == "" {
+		if c.Consortium == "" {
+			return errors.New("consortium is not defined in channel config")
+		}
+
+		if err := c.Application.Validate(); err != nil {
+			return fmt.Errorf("invalid application: %v", err)
+		}
+
+		return c.validateMSPIDs()
+	}
+
+	if len(c.Capabilities) == 0 {
+		return errors.New("capabilities is not defined in channel config")
+	}
+
+	if err := validatePolicies(c.Policies); err != nil {
+		return fmt.Errorf("invalid channel policies: %v", err)
+	}
+
+	if err := c.Orderer.Validate(); err != nil {
+		return fmt.Errorf("invalid orderer: %v", err)
+	}
+
+	if err := validateConsensusCapabilities(c.Orderer.OrdererType, c.Capabilities, c.Orderer.Capabilities); err != nil {
+		return err
+	}
+
+	if c.Application.Policies != nil || len(c.Application.Organizations) > 0 {
+		if err := c.Application.Validate(); err != nil {
+			return fmt.Errorf("invalid application: %v", err)
+		}
+	}
+
+	for _, consortium := range c.Consortiums {
+		if err := consortium.Validate(); err != nil {
+			return fmt.Errorf("invalid consortiums: %v", err)
+		}
+	}
+
+	return c.validateMSPIDs()
+}
+
+// validateMSPIDs checks that organizations of the application, orderer and
+// consortiums which declare the same MSP ID also declare the same trust roots.
+// Otherwise policies referencing the MSP ID are evaluated against whichever
+// definition the evaluating node happens to load.
+func (c Channel) validateMSPIDs() error {
+	type declaration struct {
+		location string
+		org      Organization
+	}
+
+	var declarations []declaration
+	for _, org := range c.Application.Organizations {
+		declarations = append(declarations, declaration{location: "application org " + org.Name, org: org})
+	}
+	for _, org := range c.Orderer.Organizations {
+		declarations = append(declarations, declaration{location: "orderer org " + org.Name, org: org})
+	}
+	for _, consortium := range c.Consortiums {
+		for _, org := range consortium.Organizations {
+			declarations = append(declarations, declaration{location: fmt.Sprintf("consortium %s org %s", consortium.Name, org.Name), org: org})
+		}
+	}
+
+	first := map[string]declaration{}
+	for _, d := range declarations {
+		mspID := d.org.MSP.Name
+		existing, ok := first[mspID]
+		if !ok {
+			first[mspID] = d
+			continue
+		}
+
+		if !sameCerts(existing.org.MSP.RootCerts, d.org.MSP.RootCerts) || !sameCerts(existing.org.MSP.IntermediateCerts, d.org.MSP.IntermediateCerts) {
+			return fmt.Errorf("MSP ID '%s' is declared by %s and %s with different trust roots", mspID, existing.location, d.location)
+		}
+	}
+
+	return nil
+}
+
+// sameCerts returns true if both lists contain the same certificates,
+// regardless of order.
+func sameCerts(a, b []*x509.Certificate) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for _, certA := range a {
+		found := false
+		for _, certB := range b {
+			if certA.Equal(certB) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
 }
 
 // Policy is an expression used to define rules for access to channels, chaincodes, etc.
@@ -60,6 +177,12 @@ type Organization struct {
 	AnchorPeers      []Address
 	OrdererEndpoints []string
 	ModPolicy        string
+
+	// CustomValues contains the config values of the organization which are
+	// not part of the standard Fabric config, keyed by value key. Values whose
+	// key was registered with RegisterCustomValue are decoded, other values
+	// are read as the raw *cb.ConfigValue, which is set as is.
+	CustomValues map[string]proto.Message
 }
 
 // Address contains the hostname and port for an endpoint.
@@ -84,6 +207,12 @@ type ConfigTx struct {
 	original *cb.Config
 	// modified state of the config
 	updated *cb.Config
+	// observer notified of the operations, if any
+	observer Observer
+	// logger receiving warnings, if any
+	logger Logger
+	// journal of the operations modifying the updated config
+	journal *journal
 }
 
 // New creates a new ConfigTx from a Config protobuf.
@@ -93,27 +222,101 @@ func New(config *cb.Config) ConfigTx {
 		original: config,
 		// Clone the base config for processing updates
 		updated: proto.Clone(config).(*cb.Config),
+		journal: newJournal(),
 	}
 }
 
-// OriginalConfig returns the original unedited config.
+// OriginalConfig returns the original unedited config. As the returned config
+// may be modified by the caller, later config updates are computed by
+// comparing the whole original and updated configs.
 func (c *ConfigTx) OriginalConfig() *cb.Config {
+	c.journal.invalidate()
 	return c.original
 }
 
-// UpdatedConfig returns the modified config.
+// UpdatedConfig returns the modified config. As the returned config may be
+// modified by the caller, later config updates are computed by comparing the
+// whole original and updated configs.
 func (c *ConfigTx) UpdatedConfig() *cb.Config {
+	c.journal.invalidate()
 	return c.updated
 }
 
 // ComputeMarshaledUpdate computes the ConfigUpdate from a base and modified
-// config transaction and returns the marshaled bytes.
-func (c *ConfigTx) ComputeMarshaledUpdate(channelID string) ([]byte, error) {
+// config transaction and returns the marshaled bytes. Unless the original or
+// updated config protos have been retrieved, only the parts of the config
+// modified by the operations performed through the ConfigTx are compared,
+// which is much faster for large configs.
+func (c *ConfigTx) ComputeMarshaledUpdate(channelID string) (marshaledUpdate []byte, err error) {
+	defer c.observe("ConfigTx.ComputeMarshaledUpdate", time.Now(), &err)
+
+	return computeScopedMarshaledUpdate(c.original, c.updated, c.journal.scope(), channelID)
+}
+
+// ComputeMarshaledUpdateFromConfigs computes the ConfigUpdate between two
+// marshaled Config protobufs and returns the marshaled bytes.
+func ComputeMarshaledUpdateFromConfigs(originalBytes, updatedBytes []byte, channelID string) ([]byte, error) {
+	original := &cb.Config{}
+	err := proto.Unmarshal(originalBytes, original)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling original config: %v", err)
+	}
+
+	updated := &cb.Config{}
+	err = proto.Unmarshal(updatedBytes, updated)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling updated config: %v", err)
+	}
+
+	return computeMarshaledUpdate(original, updated, channelID)
+}
+
+// ComputeMarshaledUpdateFromConfigBlocks computes the ConfigUpdate between the
+// configs of two marshaled config blocks and returns the marshaled bytes.
+func ComputeMarshaledUpdateFromConfigBlocks(originalBlockBytes, updatedBlockBytes []byte, channelID string) ([]byte, error) {
+	original, err := unmarshalConfigFromBlockBytes(originalBlockBytes)
+	if err != nil {
+		return nil, fmt.Errorf("original config block: %v", err)
+	}
+
+	updated, err := unmarshalConfigFromBlockBytes(updatedBlockBytes)
+	if err != nil {
+		return nil, fmt.Errorf("updated config block: %v", err)
+	}
+
+	return computeMarshaledUpdate(original, updated, channelID)
+}
+
+func unmarshalConfigFromBlockBytes(blockBytes []byte) (*cb.Config, error) {
+	block := &cb.Block{}
+	err := proto.Unmarshal(blockBytes, block)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling block: %v", err)
+	}
+
+	configEnvelope, _, err := unmarshalConfigBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	if configEnvelope.Config == nil {
+		return nil, errors.New("config envelope does not contain a config")
+	}
+
+	return configEnvelope.Config, nil
+}
+
+func computeMarshaledUpdate(original, updated *cb.Config, channelID string) ([]byte, error) {
+	return computeScopedMarshaledUpdate(original, updated, nil, channelID)
+}
+
+// computeScopedMarshaledUpdate computes the config update within the scope
+// and returns the marshaled bytes.
+func computeScopedMarshaledUpdate(original, updated *cb.Config, scope *updateScope, channelID string) ([]byte, error) {
 	if channelID == "" {
 		return nil, errors.New("channel ID is required")
 	}
 
-	update, err := computeConfigUpdate(c.original, c.updated)
+	update, err := computeScopedConfigUpdate(original, updated, scope)
 	if err != nil {
 		return nil, fmt.Errorf("failed to compute update: %v", err)
 	}
@@ -152,7 +355,8 @@ func NewEnvelope(marshaledUpdate []byte, signatures ...*cb.ConfigSignature) (*cb
 
 // NewMarshaledCreateChannelTx creates a create channel config update
 // transaction using the provided application channel configuration and returns
-// the marshaled bytes.
+// the marshaled bytes. The application ACLs, capabilities and the anchor peers
+// of the application orgs are included in the transaction.
 func NewMarshaledCreateChannelTx(channelConfig Channel, channelID string) ([]byte, error) {
 	if channelID == "" {
 		return nil, errors.New("profile's channel ID is required")
@@ -288,6 +492,11 @@ func newChannelGroupWithOrderer(channelConfig Channel) (*cb.ConfigGroup, error)
 		return nil, err
 	}
 
+	err = setCustomValues(channelGroup, channelConfig.CustomValues)
+	if err != nil {
+		return nil, err
+	}
+
 	ordererGroup, err := newOrdererGroup(channelConfig.Orderer)
 	if err != nil {
 		return nil, err
@@ -376,27 +585,15 @@ func setValue(cg *cb.ConfigGroup, value *standardConfigValue, modPolicy string)
 
 // implicitMetaFromString parses a *cb.ImplicitMetaPolicy from an input string.
 func implicitMetaFromString(input string) (*cb.ImplicitMetaPolicy, error) {
-	args := strings.Split(input, " ")
-	if len(args) != 2 {
-		return nil, fmt.Errorf("expected two space separated tokens, but got %d", len(args))
-	}
-
-	res := &cb.ImplicitMetaPolicy{
-		SubPolicy: args[1],
-	}
-
-	switch args[0] {
-	case cb.ImplicitMetaPolicy_ANY.String():
-		res.Rule = cb.ImplicitMetaPolicy_ANY
-	case cb.ImplicitMetaPolicy_ALL.String():
-		res.Rule = cb.ImplicitMetaPolicy_ALL
-	case cb.ImplicitMetaPolicy_MAJORITY.String():
-		res.Rule = cb.ImplicitMetaPolicy_MAJORITY
-	default:
-		return nil, fmt.Errorf("unknown rule type '%s', expected ALL, ANY, or MAJORITY", args[0])
+	rule, err := ParseImplicitMetaRule(input)
+	if err != nil {
+		return nil, err
 	}
 
-	return res, nil
+	return &cb.ImplicitMetaPolicy{
+		Rule:      cb.ImplicitMetaPolicy_Rule(cb.ImplicitMetaPolicy_Rule_value[string(rule.Type)]),
+		SubPolicy: rule.SubPolicy,
+	}, nil
 }
 
 // mspValue returns the config definition for an MSP.
@@ -424,6 +621,15 @@ func defaultConfigTemplate(channelConfig Channel) (*cb.ConfigGroup, error) {
 	channelGroup.Groups[ApplicationGroupKey].Values = nil
 	channelGroup.Groups[ApplicationGroupKey].Policies = nil
 
+	// The orderer knows the application orgs from their consortium definition,
+	// which includes their MSP and policies but no anchor peers.
+	for _, org := range channelConfig.Application.Organizations {
+		channelGroup.Groups[ApplicationGroupKey].Groups[org.Name], err = newOrgConfigGroup(org)
+		if err != nil {
+			return nil, fmt.Errorf("org group '%s': %v", org.Name, err)
+		}
+	}
+
 	return channelGroup, nil
 }
 
@@ -440,6 +646,11 @@ func newChannelGroup(channelConfig Channel) (*cb.ConfigGroup, error) {
 		return nil, err
 	}
 
+	err = setCustomValues(channelGroup, channelConfig.CustomValues)
+	if err != nil {
+		return nil, err
+	}
+
 	channelGroup.Groups[ApplicationGroupKey], err = newApplicationGroupTemplate(channelConfig.Application)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create application group: %v", err)
@@ -463,6 +674,16 @@ func newChannelCreateConfigUpdate(channelID string, channelConfig Channel, templ
 		return nil, err
 	}
 
+	// Include the anchor peers of the application orgs so that they are set
+	// when the channel is created. Orgs defining anchor peers are modified
+	// by the update, which then requires the signatures of their admins.
+	for _, org := range channelConfig.Application.Organizations {
+		newChannelGroup.Groups[ApplicationGroupKey].Groups[org.Name], err = newApplicationOrgConfigGroup(org)
+		if err != nil {
+			return nil, fmt.Errorf("org group '%s': %v", org.Name, err)
+		}
+	}
+
 	updt, err := computeConfigUpdate(&cb.Config{ChannelGroup: templateConfig}, &cb.Config{ChannelGroup: newChannelGroup})
 	if err != nil {
 		return nil, fmt.Errorf("computing update: %v", err)
@@ -590,23 +811,6 @@ func unmarshalConfigValueAtKey(group *cb.ConfigGroup, key string, msg proto.Mess
 	return nil
 }
 
-func parseAddress(address string) (string, int, error) {
-	hostport := strings.Split(address, ":")
-	if len(hostport) != 2 {
-		return "", 0, fmt.Errorf("unable to parse host and port from %s", address)
-	}
-
-	host := hostport[0]
-	port := hostport[1]
-
-	portNum, err := strconv.Atoi(port)
-	if err != nil {
-		return "", 0, err
-	}
-
-	return host, portNum, nil
-}
-
 // newBlock constructs a block with no data and no metadata.
 func newBlock(seqNum uint64, previousHash []byte) *cb.Block {
 	block := &cb.Block{}
@@ -14,6 +14,7 @@ import (
 	"testing"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	pb "github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/protolator"
 	. "github.com/onsi/gomega"
@@ -273,6 +274,48 @@ func TestNewCreateChannelTx(t *testing.T) {
 	gt.Expect(envelope).To(Equal(&expectedEnvelope))
 }
 
+func TestNewCreateChannelTxWithAnchorPeers(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	profile := baseProfile(t)
+	profile.Application.Organizations[0].AnchorPeers = []Address{
+		{Host: "peer0.org1", Port: 7051},
+	}
+
+	marshaledCreateChannelTx, err := NewMarshaledCreateChannelTx(profile, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	configUpdate := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledCreateChannelTx, configUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	readSet := configUpdate.ReadSet.Groups[ApplicationGroupKey]
+	writeSet := configUpdate.WriteSet.Groups[ApplicationGroupKey]
+	gt.Expect(writeSet.Values).To(HaveKey(ACLsKey))
+	gt.Expect(writeSet.Values).To(HaveKey(CapabilitiesKey))
+
+	gt.Expect(readSet.Groups["Org1"].Version).To(Equal(uint64(0)))
+	gt.Expect(readSet.Groups["Org1"].Values).To(HaveKey(MSPKey))
+	gt.Expect(readSet.Groups["Org1"].Values).NotTo(HaveKey(AnchorPeersKey))
+
+	org1 := writeSet.Groups["Org1"]
+	gt.Expect(org1.Version).To(Equal(uint64(1)))
+	gt.Expect(org1.ModPolicy).To(Equal(AdminsPolicyKey))
+	gt.Expect(org1.Values).To(HaveKey(AnchorPeersKey))
+	gt.Expect(org1.Values[AnchorPeersKey].Version).To(Equal(uint64(0)))
+
+	anchorPeers := &pb.AnchorPeers{}
+	err = proto.Unmarshal(org1.Values[AnchorPeersKey].Value, anchorPeers)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(anchorPeers.AnchorPeers).To(HaveLen(1))
+	gt.Expect(anchorPeers.AnchorPeers[0].Host).To(Equal("peer0.org1"))
+	gt.Expect(anchorPeers.AnchorPeers[0].Port).To(Equal(int32(7051)))
+
+	gt.Expect(writeSet.Groups["Org2"]).To(Equal(&cb.ConfigGroup{Version: 0}))
+}
+
 func TestNewCreateChannelTxFailure(t *testing.T) {
 	t.Parallel()
 
@@ -2034,6 +2077,135 @@ func TestComputeUpdateFailures(t *testing.T) {
 	}
 }
 
+func TestComputeMarshaledUpdateFromConfigs(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	genesisBlock, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	configEnvelope, _, err := unmarshalConfigBlock(genesisBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(configEnvelope.Config)
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expectedUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	originalBytes, err := proto.Marshal(c.OriginalConfig())
+	gt.Expect(err).NotTo(HaveOccurred())
+	updatedBytes, err := proto.Marshal(c.UpdatedConfig())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	marshaledUpdate, err := ComputeMarshaledUpdateFromConfigs(originalBytes, updatedBytes, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(unmarshalConfigUpdate(gt, marshaledUpdate), unmarshalConfigUpdate(gt, expectedUpdate))).To(BeTrue())
+
+	originalBlockBytes, err := proto.Marshal(genesisBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+	updatedBlock, err := newGenesisBlock(c.UpdatedConfig().ChannelGroup, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	updatedBlockBytes, err := proto.Marshal(updatedBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	marshaledUpdate, err = ComputeMarshaledUpdateFromConfigBlocks(originalBlockBytes, updatedBlockBytes, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(unmarshalConfigUpdate(gt, marshaledUpdate), unmarshalConfigUpdate(gt, expectedUpdate))).To(BeTrue())
+}
+
+func TestComputeMarshaledUpdateFromConfigsFailures(t *testing.T) {
+	t.Parallel()
+
+	configBytes, err := proto.Marshal(&cb.Config{ChannelGroup: newConfigGroup()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blockBytes, err := proto.Marshal(&cb.Block{Header: &cb.BlockHeader{}, Data: &cb.BlockData{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		testName    string
+		compute     func() ([]byte, error)
+		expectedErr string
+	}{
+		{
+			testName: "When the original config is malformed",
+			compute: func() ([]byte, error) {
+				return ComputeMarshaledUpdateFromConfigs([]byte("bad-config"), configBytes, "testchannel")
+			},
+			expectedErr: "unmarshaling original config: ",
+		},
+		{
+			testName: "When the updated config is malformed",
+			compute: func() ([]byte, error) {
+				return ComputeMarshaledUpdateFromConfigs(configBytes, []byte("bad-config"), "testchannel")
+			},
+			expectedErr: "unmarshaling updated config: ",
+		},
+		{
+			testName: "When the configs do not differ",
+			compute: func() ([]byte, error) {
+				return ComputeMarshaledUpdateFromConfigs(configBytes, configBytes, "testchannel")
+			},
+			expectedErr: "failed to compute update: no differences detected between original and updated config",
+		},
+		{
+			testName: "When the channel ID is not specified",
+			compute: func() ([]byte, error) {
+				return ComputeMarshaledUpdateFromConfigs(configBytes, configBytes, "")
+			},
+			expectedErr: "channel ID is required",
+		},
+		{
+			testName: "When the original block is malformed",
+			compute: func() ([]byte, error) {
+				return ComputeMarshaledUpdateFromConfigBlocks([]byte("bad-block"), blockBytes, "testchannel")
+			},
+			expectedErr: "original config block: unmarshaling block: ",
+		},
+		{
+			testName: "When the updated block is not a config block",
+			compute: func() ([]byte, error) {
+				genesisBlock, err := newGenesisBlock(newConfigGroup(), "testchannel")
+				if err != nil {
+					return nil, err
+				}
+				originalBlockBytes, err := proto.Marshal(genesisBlock)
+				if err != nil {
+					return nil, err
+				}
+				return ComputeMarshaledUpdateFromConfigBlocks(originalBlockBytes, blockBytes, "testchannel")
+			},
+			expectedErr: "updated config block: block 0 contains 0 transactions, config blocks contain exactly one",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			marshaledUpdate, err := tt.compute()
+			gt.Expect(err).To(HaveOccurred())
+			gt.Expect(err.Error()).To(HavePrefix(tt.expectedErr))
+			gt.Expect(marshaledUpdate).To(BeNil())
+		})
+	}
+}
+
+func unmarshalConfigUpdate(gt *GomegaWithT, marshaledUpdate []byte) *cb.ConfigUpdate {
+	configUpdate := &cb.ConfigUpdate{}
+	err := proto.Unmarshal(marshaledUpdate, configUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return configUpdate
+}
+
 func TestChannelConfiguration(t *testing.T) {
 	t.Parallel()
 
@@ -2234,3 +2406,62 @@ func baseApplicationChannelGroup(t *testing.T) (*cb.ConfigGroup, []*ecdsa.Privat
 
 	return channelGroup, privKeys, nil
 }
+
+func TestChannelValidate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	profile := baseProfile(t)
+	distinctMSPIDs(profile.Application.Organizations, "")
+	gt.Expect(profile.Validate()).To(Succeed())
+
+	profile.Consortium = ""
+	gt.Expect(profile.Validate()).To(MatchError("consortium is not defined in channel config"))
+
+	systemChannel, _, _ := baseSystemChannelProfile(t)
+	distinctMSPIDs(systemChannel.Consortiums[0].Organizations, "")
+	gt.Expect(systemChannel.Validate()).To(Succeed())
+
+	systemChannel.Consortiums[0].Name = ""
+	gt.Expect(systemChannel.Validate()).To(MatchError("invalid consortiums: consortium name is required"))
+
+	applicationChannel, _, _ := baseApplicationChannelProfile(t)
+	distinctMSPIDs(applicationChannel.Application.Organizations, "")
+	gt.Expect(applicationChannel.Validate()).To(Succeed())
+
+	applicationChannel.Capabilities = nil
+	gt.Expect(applicationChannel.Validate()).To(MatchError("capabilities is not defined in channel config"))
+
+	applicationChannel, _, _ = baseApplicationChannelProfile(t)
+	delete(applicationChannel.Policies, ReadersPolicyKey)
+	gt.Expect(applicationChannel.Validate()).To(MatchError("invalid channel policies: no Readers policy defined"))
+
+	applicationChannel, _, _ = baseApplicationChannelProfile(t)
+	applicationChannel.Orderer.BatchSize.AbsoluteMaxBytes = 0
+	gt.Expect(applicationChannel.Validate()).To(MatchError("invalid orderer: batch size absolute max bytes must be greater than zero"))
+
+	applicationChannel, _, _ = baseApplicationChannelProfile(t)
+	applicationChannel.Application.Organizations[0].Name = ""
+	gt.Expect(applicationChannel.Validate()).To(MatchError("invalid application: organization name is required"))
+
+	applicationChannel, _, _ = baseApplicationChannelProfile(t)
+	gt.Expect(applicationChannel.Validate()).To(MatchError("MSP ID 'MSPID' is declared by application org Org1 and application org Org2 with different trust roots"))
+
+	distinctMSPIDs(applicationChannel.Application.Organizations, "")
+	applicationChannel.Application.Organizations[1].MSP.Name = "MSPID"
+	gt.Expect(applicationChannel.Validate()).To(MatchError("MSP ID 'MSPID' is declared by application org Org2 and orderer org OrdererOrg with different trust roots"))
+
+	applicationChannel.Application.Organizations[1].MSP = applicationChannel.Orderer.Organizations[0].MSP
+	gt.Expect(applicationChannel.Validate()).To(Succeed())
+
+	systemChannel, _, _ = baseSystemChannelProfile(t)
+	gt.Expect(systemChannel.Validate()).To(MatchError("MSP ID 'MSPID' is declared by orderer org OrdererOrg and consortium Consortium1 org Org1 with different trust roots"))
+}
+
+// distinctMSPIDs gives each organization an MSP ID derived from its name, as
+// the base fixtures declare the MSP ID 'MSPID' with different root certs.
+func distinctMSPIDs(orgs []Organization, prefix string) {
+	for i := range orgs {
+		orgs[i].MSP.Name = prefix + orgs[i].Name + "MSP"
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+)
+
+// VersionMode determines how CopyConfigGroup and ExtractConfigGroup treat the
+// versions of the copied group and its elements.
+type VersionMode int
+
+const (
+	// KeepVersions keeps the versions of the copy. This is the default.
+	KeepVersions VersionMode = iota
+
+	// ResetVersions sets the versions of the copied group and of its
+	// subgroups, values and policies to zero, as for a group added by a
+	// config update. It suits copying a group into another channel or
+	// templating group structures.
+	ResetVersions
+)
+
+// CopyConfigGroup returns a deep copy of the config group which shares no
+// memory with it. The groups, values and policies maps of the copy and of its
+// subgroups are never nil, so elements can be added to them directly.
+func CopyConfigGroup(group *cb.ConfigGroup, mode VersionMode) *cb.ConfigGroup {
+	if group == nil {
+		return nil
+	}
+
+	copied := proto.Clone(group).(*cb.ConfigGroup)
+	normalizeCopiedGroup(copied, mode)
+
+	return copied
+}
+
+// ExtractConfigGroup returns a deep copy, see CopyConfigGroup, of the subgroup
+// of the config group at the path. The path is made of the names of the
+// nested subgroups separated by slashes, such as Application/Org1 below the
+// channel group; an empty path selects the group itself.
+func ExtractConfigGroup(group *cb.ConfigGroup, path string, mode VersionMode) (*cb.ConfigGroup, error) {
+	if group == nil {
+		return nil, errors.New("config group is nil")
+	}
+
+	if path != "" {
+		names := strings.Split(path, "/")
+		for i, name := range names {
+			subGroup, ok := group.Groups[name]
+			if name == "" || !ok {
+				return nil, fmt.Errorf("group %s does not exist", strings.Join(names[:i+1], "/"))
+			}
+			group = subGroup
+		}
+	}
+
+	return CopyConfigGroup(group, mode), nil
+}
+
+// normalizeCopiedGroup initializes the nil maps of the group and its
+// subgroups and applies the version mode.
+func normalizeCopiedGroup(group *cb.ConfigGroup, mode VersionMode) {
+	if group.Groups == nil {
+		group.Groups = map[string]*cb.ConfigGroup{}
+	}
+	if group.Values == nil {
+		group.Values = map[string]*cb.ConfigValue{}
+	}
+	if group.Policies == nil {
+		group.Policies = map[string]*cb.ConfigPolicy{}
+	}
+
+	if mode == ResetVersions {
+		group.Version = 0
+		for _, value := range group.Values {
+			if value != nil {
+				value.Version = 0
+			}
+		}
+		for _, policy := range group.Policies {
+			if policy != nil {
+				policy.Version = 0
+			}
+		}
+	}
+
+	for _, subGroup := range group.Groups {
+		if subGroup != nil {
+			normalizeCopiedGroup(subGroup, mode)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestCopyConfigGroup(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	org1 := channelGroup.Groups[ApplicationGroupKey].Groups["Org1"]
+	org1.Version = 3
+	org1.Values[MSPKey].Version = 2
+	org1.Policies[AdminsPolicyKey].Version = 1
+
+	copied := CopyConfigGroup(channelGroup, KeepVersions)
+	gt.Expect(proto.Equal(copied, channelGroup)).To(BeTrue())
+	copied.Groups[ApplicationGroupKey].Groups["Org1"].Values[MSPKey].Value = []byte("modified")
+	gt.Expect(org1.Values[MSPKey].Value).NotTo(Equal([]byte("modified")))
+
+	copied = CopyConfigGroup(channelGroup, ResetVersions)
+	copiedOrg1 := copied.Groups[ApplicationGroupKey].Groups["Org1"]
+	gt.Expect(copiedOrg1.Version).To(BeZero())
+	gt.Expect(copiedOrg1.Values[MSPKey].Version).To(BeZero())
+	gt.Expect(copiedOrg1.Policies[AdminsPolicyKey].Version).To(BeZero())
+	gt.Expect(org1.Version).To(Equal(uint64(3)))
+	gt.Expect(org1.Values[MSPKey].Version).To(Equal(uint64(2)))
+	gt.Expect(org1.Policies[AdminsPolicyKey].Version).To(Equal(uint64(1)))
+
+	copied = CopyConfigGroup(&cb.ConfigGroup{Groups: map[string]*cb.ConfigGroup{"Empty": {}}}, KeepVersions)
+	gt.Expect(copied.Values).NotTo(BeNil())
+	gt.Expect(copied.Policies).NotTo(BeNil())
+	gt.Expect(copied.Groups["Empty"].Groups).NotTo(BeNil())
+	gt.Expect(copied.Groups["Empty"].Values).NotTo(BeNil())
+	gt.Expect(copied.Groups["Empty"].Policies).NotTo(BeNil())
+
+	gt.Expect(CopyConfigGroup(nil, KeepVersions)).To(BeNil())
+}
+
+func TestExtractConfigGroup(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	org1 := channelGroup.Groups[ApplicationGroupKey].Groups["Org1"]
+	org1.Values[MSPKey].Version = 2
+
+	extracted, err := ExtractConfigGroup(channelGroup, "Application/Org1", KeepVersions)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(extracted, org1)).To(BeTrue())
+	gt.Expect(extracted).NotTo(BeIdenticalTo(org1))
+
+	extracted, err = ExtractConfigGroup(channelGroup, "Application/Org1", ResetVersions)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(extracted.Values[MSPKey].Version).To(BeZero())
+
+	// the extracted org can be added to another channel under a new name
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	c.updated.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org3"] = extracted
+	gt.Expect(c.Application().Organization("Org3")).NotTo(BeNil())
+
+	extracted, err = ExtractConfigGroup(channelGroup, "", KeepVersions)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(extracted, channelGroup)).To(BeTrue())
+}
+
+func TestExtractConfigGroupFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		path        string
+		expectedErr string
+	}{
+		{
+			testName:    "when the group does not exist",
+			path:        "Orderer",
+			expectedErr: "group Orderer does not exist",
+		},
+		{
+			testName:    "when a nested group does not exist",
+			path:        "Application/Org3/Sub",
+			expectedErr: "group Application/Org3 does not exist",
+		},
+		{
+			testName:    "when the path has an empty name",
+			path:        "Application//Org1",
+			expectedErr: "group Application/ does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseApplicationChannelGroup(t)
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			_, err = ExtractConfigGroup(channelGroup, tt.path, KeepVersions)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+
+	gt := NewGomegaWithT(t)
+	_, err := ExtractConfigGroup(nil, "Application", KeepVersions)
+	gt.Expect(err).To(MatchError("config group is nil"))
+}
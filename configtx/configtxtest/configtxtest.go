@@ -0,0 +1,251 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package configtxtest generates the certificates, keys and CRLs of test
+// fixtures for channel configs.
+package configtxtest
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	mrand "math/rand"
+	"time"
+)
+
+// Validity is the validity period of the generated certificates and CRLs.
+const Validity = 365 * 24 * time.Hour
+
+// Generator generates certificates, private keys and CRLs. The zero value
+// uses crypto/rand and the current time.
+//
+// The output of a generator only depends on the bytes read from Rand and the
+// times returned by Clock, so supplying a seeded source and a fixed clock
+// produces identical fixtures across runs.
+type Generator struct {
+	// Rand is the source of the private keys and serial numbers. If nil,
+	// crypto/rand is used.
+	Rand io.Reader
+
+	// Clock returns the time at which certificates become valid and CRLs are
+	// issued. If nil, time.Now is used.
+	Clock func() time.Time
+}
+
+// NewSeededGenerator returns a generator whose fixtures are reproducible:
+// its randomness is derived from the seed and its clock always returns now.
+// The returned generator is not safe for concurrent use.
+func NewSeededGenerator(seed int64, now time.Time) *Generator {
+	return &Generator{
+		Rand: mrand.New(mrand.NewSource(seed)),
+		Clock: func() time.Time {
+			return now
+		},
+	}
+}
+
+// CA returns a self-signed root CA certificate for the organization and its
+// private key.
+func (g *Generator) CA(orgName string) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	template := g.template("ca."+orgName, orgName)
+	template.IsCA = true
+	template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	return g.newCert(template, nil, nil)
+}
+
+// IntermediateCA returns an intermediate CA certificate for the organization
+// issued by the given CA and its private key.
+func (g *Generator) IntermediateCA(orgName string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	if ca == nil || caKey == nil {
+		return nil, nil, errors.New("issuing CA and key are required")
+	}
+
+	template := g.template("intermediateca."+orgName, orgName)
+	template.IsCA = true
+	template.KeyUsage |= x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	return g.newCert(template, ca, caKey)
+}
+
+// Cert returns a certificate with the given common name issued by the given
+// CA and its private key. The certificate belongs to the organization of the
+// CA and can be used for signing and for TLS.
+func (g *Generator) Cert(commonName string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	if ca == nil || caKey == nil {
+		return nil, nil, errors.New("issuing CA and key are required")
+	}
+
+	var orgName string
+	if len(ca.Subject.Organization) > 0 {
+		orgName = ca.Subject.Organization[0]
+	}
+
+	template := g.template(commonName, orgName)
+	template.DNSNames = []string{commonName}
+	template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+
+	return g.newCert(template, ca, caKey)
+}
+
+// NodeCert returns a certificate like Cert which additionally carries the
+// given organizational unit, such as the client, peer, admin or orderer OU of
+// an MSP which enables NodeOUs.
+func (g *Generator) NodeCert(commonName, ou string, ca *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	if ca == nil || caKey == nil {
+		return nil, nil, errors.New("issuing CA and key are required")
+	}
+
+	var orgName string
+	if len(ca.Subject.Organization) > 0 {
+		orgName = ca.Subject.Organization[0]
+	}
+
+	template := g.template(commonName, orgName)
+	template.Subject.OrganizationalUnit = []string{ou}
+	template.DNSNames = []string{commonName}
+	template.ExtKeyUsage = append(template.ExtKeyUsage, x509.ExtKeyUsageClientAuth)
+
+	return g.newCert(template, ca, caKey)
+}
+
+// CRL returns a CRL issued by the given CA which revokes the given
+// certificates at the current time of the generator.
+func (g *Generator) CRL(ca *x509.Certificate, caKey *ecdsa.PrivateKey, revoked ...*x509.Certificate) (*pkix.CertificateList, error) {
+	if ca == nil || caKey == nil {
+		return nil, errors.New("issuing CA and key are required")
+	}
+
+	now := g.now()
+	revokedCerts := make([]pkix.RevokedCertificate, len(revoked))
+	for i, cert := range revoked {
+		revokedCerts[i] = pkix.RevokedCertificate{
+			SerialNumber:   cert.SerialNumber,
+			RevocationTime: now,
+		}
+	}
+
+	crlBytes, err := ca.CreateCRL(g.rand(), deterministicSigner{caKey}, revokedCerts, now, now.Add(Validity))
+	if err != nil {
+		return nil, fmt.Errorf("creating CRL: %v", err)
+	}
+
+	crl, err := x509.ParseCRL(crlBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing CRL: %v", err)
+	}
+
+	return crl, nil
+}
+
+func (g *Generator) template(commonName, orgName string) *x509.Certificate {
+	now := g.now()
+
+	return &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName:   commonName,
+			Organization: []string{orgName},
+		},
+		NotBefore:             now,
+		NotAfter:              now.Add(Validity),
+		KeyUsage:              x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+}
+
+// newCert issues a certificate for a new private key from the template. If
+// parent is nil, the certificate is self-signed.
+func (g *Generator) newCert(template, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey, error) {
+	priv, err := newPrivateKey(g.rand())
+	if err != nil {
+		return nil, nil, fmt.Errorf("generating private key: %v", err)
+	}
+
+	serialNumber, err := g.serialNumber()
+	if err != nil {
+		return nil, nil, err
+	}
+	template.SerialNumber = serialNumber
+
+	template.SubjectKeyId, err = subjectKeyID(&priv.PublicKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if parent == nil {
+		parent = template
+		parentKey = priv
+	}
+
+	derBytes, err := x509.CreateCertificate(g.rand(), template, parent, &priv.PublicKey, deterministicSigner{parentKey})
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating certificate: %v", err)
+	}
+
+	cert, err := x509.ParseCertificate(derBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing certificate: %v", err)
+	}
+
+	return cert, priv, nil
+}
+
+func (g *Generator) serialNumber() (*big.Int, error) {
+	b := make([]byte, 16)
+	_, err := io.ReadFull(g.rand(), b)
+	if err != nil {
+		return nil, fmt.Errorf("generating serial number: %v", err)
+	}
+
+	// Serial numbers must be positive.
+	return new(big.Int).Add(new(big.Int).SetBytes(b), big.NewInt(1)), nil
+}
+
+func (g *Generator) rand() io.Reader {
+	if g.Rand == nil {
+		return rand.Reader
+	}
+
+	return g.Rand
+}
+
+func (g *Generator) now() time.Time {
+	if g.Clock == nil {
+		return time.Now()
+	}
+
+	return g.Clock()
+}
+
+// subjectKeyID returns the SHA-1 hash of the marshaled public key, as
+// computed by x509.CreateCertificate for CA certificates.
+func subjectKeyID(pub *ecdsa.PublicKey) ([]byte, error) {
+	pkixPub, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling public key: %v", err)
+	}
+
+	var spki struct {
+		Algorithm        pkix.AlgorithmIdentifier
+		SubjectPublicKey asn1.BitString
+	}
+	_, err = asn1.Unmarshal(pkixPub, &spki)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling public key: %v", err)
+	}
+
+	ski := sha1.Sum(spki.SubjectPublicKey.Bytes)
+
+	return ski[:], nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxtest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSeededGeneratorIsReproducible(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	generate := func(seed int64) [][]byte {
+		g := NewSeededGenerator(seed, now)
+
+		ca, caKey, err := g.CA("Org1")
+		gt.Expect(err).NotTo(HaveOccurred())
+		intermediate, intermediateKey, err := g.IntermediateCA("Org1", ca, caKey)
+		gt.Expect(err).NotTo(HaveOccurred())
+		cert, _, err := g.Cert("peer0.org1.example.com", intermediate, intermediateKey)
+		gt.Expect(err).NotTo(HaveOccurred())
+		crl, err := g.CRL(intermediate, intermediateKey, cert)
+		gt.Expect(err).NotTo(HaveOccurred())
+		crlBytes, err := asn1.Marshal(*crl)
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		return [][]byte{ca.Raw, intermediate.Raw, cert.Raw, crlBytes}
+	}
+
+	first := generate(42)
+	gt.Expect(generate(42)).To(Equal(first))
+	gt.Expect(generate(43)).NotTo(Equal(first))
+}
+
+func TestGenerator(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	g := &Generator{}
+
+	ca, caKey, err := g.CA("Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ca.IsCA).To(BeTrue())
+	gt.Expect(ca.KeyUsage & x509.KeyUsageCertSign).NotTo(BeZero())
+	gt.Expect(ca.CheckSignatureFrom(ca)).To(Succeed())
+
+	intermediate, intermediateKey, err := g.IntermediateCA("Org1", ca, caKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(intermediate.IsCA).To(BeTrue())
+	gt.Expect(intermediate.CheckSignatureFrom(ca)).To(Succeed())
+
+	cert, _, err := g.Cert("peer0.org1.example.com", intermediate, intermediateKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(cert.IsCA).To(BeFalse())
+	gt.Expect(cert.Subject.Organization).To(Equal([]string{"Org1"}))
+	gt.Expect(cert.SubjectKeyId).NotTo(BeEmpty())
+	gt.Expect(cert.AuthorityKeyId).To(Equal(intermediate.SubjectKeyId))
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(intermediate)
+	_, err = cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		DNSName:       "peer0.org1.example.com",
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	nodeCert, _, err := g.NodeCert("orderer0.org1.example.com", "orderer", ca, caKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(nodeCert.Subject.OrganizationalUnit).To(Equal([]string{"orderer"}))
+	gt.Expect(nodeCert.CheckSignatureFrom(ca)).To(Succeed())
+
+	crl, err := g.CRL(intermediate, intermediateKey, cert)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(intermediate.CheckCRLSignature(crl)).To(Succeed())
+	gt.Expect(crl.TBSCertList.RevokedCertificates).To(HaveLen(1))
+	gt.Expect(crl.TBSCertList.RevokedCertificates[0].SerialNumber).To(Equal(cert.SerialNumber))
+
+	_, _, err = g.Cert("peer0.org1.example.com", nil, intermediateKey)
+	gt.Expect(err).To(MatchError("issuing CA and key are required"))
+	_, _, err = g.NodeCert("orderer0.org1.example.com", "orderer", nil, nil)
+	gt.Expect(err).To(MatchError("issuing CA and key are required"))
+	_, _, err = g.IntermediateCA("Org1", ca, nil)
+	gt.Expect(err).To(MatchError("issuing CA and key are required"))
+	_, err = g.CRL(nil, nil)
+	gt.Expect(err).To(MatchError("issuing CA and key are required"))
+}
+
+func TestGeneratorClock(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	g := &Generator{Clock: func() time.Time { return now }}
+
+	ca, caKey, err := g.CA("Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ca.NotBefore).To(Equal(now))
+	gt.Expect(ca.NotAfter).To(Equal(now.Add(Validity)))
+
+	crl, err := g.CRL(ca, caKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(crl.TBSCertList.ThisUpdate).To(Equal(now))
+	gt.Expect(crl.TBSCertList.NextUpdate).To(Equal(now.Add(Validity)))
+}
+
+// TestDeterministicSigner checks the signer against the P-256 SHA-256 test
+// vector of RFC 6979 A.2.5, normalized to a low-S signature.
+func TestDeterministicSigner(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	curve := elliptic.P256()
+	priv := &ecdsa.PrivateKey{D: hexInt(t, "C9AFA9D845BA75166B5C215767B1D6934E50C3DB36E89B127B8A622B120F6721")}
+	priv.Curve = curve
+	priv.X, priv.Y = curve.ScalarBaseMult(priv.D.Bytes())
+
+	digest := sha256.Sum256([]byte("sample"))
+	sig, err := deterministicSigner{priv}.Sign(nil, digest[:], nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var rs struct{ R, S *big.Int }
+	_, err = asn1.Unmarshal(sig, &rs)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expectedS := new(big.Int).Sub(curve.Params().N, hexInt(t, "F7CB1C942D657C41D436C7A1B6E29F65F3E900DBB9AFF4064DC4AB2F843ACDA8"))
+	gt.Expect(rs.R).To(Equal(hexInt(t, "EFD48B2AACB6A8FD1140DD9CD45E81D69D2C877B56AAF991C34D0EA84EAF3716")))
+	gt.Expect(rs.S).To(Equal(expectedS))
+	gt.Expect(ecdsa.Verify(&priv.PublicKey, digest[:], rs.R, rs.S)).To(BeTrue())
+}
+
+func hexInt(t *testing.T, s string) *big.Int {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decoding hex: %v", err)
+	}
+
+	return new(big.Int).SetBytes(b)
+}
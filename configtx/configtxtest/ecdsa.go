@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxtest
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+// newPrivateKey derives a P-256 private key from the bytes read from rand.
+// Unlike ecdsa.GenerateKey, the key only depends on the bytes read, which
+// makes it reproducible when rand is seeded.
+func newPrivateKey(rand io.Reader) (*ecdsa.PrivateKey, error) {
+	curve := elliptic.P256()
+	n := curve.Params().N
+
+	// As in FIPS 186-4 B.4.1, read 64 extra bits to make the bias of the
+	// modular reduction negligible.
+	b := make([]byte, n.BitLen()/8+8)
+	_, err := io.ReadFull(rand, b)
+	if err != nil {
+		return nil, fmt.Errorf("reading random bytes: %v", err)
+	}
+
+	d := new(big.Int).SetBytes(b)
+	d.Mod(d, new(big.Int).Sub(n, big.NewInt(1)))
+	d.Add(d, big.NewInt(1))
+
+	priv := &ecdsa.PrivateKey{D: d}
+	priv.Curve = curve
+	priv.X, priv.Y = curve.ScalarBaseMult(d.Bytes())
+
+	return priv, nil
+}
+
+// deterministicSigner signs with deterministic ECDSA nonces as specified by
+// RFC 6979 and low-S normalized signatures as required by Fabric, so that
+// certificates and CRLs signed with the same key and content are identical.
+type deterministicSigner struct {
+	*ecdsa.PrivateKey
+}
+
+// Sign signs the digest. The rand and opts arguments are ignored.
+func (s deterministicSigner) Sign(_ io.Reader, digest []byte, _ crypto.SignerOpts) ([]byte, error) {
+	curve := s.Curve
+	n := curve.Params().N
+
+	e := bits2int(digest, n)
+	k := rfc6979Nonce(s.D, digest, n)
+
+	x, _ := curve.ScalarBaseMult(k.Bytes())
+	r := new(big.Int).Mod(x, n)
+	if r.Sign() == 0 {
+		return nil, errors.New("invalid nonce")
+	}
+
+	sig := new(big.Int).Mul(r, s.D)
+	sig.Add(sig, e)
+	sig.Mul(sig, new(big.Int).ModInverse(k, n))
+	sig.Mod(sig, n)
+	if sig.Sign() == 0 {
+		return nil, errors.New("invalid nonce")
+	}
+
+	if sig.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		sig.Sub(n, sig)
+	}
+
+	return asn1.Marshal(struct{ R, S *big.Int }{r, sig})
+}
+
+// rfc6979Nonce returns the nonce for signing the digest with the private
+// key d, using HMAC-SHA256 as specified by section 3.2 of RFC 6979.
+func rfc6979Nonce(d *big.Int, digest []byte, n *big.Int) *big.Int {
+	rlen := (n.BitLen() + 7) / 8
+
+	x := int2octets(d, rlen)
+	h1 := int2octets(new(big.Int).Mod(bits2int(digest, n), n), rlen)
+
+	v := make([]byte, sha256.Size)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, sha256.Size)
+
+	k = hmacSHA256(k, v, []byte{0x00}, x, h1)
+	v = hmacSHA256(k, v)
+	k = hmacSHA256(k, v, []byte{0x01}, x, h1)
+	v = hmacSHA256(k, v)
+
+	for {
+		var t []byte
+		for len(t) < rlen {
+			v = hmacSHA256(k, v)
+			t = append(t, v...)
+		}
+
+		nonce := bits2int(t[:rlen], n)
+		if nonce.Sign() > 0 && nonce.Cmp(n) < 0 {
+			return nonce
+		}
+
+		k = hmacSHA256(k, v, []byte{0x00})
+		v = hmacSHA256(k, v)
+	}
+}
+
+// bits2int converts the leftmost bits of b to an integer of at most the bit
+// length of n.
+func bits2int(b []byte, n *big.Int) *big.Int {
+	i := new(big.Int).SetBytes(b)
+	if excess := len(b)*8 - n.BitLen(); excess > 0 {
+		i.Rsh(i, uint(excess))
+	}
+
+	return i
+}
+
+// int2octets returns the big-endian encoding of i padded to rlen bytes.
+func int2octets(i *big.Int, rlen int) []byte {
+	b := i.Bytes()
+	if len(b) >= rlen {
+		return b[len(b)-rlen:]
+	}
+
+	return append(make([]byte, rlen-len(b)), b...)
+}
+
+func hmacSHA256(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+
+	return mac.Sum(nil)
+}
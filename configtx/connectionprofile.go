@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ConnectionProfile is a common connection profile as consumed by the Fabric
+// SDKs and the Fabric Gateway clients.
+type ConnectionProfile struct {
+	Name    string `json:"name" yaml:"name"`
+	Version string `json:"version" yaml:"version"`
+	// Channels maps the channel name to the orderers and peers serving it.
+	Channels map[string]ConnectionProfileChannel `json:"channels" yaml:"channels"`
+	// Organizations maps the organization names to their MSP IDs and peers.
+	Organizations map[string]ConnectionProfileOrg  `json:"organizations" yaml:"organizations"`
+	Orderers      map[string]ConnectionProfileNode `json:"orderers,omitempty" yaml:"orderers,omitempty"`
+	Peers         map[string]ConnectionProfileNode `json:"peers,omitempty" yaml:"peers,omitempty"`
+}
+
+// ConnectionProfileChannel lists the names of the orderers and peers of a
+// channel in a connection profile.
+type ConnectionProfileChannel struct {
+	Orderers []string            `json:"orderers,omitempty" yaml:"orderers,omitempty"`
+	Peers    map[string]struct{} `json:"peers,omitempty" yaml:"peers,omitempty"`
+}
+
+// ConnectionProfileOrg is an organization of a connection profile.
+type ConnectionProfileOrg struct {
+	MSPID string   `json:"mspid" yaml:"mspid"`
+	Peers []string `json:"peers,omitempty" yaml:"peers,omitempty"`
+}
+
+// ConnectionProfileNode is an orderer or peer of a connection profile.
+type ConnectionProfileNode struct {
+	URL         string                 `json:"url" yaml:"url"`
+	TLSCACerts  ConnectionProfileCerts `json:"tlsCACerts" yaml:"tlsCACerts"`
+	GRPCOptions map[string]string      `json:"grpcOptions,omitempty" yaml:"grpcOptions,omitempty"`
+}
+
+// ConnectionProfileCerts holds PEM encoded certificates.
+type ConnectionProfileCerts struct {
+	PEM string `json:"pem" yaml:"pem"`
+}
+
+// JSON returns the connection profile encoded as JSON.
+func (p ConnectionProfile) JSON() ([]byte, error) {
+	return json.MarshalIndent(p, "", "\t")
+}
+
+// YAML returns the connection profile encoded as YAML.
+func (p ConnectionProfile) YAML() ([]byte, error) {
+	return yaml.Marshal(p)
+}
+
+// ConnectionProfile returns a connection profile for the channel with the
+// given ID built from the updated config. It lists the orderer endpoints of
+// the orderer orgs and the anchor peers of the application orgs, each trusting
+// the TLS root and intermediate certs of its org.
+func (c *ConfigTx) ConnectionProfile(channelID string) (ConnectionProfile, error) {
+	if channelID == "" {
+		return ConnectionProfile{}, errors.New("channel ID is required")
+	}
+
+	profile := ConnectionProfile{
+		Name:          channelID,
+		Version:       "1.0.0",
+		Organizations: map[string]ConnectionProfileOrg{},
+	}
+	channel := ConnectionProfileChannel{}
+
+	if _, ok := c.updated.ChannelGroup.Groups[OrdererGroupKey]; ok {
+		ordererConfig, err := c.Orderer().Configuration()
+		if err != nil {
+			return ConnectionProfile{}, fmt.Errorf("retrieving orderer config: %v", err)
+		}
+
+		for _, org := range ordererConfig.Organizations {
+			profile.Organizations[org.Name] = ConnectionProfileOrg{MSPID: org.MSP.Name}
+
+			for _, endpoint := range org.OrdererEndpoints {
+				host, port, err := splitEndpoint(endpoint)
+				if err != nil {
+					return ConnectionProfile{}, fmt.Errorf("invalid endpoint '%s' of org %s: %v", endpoint, org.Name, err)
+				}
+
+				if profile.Orderers == nil {
+					profile.Orderers = map[string]ConnectionProfileNode{}
+				}
+				name := connectionProfileNodeName(profile.Orderers, host, port)
+				profile.Orderers[name] = newConnectionProfileNode(host, port, org.MSP)
+				channel.Orderers = append(channel.Orderers, name)
+			}
+		}
+	}
+
+	if _, ok := c.updated.ChannelGroup.Groups[ApplicationGroupKey]; ok {
+		application, err := c.Application().Configuration()
+		if err != nil {
+			return ConnectionProfile{}, fmt.Errorf("retrieving application config: %v", err)
+		}
+
+		for _, org := range application.Organizations {
+			profileOrg := ConnectionProfileOrg{MSPID: org.MSP.Name}
+
+			for _, anchorPeer := range org.AnchorPeers {
+				if profile.Peers == nil {
+					profile.Peers = map[string]ConnectionProfileNode{}
+					channel.Peers = map[string]struct{}{}
+				}
+				name := connectionProfileNodeName(profile.Peers, anchorPeer.Host, anchorPeer.Port)
+				profile.Peers[name] = newConnectionProfileNode(anchorPeer.Host, anchorPeer.Port, org.MSP)
+				channel.Peers[name] = struct{}{}
+				profileOrg.Peers = append(profileOrg.Peers, name)
+			}
+
+			profile.Organizations[org.Name] = profileOrg
+		}
+	}
+
+	profile.Channels = map[string]ConnectionProfileChannel{channelID: channel}
+
+	return profile, nil
+}
+
+// connectionProfileNodeName returns the name of a node of a connection
+// profile, which is its host unless another node of the same host is already
+// named so.
+func connectionProfileNodeName(nodes map[string]ConnectionProfileNode, host string, port int) string {
+	if _, ok := nodes[host]; !ok {
+		return host
+	}
+
+	return fmt.Sprintf("%s:%d", host, port)
+}
+
+func newConnectionProfileNode(host string, port int, msp MSP) ConnectionProfileNode {
+	return ConnectionProfileNode{
+		URL: fmt.Sprintf("grpcs://%s:%d", host, port),
+		TLSCACerts: ConnectionProfileCerts{
+			PEM: pemBundle(msp.TLSRootCerts) + pemBundle(msp.TLSIntermediateCerts),
+		},
+		GRPCOptions: map[string]string{
+			"ssl-target-name-override": host,
+		},
+	}
+}
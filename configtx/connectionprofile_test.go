@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+func TestConnectionProfile(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	genesisBlock, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	configEnvelope, _, err := unmarshalConfigBlock(genesisBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(configEnvelope.Config)
+
+	for _, port := range []int{7051, 8051} {
+		err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: port})
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	profile, err := c.ConnectionProfile("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererMSP, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	org1MSP, err := c.Application().Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(profile.Name).To(Equal("testchannel"))
+	gt.Expect(profile.Channels).To(Equal(map[string]ConnectionProfileChannel{
+		"testchannel": {
+			Orderers: []string{"localhost"},
+			Peers: map[string]struct{}{
+				"peer0.org1.example.com":      {},
+				"peer0.org1.example.com:8051": {},
+			},
+		},
+	}))
+	gt.Expect(profile.Organizations).To(Equal(map[string]ConnectionProfileOrg{
+		"OrdererOrg": {MSPID: ordererMSP.Name},
+		"Org1": {
+			MSPID: org1MSP.Name,
+			Peers: []string{"peer0.org1.example.com", "peer0.org1.example.com:8051"},
+		},
+		"Org2": {MSPID: "MSPID"},
+	}))
+	gt.Expect(profile.Orderers).To(Equal(map[string]ConnectionProfileNode{
+		"localhost": {
+			URL:         "grpcs://localhost:123",
+			TLSCACerts:  ConnectionProfileCerts{PEM: string(pemEncodeX509Certificate(ordererMSP.TLSRootCerts[0])) + string(pemEncodeX509Certificate(ordererMSP.TLSIntermediateCerts[0]))},
+			GRPCOptions: map[string]string{"ssl-target-name-override": "localhost"},
+		},
+	}))
+	gt.Expect(profile.Peers["peer0.org1.example.com:8051"]).To(Equal(ConnectionProfileNode{
+		URL:         "grpcs://peer0.org1.example.com:8051",
+		TLSCACerts:  ConnectionProfileCerts{PEM: string(pemEncodeX509Certificate(org1MSP.TLSRootCerts[0])) + string(pemEncodeX509Certificate(org1MSP.TLSIntermediateCerts[0]))},
+		GRPCOptions: map[string]string{"ssl-target-name-override": "peer0.org1.example.com"},
+	}))
+
+	jsonProfile, err := profile.JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	decoded := ConnectionProfile{}
+	gt.Expect(json.Unmarshal(jsonProfile, &decoded)).To(Succeed())
+	gt.Expect(decoded).To(Equal(profile))
+
+	yamlProfile, err := profile.YAML()
+	gt.Expect(err).NotTo(HaveOccurred())
+	decoded = ConnectionProfile{}
+	gt.Expect(yaml.Unmarshal(yamlProfile, &decoded)).To(Succeed())
+	gt.Expect(decoded).To(Equal(profile))
+	gt.Expect(string(yamlProfile)).To(ContainSubstring("url: grpcs://localhost:123"))
+
+	_, err = c.ConnectionProfile("")
+	gt.Expect(err).To(MatchError("channel ID is required"))
+}
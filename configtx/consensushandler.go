@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// ConsensusTypeHandler handles the consensus metadata of a consensus type
+// which this package does not model, such as a proprietary consensus plugin.
+// Once registered with RegisterConsensusType, the orderer group code uses it
+// to read, write and validate the CustomMetadata of an Orderer of that type.
+type ConsensusTypeHandler interface {
+	// MarshalMetadata marshals the metadata into the metadata of the
+	// ConsensusType config value.
+	MarshalMetadata(metadata interface{}) ([]byte, error)
+	// UnmarshalMetadata unmarshals the metadata of the ConsensusType config
+	// value.
+	UnmarshalMetadata(metadata []byte) (interface{}, error)
+	// ValidateMetadata checks that the metadata is complete and consistent.
+	ValidateMetadata(metadata interface{}) error
+}
+
+// builtinConsensusTypes are the consensus types modeled by this package,
+// whose handling cannot be replaced.
+var builtinConsensusTypes = map[string]bool{
+	orderer.ConsensusTypeSolo:     true,
+	orderer.ConsensusTypeKafka:    true,
+	orderer.ConsensusTypeEtcdRaft: true,
+	orderer.ConsensusTypeSmartBFT: true,
+}
+
+var consensusTypeRegistry = struct {
+	sync.RWMutex
+	handlers map[string]ConsensusTypeHandler
+}{
+	handlers: map[string]ConsensusTypeHandler{},
+}
+
+// RegisterConsensusType registers the handler of the metadata of a consensus
+// type. Registering a built-in or an already registered consensus type
+// returns an error.
+func RegisterConsensusType(consensusType string, handler ConsensusTypeHandler) error {
+	if consensusType == "" {
+		return errors.New("consensus type is required")
+	}
+
+	if handler == nil {
+		return fmt.Errorf("handler for consensus type %s is required", consensusType)
+	}
+
+	if builtinConsensusTypes[consensusType] {
+		return fmt.Errorf("consensus type %s is built in and cannot be registered", consensusType)
+	}
+
+	consensusTypeRegistry.Lock()
+	defer consensusTypeRegistry.Unlock()
+
+	if _, ok := consensusTypeRegistry.handlers[consensusType]; ok {
+		return fmt.Errorf("consensus type %s is already registered", consensusType)
+	}
+	consensusTypeRegistry.handlers[consensusType] = handler
+
+	return nil
+}
+
+// consensusTypeHandler returns the handler of a registered consensus type.
+func consensusTypeHandler(consensusType string) (ConsensusTypeHandler, bool) {
+	consensusTypeRegistry.RLock()
+	defer consensusTypeRegistry.RUnlock()
+
+	handler, ok := consensusTypeRegistry.handlers[consensusType]
+	return handler, ok
+}
@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+// testConsensusMetadata is the metadata of the consensus types registered by
+// the tests, marshaled as JSON by testConsensusHandler.
+type testConsensusMetadata struct {
+	Nodes []string `json:"nodes"`
+}
+
+type testConsensusHandler struct{}
+
+func (testConsensusHandler) MarshalMetadata(metadata interface{}) ([]byte, error) {
+	m, ok := metadata.(testConsensusMetadata)
+	if !ok {
+		return nil, fmt.Errorf("unexpected metadata type %T", metadata)
+	}
+
+	return json.Marshal(m)
+}
+
+func (testConsensusHandler) UnmarshalMetadata(metadata []byte) (interface{}, error) {
+	m := testConsensusMetadata{}
+	err := json.Unmarshal(metadata, &m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (testConsensusHandler) ValidateMetadata(metadata interface{}) error {
+	m, ok := metadata.(testConsensusMetadata)
+	if !ok {
+		return fmt.Errorf("unexpected metadata type %T", metadata)
+	}
+	if len(m.Nodes) == 0 {
+		return errors.New("nodes are required")
+	}
+
+	return nil
+}
+
+// unregisterConsensusType removes a consensus type registered by a test, so
+// that the global registry does not leak into other tests.
+func unregisterConsensusType(consensusType string) {
+	consensusTypeRegistry.Lock()
+	defer consensusTypeRegistry.Unlock()
+
+	delete(consensusTypeRegistry.handlers, consensusType)
+}
+
+func TestRegisterConsensusType(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	err := RegisterConsensusType("", testConsensusHandler{})
+	gt.Expect(err).To(MatchError("consensus type is required"))
+
+	err = RegisterConsensusType("registered", nil)
+	gt.Expect(err).To(MatchError("handler for consensus type registered is required"))
+
+	err = RegisterConsensusType(orderer.ConsensusTypeSmartBFT, testConsensusHandler{})
+	gt.Expect(err).To(MatchError("consensus type smartbft is built in and cannot be registered"))
+
+	err = RegisterConsensusType("registered", testConsensusHandler{})
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer unregisterConsensusType("registered")
+	err = RegisterConsensusType("registered", testConsensusHandler{})
+	gt.Expect(err).To(MatchError("consensus type registered is already registered"))
+
+	handler, ok := consensusTypeHandler("registered")
+	gt.Expect(ok).To(BeTrue())
+	gt.Expect(handler).To(Equal(testConsensusHandler{}))
+	_, ok = consensusTypeHandler("unregistered")
+	gt.Expect(ok).To(BeFalse())
+}
+
+func TestCustomConsensusType(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	err := RegisterConsensusType("testhandler", testConsensusHandler{})
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer unregisterConsensusType("testhandler")
+
+	customOrderer, _ := baseSoloOrderer(t)
+	customOrderer.OrdererType = "testhandler"
+	gt.Expect(customOrderer.Validate()).To(MatchError("invalid testhandler configuration: unexpected metadata type <nil>"))
+	customOrderer.CustomMetadata = testConsensusMetadata{}
+	gt.Expect(customOrderer.Validate()).To(MatchError("invalid testhandler configuration: nodes are required"))
+	customOrderer.CustomMetadata = testConsensusMetadata{Nodes: []string{"node-1.example.com:7050"}}
+	gt.Expect(customOrderer.Validate()).To(Succeed())
+
+	c := ordererConfigTx(t, customOrderer)
+	metadata, err := c.Orderer().ConsensusMetadata()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(metadata).To(MatchJSON(`{"nodes": ["node-1.example.com:7050"]}`))
+
+	cfg, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(cfg.OrdererType).To(Equal("testhandler"))
+	gt.Expect(cfg.CustomMetadata).To(Equal(customOrderer.CustomMetadata))
+
+	err = c.Orderer().Update(func(cfg *Orderer) error {
+		m := cfg.CustomMetadata.(testConsensusMetadata)
+		m.Nodes = append(m.Nodes, "node-2.example.com:7050")
+		cfg.CustomMetadata = m
+		return nil
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	cfg, err = c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(cfg.CustomMetadata).To(Equal(testConsensusMetadata{
+		Nodes: []string{"node-1.example.com:7050", "node-2.example.com:7050"},
+	}))
+
+	err = c.Orderer().SetConsensusMetadata([]byte("garbage"))
+	gt.Expect(err).To(MatchError(ContainSubstring("unmarshaling testhandler metadata: ")))
+
+	err = c.Orderer().Update(func(cfg *Orderer) error {
+		cfg.CustomMetadata = "garbage"
+		return nil
+	})
+	gt.Expect(err).To(MatchError("invalid testhandler configuration: unexpected metadata type string"))
+}
+
+func TestUnregisteredConsensusType(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	ordererGroup := channelGroup.Groups[OrdererGroupKey]
+	err = setValue(ordererGroup, consensusTypeValue("unregistered", []byte("metadata"), 0), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	cfg, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(cfg.CustomMetadata).To(BeNil())
+	gt.Expect(cfg.Validate()).To(MatchError("unknown orderer type 'unregistered'"))
+
+	metadata, err := c.Orderer().ConsensusMetadata()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(metadata).To(Equal([]byte("metadata")))
+}
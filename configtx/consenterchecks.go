@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"fmt"
+	"reflect"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// WithConsenterChecks returns a copy of the orderer group whose consenter
+// modifications check the consenters they add or change. The server TLS cert
+// of such a consenter must contain a SAN matching its host, and the identity
+// cert of a SmartBFT consenter must carry the orderer OU of its MSP if the MSP
+// enables NodeOUs. The checks apply to AddConsenter, AddSmartBFTConsenter,
+// SetEtcdRaftConsensusType and SetSmartBFTConsensusType, so certificate
+// rotations performed by setting the consensus metadata are checked as well.
+// Consenters which are left unchanged are not checked.
+func (o *OrdererGroup) WithConsenterChecks() *OrdererGroup {
+	checked := *o
+	checked.consenterChecks = true
+
+	return &checked
+}
+
+// checkEtcdRaftConsenters checks the etcdraft consenters which are not part of
+// the current consensus metadata if consenter checks are enabled.
+func (o *OrdererGroup) checkEtcdRaftConsenters(consenters []orderer.Consenter) error {
+	if !o.consenterChecks {
+		return nil
+	}
+
+	cfg, err := o.Configuration()
+	if err != nil {
+		return err
+	}
+
+	existing := map[string]orderer.Consenter{}
+	for _, consenter := range cfg.EtcdRaft.Consenters {
+		existing[consenterAddress(consenter.Address)] = consenter
+	}
+
+	for _, consenter := range consenters {
+		if reflect.DeepEqual(existing[consenterAddress(consenter.Address)], consenter) {
+			continue
+		}
+
+		err := checkServerTLSCertHost(consenter.Address, consenter.ServerTLSCert)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkSmartBFTConsenters checks the SmartBFT consenters which are not part of
+// the current consensus metadata if consenter checks are enabled.
+func (o *OrdererGroup) checkSmartBFTConsenters(consenters []orderer.SmartBFTConsenter) error {
+	if !o.consenterChecks {
+		return nil
+	}
+
+	cfg, err := o.Configuration()
+	if err != nil {
+		return err
+	}
+
+	existing := map[uint64]orderer.SmartBFTConsenter{}
+	for _, consenter := range cfg.SmartBFT.Consenters {
+		existing[consenter.ID] = consenter
+	}
+
+	for _, consenter := range consenters {
+		if reflect.DeepEqual(existing[consenter.ID], consenter) {
+			continue
+		}
+
+		err := checkServerTLSCertHost(consenter.Address, consenter.ServerTLSCert)
+		if err != nil {
+			return err
+		}
+
+		err = checkIdentityOrdererOU(cfg.Organizations, consenter)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// consenterAddress returns the address of a consenter in host:port form.
+func consenterAddress(address orderer.EtcdAddress) string {
+	return hostPort(address.Host, address.Port)
+}
+
+// normalizedConsenterAddress validates the address of a consenter and returns
+// it normalized, see Address.Normalized.
+func normalizedConsenterAddress(address orderer.EtcdAddress) (orderer.EtcdAddress, error) {
+	normalized := Address{Host: address.Host, Port: address.Port}.Normalized()
+	err := normalized.Validate()
+	if err != nil {
+		return orderer.EtcdAddress{}, fmt.Errorf("invalid consenter address: %v", err)
+	}
+
+	return orderer.EtcdAddress{Host: normalized.Host, Port: normalized.Port}, nil
+}
+
+// sameConsenter reports whether the consenters are equal once their
+// addresses are normalized, so that consenters stored before addresses were
+// normalized are matched too.
+func sameConsenter(a, b orderer.Consenter) bool {
+	a.Address = normalizedEtcdAddress(a.Address)
+	b.Address = normalizedEtcdAddress(b.Address)
+
+	return reflect.DeepEqual(a, b)
+}
+
+func normalizedEtcdAddress(address orderer.EtcdAddress) orderer.EtcdAddress {
+	normalized := Address{Host: address.Host, Port: address.Port}.Normalized()
+
+	return orderer.EtcdAddress{Host: normalized.Host, Port: normalized.Port}
+}
+
+// checkServerTLSCertHost checks that the server TLS cert of the consenter at
+// address contains a SAN matching the host.
+func checkServerTLSCertHost(address orderer.EtcdAddress, cert *x509.Certificate) error {
+	if cert == nil {
+		return fmt.Errorf("server tls cert for consenter %s is required", consenterAddress(address))
+	}
+
+	err := cert.VerifyHostname(address.Host)
+	if err != nil {
+		return fmt.Errorf("server tls cert of consenter %s does not match its host: %v", consenterAddress(address), err)
+	}
+
+	return nil
+}
+
+// checkIdentityOrdererOU checks that the identity cert of the SmartBFT
+// consenter carries the orderer OU of the MSP of the consenter if the MSP
+// enables NodeOUs. Consenters whose MSP is not an orderer org MSP are not
+// checked.
+func checkIdentityOrdererOU(orgs []Organization, consenter orderer.SmartBFTConsenter) error {
+	for _, org := range orgs {
+		if org.MSP.Name != consenter.MSPID {
+			continue
+		}
+
+		nodeOUs := org.MSP.NodeOUs
+		ou := nodeOUs.OrdererOUIdentifier.OrganizationalUnitIdentifier
+		if !nodeOUs.Enable || ou == "" {
+			return nil
+		}
+
+		if consenter.Identity == nil {
+			return fmt.Errorf("identity cert for consenter %d is required", consenter.ID)
+		}
+
+		if !containsString(consenter.Identity.Subject.OrganizationalUnit, ou) {
+			return fmt.Errorf("identity cert of consenter %d does not carry the orderer OU %s of MSP %s", consenter.ID, ou, consenter.MSPID)
+		}
+
+		return nil
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+// generateConsenterCert returns a cert for the given host carrying the given
+// OUs, signed by the CA.
+func generateConsenterCert(t *testing.T, host string, ous []string, caCert *x509.Certificate, caPrivKey *ecdsa.PrivateKey) *x509.Certificate {
+	cert, _ := generateCertAndPrivateKey(t, &x509.Certificate{
+		SerialNumber: generateSerialNumber(t),
+		Subject: pkix.Name{
+			CommonName:         host,
+			OrganizationalUnit: ous,
+		},
+		DNSNames:    []string{host},
+		NotBefore:   time.Now(),
+		NotAfter:    time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}, caCert, caPrivKey)
+
+	return cert
+}
+
+func TestEtcdRaftConsenterChecks(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	validCert := generateConsenterCert(t, "node-4.example.com", nil, caCert, caPrivKey)
+	otherHostCert := generateConsenterCert(t, "node-5.example.com", nil, caCert, caPrivKey)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	c := ordererConfigTx(t, etcdRaftOrderer)
+
+	err := c.Orderer().WithConsenterChecks().AddConsenter(orderer.Consenter{
+		Address:       orderer.EtcdAddress{Host: "node-4.example.com", Port: 7050},
+		ClientTLSCert: otherHostCert,
+		ServerTLSCert: otherHostCert,
+	})
+	gt.Expect(err).To(MatchError("server tls cert of consenter node-4.example.com:7050 does not match its host: x509: certificate is valid for node-5.example.com, not node-4.example.com"))
+
+	err = c.Orderer().WithConsenterChecks().AddConsenter(orderer.Consenter{
+		Address:       orderer.EtcdAddress{Host: "node-4.example.com", Port: 7050},
+		ClientTLSCert: validCert,
+		ServerTLSCert: validCert,
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Orderer().AddConsenter(orderer.Consenter{
+		Address:       orderer.EtcdAddress{Host: "node-6.example.com", Port: 7050},
+		ClientTLSCert: otherHostCert,
+		ServerTLSCert: otherHostCert,
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// The certs of the base consenters carry no SANs, but unchanged
+	// consenters are not checked when the consensus metadata is set.
+	cfg, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	cfg.EtcdRaft.Consenters[3].ServerTLSCert = otherHostCert
+	err = c.Orderer().WithConsenterChecks().SetEtcdRaftConsensusType(cfg.EtcdRaft, cfg.State)
+	gt.Expect(err).To(MatchError("server tls cert of consenter node-4.example.com:7050 does not match its host: x509: certificate is valid for node-5.example.com, not node-4.example.com"))
+
+	cfg.EtcdRaft.Consenters[3].ServerTLSCert = validCert
+	cfg.EtcdRaft.Consenters[3].ClientTLSCert = otherHostCert
+	err = c.Orderer().WithConsenterChecks().SetEtcdRaftConsensusType(cfg.EtcdRaft, cfg.State)
+	gt.Expect(err).NotTo(HaveOccurred())
+}
@@ -9,6 +9,7 @@ package configtx
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
@@ -21,9 +22,32 @@ type Consortium struct {
 	Organizations []Organization
 }
 
+// Validate checks that the consortium is named and that its organizations
+// are valid and unique.
+func (c Consortium) Validate() error {
+	if c.Name == "" {
+		return errors.New("consortium name is required")
+	}
+
+	names := map[string]struct{}{}
+	for _, org := range c.Organizations {
+		if err := org.Validate(); err != nil {
+			return fmt.Errorf("consortium %s: %v", c.Name, err)
+		}
+
+		if _, ok := names[org.Name]; ok {
+			return fmt.Errorf("duplicate org %s in consortium %s", org.Name, c.Name)
+		}
+		names[org.Name] = struct{}{}
+	}
+
+	return nil
+}
+
 // ConsortiumsGroup encapsulates the parts of the config that control consortiums.
 type ConsortiumsGroup struct {
 	consortiumsGroup *cb.ConfigGroup
+	observed         observed
 }
 
 // ConsortiumGroup encapsulates the parts of the config that control
@@ -32,6 +56,10 @@ type ConsortiumsGroup struct {
 type ConsortiumGroup struct {
 	consortiumGroup *cb.ConfigGroup
 	name            string
+	// skipSubPolicyChecks disables the check of the sub-policy of the
+	// channel creation policy, see WithoutSubPolicyChecks.
+	skipSubPolicyChecks bool
+	observed            observed
 }
 
 // ConsortiumOrg encapsulates the parts of the config that control a
@@ -39,19 +67,24 @@ type ConsortiumGroup struct {
 type ConsortiumOrg struct {
 	orgGroup *cb.ConfigGroup
 	name     string
+	observed observed
 }
 
 // MSP returns an OrganizationMSP object that can be used to configure the organization's MSP.
 func (c *ConsortiumOrg) MSP() *OrganizationMSP {
 	return &OrganizationMSP{
 		configGroup: c.orgGroup,
+		observed:    c.observed.child("Values/" + MSPKey),
 	}
 }
 
 // Consortiums returns the consortiums group from the updated config.
 func (c *ConfigTx) Consortiums() *ConsortiumsGroup {
 	consortiumsGroup := c.updated.ChannelGroup.Groups[ConsortiumsGroupKey]
-	return &ConsortiumsGroup{consortiumsGroup: consortiumsGroup}
+	return &ConsortiumsGroup{
+		consortiumsGroup: consortiumsGroup,
+		observed:         c.observed().child(ConsortiumsGroupKey),
+	}
 }
 
 // Consortium returns a consortium group from the updated config.
@@ -60,12 +93,18 @@ func (c *ConfigTx) Consortium(name string) *ConsortiumGroup {
 	if !ok {
 		return nil
 	}
-	return &ConsortiumGroup{name: name, consortiumGroup: consortiumGroup}
+	return &ConsortiumGroup{
+		name:            name,
+		consortiumGroup: consortiumGroup,
+		observed:        c.observed().child(ConsortiumsGroupKey).child(name),
+	}
 }
 
 // SetConsortium sets the consortium in a channel configuration.
 // If the consortium already exists in the current configuration, its value will be overwritten.
-func (c *ConsortiumsGroup) SetConsortium(consortium Consortium) error {
+func (c *ConsortiumsGroup) SetConsortium(consortium Consortium) (err error) {
+	defer c.observe("ConsortiumsGroup.SetConsortium", time.Now(), &err)
+
 	c.consortiumsGroup.Groups[consortium.Name] = newConfigGroup()
 
 	for _, org := range consortium.Organizations {
@@ -80,12 +119,14 @@ func (c *ConsortiumsGroup) SetConsortium(consortium Consortium) error {
 
 func (c *ConsortiumsGroup) consortium(name string) *ConsortiumGroup {
 	consortiumGroup := c.consortiumsGroup.Groups[name]
-	return &ConsortiumGroup{name: name, consortiumGroup: consortiumGroup}
+	return &ConsortiumGroup{name: name, consortiumGroup: consortiumGroup, observed: c.observed.child(name)}
 }
 
 // RemoveConsortium removes a consortium from a channel configuration.
 // Removal will panic if the consortiums group does not exist.
 func (c *ConsortiumsGroup) RemoveConsortium(name string) {
+	defer c.observe("ConsortiumsGroup.RemoveConsortium", time.Now(), nil)
+
 	delete(c.consortiumsGroup.Groups, name)
 }
 
@@ -95,14 +136,16 @@ func (c *ConsortiumGroup) Organization(name string) *ConsortiumOrg {
 	if !ok {
 		return nil
 	}
-	return &ConsortiumOrg{name: name, orgGroup: orgGroup}
+	return &ConsortiumOrg{name: name, orgGroup: orgGroup, observed: c.observed.child(name)}
 }
 
 // SetOrganization sets the organization config group for the given org key in
 // an existing Consortium configuration's Groups map.
 // If the consortium org already exists in the current configuration, its
 // value will be overwritten.
-func (c *ConsortiumGroup) SetOrganization(org Organization) error {
+func (c *ConsortiumGroup) SetOrganization(org Organization) (err error) {
+	defer c.observe("ConsortiumGroup.SetOrganization", time.Now(), &err)
+
 	orgGroup, err := newOrgConfigGroup(org)
 	if err != nil {
 		return fmt.Errorf("failed to create consortium org %s: %v", org.Name, err)
@@ -116,6 +159,8 @@ func (c *ConsortiumGroup) SetOrganization(org Organization) error {
 // RemoveOrganization removes an org from a consortium group.
 // Removal will panic if either the consortiums group or consortium group does not exist.
 func (c *ConsortiumGroup) RemoveOrganization(name string) {
+	defer c.observe("ConsortiumGroup.RemoveOrganization", time.Now(), nil)
+
 	delete(c.consortiumGroup.Groups, name)
 }
 
@@ -123,7 +168,7 @@ func (c *ConsortiumGroup) RemoveOrganization(name string) {
 // config. Consortiums are only defined for the ordering system channel.
 func (c *ConsortiumsGroup) Configuration() ([]Consortium, error) {
 	consortiums := []Consortium{}
-	for consortiumName := range c.consortiumsGroup.Groups {
+	for _, consortiumName := range sortedGroupKeys(c.consortiumsGroup) {
 		consortium, err := c.consortium(consortiumName).Configuration()
 		if err != nil {
 			return nil, err
@@ -137,10 +182,10 @@ func (c *ConsortiumsGroup) Configuration() ([]Consortium, error) {
 // Configuration returns the configuration for a consortium group.
 func (c *ConsortiumGroup) Configuration() (Consortium, error) {
 	orgs := []Organization{}
-	for orgName, orgGroup := range c.consortiumGroup.Groups {
-		org, err := getOrganization(orgGroup, orgName)
+	for _, orgName := range sortedGroupKeys(c.consortiumGroup) {
+		org, err := getOrganization(c.consortiumGroup.Groups[orgName], orgName)
 		if err != nil {
-			return Consortium{}, fmt.Errorf("failed to retrieve organization %s from consortium %s: ", orgName, c.name)
+			return Consortium{}, fmt.Errorf("failed to retrieve organization %s from consortium %s: %v", orgName, c.name, err)
 		}
 		orgs = append(orgs, org)
 	}
@@ -165,7 +210,9 @@ func (c *ConsortiumOrg) Configuration() (Organization, error) {
 }
 
 // SetMSP updates the MSP config for the specified consortium org group.
-func (c *ConsortiumOrg) SetMSP(updatedMSP MSP) error {
+func (c *ConsortiumOrg) SetMSP(updatedMSP MSP) (err error) {
+	defer c.observe("ConsortiumOrg.SetMSP", time.Now(), &err)
+
 	currentMSP, err := c.MSP().Configuration()
 	if err != nil {
 		return fmt.Errorf("retrieving msp: %v", err)
@@ -202,15 +249,37 @@ func (c *ConsortiumOrg) setMSPConfig(updatedMSP MSP) error {
 	return nil
 }
 
+// WithoutSubPolicyChecks returns a copy of the consortium group whose
+// SetChannelCreationPolicy method accepts a policy whose sub-policy is missing
+// in the consortium orgs.
+func (c *ConsortiumGroup) WithoutSubPolicyChecks() *ConsortiumGroup {
+	unchecked := *c
+	unchecked.skipSubPolicyChecks = true
+
+	return &unchecked
+}
+
 // SetChannelCreationPolicy sets the ConsortiumChannelCreationPolicy for
 // the given configuration Group.
 // If the policy already exists in current configuration, its value will be overwritten.
-func (c *ConsortiumGroup) SetChannelCreationPolicy(policy Policy) error {
+// The sub-policy of the ImplicitMeta rule must be defined by the consortium
+// orgs as described by ApplicationGroup.SetPolicy.
+func (c *ConsortiumGroup) SetChannelCreationPolicy(policy Policy) (err error) {
+	defer c.observe("ConsortiumGroup.SetChannelCreationPolicy", time.Now(), &err)
+
 	imp, err := implicitMetaFromString(policy.Rule)
 	if err != nil {
 		return fmt.Errorf("invalid implicit meta policy rule '%s': %v", policy.Rule, err)
 	}
 
+	if !c.skipSubPolicyChecks {
+		rule, _ := ParseImplicitMetaRule(policy.Rule)
+		err = checkSubPolicy(c.consortiumGroup, rule)
+		if err != nil {
+			return fmt.Errorf("invalid implicit meta policy rule '%s': %v", policy.Rule, err)
+		}
+	}
+
 	implicitMetaPolicy, err := implicitMetaPolicy(imp.SubPolicy, imp.Rule)
 	if err != nil {
 		return fmt.Errorf("failed to make implicit meta policy: %v", err)
@@ -230,7 +299,9 @@ func (c *ConsortiumOrg) Policies() (map[string]Policy, error) {
 }
 
 // SetModPolicy sets the specified modification policy for the consortium org group.
-func (c *ConsortiumOrg) SetModPolicy(modPolicy string) error {
+func (c *ConsortiumOrg) SetModPolicy(modPolicy string) (err error) {
+	defer c.observe("ConsortiumOrg.SetModPolicy", time.Now(), &err)
+
 	if modPolicy == "" {
 		return errors.New("non empty mod policy is required")
 	}
@@ -242,8 +313,24 @@ func (c *ConsortiumOrg) SetModPolicy(modPolicy string) error {
 
 // SetPolicy sets the specified policy in the consortium org group's config policy map.
 // If the policy already exists in current configuration, its value will be overwritten.
-func (c *ConsortiumOrg) SetPolicy(name string, policy Policy) error {
-	err := setPolicy(c.orgGroup, name, policy)
+func (c *ConsortiumOrg) SetPolicy(name string, policy Policy) (err error) {
+	defer c.observe("ConsortiumOrg.SetPolicy", time.Now(), &err)
+
+	err = setPolicy(c.orgGroup, name, policy)
+	if err != nil {
+		return fmt.Errorf("failed to set policy '%s' to consortium org '%s': %v", name, c.name, err)
+	}
+
+	return nil
+}
+
+// SetSignaturePolicy sets the signature policy of the envelope in the
+// consortium org group's config policy map, overwriting an existing policy of
+// that name. See ApplicationOrg.SetSignaturePolicy.
+func (c *ConsortiumOrg) SetSignaturePolicy(name string, envelope *cb.SignaturePolicyEnvelope, modPolicy string) (err error) {
+	defer c.observe("ConsortiumOrg.SetSignaturePolicy", time.Now(), &err)
+
+	err = setSignaturePolicyEnvelope(c.orgGroup, name, envelope, modPolicy)
 	if err != nil {
 		return fmt.Errorf("failed to set policy '%s' to consortium org '%s': %v", name, c.name, err)
 	}
@@ -253,8 +340,10 @@ func (c *ConsortiumOrg) SetPolicy(name string, policy Policy) error {
 
 // SetPolicies sets the specified policies in the consortium org group's config policy map.
 // If the policies already exist in current configuration, the values will be replaced with new policies.
-func (c *ConsortiumOrg) SetPolicies(policies map[string]Policy) error {
-	err := setPolicies(c.orgGroup, policies)
+func (c *ConsortiumOrg) SetPolicies(policies map[string]Policy) (err error) {
+	defer c.observe("ConsortiumOrg.SetPolicies", time.Now(), &err)
+
+	err = setPolicies(c.orgGroup, policies)
 	if err != nil {
 		return fmt.Errorf("failed to set policies to consortium org '%s': %v", c.name, err)
 	}
@@ -262,9 +351,26 @@ func (c *ConsortiumOrg) SetPolicies(policies map[string]Policy) error {
 	return nil
 }
 
+// SetDefaultPolicies sets the Readers, Writers, Admins and Endorsement
+// policies of the consortium org to the defaults of an application org, see
+// ApplicationOrg.SetDefaultPolicies, as the org becomes an application org of
+// the channels created by the consortium.
+func (c *ConsortiumOrg) SetDefaultPolicies(nodeOUsEnabled bool) (err error) {
+	defer c.observe("ConsortiumOrg.SetDefaultPolicies", time.Now(), &err)
+
+	err = setDefaultOrgPolicies(c.orgGroup, nodeOUsEnabled, applicationOrgPolicies)
+	if err != nil {
+		return fmt.Errorf("failed to set default policies to consortium org '%s': %v", c.name, err)
+	}
+
+	return nil
+}
+
 // RemovePolicy removes an existing policy from a consortium's organization.
 // Removal will panic if either the consortiums group, consortium group, or consortium org group does not exist.
 func (c *ConsortiumOrg) RemovePolicy(name string) {
+	defer c.observe("ConsortiumOrg.RemovePolicy", time.Now(), nil)
+
 	delete(c.orgGroup.Policies, name)
 }
 
@@ -2304,3 +2304,22 @@ func baseConsortiumChannelGroup(t *testing.T) (*cb.ConfigGroup, []*ecdsa.Private
 
 	return channelGroup, privKeys, nil
 }
+
+func TestConsortiumValidate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	consortiums, _ := baseConsortiums(t)
+	gt.Expect(consortiums[0].Validate()).To(Succeed())
+
+	consortium := consortiums[0]
+	consortium.Name = ""
+	gt.Expect(consortium.Validate()).To(MatchError("consortium name is required"))
+
+	consortium = consortiums[0]
+	consortium.Organizations = []Organization{consortium.Organizations[0], consortium.Organizations[0]}
+	gt.Expect(consortium.Validate()).To(MatchError("duplicate org Org1 in consortium Consortium1"))
+
+	consortium.Organizations = []Organization{{Name: "Org3"}}
+	gt.Expect(consortium.Validate()).To(MatchError("consortium Consortium1: org Org3: no policies defined"))
+}
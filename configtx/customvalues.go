@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator/protoext/customext"
+)
+
+// RegisterCustomValue registers the message type of the config values with
+// the given key which are not part of the standard Fabric config, such as
+// values containing a google.protobuf.Any or vendor-defined messages.
+// Registered values are read into the CustomValues of a Channel or an
+// Organization and are decoded by protolator. Values which are not registered
+// are read as the raw *cb.ConfigValue. Registering a standard or an already registered key
+// returns an error.
+func RegisterCustomValue(key string, newMsg func() proto.Message) error {
+	return customext.RegisterConfigValue(key, newMsg)
+}
+
+// UnregisterCustomValue removes the registration of the config values with
+// the given key, see RegisterCustomValue.
+func UnregisterCustomValue(key string) {
+	customext.UnregisterConfigValue(key)
+}
+
+// getCustomValues returns the custom values of a config group. Registered
+// values are decoded into their message type, other values which are not
+// part of the standard Fabric config are returned as the raw
+// *cb.ConfigValue, so that they are carried through when the group is built
+// again.
+func getCustomValues(group *cb.ConfigGroup) (map[string]proto.Message, error) {
+	var customValues map[string]proto.Message
+
+	for key, value := range group.Values {
+		if customext.IsBuiltinConfigValue(key) {
+			continue
+		}
+
+		msg, ok := customext.ConfigValue(key)
+		if ok {
+			err := proto.Unmarshal(value.Value, msg)
+			if err != nil {
+				return nil, fmt.Errorf("unmarshaling custom value %s: %v", key, err)
+			}
+		} else {
+			msg = &cb.ConfigValue{Value: value.Value, ModPolicy: value.ModPolicy}
+		}
+
+		if customValues == nil {
+			customValues = map[string]proto.Message{}
+		}
+		customValues[key] = msg
+	}
+
+	return customValues, nil
+}
+
+// setCustomValues sets the custom values of a config group. A raw
+// *cb.ConfigValue is set as is, other values are set with the mod policy
+// Admins.
+func setCustomValues(group *cb.ConfigGroup, customValues map[string]proto.Message) error {
+	for key, msg := range customValues {
+		if customext.IsBuiltinConfigValue(key) {
+			return fmt.Errorf("custom value %s is a standard config value", key)
+		}
+		if msg == nil {
+			return fmt.Errorf("custom value %s is nil", key)
+		}
+
+		if raw, ok := msg.(*cb.ConfigValue); ok {
+			if group.Values == nil {
+				group.Values = map[string]*cb.ConfigValue{}
+			}
+			group.Values[key] = &cb.ConfigValue{Value: raw.Value, ModPolicy: raw.ModPolicy}
+			continue
+		}
+
+		err := setValue(group, &standardConfigValue{key: key, value: msg}, AdminsPolicyKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
+	. "github.com/onsi/gomega"
+)
+
+func TestCustomValuesRoundTrip(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	err := RegisterCustomValue("OrgVendorSettings", func() proto.Message { return &any.Any{} })
+	gt.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() { UnregisterCustomValue("OrgVendorSettings") })
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	org, err := c.Application().Organization("Org1").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.CustomValues).To(BeNil())
+
+	vendorSettings, err := ptypes.MarshalAny(&cb.Capabilities{
+		Capabilities: map[string]*cb.Capability{"VendorFeature": {}},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	org.CustomValues = map[string]proto.Message{"OrgVendorSettings": vendorSettings}
+
+	err = c.Application().SetOrganization(org)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	orgGroup := c.updated.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"]
+	gt.Expect(orgGroup.Values["OrgVendorSettings"].ModPolicy).To(Equal(AdminsPolicyKey))
+
+	// Unregistered values are read as raw config values.
+	unregistered := &cb.ConfigValue{Value: []byte("opaque"), ModPolicy: WritersPolicyKey}
+	orgGroup.Values["OrgUnregistered"] = unregistered
+
+	org, err = c.Application().Organization("Org1").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.CustomValues).To(HaveLen(2))
+	gt.Expect(proto.Equal(org.CustomValues["OrgVendorSettings"], vendorSettings)).To(BeTrue())
+	gt.Expect(proto.Equal(org.CustomValues["OrgUnregistered"], unregistered)).To(BeTrue())
+
+	ordererOrgGroup, err := newOrdererOrgConfigGroup(org)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(ordererOrgGroup.Values["OrgUnregistered"], unregistered)).To(BeTrue())
+	ordererOrg, err := getOrganization(ordererOrgGroup, "Org1")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(ordererOrg.CustomValues["OrgVendorSettings"], vendorSettings)).To(BeTrue())
+	gt.Expect(proto.Equal(ordererOrg.CustomValues["OrgUnregistered"], unregistered)).To(BeTrue())
+}
+
+func TestCustomValuesFailures(t *testing.T) {
+	t.Parallel()
+
+	err := RegisterCustomValue("OrgMalformedSettings", func() proto.Message { return &any.Any{} })
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { UnregisterCustomValue("OrgMalformedSettings") })
+
+	tests := []struct {
+		testName    string
+		setup       func(c *ConfigTx) error
+		expectedErr string
+	}{
+		{
+			testName: "When registering a standard config value",
+			setup: func(c *ConfigTx) error {
+				return RegisterCustomValue(AnchorPeersKey, func() proto.Message { return &any.Any{} })
+			},
+			expectedErr: "config value AnchorPeers is decoded natively and cannot be registered",
+		},
+		{
+			testName: "When setting a standard config value as custom value",
+			setup: func(c *ConfigTx) error {
+				org, err := c.Application().Organization("Org1").Configuration()
+				if err != nil {
+					return err
+				}
+				org.CustomValues = map[string]proto.Message{MSPKey: &any.Any{}}
+				return c.Application().SetOrganization(org)
+			},
+			expectedErr: "failed to create application org Org1: custom value MSP is a standard config value",
+		},
+		{
+			testName: "When setting a nil custom value",
+			setup: func(c *ConfigTx) error {
+				org, err := c.Application().Organization("Org1").Configuration()
+				if err != nil {
+					return err
+				}
+				org.CustomValues = map[string]proto.Message{"OrgNilSettings": nil}
+				return c.Application().SetOrganization(org)
+			},
+			expectedErr: "failed to create application org Org1: custom value OrgNilSettings is nil",
+		},
+		{
+			testName: "When a registered custom value is malformed",
+			setup: func(c *ConfigTx) error {
+				orgGroup := c.updated.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"]
+				orgGroup.Values["OrgMalformedSettings"] = &cb.ConfigValue{Value: []byte("malformed")}
+				_, err := c.Application().Organization("Org1").Configuration()
+				return err
+			},
+			expectedErr: "unmarshaling custom value OrgMalformedSettings: ",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseApplicationChannelGroup(t)
+			gt.Expect(err).NotTo(HaveOccurred())
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			err = tt.setup(&c)
+			gt.Expect(err).To(HaveOccurred())
+			gt.Expect(err.Error()).To(HavePrefix(tt.expectedErr))
+		})
+	}
+}
@@ -0,0 +1,295 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+)
+
+// DriftKind is the kind of org property compared across channels.
+type DriftKind string
+
+const (
+	// DriftAnchorPeers is the anchor peers of an application org.
+	DriftAnchorPeers DriftKind = "anchor-peers"
+
+	// DriftOrdererEndpoints is the orderer endpoints of an orderer org.
+	DriftOrdererEndpoints DriftKind = "orderer-endpoints"
+
+	// DriftCACerts is a kind of CA certificates of the MSP of an org.
+	DriftCACerts DriftKind = "ca-certs"
+
+	// DriftPolicy is a policy of an org.
+	DriftPolicy DriftKind = "policy"
+)
+
+// OrgDrift is a property of an org which differs between channels.
+type OrgDrift struct {
+	// MSPID identifies the org, whose group may be named differently in
+	// each channel.
+	MSPID string    `json:"msp_id"`
+	Kind  DriftKind `json:"kind"`
+	// Name distinguishes the properties of a kind. It is the kind of CA
+	// certificates, e.g. "tls root", or the section and name of a policy,
+	// e.g. "Application/Endorsement".
+	Name string `json:"name,omitempty"`
+	// Values maps the ID of every channel the org has the property in to
+	// the value of the property in that channel. Anchor peers and orderer
+	// endpoints are sorted host:port pairs, CA certificates are sorted hex
+	// encoded SHA-256 fingerprints and policies are their type and rule. An
+	// empty value means the org lacks the property in that channel.
+	Values map[string]string `json:"values"`
+}
+
+// DriftReport is the drift between the orgs of a set of channels.
+type DriftReport []OrgDrift
+
+// JSON returns the report encoded as a JSON array.
+func (r DriftReport) JSON() ([]byte, error) {
+	drifts := r
+	if drifts == nil {
+		drifts = DriftReport{}
+	}
+
+	return json.MarshalIndent(drifts, "", "\t")
+}
+
+// driftKey identifies an org property compared across channels.
+type driftKey struct {
+	mspID string
+	kind  DriftKind
+	// section is the section of the org group of a policy.
+	section string
+	name    string
+}
+
+// driftCollector collects the org properties of a set of channels.
+type driftCollector struct {
+	values map[driftKey]map[string]string
+	// sections holds the channels each org is part of, keyed by the MSP ID
+	// and section, so that policies an org lacks are reported.
+	sections map[driftKey]map[string]bool
+}
+
+// ChannelDrift compares the orgs shared by the channel configs, keyed by
+// channel ID, and reports every anchor peer, orderer endpoint, CA
+// certificate and policy difference. Orgs are matched by MSP ID. A property
+// is only compared between the channels the org has it in, so the anchor
+// peers of an org are not compared with a channel it is only an orderer org
+// of. The CA certificates of an org are taken from the first of its
+// application, orderer and consortium groups in a channel.
+func ChannelDrift(configs map[string]*cb.Config) (DriftReport, error) {
+	collector := &driftCollector{
+		values:   map[driftKey]map[string]string{},
+		sections: map[driftKey]map[string]bool{},
+	}
+
+	channelIDs := make([]string, 0, len(configs))
+	for channelID := range configs {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+
+	for _, channelID := range channelIDs {
+		config := configs[channelID]
+		if config == nil || config.ChannelGroup == nil {
+			return nil, fmt.Errorf("config of channel %s is empty", channelID)
+		}
+
+		err := collector.collectChannel(channelID, config.ChannelGroup)
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: %v", channelID, err)
+		}
+	}
+
+	return collector.report(), nil
+}
+
+// collectChannel collects the properties of the orgs of the channel group.
+func (d *driftCollector) collectChannel(channelID string, channelGroup *cb.ConfigGroup) error {
+	if applicationGroup, ok := channelGroup.Groups[ApplicationGroupKey]; ok {
+		for _, orgName := range sortedGroupKeys(applicationGroup) {
+			orgGroup := applicationGroup.Groups[orgName]
+			if _, ok := orgGroup.Values[MSPKey]; !ok {
+				continue
+			}
+
+			org, err := getOrganization(orgGroup, orgName)
+			if err != nil {
+				return fmt.Errorf("retrieving application org %s: %v", orgName, err)
+			}
+
+			anchorPeers := make([]string, len(org.AnchorPeers))
+			for i, anchorPeer := range org.AnchorPeers {
+				anchorPeers[i] = hostPort(anchorPeer.Host, anchorPeer.Port)
+			}
+			d.set(driftKey{mspID: org.MSP.Name, kind: DriftAnchorPeers}, channelID, sortedList(anchorPeers))
+			d.collectOrg(channelID, ApplicationGroupKey, org)
+		}
+	}
+
+	if ordererGroup, ok := channelGroup.Groups[OrdererGroupKey]; ok {
+		for _, orgName := range sortedGroupKeys(ordererGroup) {
+			orgGroup := ordererGroup.Groups[orgName]
+			if _, ok := orgGroup.Values[MSPKey]; !ok {
+				continue
+			}
+
+			org, err := (&OrdererOrg{orgGroup: orgGroup, name: orgName}).Configuration()
+			if err != nil {
+				return fmt.Errorf("retrieving orderer org %s: %v", orgName, err)
+			}
+
+			endpoints := append([]string{}, org.OrdererEndpoints...)
+			d.set(driftKey{mspID: org.MSP.Name, kind: DriftOrdererEndpoints}, channelID, sortedList(endpoints))
+			d.collectOrg(channelID, OrdererGroupKey, org)
+		}
+	}
+
+	if consortiumsGroup, ok := channelGroup.Groups[ConsortiumsGroupKey]; ok {
+		for _, consortiumName := range sortedGroupKeys(consortiumsGroup) {
+			consortiumGroup := consortiumsGroup.Groups[consortiumName]
+			for _, orgName := range sortedGroupKeys(consortiumGroup) {
+				orgGroup := consortiumGroup.Groups[orgName]
+				if _, ok := orgGroup.Values[MSPKey]; !ok {
+					continue
+				}
+
+				org, err := getOrganization(orgGroup, orgName)
+				if err != nil {
+					return fmt.Errorf("retrieving org %s of consortium %s: %v", orgName, consortiumName, err)
+				}
+
+				d.collectOrg(channelID, ConsortiumsGroupKey+"/"+consortiumName, org)
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectOrg collects the CA certificates and policies of an org of the
+// section of the channel.
+func (d *driftCollector) collectOrg(channelID, section string, org Organization) {
+	mspID := org.MSP.Name
+
+	sectionKey := driftKey{mspID: mspID, section: section}
+	if d.sections[sectionKey] == nil {
+		d.sections[sectionKey] = map[string]bool{}
+	}
+	d.sections[sectionKey][channelID] = true
+
+	caCerts := []struct {
+		name  string
+		certs []*x509.Certificate
+	}{
+		{name: "root", certs: org.MSP.RootCerts},
+		{name: "intermediate", certs: org.MSP.IntermediateCerts},
+		{name: "tls root", certs: org.MSP.TLSRootCerts},
+		{name: "tls intermediate", certs: org.MSP.TLSIntermediateCerts},
+	}
+	for _, caCert := range caCerts {
+		key := driftKey{mspID: mspID, kind: DriftCACerts, name: caCert.name}
+		if _, ok := d.values[key][channelID]; ok {
+			continue
+		}
+
+		fingerprints := make([]string, len(caCert.certs))
+		for i, cert := range caCert.certs {
+			hash := sha256.Sum256(cert.Raw)
+			fingerprints[i] = hex.EncodeToString(hash[:])
+		}
+		d.set(key, channelID, sortedList(fingerprints))
+	}
+
+	for name, policy := range org.Policies {
+		key := driftKey{mspID: mspID, kind: DriftPolicy, section: section, name: name}
+		d.set(key, channelID, policy.Type+" "+policy.Rule)
+	}
+}
+
+// set records the value of the property in the channel.
+func (d *driftCollector) set(key driftKey, channelID, value string) {
+	if d.values[key] == nil {
+		d.values[key] = map[string]string{}
+	}
+	d.values[key][channelID] = value
+}
+
+// report returns the properties whose values differ between channels,
+// sorted by MSP ID, kind and name.
+func (d *driftCollector) report() DriftReport {
+	var report DriftReport
+
+	for key, values := range d.values {
+		name := key.name
+		if key.kind == DriftPolicy {
+			name = key.section + "/" + key.name
+
+			// a policy an org lacks in a channel is reported as empty
+			for channelID := range d.sections[driftKey{mspID: key.mspID, section: key.section}] {
+				if _, ok := values[channelID]; !ok {
+					values[channelID] = ""
+				}
+			}
+		}
+
+		if !drifted(values) {
+			continue
+		}
+
+		report = append(report, OrgDrift{
+			MSPID:  key.mspID,
+			Kind:   key.kind,
+			Name:   name,
+			Values: values,
+		})
+	}
+
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].MSPID != report[j].MSPID {
+			return report[i].MSPID < report[j].MSPID
+		}
+		if report[i].Kind != report[j].Kind {
+			return report[i].Kind < report[j].Kind
+		}
+		return report[i].Name < report[j].Name
+	})
+
+	return report
+}
+
+// drifted reports whether the values of a property differ between channels.
+func drifted(values map[string]string) bool {
+	var first *string
+	for _, value := range values {
+		value := value
+		if first == nil {
+			first = &value
+			continue
+		}
+		if value != *first {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortedList returns the items sorted and joined by commas.
+func sortedList(items []string) string {
+	sort.Strings(items)
+	return strings.Join(items, ", ")
+}
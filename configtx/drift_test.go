@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestChannelDrift(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	// Org2 shares the MSP ID of Org1
+	delete(channelGroup.Groups[ApplicationGroupKey].Groups, "Org2")
+	base := &cb.Config{ChannelGroup: channelGroup}
+
+	drifted := New(proto.Clone(base).(*cb.Config))
+	org1 := drifted.Application().Organization("Org1")
+	err = org1.AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = org1.SetPolicy("Custom", Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.admin')"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	newCert, _ := generateCACertAndPrivateKey(t, "ca-org1.example.com")
+	err = org1.MSP().AddTLSRootCert(newCert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	report, err := ChannelDrift(map[string]*cb.Config{
+		"channel1": proto.Clone(base).(*cb.Config),
+		"channel2": drifted.UpdatedConfig(),
+		"channel3": proto.Clone(base).(*cb.Config),
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	baseTx := New(base)
+	originalMSP, err := baseTx.Application().Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	originalTLSRootCerts := fingerprint(originalMSP.TLSRootCerts[0].Raw)
+	driftedTLSRootCerts := []string{originalTLSRootCerts, fingerprint(newCert.Raw)}
+	sort.Strings(driftedTLSRootCerts)
+	customPolicy, err := org1.Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(report).To(Equal(DriftReport{
+		{
+			MSPID: "MSPID",
+			Kind:  DriftAnchorPeers,
+			Values: map[string]string{
+				"channel1": "",
+				"channel2": "peer0.org1.example.com:7051",
+				"channel3": "",
+			},
+		},
+		{
+			MSPID: "MSPID",
+			Kind:  DriftCACerts,
+			Name:  "tls root",
+			Values: map[string]string{
+				"channel1": originalTLSRootCerts,
+				"channel2": strings.Join(driftedTLSRootCerts, ", "),
+				"channel3": originalTLSRootCerts,
+			},
+		},
+		{
+			MSPID: "MSPID",
+			Kind:  DriftPolicy,
+			Name:  "Application/Custom",
+			Values: map[string]string{
+				"channel1": "",
+				"channel2": SignaturePolicyType + " " + customPolicy["Custom"].Rule,
+				"channel3": "",
+			},
+		},
+	}))
+
+	reportJSON, err := report.JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	parsed := DriftReport{}
+	err = json.Unmarshal(reportJSON, &parsed)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(parsed).To(Equal(report))
+
+	report, err = ChannelDrift(map[string]*cb.Config{
+		"channel1": proto.Clone(base).(*cb.Config),
+		"channel3": proto.Clone(base).(*cb.Config),
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(report).To(BeEmpty())
+
+	reportJSON, err = report.JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(reportJSON).To(MatchJSON("[]"))
+}
+
+func TestChannelDriftOrdererEndpoints(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	base := &cb.Config{ChannelGroup: channelGroup}
+
+	drifted := New(proto.Clone(base).(*cb.Config))
+	err = drifted.Orderer().Organization("OrdererOrg").SetEndpoint(Address{Host: "orderer2.example.com", Port: 7050})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	report, err := ChannelDrift(map[string]*cb.Config{
+		"channel1": base,
+		"channel2": drifted.UpdatedConfig(),
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(report).To(Equal(DriftReport{
+		{
+			MSPID: "MSPID",
+			Kind:  DriftOrdererEndpoints,
+			Values: map[string]string{
+				"channel1": "localhost:123",
+				"channel2": "localhost:123, orderer2.example.com:7050",
+			},
+		},
+	}))
+}
+
+func TestChannelDriftFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	_, err := ChannelDrift(map[string]*cb.Config{"channel1": nil})
+	gt.Expect(err).To(MatchError("config of channel channel1 is empty"))
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Values[MSPKey].Value = []byte("garbage")
+	_, err = ChannelDrift(map[string]*cb.Config{"channel1": {ChannelGroup: channelGroup}})
+	gt.Expect(err).To(MatchError(ContainSubstring("channel channel1: retrieving application org Org1: ")))
+}
+
+// fingerprint returns the hex encoded SHA-256 hash of the bytes.
+func fingerprint(b []byte) string {
+	hash := sha256.Sum256(b)
+	return hex.EncodeToString(hash[:])
+}
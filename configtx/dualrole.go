@@ -0,0 +1,104 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SetDualRoleOrganization sets the organization as both an application org
+// and an orderer org in the updated config. Both copies share the MSP and
+// policies of the organization; the application org receives its anchor
+// peers and the orderer org its orderer endpoints. If the organization
+// already exists in either group, its value will be overwritten.
+func (c *ConfigTx) SetDualRoleOrganization(org Organization) (err error) {
+	defer c.observe("ConfigTx.SetDualRoleOrganization", time.Now(), &err)
+
+	application, orderer, err := c.dualRoleGroups()
+	if err != nil {
+		return err
+	}
+
+	applicationOrg := org
+	applicationOrg.OrdererEndpoints = nil
+	err = application.SetOrganization(applicationOrg)
+	if err != nil {
+		return err
+	}
+
+	ordererOrg := org
+	ordererOrg.AnchorPeers = nil
+	err = orderer.SetOrganization(ordererOrg)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetDualRoleOrganizationMSP updates the MSP of an organization which is both
+// an application org and an orderer org, keeping both copies in sync.
+func (c *ConfigTx) SetDualRoleOrganizationMSP(orgName string, updatedMSP MSP) (err error) {
+	defer c.observe("ConfigTx.SetDualRoleOrganizationMSP", time.Now(), &err)
+
+	application, orderer, err := c.dualRoleGroups()
+	if err != nil {
+		return err
+	}
+
+	applicationOrg := application.Organization(orgName)
+	if applicationOrg == nil {
+		return fmt.Errorf("application org %s does not exist", orgName)
+	}
+
+	ordererOrg := orderer.Organization(orgName)
+	if ordererOrg == nil {
+		return fmt.Errorf("orderer org %s does not exist", orgName)
+	}
+
+	err = applicationOrg.SetMSP(updatedMSP)
+	if err != nil {
+		return fmt.Errorf("updating application org %s msp: %v", orgName, err)
+	}
+
+	err = ordererOrg.SetMSP(updatedMSP)
+	if err != nil {
+		return fmt.Errorf("updating orderer org %s msp: %v", orgName, err)
+	}
+
+	return nil
+}
+
+// RemoveDualRoleOrganization removes the organization from both the
+// application and orderer groups.
+func (c *ConfigTx) RemoveDualRoleOrganization(orgName string) (err error) {
+	defer c.observe("ConfigTx.RemoveDualRoleOrganization", time.Now(), &err)
+
+	application, orderer, err := c.dualRoleGroups()
+	if err != nil {
+		return err
+	}
+
+	application.RemoveOrganization(orgName)
+	orderer.RemoveOrganization(orgName)
+
+	return nil
+}
+
+func (c *ConfigTx) dualRoleGroups() (*ApplicationGroup, *OrdererGroup, error) {
+	if _, ok := c.updated.ChannelGroup.Groups[ApplicationGroupKey]; !ok {
+		return nil, nil, errors.New("application group does not exist")
+	}
+
+	if _, ok := c.updated.ChannelGroup.Groups[OrdererGroupKey]; !ok {
+		return nil, nil, errors.New("orderer group does not exist")
+	}
+
+	return c.Application(), c.Orderer(), nil
+}
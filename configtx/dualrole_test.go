@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestDualRoleOrganization(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	genesisBlock, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	configEnvelope, _, err := unmarshalConfigBlock(genesisBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(configEnvelope.Config)
+
+	org3MSP, _ := baseMSP(t)
+	org3MSP.Name = "Org3MSP"
+	org3 := Organization{
+		Name:             "Org3",
+		Policies:         applicationOrgStandardPolicies(),
+		MSP:              org3MSP,
+		AnchorPeers:      []Address{{Host: "peer0.org3", Port: 7051}},
+		OrdererEndpoints: []string{"orderer.org3:7050"},
+	}
+
+	err = c.SetDualRoleOrganization(org3)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	applicationOrg, err := c.Application().Organization("Org3").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(applicationOrg.AnchorPeers).To(Equal(org3.AnchorPeers))
+	gt.Expect(applicationOrg.OrdererEndpoints).To(BeEmpty())
+
+	ordererOrg, err := c.Orderer().Organization("Org3").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererOrg.OrdererEndpoints).To(Equal(org3.OrdererEndpoints))
+	gt.Expect(ordererOrg.AnchorPeers).To(BeEmpty())
+	gt.Expect(ordererOrg.MSP).To(Equal(applicationOrg.MSP))
+
+	newAdmin := generateCert(t, "org3.example.com")
+	updatedMSP := applicationOrg.MSP
+	updatedMSP.Admins = append(updatedMSP.Admins, newAdmin)
+	err = c.SetDualRoleOrganizationMSP("Org3", updatedMSP)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	applicationMSP, err := c.Application().Organization("Org3").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	ordererMSP, err := c.Orderer().Organization("Org3").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(applicationMSP.Admins).To(ContainElement(newAdmin))
+	gt.Expect(ordererMSP).To(Equal(applicationMSP))
+
+	err = c.SetDualRoleOrganizationMSP("Org1", updatedMSP)
+	gt.Expect(err).To(MatchError("orderer org Org1 does not exist"))
+
+	err = c.SetDualRoleOrganizationMSP("Org4", updatedMSP)
+	gt.Expect(err).To(MatchError("application org Org4 does not exist"))
+
+	renamedMSP := updatedMSP
+	renamedMSP.Name = "OtherMSP"
+	err = c.SetDualRoleOrganizationMSP("Org3", renamedMSP)
+	gt.Expect(err).To(MatchError("updating application org Org3 msp: MSP name cannot be changed"))
+
+	err = c.RemoveDualRoleOrganization("Org3")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.Application().Organization("Org3")).To(BeNil())
+	gt.Expect(c.Orderer().Organization("Org3")).To(BeNil())
+}
+
+func TestDualRoleOrganizationFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	org := baseApplicationOrg(t)
+	err = c.SetDualRoleOrganization(org)
+	gt.Expect(err).To(MatchError("orderer group does not exist"))
+
+	err = c.RemoveDualRoleOrganization(org.Name)
+	gt.Expect(err).To(MatchError("orderer group does not exist"))
+
+	delete(channelGroup.Groups, ApplicationGroupKey)
+	c = New(&cb.Config{ChannelGroup: channelGroup})
+	err = c.SetDualRoleOrganizationMSP(org.Name, org.MSP)
+	gt.Expect(err).To(MatchError("application group does not exist"))
+}
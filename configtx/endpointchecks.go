@@ -0,0 +1,91 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"fmt"
+	"time"
+)
+
+// endpointTLSChecks holds the checks of the orderer endpoints added to an
+// orderer org.
+type endpointTLSChecks struct {
+	// certs are the supplied TLS certificates of endpoints, keyed by
+	// host:port.
+	certs map[string]*x509.Certificate
+	// probe enables dialing endpoints without supplied certificate.
+	probe        bool
+	probeTimeout time.Duration
+}
+
+// WithEndpointTLSCerts returns a copy of the orderer org whose SetEndpoint
+// checks that an endpoint it adds is served with a TLS certificate chaining
+// to the TLS CA certificates of the org MSP. The certificates are keyed by
+// the host:port of the normalized endpoint, see Address.Normalized.
+// Endpoints without certificate are not checked unless WithEndpointTLSProbe
+// is used as well. This catches endpoints added to the wrong org before
+// clients fail to verify the TLS certificate of the endpoint.
+func (o *OrdererOrg) WithEndpointTLSCerts(certs map[string]*x509.Certificate) *OrdererOrg {
+	checked := *o
+	checked.endpointChecks.certs = certs
+
+	return &checked
+}
+
+// WithEndpointTLSProbe returns a copy of the orderer org whose SetEndpoint
+// performs a TLS handshake with an endpoint it adds, within the timeout, and
+// checks that the certificate presented by the endpoint chains to the TLS CA
+// certificates of the org MSP. Endpoints whose certificate is supplied by
+// WithEndpointTLSCerts are not dialed. An endpoint which cannot be reached is
+// not added.
+func (o *OrdererOrg) WithEndpointTLSProbe(timeout time.Duration) *OrdererOrg {
+	checked := *o
+	checked.endpointChecks.probe = true
+	checked.endpointChecks.probeTimeout = timeout
+
+	return &checked
+}
+
+// checkEndpointTLS checks the TLS certificate of the normalized endpoint if
+// it is supplied or endpoint probing is enabled.
+func (o *OrdererOrg) checkEndpointTLS(endpoint Address) error {
+	address := hostPort(endpoint.Host, endpoint.Port)
+	cert, supplied := o.endpointChecks.certs[address]
+	if !supplied && !o.endpointChecks.probe {
+		return nil
+	}
+
+	msp, err := o.MSP().Configuration()
+	if err != nil {
+		return fmt.Errorf("checking endpoint %s of orderer org %s: %v", address, o.name, err)
+	}
+
+	if len(msp.TLSRootCerts) == 0 {
+		return fmt.Errorf("checking endpoint %s of orderer org %s: MSP %s has no tls root certs", address, o.name, msp.Name)
+	}
+
+	if !supplied {
+		status := CheckEndpoint(address, msp.TLSRootCerts, msp.TLSIntermediateCerts, o.endpointChecks.probeTimeout)
+		if status.State != EndpointReachable {
+			return fmt.Errorf("probing endpoint %s of orderer org %s (%s): %v", address, o.name, status.State, status.Err)
+		}
+
+		return nil
+	}
+
+	if cert == nil {
+		return fmt.Errorf("tls cert for endpoint %s of orderer org %s is required", address, o.name)
+	}
+
+	err = verifyChain(msp.TLSRootCerts, msp.TLSIntermediateCerts, false)(cert)
+	if err != nil {
+		return fmt.Errorf("tls cert of endpoint %s does not chain to the tls CAs of orderer org %s: %v", address, o.name, err)
+	}
+
+	return nil
+}
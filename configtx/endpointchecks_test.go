@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestEndpointTLSChecks(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, privKeys, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	ordererOrg := c.Orderer().Organization("OrdererOrg")
+
+	msp, err := ordererOrg.MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	serverCert, serverPrivKey := generateTLSServerCert(t, msp.TLSRootCerts[0], privKeys[0])
+	otherCACert, otherCAPrivKey := generateCACertAndPrivateKey(t, "other-org")
+	otherServerCert, otherServerPrivKey := generateTLSServerCert(t, otherCACert, otherCAPrivKey)
+
+	address := startTLSServer(t, serverCert, serverPrivKey)
+	otherAddress := startTLSServer(t, otherServerCert, otherServerPrivKey)
+	closedAddress := closedEndpoint(t)
+
+	tests := []struct {
+		testName    string
+		org         *OrdererOrg
+		address     string
+		expectedErr string
+	}{
+		{
+			testName: "when the supplied cert chains to the org TLS CAs",
+			org:      ordererOrg.WithEndpointTLSCerts(map[string]*x509.Certificate{"orderer1.example.com:7050": serverCert}),
+			address:  "orderer1.example.com:7050",
+		},
+		{
+			testName:    "when the supplied cert does not chain to the org TLS CAs",
+			org:         ordererOrg.WithEndpointTLSCerts(map[string]*x509.Certificate{"orderer2.example.com:7050": otherServerCert}),
+			address:     "orderer2.example.com:7050",
+			expectedErr: "tls cert of endpoint orderer2.example.com:7050 does not chain to the tls CAs of orderer org OrdererOrg: x509: certificate signed by unknown authority",
+		},
+		{
+			testName:    "when the supplied cert is nil",
+			org:         ordererOrg.WithEndpointTLSCerts(map[string]*x509.Certificate{"orderer2.example.com:7050": nil}),
+			address:     "orderer2.example.com:7050",
+			expectedErr: "tls cert for endpoint orderer2.example.com:7050 of orderer org OrdererOrg is required",
+		},
+		{
+			testName: "when no cert is supplied for the endpoint",
+			org:      ordererOrg.WithEndpointTLSCerts(map[string]*x509.Certificate{"orderer1.example.com:7050": otherServerCert}),
+			address:  "orderer3.example.com:7050",
+		},
+		{
+			testName: "when the probed endpoint presents a cert chaining to the org TLS CAs",
+			org:      ordererOrg.WithEndpointTLSProbe(time.Second),
+			address:  address,
+		},
+		{
+			testName:    "when the probed endpoint presents a cert of another org",
+			org:         ordererOrg.WithEndpointTLSProbe(time.Second),
+			address:     otherAddress,
+			expectedErr: "probing endpoint " + otherAddress + " of orderer org OrdererOrg (tls-mismatch): x509: certificate signed by unknown authority",
+		},
+		{
+			testName:    "when the probed endpoint is unreachable",
+			org:         ordererOrg.WithEndpointTLSProbe(time.Second),
+			address:     closedAddress,
+			expectedErr: "probing endpoint " + closedAddress + " of orderer org OrdererOrg (unreachable)",
+		},
+		{
+			testName: "when the supplied cert takes precedence over probing",
+			org:      ordererOrg.WithEndpointTLSProbe(time.Second).WithEndpointTLSCerts(map[string]*x509.Certificate{closedAddress: serverCert}),
+			address:  closedAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		host, portStr, err := net.SplitHostPort(tt.address)
+		gt.Expect(err).NotTo(HaveOccurred())
+		port, err := strconv.Atoi(portStr)
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		err = tt.org.SetEndpoint(Address{Host: host, Port: port})
+		if tt.expectedErr != "" {
+			gt.Expect(err).To(MatchError(ContainSubstring(tt.expectedErr)), tt.testName)
+			continue
+		}
+		gt.Expect(err).NotTo(HaveOccurred(), tt.testName)
+	}
+
+	cfg, err := ordererOrg.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(cfg.OrdererEndpoints).To(Equal([]string{
+		"localhost:123",
+		"orderer1.example.com:7050",
+		"orderer3.example.com:7050",
+		address,
+		closedAddress,
+	}))
+}
+
+func TestEndpointTLSChecksWithoutTLSRootCerts(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	ordererOrg := c.Orderer().Organization("OrdererOrg")
+
+	msp, err := ordererOrg.MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	msp.TLSRootCerts = nil
+	msp.TLSIntermediateCerts = nil
+	err = ordererOrg.SetMSP(msp)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = ordererOrg.WithEndpointTLSProbe(time.Second).SetEndpoint(Address{Host: "orderer1.example.com", Port: 7050})
+	gt.Expect(err).To(MatchError("checking endpoint orderer1.example.com:7050 of orderer org OrdererOrg: MSP MSPID has no tls root certs"))
+}
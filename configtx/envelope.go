@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// IdentitySummary summarizes a serialized identity.
+type IdentitySummary struct {
+	MSPID string
+	// Subject is the subject of the certificate of the identity. It is empty
+	// if the identity is not an X.509 certificate.
+	Subject string
+}
+
+// EnvelopeInfo describes an envelope based on its payload headers.
+type EnvelopeInfo struct {
+	HeaderType cb.HeaderType
+	ChannelID  string
+	TxID       string
+	// Timestamp is the time at which the envelope was created.
+	Timestamp time.Time
+	// Creator is the identity which created the envelope. It is empty for
+	// envelopes without creator such as genesis blocks.
+	Creator IdentitySummary
+}
+
+// Classify returns the header type, channel ID, transaction ID, timestamp and
+// creator of an envelope.
+func Classify(env *cb.Envelope) (EnvelopeInfo, error) {
+	_, channelHeader, signatureHeader, err := unwrapEnvelope(env)
+	if err != nil {
+		return EnvelopeInfo{}, err
+	}
+
+	info := EnvelopeInfo{
+		HeaderType: cb.HeaderType(channelHeader.Type),
+		ChannelID:  channelHeader.ChannelId,
+		TxID:       channelHeader.TxId,
+	}
+
+	if channelHeader.Timestamp != nil {
+		info.Timestamp, err = ptypes.Timestamp(channelHeader.Timestamp)
+		if err != nil {
+			return EnvelopeInfo{}, fmt.Errorf("invalid timestamp: %v", err)
+		}
+	}
+
+	info.Creator, err = summarizeIdentity(signatureHeader.Creator)
+	if err != nil {
+		return EnvelopeInfo{}, err
+	}
+
+	return info, nil
+}
+
+// unwrapEnvelope returns the payload of the envelope along with its channel
+// and signature headers.
+func unwrapEnvelope(env *cb.Envelope) (*cb.Payload, *cb.ChannelHeader, *cb.SignatureHeader, error) {
+	if env == nil {
+		return nil, nil, nil, errors.New("envelope is empty")
+	}
+
+	payload := &cb.Payload{}
+	err := proto.Unmarshal(env.Payload, payload)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unmarshaling payload: %v", err)
+	}
+	if payload.Header == nil {
+		return nil, nil, nil, errors.New("payload header is missing")
+	}
+
+	channelHeader := &cb.ChannelHeader{}
+	err = proto.Unmarshal(payload.Header.ChannelHeader, channelHeader)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unmarshaling channel header: %v", err)
+	}
+
+	signatureHeader := &cb.SignatureHeader{}
+	err = proto.Unmarshal(payload.Header.SignatureHeader, signatureHeader)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("unmarshaling signature header: %v", err)
+	}
+
+	return payload, channelHeader, signatureHeader, nil
+}
+
+// summarizeIdentity returns the summary of a marshaled serialized identity.
+func summarizeIdentity(serializedIdentity []byte) (IdentitySummary, error) {
+	identity := &mb.SerializedIdentity{}
+	err := proto.Unmarshal(serializedIdentity, identity)
+	if err != nil {
+		return IdentitySummary{}, fmt.Errorf("unmarshaling serialized identity: %v", err)
+	}
+
+	summary := IdentitySummary{MSPID: identity.Mspid}
+	if block, _ := pem.Decode(identity.IdBytes); block != nil && block.Type == "CERTIFICATE" {
+		cert, err := parseCertificateFromBytes(identity.IdBytes)
+		if err == nil {
+			summary.Subject = cert.Subject.String()
+		}
+	}
+
+	return summary, nil
+}
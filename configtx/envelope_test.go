@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	channel.Consortium = "SampleConsortium"
+	marshaledUpdate, err := NewMarshaledCreateChannelTx(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	env, err := NewEnvelope(marshaledUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signingMSP, signingKey := baseMSP(t)
+	signingIdentity := &SigningIdentity{
+		Certificate: signingMSP.Admins[0],
+		PrivateKey:  signingKey,
+		MSPID:       "MSPID",
+	}
+	err = signingIdentity.SignEnvelope(env)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	info, err := Classify(env)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(info.HeaderType).To(Equal(cb.HeaderType_CONFIG_UPDATE))
+	gt.Expect(info.ChannelID).To(Equal("testchannel"))
+	gt.Expect(info.Timestamp).NotTo(BeZero())
+	gt.Expect(info.Creator).To(Equal(IdentitySummary{
+		MSPID:   "MSPID",
+		Subject: signingMSP.Admins[0].Subject.String(),
+	}))
+
+	genesisBlock, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	genesisEnv := &cb.Envelope{}
+	err = proto.Unmarshal(genesisBlock.Data.Data[0], genesisEnv)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	info, err = Classify(genesisEnv)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(info.HeaderType).To(Equal(cb.HeaderType_CONFIG))
+	gt.Expect(info.ChannelID).To(Equal("testchannel"))
+	gt.Expect(info.TxID).To(HaveLen(64))
+	gt.Expect(info.Creator).To(Equal(IdentitySummary{}))
+}
+
+func TestClassifyFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		env         *cb.Envelope
+		expectedErr string
+	}{
+		{
+			testName:    "when the envelope is nil",
+			env:         nil,
+			expectedErr: "envelope is empty",
+		},
+		{
+			testName:    "when the payload is invalid",
+			env:         &cb.Envelope{Payload: []byte("bad-payload")},
+			expectedErr: "unmarshaling payload: unexpected EOF",
+		},
+		{
+			testName:    "when the payload header is missing",
+			env:         &cb.Envelope{},
+			expectedErr: "payload header is missing",
+		},
+		{
+			testName: "when the creator is invalid",
+			env: func() *cb.Envelope {
+				signatureHeader, _ := proto.Marshal(&cb.SignatureHeader{Creator: []byte("bad-creator")})
+				payload, _ := proto.Marshal(&cb.Payload{Header: &cb.Header{SignatureHeader: signatureHeader}})
+				return &cb.Envelope{Payload: payload}
+			}(),
+			expectedErr: "unmarshaling serialized identity: unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := Classify(tt.env)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
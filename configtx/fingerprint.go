@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/protolator"
+)
+
+// Fingerprint returns the hex encoded SHA-256 hash of the updated config with
+// the config sequence and all group, value and policy versions removed. Two
+// channels which are configured identically have the same fingerprint, no
+// matter how many config updates led to their current configuration.
+func (c *ConfigTx) Fingerprint() (string, error) {
+	return configFingerprint(c.updated)
+}
+
+// configFingerprint returns the fingerprint of the config, see Fingerprint.
+// The config is hashed in its protolator JSON form rather than as marshaled
+// protobuf, because the values and policies hold marshaled messages whose map
+// fields, such as capabilities and ACLs, have no canonical encoding.
+func configFingerprint(config *cb.Config) (string, error) {
+	normalized := &cb.Config{
+		ChannelGroup: CopyConfigGroup(config.ChannelGroup, ResetVersions),
+	}
+
+	hash := sha256.New()
+	err := protolator.DeepMarshalJSON(hash, normalized)
+	if err != nil {
+		return "", fmt.Errorf("decoding normalized config: %v", err)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestFingerprint(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeEtcdRaft)
+	gt.Expect(err).NotTo(HaveOccurred())
+	config := &cb.Config{ChannelGroup: channelGroup}
+
+	c := New(config)
+	fingerprint, err := c.Fingerprint()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(fingerprint).To(HaveLen(64))
+
+	fingerprintOf := func(mutate func(*cb.Config)) string {
+		bumped := proto.Clone(config).(*cb.Config)
+		mutate(bumped)
+		c := New(bumped)
+		fingerprint, err := c.Fingerprint()
+		gt.Expect(err).NotTo(HaveOccurred())
+		return fingerprint
+	}
+
+	versionsBumped := fingerprintOf(func(config *cb.Config) {
+		config.Sequence = 7
+		config.ChannelGroup.Version = 3
+		ordererGroup := config.ChannelGroup.Groups[OrdererGroupKey]
+		ordererGroup.Version = 2
+		ordererGroup.Values[orderer.BatchTimeoutKey].Version = 4
+		ordererGroup.Policies[AdminsPolicyKey].Version = 1
+	})
+	gt.Expect(versionsBumped).To(Equal(fingerprint))
+
+	c = New(proto.Clone(config).(*cb.Config))
+	err = c.Orderer().SetBatchTimeout(time.Minute)
+	gt.Expect(err).NotTo(HaveOccurred())
+	modified, err := c.Fingerprint()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(modified).NotTo(Equal(fingerprint))
+
+	modPolicyChanged := fingerprintOf(func(config *cb.Config) {
+		config.ChannelGroup.Groups[OrdererGroupKey].ModPolicy = ReadersPolicyKey
+	})
+	gt.Expect(modPolicyChanged).NotTo(Equal(fingerprint))
+}
+
+func TestFingerprintMapOrder(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeEtcdRaft)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// Concatenated map entries decode to the same map whatever their order,
+	// so these are two encodings of the same capabilities.
+	var forward, backward []byte
+	for i := 0; i < 8; i++ {
+		entry, err := proto.Marshal(&cb.Capabilities{
+			Capabilities: map[string]*cb.Capability{
+				fmt.Sprintf("V%d", i): {},
+			},
+		})
+		gt.Expect(err).NotTo(HaveOccurred())
+		forward = append(forward, entry...)
+		backward = append(append([]byte{}, entry...), backward...)
+	}
+	gt.Expect(forward).NotTo(Equal(backward))
+
+	fingerprintOf := func(capabilities []byte) string {
+		config := &cb.Config{ChannelGroup: proto.Clone(channelGroup).(*cb.ConfigGroup)}
+		config.ChannelGroup.Groups[OrdererGroupKey].Values[CapabilitiesKey] = &cb.ConfigValue{
+			Value:     capabilities,
+			ModPolicy: AdminsPolicyKey,
+		}
+		c := New(config)
+		fingerprint, err := c.Fingerprint()
+		gt.Expect(err).NotTo(HaveOccurred())
+		return fingerprint
+	}
+
+	gt.Expect(fingerprintOf(forward)).To(Equal(fingerprintOf(backward)))
+}
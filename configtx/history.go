@@ -0,0 +1,235 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+)
+
+// ConfigChangeKind describes how a config element changed between two configs.
+type ConfigChangeKind string
+
+const (
+	// ConfigChangeAdded indicates that the element was added.
+	ConfigChangeAdded ConfigChangeKind = "added"
+
+	// ConfigChangeRemoved indicates that the element was removed.
+	ConfigChangeRemoved ConfigChangeKind = "removed"
+
+	// ConfigChangeModified indicates that the content or mod policy of the
+	// element changed.
+	ConfigChangeModified ConfigChangeKind = "modified"
+)
+
+// ConfigChange is a change of a single config group, value or policy.
+type ConfigChange struct {
+	// Path identifies the changed element, e.g. /Channel/Orderer for the
+	// orderer group, /Channel/Orderer/Values/BatchTimeout for one of its
+	// values or /Channel/Orderer/Policies/Admins for one of its policies.
+	Path string
+	Kind ConfigChangeKind
+}
+
+// ConfigHistoryEntry describes the config committed by a config block.
+type ConfigHistoryEntry struct {
+	BlockNumber uint64
+	Sequence    uint64
+	// Timestamp is the time at which the config transaction was created.
+	Timestamp time.Time
+	// Signers are the signers of the config update which led to this config.
+	Signers []IdentitySummary
+	// Changes are the changes from the config of the previous entry. The
+	// first entry serves as the baseline and has no changes.
+	Changes []ConfigChange
+}
+
+// ConfigHistory builds the timeline of config changes of a channel from its
+// config blocks. The blocks must be given in the order they were committed.
+func ConfigHistory(blocks []*cb.Block) ([]ConfigHistoryEntry, error) {
+	var history []ConfigHistoryEntry
+	var previous *cb.Config
+
+	for i, block := range blocks {
+		configEnvelope, channelHeader, err := unmarshalConfigBlock(block)
+		if err != nil {
+			return nil, fmt.Errorf("config block at index %d: %v", i, err)
+		}
+
+		config := configEnvelope.Config
+		entry := ConfigHistoryEntry{
+			BlockNumber: block.Header.Number,
+			Sequence:    config.Sequence,
+		}
+
+		if channelHeader.Timestamp != nil {
+			entry.Timestamp, err = ptypes.Timestamp(channelHeader.Timestamp)
+			if err != nil {
+				return nil, fmt.Errorf("config block %d: invalid timestamp: %v", block.Header.Number, err)
+			}
+		}
+
+		entry.Signers, err = configUpdateSigners(configEnvelope.LastUpdate)
+		if err != nil {
+			return nil, fmt.Errorf("config block %d: %v", block.Header.Number, err)
+		}
+
+		if previous != nil {
+			if config.Sequence <= previous.Sequence {
+				return nil, fmt.Errorf("config block %d: config sequence %d does not follow sequence %d", block.Header.Number, config.Sequence, previous.Sequence)
+			}
+			entry.Changes = diffConfigGroup("/"+ChannelGroupKey, previous.ChannelGroup, config.ChannelGroup)
+		}
+
+		history = append(history, entry)
+		previous = config
+	}
+
+	return history, nil
+}
+
+// unmarshalConfigBlock returns the config envelope and channel header of the
+// config transaction in the block.
+func unmarshalConfigBlock(block *cb.Block) (*cb.ConfigEnvelope, *cb.ChannelHeader, error) {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return nil, nil, errors.New("block is empty")
+	}
+	if len(block.Data.Data) != 1 {
+		return nil, nil, fmt.Errorf("block %d contains %d transactions, config blocks contain exactly one", block.Header.Number, len(block.Data.Data))
+	}
+
+	envelope := &cb.Envelope{}
+	err := proto.Unmarshal(block.Data.Data[0], envelope)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshaling envelope: %v", err)
+	}
+
+	payload, channelHeader, _, err := unwrapEnvelope(envelope)
+	if err != nil {
+		return nil, nil, err
+	}
+	if channelHeader.Type != int32(cb.HeaderType_CONFIG) {
+		return nil, nil, fmt.Errorf("block %d is not a config block, transaction type is %s", block.Header.Number, cb.HeaderType(channelHeader.Type))
+	}
+
+	configEnvelope := &cb.ConfigEnvelope{}
+	err = proto.Unmarshal(payload.Data, configEnvelope)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unmarshaling config envelope: %v", err)
+	}
+	if configEnvelope.Config == nil {
+		return nil, nil, errors.New("config is missing")
+	}
+
+	return configEnvelope, channelHeader, nil
+}
+
+// configUpdateSigners returns the signers of the config update envelope
+// carried by lastUpdate. A genesis block has no last update and no signers.
+func configUpdateSigners(lastUpdate *cb.Envelope) ([]IdentitySummary, error) {
+	if lastUpdate == nil {
+		return nil, nil
+	}
+
+	payload, _, _, err := unwrapEnvelope(lastUpdate)
+	if err != nil {
+		return nil, fmt.Errorf("last update: %v", err)
+	}
+
+	configUpdateEnvelope := &cb.ConfigUpdateEnvelope{}
+	err = proto.Unmarshal(payload.Data, configUpdateEnvelope)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config update envelope: %v", err)
+	}
+
+	var signers []IdentitySummary
+	for _, signature := range configUpdateEnvelope.Signatures {
+		signatureHeader := &cb.SignatureHeader{}
+		err = proto.Unmarshal(signature.SignatureHeader, signatureHeader)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling signature header: %v", err)
+		}
+
+		signer, err := summarizeIdentity(signatureHeader.Creator)
+		if err != nil {
+			return nil, fmt.Errorf("signature creator: %v", err)
+		}
+		signers = append(signers, signer)
+	}
+
+	return signers, nil
+}
+
+// diffConfigGroup returns the changes between the original and updated config
+// group at path, sorted by path.
+func diffConfigGroup(path string, original, updated *cb.ConfigGroup) []ConfigChange {
+	var changes []ConfigChange
+
+	if original.ModPolicy != updated.ModPolicy {
+		changes = append(changes, ConfigChange{Path: path, Kind: ConfigChangeModified})
+	}
+
+	for key, originalGroup := range original.Groups {
+		groupPath := path + "/" + key
+		updatedGroup, ok := updated.Groups[key]
+		if !ok {
+			changes = append(changes, ConfigChange{Path: groupPath, Kind: ConfigChangeRemoved})
+			continue
+		}
+		changes = append(changes, diffConfigGroup(groupPath, originalGroup, updatedGroup)...)
+	}
+	for key := range updated.Groups {
+		if _, ok := original.Groups[key]; !ok {
+			changes = append(changes, ConfigChange{Path: path + "/" + key, Kind: ConfigChangeAdded})
+		}
+	}
+
+	for key, originalValue := range original.Values {
+		valuePath := path + "/Values/" + key
+		updatedValue, ok := updated.Values[key]
+		switch {
+		case !ok:
+			changes = append(changes, ConfigChange{Path: valuePath, Kind: ConfigChangeRemoved})
+		case !bytes.Equal(originalValue.Value, updatedValue.Value) || originalValue.ModPolicy != updatedValue.ModPolicy:
+			changes = append(changes, ConfigChange{Path: valuePath, Kind: ConfigChangeModified})
+		}
+	}
+	for key := range updated.Values {
+		if _, ok := original.Values[key]; !ok {
+			changes = append(changes, ConfigChange{Path: path + "/Values/" + key, Kind: ConfigChangeAdded})
+		}
+	}
+
+	for key, originalPolicy := range original.Policies {
+		policyPath := path + "/Policies/" + key
+		updatedPolicy, ok := updated.Policies[key]
+		switch {
+		case !ok:
+			changes = append(changes, ConfigChange{Path: policyPath, Kind: ConfigChangeRemoved})
+		case !proto.Equal(originalPolicy.Policy, updatedPolicy.Policy) || originalPolicy.ModPolicy != updatedPolicy.ModPolicy:
+			changes = append(changes, ConfigChange{Path: policyPath, Kind: ConfigChangeModified})
+		}
+	}
+	for key := range updated.Policies {
+		if _, ok := original.Policies[key]; !ok {
+			changes = append(changes, ConfigChange{Path: path + "/Policies/" + key, Kind: ConfigChangeAdded})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes
+}
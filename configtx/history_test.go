@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestConfigHistory(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	genesisBlock, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	configEnvelope, _, err := unmarshalConfigBlock(genesisBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(configEnvelope.Config)
+	err = c.Orderer().SetBatchTimeout(time.Minute)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c.Application().RemoveOrganization("Org1")
+	err = c.Channel().RemoveCapability("V2_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	signingMSP, signingKey := baseMSP(t)
+	signingIdentity := &SigningIdentity{
+		Certificate: signingMSP.Admins[0],
+		PrivateKey:  signingKey,
+		MSPID:       "MSPID",
+	}
+	signature, err := signingIdentity.CreateConfigSignature(marshaledUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+	lastUpdate, err := NewEnvelope(marshaledUpdate, signature)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	updatedConfig := c.UpdatedConfig()
+	updatedConfig.Sequence = 1
+	configBlock := newTestConfigBlock(t, genesisBlock, updatedConfig, lastUpdate)
+
+	history, err := ConfigHistory([]*cb.Block{genesisBlock, configBlock})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(history).To(HaveLen(2))
+
+	gt.Expect(history[0].BlockNumber).To(Equal(uint64(0)))
+	gt.Expect(history[0].Signers).To(BeEmpty())
+	gt.Expect(history[0].Changes).To(BeEmpty())
+	gt.Expect(history[0].Timestamp).NotTo(BeZero())
+
+	gt.Expect(history[1].BlockNumber).To(Equal(uint64(1)))
+	gt.Expect(history[1].Sequence).To(Equal(uint64(1)))
+	gt.Expect(history[1].Signers).To(Equal([]IdentitySummary{
+		{
+			MSPID:   "MSPID",
+			Subject: signingMSP.Admins[0].Subject.String(),
+		},
+	}))
+	gt.Expect(history[1].Changes).To(Equal([]ConfigChange{
+		{Path: "/Channel/Application/Org1", Kind: ConfigChangeRemoved},
+		{Path: "/Channel/Orderer/Values/BatchTimeout", Kind: ConfigChangeModified},
+		{Path: "/Channel/Values/Capabilities", Kind: ConfigChangeModified},
+	}))
+}
+
+func TestConfigHistoryFailures(t *testing.T) {
+	t.Parallel()
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	genesisBlock, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	tests := []struct {
+		testName    string
+		blocks      func() []*cb.Block
+		expectedErr string
+	}{
+		{
+			testName: "when a block is empty",
+			blocks: func() []*cb.Block {
+				return []*cb.Block{{}}
+			},
+			expectedErr: "config block at index 0: block is empty",
+		},
+		{
+			testName: "when a block is not a config block",
+			blocks: func() []*cb.Block {
+				block := proto.Clone(genesisBlock).(*cb.Block)
+				envelope := &cb.Envelope{}
+				_ = proto.Unmarshal(block.Data.Data[0], envelope)
+				payload := &cb.Payload{}
+				_ = proto.Unmarshal(envelope.Payload, payload)
+				payload.Header.ChannelHeader, _ = proto.Marshal(channelHeader(cb.HeaderType_ENDORSER_TRANSACTION, msgVersion, "testchannel", epoch))
+				envelope.Payload, _ = proto.Marshal(payload)
+				block.Data.Data[0], _ = proto.Marshal(envelope)
+				return []*cb.Block{block}
+			},
+			expectedErr: "config block at index 0: block 0 is not a config block, transaction type is ENDORSER_TRANSACTION",
+		},
+		{
+			testName: "when the config sequence does not increase",
+			blocks: func() []*cb.Block {
+				return []*cb.Block{genesisBlock, genesisBlock}
+			},
+			expectedErr: "config block 0: config sequence 0 does not follow sequence 0",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := ConfigHistory(tt.blocks())
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
+
+func TestDiffConfigGroup(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	updated := proto.Clone(channelGroup).(*cb.ConfigGroup)
+	updated.Version = 1
+	updated.Groups[OrdererGroupKey].ModPolicy = ReadersPolicyKey
+	delete(updated.Groups[OrdererGroupKey].Policies, ReadersPolicyKey)
+	updated.Groups[OrdererGroupKey].Values["Custom"] = &cb.ConfigValue{Value: []byte("custom")}
+	updated.Groups["Custom"] = newConfigGroup()
+
+	changes := diffConfigGroup("/Channel", channelGroup, updated)
+	gt.Expect(changes).To(Equal([]ConfigChange{
+		{Path: "/Channel/Custom", Kind: ConfigChangeAdded},
+		{Path: "/Channel/Orderer", Kind: ConfigChangeModified},
+		{Path: "/Channel/Orderer/Policies/Readers", Kind: ConfigChangeRemoved},
+		{Path: "/Channel/Orderer/Values/Custom", Kind: ConfigChangeAdded},
+	}))
+
+	gt.Expect(diffConfigGroup("/Channel", channelGroup, channelGroup)).To(BeEmpty())
+}
+
+// newTestConfigBlock returns a config block following the previous block
+// which commits the config.
+func newTestConfigBlock(t *testing.T, previous *cb.Block, config *cb.Config, lastUpdate *cb.Envelope) *cb.Block {
+	gt := NewGomegaWithT(t)
+
+	configEnvelope, err := proto.Marshal(&cb.ConfigEnvelope{Config: config, LastUpdate: lastUpdate})
+	gt.Expect(err).NotTo(HaveOccurred())
+	header, err := payloadHeader(channelHeader(cb.HeaderType_CONFIG, msgVersion, "testchannel", epoch), &cb.SignatureHeader{})
+	gt.Expect(err).NotTo(HaveOccurred())
+	payload, err := proto.Marshal(&cb.Payload{Header: header, Data: configEnvelope})
+	gt.Expect(err).NotTo(HaveOccurred())
+	envelope, err := proto.Marshal(&cb.Envelope{Payload: payload})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	previousHash, err := blockHeaderHash(previous.Header)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	block := newBlock(previous.Header.Number+1, previousHash)
+	block.Data = &cb.BlockData{Data: [][]byte{envelope}}
+	block.Header.DataHash = blockDataHash(block.Data)
+
+	return block
+}
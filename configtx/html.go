@@ -0,0 +1,252 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/protolator"
+)
+
+// htmlGroup is the view of a config group rendered by RenderHTML.
+type htmlGroup struct {
+	Name      string
+	Path      string
+	Open      bool
+	Version   uint64
+	ModPolicy string
+	Values    []htmlValue
+	Policies  []htmlPolicy
+	Groups    []htmlGroup
+}
+
+type htmlValue struct {
+	Name         string
+	Version      uint64
+	ModPolicy    string
+	JSON         string
+	Certificates []htmlCertificate
+}
+
+type htmlPolicy struct {
+	Name      string
+	Type      string
+	Rule      string
+	ModPolicy string
+}
+
+type htmlCertificate struct {
+	Subject      string
+	Issuer       string
+	SerialNumber string
+	SANs         string
+	NotBefore    string
+	NotAfter     string
+}
+
+var htmlTemplate = template.Must(template.New("config").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Channel config</title>
+<style>
+body { font-family: sans-serif; }
+details { margin-left: 1em; }
+summary { cursor: pointer; }
+table { border-collapse: collapse; margin: 0.5em 0; }
+th, td { border: 1px solid #ccc; padding: 0.2em 0.5em; text-align: left; vertical-align: top; }
+pre { background: #f6f6f6; padding: 0.5em; overflow: auto; }
+</style>
+</head>
+<body>
+<h1>Channel config (sequence {{.Sequence}})</h1>
+{{template "group" .ChannelGroup}}
+</body>
+</html>
+{{define "group"}}<details id="{{.Path}}"{{if .Open}} open{{end}}>
+<summary><strong>{{.Name}}</strong> (version {{.Version}}, mod policy {{.ModPolicy}})</summary>
+{{if .Policies}}<table>
+<tr><th>Policy</th><th>Type</th><th>Rule</th><th>Mod policy</th></tr>
+{{range .Policies}}<tr><td>{{.Name}}</td><td>{{.Type}}</td><td><code>{{.Rule}}</code></td><td>{{.ModPolicy}}</td></tr>
+{{end}}</table>
+{{end}}{{range .Values}}<details>
+<summary>{{.Name}} (version {{.Version}}, mod policy {{.ModPolicy}})</summary>
+{{if .Certificates}}<table>
+<tr><th>Subject</th><th>Issuer</th><th>Serial number</th><th>SANs</th><th>Not before</th><th>Not after</th></tr>
+{{range .Certificates}}<tr><td>{{.Subject}}</td><td>{{.Issuer}}</td><td>{{.SerialNumber}}</td><td>{{.SANs}}</td><td>{{.NotBefore}}</td><td>{{.NotAfter}}</td></tr>
+{{end}}</table>
+{{end}}<pre>{{.JSON}}</pre>
+</details>
+{{end}}{{range .Groups}}{{template "group" .}}{{end}}</details>
+{{end}}`))
+
+// RenderHTML writes the updated config as a static HTML page. Every config
+// group is a collapsible section listing its policies with their rules, its
+// deeply decoded values and the details of the certificates found in them,
+// followed by its sub-groups.
+func (c *ConfigTx) RenderHTML(w io.Writer) error {
+	if w == nil {
+		return errors.New("writer is required")
+	}
+
+	var decoded bytes.Buffer
+	err := protolator.DeepMarshalJSON(&decoded, c.updated)
+	if err != nil {
+		return fmt.Errorf("decoding config: %v", err)
+	}
+
+	tree := map[string]interface{}{}
+	err = json.Unmarshal(decoded.Bytes(), &tree)
+	if err != nil {
+		return fmt.Errorf("unmarshaling decoded config: %v", err)
+	}
+
+	channelTree, _ := tree["channel_group"].(map[string]interface{})
+	channelGroup, err := newHTMLGroup(ChannelGroupKey, ChannelGroupKey, c.updated.ChannelGroup, channelTree)
+	if err != nil {
+		return err
+	}
+	channelGroup.Open = true
+
+	return htmlTemplate.Execute(w, struct {
+		Sequence     uint64
+		ChannelGroup htmlGroup
+	}{
+		Sequence:     c.updated.Sequence,
+		ChannelGroup: channelGroup,
+	})
+}
+
+// newHTMLGroup returns the view of a config group. The decoded values of the
+// group are taken from its tree in the deeply decoded config.
+func newHTMLGroup(name, path string, group *cb.ConfigGroup, tree map[string]interface{}) (htmlGroup, error) {
+	g := htmlGroup{
+		Name:      name,
+		Path:      path,
+		Version:   group.Version,
+		ModPolicy: group.ModPolicy,
+	}
+
+	policies, err := getPolicies(group.Policies)
+	if err != nil {
+		return htmlGroup{}, fmt.Errorf("rendering policies of %s: %v", path, err)
+	}
+	policyNames := make([]string, 0, len(policies))
+	for policyName := range policies {
+		policyNames = append(policyNames, policyName)
+	}
+	sort.Strings(policyNames)
+	for _, policyName := range policyNames {
+		policy := policies[policyName]
+		g.Policies = append(g.Policies, htmlPolicy{
+			Name:      policyName,
+			Type:      policy.Type,
+			Rule:      policy.Rule,
+			ModPolicy: policy.ModPolicy,
+		})
+	}
+
+	valueTrees, _ := tree["values"].(map[string]interface{})
+	valueNames := make([]string, 0, len(group.Values))
+	for valueName := range group.Values {
+		valueNames = append(valueNames, valueName)
+	}
+	sort.Strings(valueNames)
+	for _, valueName := range valueNames {
+		valueTree, _ := valueTrees[valueName].(map[string]interface{})
+		valueJSON, err := json.MarshalIndent(valueTree["value"], "", "  ")
+		if err != nil {
+			return htmlGroup{}, fmt.Errorf("rendering value %s of %s: %v", valueName, path, err)
+		}
+
+		g.Values = append(g.Values, htmlValue{
+			Name:         valueName,
+			Version:      group.Values[valueName].Version,
+			ModPolicy:    group.Values[valueName].ModPolicy,
+			JSON:         string(valueJSON),
+			Certificates: htmlCertificates(valueTree["value"]),
+		})
+	}
+
+	groupTrees, _ := tree["groups"].(map[string]interface{})
+	for _, groupName := range sortedGroupKeys(group) {
+		groupTree, _ := groupTrees[groupName].(map[string]interface{})
+		subGroup, err := newHTMLGroup(groupName, path+"/"+groupName, group.Groups[groupName], groupTree)
+		if err != nil {
+			return htmlGroup{}, err
+		}
+		g.Groups = append(g.Groups, subGroup)
+	}
+
+	return g, nil
+}
+
+// htmlCertificates returns the details of the PEM encoded certificates found
+// in a decoded value.
+func htmlCertificates(tree interface{}) []htmlCertificate {
+	var certs []htmlCertificate
+
+	switch t := tree.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for key := range t {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			certs = append(certs, htmlCertificates(t[key])...)
+		}
+	case []interface{}:
+		for _, v := range t {
+			certs = append(certs, htmlCertificates(v)...)
+		}
+	case string:
+		// certificates annotated by protolator are not base64 encoded
+		pemBytes, err := base64.StdEncoding.DecodeString(t)
+		if err != nil {
+			pemBytes = []byte(t)
+		}
+		block, _ := pem.Decode(pemBytes)
+		if block == nil || block.Type != "CERTIFICATE" {
+			return nil
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil
+		}
+
+		var sans []string
+		sans = append(sans, cert.DNSNames...)
+		sans = append(sans, cert.EmailAddresses...)
+		for _, ip := range cert.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+
+		certs = append(certs, htmlCertificate{
+			Subject:      cert.Subject.String(),
+			Issuer:       cert.Issuer.String(),
+			SerialNumber: cert.SerialNumber.String(),
+			SANs:         strings.Join(sans, ", "),
+			NotBefore:    cert.NotBefore.UTC().Format(time.RFC3339),
+			NotAfter:     cert.NotAfter.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return certs
+}
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"html/template"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestRenderHTML(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSmartBFT)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	msp, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	policies, err := c.Orderer().Organization("OrdererOrg").Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var buffer bytes.Buffer
+	err = c.RenderHTML(&buffer)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	html := buffer.String()
+	gt.Expect(html).To(HavePrefix("<!DOCTYPE html>"))
+	gt.Expect(html).To(ContainSubstring(`<details id="Channel" open>`))
+	gt.Expect(html).To(ContainSubstring(`<details id="Channel/Orderer">`))
+	gt.Expect(html).To(ContainSubstring(`<details id="Channel/Orderer/OrdererOrg">`))
+	gt.Expect(html).To(ContainSubstring("<td>BlockValidation</td>"))
+	gt.Expect(html).To(ContainSubstring("<code>" + template.HTMLEscapeString(policies[AdminsPolicyKey].Rule) + "</code>"))
+	gt.Expect(html).To(ContainSubstring("<summary>ConsensusType (version 0, mod policy Admins)</summary>"))
+	gt.Expect(html).To(ContainSubstring(template.HTMLEscapeString(`"type": "smartbft"`)))
+	gt.Expect(html).To(ContainSubstring("<td>" + template.HTMLEscapeString(msp.RootCerts[0].Subject.String()) + "</td>"))
+	gt.Expect(html).To(ContainSubstring("<td>" + ordererConfig.SmartBFT.Consenters[0].ClientTLSCert.SerialNumber.String() + "</td>"))
+}
+
+func TestRenderHTMLFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSmartBFT)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	err = c.RenderHTML(nil)
+	gt.Expect(err).To(MatchError("writer is required"))
+
+	channelGroup.Groups[OrdererGroupKey].Policies[AdminsPolicyKey].Policy.Type = 15
+	c = New(&cb.Config{ChannelGroup: channelGroup})
+	err = c.RenderHTML(&bytes.Buffer{})
+	gt.Expect(err).To(MatchError(HavePrefix("decoding config: ")))
+	gt.Expect(err).To(MatchError(ContainSubstring("unable to decode policy type: 15")))
+}
@@ -0,0 +1,155 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ResolvedIdentity describes how the MSP of an org of the channel would
+// treat an identity whose certificate chains to one of its root certs.
+type ResolvedIdentity struct {
+	Org   string
+	MSPID string
+	// Path identifies the MSP within the config, e.g.
+	// /Channel/Application/Org1/Values/MSP.
+	Path string
+	// Roles are the roles the MSP grants the identity, in the form used by
+	// PolicySigner: member for every identity, admin for identities listed
+	// as admins or carrying the admin OU and client, peer or orderer for
+	// identities carrying the respective NodeOU.
+	Roles []string
+	// Problems lists the reasons for which the MSP would reject the
+	// identity despite its certificate chaining to the MSP, such as a
+	// revoked or expired certificate. An identity with problems cannot
+	// satisfy any principal of the MSP.
+	Problems []string
+}
+
+// ResolveIdentity returns how the MSPs of the application, orderer and
+// consortium orgs of the updated config to whose root certs the certificate
+// chains would treat the identity of the certificate, which helps diagnosing
+// authorization failures. An org appearing in several groups is reported
+// once per group. The result is empty if the certificate chains to none of
+// the MSPs.
+func (c *ConfigTx) ResolveIdentity(cert *x509.Certificate) ([]ResolvedIdentity, error) {
+	if cert == nil {
+		return nil, errors.New("certificate is required")
+	}
+
+	var resolved []ResolvedIdentity
+	now := time.Now()
+
+	err := walkOrgMSPs(c.updated.ChannelGroup, func(orgName, path string, msp *MSP) bool {
+		if verifyChain(msp.RootCerts, msp.IntermediateCerts, true)(cert) != nil {
+			return false
+		}
+
+		resolved = append(resolved, ResolvedIdentity{
+			Org:      orgName,
+			MSPID:    msp.Name,
+			Path:     path,
+			Roles:    identityRoles(msp, cert),
+			Problems: identityProblems(msp, cert, now),
+		})
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resolved, nil
+}
+
+// identityRoles returns the roles the MSP grants the identity of the
+// certificate.
+func identityRoles(msp *MSP, cert *x509.Certificate) []string {
+	ous := cert.Subject.OrganizationalUnit
+	nodeOUs := msp.NodeOUs
+	hasNodeOU := func(ou string) bool {
+		return nodeOUs.Enable && ou != "" && containsString(ous, ou)
+	}
+
+	roles := []string{"member"}
+
+	admin := hasNodeOU(nodeOUs.AdminOUIdentifier.OrganizationalUnitIdentifier)
+	for _, adminCert := range msp.Admins {
+		if adminCert.Equal(cert) {
+			admin = true
+		}
+	}
+	if admin {
+		roles = append(roles, "admin")
+	}
+
+	if hasNodeOU(nodeOUs.ClientOUIdentifier.OrganizationalUnitIdentifier) {
+		roles = append(roles, "client")
+	}
+	if hasNodeOU(nodeOUs.PeerOUIdentifier.OrganizationalUnitIdentifier) {
+		roles = append(roles, "peer")
+	}
+	if hasNodeOU(nodeOUs.OrdererOUIdentifier.OrganizationalUnitIdentifier) {
+		roles = append(roles, "orderer")
+	}
+
+	return roles
+}
+
+// identityProblems returns the reasons for which the MSP rejects the identity
+// of the certificate at the given time.
+func identityProblems(msp *MSP, cert *x509.Certificate, now time.Time) []string {
+	var problems []string
+
+	if now.After(cert.NotAfter) {
+		problems = append(problems, fmt.Sprintf("certificate expired at %s", cert.NotAfter.UTC().Format(time.RFC3339)))
+	}
+
+	for _, crl := range msp.RevocationList {
+		if crl.TBSCertList.Issuer.String() != cert.Issuer.ToRDNSequence().String() {
+			continue
+		}
+		for _, revoked := range crl.TBSCertList.RevokedCertificates {
+			if revoked.SerialNumber.Cmp(cert.SerialNumber) == 0 {
+				problems = append(problems, fmt.Sprintf("certificate is revoked by a CRL of %s", crl.TBSCertList.Issuer))
+			}
+		}
+	}
+
+	if len(msp.OrganizationalUnitIdentifiers) > 0 {
+		var ouIDs []string
+		var found bool
+		for _, ouID := range msp.OrganizationalUnitIdentifiers {
+			ouIDs = append(ouIDs, ouID.OrganizationalUnitIdentifier)
+			found = found || containsString(cert.Subject.OrganizationalUnit, ouID.OrganizationalUnitIdentifier)
+		}
+		if !found {
+			problems = append(problems, fmt.Sprintf("certificate carries none of the organizational units %s", strings.Join(ouIDs, ", ")))
+		}
+	}
+
+	nodeOUs := msp.NodeOUs
+	if nodeOUs.Enable {
+		var found bool
+		for _, ou := range []string{
+			nodeOUs.ClientOUIdentifier.OrganizationalUnitIdentifier,
+			nodeOUs.PeerOUIdentifier.OrganizationalUnitIdentifier,
+			nodeOUs.AdminOUIdentifier.OrganizationalUnitIdentifier,
+			nodeOUs.OrdererOUIdentifier.OrganizationalUnitIdentifier,
+		} {
+			found = found || ou != "" && containsString(cert.Subject.OrganizationalUnit, ou)
+		}
+		if !found {
+			problems = append(problems, "certificate carries none of the node OUs of the MSP")
+		}
+	}
+
+	return problems
+}
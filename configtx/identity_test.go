@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/membership"
+	. "github.com/onsi/gomega"
+)
+
+func TestResolveIdentity(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	msp, caPrivKey := baseMSP(t)
+	caCert := msp.RootCerts[0]
+	msp.OrganizationalUnitIdentifiers = nil
+	msp.NodeOUs = membership.NodeOUs{
+		Enable:              true,
+		ClientOUIdentifier:  membership.OUIdentifier{Certificate: caCert, OrganizationalUnitIdentifier: "client"},
+		PeerOUIdentifier:    membership.OUIdentifier{Certificate: caCert, OrganizationalUnitIdentifier: "peer"},
+		AdminOUIdentifier:   membership.OUIdentifier{Certificate: caCert, OrganizationalUnitIdentifier: "admin"},
+		OrdererOUIdentifier: membership.OUIdentifier{Certificate: caCert, OrganizationalUnitIdentifier: "orderer"},
+	}
+
+	peerCert := generateConsenterCert(t, "peer0.org1.example.com", []string{"peer"}, caCert, caPrivKey)
+	adminCert := generateConsenterCert(t, "admin.org1.example.com", []string{"client"}, caCert, caPrivKey)
+	revokedCert := generateConsenterCert(t, "user.org1.example.com", []string{"client"}, caCert, caPrivKey)
+	noOUCert := generateConsenterCert(t, "user.org1.example.com", nil, caCert, caPrivKey)
+	otherCACert, otherCAPrivKey := generateCACertAndPrivateKey(t, "org2.example.com")
+	otherCert := generateConsenterCert(t, "peer0.org2.example.com", []string{"peer"}, otherCACert, otherCAPrivKey)
+
+	msp.Admins = []*x509.Certificate{adminCert}
+	crlBytes, err := caCert.CreateCRL(rand.Reader, caPrivKey, []pkix.RevokedCertificate{
+		{SerialNumber: revokedCert.SerialNumber, RevocationTime: time.Now()},
+	}, time.Now(), time.Now().Add(YEAR))
+	gt.Expect(err).NotTo(HaveOccurred())
+	crl, err := x509.ParseCRL(crlBytes)
+	gt.Expect(err).NotTo(HaveOccurred())
+	msp.RevocationList = append(msp.RevocationList, crl)
+
+	orgGroup, err := newApplicationOrgConfigGroup(Organization{
+		Name:     "Org1",
+		Policies: standardPolicies(),
+		MSP:      msp,
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup := newConfigGroup()
+	channelGroup.Groups[ApplicationGroupKey] = newConfigGroup()
+	channelGroup.Groups[ApplicationGroupKey].Groups["Org1"] = orgGroup
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	tests := []struct {
+		testName         string
+		cert             *x509.Certificate
+		expectedRoles    []string
+		expectedProblems []string
+	}{
+		{
+			testName:      "When the identity carries a node OU",
+			cert:          peerCert,
+			expectedRoles: []string{"member", "peer"},
+		},
+		{
+			testName:      "When the identity is an admin of the MSP",
+			cert:          adminCert,
+			expectedRoles: []string{"member", "admin", "client"},
+		},
+		{
+			testName:         "When the certificate is revoked",
+			cert:             revokedCert,
+			expectedRoles:    []string{"member", "client"},
+			expectedProblems: []string{"certificate is revoked by a CRL of " + crl.TBSCertList.Issuer.String()},
+		},
+		{
+			testName:         "When the identity carries no node OU",
+			cert:             noOUCert,
+			expectedRoles:    []string{"member"},
+			expectedProblems: []string{"certificate carries none of the node OUs of the MSP"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			resolved, err := c.ResolveIdentity(tt.cert)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(resolved).To(Equal([]ResolvedIdentity{
+				{
+					Org:      "Org1",
+					MSPID:    "MSPID",
+					Path:     "/Channel/Application/Org1/Values/MSP",
+					Roles:    tt.expectedRoles,
+					Problems: tt.expectedProblems,
+				},
+			}))
+		})
+	}
+
+	resolved, err := c.ResolveIdentity(otherCert)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(resolved).To(BeEmpty())
+
+	_, err = c.ResolveIdentity(nil)
+	gt.Expect(err).To(MatchError("certificate is required"))
+}
@@ -0,0 +1,232 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// OrdererNodeRole is the role of an ordering node in the consensus protocol.
+type OrdererNodeRole string
+
+const (
+	// OrdererNodeRoleConsenter is the role of a node taking part in consensus.
+	OrdererNodeRoleConsenter OrdererNodeRole = "consenter"
+
+	// OrdererNodeRoleEndpoint is the role of an orderer endpoint advertised
+	// to clients by an orderer organization.
+	OrdererNodeRoleEndpoint OrdererNodeRole = "endpoint"
+)
+
+// OrdererNode is an entry of the ordering node inventory of a channel.
+type OrdererNode struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// Org is the name of the orderer organization owning the node. It is
+	// empty if the owning organization cannot be determined.
+	Org           string          `json:"org"`
+	ConsensusType string          `json:"consensus_type"`
+	Role          OrdererNodeRole `json:"role"`
+	// ConsenterID is the identifier of a SmartBFT consenter.
+	ConsenterID uint64 `json:"consenter_id,omitempty"`
+	// CertSerial is the hex encoded serial number of the server TLS
+	// certificate of a consenter.
+	CertSerial string `json:"cert_serial,omitempty"`
+	// CertExpiry is the expiry of the server TLS certificate of a consenter.
+	CertExpiry *time.Time `json:"cert_expiry,omitempty"`
+}
+
+// OrdererInventory is the ordering node inventory of a channel.
+type OrdererInventory []OrdererNode
+
+// JSON returns the inventory encoded as a JSON array.
+func (i OrdererInventory) JSON() ([]byte, error) {
+	nodes := i
+	if nodes == nil {
+		nodes = OrdererInventory{}
+	}
+
+	return json.MarshalIndent(nodes, "", "\t")
+}
+
+// CSV returns the inventory encoded as CSV with a header row.
+func (i OrdererInventory) CSV() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	w := csv.NewWriter(buf)
+
+	records := [][]string{
+		{"host", "port", "org", "consensus_type", "role", "consenter_id", "cert_serial", "cert_expiry"},
+	}
+	for _, node := range i {
+		var consenterID, certExpiry string
+		if node.ConsenterID != 0 {
+			consenterID = strconv.FormatUint(node.ConsenterID, 10)
+		}
+		if node.CertExpiry != nil {
+			certExpiry = node.CertExpiry.UTC().Format(time.RFC3339)
+		}
+		records = append(records, []string{
+			node.Host,
+			strconv.Itoa(node.Port),
+			node.Org,
+			node.ConsensusType,
+			string(node.Role),
+			consenterID,
+			node.CertSerial,
+			certExpiry,
+		})
+	}
+
+	err := w.WriteAll(records)
+	if err != nil {
+		return nil, fmt.Errorf("writing csv: %v", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Inventory returns the ordering nodes of the updated config. It lists the
+// orderer endpoints of every orderer organization followed by the consenters
+// of the etcdraft or SmartBFT consensus metadata.
+func (o *OrdererGroup) Inventory() (OrdererInventory, error) {
+	ordererConfig, err := o.Configuration()
+	if err != nil {
+		return nil, err
+	}
+
+	return ordererConfig.Inventory()
+}
+
+// Inventory returns the ordering nodes of the orderer configuration. It lists
+// the orderer endpoints of every orderer organization followed by the
+// consenters of the etcdraft or SmartBFT consensus metadata.
+func (o Orderer) Inventory() (OrdererInventory, error) {
+	var inventory OrdererInventory
+
+	for _, org := range o.Organizations {
+		for _, endpoint := range org.OrdererEndpoints {
+			host, port, err := splitEndpoint(endpoint)
+			if err != nil {
+				return nil, fmt.Errorf("invalid endpoint '%s' of org %s: %v", endpoint, org.Name, err)
+			}
+			inventory = append(inventory, OrdererNode{
+				Host:          host,
+				Port:          port,
+				Org:           org.Name,
+				ConsensusType: o.OrdererType,
+				Role:          OrdererNodeRoleEndpoint,
+			})
+		}
+	}
+
+	switch o.OrdererType {
+	case orderer.ConsensusTypeEtcdRaft:
+		for _, consenter := range o.EtcdRaft.Consenters {
+			node := OrdererNode{
+				Host:          consenter.Address.Host,
+				Port:          consenter.Address.Port,
+				Org:           tlsCertOrg(o.Organizations, consenter.ServerTLSCert),
+				ConsensusType: o.OrdererType,
+				Role:          OrdererNodeRoleConsenter,
+			}
+			setCertInfo(&node, consenter.ServerTLSCert)
+			inventory = append(inventory, node)
+		}
+	case orderer.ConsensusTypeSmartBFT:
+		for _, consenter := range o.SmartBFT.Consenters {
+			node := OrdererNode{
+				Host:          consenter.Address.Host,
+				Port:          consenter.Address.Port,
+				Org:           mspIDOrg(o.Organizations, consenter.MSPID),
+				ConsensusType: o.OrdererType,
+				Role:          OrdererNodeRoleConsenter,
+				ConsenterID:   consenter.ID,
+			}
+			setCertInfo(&node, consenter.ServerTLSCert)
+			inventory = append(inventory, node)
+		}
+	}
+
+	return inventory, nil
+}
+
+func splitEndpoint(endpoint string) (string, int, error) {
+	host, portStr, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return "", 0, err
+	}
+
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid port '%s'", portStr)
+	}
+
+	return host, port, nil
+}
+
+func setCertInfo(node *OrdererNode, cert *x509.Certificate) {
+	if cert == nil {
+		return
+	}
+
+	expiry := cert.NotAfter
+	node.CertSerial = fmt.Sprintf("%x", cert.SerialNumber)
+	node.CertExpiry = &expiry
+}
+
+// tlsCertOrg returns the name of the organization whose TLS CA issued the
+// certificate.
+func tlsCertOrg(orgs []Organization, cert *x509.Certificate) string {
+	return issuingOrg(orgs, cert, func(msp MSP) ([]*x509.Certificate, []*x509.Certificate) {
+		return msp.TLSRootCerts, msp.TLSIntermediateCerts
+	})
+}
+
+// identityCertOrg returns the name of the organization whose CA issued the
+// identity certificate.
+func identityCertOrg(orgs []Organization, cert *x509.Certificate) string {
+	return issuingOrg(orgs, cert, func(msp MSP) ([]*x509.Certificate, []*x509.Certificate) {
+		return msp.RootCerts, msp.IntermediateCerts
+	})
+}
+
+// issuingOrg returns the name of the first organization whose CA certs, as
+// selected by caCerts, verify the certificate.
+func issuingOrg(orgs []Organization, cert *x509.Certificate, caCerts func(MSP) (roots, intermediates []*x509.Certificate)) string {
+	if cert == nil {
+		return ""
+	}
+
+	for _, org := range orgs {
+		rootCerts, intermediateCerts := caCerts(org.MSP)
+		if verifyChain(rootCerts, intermediateCerts, true)(cert) == nil {
+			return org.Name
+		}
+	}
+
+	return ""
+}
+
+// mspIDOrg returns the name of the organization with the given MSP ID.
+func mspIDOrg(orgs []Organization, mspID string) string {
+	for _, org := range orgs {
+		if org.MSP.Name == mspID {
+			return org.Name
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"fmt"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestOrdererInventory(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	serverCert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", caCert, caPrivKey)
+	expiry := serverCert.NotAfter
+	serial := fmt.Sprintf("%x", serverCert.SerialNumber)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	etcdRaftOrderer.Organizations[0].MSP.TLSRootCerts = []*x509.Certificate{caCert}
+	etcdRaftOrderer.EtcdRaft.Consenters = etcdRaftOrderer.EtcdRaft.Consenters[:2]
+	etcdRaftOrderer.EtcdRaft.Consenters[0].ServerTLSCert = serverCert
+
+	inventory, err := etcdRaftOrderer.Inventory()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(inventory).To(HaveLen(3))
+	gt.Expect(inventory[0]).To(Equal(OrdererNode{
+		Host:          "localhost",
+		Port:          123,
+		Org:           "OrdererOrg",
+		ConsensusType: orderer.ConsensusTypeEtcdRaft,
+		Role:          OrdererNodeRoleEndpoint,
+	}))
+	gt.Expect(inventory[1]).To(Equal(OrdererNode{
+		Host:          "node-1.example.com",
+		Port:          7050,
+		Org:           "OrdererOrg",
+		ConsensusType: orderer.ConsensusTypeEtcdRaft,
+		Role:          OrdererNodeRoleConsenter,
+		CertSerial:    serial,
+		CertExpiry:    &expiry,
+	}))
+	gt.Expect(inventory[2].Host).To(Equal("node-2.example.com"))
+	gt.Expect(inventory[2].Org).To(BeEmpty())
+
+	smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+	smartBFTOrderer.Organizations[0].OrdererEndpoints = nil
+	smartBFTOrderer.SmartBFT.Consenters = smartBFTOrderer.SmartBFT.Consenters[:1]
+	smartBFTOrderer.SmartBFT.Consenters[0].ServerTLSCert = serverCert
+
+	inventory, err = smartBFTOrderer.Inventory()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(inventory).To(Equal(OrdererInventory{
+		{
+			Host:          "node-1.example.com",
+			Port:          7050,
+			Org:           "OrdererOrg",
+			ConsensusType: orderer.ConsensusTypeSmartBFT,
+			Role:          OrdererNodeRoleConsenter,
+			ConsenterID:   1,
+			CertSerial:    serial,
+			CertExpiry:    &expiry,
+		},
+	}))
+
+	etcdRaftOrderer.Organizations[0].OrdererEndpoints = []string{"localhost"}
+	_, err = etcdRaftOrderer.Inventory()
+	gt.Expect(err).To(MatchError("invalid endpoint 'localhost' of org OrdererOrg: address localhost: missing port in address"))
+}
+
+func TestOrdererGroupInventory(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSmartBFT)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	inventory, err := c.Orderer().Inventory()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(inventory).To(HaveLen(5))
+	gt.Expect(inventory[0].Role).To(Equal(OrdererNodeRoleEndpoint))
+	for _, node := range inventory[1:] {
+		gt.Expect(node.Role).To(Equal(OrdererNodeRoleConsenter))
+		gt.Expect(node.Org).To(Equal("OrdererOrg"))
+	}
+}
+
+func TestOrdererInventoryEncoding(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	expiry := time.Date(2030, time.January, 2, 3, 4, 5, 0, time.UTC)
+	inventory := OrdererInventory{
+		{
+			Host:          "localhost",
+			Port:          123,
+			Org:           "OrdererOrg",
+			ConsensusType: orderer.ConsensusTypeSmartBFT,
+			Role:          OrdererNodeRoleEndpoint,
+		},
+		{
+			Host:          "node-1.example.com",
+			Port:          7050,
+			Org:           "OrdererOrg",
+			ConsensusType: orderer.ConsensusTypeSmartBFT,
+			Role:          OrdererNodeRoleConsenter,
+			ConsenterID:   1,
+			CertSerial:    "2a",
+			CertExpiry:    &expiry,
+		},
+	}
+
+	csvBytes, err := inventory.CSV()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(string(csvBytes)).To(Equal(`host,port,org,consensus_type,role,consenter_id,cert_serial,cert_expiry
+localhost,123,OrdererOrg,smartbft,endpoint,,,
+node-1.example.com,7050,OrdererOrg,smartbft,consenter,1,2a,2030-01-02T03:04:05Z
+`))
+
+	jsonBytes, err := inventory.JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(jsonBytes).To(MatchJSON(`[
+		{
+			"host": "localhost",
+			"port": 123,
+			"org": "OrdererOrg",
+			"consensus_type": "smartbft",
+			"role": "endpoint"
+		},
+		{
+			"host": "node-1.example.com",
+			"port": 7050,
+			"org": "OrdererOrg",
+			"consensus_type": "smartbft",
+			"role": "consenter",
+			"consenter_id": 1,
+			"cert_serial": "2a",
+			"cert_expiry": "2030-01-02T03:04:05Z"
+		}
+	]`))
+
+	jsonBytes, err = OrdererInventory(nil).JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(string(jsonBytes)).To(Equal("[]"))
+}
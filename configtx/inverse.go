@@ -0,0 +1,241 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+)
+
+// ComputeMarshaledInverseUpdate computes the ConfigUpdate reverting a config
+// update. originalBytes is the marshaled Config the update was applied to and
+// marshaledUpdate the marshaled ConfigUpdate. The returned marshaled
+// ConfigUpdate restores the original config when submitted against the config
+// resulting from the update, so it can be prepared along with the update to
+// roll it back.
+func ComputeMarshaledInverseUpdate(originalBytes, marshaledUpdate []byte, channelID string) ([]byte, error) {
+	original := &cb.Config{}
+	err := proto.Unmarshal(originalBytes, original)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling original config: %v", err)
+	}
+
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, update)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config update: %v", err)
+	}
+
+	updated, err := applyConfigUpdate(original, update)
+	if err != nil {
+		return nil, fmt.Errorf("applying config update: %v", err)
+	}
+
+	return computeMarshaledInverseUpdate(original, updated, channelID)
+}
+
+// ComputeMarshaledInverseUpdateFromConfigs computes the ConfigUpdate which
+// reverts the updated config to the original config, both given as marshaled
+// Config protobufs, and returns the marshaled bytes. The updated config must be
+// the config as committed, i.e. with the versions of its elements incremented
+// by the update.
+func ComputeMarshaledInverseUpdateFromConfigs(originalBytes, updatedBytes []byte, channelID string) ([]byte, error) {
+	original := &cb.Config{}
+	err := proto.Unmarshal(originalBytes, original)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling original config: %v", err)
+	}
+
+	updated := &cb.Config{}
+	err = proto.Unmarshal(updatedBytes, updated)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling updated config: %v", err)
+	}
+
+	return computeMarshaledInverseUpdate(original, updated, channelID)
+}
+
+// ComputeMarshaledInverseUpdateFromConfigBlocks computes the ConfigUpdate
+// which reverts the config of the updated config block to the config of the
+// original config block and returns the marshaled bytes.
+func ComputeMarshaledInverseUpdateFromConfigBlocks(originalBlockBytes, updatedBlockBytes []byte, channelID string) ([]byte, error) {
+	original, err := unmarshalConfigFromBlockBytes(originalBlockBytes)
+	if err != nil {
+		return nil, fmt.Errorf("original config block: %v", err)
+	}
+
+	updated, err := unmarshalConfigFromBlockBytes(updatedBlockBytes)
+	if err != nil {
+		return nil, fmt.Errorf("updated config block: %v", err)
+	}
+
+	return computeMarshaledInverseUpdate(original, updated, channelID)
+}
+
+// computeMarshaledInverseUpdate computes the update from the updated config
+// back to the original config. The read set and the versions of the write set
+// are taken from the updated config, against which the update is submitted.
+// computeConfigUpdate modifies the versions of its second argument, so the
+// original config is cloned.
+func computeMarshaledInverseUpdate(original, updated *cb.Config, channelID string) ([]byte, error) {
+	return computeMarshaledUpdate(updated, proto.Clone(original).(*cb.Config), channelID)
+}
+
+// applyConfigUpdate returns the config resulting from applying the update to
+// the config, as the orderer does when it commits the update. The read set
+// must match the versions of the config.
+func applyConfigUpdate(config *cb.Config, update *cb.ConfigUpdate) (*cb.Config, error) {
+	if config.ChannelGroup == nil {
+		return nil, errors.New("no channel group included for config")
+	}
+
+	if update.WriteSet == nil {
+		return nil, errors.New("config update does not contain a write set")
+	}
+
+	if update.ReadSet != nil {
+		err := verifyReadSet("/"+ChannelGroupKey, config.ChannelGroup, update.ReadSet)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	channelGroup, err := applyGroupUpdate("/"+ChannelGroupKey, config.ChannelGroup, update.WriteSet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cb.Config{
+		Sequence:     config.Sequence + 1,
+		ChannelGroup: channelGroup,
+	}, nil
+}
+
+// verifyReadSet checks that every element of the read set exists in the group
+// at path with the same version.
+func verifyReadSet(path string, group, readSet *cb.ConfigGroup) error {
+	if group.Version != readSet.Version {
+		return fmt.Errorf("read set version %d of %s does not match config version %d", readSet.Version, path, group.Version)
+	}
+
+	for name, policy := range readSet.Policies {
+		existing, ok := group.Policies[name]
+		if !ok {
+			return fmt.Errorf("read set policy %s/Policies/%s does not exist in config", path, name)
+		}
+		if existing.Version != policy.Version {
+			return fmt.Errorf("read set version %d of %s/Policies/%s does not match config version %d", policy.Version, path, name, existing.Version)
+		}
+	}
+
+	for name, value := range readSet.Values {
+		existing, ok := group.Values[name]
+		if !ok {
+			return fmt.Errorf("read set value %s/Values/%s does not exist in config", path, name)
+		}
+		if existing.Version != value.Version {
+			return fmt.Errorf("read set version %d of %s/Values/%s does not match config version %d", value.Version, path, name, existing.Version)
+		}
+	}
+
+	for name, subReadSet := range readSet.Groups {
+		subGroup, ok := group.Groups[name]
+		if !ok {
+			return fmt.Errorf("read set group %s/%s does not exist in config", path, name)
+		}
+		err := verifyReadSet(path+"/"+name, subGroup, subReadSet)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyGroupUpdate returns the group at path resulting from applying the
+// write set to it. Elements of the write set whose version is higher than the
+// version in the group replace the element of the group, and new elements are
+// added. If the version of the group itself is incremented, its members are
+// replaced by those of the write set, so that members missing from the write
+// set are removed.
+func applyGroupUpdate(path string, group, writeSet *cb.ConfigGroup) (*cb.ConfigGroup, error) {
+	if group == nil {
+		return proto.Clone(writeSet).(*cb.ConfigGroup), nil
+	}
+
+	if writeSet.Version != group.Version && writeSet.Version != group.Version+1 {
+		return nil, fmt.Errorf("write set version %d of %s is not valid for config version %d", writeSet.Version, path, group.Version)
+	}
+
+	result := proto.Clone(group).(*cb.ConfigGroup)
+	if writeSet.Version == group.Version+1 {
+		result.Version = writeSet.Version
+		result.ModPolicy = writeSet.ModPolicy
+		result.Policies = map[string]*cb.ConfigPolicy{}
+		result.Values = map[string]*cb.ConfigValue{}
+		result.Groups = map[string]*cb.ConfigGroup{}
+		for name := range writeSet.Policies {
+			if policy, ok := group.Policies[name]; ok {
+				result.Policies[name] = proto.Clone(policy).(*cb.ConfigPolicy)
+			}
+		}
+		for name := range writeSet.Values {
+			if value, ok := group.Values[name]; ok {
+				result.Values[name] = proto.Clone(value).(*cb.ConfigValue)
+			}
+		}
+		for name := range writeSet.Groups {
+			if subGroup, ok := group.Groups[name]; ok {
+				result.Groups[name] = proto.Clone(subGroup).(*cb.ConfigGroup)
+			}
+		}
+	}
+
+	for name, policy := range writeSet.Policies {
+		existing, ok := result.Policies[name]
+		if ok && policy.Version == existing.Version {
+			continue
+		}
+		if ok && policy.Version != existing.Version+1 {
+			return nil, fmt.Errorf("write set version %d of %s/Policies/%s is not valid for config version %d", policy.Version, path, name, existing.Version)
+		}
+		if result.Policies == nil {
+			result.Policies = map[string]*cb.ConfigPolicy{}
+		}
+		result.Policies[name] = proto.Clone(policy).(*cb.ConfigPolicy)
+	}
+
+	for name, value := range writeSet.Values {
+		existing, ok := result.Values[name]
+		if ok && value.Version == existing.Version {
+			continue
+		}
+		if ok && value.Version != existing.Version+1 {
+			return nil, fmt.Errorf("write set version %d of %s/Values/%s is not valid for config version %d", value.Version, path, name, existing.Version)
+		}
+		if result.Values == nil {
+			result.Values = map[string]*cb.ConfigValue{}
+		}
+		result.Values[name] = proto.Clone(value).(*cb.ConfigValue)
+	}
+
+	for name, subWriteSet := range writeSet.Groups {
+		subGroup, err := applyGroupUpdate(path+"/"+name, result.Groups[name], subWriteSet)
+		if err != nil {
+			return nil, err
+		}
+		if result.Groups == nil {
+			result.Groups = map[string]*cb.ConfigGroup{}
+		}
+		result.Groups[name] = subGroup
+	}
+
+	return result, nil
+}
@@ -0,0 +1,153 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+// inverseUpdateConfigs returns an original config, a marshaled update of it
+// and the config resulting from the update.
+func inverseUpdateConfigs(t *testing.T) (*cb.Config, []byte, *cb.Config) {
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	original := proto.Clone(c.OriginalConfig()).(*cb.Config)
+
+	org3 := baseApplicationOrg(t)
+	org3.Name = "Org3"
+	err := c.Apply([]Change{
+		AddApplicationOrg{Org: org3},
+		RemoveApplicationOrg{Name: "Org2"},
+		SetBatchTimeout{Timeout: 5 * time.Second},
+		SetPolicy{Group: "Application/Org1", Name: "Custom", Policy: Policy{
+			Type:      SignaturePolicyType,
+			Rule:      "OR('MSPID.admin')",
+			ModPolicy: AdminsPolicyKey,
+		}},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+	updated, err := applyConfigUpdate(original, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return original, marshaledUpdate, updated
+}
+
+// expectReverted checks that the marshaled inverse update reverts the updated
+// config to the original config.
+func expectReverted(gt *GomegaWithT, original, updated *cb.Config, marshaledInverse []byte) {
+	inverse := &cb.ConfigUpdate{}
+	err := proto.Unmarshal(marshaledInverse, inverse)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(inverse.ChannelId).To(Equal("testchannel"))
+
+	reverted, err := applyConfigUpdate(updated, inverse)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(reverted.Sequence).To(Equal(updated.Sequence + 1))
+
+	_, err = computeConfigUpdate(reverted, proto.Clone(original).(*cb.Config))
+	gt.Expect(err).To(MatchError("no differences detected between original and updated config"))
+}
+
+func TestComputeMarshaledInverseUpdate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	original, marshaledUpdate, updated := inverseUpdateConfigs(t)
+	gt.Expect(updated.ChannelGroup.Groups[ApplicationGroupKey].Groups).To(HaveKey("Org3"))
+	gt.Expect(updated.ChannelGroup.Groups[ApplicationGroupKey].Groups).NotTo(HaveKey("Org2"))
+
+	originalBytes, err := proto.Marshal(original)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	marshaledInverse, err := ComputeMarshaledInverseUpdate(originalBytes, marshaledUpdate, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	expectReverted(gt, original, updated, marshaledInverse)
+}
+
+func TestComputeMarshaledInverseUpdateFromConfigs(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	original, _, updated := inverseUpdateConfigs(t)
+	originalBytes, err := proto.Marshal(original)
+	gt.Expect(err).NotTo(HaveOccurred())
+	updatedBytes, err := proto.Marshal(updated)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	marshaledInverse, err := ComputeMarshaledInverseUpdateFromConfigs(originalBytes, updatedBytes, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	expectReverted(gt, original, updated, marshaledInverse)
+}
+
+func TestComputeMarshaledInverseUpdateFromConfigBlocks(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	original, _, updated := inverseUpdateConfigs(t)
+	originalBlock := newTestConfigBlock(t, newBlock(0, nil), original, nil)
+	updatedBlock := newTestConfigBlock(t, originalBlock, updated, nil)
+	originalBlockBytes, err := proto.Marshal(originalBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+	updatedBlockBytes, err := proto.Marshal(updatedBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	marshaledInverse, err := ComputeMarshaledInverseUpdateFromConfigBlocks(originalBlockBytes, updatedBlockBytes, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	expectReverted(gt, original, updated, marshaledInverse)
+}
+
+func TestComputeMarshaledInverseUpdateFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	original, marshaledUpdate, _ := inverseUpdateConfigs(t)
+	originalBytes, err := proto.Marshal(original)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = ComputeMarshaledInverseUpdate([]byte("garbage"), marshaledUpdate, "testchannel")
+	gt.Expect(err).To(MatchError(HavePrefix("unmarshaling original config: ")))
+
+	_, err = ComputeMarshaledInverseUpdate(originalBytes, []byte("garbage"), "testchannel")
+	gt.Expect(err).To(MatchError(HavePrefix("unmarshaling config update: ")))
+
+	_, err = ComputeMarshaledInverseUpdate(originalBytes, marshaledUpdate, "")
+	gt.Expect(err).To(MatchError("channel ID is required"))
+
+	_, err = ComputeMarshaledInverseUpdate(originalBytes, nil, "testchannel")
+	gt.Expect(err).To(MatchError("applying config update: config update does not contain a write set"))
+
+	stale := proto.Clone(original).(*cb.Config)
+	stale.ChannelGroup.Groups[ApplicationGroupKey].Version = 2
+	staleBytes, err := proto.Marshal(stale)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = ComputeMarshaledInverseUpdate(staleBytes, marshaledUpdate, "testchannel")
+	gt.Expect(err).To(MatchError("applying config update: read set version 0 of /Channel/Application does not match config version 2"))
+
+	stale = proto.Clone(original).(*cb.Config)
+	stale.ChannelGroup.Groups[OrdererGroupKey].Values[orderer.BatchTimeoutKey].Version = 3
+	staleBytes, err = proto.Marshal(stale)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = ComputeMarshaledInverseUpdate(staleBytes, marshaledUpdate, "testchannel")
+	gt.Expect(err).To(MatchError("applying config update: write set version 1 of /Channel/Orderer/Values/BatchTimeout is not valid for config version 3"))
+
+	_, err = ComputeMarshaledInverseUpdateFromConfigs(originalBytes, originalBytes, "testchannel")
+	gt.Expect(err).To(MatchError("failed to compute update: no differences detected between original and updated config"))
+}
@@ -0,0 +1,112 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"strings"
+)
+
+// journal records the paths of the operations performed through a ConfigTx,
+// so that a config update only needs to compare the parts of the original
+// and updated configs which may have been modified. A journal is complete as
+// long as every modification of the updated config went through an
+// operation. It is no longer complete once the original or updated config
+// protos are handed out, as they may then be modified directly.
+type journal struct {
+	complete bool
+	// paths are the paths of the recorded operations.
+	paths []string
+}
+
+// readOnlyOperations are the observed operations which do not modify the
+// updated config, other than the versions set when computing an update.
+var readOnlyOperations = map[string]bool{
+	"ConfigTx.ComputeMarshaledUpdate": true,
+	"ConfigTx.EstimateSize":           true,
+}
+
+// newJournal returns an empty, complete journal.
+func newJournal() *journal {
+	return &journal{complete: true}
+}
+
+// record adds the path of the named operation to the journal.
+func (j *journal) record(name, path string) {
+	if j == nil || !j.complete || readOnlyOperations[name] {
+		return
+	}
+
+	j.paths = append(j.paths, path)
+}
+
+// invalidate marks the journal as incomplete.
+func (j *journal) invalidate() {
+	if j != nil {
+		j.complete = false
+		j.paths = nil
+	}
+}
+
+// scope returns the parts of the channel group which must be compared to
+// compute the config update, or nil if the whole channel group must be
+// compared because the journal is incomplete.
+func (j *journal) scope() *updateScope {
+	if j == nil || !j.complete {
+		return nil
+	}
+
+	root := &updateScope{}
+	for _, path := range j.paths {
+		names := strings.Split(strings.TrimPrefix(path, "/"+ChannelGroupKey), "/")
+		s := root
+		full := true
+		for _, name := range names {
+			if name == "" {
+				continue
+			}
+			// the values and policies of a group in scope are always
+			// compared, so the operation only puts the group in scope
+			if name == "Values" || name == "Policies" {
+				full = false
+				break
+			}
+			if s.children == nil {
+				s.children = map[string]*updateScope{}
+			}
+			if s.children[name] == nil {
+				s.children[name] = &updateScope{}
+			}
+			s = s.children[name]
+		}
+		if full {
+			s.full = true
+		}
+	}
+
+	return root
+}
+
+// updateScope is a config group which may have been modified. The policies,
+// values and mod policy of the group are always compared, its child groups
+// only if the scope is full or if they have a scope of their own. A nil
+// scope is full.
+type updateScope struct {
+	full     bool
+	children map[string]*updateScope
+}
+
+// child returns the scope of the named child group and whether the child
+// group must be compared at all.
+func (s *updateScope) child(name string) (*updateScope, bool) {
+	if s == nil || s.full {
+		return nil, true
+	}
+
+	childScope, ok := s.children[name]
+
+	return childScope, ok
+}
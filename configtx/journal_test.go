@@ -0,0 +1,1397 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/membership"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
+	. "github.com/onsi/gomega"
+)
+
+func TestJournalScope(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	gt.Expect(c.journal.scope()).To(Equal(&updateScope{}))
+
+	err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().Organization("Org2").MSP().SetEnableNodeOUs(true)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().BatchSize().SetMaxMessageCount(500)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(c.journal.scope()).To(Equal(&updateScope{
+		children: map[string]*updateScope{
+			ApplicationGroupKey: {
+				children: map[string]*updateScope{
+					"Org1": {full: true},
+					"Org2": {},
+				},
+			},
+			OrdererGroupKey: {},
+		},
+	}))
+
+	_ = c.UpdatedConfig()
+	gt.Expect(c.journal.scope()).To(BeNil())
+}
+
+func TestJournalUpdate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName string
+		modify   func(*testing.T, *ConfigTx)
+	}{
+		{
+			testName: "When an application org is modified",
+			modify: func(t *testing.T, c *ConfigTx) {
+				err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+				NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			testName: "When an org MSP and the orderer batch size are modified",
+			modify: func(t *testing.T, c *ConfigTx) {
+				gt := NewGomegaWithT(t)
+				err := c.Application().Organization("Org2").MSP().SetEnableNodeOUs(true)
+				gt.Expect(err).NotTo(HaveOccurred())
+				err = c.Orderer().BatchSize().SetMaxMessageCount(500)
+				gt.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+		{
+			testName: "When an application org is removed",
+			modify: func(t *testing.T, c *ConfigTx) {
+				c.Application().RemoveOrganization("Org2")
+			},
+		},
+		{
+			testName: "When the updated config is modified directly",
+			modify: func(t *testing.T, c *ConfigTx) {
+				c.UpdatedConfig().ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].ModPolicy = ReadersPolicyKey
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := applyConfigTx(t)
+			tt.modify(t, &c)
+
+			marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			expectedUpdate, err := computeMarshaledUpdate(c.original, proto.Clone(c.updated).(*cb.Config), "testchannel")
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(proto.Equal(unmarshalConfigUpdate(gt, marshaledUpdate), unmarshalConfigUpdate(gt, expectedUpdate))).To(BeTrue())
+		})
+	}
+}
+
+// journalConfigTx returns a ConfigTx with application, orderer and
+// consortiums groups, legacy orderer addresses and a consortium name, so that
+// every operation can be performed on it, along with the private key of the
+// root CA of the orderer org.
+func journalConfigTx(t *testing.T, ordererType string) (ConfigTx, *ecdsa.PrivateKey) {
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererConf, ordererPrivKeys := baseOrdererOfType(t, ordererType)
+	channelGroup.Groups[OrdererGroupKey], err = newOrdererGroup(ordererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	consortiums, _ := baseConsortiums(t)
+	channelGroup.Groups[ConsortiumsGroupKey], err = newConsortiumsGroup(consortiums)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = setPolicies(channelGroup, standardPolicies())
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(channelGroup, capabilitiesValue([]string{"V3_0"}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(channelGroup, consortiumValue("Consortium1"), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup.Values[OrdererAddressesKey] = &cb.ConfigValue{
+		Value:     marshalOrPanic(&cb.OrdererAddresses{Addresses: []string{"localhost:123"}}),
+		ModPolicy: AdminsPolicyKey,
+	}
+
+	return New(&cb.Config{ChannelGroup: channelGroup}), ordererPrivKeys[0]
+}
+
+// journalReadOnlyMethods are the exported methods of the ConfigTx and of the
+// groups, orgs, MSPs and values retrieved from it which do not modify the
+// updated config.
+var journalReadOnlyMethods = []string{
+	"ApplicationGroup.ACLs",
+	"ApplicationGroup.Capabilities",
+	"ApplicationGroup.Configuration",
+	"ApplicationGroup.Organization",
+	"ApplicationGroup.Policies",
+	"ApplicationGroup.WithoutSubPolicyChecks",
+	"ApplicationOrg.AnchorPeers",
+	"ApplicationOrg.Configuration",
+	"ApplicationOrg.MSP",
+	"ApplicationOrg.Policies",
+	"BatchSizeValue.AbsoluteMaxBytesString",
+	"BatchSizeValue.PreferredMaxBytesString",
+	"ChannelGroup.Capabilities",
+	"ChannelGroup.Configuration",
+	"ChannelGroup.Policies",
+	"ChannelGroup.WithoutSubPolicyChecks",
+	"ConfigTx.Application",
+	"ConfigTx.Archive",
+	"ConfigTx.CRLReport",
+	"ConfigTx.Certificates",
+	"ConfigTx.Channel",
+	"ConfigTx.CheckChannelParticipation",
+	"ConfigTx.ComputeMarshaledUpdate",
+	"ConfigTx.ConnectionProfile",
+	"ConfigTx.Consortium",
+	"ConfigTx.Consortiums",
+	"ConfigTx.EnabledFeatures",
+	"ConfigTx.EstimateSize",
+	"ConfigTx.ExportWriteSet",
+	"ConfigTx.FindCertificate",
+	"ConfigTx.Fingerprint",
+	"ConfigTx.MembershipSnapshot",
+	"ConfigTx.MinFabricVersion",
+	"ConfigTx.NewSignedUpdateBundle",
+	"ConfigTx.Orderer",
+	"ConfigTx.OriginalConfig",
+	"ConfigTx.PoliciesBrokenWithout",
+	"ConfigTx.PolicyGraph",
+	"ConfigTx.RenderHTML",
+	"ConfigTx.ResolveIdentity",
+	"ConfigTx.SatisfiedPolicies",
+	"ConfigTx.SetLogger",
+	"ConfigTx.SetObserver",
+	"ConfigTx.UnsatisfiablePolicies",
+	"ConfigTx.UpdatedConfig",
+	"ConfigTx.UpgradeChanges",
+	"ConfigTx.ValidateConsensusMigration",
+	"ConfigTx.ValidatePolicySatisfiability",
+	"ConfigTx.WriteUpdateTo",
+	"ConsortiumGroup.Configuration",
+	"ConsortiumGroup.Organization",
+	"ConsortiumGroup.WithoutSubPolicyChecks",
+	"ConsortiumOrg.Configuration",
+	"ConsortiumOrg.MSP",
+	"ConsortiumOrg.Policies",
+	"ConsortiumsGroup.Configuration",
+	"OrdererGroup.BatchSize",
+	"OrdererGroup.Capabilities",
+	"OrdererGroup.Configuration",
+	"OrdererGroup.ConsensusMetadata",
+	"OrdererGroup.ConsenterOwnership",
+	"OrdererGroup.ConsentersForOrg",
+	"OrdererGroup.EtcdRaftOptions",
+	"OrdererGroup.Inventory",
+	"OrdererGroup.Organization",
+	"OrdererGroup.Policies",
+	"OrdererGroup.WithConsenterChecks",
+	"OrdererGroup.WithReachabilityChecks",
+	"OrdererGroup.WithoutSubPolicyChecks",
+	"OrdererOrg.Configuration",
+	"OrdererOrg.MSP",
+	"OrdererOrg.Policies",
+	"OrdererOrg.WithEndpointTLSCerts",
+	"OrdererOrg.WithEndpointTLSProbe",
+	"OrganizationMSP.Configuration",
+	"OrganizationMSP.WithCertificateChecks",
+	"OrganizationMSP.WithStrictAdds",
+}
+
+// journalOperation modifies the updated config through the method named by
+// method. The config is prepared by setup, if set, before the journal starts.
+type journalOperation struct {
+	method      string
+	ordererType string
+	setup       func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey)
+	modify      func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error
+}
+
+// ordererMSP returns the MSP of the orderer org and its root cert.
+func ordererMSP(t *testing.T, c *ConfigTx) (*OrganizationMSP, *x509.Certificate) {
+	msp := c.Orderer().Organization("OrdererOrg").MSP()
+	mspConfig, err := msp.Configuration()
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	return msp, mspConfig.RootCerts[0]
+}
+
+// journalCRL returns a CRL of the root cert revoking the serial number.
+func journalCRL(t *testing.T, rootCert *x509.Certificate, caKey *ecdsa.PrivateKey, serialNumber int64, revocationTime time.Time) *pkix.CertificateList {
+	gt := NewGomegaWithT(t)
+
+	now := time.Now()
+	crlBytes, err := rootCert.CreateCRL(rand.Reader, caKey, []pkix.RevokedCertificate{
+		{SerialNumber: big.NewInt(serialNumber), RevocationTime: revocationTime},
+	}, now, now.Add(YEAR))
+	gt.Expect(err).NotTo(HaveOccurred())
+	crl, err := x509.ParseCRL(crlBytes)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return crl
+}
+
+// journalOperations returns an operation for every exported method modifying
+// the updated config.
+func journalOperations() []journalOperation {
+	customPolicy := Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.member')", ModPolicy: AdminsPolicyKey}
+	newOrg := func(t *testing.T, name string) Organization {
+		org := baseApplicationOrg(t)
+		org.Name = name
+		org.MSP.Name = name + "MSP"
+		return org
+	}
+	newCA := func(t *testing.T) *x509.Certificate {
+		caCert, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+		return caCert
+	}
+	newIntermediate := func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) *x509.Certificate {
+		_, rootCert := ordererMSP(t, c)
+		intermediateCert, _ := generateIntermediateCACertAndPrivateKey(t, "org1.example.com", rootCert, caKey)
+		return intermediateCert
+	}
+	newCert := func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) *x509.Certificate {
+		_, rootCert := ordererMSP(t, c)
+		cert, _ := generateCertAndPrivateKeyFromCACert(t, "org1.example.com", rootCert, caKey)
+		return cert
+	}
+	addRootCert := func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) {
+		msp, _ := ordererMSP(t, c)
+		err := msp.AddRootCert(newCA(t))
+		NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+	}
+	addTLSRootCert := func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) {
+		msp, _ := ordererMSP(t, c)
+		err := msp.AddTLSRootCert(newCA(t))
+		NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+	}
+	lastRootCert := func(t *testing.T, c *ConfigTx) *x509.Certificate {
+		msp, _ := ordererMSP(t, c)
+		mspConfig, err := msp.Configuration()
+		NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+		return mspConfig.RootCerts[len(mspConfig.RootCerts)-1]
+	}
+	lastTLSRootCert := func(t *testing.T, c *ConfigTx) *x509.Certificate {
+		msp, _ := ordererMSP(t, c)
+		mspConfig, err := msp.Configuration()
+		NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+		return mspConfig.TLSRootCerts[len(mspConfig.TLSRootCerts)-1]
+	}
+	setDualRoleOrg := func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) {
+		org := newOrg(t, "Org3")
+		org.OrdererEndpoints = []string{"org3.example.com:7050"}
+		err := c.SetDualRoleOrganization(org)
+		NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+	}
+	writeOrgMSPDir := func(t *testing.T) string {
+		caCert, _ := generateCACertAndPrivateKey(t, "org3.example.com")
+		dir := writeMSPDir(t, map[string][]byte{
+			"cacerts/ca.pem": pemutil.EncodeCertificate(caCert),
+		})
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		return dir
+	}
+
+	return []journalOperation{
+		{
+			method: "ApplicationGroup.AddCapability",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().AddCapability("V2_0")
+			},
+		},
+		{
+			method: "ApplicationGroup.AddOrganizationFromMSPDir",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				_, err := c.Application().AddOrganizationFromMSPDir("Org3MSP", writeOrgMSPDir(t), nil, nil)
+				return err
+			},
+		},
+		{
+			method: "ApplicationGroup.RemoveACLs",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().RemoveACLs([]string{"acl1"})
+			},
+		},
+		{
+			method: "ApplicationGroup.RemoveCapability",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().RemoveCapability("V1_3")
+			},
+		},
+		{
+			method: "ApplicationGroup.RemoveOrganization",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				c.Application().RemoveOrganization("Org2")
+				return nil
+			},
+		},
+		{
+			method: "ApplicationGroup.RemovePolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().RemovePolicy(ReadersPolicyKey)
+			},
+		},
+		{
+			method: "ApplicationGroup.SetACL",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().SetACL("acl2", "/Channel/Application/Readers")
+			},
+		},
+		{
+			method: "ApplicationGroup.SetACLs",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().SetACLs(map[string]string{"acl2": "/Channel/Application/Readers"})
+			},
+		},
+		{
+			method: "ApplicationGroup.SetModPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().SetModPolicy(ReadersPolicyKey)
+			},
+		},
+		{
+			method: "ApplicationGroup.SetOrganization",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().SetOrganization(newOrg(t, "Org3"))
+			},
+		},
+		{
+			method: "ApplicationGroup.SetPolicies",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				policies := standardPolicies()
+				policies["Custom"] = customPolicy
+				return c.Application().SetPolicies(policies)
+			},
+		},
+		{
+			method: "ApplicationGroup.SetPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().SetPolicy("Custom", customPolicy)
+			},
+		},
+		{
+			method: "ApplicationOrg.AddAnchorPeer",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+			},
+		},
+		{
+			method: "ApplicationOrg.RemoveAnchorPeer",
+			setup: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) {
+				err := c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+				NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+			},
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().Organization("Org1").RemoveAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+			},
+		},
+		{
+			method: "ApplicationOrg.RemovePolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().Organization("Org1").RemovePolicy(EndorsementPolicyKey)
+			},
+		},
+		{
+			method: "ApplicationOrg.SetDefaultPolicies",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().Organization("Org1").SetDefaultPolicies(false)
+			},
+		},
+		{
+			method: "ApplicationOrg.SetMSP",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, err := c.Application().Organization("Org1").MSP().Configuration()
+				if err != nil {
+					return err
+				}
+				msp.CryptoConfig.SignatureHashFamily = "SHA2"
+				return c.Application().Organization("Org1").SetMSP(msp)
+			},
+		},
+		{
+			method: "ApplicationOrg.SetModPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().Organization("Org1").SetModPolicy(ReadersPolicyKey)
+			},
+		},
+		{
+			method: "ApplicationOrg.SetPolicies",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				policies := applicationOrgStandardPolicies()
+				policies["Custom"] = customPolicy
+				return c.Application().Organization("Org1").SetPolicies(policies)
+			},
+		},
+		{
+			method: "ApplicationOrg.SetPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Application().Organization("Org1").SetPolicy("Custom", customPolicy)
+			},
+		},
+		{
+			method: "ApplicationOrg.SetSignaturePolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				envelope, err := AnyOf(SignedBy(OUPrincipal("MSPID", "department1"))).Envelope()
+				if err != nil {
+					return err
+				}
+				return c.Application().Organization("Org1").SetSignaturePolicy("Custom", envelope, AdminsPolicyKey)
+			},
+		},
+		{
+			method: "BatchSizeValue.SetAbsoluteMaxBytes",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().BatchSize().SetAbsoluteMaxBytes(1000)
+			},
+		},
+		{
+			method: "BatchSizeValue.SetAbsoluteMaxBytesString",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().BatchSize().SetAbsoluteMaxBytesString("1 KB")
+			},
+		},
+		{
+			method: "BatchSizeValue.SetMaxMessageCount",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().BatchSize().SetMaxMessageCount(500)
+			},
+		},
+		{
+			method: "BatchSizeValue.SetPreferredMaxBytes",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().BatchSize().SetPreferredMaxBytes(50)
+			},
+		},
+		{
+			method: "BatchSizeValue.SetPreferredMaxBytesString",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().BatchSize().SetPreferredMaxBytesString("50 B")
+			},
+		},
+		{
+			method: "ChannelGroup.AddCapability",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Channel().AddCapability("V2_0")
+			},
+		},
+		{
+			method: "ChannelGroup.RemoveCapability",
+			setup: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) {
+				err := c.Channel().AddCapability("V2_0")
+				NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+			},
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Channel().RemoveCapability("V2_0")
+			},
+		},
+		{
+			method: "ChannelGroup.RemoveConsortium",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				c.Channel().RemoveConsortium()
+				return nil
+			},
+		},
+		{
+			method: "ChannelGroup.RemoveLegacyOrdererAddresses",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				c.Channel().RemoveLegacyOrdererAddresses()
+				return nil
+			},
+		},
+		{
+			method: "ChannelGroup.RemovePolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Channel().RemovePolicy(ReadersPolicyKey)
+			},
+		},
+		{
+			method: "ChannelGroup.SetModPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Channel().SetModPolicy(ReadersPolicyKey)
+			},
+		},
+		{
+			method: "ChannelGroup.SetPolicies",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				policies := standardPolicies()
+				policies["Custom"] = customPolicy
+				return c.Channel().SetPolicies(policies)
+			},
+		},
+		{
+			method: "ChannelGroup.SetPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Channel().SetPolicy("Custom", customPolicy)
+			},
+		},
+		{
+			method: "ConfigTx.Apply",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Apply([]Change{
+					SetBatchTimeout{Timeout: 3 * time.Second},
+					RemoveApplicationOrg{Name: "Org2"},
+				})
+			},
+		},
+		{
+			method: "ConfigTx.ApplyDocument",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.ApplyDocument([]byte("changes:\n- op: set-batch-timeout\n  timeout: 3s\n"))
+			},
+		},
+		{
+			method: "ConfigTx.ForceRemoveValue",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.ForceRemoveValue("", OrdererAddressesKey)
+			},
+		},
+		{
+			method: "ConfigTx.MigrateLegacyOrdererAddresses",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.MigrateLegacyOrdererAddresses(map[string]string{"localhost:123": "OrdererOrg"})
+			},
+		},
+		{
+			method: "ConfigTx.RemoveApplication",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.RemoveApplication()
+			},
+		},
+		{
+			method: "ConfigTx.RemoveConsortiums",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.RemoveConsortiums()
+			},
+		},
+		{
+			method: "ConfigTx.RemoveDualRoleOrganization",
+			setup:  setDualRoleOrg,
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.RemoveDualRoleOrganization("Org3")
+			},
+		},
+		{
+			method: "ConfigTx.RemoveValue",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.RemoveValue("", OrdererAddressesKey)
+			},
+		},
+		{
+			method: "ConfigTx.ReplaceCertificateEverywhere",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				_, rootCert := ordererMSP(t, c)
+				return c.ReplaceCertificateEverywhere(rootCert, newCA(t))
+			},
+		},
+		{
+			method: "ConfigTx.SetApplication",
+			setup: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) {
+				err := c.RemoveApplication()
+				NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+			},
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				application, _ := baseApplication(t)
+				return c.SetApplication(application)
+			},
+		},
+		{
+			method: "ConfigTx.SetDualRoleOrganization",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				setDualRoleOrg(t, c, caKey)
+				return nil
+			},
+		},
+		{
+			method: "ConfigTx.SetDualRoleOrganizationMSP",
+			setup:  setDualRoleOrg,
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, err := c.Application().Organization("Org3").MSP().Configuration()
+				if err != nil {
+					return err
+				}
+				msp.CryptoConfig.SignatureHashFamily = "SHA2"
+				return c.SetDualRoleOrganizationMSP("Org3", msp)
+			},
+		},
+		{
+			method: "ConfigTx.Upgrade",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Upgrade(FabricV3_0)
+			},
+		},
+		{
+			method: "ConsortiumGroup.RemoveOrganization",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				c.Consortium("Consortium1").RemoveOrganization("Org2")
+				return nil
+			},
+		},
+		{
+			method: "ConsortiumGroup.SetChannelCreationPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Consortium("Consortium1").SetChannelCreationPolicy(Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Admins"})
+			},
+		},
+		{
+			method: "ConsortiumGroup.SetOrganization",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Consortium("Consortium1").SetOrganization(newOrg(t, "Org3"))
+			},
+		},
+		{
+			method: "ConsortiumOrg.RemovePolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				c.Consortium("Consortium1").Organization("Org1").RemovePolicy(EndorsementPolicyKey)
+				return nil
+			},
+		},
+		{
+			method: "ConsortiumOrg.SetDefaultPolicies",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Consortium("Consortium1").Organization("Org1").SetDefaultPolicies(false)
+			},
+		},
+		{
+			method: "ConsortiumOrg.SetMSP",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				org := c.Consortium("Consortium1").Organization("Org1")
+				msp, err := org.MSP().Configuration()
+				if err != nil {
+					return err
+				}
+				msp.CryptoConfig.SignatureHashFamily = "SHA2"
+				return org.SetMSP(msp)
+			},
+		},
+		{
+			method: "ConsortiumOrg.SetModPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Consortium("Consortium1").Organization("Org1").SetModPolicy(ReadersPolicyKey)
+			},
+		},
+		{
+			method: "ConsortiumOrg.SetPolicies",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				policies := orgStandardPolicies()
+				policies["Custom"] = customPolicy
+				return c.Consortium("Consortium1").Organization("Org1").SetPolicies(policies)
+			},
+		},
+		{
+			method: "ConsortiumOrg.SetPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Consortium("Consortium1").Organization("Org1").SetPolicy("Custom", customPolicy)
+			},
+		},
+		{
+			method: "ConsortiumOrg.SetSignaturePolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				envelope, err := AnyOf(SignedBy(OUPrincipal("MSPID", "department1"))).Envelope()
+				if err != nil {
+					return err
+				}
+				return c.Consortium("Consortium1").Organization("Org1").SetSignaturePolicy("Custom", envelope, AdminsPolicyKey)
+			},
+		},
+		{
+			method: "ConsortiumsGroup.RemoveConsortium",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				c.Consortiums().RemoveConsortium("Consortium1")
+				return nil
+			},
+		},
+		{
+			method: "ConsortiumsGroup.SetConsortium",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Consortiums().SetConsortium(Consortium{
+					Name:          "Consortium2",
+					Organizations: []Organization{newOrg(t, "Org3")},
+				})
+			},
+		},
+		{
+			method: "EtcdRaftOptionsValue.SetElectionInterval",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().EtcdRaftOptions().SetElectionInterval(20)
+			},
+		},
+		{
+			method: "EtcdRaftOptionsValue.SetHeartbeatTick",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().EtcdRaftOptions().SetHeartbeatTick(2)
+			},
+		},
+		{
+			method: "EtcdRaftOptionsValue.SetMaxInflightBlocks",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().EtcdRaftOptions().SetMaxInflightBlocks(10)
+			},
+		},
+		{
+			method: "EtcdRaftOptionsValue.SetSnapshotIntervalSize",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().EtcdRaftOptions().SetSnapshotIntervalSize(1000)
+			},
+		},
+		{
+			method: "EtcdRaftOptionsValue.SetTickInterval",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().EtcdRaftOptions().SetTickInterval("500ms")
+			},
+		},
+		{
+			method: "OrdererGroup.AddCapability",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().AddCapability("V2_0")
+			},
+		},
+		{
+			method: "OrdererGroup.AddConsenter",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				ordererConfig, err := c.Orderer().Configuration()
+				if err != nil {
+					return err
+				}
+				consenter := ordererConfig.EtcdRaft.Consenters[0]
+				consenter.Address.Host = "node-4.example.com"
+				return c.Orderer().AddConsenter(consenter)
+			},
+		},
+		{
+			method:      "OrdererGroup.AddSmartBFTConsenter",
+			ordererType: orderer.ConsensusTypeSmartBFT,
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				ordererConfig, err := c.Orderer().Configuration()
+				if err != nil {
+					return err
+				}
+				consenter := ordererConfig.SmartBFT.Consenters[0]
+				consenter.ID = 5
+				consenter.Address.Host = "node-5.example.com"
+				return c.Orderer().AddSmartBFTConsenter(consenter)
+			},
+		},
+		{
+			method: "OrdererGroup.AddOrganizationFromMSPDir",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().AddOrganizationFromMSPDir("Orderer2MSP", writeOrgMSPDir(t), []Address{{Host: "orderer2.example.com", Port: 7050}}, nil)
+			},
+		},
+		{
+			method: "OrdererGroup.EnterMaintenanceMode",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().EnterMaintenanceMode()
+			},
+		},
+		{
+			method: "OrdererGroup.ExitMaintenanceMode",
+			setup: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) {
+				err := c.Orderer().SetConsensusState(orderer.ConsensusStateMaintenance)
+				NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+			},
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().ExitMaintenanceMode()
+			},
+		},
+		{
+			method: "OrdererGroup.RemoveCapability",
+			setup: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) {
+				err := c.Orderer().AddCapability("V2_0")
+				NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+			},
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().RemoveCapability("V1_3")
+			},
+		},
+		{
+			method: "OrdererGroup.RemoveConsenter",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				ordererConfig, err := c.Orderer().Configuration()
+				if err != nil {
+					return err
+				}
+				return c.Orderer().RemoveConsenter(ordererConfig.EtcdRaft.Consenters[0])
+			},
+		},
+		{
+			method: "OrdererGroup.RemoveLegacyKafkaBrokers",
+			setup: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) {
+				err := setValue(c.updated.ChannelGroup.Groups[OrdererGroupKey], kafkaBrokersValue([]string{"broker1"}), AdminsPolicyKey)
+				NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+			},
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				c.Orderer().RemoveLegacyKafkaBrokers()
+				return nil
+			},
+		},
+		{
+			method: "OrdererGroup.RemoveOrganization",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				c.Orderer().RemoveOrganization("OrdererOrg")
+				return nil
+			},
+		},
+		{
+			method: "OrdererGroup.RemoveOrganizationAndConsenters",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().RemoveOrganizationAndConsenters("OrdererOrg")
+			},
+		},
+		{
+			method: "OrdererGroup.RemovePolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().RemovePolicy(ReadersPolicyKey)
+			},
+		},
+		{
+			method:      "OrdererGroup.RemoveSmartBFTConsenter",
+			ordererType: orderer.ConsensusTypeSmartBFT,
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().RemoveSmartBFTConsenter(4)
+			},
+		},
+		{
+			method:      "OrdererGroup.SetBFTBlockValidationPolicy",
+			ordererType: orderer.ConsensusTypeSmartBFT,
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().SetBFTBlockValidationPolicy()
+			},
+		},
+		{
+			method: "OrdererGroup.SetBatchTimeout",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().SetBatchTimeout(3 * time.Second)
+			},
+		},
+		{
+			method: "OrdererGroup.SetBatchTimeoutString",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().SetBatchTimeoutString("3s")
+			},
+		},
+		{
+			method: "OrdererGroup.SetConfiguration",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				ordererConfig, err := c.Orderer().Configuration()
+				if err != nil {
+					return err
+				}
+				ordererConfig.BatchTimeout = 3 * time.Second
+				return c.Orderer().SetConfiguration(ordererConfig)
+			},
+		},
+		{
+			method: "OrdererGroup.SetConsensusMetadata",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				ordererConfig, err := c.Orderer().Configuration()
+				if err != nil {
+					return err
+				}
+				ordererConfig.EtcdRaft.Options.TickInterval = "500ms"
+				metadata, err := marshalEtcdRaftMetadata(ordererConfig.EtcdRaft)
+				if err != nil {
+					return err
+				}
+				return c.Orderer().SetConsensusMetadata(metadata)
+			},
+		},
+		{
+			method: "OrdererGroup.SetConsensusState",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().SetConsensusState(orderer.ConsensusStateMaintenance)
+			},
+		},
+		{
+			method: "OrdererGroup.SetEtcdRaftConsensusType",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				ordererConfig, err := c.Orderer().Configuration()
+				if err != nil {
+					return err
+				}
+				ordererConfig.EtcdRaft.Options.TickInterval = "500ms"
+				return c.Orderer().SetEtcdRaftConsensusType(ordererConfig.EtcdRaft, ordererConfig.State)
+			},
+		},
+		{
+			method: "OrdererGroup.SetMaxChannels",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().SetMaxChannels(10)
+			},
+		},
+		{
+			method: "OrdererGroup.SetModPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().SetModPolicy(ReadersPolicyKey)
+			},
+		},
+		{
+			method: "OrdererGroup.SetOrganization",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				org := newOrg(t, "Orderer2")
+				org.AnchorPeers = nil
+				org.OrdererEndpoints = []string{"orderer2.example.com:7050"}
+				return c.Orderer().SetOrganization(org)
+			},
+		},
+		{
+			method: "OrdererGroup.SetPolicies",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				policies := ordererStandardPolicies()
+				policies["Custom"] = customPolicy
+				return c.Orderer().SetPolicies(policies)
+			},
+		},
+		{
+			method: "OrdererGroup.SetPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().SetPolicy("Custom", customPolicy)
+			},
+		},
+		{
+			method:      "OrdererGroup.SetSmartBFTConsensusType",
+			ordererType: orderer.ConsensusTypeSmartBFT,
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				ordererConfig, err := c.Orderer().Configuration()
+				if err != nil {
+					return err
+				}
+				ordererConfig.SmartBFT.Options.RequestPoolSize = 500
+				return c.Orderer().SetSmartBFTConsensusType(ordererConfig.SmartBFT, ordererConfig.State)
+			},
+		},
+		{
+			method: "OrdererGroup.Update",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().Update(func(ordererConfig *Orderer) error {
+					ordererConfig.BatchTimeout = 3 * time.Second
+					return nil
+				})
+			},
+		},
+		{
+			method: "OrdererOrg.RemoveEndpoint",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().Organization("OrdererOrg").RemoveEndpoint(Address{Host: "localhost", Port: 123})
+			},
+		},
+		{
+			method: "OrdererOrg.RemovePolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().Organization("OrdererOrg").RemovePolicy(EndorsementPolicyKey)
+			},
+		},
+		{
+			method: "OrdererOrg.SetDefaultPolicies",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().Organization("OrdererOrg").SetDefaultPolicies(false)
+			},
+		},
+		{
+			method: "OrdererOrg.SetEndpoint",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().Organization("OrdererOrg").SetEndpoint(Address{Host: "orderer.example.com", Port: 7050})
+			},
+		},
+		{
+			method: "OrdererOrg.SetMSP",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+				if err != nil {
+					return err
+				}
+				msp.CryptoConfig.SignatureHashFamily = "SHA2"
+				return c.Orderer().Organization("OrdererOrg").SetMSP(msp)
+			},
+		},
+		{
+			method: "OrdererOrg.SetModPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().Organization("OrdererOrg").SetModPolicy(ReadersPolicyKey)
+			},
+		},
+		{
+			method: "OrdererOrg.SetPolicies",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				policies := orgStandardPolicies()
+				policies["Custom"] = customPolicy
+				return c.Orderer().Organization("OrdererOrg").SetPolicies(policies)
+			},
+		},
+		{
+			method: "OrdererOrg.SetPolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				return c.Orderer().Organization("OrdererOrg").SetPolicy("Custom", customPolicy)
+			},
+		},
+		{
+			method: "OrdererOrg.SetSignaturePolicy",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				envelope, err := AnyOf(SignedBy(OUPrincipal("MSPID", "department1"))).Envelope()
+				if err != nil {
+					return err
+				}
+				return c.Orderer().Organization("OrdererOrg").SetSignaturePolicy("Custom", envelope, AdminsPolicyKey)
+			},
+		},
+		{
+			method: "OrganizationMSP.AddAdminCert",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.AddAdminCert(newCert(t, c, caKey))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddAdminCertsFromPEM",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.AddAdminCertsFromPEM(pemutil.EncodeCertificate(newCert(t, c, caKey)))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddCRL",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.AddCRL(journalCRL(t, rootCert, caKey, 5, time.Now()))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddCRLFromSigningIdentity",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				signingIdentity := &SigningIdentity{Certificate: rootCert, PrivateKey: caKey, MSPID: "MSPID"}
+				return msp.AddCRLFromSigningIdentity(signingIdentity, newCert(t, c, caKey))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddIntermediateCert",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.AddIntermediateCert(newIntermediate(t, c, caKey))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddIntermediateCertsFromPEM",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.AddIntermediateCertsFromPEM(pemutil.EncodeCertificate(newIntermediate(t, c, caKey)))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddOUIdentifier",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.AddOUIdentifier(membership.OUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "OUID2"})
+			},
+		},
+		{
+			method: "OrganizationMSP.AddRootCert",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.AddRootCert(newCA(t))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddRootCertsFromPEM",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.AddRootCertsFromPEM(pemutil.EncodeCertificate(newCA(t)))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddStagedIntermediateCert",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				caCert, caKey := generateCACertAndPrivateKey(t, "org1.example.com")
+				intermediateCert, _ := generateIntermediateCACertAndPrivateKey(t, "org1.example.com", caCert, caKey)
+				return msp.AddStagedIntermediateCert(intermediateCert)
+			},
+		},
+		{
+			method: "OrganizationMSP.AddStagedTLSIntermediateCert",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				caCert, caKey := generateCACertAndPrivateKey(t, "org1.example.com")
+				intermediateCert, _ := generateIntermediateCACertAndPrivateKey(t, "org1.example.com", caCert, caKey)
+				return msp.AddStagedTLSIntermediateCert(intermediateCert)
+			},
+		},
+		{
+			method: "OrganizationMSP.AddTLSIntermediateCert",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.AddTLSIntermediateCert(newIntermediate(t, c, caKey))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddTLSIntermediateCertsFromPEM",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.AddTLSIntermediateCertsFromPEM(pemutil.EncodeCertificate(newIntermediate(t, c, caKey)))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddTLSRootCert",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.AddTLSRootCert(newCA(t))
+			},
+		},
+		{
+			method: "OrganizationMSP.AddTLSRootCertsFromPEM",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.AddTLSRootCertsFromPEM(pemutil.EncodeCertificate(newCA(t)))
+			},
+		},
+		{
+			method: "OrganizationMSP.CompactCRLs",
+			setup: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) {
+				msp, rootCert := ordererMSP(t, c)
+				err := msp.AddCRL(journalCRL(t, rootCert, caKey, 5, time.Now().Add(-2*YEAR)))
+				NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+			},
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				signingIdentity := &SigningIdentity{Certificate: rootCert, PrivateKey: caKey, MSPID: "MSPID"}
+				return msp.CompactCRLs(signingIdentity, YEAR)
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveAdminCert",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.RemoveAdminCert(rootCert)
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveAdminCertMatching",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.RemoveAdminCertMatching(MatchCertificate(rootCert))
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveIntermediateCert",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.RemoveIntermediateCert(rootCert)
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveIntermediateCertMatching",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.RemoveIntermediateCertMatching(MatchCertificate(rootCert))
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveOUIdentifier",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.RemoveOUIdentifier(membership.OUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "OUID"})
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveRootCert",
+			setup:  addRootCert,
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.RemoveRootCert(lastRootCert(t, c))
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveRootCertMatching",
+			setup:  addRootCert,
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.RemoveRootCertMatching(MatchCertificate(lastRootCert(t, c)))
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveTLSIntermediateCert",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.RemoveTLSIntermediateCert(rootCert)
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveTLSIntermediateCertMatching",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.RemoveTLSIntermediateCertMatching(MatchCertificate(rootCert))
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveTLSRootCert",
+			setup:  addTLSRootCert,
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.RemoveTLSRootCert(lastTLSRootCert(t, c))
+			},
+		},
+		{
+			method: "OrganizationMSP.RemoveTLSRootCertMatching",
+			setup:  addTLSRootCert,
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.RemoveTLSRootCertMatching(MatchCertificate(lastTLSRootCert(t, c)))
+			},
+		},
+		{
+			method: "OrganizationMSP.SetAdminOUIdentifier",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.SetAdminOUIdentifier(membership.OUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "admin"})
+			},
+		},
+		{
+			method: "OrganizationMSP.SetClientOUIdentifier",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.SetClientOUIdentifier(membership.OUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "client"})
+			},
+		},
+		{
+			method: "OrganizationMSP.SetCryptoConfig",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.SetCryptoConfig(membership.CryptoConfig{SignatureHashFamily: "SHA2", IdentityIdentifierHashFunction: "SHA256"})
+			},
+		},
+		{
+			method: "OrganizationMSP.SetEnableNodeOUs",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, _ := ordererMSP(t, c)
+				return msp.SetEnableNodeOUs(true)
+			},
+		},
+		{
+			method: "OrganizationMSP.SetOUIdentifiers",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.SetOUIdentifiers([]membership.OUIdentifier{{Certificate: rootCert, OrganizationalUnitIdentifier: "OUID2"}})
+			},
+		},
+		{
+			method: "OrganizationMSP.SetOrdererOUIdentifier",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.SetOrdererOUIdentifier(membership.OUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "orderer"})
+			},
+		},
+		{
+			method: "OrganizationMSP.SetPeerOUIdentifier",
+			modify: func(t *testing.T, c *ConfigTx, caKey *ecdsa.PrivateKey) error {
+				msp, rootCert := ordererMSP(t, c)
+				return msp.SetPeerOUIdentifier(membership.OUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "peer"})
+			},
+		},
+	}
+}
+
+func TestJournalOperations(t *testing.T) {
+	t.Parallel()
+
+	for _, op := range journalOperations() {
+		op := op
+		t.Run(op.method, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			ordererType := op.ordererType
+			if ordererType == "" {
+				ordererType = orderer.ConsensusTypeEtcdRaft
+			}
+			c, caKey := journalConfigTx(t, ordererType)
+			if op.setup != nil {
+				op.setup(t, &c, caKey)
+				c = New(c.UpdatedConfig())
+			}
+
+			err := op.modify(t, &c, caKey)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(c.journal.scope()).NotTo(BeNil())
+
+			marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			expectedUpdate, err := computeMarshaledUpdate(c.original, proto.Clone(c.updated).(*cb.Config), "testchannel")
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(proto.Equal(unmarshalConfigUpdate(gt, marshaledUpdate), unmarshalConfigUpdate(gt, expectedUpdate))).To(BeTrue())
+		})
+	}
+}
+
+func TestJournalOperationsCoverMethods(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	types := []reflect.Type{
+		reflect.TypeOf(&ConfigTx{}),
+		reflect.TypeOf(&ChannelGroup{}),
+		reflect.TypeOf(&ApplicationGroup{}),
+		reflect.TypeOf(&ApplicationOrg{}),
+		reflect.TypeOf(&OrdererGroup{}),
+		reflect.TypeOf(&OrdererOrg{}),
+		reflect.TypeOf(&ConsortiumsGroup{}),
+		reflect.TypeOf(&ConsortiumGroup{}),
+		reflect.TypeOf(&ConsortiumOrg{}),
+		reflect.TypeOf(&OrganizationMSP{}),
+		reflect.TypeOf(&BatchSizeValue{}),
+		reflect.TypeOf(&EtcdRaftOptionsValue{}),
+	}
+
+	covered := map[string]bool{}
+	for _, method := range journalReadOnlyMethods {
+		covered[method] = true
+	}
+	for _, op := range journalOperations() {
+		covered[op.method] = true
+	}
+
+	// every method is either read-only or has an operation checking that
+	// its modifications are journaled, and vice versa
+	var uncovered []string
+	for _, typ := range types {
+		for i := 0; i < typ.NumMethod(); i++ {
+			method := typ.Elem().Name() + "." + typ.Method(i).Name
+			if !covered[method] {
+				uncovered = append(uncovered, method)
+			}
+			delete(covered, method)
+		}
+	}
+	gt.Expect(uncovered).To(BeEmpty())
+	gt.Expect(covered).To(BeEmpty())
+}
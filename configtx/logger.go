@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"time"
+)
+
+// CertExpiryWarningPeriod is the remaining validity below which the
+// certificate of a consenter added to the config is logged as expiring.
+const CertExpiryWarningPeriod = 30 * 24 * time.Hour
+
+// Logger receives the warnings emitted while modifying a config, such as a
+// root CA retained alongside the root CA replacing it or a consenter cert
+// about to expire. keyvals are alternating keys and values describing the
+// warning; the first pair is always the path of the config element concerned.
+type Logger interface {
+	Warn(msg string, keyvals ...interface{})
+}
+
+// NopLogger is a Logger discarding every warning. It is used when no logger is
+// set.
+type NopLogger struct{}
+
+// Warn does nothing.
+func (NopLogger) Warn(string, ...interface{}) {}
+
+// SetLogger sets the logger receiving the warnings emitted by the operations
+// performed through the ConfigTx and through the groups, orgs and MSPs
+// retrieved from it afterwards. A nil logger discards warnings.
+func (c *ConfigTx) SetLogger(logger Logger) {
+	c.logger = logger
+}
+
+// warn logs a warning about the observed element.
+func (o observed) warn(msg string, keyvals ...interface{}) {
+	logger := o.logger
+	if logger == nil {
+		logger = NopLogger{}
+	}
+
+	logger.Warn(msg, append([]interface{}{"path", o.path}, keyvals...)...)
+}
+
+// warnRetainedRootCA warns if the MSP still trusts a root CA with the subject
+// of a root CA being added, as it does during a CA rotation until the old
+// root CA is removed.
+func (o observed) warnRetainedRootCA(roots []*x509.Certificate, cert *x509.Certificate) {
+	for _, root := range roots {
+		if root.Equal(cert) || root.Subject.String() != cert.Subject.String() {
+			continue
+		}
+
+		o.warn("old root CA retained",
+			"subject", cert.Subject.String(),
+			"serial_number", root.SerialNumber.String(),
+			"new_serial_number", cert.SerialNumber.String(),
+		)
+	}
+}
+
+// warnExpiringCert warns if a consenter cert expires within
+// CertExpiryWarningPeriod.
+func (o observed) warnExpiringCert(consenter, kind string, cert *x509.Certificate) {
+	if cert == nil {
+		return
+	}
+
+	remaining := time.Until(cert.NotAfter)
+	if remaining >= CertExpiryWarningPeriod {
+		return
+	}
+
+	if remaining <= 0 {
+		o.warn("consenter cert expired",
+			"consenter", consenter,
+			"cert", kind,
+			"not_after", cert.NotAfter.UTC().Format(time.RFC3339),
+		)
+		return
+	}
+
+	o.warn("consenter cert expires soon",
+		"consenter", consenter,
+		"cert", kind,
+		"expires_in_days", int(remaining.Hours()/24),
+		"not_after", cert.NotAfter.UTC().Format(time.RFC3339),
+	)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+type warning struct {
+	msg     string
+	keyvals []interface{}
+}
+
+type recordingLogger struct {
+	warnings []warning
+}
+
+func (r *recordingLogger) Warn(msg string, keyvals ...interface{}) {
+	r.warnings = append(r.warnings, warning{msg: msg, keyvals: keyvals})
+}
+
+func TestLoggerRetainedRootCA(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	logger := &recordingLogger{}
+	c.SetLogger(logger)
+
+	msp, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	oldRoot := msp.RootCerts[0]
+
+	template := &x509.Certificate{
+		SerialNumber:          generateSerialNumber(t),
+		Subject:               oldRoot.Subject,
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	newRoot, _ := generateCertAndPrivateKey(t, template, template, nil)
+	otherRoot, _ := generateCACertAndPrivateKey(t, "other-org")
+
+	err = c.Orderer().Organization("OrdererOrg").MSP().AddRootCert(otherRoot)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(logger.warnings).To(BeEmpty())
+
+	err = c.Orderer().Organization("OrdererOrg").MSP().AddRootCert(newRoot)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(logger.warnings).To(Equal([]warning{
+		{
+			msg: "old root CA retained",
+			keyvals: []interface{}{
+				"path", "/Channel/Orderer/OrdererOrg/Values/MSP",
+				"subject", oldRoot.Subject.String(),
+				"serial_number", oldRoot.SerialNumber.String(),
+				"new_serial_number", newRoot.SerialNumber.String(),
+			},
+		},
+	}))
+}
+
+func TestLoggerExpiringConsenterCert(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeEtcdRaft)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	logger := &recordingLogger{}
+	c.SetLogger(logger)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	notAfter := time.Now().Add(20*24*time.Hour + time.Hour)
+	expiringCert, _ := generateCertAndPrivateKey(t, &x509.Certificate{
+		SerialNumber: generateSerialNumber(t),
+		NotBefore:    time.Now(),
+		NotAfter:     notAfter,
+	}, caCert, caPrivKey)
+	validCert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", caCert, caPrivKey)
+
+	err = c.Orderer().AddConsenter(orderer.Consenter{
+		Address:       orderer.EtcdAddress{Host: "node-2.example.com", Port: 7050},
+		ClientTLSCert: validCert,
+		ServerTLSCert: expiringCert,
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(logger.warnings).To(Equal([]warning{
+		{
+			msg: "consenter cert expires soon",
+			keyvals: []interface{}{
+				"path", "/Channel/Orderer",
+				"consenter", "node-2.example.com:7050",
+				"cert", "server tls cert",
+				"expires_in_days", 20,
+				"not_after", notAfter.UTC().Format(time.RFC3339),
+			},
+		},
+	}))
+}
+
+func TestLoggerCertificateChecks(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	logger := &recordingLogger{}
+	c.SetLogger(logger)
+
+	cert := generateCert(t, "not-a-ca")
+	err = c.Orderer().Organization("OrdererOrg").MSP().WithCertificateChecks(CertificateChecksLenient, nil).AddRootCert(cert)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(logger.warnings).NotTo(BeEmpty())
+	for _, w := range logger.warnings {
+		gt.Expect(w.msg).To(Equal("certificate check failed"))
+		gt.Expect(w.keyvals[:2]).To(Equal([]interface{}{"path", "/Channel/Orderer/OrdererOrg/Values/MSP"}))
+		gt.Expect(w.keyvals[2]).To(Equal("error"))
+	}
+}
+
+func TestLoggerLegacyOrdererAddresses(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := legacyOrdererAddressesConfig(t, "node-1.example.com:7050", "localhost:123")
+
+	logger := &recordingLogger{}
+	c.SetLogger(logger)
+
+	err := c.MigrateLegacyOrdererAddresses(nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(logger.warnings).To(Equal([]warning{
+		{
+			msg: "legacy orderer address attributed by consenter cert",
+			keyvals: []interface{}{
+				"path", "/Channel",
+				"address", "node-1.example.com:7050",
+				"org", "OrdererOrg",
+			},
+		},
+	}))
+}
+
+func TestNopLogger(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	cert := generateCert(t, "not-a-ca")
+	gt.Expect(func() {
+		err = c.Orderer().Organization("OrdererOrg").MSP().WithCertificateChecks(CertificateChecksLenient, nil).AddRootCert(cert)
+	}).NotTo(Panic())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var logger Logger = NopLogger{}
+	logger.Warn("ignored", "key", "value")
+}
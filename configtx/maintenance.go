@@ -0,0 +1,134 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	ob "github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// EnterMaintenanceMode sets the consensus state of the orderer to
+// maintenance. The orderers reject updates which change the consensus state
+// along with anything else, so an error is returned if the orderer is
+// already in maintenance mode or if the config contains other changes.
+func (o *OrdererGroup) EnterMaintenanceMode() (err error) {
+	defer o.observe("OrdererGroup.EnterMaintenanceMode", time.Now(), &err)
+
+	return o.changeConsensusState(ob.ConsensusType_STATE_NORMAL, orderer.ConsensusStateMaintenance)
+}
+
+// ExitMaintenanceMode sets the consensus state of the orderer back to
+// normal. An error is returned if the orderer is not in maintenance mode or
+// if the config contains other changes, such as a consensus type change,
+// which must be submitted in a prior update.
+func (o *OrdererGroup) ExitMaintenanceMode() (err error) {
+	defer o.observe("OrdererGroup.ExitMaintenanceMode", time.Now(), &err)
+
+	return o.changeConsensusState(ob.ConsensusType_STATE_MAINTENANCE, orderer.ConsensusStateNormal)
+}
+
+func (o *OrdererGroup) changeConsensusState(from ob.ConsensusType_State, to orderer.ConsensusState) error {
+	if o.originalChannelGroup == nil {
+		return errors.New("original config is required to change the consensus state")
+	}
+
+	originalConsensusType, err := consensusTypeOf(o.originalChannelGroup)
+	if err != nil {
+		return fmt.Errorf("original config: %v", err)
+	}
+
+	if originalConsensusType.State != from {
+		if from == ob.ConsensusType_STATE_NORMAL {
+			return errors.New("orderer is already in maintenance mode")
+		}
+		return errors.New("orderer is not in maintenance mode")
+	}
+
+	if !proto.Equal(o.originalChannelGroup, o.channelGroup) {
+		return errors.New("config contains other changes, the consensus state must be changed in an update of its own")
+	}
+
+	return o.SetConsensusState(to)
+}
+
+// ValidateConsensusMigration checks that the changes of the updated config
+// to the orderer consensus type follow the rules enforced by the orderers
+// during a consensus type migration: an update changing the consensus state
+// must not change anything else, and an update changing the consensus type
+// may only be submitted in maintenance mode and must not change anything but
+// the consensus type and its metadata.
+func (c *ConfigTx) ValidateConsensusMigration() error {
+	if _, ok := c.original.ChannelGroup.Groups[OrdererGroupKey]; !ok {
+		return nil
+	}
+	if _, ok := c.updated.ChannelGroup.Groups[OrdererGroupKey]; !ok {
+		return nil
+	}
+
+	original, err := consensusTypeOf(c.original.ChannelGroup)
+	if err != nil {
+		return fmt.Errorf("original config: %v", err)
+	}
+
+	updated, err := consensusTypeOf(c.updated.ChannelGroup)
+	if err != nil {
+		return fmt.Errorf("updated config: %v", err)
+	}
+
+	stateChanged := original.State != updated.State
+	typeChanged := original.Type != updated.Type
+
+	if !stateChanged && !typeChanged {
+		return nil
+	}
+
+	if stateChanged {
+		if typeChanged || !bytes.Equal(original.Metadata, updated.Metadata) {
+			return errors.New("consensus state and consensus type or metadata cannot be changed in the same update")
+		}
+	}
+
+	if typeChanged && (original.State != ob.ConsensusType_STATE_MAINTENANCE || updated.State != ob.ConsensusType_STATE_MAINTENANCE) {
+		return fmt.Errorf("consensus type can only be changed from %s to %s in maintenance mode", original.Type, updated.Type)
+	}
+
+	// Apart from the ConsensusType value, the channel must be left untouched.
+	channelGroup := proto.Clone(c.updated.ChannelGroup).(*cb.ConfigGroup)
+	channelGroup.Groups[OrdererGroupKey].Values[orderer.ConsensusTypeKey] = c.original.ChannelGroup.Groups[OrdererGroupKey].Values[orderer.ConsensusTypeKey]
+	if !proto.Equal(channelGroup, c.original.ChannelGroup) {
+		if stateChanged {
+			return errors.New("config contains other changes, the consensus state must be changed in an update of its own")
+		}
+		return errors.New("config contains other changes, the consensus type must be changed in an update of its own")
+	}
+
+	return nil
+}
+
+// consensusTypeOf returns the ConsensusType value of the orderer group of a
+// channel group.
+func consensusTypeOf(channelGroup *cb.ConfigGroup) (*ob.ConsensusType, error) {
+	ordererGroup, ok := channelGroup.Groups[OrdererGroupKey]
+	if !ok {
+		return nil, errors.New("orderer group does not exist")
+	}
+
+	consensusType := &ob.ConsensusType{}
+	err := unmarshalConfigValueAtKey(ordererGroup, orderer.ConsensusTypeKey, consensusType)
+	if err != nil {
+		return nil, err
+	}
+
+	return consensusType, nil
+}
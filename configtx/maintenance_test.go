@@ -0,0 +1,206 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestConsensusMigrationThroughMaintenanceMode(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+	c := ordererConfigTx(t, etcdRaftOrderer)
+
+	err := c.Orderer().EnterMaintenanceMode()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.ValidateConsensusMigration()).To(Succeed())
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.State).To(Equal(orderer.ConsensusStateMaintenance))
+
+	c = New(proto.Clone(c.UpdatedConfig()).(*cb.Config))
+	err = c.Orderer().SetSmartBFTConsensusType(smartBFTOrderer.SmartBFT, orderer.ConsensusStateMaintenance)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.ValidateConsensusMigration()).To(Succeed())
+
+	err = c.Orderer().ExitMaintenanceMode()
+	gt.Expect(err).To(MatchError("config contains other changes, the consensus state must be changed in an update of its own"))
+
+	c = New(proto.Clone(c.UpdatedConfig()).(*cb.Config))
+	err = c.Orderer().ExitMaintenanceMode()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.ValidateConsensusMigration()).To(Succeed())
+	ordererConfig, err = c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.OrdererType).To(Equal(orderer.ConsensusTypeSmartBFT))
+	gt.Expect(ordererConfig.State).To(Equal(orderer.ConsensusStateNormal))
+}
+
+func TestMaintenanceModeFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		state       orderer.ConsensusState
+		change      func(c *ConfigTx) error
+		expectedErr string
+	}{
+		{
+			testName: "When entering maintenance mode twice",
+			state:    orderer.ConsensusStateMaintenance,
+			change: func(c *ConfigTx) error {
+				return c.Orderer().EnterMaintenanceMode()
+			},
+			expectedErr: "orderer is already in maintenance mode",
+		},
+		{
+			testName: "When exiting maintenance mode in normal state",
+			state:    orderer.ConsensusStateNormal,
+			change: func(c *ConfigTx) error {
+				return c.Orderer().ExitMaintenanceMode()
+			},
+			expectedErr: "orderer is not in maintenance mode",
+		},
+		{
+			testName: "When entering maintenance mode along with other changes",
+			state:    orderer.ConsensusStateNormal,
+			change: func(c *ConfigTx) error {
+				err := c.Orderer().BatchSize().SetMaxMessageCount(42)
+				if err != nil {
+					return err
+				}
+				return c.Orderer().EnterMaintenanceMode()
+			},
+			expectedErr: "config contains other changes, the consensus state must be changed in an update of its own",
+		},
+		{
+			testName: "When the original config has no orderer group",
+			state:    orderer.ConsensusStateNormal,
+			change: func(c *ConfigTx) error {
+				delete(c.original.ChannelGroup.Groups, OrdererGroupKey)
+				return c.Orderer().EnterMaintenanceMode()
+			},
+			expectedErr: "original config: orderer group does not exist",
+		},
+		{
+			testName: "When the orderer group is not retrieved from a ConfigTx",
+			state:    orderer.ConsensusStateNormal,
+			change: func(c *ConfigTx) error {
+				o := &OrdererGroup{channelGroup: c.updated.ChannelGroup, ordererGroup: c.updated.ChannelGroup.Groups[OrdererGroupKey]}
+				return o.EnterMaintenanceMode()
+			},
+			expectedErr: "original config is required to change the consensus state",
+		},
+		{
+			testName: "When changing the consensus type in normal state",
+			state:    orderer.ConsensusStateNormal,
+			change: func(c *ConfigTx) error {
+				smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+				err := c.Orderer().SetSmartBFTConsensusType(smartBFTOrderer.SmartBFT, orderer.ConsensusStateNormal)
+				if err != nil {
+					return err
+				}
+				return c.ValidateConsensusMigration()
+			},
+			expectedErr: "consensus type can only be changed from etcdraft to smartbft in maintenance mode",
+		},
+		{
+			testName: "When changing the consensus type while exiting maintenance mode",
+			state:    orderer.ConsensusStateMaintenance,
+			change: func(c *ConfigTx) error {
+				smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+				err := c.Orderer().SetSmartBFTConsensusType(smartBFTOrderer.SmartBFT, orderer.ConsensusStateNormal)
+				if err != nil {
+					return err
+				}
+				return c.ValidateConsensusMigration()
+			},
+			expectedErr: "consensus state and consensus type or metadata cannot be changed in the same update",
+		},
+		{
+			testName: "When changing the consensus type along with other changes",
+			state:    orderer.ConsensusStateMaintenance,
+			change: func(c *ConfigTx) error {
+				smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+				err := c.Orderer().SetSmartBFTConsensusType(smartBFTOrderer.SmartBFT, orderer.ConsensusStateMaintenance)
+				if err != nil {
+					return err
+				}
+				err = c.Orderer().SetBatchTimeout(time.Minute)
+				if err != nil {
+					return err
+				}
+				return c.ValidateConsensusMigration()
+			},
+			expectedErr: "config contains other changes, the consensus type must be changed in an update of its own",
+		},
+		{
+			testName: "When changing the consensus state along with other changes",
+			state:    orderer.ConsensusStateNormal,
+			change: func(c *ConfigTx) error {
+				err := c.Orderer().SetConsensusState(orderer.ConsensusStateMaintenance)
+				if err != nil {
+					return err
+				}
+				err = c.Orderer().SetBatchTimeout(time.Minute)
+				if err != nil {
+					return err
+				}
+				return c.ValidateConsensusMigration()
+			},
+			expectedErr: "config contains other changes, the consensus state must be changed in an update of its own",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+			etcdRaftOrderer.State = tt.state
+			c := ordererConfigTx(t, etcdRaftOrderer)
+
+			err := tt.change(&c)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
+
+// ordererConfigTx returns a ConfigTx for a config containing only an orderer
+// group built from the given orderer config.
+func ordererConfigTx(t *testing.T, o Orderer) ConfigTx {
+	ordererGroup, err := newOrdererGroup(o)
+	if err != nil {
+		t.Fatalf("creating orderer group: %v", err)
+	}
+
+	channelGroup := &cb.ConfigGroup{
+		Groups: map[string]*cb.ConfigGroup{
+			OrdererGroupKey: ordererGroup,
+		},
+		Values: map[string]*cb.ConfigValue{},
+	}
+
+	// SmartBFT requires the V3_0 channel capability.
+	err = setValue(channelGroup, capabilitiesValue([]string{"V3_0"}), AdminsPolicyKey)
+	if err != nil {
+		t.Fatalf("setting channel capabilities: %v", err)
+	}
+
+	return New(&cb.Config{ChannelGroup: channelGroup})
+}
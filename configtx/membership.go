@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
+)
+
+// MSPTrustMaterial is the trust material of an MSP of a channel. The
+// certificates and CRLs are PEM bundles.
+type MSPTrustMaterial struct {
+	MSPID string `json:"msp_id"`
+	// Orgs are the names of the organizations declaring the MSP.
+	Orgs                 []string `json:"orgs"`
+	RootCerts            string   `json:"root_certs"`
+	IntermediateCerts    string   `json:"intermediate_certs,omitempty"`
+	TLSRootCerts         string   `json:"tls_root_certs,omitempty"`
+	TLSIntermediateCerts string   `json:"tls_intermediate_certs,omitempty"`
+	CRLs                 string   `json:"crls,omitempty"`
+}
+
+// MembershipSnapshot is the trust material of every MSP of a channel, sorted
+// by MSP ID.
+type MembershipSnapshot []MSPTrustMaterial
+
+// JSON returns the snapshot encoded as a JSON array.
+func (s MembershipSnapshot) JSON() ([]byte, error) {
+	snapshot := s
+	if snapshot == nil {
+		snapshot = MembershipSnapshot{}
+	}
+
+	return json.MarshalIndent(snapshot, "", "\t")
+}
+
+// MembershipSnapshot returns the trust material of the MSPs of the
+// application, orderer and consortium orgs of the updated config. Orgs
+// declaring the same MSP ID share an entry; the trust material is taken from
+// the first of them, application orgs first.
+func (c *ConfigTx) MembershipSnapshot() (MembershipSnapshot, error) {
+	members := map[string]*MSPTrustMaterial{}
+	var walkErr error
+
+	err := walkOrgMSPs(c.updated.ChannelGroup, func(orgName, path string, msp *MSP) bool {
+		if walkErr != nil {
+			return false
+		}
+
+		if member, ok := members[msp.Name]; ok {
+			if !containsString(member.Orgs, orgName) {
+				member.Orgs = append(member.Orgs, orgName)
+			}
+			return false
+		}
+
+		var crls bytes.Buffer
+		for _, crl := range msp.RevocationList {
+			pemCRL, err := pemEncodeCRL(crl)
+			if err != nil {
+				walkErr = fmt.Errorf("encoding CRL of org %s: %v", orgName, err)
+				return false
+			}
+			crls.Write(pemCRL)
+		}
+
+		members[msp.Name] = &MSPTrustMaterial{
+			MSPID:                msp.Name,
+			Orgs:                 []string{orgName},
+			RootCerts:            pemBundle(msp.RootCerts),
+			IntermediateCerts:    pemBundle(msp.IntermediateCerts),
+			TLSRootCerts:         pemBundle(msp.TLSRootCerts),
+			TLSIntermediateCerts: pemBundle(msp.TLSIntermediateCerts),
+			CRLs:                 crls.String(),
+		}
+
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if walkErr != nil {
+		return nil, walkErr
+	}
+
+	snapshot := make(MembershipSnapshot, 0, len(members))
+	for _, member := range members {
+		snapshot = append(snapshot, *member)
+	}
+	sort.Slice(snapshot, func(i, j int) bool {
+		return snapshot[i].MSPID < snapshot[j].MSPID
+	})
+
+	return snapshot, nil
+}
+
+// pemBundle returns the concatenated PEM encodings of the certificates.
+func pemBundle(certs []*x509.Certificate) string {
+	return string(pemutil.EncodeCertificates(certs))
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestMembershipSnapshot(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channel, _, _ := baseApplicationChannelProfile(t)
+	distinctMSPIDs(channel.Application.Organizations, "")
+	distinctMSPIDs(channel.Orderer.Organizations, "")
+	genesisBlock, err := NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	configEnvelope, _, err := unmarshalConfigBlock(genesisBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(configEnvelope.Config)
+
+	snapshot, err := c.MembershipSnapshot()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	org1MSP, err := c.Application().Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	crl, err := pemEncodeCRL(org1MSP.RevocationList[0])
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var mspIDs []string
+	for _, member := range snapshot {
+		mspIDs = append(mspIDs, member.MSPID)
+	}
+	gt.Expect(mspIDs).To(Equal([]string{"OrdererOrgMSP", "Org1MSP", "Org2MSP"}))
+
+	gt.Expect(snapshot[1]).To(Equal(MSPTrustMaterial{
+		MSPID:                "Org1MSP",
+		Orgs:                 []string{"Org1"},
+		RootCerts:            string(pemEncodeX509Certificate(org1MSP.RootCerts[0])),
+		IntermediateCerts:    string(pemEncodeX509Certificate(org1MSP.IntermediateCerts[0])),
+		TLSRootCerts:         string(pemEncodeX509Certificate(org1MSP.TLSRootCerts[0])),
+		TLSIntermediateCerts: string(pemEncodeX509Certificate(org1MSP.TLSIntermediateCerts[0])),
+		CRLs:                 string(crl),
+	}))
+
+	snapshotJSON, err := snapshot.JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	var decoded MembershipSnapshot
+	gt.Expect(json.Unmarshal(snapshotJSON, &decoded)).To(Succeed())
+	gt.Expect(decoded).To(Equal(snapshot))
+
+	emptyJSON, err := MembershipSnapshot(nil).JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(string(emptyJSON)).To(Equal("[]"))
+}
+
+func TestMembershipSnapshotSharedMSPID(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	snapshot, err := c.MembershipSnapshot()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(snapshot).To(HaveLen(1))
+	gt.Expect(snapshot[0].MSPID).To(Equal("MSPID"))
+	gt.Expect(snapshot[0].Orgs).To(Equal([]string{"Org1", "Org2"}))
+
+	org1MSP, err := c.Application().Organization("Org1").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(snapshot[0].RootCerts).To(Equal(pemBundle(org1MSP.RootCerts)))
+}
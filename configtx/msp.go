@@ -7,20 +7,24 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/rand"
 	"crypto/x509"
 	"crypto/x509/pkix"
-	"encoding/asn1"
 	"encoding/pem"
+	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
+	"sort"
 	"time"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/configtx/membership"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
 )
 
 // MSP is the configuration information for a Fabric MSP.
@@ -72,12 +76,123 @@ type MSP struct {
 	NodeOUs membership.NodeOUs
 }
 
+// Validate checks that the MSP is named, trusts at least one root
+// certificate and that its CA certificates form valid chains.
+func (m *MSP) Validate() error {
+	if m.Name == "" {
+		return errors.New("MSP name is required")
+	}
+
+	if len(m.RootCerts) == 0 {
+		return fmt.Errorf("MSP %s: root certs are required", m.Name)
+	}
+
+	if err := m.validateCACerts(); err != nil {
+		return fmt.Errorf("MSP %s: %v", m.Name, err)
+	}
+
+	if err := m.validateOUIdentifiers(); err != nil {
+		return fmt.Errorf("MSP %s: %v", m.Name, err)
+	}
+
+	return nil
+}
+
 // YEAR is a time duration for a standard 365 day year.
 const YEAR = 365 * 24 * time.Hour
 
 // OrganizationMSP encapsulates the configuration functions used to modify an organization MSP.
 type OrganizationMSP struct {
 	configGroup *cb.ConfigGroup
+	checks      certificateChecks
+	strictAdds  bool
+	observed    observed
+}
+
+// ErrAlreadyExists is returned, possibly wrapped, by the Add methods of an
+// organization MSP in strict mode when the MSP already contains the item to
+// add. Use errors.Is to test for it.
+var ErrAlreadyExists = errors.New("already exists")
+
+// WithStrictAdds returns a copy of the organization MSP whose Add methods
+// return ErrAlreadyExists for certificates, OU identifiers and CRLs the MSP
+// already contains. By default such additions leave the MSP untouched and
+// succeed.
+func (m *OrganizationMSP) WithStrictAdds() *OrganizationMSP {
+	msp := *m
+	msp.strictAdds = true
+
+	return &msp
+}
+
+// alreadyExists returns the error of adding the described item to an MSP
+// which already contains it: nil by default, ErrAlreadyExists in strict mode.
+func (m *OrganizationMSP) alreadyExists(item string) error {
+	if !m.strictAdds {
+		return nil
+	}
+
+	return fmt.Errorf("%s %w", item, ErrAlreadyExists)
+}
+
+// CertificateCheckMode determines how the certificate modifications of an
+// organization MSP treat root and intermediate certs whose KeyUsage lacks
+// x509.KeyUsageCertSign or which are not CA certificates.
+type CertificateCheckMode int
+
+const (
+	// CertificateChecksStrict rejects such certificates. This is the default.
+	CertificateChecksStrict CertificateCheckMode = iota
+
+	// CertificateChecksLenient accepts such certificates and reports each
+	// problem as a warning instead.
+	CertificateChecksLenient
+)
+
+// WithCertificateChecks returns a copy of the organization MSP whose
+// certificate modifications use the given check mode. In lenient mode warn
+// is called with every problem found; if it is nil, problems are logged as
+// warnings instead. Intermediate certs
+// must chain to a root cert of the MSP in either mode.
+func (m *OrganizationMSP) WithCertificateChecks(mode CertificateCheckMode, warn func(error)) *OrganizationMSP {
+	msp := &OrganizationMSP{
+		configGroup: m.configGroup,
+		checks: certificateChecks{
+			mode: mode,
+			warn: warn,
+		},
+		strictAdds: m.strictAdds,
+		observed:   m.observed,
+	}
+	if warn == nil {
+		observed := m.observed
+		msp.checks.warn = func(err error) {
+			observed.warn("certificate check failed", "error", err.Error())
+		}
+	}
+
+	return msp
+}
+
+// certificateChecks holds the check mode of CA certificate validation. The
+// zero value is strict.
+type certificateChecks struct {
+	mode CertificateCheckMode
+	warn func(error)
+}
+
+// report returns err in strict mode. In lenient mode it passes err to the
+// warning callback and returns nil.
+func (c certificateChecks) report(err error) error {
+	if c.mode != CertificateChecksLenient {
+		return err
+	}
+
+	if c.warn != nil {
+		c.warn(err)
+	}
+
+	return nil
 }
 
 // Configuration returns the MSP value for a organization in the updated config.
@@ -86,7 +201,9 @@ func (m *OrganizationMSP) Configuration() (MSP, error) {
 }
 
 // AddAdminCert adds an administator identity to the organization MSP.
-func (m *OrganizationMSP) AddAdminCert(cert *x509.Certificate) error {
+func (m *OrganizationMSP) AddAdminCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.AddAdminCert", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -94,7 +211,7 @@ func (m *OrganizationMSP) AddAdminCert(cert *x509.Certificate) error {
 
 	for _, c := range msp.Admins {
 		if c.Equal(cert) {
-			return nil
+			return m.alreadyExists(certDescription("admin cert", cert))
 		}
 	}
 
@@ -103,8 +220,96 @@ func (m *OrganizationMSP) AddAdminCert(cert *x509.Certificate) error {
 	return msp.setConfig(m.configGroup)
 }
 
+// AddAdminCertsFromPEM adds every certificate of a PEM bundle as an
+// administrator identity of the organization MSP. If a certificate cannot be
+// added, the MSP is left untouched.
+func (m *OrganizationMSP) AddAdminCertsFromPEM(bundle []byte) (err error) {
+	defer m.observe("OrganizationMSP.AddAdminCertsFromPEM", time.Now(), &err)
+
+	return m.addCertsFromPEM(bundle, m.AddAdminCert)
+}
+
+// AddRootCertsFromPEM adds every certificate of a PEM bundle as a root
+// certificate trusted by the organization MSP. If a certificate cannot be
+// added, the MSP is left untouched.
+func (m *OrganizationMSP) AddRootCertsFromPEM(bundle []byte) (err error) {
+	defer m.observe("OrganizationMSP.AddRootCertsFromPEM", time.Now(), &err)
+
+	return m.addCertsFromPEM(bundle, m.AddRootCert)
+}
+
+// AddIntermediateCertsFromPEM adds every certificate of a PEM bundle as an
+// intermediate certificate trusted by the organization MSP. Each certificate
+// must be signed by a root cert of the MSP. If a certificate cannot be added,
+// the MSP is left untouched.
+func (m *OrganizationMSP) AddIntermediateCertsFromPEM(bundle []byte) (err error) {
+	defer m.observe("OrganizationMSP.AddIntermediateCertsFromPEM", time.Now(), &err)
+
+	return m.addCertsFromPEM(bundle, m.AddIntermediateCert)
+}
+
+// AddTLSRootCertsFromPEM adds every certificate of a PEM bundle as a TLS root
+// cert trusted by the organization MSP. If a certificate cannot be added, the
+// MSP is left untouched.
+func (m *OrganizationMSP) AddTLSRootCertsFromPEM(bundle []byte) (err error) {
+	defer m.observe("OrganizationMSP.AddTLSRootCertsFromPEM", time.Now(), &err)
+
+	return m.addCertsFromPEM(bundle, m.AddTLSRootCert)
+}
+
+// AddTLSIntermediateCertsFromPEM adds every certificate of a PEM bundle as a
+// TLS intermediate cert trusted by the organization MSP. Each certificate
+// must chain to a TLS root cert of the MSP. If a certificate cannot be added,
+// the MSP is left untouched.
+func (m *OrganizationMSP) AddTLSIntermediateCertsFromPEM(bundle []byte) (err error) {
+	defer m.observe("OrganizationMSP.AddTLSIntermediateCertsFromPEM", time.Now(), &err)
+
+	return m.addCertsFromPEM(bundle, m.AddTLSIntermediateCert)
+}
+
+// addCertsFromPEM splits the PEM bundle and adds each of its certificates
+// with add, restoring the MSP value if any of them fails.
+func (m *OrganizationMSP) addCertsFromPEM(bundle []byte, add func(*x509.Certificate) error) error {
+	certs, err := pemutil.ParseCertificates(bundle)
+	if err != nil {
+		return fmt.Errorf("parsing PEM bundle: %v", err)
+	}
+
+	original, ok := m.configGroup.Values[MSPKey]
+	if !ok {
+		return errors.New("config does not contain value for MSP")
+	}
+	original = proto.Clone(original).(*cb.ConfigValue)
+
+	for i, cert := range certs {
+		err = add(cert)
+		if err != nil {
+			m.configGroup.Values[MSPKey] = original
+			return fmt.Errorf("certificate %d of PEM bundle: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// certDescription describes a certificate of the given kind in errors.
+func certDescription(kind string, cert *x509.Certificate) string {
+	return fmt.Sprintf("%s with serial number %d", kind, cert.SerialNumber)
+}
+
+// sameCertificate reports whether both certificates are nil or equal.
+func sameCertificate(a, b *x509.Certificate) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.Equal(b)
+}
+
 // RemoveAdminCert removes an administator identity from the organization MSP.
-func (m *OrganizationMSP) RemoveAdminCert(cert *x509.Certificate) error {
+func (m *OrganizationMSP) RemoveAdminCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.RemoveAdminCert", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -124,7 +329,9 @@ func (m *OrganizationMSP) RemoveAdminCert(cert *x509.Certificate) error {
 }
 
 // AddRootCert adds a root certificate trusted by the organization MSP.
-func (m *OrganizationMSP) AddRootCert(cert *x509.Certificate) error {
+func (m *OrganizationMSP) AddRootCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.AddRootCert", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -132,22 +339,26 @@ func (m *OrganizationMSP) AddRootCert(cert *x509.Certificate) error {
 
 	for _, c := range msp.RootCerts {
 		if c.Equal(cert) {
-			return nil
+			return m.alreadyExists(certDescription("root cert", cert))
 		}
 	}
 
 	msp.RootCerts = append(msp.RootCerts, cert)
 
-	err = msp.validateCACerts()
+	err = msp.validateCACertsWith(m.checks)
 	if err != nil {
 		return err
 	}
 
+	m.observed.warnRetainedRootCA(msp.RootCerts[:len(msp.RootCerts)-1], cert)
+
 	return msp.setConfig(m.configGroup)
 }
 
 // RemoveRootCert removes a trusted root certificate from the organization MSP.
-func (m *OrganizationMSP) RemoveRootCert(cert *x509.Certificate) error {
+func (m *OrganizationMSP) RemoveRootCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.RemoveRootCert", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -163,7 +374,7 @@ func (m *OrganizationMSP) RemoveRootCert(cert *x509.Certificate) error {
 
 	msp.RootCerts = certs
 
-	err = msp.validateCACerts()
+	err = msp.validateCACertsWith(m.checks)
 	if err != nil {
 		return err
 	}
@@ -172,30 +383,28 @@ func (m *OrganizationMSP) RemoveRootCert(cert *x509.Certificate) error {
 }
 
 // AddIntermediateCert adds an intermediate certificate trusted by the organization MSP.
-func (m *OrganizationMSP) AddIntermediateCert(cert *x509.Certificate) error {
-	msp, err := getMSPConfig(m.configGroup)
-	if err != nil {
-		return err
-	}
+// The certificate must be signed by a root cert of the MSP.
+func (m *OrganizationMSP) AddIntermediateCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.AddIntermediateCert", time.Now(), &err)
 
-	for _, c := range msp.IntermediateCerts {
-		if c.Equal(cert) {
-			return nil
-		}
-	}
+	return m.addIntermediateCert("intermediate cert", func(msp *MSP) *[]*x509.Certificate { return &msp.IntermediateCerts }, cert, true)
+}
 
-	msp.IntermediateCerts = append(msp.IntermediateCerts, cert)
+// AddStagedIntermediateCert adds an intermediate certificate to the organization
+// MSP without requiring it to be signed by a root cert of the MSP. This supports
+// staged rotations in which the intermediate is added before the root which
+// issued it. The other certificate modifications of the MSP verify the chain of
+// every intermediate, so the issuing root should be added next.
+func (m *OrganizationMSP) AddStagedIntermediateCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.AddStagedIntermediateCert", time.Now(), &err)
 
-	err = msp.validateCACerts()
-	if err != nil {
-		return err
-	}
-
-	return msp.setConfig(m.configGroup)
+	return m.addIntermediateCert("intermediate cert", func(msp *MSP) *[]*x509.Certificate { return &msp.IntermediateCerts }, cert, false)
 }
 
 // RemoveIntermediateCert removes a trusted intermediate certificate from the organization MSP.
-func (m *OrganizationMSP) RemoveIntermediateCert(cert *x509.Certificate) error {
+func (m *OrganizationMSP) RemoveIntermediateCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.RemoveIntermediateCert", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -211,7 +420,7 @@ func (m *OrganizationMSP) RemoveIntermediateCert(cert *x509.Certificate) error {
 
 	msp.IntermediateCerts = certs
 
-	err = msp.validateCACerts()
+	err = msp.validateCACertsWith(m.checks)
 	if err != nil {
 		return err
 	}
@@ -220,15 +429,17 @@ func (m *OrganizationMSP) RemoveIntermediateCert(cert *x509.Certificate) error {
 }
 
 // AddOUIdentifier adds a custom organizational unit identifier to the organization MSP.
-func (m *OrganizationMSP) AddOUIdentifier(ou membership.OUIdentifier) error {
+func (m *OrganizationMSP) AddOUIdentifier(ou membership.OUIdentifier) (err error) {
+	defer m.observe("OrganizationMSP.AddOUIdentifier", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
 	}
 
 	for _, o := range msp.OrganizationalUnitIdentifiers {
-		if reflect.DeepEqual(o, ou) {
-			return nil
+		if o.OrganizationalUnitIdentifier == ou.OrganizationalUnitIdentifier && sameCertificate(o.Certificate, ou.Certificate) {
+			return m.alreadyExists(fmt.Sprintf("ou identifier %s", ou.OrganizationalUnitIdentifier))
 		}
 	}
 
@@ -237,8 +448,40 @@ func (m *OrganizationMSP) AddOUIdentifier(ou membership.OUIdentifier) error {
 	return msp.setConfig(m.configGroup)
 }
 
+// SetOUIdentifiers replaces the custom organizational unit identifiers of the
+// organization MSP. The certificate of each identifier must chain to a root or
+// intermediate cert of the MSP and no identifier may be repeated.
+func (m *OrganizationMSP) SetOUIdentifiers(ous []membership.OUIdentifier) (err error) {
+	defer m.observe("OrganizationMSP.SetOUIdentifiers", time.Now(), &err)
+
+	msp, err := getMSPConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	verify := msp.ouIdentifierVerifier()
+	for i, ou := range ous {
+		for _, o := range ous[:i] {
+			if o.OrganizationalUnitIdentifier == ou.OrganizationalUnitIdentifier && sameCertificate(o.Certificate, ou.Certificate) {
+				return fmt.Errorf("organizational unit identifier '%s' is repeated", ou.OrganizationalUnitIdentifier)
+			}
+		}
+
+		err = verify(fmt.Sprintf("organizational unit identifier '%s'", ou.OrganizationalUnitIdentifier), ou)
+		if err != nil {
+			return err
+		}
+	}
+
+	msp.OrganizationalUnitIdentifiers = ous
+
+	return msp.setConfig(m.configGroup)
+}
+
 // RemoveOUIdentifier removes an existing organizational unit identifier from the organization MSP.
-func (m *OrganizationMSP) RemoveOUIdentifier(ou membership.OUIdentifier) error {
+func (m *OrganizationMSP) RemoveOUIdentifier(ou membership.OUIdentifier) (err error) {
+	defer m.observe("OrganizationMSP.RemoveOUIdentifier", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -258,7 +501,9 @@ func (m *OrganizationMSP) RemoveOUIdentifier(ou membership.OUIdentifier) error {
 }
 
 // SetCryptoConfig sets the configuration for the cryptographic algorithms for the organization MSP.
-func (m *OrganizationMSP) SetCryptoConfig(cryptoConfig membership.CryptoConfig) error {
+func (m *OrganizationMSP) SetCryptoConfig(cryptoConfig membership.CryptoConfig) (err error) {
+	defer m.observe("OrganizationMSP.SetCryptoConfig", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -270,7 +515,9 @@ func (m *OrganizationMSP) SetCryptoConfig(cryptoConfig membership.CryptoConfig)
 }
 
 // AddTLSRootCert adds a TLS root certificate trusted by the organization MSP.
-func (m *OrganizationMSP) AddTLSRootCert(cert *x509.Certificate) error {
+func (m *OrganizationMSP) AddTLSRootCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.AddTLSRootCert", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -278,22 +525,26 @@ func (m *OrganizationMSP) AddTLSRootCert(cert *x509.Certificate) error {
 
 	for _, c := range msp.TLSRootCerts {
 		if c.Equal(cert) {
-			return nil
+			return m.alreadyExists(certDescription("tls root cert", cert))
 		}
 	}
 
 	msp.TLSRootCerts = append(msp.TLSRootCerts, cert)
 
-	err = msp.validateCACerts()
+	err = msp.validateCACertsWith(m.checks)
 	if err != nil {
 		return err
 	}
 
+	m.observed.warnRetainedRootCA(msp.TLSRootCerts[:len(msp.TLSRootCerts)-1], cert)
+
 	return msp.setConfig(m.configGroup)
 }
 
 // RemoveTLSRootCert removes a trusted TLS root certificate from the organization MSP.
-func (m *OrganizationMSP) RemoveTLSRootCert(cert *x509.Certificate) error {
+func (m *OrganizationMSP) RemoveTLSRootCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.RemoveTLSRootCert", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -309,7 +560,7 @@ func (m *OrganizationMSP) RemoveTLSRootCert(cert *x509.Certificate) error {
 
 	msp.TLSRootCerts = certs
 
-	err = msp.validateCACerts()
+	err = msp.validateCACertsWith(m.checks)
 	if err != nil {
 		return err
 	}
@@ -318,21 +569,45 @@ func (m *OrganizationMSP) RemoveTLSRootCert(cert *x509.Certificate) error {
 }
 
 // AddTLSIntermediateCert adds a TLS intermediate cert trusted by the organization MSP.
-func (m *OrganizationMSP) AddTLSIntermediateCert(cert *x509.Certificate) error {
+// The certificate must chain to a TLS root cert of the MSP.
+func (m *OrganizationMSP) AddTLSIntermediateCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.AddTLSIntermediateCert", time.Now(), &err)
+
+	return m.addIntermediateCert("tls intermediate cert", func(msp *MSP) *[]*x509.Certificate { return &msp.TLSIntermediateCerts }, cert, true)
+}
+
+// AddStagedTLSIntermediateCert adds a TLS intermediate cert to the organization
+// MSP without requiring it to chain to a TLS root cert of the MSP, for staged
+// rotations in which the TLS root is added afterwards.
+func (m *OrganizationMSP) AddStagedTLSIntermediateCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.AddStagedTLSIntermediateCert", time.Now(), &err)
+
+	return m.addIntermediateCert("tls intermediate cert", func(msp *MSP) *[]*x509.Certificate { return &msp.TLSIntermediateCerts }, cert, false)
+}
+
+// addIntermediateCert adds cert to the intermediate certificate list of the MSP
+// selected by field. If verifyChain is false, the intermediate certs are only
+// checked to be valid CA certs, not to chain to a root cert of the MSP.
+func (m *OrganizationMSP) addIntermediateCert(kind string, field func(*MSP) *[]*x509.Certificate, cert *x509.Certificate, verifyChain bool) error {
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
 	}
 
-	for _, c := range msp.TLSIntermediateCerts {
+	certs := field(&msp)
+	for _, c := range *certs {
 		if c.Equal(cert) {
-			return nil
+			return m.alreadyExists(certDescription(kind, cert))
 		}
 	}
 
-	msp.TLSIntermediateCerts = append(msp.TLSIntermediateCerts, cert)
+	*certs = append(*certs, cert)
 
-	err = msp.validateCACerts()
+	if verifyChain {
+		err = msp.validateCACertsWith(m.checks)
+	} else {
+		err = msp.validateCACertsIgnoringChains(m.checks)
+	}
 	if err != nil {
 		return err
 	}
@@ -341,7 +616,9 @@ func (m *OrganizationMSP) AddTLSIntermediateCert(cert *x509.Certificate) error {
 }
 
 // RemoveTLSIntermediateCert removes a trusted TLS intermediate cert from the organization MSP.
-func (m *OrganizationMSP) RemoveTLSIntermediateCert(cert *x509.Certificate) error {
+func (m *OrganizationMSP) RemoveTLSIntermediateCert(cert *x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.RemoveTLSIntermediateCert", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -357,7 +634,112 @@ func (m *OrganizationMSP) RemoveTLSIntermediateCert(cert *x509.Certificate) erro
 
 	msp.TLSIntermediateCerts = certs
 
-	err = msp.validateCACerts()
+	err = msp.validateCACertsWith(m.checks)
+	if err != nil {
+		return err
+	}
+
+	return msp.setConfig(m.configGroup)
+}
+
+// CertificateMatcher selects certificates, e.g. to remove them from an MSP
+// without holding a byte-identical copy of the certificate.
+type CertificateMatcher func(cert *x509.Certificate) bool
+
+// MatchCertificate matches certificates equal to cert.
+func MatchCertificate(cert *x509.Certificate) CertificateMatcher {
+	return func(c *x509.Certificate) bool {
+		return c.Equal(cert)
+	}
+}
+
+// MatchSerialNumber matches certificates with the given serial number.
+func MatchSerialNumber(serialNumber *big.Int) CertificateMatcher {
+	return func(c *x509.Certificate) bool {
+		return c.SerialNumber != nil && c.SerialNumber.Cmp(serialNumber) == 0
+	}
+}
+
+// MatchSubjectKeyID matches certificates with the given subject key
+// identifier.
+func MatchSubjectKeyID(subjectKeyID []byte) CertificateMatcher {
+	return func(c *x509.Certificate) bool {
+		return len(c.SubjectKeyId) > 0 && bytes.Equal(c.SubjectKeyId, subjectKeyID)
+	}
+}
+
+// MatchSubject matches certificates whose subject, in the RFC 2253 string
+// representation returned by pkix.Name.String, equals subject.
+func MatchSubject(subject string) CertificateMatcher {
+	return func(c *x509.Certificate) bool {
+		return c.Subject.String() == subject
+	}
+}
+
+// RemoveAdminCertMatching removes the administrator identities matched by
+// matcher from the organization MSP.
+func (m *OrganizationMSP) RemoveAdminCertMatching(matcher CertificateMatcher) (err error) {
+	defer m.observe("OrganizationMSP.RemoveAdminCertMatching", time.Now(), &err)
+
+	return m.removeCertsMatching("admin", func(msp *MSP) *[]*x509.Certificate { return &msp.Admins }, matcher)
+}
+
+// RemoveRootCertMatching removes the trusted root certificates matched by
+// matcher from the organization MSP.
+func (m *OrganizationMSP) RemoveRootCertMatching(matcher CertificateMatcher) (err error) {
+	defer m.observe("OrganizationMSP.RemoveRootCertMatching", time.Now(), &err)
+
+	return m.removeCertsMatching("root", func(msp *MSP) *[]*x509.Certificate { return &msp.RootCerts }, matcher)
+}
+
+// RemoveIntermediateCertMatching removes the trusted intermediate
+// certificates matched by matcher from the organization MSP.
+func (m *OrganizationMSP) RemoveIntermediateCertMatching(matcher CertificateMatcher) (err error) {
+	defer m.observe("OrganizationMSP.RemoveIntermediateCertMatching", time.Now(), &err)
+
+	return m.removeCertsMatching("intermediate", func(msp *MSP) *[]*x509.Certificate { return &msp.IntermediateCerts }, matcher)
+}
+
+// RemoveTLSRootCertMatching removes the trusted TLS root certificates matched
+// by matcher from the organization MSP.
+func (m *OrganizationMSP) RemoveTLSRootCertMatching(matcher CertificateMatcher) (err error) {
+	defer m.observe("OrganizationMSP.RemoveTLSRootCertMatching", time.Now(), &err)
+
+	return m.removeCertsMatching("tls root", func(msp *MSP) *[]*x509.Certificate { return &msp.TLSRootCerts }, matcher)
+}
+
+// RemoveTLSIntermediateCertMatching removes the trusted TLS intermediate
+// certificates matched by matcher from the organization MSP.
+func (m *OrganizationMSP) RemoveTLSIntermediateCertMatching(matcher CertificateMatcher) (err error) {
+	defer m.observe("OrganizationMSP.RemoveTLSIntermediateCertMatching", time.Now(), &err)
+
+	return m.removeCertsMatching("tls intermediate", func(msp *MSP) *[]*x509.Certificate { return &msp.TLSIntermediateCerts }, matcher)
+}
+
+// removeCertsMatching removes the certificates matched by matcher from the
+// certificate list of the MSP selected by field. It is an error if no
+// certificate matches.
+func (m *OrganizationMSP) removeCertsMatching(kind string, field func(*MSP) *[]*x509.Certificate, matcher CertificateMatcher) error {
+	msp, err := getMSPConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	certs := field(&msp)
+	var remaining []*x509.Certificate
+	for _, c := range *certs {
+		if !matcher(c) {
+			remaining = append(remaining, c)
+		}
+	}
+
+	if len(remaining) == len(*certs) {
+		return fmt.Errorf("no %s cert matches", kind)
+	}
+
+	*certs = remaining
+
+	err = msp.validateCACertsWith(m.checks)
 	if err != nil {
 		return err
 	}
@@ -366,7 +748,9 @@ func (m *OrganizationMSP) RemoveTLSIntermediateCert(cert *x509.Certificate) erro
 }
 
 // SetClientOUIdentifier sets the NodeOUs client ou identifier for the organization MSP.
-func (m *OrganizationMSP) SetClientOUIdentifier(clientOU membership.OUIdentifier) error {
+func (m *OrganizationMSP) SetClientOUIdentifier(clientOU membership.OUIdentifier) (err error) {
+	defer m.observe("OrganizationMSP.SetClientOUIdentifier", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -378,7 +762,9 @@ func (m *OrganizationMSP) SetClientOUIdentifier(clientOU membership.OUIdentifier
 }
 
 // SetPeerOUIdentifier sets the NodeOUs peer ou identifier for the organization MSP.
-func (m *OrganizationMSP) SetPeerOUIdentifier(peerOU membership.OUIdentifier) error {
+func (m *OrganizationMSP) SetPeerOUIdentifier(peerOU membership.OUIdentifier) (err error) {
+	defer m.observe("OrganizationMSP.SetPeerOUIdentifier", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -390,7 +776,9 @@ func (m *OrganizationMSP) SetPeerOUIdentifier(peerOU membership.OUIdentifier) er
 }
 
 // SetAdminOUIdentifier sets the NodeOUs admin ou identifier for the organization MSP.
-func (m *OrganizationMSP) SetAdminOUIdentifier(adminOU membership.OUIdentifier) error {
+func (m *OrganizationMSP) SetAdminOUIdentifier(adminOU membership.OUIdentifier) (err error) {
+	defer m.observe("OrganizationMSP.SetAdminOUIdentifier", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -402,7 +790,9 @@ func (m *OrganizationMSP) SetAdminOUIdentifier(adminOU membership.OUIdentifier)
 }
 
 // SetOrdererOUIdentifier sets the NodeOUs orderer ou identifier for the organization MSP.
-func (m *OrganizationMSP) SetOrdererOUIdentifier(ordererOU membership.OUIdentifier) error {
+func (m *OrganizationMSP) SetOrdererOUIdentifier(ordererOU membership.OUIdentifier) (err error) {
+	defer m.observe("OrganizationMSP.SetOrdererOUIdentifier", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -415,7 +805,9 @@ func (m *OrganizationMSP) SetOrdererOUIdentifier(ordererOU membership.OUIdentifi
 
 // SetEnableNodeOUs sets the NodeOUs recognition, if NodeOUs recognition is enabled then an msp identity
 // that does not contain exactly one of the fabric Node OU Identifiers will be considered invalid.
-func (m *OrganizationMSP) SetEnableNodeOUs(isEnabled bool) error {
+func (m *OrganizationMSP) SetEnableNodeOUs(isEnabled bool) (err error) {
+	defer m.observe("OrganizationMSP.SetEnableNodeOUs", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -427,12 +819,20 @@ func (m *OrganizationMSP) SetEnableNodeOUs(isEnabled bool) error {
 }
 
 // AddCRL adds a CRL to the identity revocation list for the organization MSP.
-func (m *OrganizationMSP) AddCRL(crl *pkix.CertificateList) error {
+func (m *OrganizationMSP) AddCRL(crl *pkix.CertificateList) (err error) {
+	defer m.observe("OrganizationMSP.AddCRL", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
 	}
 
+	for _, c := range msp.RevocationList {
+		if len(c.TBSCertList.Raw) > 0 && bytes.Equal(c.TBSCertList.Raw, crl.TBSCertList.Raw) {
+			return m.alreadyExists(fmt.Sprintf("crl issued by %s", crl.TBSCertList.Issuer))
+		}
+	}
+
 	msp.RevocationList = append(msp.RevocationList, crl)
 
 	return msp.setConfig(m.configGroup)
@@ -440,7 +840,9 @@ func (m *OrganizationMSP) AddCRL(crl *pkix.CertificateList) error {
 
 // AddCRLFromSigningIdentity creates a CRL from the provided signing identity and associated certs and then adds the CRL to
 // the identity revocation list for the organization MSP.
-func (m *OrganizationMSP) AddCRLFromSigningIdentity(signingIdentity *SigningIdentity, certs ...*x509.Certificate) error {
+func (m *OrganizationMSP) AddCRLFromSigningIdentity(signingIdentity *SigningIdentity, certs ...*x509.Certificate) (err error) {
+	defer m.observe("OrganizationMSP.AddCRLFromSigningIdentity", time.Now(), &err)
+
 	msp, err := getMSPConfig(m.configGroup)
 	if err != nil {
 		return err
@@ -493,6 +895,100 @@ func (m *MSP) newMSPCRL(signingIdentity *SigningIdentity, certs ...*x509.Certifi
 	return crl, nil
 }
 
+// CompactCRLs replaces the CRLs of the organization MSP which were issued by
+// the signing identity with a single CRL produced by MergeCRLs. CRLs issued by
+// other CAs are left untouched.
+func (m *OrganizationMSP) CompactCRLs(signingIdentity *SigningIdentity, maxCertLifetime time.Duration) (err error) {
+	defer m.observe("OrganizationMSP.CompactCRLs", time.Now(), &err)
+
+	if signingIdentity == nil {
+		return errors.New("signing identity is required")
+	}
+
+	msp, err := getMSPConfig(m.configGroup)
+	if err != nil {
+		return err
+	}
+
+	var issued, others []*pkix.CertificateList
+	for _, crl := range msp.RevocationList {
+		if signingIdentity.Certificate.CheckCRLSignature(crl) == nil {
+			issued = append(issued, crl)
+			continue
+		}
+		others = append(others, crl)
+	}
+
+	if len(issued) == 0 {
+		return nil
+	}
+
+	merged, err := msp.MergeCRLs(signingIdentity, maxCertLifetime, issued...)
+	if err != nil {
+		return err
+	}
+	msp.RevocationList = append(others, merged)
+
+	return msp.setConfig(m.configGroup)
+}
+
+// MergeCRLs merges the provided CRLs, which must have been issued by the CA
+// of the signing identity, into a single CRL signed by the signing identity.
+// Certificates revoked by more than one CRL are listed once with their earliest
+// revocation time. If maxCertLifetime is positive, entries revoked longer than
+// maxCertLifetime ago are dropped, as the certificates they refer to have
+// expired by then and can no longer be used.
+func (m *MSP) MergeCRLs(signingIdentity *SigningIdentity, maxCertLifetime time.Duration, crls ...*pkix.CertificateList) (*pkix.CertificateList, error) {
+	if signingIdentity == nil {
+		return nil, errors.New("signing identity is required")
+	}
+
+	if err := m.isCACert(signingIdentity.Certificate); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+
+	revoked := map[string]pkix.RevokedCertificate{}
+	for i, crl := range crls {
+		if err := signingIdentity.Certificate.CheckCRLSignature(crl); err != nil {
+			return nil, fmt.Errorf("CRL at index %d was not issued by the signing identity: %v", i, err)
+		}
+
+		for _, entry := range crl.TBSCertList.RevokedCertificates {
+			if maxCertLifetime > 0 && entry.RevocationTime.Add(maxCertLifetime).Before(now) {
+				continue
+			}
+
+			serial := entry.SerialNumber.String()
+			if existing, ok := revoked[serial]; ok && !entry.RevocationTime.Before(existing.RevocationTime) {
+				continue
+			}
+			revoked[serial] = entry
+		}
+	}
+
+	revokedCertificates := make([]pkix.RevokedCertificate, 0, len(revoked))
+	for _, entry := range revoked {
+		revokedCertificates = append(revokedCertificates, entry)
+	}
+	sort.Slice(revokedCertificates, func(i, j int) bool {
+		return revokedCertificates[i].SerialNumber.Cmp(revokedCertificates[j].SerialNumber) < 0
+	})
+
+	crlBytes, err := signingIdentity.Certificate.CreateCRL(rand.Reader, signingIdentity.PrivateKey, revokedCertificates, now, now.Add(YEAR))
+	if err != nil {
+		return nil, err
+	}
+
+	crl, err := x509.ParseCRL(crlBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return crl, nil
+}
+
 // validateCertificates first validates that the signing certificate is either
 // a root or intermediate CA certificate for the specified application org. It
 // then validates that the certificates to add to the CRL were signed by that
@@ -602,22 +1098,22 @@ func getMSPConfig(configGroup *cb.ConfigGroup) (MSP, error) {
 	// NODE OUS
 	nodeOUs := membership.NodeOUs{}
 	if fabricMSPConfig.FabricNodeOus != nil {
-		clientOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.ClientOuIdentifier.Certificate)
+		clientOUIdentifierCert, err := parseNodeOUIdentifierCertificate(fabricMSPConfig.FabricNodeOus.ClientOuIdentifier.Certificate)
 		if err != nil {
 			return MSP{}, fmt.Errorf("parsing client ou identifier cert: %v", err)
 		}
 
-		peerOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.PeerOuIdentifier.Certificate)
+		peerOUIdentifierCert, err := parseNodeOUIdentifierCertificate(fabricMSPConfig.FabricNodeOus.PeerOuIdentifier.Certificate)
 		if err != nil {
 			return MSP{}, fmt.Errorf("parsing peer ou identifier cert: %v", err)
 		}
 
-		adminOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.AdminOuIdentifier.Certificate)
+		adminOUIdentifierCert, err := parseNodeOUIdentifierCertificate(fabricMSPConfig.FabricNodeOus.AdminOuIdentifier.Certificate)
 		if err != nil {
 			return MSP{}, fmt.Errorf("parsing admin ou identifier cert: %v", err)
 		}
 
-		ordererOUIdentifierCert, err := parseCertificateFromBytes(fabricMSPConfig.FabricNodeOus.OrdererOuIdentifier.Certificate)
+		ordererOUIdentifierCert, err := parseNodeOUIdentifierCertificate(fabricMSPConfig.FabricNodeOus.OrdererOuIdentifier.Certificate)
 		if err != nil {
 			return MSP{}, fmt.Errorf("parsing orderer ou identifier cert: %v", err)
 		}
@@ -664,6 +1160,18 @@ func parseCertificateListFromBytes(certs [][]byte) ([]*x509.Certificate, error)
 	certificateList := []*x509.Certificate{}
 
 	for _, cert := range certs {
+		// Entries may be PEM bundles of several certificates, as emitted
+		// for CA chains by fabric-ca and cryptogen.
+		if isPEMBundle(cert) {
+			bundle, err := pemutil.ParseCertificates(cert)
+			if err != nil {
+				return certificateList, err
+			}
+
+			certificateList = append(certificateList, bundle...)
+			continue
+		}
+
 		certificate, err := parseCertificateFromBytes(cert)
 		if err != nil {
 			return certificateList, err
@@ -676,12 +1184,7 @@ func parseCertificateListFromBytes(certs [][]byte) ([]*x509.Certificate, error)
 }
 
 func parseCertificateFromBytes(cert []byte) (*x509.Certificate, error) {
-	pemBlock, _ := pem.Decode(cert)
-	if pemBlock == nil {
-		return &x509.Certificate{}, fmt.Errorf("no PEM data found in cert[% x]", cert)
-	}
-
-	certificate, err := x509.ParseCertificate(pemBlock.Bytes)
+	certificate, err := pemutil.ParseCertificate(cert)
 	if err != nil {
 		return &x509.Certificate{}, err
 	}
@@ -689,18 +1192,52 @@ func parseCertificateFromBytes(cert []byte) (*x509.Certificate, error) {
 	return certificate, nil
 }
 
+// parseNodeOUIdentifierCertificate parses the certificate of a NodeOU
+// identifier. Unlike that of an organizational unit identifier, it is
+// optional: Fabric then classifies identities by OU alone.
+func parseNodeOUIdentifierCertificate(cert []byte) (*x509.Certificate, error) {
+	if len(cert) == 0 {
+		return nil, nil
+	}
+
+	return parseCertificateFromBytes(cert)
+}
+
+// parseLeafCertificate parses the first certificate of a PEM encoded
+// certificate or bundle. The other certificates of a bundle, such as the
+// chain of the first one, must be valid but are otherwise ignored.
+func parseLeafCertificate(cert []byte) (*x509.Certificate, error) {
+	if !isPEMBundle(cert) {
+		return parseCertificateFromBytes(cert)
+	}
+
+	bundle, err := pemutil.ParseCertificates(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	return bundle[0], nil
+}
+
+// isPEMBundle returns whether data contains more than one PEM block.
+func isPEMBundle(data []byte) bool {
+	block, rest := pem.Decode(data)
+	if block == nil {
+		return false
+	}
+
+	block, _ = pem.Decode(rest)
+
+	return block != nil
+}
+
 func parseCRL(crls [][]byte) ([]*pkix.CertificateList, error) {
 	certificateLists := []*pkix.CertificateList{}
 
 	for _, crl := range crls {
-		pemBlock, _ := pem.Decode(crl)
-		if pemBlock == nil {
-			return certificateLists, fmt.Errorf("no PEM data found in CRL[% x]", crl)
-		}
-
-		certificateList, err := x509.ParseCRL(pemBlock.Bytes)
+		certificateList, err := pemutil.ParseCRL(crl)
 		if err != nil {
-			return certificateLists, fmt.Errorf("parsing crl: %v", err)
+			return certificateLists, err
 		}
 
 		certificateLists = append(certificateLists, certificateList)
@@ -714,17 +1251,7 @@ func parsePrivateKeyFromBytes(priv []byte) (crypto.PrivateKey, error) {
 		return nil, nil
 	}
 
-	pemBlock, _ := pem.Decode(priv)
-	if pemBlock == nil {
-		return nil, fmt.Errorf("no PEM data found in private key[% x]", priv)
-	}
-
-	privateKey, err := x509.ParsePKCS8PrivateKey(pemBlock.Bytes)
-	if err != nil {
-		return nil, fmt.Errorf("failed parsing PKCS#8 private key: %v", err)
-	}
-
-	return privateKey, nil
+	return pemutil.ParsePrivateKey(priv)
 }
 
 func parseOUIdentifiers(identifiers []*mb.FabricOUIdentifier) ([]membership.OUIdentifier, error) {
@@ -755,26 +1282,29 @@ func (m *MSP) toProto() (*mb.FabricMSPConfig, error) {
 		return nil, fmt.Errorf("building pem encoded revocation list: %v", err)
 	}
 
-	ouIdentifiers := buildOUIdentifiers(m.OrganizationalUnitIdentifiers)
+	ouIdentifiers, err := buildOUIdentifiers(m.OrganizationalUnitIdentifiers)
+	if err != nil {
+		return nil, err
+	}
 
 	var fabricNodeOUs *mb.FabricNodeOUs
 	if m.NodeOUs != (membership.NodeOUs{}) {
 		fabricNodeOUs = &mb.FabricNodeOUs{
 			Enable: m.NodeOUs.Enable,
 			ClientOuIdentifier: &mb.FabricOUIdentifier{
-				Certificate:                  pemEncodeX509Certificate(m.NodeOUs.ClientOUIdentifier.Certificate),
+				Certificate:                  pemEncodeNodeOUIdentifierCertificate(m.NodeOUs.ClientOUIdentifier.Certificate),
 				OrganizationalUnitIdentifier: m.NodeOUs.ClientOUIdentifier.OrganizationalUnitIdentifier,
 			},
 			PeerOuIdentifier: &mb.FabricOUIdentifier{
-				Certificate:                  pemEncodeX509Certificate(m.NodeOUs.PeerOUIdentifier.Certificate),
+				Certificate:                  pemEncodeNodeOUIdentifierCertificate(m.NodeOUs.PeerOUIdentifier.Certificate),
 				OrganizationalUnitIdentifier: m.NodeOUs.PeerOUIdentifier.OrganizationalUnitIdentifier,
 			},
 			AdminOuIdentifier: &mb.FabricOUIdentifier{
-				Certificate:                  pemEncodeX509Certificate(m.NodeOUs.AdminOUIdentifier.Certificate),
+				Certificate:                  pemEncodeNodeOUIdentifierCertificate(m.NodeOUs.AdminOUIdentifier.Certificate),
 				OrganizationalUnitIdentifier: m.NodeOUs.AdminOUIdentifier.OrganizationalUnitIdentifier,
 			},
 			OrdererOuIdentifier: &mb.FabricOUIdentifier{
-				Certificate:                  pemEncodeX509Certificate(m.NodeOUs.OrdererOUIdentifier.Certificate),
+				Certificate:                  pemEncodeNodeOUIdentifierCertificate(m.NodeOUs.OrdererOUIdentifier.Certificate),
 				OrganizationalUnitIdentifier: m.NodeOUs.OrdererOUIdentifier.OrganizationalUnitIdentifier,
 			},
 		}
@@ -797,10 +1327,14 @@ func (m *MSP) toProto() (*mb.FabricMSPConfig, error) {
 	}, nil
 }
 
-func buildOUIdentifiers(identifiers []membership.OUIdentifier) []*mb.FabricOUIdentifier {
+func buildOUIdentifiers(identifiers []membership.OUIdentifier) ([]*mb.FabricOUIdentifier, error) {
 	fabricIdentifiers := []*mb.FabricOUIdentifier{}
 
 	for _, identifier := range identifiers {
+		if identifier.Certificate == nil {
+			return nil, fmt.Errorf("organizational unit identifier '%s' has no certificate", identifier.OrganizationalUnitIdentifier)
+		}
+
 		fabricOUIdentifier := &mb.FabricOUIdentifier{
 			Certificate:                  pemEncodeX509Certificate(identifier.Certificate),
 			OrganizationalUnitIdentifier: identifier.OrganizationalUnitIdentifier,
@@ -809,7 +1343,17 @@ func buildOUIdentifiers(identifiers []membership.OUIdentifier) []*mb.FabricOUIde
 		fabricIdentifiers = append(fabricIdentifiers, fabricOUIdentifier)
 	}
 
-	return fabricIdentifiers
+	return fabricIdentifiers, nil
+}
+
+// pemEncodeNodeOUIdentifierCertificate returns the PEM encoding of the
+// optional certificate of a NodeOU identifier.
+func pemEncodeNodeOUIdentifierCertificate(cert *x509.Certificate) []byte {
+	if cert == nil {
+		return nil
+	}
+
+	return pemEncodeX509Certificate(cert)
 }
 
 // buildPemEncodedRevocationList returns a byte slice of the pem-encoded
@@ -831,11 +1375,7 @@ func buildPemEncodedRevocationList(crls []*pkix.CertificateList) ([][]byte, erro
 }
 
 func pemEncodeCRL(crl *pkix.CertificateList) ([]byte, error) {
-	asn1MarshalledBytes, err := asn1.Marshal(*crl)
-	if err != nil {
-		return nil, err
-	}
-	return pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: asn1MarshalledBytes}), nil
+	return pemutil.EncodeCRL(crl)
 }
 
 func buildPemEncodedCertListFromX509(certList []*x509.Certificate) [][]byte {
@@ -848,16 +1388,11 @@ func buildPemEncodedCertListFromX509(certList []*x509.Certificate) [][]byte {
 }
 
 func pemEncodeX509Certificate(cert *x509.Certificate) []byte {
-	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	return pemutil.EncodeCertificate(cert)
 }
 
 func pemEncodePKCS8PrivateKey(priv crypto.PrivateKey) ([]byte, error) {
-	privBytes, err := x509.MarshalPKCS8PrivateKey(priv)
-	if err != nil {
-		return nil, fmt.Errorf("marshaling PKCS#8 private key: %v", err)
-	}
-
-	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privBytes}), nil
+	return pemutil.EncodePrivateKey(priv)
 }
 
 // newMSPConfig returns an config for a msp.
@@ -880,14 +1415,16 @@ func newMSPConfig(updatedMSP MSP) (*mb.MSPConfig, error) {
 }
 
 func (m *MSP) validateCACerts() error {
-	err := validateCACerts(m.RootCerts)
-	if err != nil {
-		return fmt.Errorf("invalid root cert: %v", err)
-	}
+	return m.validateCACertsWith(certificateChecks{})
+}
 
-	err = validateCACerts(m.IntermediateCerts)
+// validateCACertsWith checks that the root and intermediate certs of the MSP
+// are valid CA certs according to checks and that the intermediates chain to
+// the roots.
+func (m *MSP) validateCACertsWith(checks certificateChecks) error {
+	err := m.validateCACertsIgnoringChains(checks)
 	if err != nil {
-		return fmt.Errorf("invalid intermediate cert: %v", err)
+		return err
 	}
 
 	// TODO: follow the workaround that msp code use to incorporate cert.Verify()
@@ -905,16 +1442,6 @@ func (m *MSP) validateCACerts() error {
 		}
 	}
 
-	err = validateCACerts(m.TLSRootCerts)
-	if err != nil {
-		return fmt.Errorf("invalid tls root cert: %v", err)
-	}
-
-	err = validateCACerts(m.TLSIntermediateCerts)
-	if err != nil {
-		return fmt.Errorf("invalid tls intermediate cert: %v", err)
-	}
-
 	tlsRootPool := x509.NewCertPool()
 	for _, rootCert := range m.TLSRootCerts {
 		tlsRootPool.AddCert(rootCert)
@@ -932,14 +1459,101 @@ func (m *MSP) validateCACerts() error {
 	return nil
 }
 
-func validateCACerts(caCerts []*x509.Certificate) error {
+// validateCACertsIgnoringChains checks that the root and intermediate certs of
+// the MSP are valid CA certs according to checks without checking that the
+// intermediates chain to the roots.
+func (m *MSP) validateCACertsIgnoringChains(checks certificateChecks) error {
+	err := validateCACerts("root", m.RootCerts, checks)
+	if err != nil {
+		return err
+	}
+
+	err = validateCACerts("intermediate", m.IntermediateCerts, checks)
+	if err != nil {
+		return err
+	}
+
+	err = validateCACerts("tls root", m.TLSRootCerts, checks)
+	if err != nil {
+		return err
+	}
+
+	err = validateCACerts("tls intermediate", m.TLSIntermediateCerts, checks)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// validateOUIdentifiers checks that every organizational unit identifier has
+// a certificate and that the certificates of the NodeOU and organizational
+// unit identifiers chain to the root and intermediate certs of the MSP.
+// Fabric ignores identifiers whose certificate does not, so identities
+// silently fail to be classified.
+func (m *MSP) validateOUIdentifiers() error {
+	verify := m.ouIdentifierVerifier()
+
+	nodeOUs := []struct {
+		name string
+		ou   membership.OUIdentifier
+	}{
+		{name: "client OU identifier", ou: m.NodeOUs.ClientOUIdentifier},
+		{name: "peer OU identifier", ou: m.NodeOUs.PeerOUIdentifier},
+		{name: "admin OU identifier", ou: m.NodeOUs.AdminOUIdentifier},
+		{name: "orderer OU identifier", ou: m.NodeOUs.OrdererOUIdentifier},
+	}
+	for _, nodeOU := range nodeOUs {
+		if err := verify(nodeOU.name, nodeOU.ou); err != nil {
+			return err
+		}
+	}
+
+	for _, ou := range m.OrganizationalUnitIdentifiers {
+		if ou.Certificate == nil {
+			return fmt.Errorf("organizational unit identifier '%s' has no certificate", ou.OrganizationalUnitIdentifier)
+		}
+		if err := verify(fmt.Sprintf("organizational unit identifier '%s'", ou.OrganizationalUnitIdentifier), ou); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ouIdentifierVerifier returns a function checking that the certificate of
+// an OU identifier, if any, chains to a root or intermediate cert of the MSP.
+func (m *MSP) ouIdentifierVerifier() func(name string, ou membership.OUIdentifier) error {
+	verify := verifyChain(m.RootCerts, m.IntermediateCerts, true)
+
+	return func(name string, ou membership.OUIdentifier) error {
+		if ou.Certificate == nil {
+			return nil
+		}
+
+		err := verify(ou.Certificate)
+		if err != nil {
+			return fmt.Errorf("%s certificate does not chain to a root or intermediate cert of this MSP. serial number: %d: %v", name, ou.Certificate.SerialNumber, err)
+		}
+
+		return nil
+	}
+}
+
+func validateCACerts(kind string, caCerts []*x509.Certificate, checks certificateChecks) error {
 	for _, caCert := range caCerts {
 		if (caCert.KeyUsage & x509.KeyUsageCertSign) == 0 {
-			return fmt.Errorf("KeyUsage must be x509.KeyUsageCertSign. serial number: %d", caCert.SerialNumber)
+			err := checks.report(fmt.Errorf("invalid %s cert: KeyUsage must be x509.KeyUsageCertSign. serial number: %d", kind, caCert.SerialNumber))
+			if err != nil {
+				return err
+			}
 		}
 
 		if !caCert.IsCA {
-			return fmt.Errorf("must be a CA certificate. serial number: %d", caCert.SerialNumber)
+			err := checks.report(fmt.Errorf("invalid %s cert: must be a CA certificate. serial number: %d", kind, caCert.SerialNumber))
+			if err != nil {
+				return err
+			}
 		}
 	}
 
@@ -13,6 +13,8 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -23,6 +25,7 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/configtx/membership"
 	"github.com/hyperledger/fabric-config/configtx/orderer"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
 	"github.com/hyperledger/fabric-config/protolator"
 	. "github.com/onsi/gomega"
 )
@@ -326,6 +329,64 @@ func TestMSPToProtoNoNodeOUs(t *testing.T) {
 	gt.Expect(fabricMSPConfigProto).To(Equal(expectedFabricMSPConfigProto))
 }
 
+func TestMSPConfigurationWithoutNodeOUCertificates(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	msp, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	msp.NodeOUs = membership.NodeOUs{
+		Enable:              true,
+		ClientOUIdentifier:  membership.OUIdentifier{OrganizationalUnitIdentifier: "client"},
+		PeerOUIdentifier:    membership.OUIdentifier{OrganizationalUnitIdentifier: "peer"},
+		AdminOUIdentifier:   membership.OUIdentifier{OrganizationalUnitIdentifier: "admin"},
+		OrdererOUIdentifier: membership.OUIdentifier{OrganizationalUnitIdentifier: "orderer"},
+	}
+
+	err = msp.Validate()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = msp.setConfig(c.updated.ChannelGroup.Groups[OrdererGroupKey].Groups["OrdererOrg"])
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	fabricMSPConfig, err := msp.toProto()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(fabricMSPConfig.FabricNodeOus.ClientOuIdentifier.Certificate).To(BeEmpty())
+
+	roundTripped, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(roundTripped).To(Equal(msp))
+}
+
+func TestMSPConfigurationWithoutOUIdentifierCertificate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	msp, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	msp.OrganizationalUnitIdentifiers = []membership.OUIdentifier{
+		{OrganizationalUnitIdentifier: "OUID"},
+	}
+
+	err = msp.Validate()
+	gt.Expect(err).To(MatchError("MSP " + msp.Name + ": organizational unit identifier 'OUID' has no certificate"))
+
+	err = msp.setConfig(c.updated.ChannelGroup.Groups[OrdererGroupKey].Groups["OrdererOrg"])
+	gt.Expect(err).To(MatchError("new msp config: organizational unit identifier 'OUID' has no certificate"))
+
+	fabricMSPConfig, err := msp.toProto()
+	gt.Expect(err).To(MatchError("organizational unit identifier 'OUID' has no certificate"))
+	gt.Expect(fabricMSPConfig).To(BeNil())
+}
+
 func TestParseCertificateFromBytesFailure(t *testing.T) {
 	t.Parallel()
 	gt := NewGomegaWithT(t)
@@ -462,7 +523,71 @@ func TestRemoveAdminCertFailure(t *testing.T) {
 	ordererMSP := c.Orderer().Organization("OrdererOrg").MSP()
 	ordererMSP.configGroup = &cb.ConfigGroup{}
 	msp, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
-	err = ordererMSP.RemoveAdminCert(msp.Admins[0])
+	err = ordererMSP.RemoveRootCert(msp.RootCerts[0])
+	gt.Expect(err).To(MatchError("config does not contain value for MSP"))
+}
+
+func TestRemoveCertMatching(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config := &cb.Config{
+		ChannelGroup: channelGroup,
+	}
+	c := New(config)
+
+	ordererMSP := c.Orderer().Organization("OrdererOrg").MSP()
+	msp, err := ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	caCert := msp.RootCerts[0]
+	gt.Expect(caCert.SubjectKeyId).NotTo(BeEmpty())
+
+	admin1 := generateCert(t, "admin1.org1.example.com")
+	admin2 := generateCert(t, "admin2.org1.example.com")
+	err = ordererMSP.AddAdminCert(admin1)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ordererMSP.AddAdminCert(admin2)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = ordererMSP.RemoveAdminCertMatching(MatchSerialNumber(admin1.SerialNumber))
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ordererMSP.RemoveAdminCertMatching(MatchSubject(admin2.Subject.String()))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	msp, err = ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.Admins).To(Equal([]*x509.Certificate{caCert}))
+
+	err = ordererMSP.RemoveAdminCertMatching(MatchSerialNumber(admin1.SerialNumber))
+	gt.Expect(err).To(MatchError("no admin cert matches"))
+
+	err = ordererMSP.RemoveTLSIntermediateCertMatching(MatchSubjectKeyID(caCert.SubjectKeyId))
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ordererMSP.RemoveIntermediateCertMatching(MatchCertificate(caCert))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	msp, err = ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.TLSIntermediateCerts).To(BeEmpty())
+	gt.Expect(msp.IntermediateCerts).To(BeEmpty())
+
+	err = ordererMSP.RemoveTLSRootCertMatching(MatchSubjectKeyID([]byte("unknown")))
+	gt.Expect(err).To(MatchError("no tls root cert matches"))
+
+	err = ordererMSP.RemoveRootCertMatching(MatchSubject("CN=unknown"))
+	gt.Expect(err).To(MatchError("no root cert matches"))
+
+	err = ordererMSP.RemoveRootCertMatching(MatchSerialNumber(caCert.SerialNumber))
+	gt.Expect(err).NotTo(HaveOccurred())
+	msp, err = ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.RootCerts).To(BeEmpty())
+
+	ordererMSP.configGroup = &cb.ConfigGroup{}
+	err = ordererMSP.RemoveRootCertMatching(MatchSerialNumber(caCert.SerialNumber))
 	gt.Expect(err).To(MatchError("config does not contain value for MSP"))
 }
 
@@ -618,6 +743,295 @@ func TestAddIntermediateCertFailure(t *testing.T) {
 	gt.Expect(err).To(MatchError("config does not contain value for MSP"))
 }
 
+func TestAddStagedIntermediateCert(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config := &cb.Config{
+		ChannelGroup: channelGroup,
+	}
+	c := New(config)
+
+	ordererMSP := c.Orderer().Organization("OrdererOrg").MSP()
+	newRootCert, newRootKey := generateCACertAndPrivateKey(t, "new-ca-org1.example.com")
+	newIntermediateCert, _ := generateIntermediateCACertAndPrivateKey(t, "new-ica-org1.example.com", newRootCert, newRootKey)
+
+	err = ordererMSP.AddIntermediateCert(newIntermediateCert)
+	gt.Expect(err).To(MatchError(fmt.Sprintf("intermediate cert not signed by any root certs of this MSP. serial number: %d", newIntermediateCert.SerialNumber)))
+
+	err = ordererMSP.AddTLSIntermediateCert(newIntermediateCert)
+	gt.Expect(err).To(MatchError(ContainSubstring("x509: certificate signed by unknown authority")))
+
+	err = ordererMSP.AddStagedIntermediateCert(newIntermediateCert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = ordererMSP.AddRootCert(newRootCert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = ordererMSP.AddStagedTLSIntermediateCert(newIntermediateCert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = ordererMSP.AddTLSRootCert(newRootCert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	msp, err := ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.IntermediateCerts).To(ContainElement(newIntermediateCert))
+	gt.Expect(msp.TLSIntermediateCerts).To(ContainElement(newIntermediateCert))
+	gt.Expect(msp.validateCACerts()).To(Succeed())
+
+	notCA := generateCert(t, "org1.example.com")
+	err = ordererMSP.AddStagedIntermediateCert(notCA)
+	gt.Expect(err).To(MatchError(ContainSubstring("invalid intermediate cert")))
+}
+
+func TestAddCertsFromPEM(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	ordererMSP := c.Orderer().Organization("OrdererOrg").MSP()
+
+	rootCert1, rootKey1 := generateCACertAndPrivateKey(t, "ca1.org1.example.com")
+	rootCert2, rootKey2 := generateCACertAndPrivateKey(t, "ca2.org1.example.com")
+	intermediateCert1, _ := generateIntermediateCACertAndPrivateKey(t, "ica1.org1.example.com", rootCert1, rootKey1)
+	intermediateCert2, _ := generateIntermediateCACertAndPrivateKey(t, "ica2.org1.example.com", rootCert2, rootKey2)
+	adminCert, _ := generateCertAndPrivateKeyFromCACert(t, "org1.example.com", rootCert1, rootKey1)
+
+	err = ordererMSP.AddRootCertsFromPEM(pemutil.EncodeCertificates([]*x509.Certificate{rootCert1, rootCert2}))
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ordererMSP.AddIntermediateCertsFromPEM(pemutil.EncodeCertificates([]*x509.Certificate{intermediateCert1, intermediateCert2}))
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ordererMSP.AddTLSRootCertsFromPEM(pemutil.EncodeCertificate(rootCert1))
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ordererMSP.AddTLSIntermediateCertsFromPEM(pemutil.EncodeCertificate(intermediateCert1))
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ordererMSP.AddAdminCertsFromPEM(pemutil.EncodeCertificate(adminCert))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	msp, err := ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.RootCerts).To(ContainElements(rootCert1, rootCert2))
+	gt.Expect(msp.IntermediateCerts).To(ContainElements(intermediateCert1, intermediateCert2))
+	gt.Expect(msp.TLSRootCerts).To(ContainElement(rootCert1))
+	gt.Expect(msp.TLSIntermediateCerts).To(ContainElement(intermediateCert1))
+	gt.Expect(msp.Admins).To(ContainElement(adminCert))
+
+	// The TLS intermediate of the second root is valid, but the second root
+	// is not a TLS root of the MSP, so neither intermediate is added.
+	err = ordererMSP.AddTLSIntermediateCertsFromPEM(pemutil.EncodeCertificates([]*x509.Certificate{intermediateCert1, intermediateCert2}))
+	gt.Expect(err).To(MatchError(ContainSubstring("certificate 1 of PEM bundle: x509: certificate signed by unknown authority")))
+
+	unsignedRoot, unsignedRootKey := generateCACertAndPrivateKey(t, "ca3.org1.example.com")
+	unsignedIntermediate, _ := generateIntermediateCACertAndPrivateKey(t, "ica3.org1.example.com", unsignedRoot, unsignedRootKey)
+	err = ordererMSP.AddIntermediateCertsFromPEM(pemutil.EncodeCertificates([]*x509.Certificate{unsignedIntermediate}))
+	gt.Expect(err).To(MatchError(fmt.Sprintf("certificate 0 of PEM bundle: intermediate cert not signed by any root certs of this MSP. serial number: %d", unsignedIntermediate.SerialNumber)))
+
+	updatedMSP, err := ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(updatedMSP).To(Equal(msp))
+
+	err = ordererMSP.AddRootCertsFromPEM([]byte("not a bundle"))
+	gt.Expect(err).To(MatchError("parsing PEM bundle: no PEM encoded certificates found"))
+}
+
+func TestStrictAdds(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, privKeys, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	ordererMSP := c.Orderer().Organization("OrdererOrg").MSP()
+
+	msp, err := ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	rootCert := msp.RootCerts[0]
+	intermediateCert, _ := generateIntermediateCACertAndPrivateKey(t, "ica.org1.example.com", rootCert, privKeys[0])
+	adminCert, _ := generateCertAndPrivateKeyFromCACert(t, "org1.example.com", rootCert, privKeys[0])
+	crl, err := msp.CreateMSPCRL(&SigningIdentity{Certificate: rootCert, PrivateKey: privKeys[0], MSPID: "MSPID"}, adminCert)
+	gt.Expect(err).NotTo(HaveOccurred())
+	ou := membership.OUIdentifier{Certificate: rootCert, OrganizationalUnitIdentifier: "dept1"}
+
+	gt.Expect(ordererMSP.AddIntermediateCert(intermediateCert)).To(Succeed())
+	gt.Expect(ordererMSP.AddTLSIntermediateCert(intermediateCert)).To(Succeed())
+	gt.Expect(ordererMSP.AddAdminCert(adminCert)).To(Succeed())
+	gt.Expect(ordererMSP.AddOUIdentifier(ou)).To(Succeed())
+	gt.Expect(ordererMSP.AddCRL(crl)).To(Succeed())
+
+	msp, err = ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	strictMSP := ordererMSP.WithStrictAdds()
+
+	tests := []struct {
+		testName    string
+		add         func() error
+		expectedErr string
+	}{
+		{
+			testName:    "When adding an existing admin cert",
+			add:         func() error { return strictMSP.AddAdminCert(adminCert) },
+			expectedErr: fmt.Sprintf("admin cert with serial number %d already exists", adminCert.SerialNumber),
+		},
+		{
+			testName:    "When adding an existing root cert",
+			add:         func() error { return strictMSP.AddRootCert(rootCert) },
+			expectedErr: fmt.Sprintf("root cert with serial number %d already exists", rootCert.SerialNumber),
+		},
+		{
+			testName:    "When adding an existing intermediate cert",
+			add:         func() error { return strictMSP.AddIntermediateCert(intermediateCert) },
+			expectedErr: fmt.Sprintf("intermediate cert with serial number %d already exists", intermediateCert.SerialNumber),
+		},
+		{
+			testName:    "When adding an existing staged intermediate cert",
+			add:         func() error { return strictMSP.AddStagedIntermediateCert(intermediateCert) },
+			expectedErr: fmt.Sprintf("intermediate cert with serial number %d already exists", intermediateCert.SerialNumber),
+		},
+		{
+			testName:    "When adding an existing tls root cert",
+			add:         func() error { return strictMSP.AddTLSRootCert(rootCert) },
+			expectedErr: fmt.Sprintf("tls root cert with serial number %d already exists", rootCert.SerialNumber),
+		},
+		{
+			testName:    "When adding an existing tls intermediate cert",
+			add:         func() error { return strictMSP.AddTLSIntermediateCert(intermediateCert) },
+			expectedErr: fmt.Sprintf("tls intermediate cert with serial number %d already exists", intermediateCert.SerialNumber),
+		},
+		{
+			testName:    "When a PEM bundle contains an existing cert",
+			add:         func() error { return strictMSP.AddAdminCertsFromPEM(pemutil.EncodeCertificate(adminCert)) },
+			expectedErr: fmt.Sprintf("certificate 0 of PEM bundle: admin cert with serial number %d already exists", adminCert.SerialNumber),
+		},
+		{
+			testName:    "When adding an existing OU identifier",
+			add:         func() error { return strictMSP.AddOUIdentifier(ou) },
+			expectedErr: "ou identifier dept1 already exists",
+		},
+		{
+			testName:    "When adding an existing CRL",
+			add:         func() error { return strictMSP.AddCRL(crl) },
+			expectedErr: fmt.Sprintf("crl issued by %s already exists", crl.TBSCertList.Issuer),
+		},
+	}
+
+	for _, tt := range tests {
+		gt.Expect(ordererMSP.Configuration()).To(Equal(msp))
+
+		err := tt.add()
+		gt.Expect(err).To(MatchError(tt.expectedErr), tt.testName)
+		gt.Expect(errors.Is(err, ErrAlreadyExists)).To(BeTrue(), tt.testName)
+	}
+
+	gt.Expect(ordererMSP.Configuration()).To(Equal(msp))
+
+	// Strict mode survives a change of the certificate check mode.
+	err = strictMSP.WithCertificateChecks(CertificateChecksLenient, nil).AddAdminCert(adminCert)
+	gt.Expect(errors.Is(err, ErrAlreadyExists)).To(BeTrue())
+}
+
+func TestMSPConfigurationWithPEMBundles(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	orgGroup := channelGroup.Groups[OrdererGroupKey].Groups["OrdererOrg"]
+
+	rootCert, rootKey := generateCACertAndPrivateKey(t, "ca.org1.example.com")
+	intermediateCert, intermediateKey := generateIntermediateCACertAndPrivateKey(t, "ica.org1.example.com", rootCert, rootKey)
+
+	mspConfig := &mb.MSPConfig{}
+	err = unmarshalConfigValueAtKey(orgGroup, MSPKey, mspConfig)
+	gt.Expect(err).NotTo(HaveOccurred())
+	fabricMSPConfig := &mb.FabricMSPConfig{}
+	err = proto.Unmarshal(mspConfig.Config, fabricMSPConfig)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	fabricMSPConfig.RootCerts = [][]byte{pemutil.EncodeCertificates([]*x509.Certificate{rootCert, intermediateCert})}
+	mspConfig.Config, err = proto.Marshal(fabricMSPConfig)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(orgGroup, mspValue(mspConfig), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	msp, err := getMSPConfig(orgGroup)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.RootCerts).To(Equal([]*x509.Certificate{rootCert, intermediateCert}))
+
+	fabricMSPConfig.RootCerts = [][]byte{append(pemutil.EncodeCertificate(rootCert), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")})...)}
+	mspConfig.Config, err = proto.Marshal(fabricMSPConfig)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(orgGroup, mspValue(mspConfig), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = getMSPConfig(orgGroup)
+	gt.Expect(err).To(MatchError(ContainSubstring("PEM block 1 is a PRIVATE KEY, not a certificate")))
+
+	// Consenter certs given as a chain resolve to their leaf certificate.
+	leafCert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer1.example.com", intermediateCert, intermediateKey)
+	cert, err := parseLeafCertificate(pemutil.EncodeCertificates([]*x509.Certificate{leafCert, intermediateCert, rootCert}))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(cert).To(Equal(leafCert))
+}
+
+func TestCertificateCheckModes(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config := &cb.Config{
+		ChannelGroup: channelGroup,
+	}
+	c := New(config)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(7),
+		Subject:               pkix.Name{CommonName: "ca.org1.example.com"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(YEAR),
+		KeyUsage:              x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	cert, _ := generateCertAndPrivateKey(t, template, template, nil)
+
+	ordererMSP := c.Orderer().Organization("OrdererOrg").MSP()
+	err = ordererMSP.AddRootCert(cert)
+	gt.Expect(err).To(MatchError("invalid root cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7"))
+
+	err = ordererMSP.WithCertificateChecks(CertificateChecksStrict, nil).AddTLSRootCert(cert)
+	gt.Expect(err).To(MatchError("invalid tls root cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7"))
+
+	var warnings []string
+	lenientMSP := ordererMSP.WithCertificateChecks(CertificateChecksLenient, func(err error) {
+		warnings = append(warnings, err.Error())
+	})
+	err = lenientMSP.AddRootCert(cert)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(warnings).To(Equal([]string{
+		"invalid root cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7",
+		"invalid root cert: must be a CA certificate. serial number: 7",
+	}))
+
+	err = ordererMSP.WithCertificateChecks(CertificateChecksLenient, nil).AddTLSRootCert(cert)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	msp, err := ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.RootCerts).To(ContainElement(cert))
+	gt.Expect(msp.TLSRootCerts).To(ContainElement(cert))
+
+	err = ordererMSP.RemoveRootCert(msp.RootCerts[0])
+	gt.Expect(err).To(MatchError("invalid root cert: KeyUsage must be x509.KeyUsageCertSign. serial number: 7"))
+}
+
 func TestRemoveIntermediateCert(t *testing.T) {
 	t.Parallel()
 	gt := NewGomegaWithT(t)
@@ -719,6 +1133,70 @@ func TestAddOUIdentifierFailures(t *testing.T) {
 	gt.Expect(err).To(MatchError("config does not contain value for MSP"))
 }
 
+func TestSetOUIdentifiers(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, privKeys, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	ordererMSP := c.Orderer().Organization("OrdererOrg").MSP()
+
+	msp, err := ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	rootCert := msp.RootCerts[0]
+	ouCert, _ := generateCertAndPrivateKeyFromCACert(t, "org1.example.com", rootCert, privKeys[0])
+	ous := []membership.OUIdentifier{
+		{Certificate: rootCert, OrganizationalUnitIdentifier: "dept1"},
+		{Certificate: ouCert, OrganizationalUnitIdentifier: "dept2"},
+	}
+
+	err = ordererMSP.SetOUIdentifiers(ous)
+	gt.Expect(err).NotTo(HaveOccurred())
+	msp, err = ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.OrganizationalUnitIdentifiers).To(Equal(ous))
+
+	unchainedCert := generateCert(t, "anothercert-org1.example.com")
+	tests := []struct {
+		testName    string
+		ous         []membership.OUIdentifier
+		expectedErr string
+	}{
+		{
+			testName: "When a certificate does not chain to the MSP",
+			ous: []membership.OUIdentifier{
+				{Certificate: rootCert, OrganizationalUnitIdentifier: "dept1"},
+				{Certificate: unchainedCert, OrganizationalUnitIdentifier: "dept4"},
+			},
+			expectedErr: fmt.Sprintf("organizational unit identifier 'dept4' certificate does not chain to a root or intermediate cert of this MSP. serial number: %d: x509: certificate signed by unknown authority", unchainedCert.SerialNumber),
+		},
+		{
+			testName: "When an identifier is repeated",
+			ous: []membership.OUIdentifier{
+				{Certificate: rootCert, OrganizationalUnitIdentifier: "dept1"},
+				{Certificate: rootCert, OrganizationalUnitIdentifier: "dept1"},
+			},
+			expectedErr: "organizational unit identifier 'dept1' is repeated",
+		},
+	}
+
+	for _, tt := range tests {
+		err := ordererMSP.SetOUIdentifiers(tt.ous)
+		gt.Expect(err).To(MatchError(tt.expectedErr), tt.testName)
+	}
+
+	updatedMSP, err := ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(updatedMSP).To(Equal(msp))
+
+	err = ordererMSP.SetOUIdentifiers(nil)
+	gt.Expect(err).NotTo(HaveOccurred())
+	msp, err = ordererMSP.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.OrganizationalUnitIdentifiers).To(BeEmpty())
+}
+
 func TestRemoveOUIdentifier(t *testing.T) {
 	t.Parallel()
 	gt := NewGomegaWithT(t)
@@ -1301,6 +1779,13 @@ func TestAddCRL(t *testing.T) {
 	ordererMSP, err = c.Orderer().Organization("OrdererOrg").MSP().Configuration()
 	gt.Expect(err).NotTo(HaveOccurred())
 	gt.Expect(ordererMSP.RevocationList).Should(ContainElement(newCRL))
+
+	revocationList := ordererMSP.RevocationList
+	err = msp.AddCRL(newCRL)
+	gt.Expect(err).NotTo(HaveOccurred())
+	ordererMSP, err = c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererMSP.RevocationList).To(Equal(revocationList))
 }
 
 func TestAddCRLFailures(t *testing.T) {
@@ -1374,6 +1859,98 @@ func TestAddCRLFromSigningIdentity(t *testing.T) {
 	// gt.Expect(ordererMSP.RevocationList).Should(ContainElement(newCRL))
 }
 
+func TestCompactCRLs(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, privKeys, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config := &cb.Config{
+		ChannelGroup: channelGroup,
+	}
+	c := New(config)
+
+	msp := c.Orderer().Organization("OrdererOrg").MSP()
+	ordererMSP, err := msp.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	cert := ordererMSP.RootCerts[0]
+	signingIdentity := &SigningIdentity{
+		Certificate: cert,
+		PrivateKey:  privKeys[0],
+		MSPID:       "MSPID",
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	newCRL := func(issuer *x509.Certificate, issuerKey *ecdsa.PrivateKey, revoked ...pkix.RevokedCertificate) *pkix.CertificateList {
+		crlBytes, err := issuer.CreateCRL(rand.Reader, issuerKey, revoked, now, now.Add(YEAR))
+		gt.Expect(err).NotTo(HaveOccurred())
+		crl, err := x509.ParseCRL(crlBytes)
+		gt.Expect(err).NotTo(HaveOccurred())
+		return crl
+	}
+
+	otherCA, otherKey := generateCACertAndPrivateKey(t, "other.example.com")
+	otherCRL := newCRL(otherCA, otherKey, pkix.RevokedCertificate{SerialNumber: big.NewInt(9), RevocationTime: now})
+
+	ordererMSP.RevocationList = []*pkix.CertificateList{
+		newCRL(cert, privKeys[0],
+			pkix.RevokedCertificate{SerialNumber: big.NewInt(1), RevocationTime: now.Add(-2 * YEAR)},
+			pkix.RevokedCertificate{SerialNumber: big.NewInt(2), RevocationTime: now.Add(-time.Hour)},
+		),
+		otherCRL,
+		newCRL(cert, privKeys[0],
+			pkix.RevokedCertificate{SerialNumber: big.NewInt(3), RevocationTime: now},
+			pkix.RevokedCertificate{SerialNumber: big.NewInt(2), RevocationTime: now},
+		),
+	}
+	err = c.Orderer().Organization("OrdererOrg").SetMSP(ordererMSP)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = msp.CompactCRLs(signingIdentity, YEAR)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererMSP, err = msp.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererMSP.RevocationList).To(HaveLen(2))
+	gt.Expect(ordererMSP.RevocationList[0]).To(Equal(otherCRL))
+
+	merged := ordererMSP.RevocationList[1]
+	gt.Expect(cert.CheckCRLSignature(merged)).To(Succeed())
+	revoked := merged.TBSCertList.RevokedCertificates
+	gt.Expect(revoked).To(HaveLen(2))
+	gt.Expect(revoked[0].SerialNumber).To(Equal(big.NewInt(2)))
+	gt.Expect(revoked[0].RevocationTime).To(BeTemporally("==", now.Add(-time.Hour)))
+	gt.Expect(revoked[1].SerialNumber).To(Equal(big.NewInt(3)))
+}
+
+func TestMergeCRLsFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	msp, privKey := baseMSP(t)
+	signingIdentity := &SigningIdentity{
+		Certificate: msp.RootCerts[0],
+		PrivateKey:  privKey,
+		MSPID:       msp.Name,
+	}
+
+	_, err := msp.MergeCRLs(nil, 0)
+	gt.Expect(err).To(MatchError("signing identity is required"))
+
+	otherCA, otherKey := generateCACertAndPrivateKey(t, "other.example.com")
+	_, err = msp.MergeCRLs(&SigningIdentity{Certificate: otherCA, PrivateKey: otherKey}, 0)
+	gt.Expect(err).To(MatchError("signing cert is not a root/intermediate cert for this MSP: MSPID"))
+
+	crlBytes, err := otherCA.CreateCRL(rand.Reader, otherKey, nil, time.Now(), time.Now().Add(YEAR))
+	gt.Expect(err).NotTo(HaveOccurred())
+	otherCRL, err := x509.ParseCRL(crlBytes)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = msp.MergeCRLs(signingIdentity, 0, msp.RevocationList[0], otherCRL)
+	gt.Expect(err).To(MatchError(ContainSubstring("CRL at index 1 was not issued by the signing identity")))
+}
+
 func baseMSP(t *testing.T) (MSP, *ecdsa.PrivateKey) {
 	gt := NewGomegaWithT(t)
 
@@ -1440,3 +2017,48 @@ func certCRLBase64(t *testing.T, msp MSP) (string, string) {
 
 	return certBase64, crlBase64
 }
+
+func TestMSPValidate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	msp, privKey := baseMSP(t)
+	gt.Expect(msp.Validate()).To(Succeed())
+
+	unnamed := msp
+	unnamed.Name = ""
+	gt.Expect(unnamed.Validate()).To(MatchError("MSP name is required"))
+
+	noRoots := msp
+	noRoots.RootCerts = nil
+	gt.Expect(noRoots.Validate()).To(MatchError("MSP MSPID: root certs are required"))
+
+	notCA := msp
+	leaf := generateCert(t, "org1.example.com")
+	notCA.RootCerts = []*x509.Certificate{leaf}
+	gt.Expect(notCA.Validate()).To(MatchError(fmt.Sprintf("MSP MSPID: invalid root cert: must be a CA certificate. serial number: %d", leaf.SerialNumber)))
+
+	intermediateCert, _ := generateIntermediateCACertAndPrivateKey(t, "org1.example.com", msp.RootCerts[0], privKey)
+	intermediateOU := msp
+	intermediateOU.IntermediateCerts = []*x509.Certificate{intermediateCert}
+	intermediateOU.NodeOUs.PeerOUIdentifier.Certificate = intermediateCert
+	gt.Expect(intermediateOU.Validate()).To(Succeed())
+
+	otherCACert, _ := generateCACertAndPrivateKey(t, "org2.example.com")
+	foreignNodeOU := msp
+	foreignNodeOU.NodeOUs.AdminOUIdentifier.Certificate = otherCACert
+	gt.Expect(foreignNodeOU.Validate()).To(MatchError(ContainSubstring(fmt.Sprintf("MSP MSPID: admin OU identifier certificate does not chain to a root or intermediate cert of this MSP. serial number: %d", otherCACert.SerialNumber))))
+
+	foreignOU := msp
+	foreignOU.OrganizationalUnitIdentifiers = []membership.OUIdentifier{
+		{
+			Certificate:                  otherCACert,
+			OrganizationalUnitIdentifier: "OUID",
+		},
+	}
+	gt.Expect(foreignOU.Validate()).To(MatchError(ContainSubstring("MSP MSPID: organizational unit identifier 'OUID' certificate does not chain")))
+
+	noOUCert := msp
+	noOUCert.NodeOUs.ClientOUIdentifier.Certificate = nil
+	gt.Expect(noOUCert.Validate()).To(Succeed())
+}
@@ -0,0 +1,292 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hyperledger/fabric-config/configtx/membership"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
+	"gopkg.in/yaml.v2"
+)
+
+// mspDirConfig is the config.yaml file of an MSP directory.
+type mspDirConfig struct {
+	OrganizationalUnitIdentifiers []mspDirOUIdentifier `yaml:"OrganizationalUnitIdentifiers,omitempty"`
+	NodeOUs                       *mspDirNodeOUs       `yaml:"NodeOUs,omitempty"`
+}
+
+// mspDirOUIdentifier is an OU identifier of config.yaml. Certificate is the
+// path of the CA cert relative to the MSP directory.
+type mspDirOUIdentifier struct {
+	Certificate                  string `yaml:"Certificate,omitempty"`
+	OrganizationalUnitIdentifier string `yaml:"OrganizationalUnitIdentifier,omitempty"`
+}
+
+type mspDirNodeOUs struct {
+	Enable              bool                `yaml:"Enable,omitempty"`
+	ClientOUIdentifier  *mspDirOUIdentifier `yaml:"ClientOUIdentifier,omitempty"`
+	PeerOUIdentifier    *mspDirOUIdentifier `yaml:"PeerOUIdentifier,omitempty"`
+	AdminOUIdentifier   *mspDirOUIdentifier `yaml:"AdminOUIdentifier,omitempty"`
+	OrdererOUIdentifier *mspDirOUIdentifier `yaml:"OrdererOUIdentifier,omitempty"`
+}
+
+// LoadMSPDir loads the MSP with the given MSP ID from an MSP directory with
+// the layout used by Fabric and cryptogen: the PEM encoded certs of the
+// cacerts, intermediatecerts, admincerts, tlscacerts and tlsintermediatecerts
+// directories, the CRLs of the crls directory and the OU identifiers and
+// NodeOUs of the optional config.yaml file. Only the cacerts directory is
+// required. The MSP uses SHA2 with SHA256 identity identifiers.
+func LoadMSPDir(dir, mspID string) (MSP, error) {
+	msp := MSP{
+		Name: mspID,
+		CryptoConfig: membership.CryptoConfig{
+			SignatureHashFamily:            "SHA2",
+			IdentityIdentifierHashFunction: "SHA256",
+		},
+	}
+
+	certDirs := []struct {
+		name  string
+		certs *[]*x509.Certificate
+	}{
+		{name: "cacerts", certs: &msp.RootCerts},
+		{name: "intermediatecerts", certs: &msp.IntermediateCerts},
+		{name: "admincerts", certs: &msp.Admins},
+		{name: "tlscacerts", certs: &msp.TLSRootCerts},
+		{name: "tlsintermediatecerts", certs: &msp.TLSIntermediateCerts},
+	}
+	for _, certDir := range certDirs {
+		files, err := readMSPSubdir(dir, certDir.name)
+		if err != nil {
+			return MSP{}, err
+		}
+		for _, file := range files {
+			certs, err := pemutil.ParseCertificates(file.content)
+			if err != nil {
+				return MSP{}, fmt.Errorf("parsing certificates %s: %v", file.name, err)
+			}
+			*certDir.certs = append(*certDir.certs, certs...)
+		}
+	}
+
+	if len(msp.RootCerts) == 0 {
+		return MSP{}, fmt.Errorf("no root certs found in %s", filepath.Join(dir, "cacerts"))
+	}
+
+	crlFiles, err := readMSPSubdir(dir, "crls")
+	if err != nil {
+		return MSP{}, err
+	}
+	for _, file := range crlFiles {
+		crl, err := pemutil.ParseCRL(file.content)
+		if err != nil {
+			return MSP{}, fmt.Errorf("parsing crl %s: %v", file.name, err)
+		}
+		msp.RevocationList = append(msp.RevocationList, crl)
+	}
+
+	configBytes, err := ioutil.ReadFile(filepath.Join(dir, "config.yaml"))
+	if os.IsNotExist(err) {
+		return msp, nil
+	}
+	if err != nil {
+		return MSP{}, fmt.Errorf("reading file: %v", err)
+	}
+
+	config := &mspDirConfig{}
+	err = yaml.Unmarshal(configBytes, config)
+	if err != nil {
+		return MSP{}, fmt.Errorf("parsing %s: %v", filepath.Join(dir, "config.yaml"), err)
+	}
+
+	for i := range config.OrganizationalUnitIdentifiers {
+		ou, err := loadMSPDirOUIdentifier(dir, &config.OrganizationalUnitIdentifiers[i])
+		if err != nil {
+			return MSP{}, err
+		}
+		msp.OrganizationalUnitIdentifiers = append(msp.OrganizationalUnitIdentifiers, ou)
+	}
+
+	if config.NodeOUs != nil {
+		msp.NodeOUs.Enable = config.NodeOUs.Enable
+		nodeOUs := []struct {
+			config *mspDirOUIdentifier
+			ou     *membership.OUIdentifier
+		}{
+			{config: config.NodeOUs.ClientOUIdentifier, ou: &msp.NodeOUs.ClientOUIdentifier},
+			{config: config.NodeOUs.PeerOUIdentifier, ou: &msp.NodeOUs.PeerOUIdentifier},
+			{config: config.NodeOUs.AdminOUIdentifier, ou: &msp.NodeOUs.AdminOUIdentifier},
+			{config: config.NodeOUs.OrdererOUIdentifier, ou: &msp.NodeOUs.OrdererOUIdentifier},
+		}
+		for _, nodeOU := range nodeOUs {
+			if nodeOU.config == nil {
+				continue
+			}
+			*nodeOU.ou, err = loadMSPDirOUIdentifier(dir, nodeOU.config)
+			if err != nil {
+				return MSP{}, err
+			}
+		}
+	}
+
+	return msp, nil
+}
+
+// readMSPSubdir reads the files of the subdirectory of the MSP directory. A
+// missing subdirectory has no files.
+func readMSPSubdir(dir, name string) ([]dirFile, error) {
+	subdir := filepath.Join(dir, name)
+	if _, err := os.Stat(subdir); os.IsNotExist(err) {
+		return nil, nil
+	}
+
+	return readDirFiles(subdir)
+}
+
+// loadMSPDirOUIdentifier loads the CA cert of the OU identifier, whose path is
+// relative to the MSP directory. The cert is optional.
+func loadMSPDirOUIdentifier(dir string, ouIdentifier *mspDirOUIdentifier) (membership.OUIdentifier, error) {
+	ou := membership.OUIdentifier{OrganizationalUnitIdentifier: ouIdentifier.OrganizationalUnitIdentifier}
+	if ouIdentifier.Certificate == "" {
+		return ou, nil
+	}
+
+	file := filepath.Join(dir, ouIdentifier.Certificate)
+	pemBytes, err := ioutil.ReadFile(file)
+	if err != nil {
+		return membership.OUIdentifier{}, fmt.Errorf("reading file: %v", err)
+	}
+
+	ou.Certificate, err = pemutil.ParseCertificate(pemBytes)
+	if err != nil {
+		return membership.OUIdentifier{}, fmt.Errorf("parsing certificate %s: %v", file, err)
+	}
+
+	return ou, nil
+}
+
+// AddOrganizationFromMSPDir adds an orderer org loaded from an MSP directory,
+// see LoadMSPDir, in a single operation. The org is named after its MSP ID and
+// listens on the given endpoints, of which there must be at least one. An
+// existing org is not replaced, see SetOrganization.
+//
+// The org gets default Readers, Writers and Admins signature policies, which
+// are overridden by the given policies. If the MSP enables NodeOUs, the
+// Readers and Writers policies are satisfied by the admins, orderers and
+// clients of the org, otherwise by any of its members. The Admins policy is
+// satisfied by the admins of the org.
+func (o *OrdererGroup) AddOrganizationFromMSPDir(name, path string, endpoints []Address, policies map[string]Policy) (err error) {
+	defer o.observe("OrdererGroup.AddOrganizationFromMSPDir", time.Now(), &err)
+
+	if o.Organization(name) != nil {
+		return fmt.Errorf("orderer org %s already exists", name)
+	}
+
+	if len(endpoints) == 0 {
+		return fmt.Errorf("orderer endpoints are required for org %s", name)
+	}
+
+	msp, err := LoadMSPDir(path, name)
+	if err != nil {
+		return fmt.Errorf("loading MSP of org %s: %v", name, err)
+	}
+
+	orgPolicies := ordererOrgPolicies(name, msp.NodeOUs.Enable)
+	for policyName, policy := range policies {
+		orgPolicies[policyName] = policy
+	}
+
+	ordererEndpoints := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		ordererEndpoints[i] = hostPort(endpoint.Host, endpoint.Port)
+	}
+
+	org := Organization{
+		Name:             name,
+		Policies:         orgPolicies,
+		MSP:              msp,
+		OrdererEndpoints: ordererEndpoints,
+	}
+	err = org.Validate()
+	if err != nil {
+		return err
+	}
+
+	return o.SetOrganization(org)
+}
+
+// AddOrganizationFromMSPDir adds an application org loaded from an MSP
+// directory, see LoadMSPDir, with the given anchor peers in a single
+// operation. The org is named after its MSP ID. An existing org is not
+// replaced, see SetOrganization. The added org is returned, so that it can be
+// reviewed before the config update is computed.
+//
+// The org gets default Readers, Writers, Admins and Endorsement signature
+// policies, which are overridden by the given policies. If the MSP enables
+// NodeOUs, the defaults are those of an org with node OUs: Readers are the
+// admins, peers and clients of the org, Writers its admins and clients and
+// Endorsement its peers. Otherwise, the Readers, Writers and Endorsement
+// policies are satisfied by any member of the org. The Admins policy is
+// satisfied by the admins of the org.
+func (a *ApplicationGroup) AddOrganizationFromMSPDir(name, path string, anchorPeers []Address, policies map[string]Policy) (org Organization, err error) {
+	defer a.observe("ApplicationGroup.AddOrganizationFromMSPDir", time.Now(), &err)
+
+	if a.Organization(name) != nil {
+		return Organization{}, fmt.Errorf("application org %s already exists", name)
+	}
+
+	msp, err := LoadMSPDir(path, name)
+	if err != nil {
+		return Organization{}, fmt.Errorf("loading MSP of org %s: %v", name, err)
+	}
+
+	orgPolicies := applicationOrgPolicies(name, msp.NodeOUs.Enable)
+	for policyName, policy := range policies {
+		orgPolicies[policyName] = policy
+	}
+
+	org = Organization{
+		Name:        name,
+		Policies:    orgPolicies,
+		MSP:         msp,
+		AnchorPeers: anchorPeers,
+	}
+	err = org.Validate()
+	if err != nil {
+		return Organization{}, err
+	}
+
+	err = a.SetOrganization(org)
+	if err != nil {
+		return Organization{}, err
+	}
+
+	return org, nil
+}
+
+func nodeOUOrdererOrgPolicies(mspID string) map[string]Policy {
+	return map[string]Policy{
+		ReadersPolicyKey: {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%[1]s.admin', '%[1]s.orderer', '%[1]s.client')", mspID)},
+		WritersPolicyKey: {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%[1]s.admin', '%[1]s.orderer', '%[1]s.client')", mspID)},
+		AdminsPolicyKey:  {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.admin')", mspID)},
+	}
+}
+
+func memberApplicationOrgPolicies(mspID string) map[string]Policy {
+	return map[string]Policy{
+		ReadersPolicyKey:     {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.member')", mspID)},
+		WritersPolicyKey:     {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.member')", mspID)},
+		AdminsPolicyKey:      {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.admin')", mspID)},
+		EndorsementPolicyKey: {Type: SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.member')", mspID)},
+	}
+}
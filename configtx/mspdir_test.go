@@ -0,0 +1,231 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
+	. "github.com/onsi/gomega"
+)
+
+const nodeOUsConfigYAML = `NodeOUs:
+  Enable: true
+  ClientOUIdentifier:
+    Certificate: cacerts/ca.pem
+    OrganizationalUnitIdentifier: client
+  PeerOUIdentifier:
+    Certificate: cacerts/ca.pem
+    OrganizationalUnitIdentifier: peer
+  AdminOUIdentifier:
+    Certificate: cacerts/ca.pem
+    OrganizationalUnitIdentifier: admin
+  OrdererOUIdentifier:
+    Certificate: cacerts/ca.pem
+    OrganizationalUnitIdentifier: orderer
+`
+
+// writeMSPDir writes the files to an MSP directory, which the caller must
+// remove.
+func writeMSPDir(t *testing.T, files map[string][]byte) string {
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "msp")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		gt.Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		gt.Expect(ioutil.WriteFile(path, content, 0644)).To(Succeed())
+	}
+
+	return dir
+}
+
+func TestLoadMSPDir(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer2.example.com")
+	tlsCACert, _ := generateCACertAndPrivateKey(t, "tls.orderer2.example.com")
+	crl, err := caCert.CreateCRL(rand.Reader, caPrivKey, nil, time.Now(), time.Now().Add(YEAR))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	dir := writeMSPDir(t, map[string][]byte{
+		"cacerts/ca.pem":       pemutil.EncodeCertificate(caCert),
+		"tlscacerts/tlsca.pem": pemutil.EncodeCertificate(tlsCACert),
+		"crls/crl.pem":         pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: crl}),
+		"config.yaml":          []byte(nodeOUsConfigYAML),
+	})
+	defer os.RemoveAll(dir)
+
+	msp, err := LoadMSPDir(dir, "Orderer2MSP")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msp.Name).To(Equal("Orderer2MSP"))
+	gt.Expect(msp.RootCerts).To(Equal([]*x509.Certificate{caCert}))
+	gt.Expect(msp.TLSRootCerts).To(Equal([]*x509.Certificate{tlsCACert}))
+	gt.Expect(msp.IntermediateCerts).To(BeEmpty())
+	gt.Expect(msp.Admins).To(BeEmpty())
+	gt.Expect(msp.RevocationList).To(HaveLen(1))
+	gt.Expect(msp.NodeOUs.Enable).To(BeTrue())
+	gt.Expect(msp.NodeOUs.OrdererOUIdentifier.Certificate).To(Equal(caCert))
+	gt.Expect(msp.NodeOUs.OrdererOUIdentifier.OrganizationalUnitIdentifier).To(Equal("orderer"))
+	gt.Expect(msp.CryptoConfig.IdentityIdentifierHashFunction).To(Equal("SHA256"))
+	gt.Expect(msp.Validate()).To(Succeed())
+}
+
+func TestLoadMSPDirFailures(t *testing.T) {
+	t.Parallel()
+
+	caCert, _ := generateCACertAndPrivateKey(t, "orderer2.example.com")
+
+	tests := []struct {
+		testName    string
+		files       map[string][]byte
+		expectedErr string
+	}{
+		{
+			testName:    "when there are no root certs",
+			files:       map[string][]byte{"tlscacerts/tlsca.pem": pemutil.EncodeCertificate(caCert)},
+			expectedErr: "no root certs found in ",
+		},
+		{
+			testName:    "when a cert cannot be parsed",
+			files:       map[string][]byte{"cacerts/ca.pem": []byte("not a cert")},
+			expectedErr: "parsing certificates ",
+		},
+		{
+			testName: "when config.yaml cannot be parsed",
+			files: map[string][]byte{
+				"cacerts/ca.pem": pemutil.EncodeCertificate(caCert),
+				"config.yaml":    []byte("NodeOUs: ["),
+			},
+			expectedErr: "config.yaml: ",
+		},
+		{
+			testName: "when an OU identifier cert is missing",
+			files: map[string][]byte{
+				"cacerts/ca.pem": pemutil.EncodeCertificate(caCert),
+				"config.yaml":    []byte("OrganizationalUnitIdentifiers:\n- Certificate: cacerts/missing.pem\n  OrganizationalUnitIdentifier: orderers\n"),
+			},
+			expectedErr: "reading file: ",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			dir := writeMSPDir(t, tt.files)
+			defer os.RemoveAll(dir)
+
+			_, err := LoadMSPDir(dir, "Orderer2MSP")
+			gt.Expect(err).To(MatchError(ContainSubstring(tt.expectedErr)))
+		})
+	}
+}
+
+func TestAddOrganizationFromMSPDir(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	caCert, _ := generateCACertAndPrivateKey(t, "orderer2.example.com")
+	dir := writeMSPDir(t, map[string][]byte{
+		"cacerts/ca.pem":       pemutil.EncodeCertificate(caCert),
+		"tlscacerts/tlsca.pem": pemutil.EncodeCertificate(caCert),
+		"config.yaml":          []byte(nodeOUsConfigYAML),
+	})
+	defer os.RemoveAll(dir)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	endpoints := []Address{{Host: "orderer2.example.com", Port: 7050}}
+	err = c.Orderer().AddOrganizationFromMSPDir("Orderer2MSP", dir, endpoints, map[string]Policy{
+		WritersPolicyKey: {Type: SignaturePolicyType, Rule: "OR('Orderer2MSP.orderer')", ModPolicy: AdminsPolicyKey},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	org, err := c.Orderer().Organization("Orderer2MSP").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.OrdererEndpoints).To(Equal([]string{"orderer2.example.com:7050"}))
+	gt.Expect(org.MSP.Name).To(Equal("Orderer2MSP"))
+	gt.Expect(org.MSP.NodeOUs.Enable).To(BeTrue())
+	gt.Expect(org.Policies[ReadersPolicyKey].Rule).To(Equal("OR('Orderer2MSP.admin', 'Orderer2MSP.orderer', 'Orderer2MSP.client')"))
+	gt.Expect(org.Policies[WritersPolicyKey].Rule).To(Equal("AND('Orderer2MSP.orderer')"))
+	gt.Expect(org.Policies[AdminsPolicyKey].Rule).To(Equal("AND('Orderer2MSP.admin')"))
+
+	err = c.Orderer().AddOrganizationFromMSPDir("Orderer2MSP", dir, endpoints, nil)
+	gt.Expect(err).To(MatchError("orderer org Orderer2MSP already exists"))
+	gt.Expect(c.Orderer().Organization("Orderer2MSP").Policies()).To(HaveKeyWithValue(WritersPolicyKey, Policy{Type: SignaturePolicyType, Rule: "AND('Orderer2MSP.orderer')", ModPolicy: AdminsPolicyKey}))
+
+	err = c.Orderer().AddOrganizationFromMSPDir("Orderer3MSP", dir, nil, nil)
+	gt.Expect(err).To(MatchError("orderer endpoints are required for org Orderer3MSP"))
+
+	err = c.Orderer().AddOrganizationFromMSPDir("Orderer3MSP", filepath.Join(dir, "missing"), endpoints, nil)
+	gt.Expect(err).To(MatchError(ContainSubstring("loading MSP of org Orderer3MSP: no root certs found in ")))
+	gt.Expect(c.Orderer().Organization("Orderer3MSP")).To(BeNil())
+}
+
+func TestAddApplicationOrganizationFromMSPDir(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	caCert, _ := generateCACertAndPrivateKey(t, "org3.example.com")
+	adminCert, _ := generateCACertAndPrivateKey(t, "admin.org3.example.com")
+	dir := writeMSPDir(t, map[string][]byte{
+		"cacerts/ca.pem":          pemutil.EncodeCertificate(caCert),
+		"admincerts/admin.pem":    pemutil.EncodeCertificate(adminCert),
+		"tlscacerts/tls-ca.pem":   pemutil.EncodeCertificate(caCert),
+		"signcerts/ignored.pem":   []byte("not read"),
+		"keystore/ignored_sk.pem": []byte("not read"),
+	})
+	defer os.RemoveAll(dir)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	anchorPeers := []Address{{Host: "peer0.org3.example.com", Port: 7051}}
+	org, err := c.Application().AddOrganizationFromMSPDir("Org3MSP", dir, anchorPeers, map[string]Policy{
+		EndorsementPolicyKey: {Type: SignaturePolicyType, Rule: "OR('Org3MSP.admin')", ModPolicy: AdminsPolicyKey},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.Name).To(Equal("Org3MSP"))
+	gt.Expect(org.AnchorPeers).To(Equal(anchorPeers))
+	gt.Expect(org.MSP.Admins).To(Equal([]*x509.Certificate{adminCert}))
+	gt.Expect(org.Policies[ReadersPolicyKey].Rule).To(Equal("OR('Org3MSP.member')"))
+	gt.Expect(org.Policies[EndorsementPolicyKey].Rule).To(Equal("OR('Org3MSP.admin')"))
+
+	added, err := c.Application().Organization("Org3MSP").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(added.AnchorPeers).To(Equal(anchorPeers))
+	gt.Expect(added.MSP.Name).To(Equal("Org3MSP"))
+	gt.Expect(added.Policies[WritersPolicyKey].Rule).To(Equal("AND('Org3MSP.member')"))
+
+	_, err = c.Application().AddOrganizationFromMSPDir("Org3MSP", dir, nil, nil)
+	gt.Expect(err).To(MatchError("application org Org3MSP already exists"))
+	addedAnchorPeers, err := c.Application().Organization("Org3MSP").AnchorPeers()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(addedAnchorPeers).To(Equal(anchorPeers))
+
+	_, err = c.Application().AddOrganizationFromMSPDir("Org4MSP", dir, []Address{{Host: "peer0.org4.example.com"}}, nil)
+	gt.Expect(err).To(MatchError("org Org4MSP: invalid anchor peer: invalid port 0 for host peer0.org4.example.com"))
+	gt.Expect(c.Application().Organization("Org4MSP")).To(BeNil())
+}
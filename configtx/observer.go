@@ -0,0 +1,173 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"time"
+)
+
+// Operation describes a modification of the updated config or the
+// computation of a config update.
+type Operation struct {
+	// Name is the type and method performing the operation, e.g.
+	// OrdererGroup.SetBatchTimeout.
+	Name string
+	// Path identifies the part of the config the operation was performed
+	// on, e.g. /Channel/Orderer for the orderer group.
+	Path     string
+	Duration time.Duration
+	// Err is the error returned by the operation, if any.
+	Err error
+}
+
+// Observer is notified of the operations performed through a ConfigTx, for
+// instance to record metrics or traces. Operations implemented in terms of
+// other operations notify the observer of each of them.
+type Observer interface {
+	Observe(op Operation)
+}
+
+// ObserverFunc is an Observer calling a function.
+type ObserverFunc func(op Operation)
+
+// Observe calls f(op).
+func (f ObserverFunc) Observe(op Operation) {
+	f(op)
+}
+
+// SetObserver sets the observer notified of the operations performed through
+// the ConfigTx and through the groups, orgs and MSPs retrieved from it
+// afterwards. A nil observer disables notifications.
+func (c *ConfigTx) SetObserver(observer Observer) {
+	c.observer = observer
+}
+
+// observed is the observer and logger of an element of the config along with
+// the path of the element.
+type observed struct {
+	observer Observer
+	logger   Logger
+	journal  *journal
+	path     string
+}
+
+// observed returns the observer and logger of the channel group.
+func (c *ConfigTx) observed() observed {
+	return observed{
+		observer: c.observer,
+		logger:   c.logger,
+		journal:  c.journal,
+		path:     "/" + ChannelGroupKey,
+	}
+}
+
+// child returns the observer and logger of the element with the given name under the
+// observed element.
+func (o observed) child(name string) observed {
+	return observed{
+		observer: o.observer,
+		logger:   o.logger,
+		journal:  o.journal,
+		path:     o.path + "/" + name,
+	}
+}
+
+// observe records the named operation started at start in the journal and
+// notifies the observer of it. It is deferred by operations, with a pointer to
+// their error result if they have one.
+func (o observed) observe(name string, start time.Time, err *error) {
+	o.journal.record(name, o.path)
+
+	if o.observer == nil {
+		return
+	}
+
+	op := Operation{
+		Name:     name,
+		Path:     o.path,
+		Duration: time.Since(start),
+	}
+	if err != nil {
+		op.Err = *err
+	}
+
+	o.observer.Observe(op)
+}
+
+// observe notifies the observer of an operation on the ConfigTx. The observe
+// methods of the groups, orgs and MSPs below do the same for operations on
+// them. They tolerate nil receivers, such as the org returned for a name that
+// does not exist.
+func (c *ConfigTx) observe(name string, start time.Time, err *error) {
+	c.observed().observe(name, start, err)
+}
+
+func (c *ChannelGroup) observe(name string, start time.Time, err *error) {
+	if c != nil {
+		c.observed.observe(name, start, err)
+	}
+}
+
+func (a *ApplicationGroup) observe(name string, start time.Time, err *error) {
+	if a != nil {
+		a.observed.observe(name, start, err)
+	}
+}
+
+func (a *ApplicationOrg) observe(name string, start time.Time, err *error) {
+	if a != nil {
+		a.observed.observe(name, start, err)
+	}
+}
+
+func (o *OrdererGroup) observe(name string, start time.Time, err *error) {
+	if o != nil {
+		o.observed.observe(name, start, err)
+	}
+}
+
+func (o *OrdererOrg) observe(name string, start time.Time, err *error) {
+	if o != nil {
+		o.observed.observe(name, start, err)
+	}
+}
+
+func (c *ConsortiumsGroup) observe(name string, start time.Time, err *error) {
+	if c != nil {
+		c.observed.observe(name, start, err)
+	}
+}
+
+func (c *ConsortiumGroup) observe(name string, start time.Time, err *error) {
+	if c != nil {
+		c.observed.observe(name, start, err)
+	}
+}
+
+func (c *ConsortiumOrg) observe(name string, start time.Time, err *error) {
+	if c != nil {
+		c.observed.observe(name, start, err)
+	}
+}
+
+func (m *OrganizationMSP) observe(name string, start time.Time, err *error) {
+	if m != nil {
+		m.observed.observe(name, start, err)
+	}
+}
+
+func (b *BatchSizeValue) observe(name string, start time.Time, err *error) {
+	if b != nil {
+		b.observed.observe(name, start, err)
+	}
+}
+
+func (e *EtcdRaftOptionsValue) observe(name string, start time.Time, err *error) {
+	if e != nil {
+		e.observed.observe(name, start, err)
+	}
+}
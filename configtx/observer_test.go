@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestObserver(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	var ops []Operation
+	c.SetObserver(ObserverFunc(func(op Operation) {
+		ops = append(ops, op)
+	}))
+
+	err = c.Orderer().SetBatchTimeout(5 * time.Second)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c.Orderer().RemoveLegacyKafkaBrokers()
+	err = c.Orderer().Organization("OrdererOrg").SetModPolicy("")
+	gt.Expect(err).To(MatchError("non empty mod policy is required"))
+	err = c.Orderer().Organization("OrdererOrg").MSP().WithCertificateChecks(CertificateChecksLenient, nil).AddRootCertsFromPEM(nil)
+	gt.Expect(err).To(MatchError("parsing PEM bundle: no PEM encoded certificates found"))
+	_, err = c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(ops).To(HaveLen(5))
+	gt.Expect(ops[0].Name).To(Equal("OrdererGroup.SetBatchTimeout"))
+	gt.Expect(ops[0].Path).To(Equal("/Channel/Orderer"))
+	gt.Expect(ops[0].Err).NotTo(HaveOccurred())
+	gt.Expect(ops[0].Duration).To(BeNumerically(">=", 0))
+	gt.Expect(ops[1].Name).To(Equal("OrdererGroup.RemoveLegacyKafkaBrokers"))
+	gt.Expect(ops[1].Path).To(Equal("/Channel/Orderer"))
+	gt.Expect(ops[2].Name).To(Equal("OrdererOrg.SetModPolicy"))
+	gt.Expect(ops[2].Path).To(Equal("/Channel/Orderer/OrdererOrg"))
+	gt.Expect(ops[2].Err).To(MatchError("non empty mod policy is required"))
+	gt.Expect(ops[3].Name).To(Equal("OrganizationMSP.AddRootCertsFromPEM"))
+	gt.Expect(ops[3].Path).To(Equal("/Channel/Orderer/OrdererOrg/Values/MSP"))
+	gt.Expect(ops[3].Err).To(MatchError("parsing PEM bundle: no PEM encoded certificates found"))
+	gt.Expect(ops[4].Name).To(Equal("ConfigTx.ComputeMarshaledUpdate"))
+	gt.Expect(ops[4].Path).To(Equal("/Channel"))
+
+	c.SetObserver(nil)
+	err = c.Channel().SetModPolicy("Admins")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ops).To(HaveLen(5))
+}
+
+func TestObserverValues(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeEtcdRaft)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	var ops []Operation
+	c.SetObserver(ObserverFunc(func(op Operation) {
+		ops = append(ops, op)
+	}))
+
+	batchSize := c.Orderer().BatchSize()
+	gt.Expect(batchSize.SetMaxMessageCount(500)).To(Succeed())
+	gt.Expect(batchSize.SetAbsoluteMaxBytes(1000)).To(Succeed())
+	gt.Expect(batchSize.SetPreferredMaxBytes(500)).To(Succeed())
+	gt.Expect(batchSize.SetAbsoluteMaxBytesString("1 KB")).To(Succeed())
+	err = batchSize.SetPreferredMaxBytesString("many")
+	gt.Expect(err).To(HaveOccurred())
+
+	etcdRaftOptions := c.Orderer().EtcdRaftOptions()
+	gt.Expect(etcdRaftOptions.SetTickInterval("500ms")).To(Succeed())
+	gt.Expect(etcdRaftOptions.SetElectionInterval(50)).To(Succeed())
+	gt.Expect(etcdRaftOptions.SetHeartbeatTick(5)).To(Succeed())
+	gt.Expect(etcdRaftOptions.SetMaxInflightBlocks(10)).To(Succeed())
+	gt.Expect(etcdRaftOptions.SetSnapshotIntervalSize(1000)).To(Succeed())
+
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = op.Name
+	}
+	gt.Expect(names).To(Equal([]string{
+		"BatchSizeValue.SetMaxMessageCount",
+		"BatchSizeValue.SetAbsoluteMaxBytes",
+		"BatchSizeValue.SetPreferredMaxBytes",
+		"BatchSizeValue.SetAbsoluteMaxBytes",
+		"BatchSizeValue.SetAbsoluteMaxBytesString",
+		"BatchSizeValue.SetPreferredMaxBytesString",
+		"EtcdRaftOptionsValue.SetTickInterval",
+		"EtcdRaftOptionsValue.SetElectionInterval",
+		"EtcdRaftOptionsValue.SetHeartbeatTick",
+		"EtcdRaftOptionsValue.SetMaxInflightBlocks",
+		"EtcdRaftOptionsValue.SetSnapshotIntervalSize",
+	}))
+	gt.Expect(ops[0].Path).To(Equal("/Channel/Orderer/Values/BatchSize"))
+	gt.Expect(ops[5].Err).To(Equal(err))
+	gt.Expect(ops[6].Path).To(Equal("/Channel/Orderer/Values/ConsensusType"))
+}
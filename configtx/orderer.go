@@ -7,18 +7,20 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
-	"crypto/x509"
+	"bytes"
 	"encoding/pem"
 	"errors"
 	"fmt"
 	"math"
-	"reflect"
 	"time"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	ob "github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
 	eb "github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/etcdraft"
+	sb "github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/smartbft"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/internal/policydsl"
 	"github.com/hyperledger/fabric-config/configtx/orderer"
 )
 
@@ -30,14 +32,21 @@ const (
 // Orderer configures the ordering service behavior for a channel.
 type Orderer struct {
 	// OrdererType is the type of orderer
-	// Options: `ConsensusTypeSolo`, `ConsensusTypeKafka` or `ConsensusTypeEtcdRaft`
+	// Options: `ConsensusTypeSolo`, `ConsensusTypeKafka`, `ConsensusTypeEtcdRaft`,
+	// `ConsensusTypeSmartBFT` or a type registered with RegisterConsensusType.
+	// The configuration of an existing channel may hold other types, whose
+	// metadata is available through OrdererGroup.ConsensusMetadata.
 	OrdererType string
 	// BatchTimeout is the wait time between transactions.
-	BatchTimeout  time.Duration
-	BatchSize     orderer.BatchSize
-	Kafka         orderer.Kafka
-	EtcdRaft      orderer.EtcdRaft
-	Organizations []Organization
+	BatchTimeout time.Duration
+	BatchSize    orderer.BatchSize
+	Kafka        orderer.Kafka
+	EtcdRaft     orderer.EtcdRaft
+	SmartBFT     orderer.SmartBFT
+	// CustomMetadata is the consensus metadata of a type registered with
+	// RegisterConsensusType, as handled by its ConsensusTypeHandler.
+	CustomMetadata interface{}
+	Organizations  []Organization
 	// MaxChannels is the maximum count of channels an orderer supports.
 	MaxChannels uint64
 	// Capabilities is a map of the capabilities the orderer supports.
@@ -53,6 +62,17 @@ type Orderer struct {
 type OrdererGroup struct {
 	channelGroup *cb.ConfigGroup
 	ordererGroup *cb.ConfigGroup
+	// originalChannelGroup is the channel group of the original config, if
+	// known. It is used to enforce the maintenance mode rules.
+	originalChannelGroup *cb.ConfigGroup
+	// consenterChecks enables the checks of WithConsenterChecks.
+	consenterChecks bool
+	// reachability holds the checks of WithReachabilityChecks.
+	reachability reachabilityChecks
+	// skipSubPolicyChecks disables the checks of the ImplicitMeta
+	// sub-policies, see WithoutSubPolicyChecks.
+	skipSubPolicyChecks bool
+	observed            observed
 }
 
 // OrdererOrg encapsulates the parts of the config that control
@@ -60,30 +80,114 @@ type OrdererGroup struct {
 type OrdererOrg struct {
 	orgGroup *cb.ConfigGroup
 	name     string
+	// endpointChecks holds the checks of WithEndpointTLSCerts and
+	// WithEndpointTLSProbe.
+	endpointChecks endpointTLSChecks
+	// reachability holds the checks of the WithReachabilityChecks of the
+	// orderer group the org was retrieved from.
+	reachability reachabilityChecks
+	observed     observed
 }
 
 // MSP returns an OrganizationMSP object that can be used to configure the organization's MSP.
 func (o *OrdererOrg) MSP() *OrganizationMSP {
 	return &OrganizationMSP{
 		configGroup: o.orgGroup,
+		observed:    o.observed.child("Values/" + MSPKey),
 	}
 }
 
 // EtcdRaftOptionsValue encapsulates the configuration functions used to modify an etcdraft configuration's options.
 type EtcdRaftOptionsValue struct {
-	value *cb.ConfigValue
+	value    *cb.ConfigValue
+	observed observed
 }
 
 // BatchSizeValue encapsulates the configuration functions used to modify an orderer configuration's batch size values.
 type BatchSizeValue struct {
-	value *cb.ConfigValue
+	value    *cb.ConfigValue
+	observed observed
+}
+
+// Validate checks that the orderer configuration is complete and consistent
+// for its consensus type.
+func (o Orderer) Validate() error {
+	if err := o.validateValues(); err != nil {
+		return err
+	}
+
+	if err := validatePolicies(o.Policies, BlockValidationPolicyKey); err != nil {
+		return err
+	}
+
+	names := map[string]struct{}{}
+	for _, org := range o.Organizations {
+		if err := org.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := names[org.Name]; ok {
+			return fmt.Errorf("duplicate orderer org %s", org.Name)
+		}
+		names[org.Name] = struct{}{}
+
+		if len(org.OrdererEndpoints) == 0 {
+			return fmt.Errorf("orderer endpoints are not defined for org %s", org.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateValues checks the parts of the orderer configuration stored in the
+// values of the orderer group: the consensus type and its metadata, the
+// consensus state, the batch timeout and the batch size.
+func (o Orderer) validateValues() error {
+	switch o.OrdererType {
+	case orderer.ConsensusTypeSolo:
+	case orderer.ConsensusTypeKafka:
+		if err := o.Kafka.Validate(); err != nil {
+			return err
+		}
+	case orderer.ConsensusTypeEtcdRaft:
+		if err := o.EtcdRaft.Validate(); err != nil {
+			return fmt.Errorf("invalid etcdraft configuration: %v", err)
+		}
+	case orderer.ConsensusTypeSmartBFT:
+		if err := o.SmartBFT.Validate(); err != nil {
+			return fmt.Errorf("invalid smartbft configuration: %v", err)
+		}
+	default:
+		handler, ok := consensusTypeHandler(o.OrdererType)
+		if !ok {
+			return fmt.Errorf("unknown orderer type '%s'", o.OrdererType)
+		}
+		if err := handler.ValidateMetadata(o.CustomMetadata); err != nil {
+			return fmt.Errorf("invalid %s configuration: %v", o.OrdererType, err)
+		}
+	}
+
+	if _, ok := ob.ConsensusType_State_value[string(o.State)]; !ok {
+		return fmt.Errorf("unknown consensus state '%s'", o.State)
+	}
+
+	if o.BatchTimeout < 0 {
+		return fmt.Errorf("batch timeout %s cannot be negative", o.BatchTimeout)
+	}
+
+	return o.BatchSize.Validate()
 }
 
 // Orderer returns the orderer group from the updated config.
 func (c *ConfigTx) Orderer() *OrdererGroup {
 	channelGroup := c.updated.ChannelGroup
 	ordererGroup := channelGroup.Groups[OrdererGroupKey]
-	return &OrdererGroup{channelGroup: channelGroup, ordererGroup: ordererGroup}
+	return &OrdererGroup{
+		channelGroup:         channelGroup,
+		ordererGroup:         ordererGroup,
+		originalChannelGroup: c.original.ChannelGroup,
+		observed:             c.observed().child(OrdererGroupKey),
+	}
 }
 
 // Organization returns the orderer org from the updated config.
@@ -92,7 +196,7 @@ func (o *OrdererGroup) Organization(name string) *OrdererOrg {
 	if !ok {
 		return nil
 	}
-	return &OrdererOrg{name: name, orgGroup: orgGroup}
+	return &OrdererOrg{name: name, orgGroup: orgGroup, reachability: o.reachability, observed: o.observed.child(name)}
 }
 
 // Configuration returns the existing orderer configuration values from the updated
@@ -101,6 +205,8 @@ func (o *OrdererGroup) Organization(name string) *OrdererOrg {
 func (o *OrdererGroup) Configuration() (Orderer, error) {
 	// CONSENSUS TYPE, STATE, AND METADATA
 	var etcdRaft orderer.EtcdRaft
+	var smartBFT orderer.SmartBFT
+	var customMetadata interface{}
 	kafkaBrokers := orderer.Kafka{}
 
 	consensusTypeProto := &ob.ConsensusType{}
@@ -132,8 +238,21 @@ func (o *OrdererGroup) Configuration() (Orderer, error) {
 		if err != nil {
 			return Orderer{}, fmt.Errorf("unmarshaling etcd raft metadata: %v", err)
 		}
+	case orderer.ConsensusTypeSmartBFT:
+		smartBFT, err = unmarshalSmartBFTMetadata(consensusTypeProto.Metadata)
+		if err != nil {
+			return Orderer{}, fmt.Errorf("unmarshaling smartbft metadata: %v", err)
+		}
 	default:
-		return Orderer{}, fmt.Errorf("config contains unknown consensus type '%s'", consensusTypeProto.Type)
+		// the metadata of consensus types this package does not model and
+		// which are not registered, such as custom consensus plugins, is
+		// available through ConsensusMetadata
+		if handler, ok := consensusTypeHandler(consensusTypeProto.Type); ok {
+			customMetadata, err = handler.UnmarshalMetadata(consensusTypeProto.Metadata)
+			if err != nil {
+				return Orderer{}, fmt.Errorf("unmarshaling %s metadata: %v", consensusTypeProto.Type, err)
+			}
+		}
 	}
 
 	// BATCHSIZE AND TIMEOUT
@@ -156,7 +275,7 @@ func (o *OrdererGroup) Configuration() (Orderer, error) {
 
 	// ORDERER ORGS
 	var ordererOrgs []Organization
-	for orgName := range o.ordererGroup.Groups {
+	for _, orgName := range sortedGroupKeys(o.ordererGroup) {
 		orgConfig, err := o.Organization(orgName).Configuration()
 		if err != nil {
 			return Orderer{}, fmt.Errorf("retrieving orderer org %s: %v", orgName, err)
@@ -192,28 +311,33 @@ func (o *OrdererGroup) Configuration() (Orderer, error) {
 			AbsoluteMaxBytes:  batchSize.AbsoluteMaxBytes,
 			PreferredMaxBytes: batchSize.PreferredMaxBytes,
 		},
-		Kafka:         kafkaBrokers,
-		EtcdRaft:      etcdRaft,
-		Organizations: ordererOrgs,
-		MaxChannels:   channelRestrictions.MaxCount,
-		Capabilities:  capabilities,
-		Policies:      policies,
-		State:         state,
-		ModPolicy:     o.ordererGroup.GetModPolicy(),
+		Kafka:          kafkaBrokers,
+		EtcdRaft:       etcdRaft,
+		SmartBFT:       smartBFT,
+		CustomMetadata: customMetadata,
+		Organizations:  ordererOrgs,
+		MaxChannels:    channelRestrictions.MaxCount,
+		Capabilities:   capabilities,
+		Policies:       policies,
+		State:          state,
+		ModPolicy:      o.ordererGroup.GetModPolicy(),
 	}, nil
 }
 
 // BatchSize returns a BatchSizeValue that can be used to configure an orderer configuration's batch size parameters.
 func (o *OrdererGroup) BatchSize() *BatchSizeValue {
 	return &BatchSizeValue{
-		value: o.ordererGroup.Values[orderer.BatchSizeKey],
+		value:    o.ordererGroup.Values[orderer.BatchSizeKey],
+		observed: o.observed.child("Values/" + orderer.BatchSizeKey),
 	}
 }
 
 // SetMaxMessageCount sets an orderer configuration's batch size max message count.
-func (b *BatchSizeValue) SetMaxMessageCount(maxMessageCount uint32) error {
+func (b *BatchSizeValue) SetMaxMessageCount(maxMessageCount uint32) (err error) {
+	defer b.observe("BatchSizeValue.SetMaxMessageCount", time.Now(), &err)
+
 	batchSize := &ob.BatchSize{}
-	err := proto.Unmarshal(b.value.Value, batchSize)
+	err = proto.Unmarshal(b.value.Value, batchSize)
 	if err != nil {
 		return err
 	}
@@ -225,9 +349,11 @@ func (b *BatchSizeValue) SetMaxMessageCount(maxMessageCount uint32) error {
 }
 
 // SetAbsoluteMaxBytes sets an orderer configuration's batch size max block size.
-func (b *BatchSizeValue) SetAbsoluteMaxBytes(maxBytes uint32) error {
+func (b *BatchSizeValue) SetAbsoluteMaxBytes(maxBytes uint32) (err error) {
+	defer b.observe("BatchSizeValue.SetAbsoluteMaxBytes", time.Now(), &err)
+
 	batchSize := &ob.BatchSize{}
-	err := proto.Unmarshal(b.value.Value, batchSize)
+	err = proto.Unmarshal(b.value.Value, batchSize)
 	if err != nil {
 		return err
 	}
@@ -239,9 +365,11 @@ func (b *BatchSizeValue) SetAbsoluteMaxBytes(maxBytes uint32) error {
 }
 
 // SetPreferredMaxBytes sets an orderer configuration's batch size preferred size of blocks.
-func (b *BatchSizeValue) SetPreferredMaxBytes(maxBytes uint32) error {
+func (b *BatchSizeValue) SetPreferredMaxBytes(maxBytes uint32) (err error) {
+	defer b.observe("BatchSizeValue.SetPreferredMaxBytes", time.Now(), &err)
+
 	batchSize := &ob.BatchSize{}
-	err := proto.Unmarshal(b.value.Value, batchSize)
+	err = proto.Unmarshal(b.value.Value, batchSize)
 	if err != nil {
 		return err
 	}
@@ -252,30 +380,145 @@ func (b *BatchSizeValue) SetPreferredMaxBytes(maxBytes uint32) error {
 	return err
 }
 
+// SetAbsoluteMaxBytesString sets an orderer configuration's batch size max
+// block size from a size such as "10 MB", see orderer.ParseByteSize.
+func (b *BatchSizeValue) SetAbsoluteMaxBytesString(maxBytes string) (err error) {
+	defer b.observe("BatchSizeValue.SetAbsoluteMaxBytesString", time.Now(), &err)
+
+	size, err := orderer.ParseByteSize(maxBytes)
+	if err != nil {
+		return err
+	}
+
+	return b.SetAbsoluteMaxBytes(size)
+}
+
+// SetPreferredMaxBytesString sets an orderer configuration's batch size
+// preferred size of blocks from a size such as "512 KiB", see
+// orderer.ParseByteSize.
+func (b *BatchSizeValue) SetPreferredMaxBytesString(maxBytes string) (err error) {
+	defer b.observe("BatchSizeValue.SetPreferredMaxBytesString", time.Now(), &err)
+
+	size, err := orderer.ParseByteSize(maxBytes)
+	if err != nil {
+		return err
+	}
+
+	return b.SetPreferredMaxBytes(size)
+}
+
+// AbsoluteMaxBytesString returns an orderer configuration's batch size max
+// block size formatted by orderer.FormatByteSize, such as "10 MiB".
+func (b *BatchSizeValue) AbsoluteMaxBytesString() (string, error) {
+	batchSize := &ob.BatchSize{}
+	err := proto.Unmarshal(b.value.Value, batchSize)
+	if err != nil {
+		return "", err
+	}
+
+	return orderer.FormatByteSize(batchSize.AbsoluteMaxBytes), nil
+}
+
+// PreferredMaxBytesString returns an orderer configuration's batch size
+// preferred size of blocks formatted by orderer.FormatByteSize, such as
+// "512 KiB".
+func (b *BatchSizeValue) PreferredMaxBytesString() (string, error) {
+	batchSize := &ob.BatchSize{}
+	err := proto.Unmarshal(b.value.Value, batchSize)
+	if err != nil {
+		return "", err
+	}
+
+	return orderer.FormatByteSize(batchSize.PreferredMaxBytes), nil
+}
+
 // SetBatchTimeout sets the wait time between transactions.
-func (o *OrdererGroup) SetBatchTimeout(timeout time.Duration) error {
+func (o *OrdererGroup) SetBatchTimeout(timeout time.Duration) (err error) {
+	defer o.observe("OrdererGroup.SetBatchTimeout", time.Now(), &err)
+
 	return setValue(o.ordererGroup, batchTimeoutValue(timeout.String()), AdminsPolicyKey)
 }
 
+// SetBatchTimeoutString sets the wait time between transactions from a
+// duration such as "2s" or "500ms", see orderer.ParseBatchTimeout.
+func (o *OrdererGroup) SetBatchTimeoutString(timeout string) (err error) {
+	defer o.observe("OrdererGroup.SetBatchTimeoutString", time.Now(), &err)
+
+	duration, err := orderer.ParseBatchTimeout(timeout)
+	if err != nil {
+		return fmt.Errorf("invalid batch timeout '%s': %v", timeout, err)
+	}
+
+	return setValue(o.ordererGroup, batchTimeoutValue(duration.String()), AdminsPolicyKey)
+}
+
 // SetMaxChannels sets the maximum count of channels an orderer supports.
-func (o *OrdererGroup) SetMaxChannels(max int) error {
+func (o *OrdererGroup) SetMaxChannels(max int) (err error) {
+	defer o.observe("OrdererGroup.SetMaxChannels", time.Now(), &err)
+
 	return setValue(o.ordererGroup, channelRestrictionsValue(uint64(max)), AdminsPolicyKey)
 }
 
 // SetEtcdRaftConsensusType sets the orderer consensus type to etcdraft, sets etcdraft metadata, and consensus state.
-func (o *OrdererGroup) SetEtcdRaftConsensusType(consensusMetadata orderer.EtcdRaft, consensusState orderer.ConsensusState) error {
+func (o *OrdererGroup) SetEtcdRaftConsensusType(consensusMetadata orderer.EtcdRaft, consensusState orderer.ConsensusState) (err error) {
+	defer o.observe("OrdererGroup.SetEtcdRaftConsensusType", time.Now(), &err)
+
+	err = o.checkEtcdRaftConsenters(consensusMetadata.Consenters)
+	if err != nil {
+		return err
+	}
+
 	consensusMetadataBytes, err := marshalEtcdRaftMetadata(consensusMetadata)
 	if err != nil {
 		return fmt.Errorf("marshaling etcdraft metadata: %v", err)
 	}
 
+	capabilities, err := o.Capabilities()
+	if err != nil {
+		return err
+	}
+
+	err = o.checkConsensusCapabilities(orderer.ConsensusTypeEtcdRaft, capabilities)
+	if err != nil {
+		return err
+	}
+
 	return setValue(o.ordererGroup, consensusTypeValue(orderer.ConsensusTypeEtcdRaft, consensusMetadataBytes, ob.ConsensusType_State_value[string(consensusState)]), AdminsPolicyKey)
 }
 
+// SetSmartBFTConsensusType sets the orderer consensus type to smartbft, sets smartbft metadata, and consensus state.
+func (o *OrdererGroup) SetSmartBFTConsensusType(consensusMetadata orderer.SmartBFT, consensusState orderer.ConsensusState) (err error) {
+	defer o.observe("OrdererGroup.SetSmartBFTConsensusType", time.Now(), &err)
+
+	err = o.checkSmartBFTConsenters(consensusMetadata.Consenters)
+	if err != nil {
+		return err
+	}
+
+	consensusMetadataBytes, err := marshalSmartBFTMetadata(consensusMetadata)
+	if err != nil {
+		return fmt.Errorf("marshaling smartbft metadata: %v", err)
+	}
+
+	capabilities, err := o.Capabilities()
+	if err != nil {
+		return err
+	}
+
+	err = o.checkConsensusCapabilities(orderer.ConsensusTypeSmartBFT, capabilities)
+	if err != nil {
+		return err
+	}
+
+	return setValue(o.ordererGroup, consensusTypeValue(orderer.ConsensusTypeSmartBFT, consensusMetadataBytes, ob.ConsensusType_State_value[string(consensusState)]), AdminsPolicyKey)
+}
+
 // SetConsensusState sets the consensus state.
-func (o *OrdererGroup) SetConsensusState(consensusState orderer.ConsensusState) error {
+func (o *OrdererGroup) SetConsensusState(consensusState orderer.ConsensusState) (err error) {
+	defer o.observe("OrdererGroup.SetConsensusState", time.Now(), &err)
+
 	consensusTypeProto := &ob.ConsensusType{}
-	err := unmarshalConfigValueAtKey(o.ordererGroup, orderer.ConsensusTypeKey, consensusTypeProto)
+	err = unmarshalConfigValueAtKey(o.ordererGroup, orderer.ConsensusTypeKey, consensusTypeProto)
 	if err != nil {
 		return err
 	}
@@ -283,10 +526,57 @@ func (o *OrdererGroup) SetConsensusState(consensusState orderer.ConsensusState)
 	return setValue(o.ordererGroup, consensusTypeValue(consensusTypeProto.Type, consensusTypeProto.Metadata, ob.ConsensusType_State_value[string(consensusState)]), AdminsPolicyKey)
 }
 
+// ConsensusMetadata returns the raw consensus metadata of the orderer. It
+// allows to administer channels running a consensus type which this package
+// does not model, such as a custom consensus plugin.
+func (o *OrdererGroup) ConsensusMetadata() ([]byte, error) {
+	consensusTypeProto := &ob.ConsensusType{}
+	err := unmarshalConfigValueAtKey(o.ordererGroup, orderer.ConsensusTypeKey, consensusTypeProto)
+	if err != nil {
+		return nil, err
+	}
+
+	return consensusTypeProto.Metadata, nil
+}
+
+// SetConsensusMetadata sets the raw consensus metadata of the orderer, leaving
+// the consensus type and state unchanged. The metadata of etcdraft and smartbft
+// orderers must unmarshal as such, so that their consenters can still be
+// administered.
+func (o *OrdererGroup) SetConsensusMetadata(metadata []byte) (err error) {
+	defer o.observe("OrdererGroup.SetConsensusMetadata", time.Now(), &err)
+
+	consensusTypeProto := &ob.ConsensusType{}
+	err = unmarshalConfigValueAtKey(o.ordererGroup, orderer.ConsensusTypeKey, consensusTypeProto)
+	if err != nil {
+		return err
+	}
+
+	switch consensusTypeProto.Type {
+	case orderer.ConsensusTypeEtcdRaft:
+		if _, err := unmarshalEtcdRaftMetadata(metadata); err != nil {
+			return fmt.Errorf("unmarshaling etcd raft metadata: %v", err)
+		}
+	case orderer.ConsensusTypeSmartBFT:
+		if _, err := unmarshalSmartBFTMetadata(metadata); err != nil {
+			return fmt.Errorf("unmarshaling smartbft metadata: %v", err)
+		}
+	default:
+		if handler, ok := consensusTypeHandler(consensusTypeProto.Type); ok {
+			if _, err := handler.UnmarshalMetadata(metadata); err != nil {
+				return fmt.Errorf("unmarshaling %s metadata: %v", consensusTypeProto.Type, err)
+			}
+		}
+	}
+
+	return setValue(o.ordererGroup, consensusTypeValue(consensusTypeProto.Type, metadata, int32(consensusTypeProto.State)), AdminsPolicyKey)
+}
+
 // EtcdRaftOptions returns an EtcdRaftOptionsValue that can be used to configure an etcdraft configuration's options.
 func (o *OrdererGroup) EtcdRaftOptions() *EtcdRaftOptionsValue {
 	return &EtcdRaftOptionsValue{
-		value: o.ordererGroup.Values[orderer.ConsensusTypeKey],
+		value:    o.ordererGroup.Values[orderer.ConsensusTypeKey],
+		observed: o.observed.child("Values/" + orderer.ConsensusTypeKey),
 	}
 }
 
@@ -312,7 +602,9 @@ func (e *EtcdRaftOptionsValue) setEtcdRaftConfig(consensusTypeProto *ob.Consensu
 }
 
 // SetTickInterval sets the Etcdraft's tick interval.
-func (e *EtcdRaftOptionsValue) SetTickInterval(interval string) error {
+func (e *EtcdRaftOptionsValue) SetTickInterval(interval string) (err error) {
+	defer e.observe("EtcdRaftOptionsValue.SetTickInterval", time.Now(), &err)
+
 	consensusTypeProto := &ob.ConsensusType{}
 	etcdRaft, err := e.etcdRaftConfig(consensusTypeProto)
 	if err != nil {
@@ -324,7 +616,9 @@ func (e *EtcdRaftOptionsValue) SetTickInterval(interval string) error {
 }
 
 // SetElectionInterval sets the Etcdraft's election interval.
-func (e *EtcdRaftOptionsValue) SetElectionInterval(interval uint32) error {
+func (e *EtcdRaftOptionsValue) SetElectionInterval(interval uint32) (err error) {
+	defer e.observe("EtcdRaftOptionsValue.SetElectionInterval", time.Now(), &err)
+
 	consensusTypeProto := &ob.ConsensusType{}
 	etcdRaft, err := e.etcdRaftConfig(consensusTypeProto)
 	if err != nil {
@@ -336,7 +630,9 @@ func (e *EtcdRaftOptionsValue) SetElectionInterval(interval uint32) error {
 }
 
 // SetHeartbeatTick sets the Etcdraft's heartbeat tick interval.
-func (e *EtcdRaftOptionsValue) SetHeartbeatTick(tick uint32) error {
+func (e *EtcdRaftOptionsValue) SetHeartbeatTick(tick uint32) (err error) {
+	defer e.observe("EtcdRaftOptionsValue.SetHeartbeatTick", time.Now(), &err)
+
 	consensusTypeProto := &ob.ConsensusType{}
 	etcdRaft, err := e.etcdRaftConfig(consensusTypeProto)
 	if err != nil {
@@ -348,7 +644,9 @@ func (e *EtcdRaftOptionsValue) SetHeartbeatTick(tick uint32) error {
 }
 
 // SetMaxInflightBlocks sets the Etcdraft's max inflight blocks.
-func (e *EtcdRaftOptionsValue) SetMaxInflightBlocks(maxBlks uint32) error {
+func (e *EtcdRaftOptionsValue) SetMaxInflightBlocks(maxBlks uint32) (err error) {
+	defer e.observe("EtcdRaftOptionsValue.SetMaxInflightBlocks", time.Now(), &err)
+
 	consensusTypeProto := &ob.ConsensusType{}
 	etcdRaft, err := e.etcdRaftConfig(consensusTypeProto)
 	if err != nil {
@@ -360,7 +658,9 @@ func (e *EtcdRaftOptionsValue) SetMaxInflightBlocks(maxBlks uint32) error {
 }
 
 // SetSnapshotIntervalSize sets the Etcdraft's snapshot interval size.
-func (e *EtcdRaftOptionsValue) SetSnapshotIntervalSize(intervalSize uint32) error {
+func (e *EtcdRaftOptionsValue) SetSnapshotIntervalSize(intervalSize uint32) (err error) {
+	defer e.observe("EtcdRaftOptionsValue.SetSnapshotIntervalSize", time.Now(), &err)
+
 	consensusTypeProto := &ob.ConsensusType{}
 	etcdRaft, err := e.etcdRaftConfig(consensusTypeProto)
 	if err != nil {
@@ -404,7 +704,9 @@ func (o *OrdererOrg) Configuration() (Organization, error) {
 // SetOrganization sets the organization config group for the given orderer
 // org key in an existing Orderer configuration's Groups map.
 // If the orderer org already exists in the current configuration, its value will be overwritten.
-func (o *OrdererGroup) SetOrganization(org Organization) error {
+func (o *OrdererGroup) SetOrganization(org Organization) (err error) {
+	defer o.observe("OrdererGroup.SetOrganization", time.Now(), &err)
+
 	orgGroup, err := newOrdererOrgConfigGroup(org)
 	if err != nil {
 		return fmt.Errorf("failed to create orderer org %s: %v", org.Name, err)
@@ -417,15 +719,160 @@ func (o *OrdererGroup) SetOrganization(org Organization) error {
 
 // RemoveOrganization removes an org from the Orderer group.
 // Removal will panic if the orderer group does not exist.
+// A warning is logged for every policy the removal leaves unsatisfiable.
 func (o *OrdererGroup) RemoveOrganization(name string) {
+	defer o.observe("OrdererGroup.RemoveOrganization", time.Now(), nil)
+	defer o.observed.watchSatisfiability(o.channelGroup)()
+
 	delete(o.ordererGroup.Groups, name)
 }
 
+// RemoveOrganizationAndConsenters removes an org from the Orderer group along
+// with everything in the config which refers to it: the etcdraft or SmartBFT
+// consenters of the org, and the endpoints of the org and addresses of its
+// consenters in the deprecated channel level OrdererAddresses value. A
+// consenter belongs to the org if its server TLS cert chains to the TLS root
+// certs of the org or, for SmartBFT only, if it has the MSP ID of the org. The
+// OrdererAddresses value is removed once it no longer contains any address.
+// An error is returned and the config is left untouched if the org does not
+// exist or its removal would leave no consenter.
+// A warning is logged for every policy the removal leaves unsatisfiable.
+func (o *OrdererGroup) RemoveOrganizationAndConsenters(name string) (err error) {
+	defer o.observe("OrdererGroup.RemoveOrganizationAndConsenters", time.Now(), &err)
+	defer o.observed.watchSatisfiability(o.channelGroup)()
+
+	if o.Organization(name) == nil {
+		return fmt.Errorf("orderer org %s does not exist", name)
+	}
+
+	cfg, err := o.Configuration()
+	if err != nil {
+		return err
+	}
+
+	removedAddresses := map[string]bool{}
+	for _, org := range cfg.Organizations {
+		if org.Name == name {
+			for _, endpoint := range org.OrdererEndpoints {
+				removedAddresses[endpoint] = true
+			}
+		}
+	}
+
+	removedConsenters := 0
+	switch cfg.OrdererType {
+	case orderer.ConsensusTypeEtcdRaft:
+		var consenters []orderer.Consenter
+		for _, consenter := range cfg.EtcdRaft.Consenters {
+			if etcdRaftConsenterOrg(cfg.Organizations, consenter) != name {
+				consenters = append(consenters, consenter)
+				continue
+			}
+			removedAddresses[consenterAddress(consenter.Address)] = true
+			removedConsenters++
+		}
+		if len(consenters) == 0 && removedConsenters != 0 {
+			return fmt.Errorf("removing orderer org %s would remove every consenter", name)
+		}
+		cfg.EtcdRaft.Consenters = consenters
+	case orderer.ConsensusTypeSmartBFT:
+		var consenters []orderer.SmartBFTConsenter
+		for _, consenter := range cfg.SmartBFT.Consenters {
+			if smartBFTConsenterOrg(cfg.Organizations, consenter) != name {
+				consenters = append(consenters, consenter)
+				continue
+			}
+			removedAddresses[consenterAddress(consenter.Address)] = true
+			removedConsenters++
+		}
+		if len(consenters) == 0 && removedConsenters != 0 {
+			return fmt.Errorf("removing orderer org %s would remove every consenter", name)
+		}
+		cfg.SmartBFT.Consenters = consenters
+	}
+
+	legacyAddresses := &cb.OrdererAddresses{}
+	legacyValue, hasLegacyAddresses := o.channelGroup.Values[OrdererAddressesKey]
+	if hasLegacyAddresses {
+		err = proto.Unmarshal(legacyValue.Value, legacyAddresses)
+		if err != nil {
+			return fmt.Errorf("unmarshaling legacy orderer addresses: %v", err)
+		}
+	}
+
+	if removedConsenters != 0 {
+		switch cfg.OrdererType {
+		case orderer.ConsensusTypeEtcdRaft:
+			consensusMetadata, err := marshalEtcdRaftMetadata(cfg.EtcdRaft)
+			if err != nil {
+				return fmt.Errorf("marshaling etcdraft metadata: %v", err)
+			}
+
+			consensusState, ok := ob.ConsensusType_State_value[string(cfg.State)]
+			if !ok {
+				return fmt.Errorf("unknown consensus state '%s'", cfg.State)
+			}
+
+			err = setValue(o.ordererGroup, consensusTypeValue(cfg.OrdererType, consensusMetadata, consensusState), AdminsPolicyKey)
+			if err != nil {
+				return err
+			}
+		case orderer.ConsensusTypeSmartBFT:
+			err = o.setSmartBFTConsenters(cfg)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	delete(o.ordererGroup.Groups, name)
+
+	if !hasLegacyAddresses {
+		return nil
+	}
+
+	var addresses []string
+	for _, address := range legacyAddresses.Addresses {
+		if !removedAddresses[address] {
+			addresses = append(addresses, address)
+		}
+	}
+
+	switch {
+	case len(addresses) == len(legacyAddresses.Addresses):
+	case len(addresses) == 0:
+		delete(o.channelGroup.Values, OrdererAddressesKey)
+	default:
+		legacyValue.Value, err = proto.Marshal(&cb.OrdererAddresses{Addresses: addresses})
+		if err != nil {
+			return fmt.Errorf("marshaling legacy orderer addresses: %v", err)
+		}
+	}
+
+	return nil
+}
+
 // SetConfiguration modifies an updated config's Orderer configuration
 // via the passed in Orderer values. It skips updating OrdererOrgGroups and Policies.
-func (o *OrdererGroup) SetConfiguration(ord Orderer) error {
+func (o *OrdererGroup) SetConfiguration(ord Orderer) (err error) {
+	defer o.observe("OrdererGroup.SetConfiguration", time.Now(), &err)
+
+	// addOrdererValues keeps the existing capabilities when none are passed
+	capabilities := ord.Capabilities
+	if len(capabilities) == 0 {
+		capabilities, err = o.Capabilities()
+		if err != nil {
+			return err
+		}
+	}
+
+	err = o.checkConsensusCapabilities(ord.OrdererType, capabilities)
+	if err != nil {
+		return err
+	}
+
 	// update orderer values
-	err := addOrdererValues(o.ordererGroup, ord)
+	err = addOrdererValues(o.ordererGroup, ord)
 	if err != nil {
 		return err
 	}
@@ -433,8 +880,93 @@ func (o *OrdererGroup) SetConfiguration(ord Orderer) error {
 	return nil
 }
 
+// Update applies the changes update makes to the orderer configuration, such
+// as to the batch size, batch timeout and consensus options, in one go. Each
+// orderer value is rewritten at most once and only if its content changes, so
+// that the config update bumps the version of every touched value once no
+// matter how many of its fields changed. If update returns an error or the
+// changed configuration is invalid, the updated config is left untouched. As
+// with SetConfiguration, changes to the organizations and policies are
+// ignored, and changes to the SmartBFT consenters require a call to
+// SetBFTBlockValidationPolicy. Orderer values the changed configuration no
+// longer has, such as the Kafka brokers after a change of the orderer type,
+// are removed.
+func (o *OrdererGroup) Update(update func(cfg *Orderer) error) (err error) {
+	defer o.observe("OrdererGroup.Update", time.Now(), &err)
+
+	cfg, err := o.Configuration()
+	if err != nil {
+		return err
+	}
+
+	err = update(&cfg)
+	if err != nil {
+		return err
+	}
+
+	err = cfg.validateValues()
+	if err != nil {
+		return err
+	}
+
+	err = o.checkConsensusCapabilities(cfg.OrdererType, cfg.Capabilities)
+	if err != nil {
+		return err
+	}
+
+	switch cfg.OrdererType {
+	case orderer.ConsensusTypeEtcdRaft:
+		err = o.checkEtcdRaftConsenters(cfg.EtcdRaft.Consenters)
+	case orderer.ConsensusTypeSmartBFT:
+		err = o.checkSmartBFTConsenters(cfg.SmartBFT.Consenters)
+	}
+	if err != nil {
+		return err
+	}
+
+	updated := &cb.ConfigGroup{}
+	err = addOrdererValues(updated, cfg)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range updated.Values {
+		existing, ok := o.ordererGroup.Values[key]
+		if ok && sameConfigValue(key, existing, value) {
+			continue
+		}
+		o.ordererGroup.Values[key] = value
+	}
+
+	for _, key := range ordererValueKeys {
+		if _, ok := updated.Values[key]; !ok {
+			delete(o.ordererGroup.Values, key)
+		}
+	}
+
+	return nil
+}
+
+// sameConfigValue reports whether the config values stored under key have
+// the same content. Capabilities are compared after unmarshaling, as the
+// marshaled order of their map entries is not deterministic.
+func sameConfigValue(key string, a, b *cb.ConfigValue) bool {
+	if key != CapabilitiesKey {
+		return bytes.Equal(a.Value, b.Value)
+	}
+
+	capabilitiesA, capabilitiesB := &cb.Capabilities{}, &cb.Capabilities{}
+	if proto.Unmarshal(a.Value, capabilitiesA) != nil || proto.Unmarshal(b.Value, capabilitiesB) != nil {
+		return false
+	}
+
+	return proto.Equal(capabilitiesA, capabilitiesB)
+}
+
 // AddConsenter adds a consenter to an etcdraft configuration.
-func (o *OrdererGroup) AddConsenter(consenter orderer.Consenter) error {
+func (o *OrdererGroup) AddConsenter(consenter orderer.Consenter) (err error) {
+	defer o.observe("OrdererGroup.AddConsenter", time.Now(), &err)
+
 	cfg, err := o.Configuration()
 	if err != nil {
 		return err
@@ -444,14 +976,33 @@ func (o *OrdererGroup) AddConsenter(consenter orderer.Consenter) error {
 		return fmt.Errorf("consensus type %s is not etcdraft", cfg.OrdererType)
 	}
 
+	consenter.Address, err = normalizedConsenterAddress(consenter.Address)
+	if err != nil {
+		return err
+	}
+
 	for _, c := range cfg.EtcdRaft.Consenters {
-		if reflect.DeepEqual(c, consenter) {
+		if sameConsenter(c, consenter) {
 			return nil
 		}
 	}
 
+	err = o.checkEtcdRaftConsenters([]orderer.Consenter{consenter})
+	if err != nil {
+		return err
+	}
+
+	err = o.checkConsenterReachability(consenter)
+	if err != nil {
+		return err
+	}
+
 	cfg.EtcdRaft.Consenters = append(cfg.EtcdRaft.Consenters, consenter)
 
+	address := consenterAddress(consenter.Address)
+	o.observed.warnExpiringCert(address, "client tls cert", consenter.ClientTLSCert)
+	o.observed.warnExpiringCert(address, "server tls cert", consenter.ServerTLSCert)
+
 	consensusMetadata, err := marshalEtcdRaftMetadata(cfg.EtcdRaft)
 	if err != nil {
 		return fmt.Errorf("marshaling etcdraft metadata: %v", err)
@@ -471,7 +1022,11 @@ func (o *OrdererGroup) AddConsenter(consenter orderer.Consenter) error {
 }
 
 // RemoveConsenter removes a consenter from an etcdraft configuration.
-func (o *OrdererGroup) RemoveConsenter(consenter orderer.Consenter) error {
+// A warning is logged for every policy the removal leaves unsatisfiable.
+func (o *OrdererGroup) RemoveConsenter(consenter orderer.Consenter) (err error) {
+	defer o.observe("OrdererGroup.RemoveConsenter", time.Now(), &err)
+	defer o.observed.watchSatisfiability(o.channelGroup)()
+
 	cfg, err := o.Configuration()
 	if err != nil {
 		return err
@@ -483,7 +1038,7 @@ func (o *OrdererGroup) RemoveConsenter(consenter orderer.Consenter) error {
 
 	consenters := cfg.EtcdRaft.Consenters[:]
 	for i, c := range cfg.EtcdRaft.Consenters {
-		if reflect.DeepEqual(c, consenter) {
+		if sameConsenter(c, consenter) {
 			consenters = append(consenters[:i], consenters[i+1:]...)
 			break
 		}
@@ -509,6 +1064,179 @@ func (o *OrdererGroup) RemoveConsenter(consenter orderer.Consenter) error {
 	return nil
 }
 
+// AddSmartBFTConsenter adds a consenter to a SmartBFT configuration and
+// regenerates the BlockValidation policy for the new consenter set.
+func (o *OrdererGroup) AddSmartBFTConsenter(consenter orderer.SmartBFTConsenter) (err error) {
+	defer o.observe("OrdererGroup.AddSmartBFTConsenter", time.Now(), &err)
+
+	cfg, err := o.Configuration()
+	if err != nil {
+		return err
+	}
+
+	if cfg.OrdererType != orderer.ConsensusTypeSmartBFT {
+		return fmt.Errorf("consensus type %s is not smartbft", cfg.OrdererType)
+	}
+
+	consenter.Address, err = normalizedConsenterAddress(consenter.Address)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range cfg.SmartBFT.Consenters {
+		if c.ID == consenter.ID {
+			return fmt.Errorf("consenter with id %d already exists", consenter.ID)
+		}
+	}
+
+	err = o.checkSmartBFTConsenterReachability(consenter)
+	if err != nil {
+		return err
+	}
+
+	cfg.SmartBFT.Consenters = append(cfg.SmartBFT.Consenters, consenter)
+
+	address := consenterAddress(consenter.Address)
+	o.observed.warnExpiringCert(address, "identity cert", consenter.Identity)
+	o.observed.warnExpiringCert(address, "client tls cert", consenter.ClientTLSCert)
+	o.observed.warnExpiringCert(address, "server tls cert", consenter.ServerTLSCert)
+
+	return o.setSmartBFTConsenters(cfg)
+}
+
+// RemoveSmartBFTConsenter removes the consenter with the given id from a
+// SmartBFT configuration and regenerates the BlockValidation policy for the
+// remaining consenter set.
+// A warning is logged for every policy the removal leaves unsatisfiable.
+func (o *OrdererGroup) RemoveSmartBFTConsenter(id uint64) (err error) {
+	defer o.observe("OrdererGroup.RemoveSmartBFTConsenter", time.Now(), &err)
+	defer o.observed.watchSatisfiability(o.channelGroup)()
+
+	cfg, err := o.Configuration()
+	if err != nil {
+		return err
+	}
+
+	if cfg.OrdererType != orderer.ConsensusTypeSmartBFT {
+		return fmt.Errorf("consensus type %s is not smartbft", cfg.OrdererType)
+	}
+
+	var consenters []orderer.SmartBFTConsenter
+	for _, c := range cfg.SmartBFT.Consenters {
+		if c.ID != id {
+			consenters = append(consenters, c)
+		}
+	}
+
+	if len(consenters) == len(cfg.SmartBFT.Consenters) {
+		return fmt.Errorf("consenter with id %d does not exist", id)
+	}
+
+	cfg.SmartBFT.Consenters = consenters
+
+	return o.setSmartBFTConsenters(cfg)
+}
+
+func (o *OrdererGroup) setSmartBFTConsenters(cfg Orderer) error {
+	err := o.SetSmartBFTConsensusType(cfg.SmartBFT, cfg.State)
+	if err != nil {
+		return err
+	}
+
+	return o.SetBFTBlockValidationPolicy()
+}
+
+// SetBFTBlockValidationPolicy sets the BlockValidation policy of a SmartBFT
+// orderer to require the signatures of a quorum of the current consenters, as
+// Fabric does for BFT orderers. Blocks which are not signed according to the
+// BlockValidation policy are rejected, so the policy must be regenerated
+// whenever the consenter set changes. AddSmartBFTConsenter and
+// RemoveSmartBFTConsenter do so; callers of SetSmartBFTConsensusType or
+// SetConfiguration which change the consenters must call this method.
+func (o *OrdererGroup) SetBFTBlockValidationPolicy() (err error) {
+	defer o.observe("OrdererGroup.SetBFTBlockValidationPolicy", time.Now(), &err)
+
+	cfg, err := o.Configuration()
+	if err != nil {
+		return err
+	}
+
+	if cfg.OrdererType != orderer.ConsensusTypeSmartBFT {
+		return fmt.Errorf("consensus type %s is not smartbft", cfg.OrdererType)
+	}
+
+	policy, err := bftBlockValidationPolicy(cfg.SmartBFT.Consenters)
+	if err != nil {
+		return err
+	}
+
+	modPolicy := AdminsPolicyKey
+	if existing, ok := o.ordererGroup.Policies[BlockValidationPolicyKey]; ok && existing.ModPolicy != "" {
+		modPolicy = existing.ModPolicy
+	}
+
+	if o.ordererGroup.Policies == nil {
+		o.ordererGroup.Policies = map[string]*cb.ConfigPolicy{}
+	}
+	o.ordererGroup.Policies[BlockValidationPolicyKey] = &cb.ConfigPolicy{
+		ModPolicy: modPolicy,
+		Policy:    policy,
+	}
+
+	return nil
+}
+
+// bftBlockValidationPolicy returns a signature policy which is satisfied by
+// the signatures of a BFT quorum of the consenters, each identified by its
+// serialized identity.
+func bftBlockValidationPolicy(consenters []orderer.SmartBFTConsenter) (*cb.Policy, error) {
+	if len(consenters) == 0 {
+		return nil, errors.New("consenters are required")
+	}
+
+	var identities []*mb.MSPPrincipal
+	var rules []*cb.SignaturePolicy
+	for i, consenter := range consenters {
+		if consenter.Identity == nil {
+			return nil, fmt.Errorf("identity for consenter %d is required", consenter.ID)
+		}
+
+		serializedIdentity, err := proto.Marshal(&mb.SerializedIdentity{
+			Mspid:   consenter.MSPID,
+			IdBytes: pemEncodeX509Certificate(consenter.Identity),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshaling identity of consenter %d: %v", consenter.ID, err)
+		}
+
+		identities = append(identities, &mb.MSPPrincipal{
+			PrincipalClassification: mb.MSPPrincipal_IDENTITY,
+			Principal:               serializedIdentity,
+		})
+		rules = append(rules, policydsl.SignedBy(int32(i)))
+	}
+
+	signaturePolicy, err := proto.Marshal(&cb.SignaturePolicyEnvelope{
+		Rule:       policydsl.NOutOf(int32(bftQuorum(len(consenters))), rules),
+		Identities: identities,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling signature policy: %v", err)
+	}
+
+	return &cb.Policy{
+		Type:  int32(cb.Policy_SIGNATURE),
+		Value: signaturePolicy,
+	}, nil
+}
+
+// bftQuorum returns the number of consenters of a cluster of n consenters
+// whose agreement is required to tolerate (n-1)/3 faulty consenters.
+func bftQuorum(n int) int {
+	f := (n - 1) / 3
+	return int(math.Ceil(float64(n+f+1) / 2))
+}
+
 // Capabilities returns a map of enabled orderer capabilities
 // from the updated config.
 func (o *OrdererGroup) Capabilities() ([]string, error) {
@@ -523,7 +1251,9 @@ func (o *OrdererGroup) Capabilities() ([]string, error) {
 // AddCapability adds capability to the provided channel config.
 // If the provided capability already exists in current configuration, this action
 // will be a no-op.
-func (o *OrdererGroup) AddCapability(capability string) error {
+func (o *OrdererGroup) AddCapability(capability string) (err error) {
+	defer o.observe("OrdererGroup.AddCapability", time.Now(), &err)
+
 	capabilities, err := o.Capabilities()
 	if err != nil {
 		return err
@@ -538,12 +1268,24 @@ func (o *OrdererGroup) AddCapability(capability string) error {
 }
 
 // RemoveCapability removes capability to the provided channel config.
-func (o *OrdererGroup) RemoveCapability(capability string) error {
+func (o *OrdererGroup) RemoveCapability(capability string) (err error) {
+	defer o.observe("OrdererGroup.RemoveCapability", time.Now(), &err)
+
 	capabilities, err := o.Capabilities()
 	if err != nil {
 		return err
 	}
 
+	consensusType, err := ordererConsensusType(o.ordererGroup)
+	if err != nil {
+		return err
+	}
+
+	err = validateOrdererConsensusCapabilities(consensusType, withoutString(capabilities, capability))
+	if err != nil {
+		return fmt.Errorf("removing capability %s: %v", capability, err)
+	}
+
 	err = removeCapability(o.ordererGroup, capabilities, AdminsPolicyKey, capability)
 	if err != nil {
 		return err
@@ -554,7 +1296,11 @@ func (o *OrdererGroup) RemoveCapability(capability string) error {
 
 // SetEndpoint adds an orderer's endpoint to an existing channel config transaction.
 // If the same endpoint already exists in current configuration, this will be a no-op.
-func (o *OrdererOrg) SetEndpoint(endpoint Address) error {
+// See WithEndpointTLSCerts and WithEndpointTLSProbe for checking the TLS
+// certificate the endpoint is served with.
+func (o *OrdererOrg) SetEndpoint(endpoint Address) (err error) {
+	defer o.observe("OrdererOrg.SetEndpoint", time.Now(), &err)
+
 	ordererAddrProto := &cb.OrdererAddresses{}
 
 	if ordererAddrConfigValue, ok := o.orgGroup.Values[EndpointsKey]; ok {
@@ -564,19 +1310,35 @@ func (o *OrdererOrg) SetEndpoint(endpoint Address) error {
 		}
 	}
 
-	endpointToAdd := fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port)
+	endpoint = endpoint.Normalized()
+	err = endpoint.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid endpoint for orderer org %s: %v", o.name, err)
+	}
+
+	endpointToAdd := hostPort(endpoint.Host, endpoint.Port)
 
 	existingOrdererEndpoints := ordererAddrProto.Addresses
 	for _, e := range existingOrdererEndpoints {
-		if e == endpointToAdd {
+		if normalizedEndpoint(e) == endpointToAdd {
 			return nil
 		}
 	}
 
+	err = o.checkEndpointTLS(endpoint)
+	if err != nil {
+		return err
+	}
+
+	err = o.checkEndpointReachability(endpoint)
+	if err != nil {
+		return err
+	}
+
 	existingOrdererEndpoints = append(existingOrdererEndpoints, endpointToAdd)
 
 	// Add orderer endpoints config value back to orderer org
-	err := setValue(o.orgGroup, endpointsValue(existingOrdererEndpoints), AdminsPolicyKey)
+	err = setValue(o.orgGroup, endpointsValue(existingOrdererEndpoints), AdminsPolicyKey)
 	if err != nil {
 		return fmt.Errorf("failed to add endpoint %v to orderer org %s: %v", endpoint, o.name, err)
 	}
@@ -586,7 +1348,9 @@ func (o *OrdererOrg) SetEndpoint(endpoint Address) error {
 
 // RemoveEndpoint removes an orderer's endpoint from an existing channel config transaction.
 // Removal will panic if either the orderer group or orderer org group does not exist.
-func (o *OrdererOrg) RemoveEndpoint(endpoint Address) error {
+func (o *OrdererOrg) RemoveEndpoint(endpoint Address) (err error) {
+	defer o.observe("OrdererOrg.RemoveEndpoint", time.Now(), &err)
+
 	ordererAddrProto := &cb.OrdererAddresses{}
 
 	if ordererAddrConfigValue, ok := o.orgGroup.Values[EndpointsKey]; ok {
@@ -596,17 +1360,18 @@ func (o *OrdererOrg) RemoveEndpoint(endpoint Address) error {
 		}
 	}
 
-	endpointToRemove := fmt.Sprintf("%s:%d", endpoint.Host, endpoint.Port)
+	endpoint = endpoint.Normalized()
+	endpointToRemove := hostPort(endpoint.Host, endpoint.Port)
 
 	existingEndpoints := ordererAddrProto.Addresses[:0]
 	for _, e := range ordererAddrProto.Addresses {
-		if e != endpointToRemove {
+		if normalizedEndpoint(e) != endpointToRemove {
 			existingEndpoints = append(existingEndpoints, e)
 		}
 	}
 
 	// Add orderer endpoints config value back to orderer org
-	err := setValue(o.orgGroup, endpointsValue(existingEndpoints), AdminsPolicyKey)
+	err = setValue(o.orgGroup, endpointsValue(existingEndpoints), AdminsPolicyKey)
 	if err != nil {
 		return fmt.Errorf("failed to remove endpoint %v from orderer org %s: %v", endpoint, o.name, err)
 	}
@@ -615,7 +1380,9 @@ func (o *OrdererOrg) RemoveEndpoint(endpoint Address) error {
 }
 
 // SetModPolicy sets the specified modification policy for the orderer group.
-func (o *OrdererGroup) SetModPolicy(modPolicy string) error {
+func (o *OrdererGroup) SetModPolicy(modPolicy string) (err error) {
+	defer o.observe("OrdererGroup.SetModPolicy", time.Now(), &err)
+
 	if modPolicy == "" {
 		return errors.New("non empty mod policy is required")
 	}
@@ -625,10 +1392,28 @@ func (o *OrdererGroup) SetModPolicy(modPolicy string) error {
 	return nil
 }
 
+// WithoutSubPolicyChecks returns a copy of the orderer group whose SetPolicy
+// and SetPolicies methods accept ImplicitMeta policies whose sub-policy is
+// missing in the orderer orgs.
+func (o *OrdererGroup) WithoutSubPolicyChecks() *OrdererGroup {
+	unchecked := *o
+	unchecked.skipSubPolicyChecks = true
+
+	return &unchecked
+}
+
 // SetPolicy sets the specified policy in the orderer group's config policy map.
 // If the policy already exists in current configuration, its value will be overwritten.
-func (o *OrdererGroup) SetPolicy(policyName string, policy Policy) error {
-	err := setPolicy(o.ordererGroup, policyName, policy)
+// The sub-policy of an ImplicitMeta policy must be defined by the orderer orgs:
+// by one of them for an ANY rule and by every org defining policies for an ALL
+// or MAJORITY rule.
+func (o *OrdererGroup) SetPolicy(policyName string, policy Policy) (err error) {
+	defer o.observe("OrdererGroup.SetPolicy", time.Now(), &err)
+
+	err = o.checkSubPolicies(map[string]Policy{policyName: policy})
+	if err == nil {
+		err = setPolicy(o.ordererGroup, policyName, policy)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to set policy '%s': %v", policyName, err)
 	}
@@ -638,12 +1423,18 @@ func (o *OrdererGroup) SetPolicy(policyName string, policy Policy) error {
 
 // SetPolicies sets the specified policy in the orderer group's config policy map.
 // If the policies already exist in current configuration, the values will be replaced with new policies.
-func (o *OrdererGroup) SetPolicies(policies map[string]Policy) error {
+// ImplicitMeta policies are checked as by SetPolicy.
+func (o *OrdererGroup) SetPolicies(policies map[string]Policy) (err error) {
+	defer o.observe("OrdererGroup.SetPolicies", time.Now(), &err)
+
 	if _, ok := policies[BlockValidationPolicyKey]; !ok {
 		return errors.New("BlockValidation policy must be defined")
 	}
 
-	err := setPolicies(o.ordererGroup, policies)
+	err = o.checkSubPolicies(policies)
+	if err == nil {
+		err = setPolicies(o.ordererGroup, policies)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to set policies: %v", err)
 	}
@@ -651,8 +1442,18 @@ func (o *OrdererGroup) SetPolicies(policies map[string]Policy) error {
 	return nil
 }
 
+func (o *OrdererGroup) checkSubPolicies(policies map[string]Policy) error {
+	if o.skipSubPolicyChecks {
+		return nil
+	}
+
+	return checkSubPolicies(o.ordererGroup, policies)
+}
+
 // RemovePolicy removes an existing orderer policy configuration.
-func (o *OrdererGroup) RemovePolicy(policyName string) error {
+func (o *OrdererGroup) RemovePolicy(policyName string) (err error) {
+	defer o.observe("OrdererGroup.RemovePolicy", time.Now(), &err)
+
 	if policyName == BlockValidationPolicyKey {
 		return errors.New("BlockValidation policy must be defined")
 	}
@@ -674,7 +1475,9 @@ func (o *OrdererGroup) Policies() (map[string]Policy, error) {
 
 // SetMSP updates the MSP config for the specified orderer org
 // in the updated config.
-func (o *OrdererOrg) SetMSP(updatedMSP MSP) error {
+func (o *OrdererOrg) SetMSP(updatedMSP MSP) (err error) {
+	defer o.observe("OrdererOrg.SetMSP", time.Now(), &err)
+
 	currentMSP, err := o.MSP().Configuration()
 	if err != nil {
 		return fmt.Errorf("retrieving msp: %v", err)
@@ -698,7 +1501,9 @@ func (o *OrdererOrg) SetMSP(updatedMSP MSP) error {
 }
 
 // SetModPolicy sets the specified modification policy for the orderer org group.
-func (o *OrdererOrg) SetModPolicy(modPolicy string) error {
+func (o *OrdererOrg) SetModPolicy(modPolicy string) (err error) {
+	defer o.observe("OrdererOrg.SetModPolicy", time.Now(), &err)
+
 	if modPolicy == "" {
 		return errors.New("non empty mod policy is required")
 	}
@@ -710,18 +1515,45 @@ func (o *OrdererOrg) SetModPolicy(modPolicy string) error {
 
 // SetPolicy sets the specified policy in the orderer org group's config policy map.
 // If the policy already exists in current configuration, its value will be overwritten.
-func (o *OrdererOrg) SetPolicy(policyName string, policy Policy) error {
+func (o *OrdererOrg) SetPolicy(policyName string, policy Policy) (err error) {
+	defer o.observe("OrdererOrg.SetPolicy", time.Now(), &err)
+
 	return setPolicy(o.orgGroup, policyName, policy)
 }
 
+// SetSignaturePolicy sets the signature policy of the envelope in the orderer
+// org group's config policy map, overwriting an existing policy of that name.
+// See ApplicationOrg.SetSignaturePolicy.
+func (o *OrdererOrg) SetSignaturePolicy(policyName string, envelope *cb.SignaturePolicyEnvelope, modPolicy string) (err error) {
+	defer o.observe("OrdererOrg.SetSignaturePolicy", time.Now(), &err)
+
+	return setSignaturePolicyEnvelope(o.orgGroup, policyName, envelope, modPolicy)
+}
+
 // SetPolicies sets the specified policies in the orderer org group's config policy map.
 // If the policies already exist in current configuration, the values will be replaced with new policies.
-func (o *OrdererOrg) SetPolicies(policies map[string]Policy) error {
+func (o *OrdererOrg) SetPolicies(policies map[string]Policy) (err error) {
+	defer o.observe("OrdererOrg.SetPolicies", time.Now(), &err)
+
 	return setPolicies(o.orgGroup, policies)
 }
 
+// SetDefaultPolicies sets the Readers, Writers and Admins policies of the
+// orderer org to the signature policies configtxgen's sample configuration
+// defines for the org's MSP. With NodeOUs enabled, Readers and Writers are
+// admins, orderers and clients. Otherwise they accept any member. Admins
+// always require an admin. NodeOUs must be enabled in the MSP of the org to
+// use the NodeOU roles.
+func (o *OrdererOrg) SetDefaultPolicies(nodeOUsEnabled bool) (err error) {
+	defer o.observe("OrdererOrg.SetDefaultPolicies", time.Now(), &err)
+
+	return setDefaultOrgPolicies(o.orgGroup, nodeOUsEnabled, ordererOrgPolicies)
+}
+
 // RemovePolicy removes an existing policy from an orderer organization.
-func (o *OrdererOrg) RemovePolicy(policyName string) error {
+func (o *OrdererOrg) RemovePolicy(policyName string) (err error) {
+	defer o.observe("OrdererOrg.RemovePolicy", time.Now(), &err)
+
 	policies, err := o.Policies()
 	if err != nil {
 		return err
@@ -740,6 +1572,8 @@ func (o *OrdererOrg) Policies() (map[string]Policy, error) {
 // RemoveLegacyKafkaBrokers removes the legacy kafka brokers config key and value from config.
 // In fabric 2.0, kafka was deprecated as a consensus type.
 func (o *OrdererGroup) RemoveLegacyKafkaBrokers() {
+	defer o.observe("OrdererGroup.RemoveLegacyKafkaBrokers", time.Now(), nil)
+
 	delete(o.ordererGroup.Values, orderer.KafkaBrokersKey)
 }
 
@@ -784,6 +1618,16 @@ func newOrdererGroup(orderer Orderer) (*cb.ConfigGroup, error) {
 
 // addOrdererValues adds configuration specified in Orderer to an orderer
 // *cb.ConfigGroup's Values map.
+// ordererValueKeys are the keys of the orderer values set by addOrdererValues.
+var ordererValueKeys = []string{
+	orderer.BatchSizeKey,
+	orderer.BatchTimeoutKey,
+	orderer.ChannelRestrictionsKey,
+	CapabilitiesKey,
+	orderer.KafkaBrokersKey,
+	orderer.ConsensusTypeKey,
+}
+
 func addOrdererValues(ordererGroup *cb.ConfigGroup, o Orderer) error {
 	err := setValue(ordererGroup, batchSizeValue(
 		o.BatchSize.MaxMessageCount,
@@ -824,8 +1668,18 @@ func addOrdererValues(ordererGroup *cb.ConfigGroup, o Orderer) error {
 		if consensusMetadata, err = marshalEtcdRaftMetadata(o.EtcdRaft); err != nil {
 			return fmt.Errorf("marshaling etcdraft metadata for orderer type '%s': %v", orderer.ConsensusTypeEtcdRaft, err)
 		}
+	case orderer.ConsensusTypeSmartBFT:
+		if consensusMetadata, err = marshalSmartBFTMetadata(o.SmartBFT); err != nil {
+			return fmt.Errorf("marshaling smartbft metadata for orderer type '%s': %v", orderer.ConsensusTypeSmartBFT, err)
+		}
 	default:
-		return fmt.Errorf("unknown orderer type '%s'", o.OrdererType)
+		handler, ok := consensusTypeHandler(o.OrdererType)
+		if !ok {
+			return fmt.Errorf("unknown orderer type '%s'", o.OrdererType)
+		}
+		if consensusMetadata, err = handler.MarshalMetadata(o.CustomMetadata); err != nil {
+			return fmt.Errorf("marshaling metadata for orderer type '%s': %v", o.OrdererType, err)
+		}
 	}
 
 	consensusState, ok := ob.ConsensusType_State_value[string(o.State)]
@@ -990,19 +1844,17 @@ func unmarshalEtcdRaftMetadata(mdBytes []byte) (orderer.EtcdRaft, error) {
 	consenters := []orderer.Consenter{}
 
 	for _, c := range etcdRaftMetadata.Consenters {
-		clientTLSCertBlock, _ := pem.Decode(c.ClientTlsCert)
-		if clientTLSCertBlock == nil {
+		if block, _ := pem.Decode(c.ClientTlsCert); block == nil {
 			return orderer.EtcdRaft{}, fmt.Errorf("no PEM data found in client TLS cert[% x]", c.ClientTlsCert)
 		}
-		clientTLSCert, err := x509.ParseCertificate(clientTLSCertBlock.Bytes)
+		clientTLSCert, err := parseLeafCertificate(c.ClientTlsCert)
 		if err != nil {
 			return orderer.EtcdRaft{}, fmt.Errorf("unable to parse client tls cert: %v", err)
 		}
-		serverTLSCertBlock, _ := pem.Decode(c.ServerTlsCert)
-		if serverTLSCertBlock == nil {
+		if block, _ := pem.Decode(c.ServerTlsCert); block == nil {
 			return orderer.EtcdRaft{}, fmt.Errorf("no PEM data found in server TLS cert[% x]", c.ServerTlsCert)
 		}
-		serverTLSCert, err := x509.ParseCertificate(serverTLSCertBlock.Bytes)
+		serverTLSCert, err := parseLeafCertificate(c.ServerTlsCert)
 		if err != nil {
 			return orderer.EtcdRaft{}, fmt.Errorf("unable to parse server tls cert: %v", err)
 		}
@@ -1035,6 +1887,152 @@ func unmarshalEtcdRaftMetadata(mdBytes []byte) (orderer.EtcdRaft, error) {
 	}, nil
 }
 
+// marshalSmartBFTMetadata serializes SmartBFT metadata.
+func marshalSmartBFTMetadata(md orderer.SmartBFT) ([]byte, error) {
+	var consenters []*sb.Consenter
+
+	if len(md.Consenters) == 0 {
+		return nil, errors.New("consenters are required")
+	}
+
+	for _, c := range md.Consenters {
+		host := c.Address.Host
+		port := c.Address.Port
+
+		if c.Identity == nil {
+			return nil, fmt.Errorf("identity for consenter %s:%d is required", host, port)
+		}
+
+		if c.ClientTLSCert == nil {
+			return nil, fmt.Errorf("client tls cert for consenter %s:%d is required", host, port)
+		}
+
+		if c.ServerTLSCert == nil {
+			return nil, fmt.Errorf("server tls cert for consenter %s:%d is required", host, port)
+		}
+
+		consenter := &sb.Consenter{
+			ConsenterId:   c.ID,
+			Host:          host,
+			Port:          uint32(port),
+			MspId:         c.MSPID,
+			Identity:      pemEncodeX509Certificate(c.Identity),
+			ClientTlsCert: pemEncodeX509Certificate(c.ClientTLSCert),
+			ServerTlsCert: pemEncodeX509Certificate(c.ServerTLSCert),
+		}
+
+		consenters = append(consenters, consenter)
+	}
+
+	leaderRotation := md.Options.LeaderRotation
+	if leaderRotation == "" {
+		leaderRotation = orderer.SmartBFTLeaderRotationUnspecified
+	}
+	rotation, ok := sb.Options_Rotation_value[string(leaderRotation)]
+	if !ok {
+		return nil, fmt.Errorf("unknown leader rotation '%s'", md.Options.LeaderRotation)
+	}
+
+	configMetadata := &sb.ConfigMetadata{
+		Consenters: consenters,
+		Options: &sb.Options{
+			RequestBatchMaxCount:      md.Options.RequestBatchMaxCount,
+			RequestBatchMaxBytes:      md.Options.RequestBatchMaxBytes,
+			RequestBatchMaxInterval:   md.Options.RequestBatchMaxInterval,
+			IncomingMessageBufferSize: md.Options.IncomingMessageBufferSize,
+			RequestPoolSize:           md.Options.RequestPoolSize,
+			RequestForwardTimeout:     md.Options.RequestForwardTimeout,
+			RequestComplainTimeout:    md.Options.RequestComplainTimeout,
+			RequestAutoRemoveTimeout:  md.Options.RequestAutoRemoveTimeout,
+			ViewChangeResendInterval:  md.Options.ViewChangeResendInterval,
+			ViewChangeTimeout:         md.Options.ViewChangeTimeout,
+			LeaderHeartbeatTimeout:    md.Options.LeaderHeartbeatTimeout,
+			LeaderHeartbeatCount:      md.Options.LeaderHeartbeatCount,
+			CollectTimeout:            md.Options.CollectTimeout,
+			SyncOnStart:               md.Options.SyncOnStart,
+			SpeedUpViewChange:         md.Options.SpeedUpViewChange,
+			LeaderRotation:            sb.Options_Rotation(rotation),
+			DecisionsPerLeader:        md.Options.DecisionsPerLeader,
+		},
+	}
+
+	data, err := proto.Marshal(configMetadata)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling config metadata: %v", err)
+	}
+
+	return data, nil
+}
+
+// unmarshalSmartBFTMetadata deserializes SmartBFT metadata.
+func unmarshalSmartBFTMetadata(mdBytes []byte) (orderer.SmartBFT, error) {
+	smartBFTMetadata := &sb.ConfigMetadata{}
+	err := proto.Unmarshal(mdBytes, smartBFTMetadata)
+	if err != nil {
+		return orderer.SmartBFT{}, fmt.Errorf("unmarshaling smartbft metadata: %v", err)
+	}
+
+	consenters := []orderer.SmartBFTConsenter{}
+
+	for _, c := range smartBFTMetadata.Consenters {
+		identity, err := parseLeafCertificate(c.Identity)
+		if err != nil {
+			return orderer.SmartBFT{}, fmt.Errorf("unable to parse identity of consenter %d: %v", c.ConsenterId, err)
+		}
+		clientTLSCert, err := parseLeafCertificate(c.ClientTlsCert)
+		if err != nil {
+			return orderer.SmartBFT{}, fmt.Errorf("unable to parse client tls cert of consenter %d: %v", c.ConsenterId, err)
+		}
+		serverTLSCert, err := parseLeafCertificate(c.ServerTlsCert)
+		if err != nil {
+			return orderer.SmartBFT{}, fmt.Errorf("unable to parse server tls cert of consenter %d: %v", c.ConsenterId, err)
+		}
+
+		consenter := orderer.SmartBFTConsenter{
+			ID: c.ConsenterId,
+			Address: orderer.EtcdAddress{
+				Host: c.Host,
+				Port: int(c.Port),
+			},
+			MSPID:         c.MspId,
+			Identity:      identity,
+			ClientTLSCert: clientTLSCert,
+			ServerTLSCert: serverTLSCert,
+		}
+
+		consenters = append(consenters, consenter)
+	}
+
+	if smartBFTMetadata.Options == nil {
+		return orderer.SmartBFT{}, errors.New("missing smartbft metadata options in config")
+	}
+
+	options := smartBFTMetadata.Options
+
+	return orderer.SmartBFT{
+		Consenters: consenters,
+		Options: orderer.SmartBFTOptions{
+			RequestBatchMaxCount:      options.RequestBatchMaxCount,
+			RequestBatchMaxBytes:      options.RequestBatchMaxBytes,
+			RequestBatchMaxInterval:   options.RequestBatchMaxInterval,
+			IncomingMessageBufferSize: options.IncomingMessageBufferSize,
+			RequestPoolSize:           options.RequestPoolSize,
+			RequestForwardTimeout:     options.RequestForwardTimeout,
+			RequestComplainTimeout:    options.RequestComplainTimeout,
+			RequestAutoRemoveTimeout:  options.RequestAutoRemoveTimeout,
+			ViewChangeResendInterval:  options.ViewChangeResendInterval,
+			ViewChangeTimeout:         options.ViewChangeTimeout,
+			LeaderHeartbeatTimeout:    options.LeaderHeartbeatTimeout,
+			LeaderHeartbeatCount:      options.LeaderHeartbeatCount,
+			CollectTimeout:            options.CollectTimeout,
+			SyncOnStart:               options.SyncOnStart,
+			SpeedUpViewChange:         options.SpeedUpViewChange,
+			LeaderRotation:            orderer.SmartBFTLeaderRotation(options.LeaderRotation.String()),
+			DecisionsPerLeader:        options.DecisionsPerLeader,
+		},
+	}, nil
+}
+
 // getOrdererOrg returns the organization config group for an orderer org in the
 // provided config. It returns nil if the org doesn't exist in the config.
 func getOrdererOrg(config *cb.Config, orgName string) *cb.ConfigGroup {
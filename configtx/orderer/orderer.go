@@ -8,6 +8,9 @@ package orderer
 
 import (
 	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
 )
 
 const (
@@ -27,6 +30,19 @@ const (
 	// ConsensusTypeEtcdRaft identifies the Raft-based consensus implementation.
 	ConsensusTypeEtcdRaft = "etcdraft"
 
+	// ConsensusTypeSmartBFT identifies the SmartBFT-based consensus implementation.
+	ConsensusTypeSmartBFT = "smartbft"
+
+	// SmartBFTLeaderRotationUnspecified leaves leader rotation to the default
+	// of the ordering service.
+	SmartBFTLeaderRotationUnspecified SmartBFTLeaderRotation = "UNDEFINED"
+
+	// SmartBFTLeaderRotationOff disables leader rotation.
+	SmartBFTLeaderRotationOff SmartBFTLeaderRotation = "OFF"
+
+	// SmartBFTLeaderRotationOn enables leader rotation.
+	SmartBFTLeaderRotationOn SmartBFTLeaderRotation = "ON"
+
 	// KafkaBrokersKey is the common.ConfigValue type key name for the KafkaBrokers message.
 	KafkaBrokersKey = "KafkaBrokers"
 
@@ -94,3 +110,259 @@ type EtcdAddress struct {
 	Host string
 	Port int
 }
+
+// SmartBFT is serialized and set as the value of ConsensusType.Metadata in
+// a channel configuration when the ConsensusType.Type is set to "smartbft".
+type SmartBFT struct {
+	Consenters []SmartBFTConsenter
+	Options    SmartBFTOptions
+}
+
+// SmartBFTConsenter represents a consenting node of a SmartBFT cluster.
+type SmartBFTConsenter struct {
+	// ID uniquely identifies the consenter within the cluster.
+	ID      uint64
+	Address EtcdAddress
+	// MSPID is the ID of the MSP which issued the consenter's identity.
+	MSPID string
+	// Identity is the certificate the consenter signs blocks with.
+	Identity      *x509.Certificate
+	ClientTLSCert *x509.Certificate
+	ServerTLSCert *x509.Certificate
+}
+
+// SmartBFTLeaderRotation defines whether the SmartBFT leader is rotated.
+// Options: `SmartBFTLeaderRotationUnspecified`, `SmartBFTLeaderRotationOff`
+// and `SmartBFTLeaderRotationOn`
+type SmartBFTLeaderRotation string
+
+// SmartBFTOptions to be specified for all the SmartBFT nodes.
+// These can be modified on a per-channel basis.
+type SmartBFTOptions struct {
+	RequestBatchMaxCount      uint64
+	RequestBatchMaxBytes      uint64
+	RequestBatchMaxInterval   string
+	IncomingMessageBufferSize uint64
+	RequestPoolSize           uint64
+	RequestForwardTimeout     string
+	RequestComplainTimeout    string
+	RequestAutoRemoveTimeout  string
+	ViewChangeResendInterval  string
+	ViewChangeTimeout         string
+	LeaderHeartbeatTimeout    string
+	LeaderHeartbeatCount      uint64
+	CollectTimeout            string
+	SyncOnStart               bool
+	SpeedUpViewChange         bool
+	LeaderRotation            SmartBFTLeaderRotation
+	DecisionsPerLeader        uint64
+}
+
+// Validate checks that the batch size limits are set and consistent.
+func (b BatchSize) Validate() error {
+	if b.MaxMessageCount == 0 {
+		return errors.New("batch size max message count must be greater than zero")
+	}
+
+	if b.AbsoluteMaxBytes == 0 {
+		return errors.New("batch size absolute max bytes must be greater than zero")
+	}
+
+	if b.PreferredMaxBytes == 0 {
+		return errors.New("batch size preferred max bytes must be greater than zero")
+	}
+
+	if b.PreferredMaxBytes > b.AbsoluteMaxBytes {
+		return fmt.Errorf("batch size preferred max bytes (%d) must not exceed absolute max bytes (%d)", b.PreferredMaxBytes, b.AbsoluteMaxBytes)
+	}
+
+	return nil
+}
+
+// Validate checks that at least one Kafka broker is defined.
+func (k Kafka) Validate() error {
+	if len(k.Brokers) == 0 {
+		return errors.New("kafka brokers are required")
+	}
+
+	for _, broker := range k.Brokers {
+		if broker == "" {
+			return errors.New("kafka broker address cannot be empty")
+		}
+	}
+
+	return nil
+}
+
+// Validate checks that the etcdraft consenters are complete and unique and
+// that the options are consistent.
+func (e EtcdRaft) Validate() error {
+	if len(e.Consenters) == 0 {
+		return errors.New("consenters are required")
+	}
+
+	addresses := map[EtcdAddress]struct{}{}
+	for _, c := range e.Consenters {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := addresses[c.Address]; ok {
+			return fmt.Errorf("duplicate consenter %s:%d", c.Address.Host, c.Address.Port)
+		}
+		addresses[c.Address] = struct{}{}
+	}
+
+	return e.Options.Validate()
+}
+
+// Validate checks that the consenter has an address and TLS certificates.
+func (c Consenter) Validate() error {
+	host := c.Address.Host
+	port := c.Address.Port
+
+	if host == "" {
+		return errors.New("consenter host is required")
+	}
+
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("invalid port %d for consenter %s", port, host)
+	}
+
+	if c.ClientTLSCert == nil {
+		return fmt.Errorf("client tls cert for consenter %s:%d is required", host, port)
+	}
+
+	if c.ServerTLSCert == nil {
+		return fmt.Errorf("server tls cert for consenter %s:%d is required", host, port)
+	}
+
+	return nil
+}
+
+// Validate checks that the etcdraft options are consistent. Options which are
+// not set are left to the defaults of the ordering service.
+func (o EtcdRaftOptions) Validate() error {
+	if o.TickInterval != "" {
+		tickInterval, err := time.ParseDuration(o.TickInterval)
+		if err != nil {
+			return fmt.Errorf("invalid tick interval '%s': %v", o.TickInterval, err)
+		}
+		if tickInterval <= 0 {
+			return fmt.Errorf("tick interval '%s' must be greater than zero", o.TickInterval)
+		}
+	}
+
+	if o.ElectionTick != 0 && o.HeartbeatTick != 0 && o.ElectionTick <= o.HeartbeatTick {
+		return fmt.Errorf("election tick (%d) must be greater than heartbeat tick (%d)", o.ElectionTick, o.HeartbeatTick)
+	}
+
+	return nil
+}
+
+// Validate checks that the SmartBFT consenters are complete and unique and
+// that the options are consistent.
+func (s SmartBFT) Validate() error {
+	if len(s.Consenters) == 0 {
+		return errors.New("consenters are required")
+	}
+
+	ids := map[uint64]struct{}{}
+	addresses := map[EtcdAddress]struct{}{}
+	for _, c := range s.Consenters {
+		if err := c.Validate(); err != nil {
+			return err
+		}
+
+		if _, ok := ids[c.ID]; ok {
+			return fmt.Errorf("duplicate consenter ID %d", c.ID)
+		}
+		ids[c.ID] = struct{}{}
+
+		if _, ok := addresses[c.Address]; ok {
+			return fmt.Errorf("duplicate consenter %s:%d", c.Address.Host, c.Address.Port)
+		}
+		addresses[c.Address] = struct{}{}
+	}
+
+	return s.Options.Validate()
+}
+
+// Validate checks that the consenter has an ID, an address, an identity
+// and TLS certificates.
+func (c SmartBFTConsenter) Validate() error {
+	host := c.Address.Host
+	port := c.Address.Port
+
+	if c.ID == 0 {
+		return fmt.Errorf("ID for consenter %s:%d is required", host, port)
+	}
+
+	if host == "" {
+		return fmt.Errorf("host for consenter %d is required", c.ID)
+	}
+
+	if port <= 0 || port > 65535 {
+		return fmt.Errorf("invalid port %d for consenter %s", port, host)
+	}
+
+	if c.MSPID == "" {
+		return fmt.Errorf("MSP ID for consenter %s:%d is required", host, port)
+	}
+
+	if c.Identity == nil {
+		return fmt.Errorf("identity for consenter %s:%d is required", host, port)
+	}
+
+	if c.ClientTLSCert == nil {
+		return fmt.Errorf("client tls cert for consenter %s:%d is required", host, port)
+	}
+
+	if c.ServerTLSCert == nil {
+		return fmt.Errorf("server tls cert for consenter %s:%d is required", host, port)
+	}
+
+	return nil
+}
+
+// Validate checks that the SmartBFT options are consistent. Options which are
+// not set are left to the defaults of the ordering service.
+func (o SmartBFTOptions) Validate() error {
+	durations := []struct {
+		name  string
+		value string
+	}{
+		{"request batch max interval", o.RequestBatchMaxInterval},
+		{"request forward timeout", o.RequestForwardTimeout},
+		{"request complain timeout", o.RequestComplainTimeout},
+		{"request auto remove timeout", o.RequestAutoRemoveTimeout},
+		{"view change resend interval", o.ViewChangeResendInterval},
+		{"view change timeout", o.ViewChangeTimeout},
+		{"leader heartbeat timeout", o.LeaderHeartbeatTimeout},
+		{"collect timeout", o.CollectTimeout},
+	}
+	for _, d := range durations {
+		if d.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(d.value)
+		if err != nil {
+			return fmt.Errorf("invalid %s '%s': %v", d.name, d.value, err)
+		}
+		if duration <= 0 {
+			return fmt.Errorf("%s '%s' must be greater than zero", d.name, d.value)
+		}
+	}
+
+	switch o.LeaderRotation {
+	case "", SmartBFTLeaderRotationUnspecified, SmartBFTLeaderRotationOff, SmartBFTLeaderRotationOn:
+	default:
+		return fmt.Errorf("unknown leader rotation '%s'", o.LeaderRotation)
+	}
+
+	if o.RequestBatchMaxCount != 0 && o.RequestPoolSize != 0 && o.RequestBatchMaxCount > o.RequestPoolSize {
+		return fmt.Errorf("request batch max count (%d) must not exceed request pool size (%d)", o.RequestBatchMaxCount, o.RequestPoolSize)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orderer
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// byteSizeUnits are the multipliers of the units of ParseByteSize. As in the
+// configtx.yaml of Fabric, KB, MB and GB are binary units.
+var byteSizeUnits = map[string]uint64{
+	"":    1,
+	"B":   1,
+	"KB":  1 << 10,
+	"KiB": 1 << 10,
+	"MB":  1 << 20,
+	"MiB": 1 << 20,
+	"GB":  1 << 30,
+	"GiB": 1 << 30,
+}
+
+var byteSizeRegexp = regexp.MustCompile(`^([0-9]+) ?([A-Za-z]*)$`)
+
+// ParseByteSize parses a size of the batch size such as "10 MB", "512 KiB" or
+// "98304". The units B, KB, KiB, MB, MiB, GB and GiB are accepted, where KB,
+// MB and GB are binary units like KiB, MiB and GiB, as in the configtx.yaml of
+// Fabric. A number without unit is a size in bytes. Fractions, other units
+// and sizes which do not fit in 32 bits are rejected.
+func ParseByteSize(size string) (uint32, error) {
+	match := byteSizeRegexp.FindStringSubmatch(size)
+	if match == nil {
+		return 0, fmt.Errorf("invalid size '%s', expected an integer followed by an optional unit such as MB", size)
+	}
+
+	multiplier, ok := byteSizeUnits[match[2]]
+	if !ok {
+		return 0, fmt.Errorf("invalid size '%s', unknown unit '%s', expected B, KB, KiB, MB, MiB, GB or GiB", size, match[2])
+	}
+
+	value, err := strconv.ParseUint(match[1], 10, 32)
+	if err != nil || value*multiplier > math.MaxUint32 {
+		return 0, fmt.Errorf("invalid size '%s', sizes must not exceed %d bytes", size, uint32(math.MaxUint32))
+	}
+
+	return uint32(value * multiplier), nil
+}
+
+// FormatByteSize formats the size in the largest of the binary units GiB,
+// MiB and KiB which represents it exactly, such as "10 MiB", or in bytes,
+// such as "1000 B". The result can be parsed with ParseByteSize.
+func FormatByteSize(size uint32) string {
+	for _, unit := range []string{"GiB", "MiB", "KiB"} {
+		multiplier := uint32(byteSizeUnits[unit])
+		if size != 0 && size%multiplier == 0 {
+			return fmt.Sprintf("%d %s", size/multiplier, unit)
+		}
+	}
+
+	return fmt.Sprintf("%d B", size)
+}
+
+// ParseBatchTimeout parses a batch timeout such as "2s" or "500ms". The
+// timeout must be positive.
+func ParseBatchTimeout(timeout string) (time.Duration, error) {
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	if duration <= 0 {
+		return 0, fmt.Errorf("batch timeout must be positive, got %s", duration)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package orderer
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseByteSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		size         string
+		expectedSize uint32
+		expectedErr  string
+	}{
+		{size: "98304", expectedSize: 98304},
+		{size: "100 B", expectedSize: 100},
+		{size: "512 KiB", expectedSize: 512 * 1024},
+		{size: "512KB", expectedSize: 512 * 1024},
+		{size: "10 MB", expectedSize: 10 * 1024 * 1024},
+		{size: "99 MiB", expectedSize: 99 * 1024 * 1024},
+		{size: "3 GiB", expectedSize: 3 * 1024 * 1024 * 1024},
+		{size: "", expectedErr: "invalid size '', expected an integer followed by an optional unit such as MB"},
+		{size: "-1 MB", expectedErr: "invalid size '-1 MB', expected an integer followed by an optional unit such as MB"},
+		{size: "1.5 MB", expectedErr: "invalid size '1.5 MB', expected an integer followed by an optional unit such as MB"},
+		{size: "10  MB", expectedErr: "invalid size '10  MB', expected an integer followed by an optional unit such as MB"},
+		{size: "10 mb", expectedErr: "invalid size '10 mb', unknown unit 'mb', expected B, KB, KiB, MB, MiB, GB or GiB"},
+		{size: "1 TB", expectedErr: "invalid size '1 TB', unknown unit 'TB', expected B, KB, KiB, MB, MiB, GB or GiB"},
+		{size: "4 GiB", expectedErr: "invalid size '4 GiB', sizes must not exceed 4294967295 bytes"},
+		{size: "4294967296", expectedErr: "invalid size '4294967296', sizes must not exceed 4294967295 bytes"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.size, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			size, err := ParseByteSize(tt.size)
+			if tt.expectedErr != "" {
+				gt.Expect(err).To(MatchError(tt.expectedErr))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(size).To(Equal(tt.expectedSize))
+
+			reparsed, err := ParseByteSize(FormatByteSize(size))
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(reparsed).To(Equal(size))
+		})
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	gt.Expect(FormatByteSize(0)).To(Equal("0 B"))
+	gt.Expect(FormatByteSize(1000)).To(Equal("1000 B"))
+	gt.Expect(FormatByteSize(1536 * 1024)).To(Equal("1536 KiB"))
+	gt.Expect(FormatByteSize(10 * 1024 * 1024)).To(Equal("10 MiB"))
+	gt.Expect(FormatByteSize(2 * 1024 * 1024 * 1024)).To(Equal("2 GiB"))
+}
+
+func TestParseBatchTimeout(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	timeout, err := ParseBatchTimeout("500ms")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(timeout).To(Equal(500 * time.Millisecond))
+
+	_, err = ParseBatchTimeout("2")
+	gt.Expect(err).To(MatchError(`time: missing unit in duration "2"`))
+	_, err = ParseBatchTimeout("-1s")
+	gt.Expect(err).To(MatchError("batch timeout must be positive, got -1s"))
+}
@@ -11,6 +11,7 @@ import (
 	"crypto/ecdsa"
 	"crypto/x509"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"math/big"
 	"testing"
@@ -911,8 +912,10 @@ func TestSetOrdererConfiguration(t *testing.T) {
 
 	updatedOrdererConf := baseOrdererConf
 
-	// Modify MaxMessageCount and ConesnsusType to etcdraft
+	// Modify MaxMessageCount and ConesnsusType to etcdraft, which requires
+	// the V1_4_2 orderer capability
 	updatedOrdererConf.BatchSize.MaxMessageCount = 10000
+	updatedOrdererConf.Capabilities = []string{"V1_4_2"}
 	updatedOrdererConf.OrdererType = orderer.ConsensusTypeEtcdRaft
 	updatedOrdererConf.EtcdRaft = orderer.EtcdRaft{
 		Consenters: []orderer.Consenter{
@@ -1128,7 +1131,7 @@ func TestSetOrdererConfiguration(t *testing.T) {
 						"mod_policy": "Admins",
 						"value": {
 							"capabilities": {
-							"V1_3": {}
+							"V1_4_2": {}
 							}
 						},
 						"version": "0"
@@ -1197,6 +1200,154 @@ func TestSetOrdererConfiguration(t *testing.T) {
 	gt.Expect(buf.String()).To(MatchJSON(expectedConfigJSON))
 }
 
+func TestOrdererUpdate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseSmartBFTOrderer(t)
+	c := ordererConfigTx(t, baseOrdererConf)
+
+	err := c.Orderer().Update(func(cfg *Orderer) error {
+		cfg.BatchSize.MaxMessageCount = 1000
+		cfg.BatchSize.AbsoluteMaxBytes = 10 * 1024 * 1024
+		cfg.BatchSize.PreferredMaxBytes = 4 * 1024 * 1024
+		cfg.BatchTimeout = 5 * time.Second
+		cfg.SmartBFT.Options.RequestBatchMaxCount = 50
+		cfg.SmartBFT.Options.DecisionsPerLeader = 10
+		return nil
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.BatchSize.MaxMessageCount).To(Equal(uint32(1000)))
+	gt.Expect(ordererConfig.BatchSize.PreferredMaxBytes).To(Equal(uint32(4 * 1024 * 1024)))
+	gt.Expect(ordererConfig.BatchTimeout).To(Equal(5 * time.Second))
+	gt.Expect(ordererConfig.SmartBFT.Options.RequestBatchMaxCount).To(Equal(uint64(50)))
+	gt.Expect(ordererConfig.SmartBFT.Options.DecisionsPerLeader).To(Equal(uint64(10)))
+
+	configUpdate, err := computeConfigUpdate(c.original, c.updated)
+	gt.Expect(err).NotTo(HaveOccurred())
+	writeSet := configUpdate.WriteSet.Groups[OrdererGroupKey]
+	gt.Expect(writeSet.Values).To(HaveLen(3))
+	for _, key := range []string{orderer.BatchSizeKey, orderer.BatchTimeoutKey, orderer.ConsensusTypeKey} {
+		gt.Expect(writeSet.Values).To(HaveKey(key))
+		gt.Expect(writeSet.Values[key].Version).To(Equal(uint64(1)), key)
+	}
+
+	// Values whose content does not change are left untouched.
+	c.updated.ChannelGroup.Groups[OrdererGroupKey].Values[orderer.ChannelRestrictionsKey].ModPolicy = "Custom"
+	err = c.Orderer().Update(func(cfg *Orderer) error {
+		cfg.BatchTimeout = 2 * time.Second
+		return nil
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.updated.ChannelGroup.Groups[OrdererGroupKey].Values[orderer.ChannelRestrictionsKey].ModPolicy).To(Equal("Custom"))
+}
+
+func TestOrdererUpdateConsensusType(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseKafkaOrderer(t)
+	c := ordererConfigTx(t, baseOrdererConf)
+	c.updated.ChannelGroup.Groups[OrdererGroupKey].Values["Custom"] = &cb.ConfigValue{Value: []byte("custom"), ModPolicy: AdminsPolicyKey}
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+
+	err := c.Orderer().Update(func(cfg *Orderer) error {
+		cfg.OrdererType = orderer.ConsensusTypeEtcdRaft
+		cfg.EtcdRaft = etcdRaftOrderer.EtcdRaft
+		cfg.Capabilities = []string{"V2_0"}
+		return nil
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.OrdererType).To(Equal(orderer.ConsensusTypeEtcdRaft))
+	gt.Expect(ordererConfig.Kafka.Brokers).To(BeEmpty())
+	gt.Expect(c.updated.ChannelGroup.Groups[OrdererGroupKey].Values).NotTo(HaveKey(orderer.KafkaBrokersKey))
+	gt.Expect(c.updated.ChannelGroup.Groups[OrdererGroupKey].Values).To(HaveKey("Custom"))
+}
+
+func TestOrdererUpdateFailures(t *testing.T) {
+	t.Parallel()
+
+	baseOrdererConf, _ := baseSmartBFTOrderer(t)
+
+	tests := []struct {
+		testName    string
+		update      func(cfg *Orderer) error
+		expectedErr string
+	}{
+		{
+			testName: "When the update fails",
+			update: func(cfg *Orderer) error {
+				cfg.BatchTimeout = time.Minute
+				return errors.New("update failed")
+			},
+			expectedErr: "update failed",
+		},
+		{
+			testName: "When the consensus state is unknown",
+			update: func(cfg *Orderer) error {
+				cfg.BatchTimeout = time.Minute
+				cfg.State = "invalid"
+				return nil
+			},
+			expectedErr: "unknown consensus state 'invalid'",
+		},
+		{
+			testName: "When the batch size is invalid",
+			update: func(cfg *Orderer) error {
+				cfg.BatchTimeout = time.Minute
+				cfg.BatchSize.MaxMessageCount = 0
+				return nil
+			},
+			expectedErr: "batch size max message count must be greater than zero",
+		},
+		{
+			testName: "When the batch timeout is negative",
+			update: func(cfg *Orderer) error {
+				cfg.BatchTimeout = -time.Second
+				return nil
+			},
+			expectedErr: "batch timeout -1s cannot be negative",
+		},
+		{
+			testName: "When the SmartBFT options are invalid",
+			update: func(cfg *Orderer) error {
+				cfg.BatchTimeout = time.Minute
+				cfg.SmartBFT.Options.ViewChangeTimeout = "bogus"
+				return nil
+			},
+			expectedErr: `invalid smartbft configuration: invalid view change timeout 'bogus': time: invalid duration "bogus"`,
+		},
+		{
+			testName: "When the SmartBFT consenters are invalid",
+			update: func(cfg *Orderer) error {
+				cfg.SmartBFT.Consenters[0].MSPID = ""
+				return nil
+			},
+			expectedErr: "invalid smartbft configuration: MSP ID for consenter node-1.example.com:7050 is required",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := ordererConfigTx(t, baseOrdererConf)
+
+			err := c.Orderer().Update(tt.update)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+			gt.Expect(proto.Equal(c.updated, c.original)).To(BeTrue())
+		})
+	}
+}
+
 func TestOrdererConfiguration(t *testing.T) {
 	t.Parallel()
 
@@ -1282,15 +1433,6 @@ func TestOrdererConfigurationFailure(t *testing.T) {
 		configMod   func(*cb.Config, *GomegaWithT)
 		expectedErr string
 	}{
-		{
-			testName:    "When the config contains an unknown consensus type",
-			ordererType: orderer.ConsensusTypeSolo,
-			configMod: func(config *cb.Config, gt *GomegaWithT) {
-				err := setValue(config.ChannelGroup.Groups[OrdererGroupKey], consensusTypeValue("badtype", nil, 0), AdminsPolicyKey)
-				gt.Expect(err).NotTo(HaveOccurred())
-			},
-			expectedErr: "config contains unknown consensus type 'badtype'",
-		},
 		{
 			testName:    "Missing Kafka brokers for kafka orderer",
 			ordererType: orderer.ConsensusTypeKafka,
@@ -2379,7 +2521,7 @@ func TestAddConsenterFailures(t *testing.T) {
 		expectedErr  string
 	}{
 		{
-			testName: "when retrieving orderer configuration fails",
+			testName: "when consensus type is unknown",
 			orderer: func(o Orderer) Orderer {
 				return o
 			},
@@ -2389,7 +2531,7 @@ func TestAddConsenterFailures(t *testing.T) {
 			consenter: func(c orderer.Consenter) orderer.Consenter {
 				return c
 			},
-			expectedErr: "config contains unknown consensus type 'foobar'",
+			expectedErr: "consensus type foobar is not etcdraft",
 		},
 		{
 			testName: "when consensus type is not etcdraft",
@@ -2766,7 +2908,7 @@ func TestRemoveConsenterFailures(t *testing.T) {
 		expectedErr  string
 	}{
 		{
-			testName: "when retrieving orderer configuration fails",
+			testName: "when consensus type is unknown",
 			orderer: func(o Orderer) Orderer {
 				return o
 			},
@@ -2776,7 +2918,7 @@ func TestRemoveConsenterFailures(t *testing.T) {
 			consenter: func(c orderer.Consenter) orderer.Consenter {
 				return c
 			},
-			expectedErr: "config contains unknown consensus type 'foobar'",
+			expectedErr: "consensus type foobar is not etcdraft",
 		},
 		{
 			testName: "when consensus type is not etcdraft",
@@ -2849,6 +2991,39 @@ func TestRemoveConsenterFailures(t *testing.T) {
 	}
 }
 
+func TestSmartBFTConsentersFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeEtcdRaft)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	err = c.Orderer().SetBFTBlockValidationPolicy()
+	gt.Expect(err).To(MatchError("consensus type etcdraft is not smartbft"))
+
+	err = c.Orderer().AddSmartBFTConsenter(orderer.SmartBFTConsenter{})
+	gt.Expect(err).To(MatchError("consensus type etcdraft is not smartbft"))
+
+	err = c.Orderer().RemoveSmartBFTConsenter(1)
+	gt.Expect(err).To(MatchError("consensus type etcdraft is not smartbft"))
+
+	_, err = bftBlockValidationPolicy(nil)
+	gt.Expect(err).To(MatchError("consenters are required"))
+
+	_, err = bftBlockValidationPolicy([]orderer.SmartBFTConsenter{{ID: 7}})
+	gt.Expect(err).To(MatchError("identity for consenter 7 is required"))
+}
+
+func TestBFTQuorum(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	for n, quorum := range map[int]int{1: 1, 2: 2, 3: 2, 4: 3, 5: 4, 6: 4, 7: 5, 10: 7} {
+		gt.Expect(bftQuorum(n)).To(Equal(quorum), "n = %d", n)
+	}
+}
+
 func TestAddOrdererCapabilityFailures(t *testing.T) {
 	t.Parallel()
 
@@ -3268,6 +3443,128 @@ func TestRemoveOrdererOrg(t *testing.T) {
 	gt.Expect(c.Orderer().Organization("OrdererOrg")).To(BeNil())
 }
 
+// twoOrgOrderer returns an orderer of the given type with the orgs OrdererOrg
+// and OrdererOrg2, where the last consenter belongs to OrdererOrg2.
+func twoOrgOrderer(t *testing.T, ordererType string) Orderer {
+	ordererConf, _ := baseOrdererOfType(t, ordererType)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	caCert2, caPrivKey2 := generateCACertAndPrivateKey(t, "orderer-org2")
+	cert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", caCert, caPrivKey)
+	cert2, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org2", caCert2, caPrivKey2)
+
+	org2 := ordererConf.Organizations[0]
+	org2.Name = "OrdererOrg2"
+	org2.OrdererEndpoints = []string{"orderer2.example.com:7050"}
+	org2.MSP.Name = "MSPID2"
+	org2.MSP.TLSRootCerts = []*x509.Certificate{caCert2}
+	ordererConf.Organizations[0].MSP.TLSRootCerts = []*x509.Certificate{caCert}
+	ordererConf.Organizations = append(ordererConf.Organizations, org2)
+
+	switch ordererType {
+	case orderer.ConsensusTypeEtcdRaft:
+		consenters := ordererConf.EtcdRaft.Consenters
+		for i := range consenters {
+			consenters[i].ServerTLSCert = cert
+			consenters[i].ClientTLSCert = cert
+		}
+		consenters[len(consenters)-1].ServerTLSCert = cert2
+		consenters[len(consenters)-1].ClientTLSCert = cert2
+	case orderer.ConsensusTypeSmartBFT:
+		consenters := ordererConf.SmartBFT.Consenters
+		consenters[len(consenters)-1].MSPID = "MSPID2"
+	}
+
+	return ordererConf
+}
+
+func TestRemoveOrdererOrgAndConsenters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		ordererType        string
+		expectedConsenters []string
+	}{
+		{
+			ordererType:        orderer.ConsensusTypeEtcdRaft,
+			expectedConsenters: []string{"node-1.example.com", "node-2.example.com"},
+		},
+		{
+			ordererType:        orderer.ConsensusTypeSmartBFT,
+			expectedConsenters: []string{"node-1.example.com", "node-2.example.com", "node-3.example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.ordererType, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			ordererGroup, err := newOrdererGroup(twoOrgOrderer(t, tt.ordererType))
+			gt.Expect(err).NotTo(HaveOccurred())
+			channelGroup := newConfigGroup()
+			channelGroup.Groups[OrdererGroupKey] = ordererGroup
+			lastConsenter := fmt.Sprintf("node-%d.example.com:7050", len(tt.expectedConsenters)+1)
+			channelGroup.Values[OrdererAddressesKey] = &cb.ConfigValue{
+				Value: marshalOrPanic(&cb.OrdererAddresses{
+					Addresses: []string{"node-1.example.com:7050", lastConsenter, "orderer2.example.com:7050"},
+				}),
+				ModPolicy: AdminsPolicyKey,
+			}
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			err = c.Orderer().RemoveOrganizationAndConsenters("OrdererOrg2")
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(c.Orderer().Organization("OrdererOrg2")).To(BeNil())
+
+			cfg, err := c.Orderer().Configuration()
+			gt.Expect(err).NotTo(HaveOccurred())
+			var hosts []string
+			for _, consenter := range cfg.EtcdRaft.Consenters {
+				hosts = append(hosts, consenter.Address.Host)
+			}
+			for _, consenter := range cfg.SmartBFT.Consenters {
+				hosts = append(hosts, consenter.Address.Host)
+			}
+			gt.Expect(hosts).To(Equal(tt.expectedConsenters))
+
+			legacyAddresses := &cb.OrdererAddresses{}
+			err = unmarshalConfigValueAtKey(c.updated.ChannelGroup, OrdererAddressesKey, legacyAddresses)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(legacyAddresses.Addresses).To(Equal([]string{"node-1.example.com:7050"}))
+			gt.Expect(c.updated.ChannelGroup.Values[OrdererAddressesKey].ModPolicy).To(Equal(AdminsPolicyKey))
+
+			err = c.Orderer().RemoveOrganizationAndConsenters("OrdererOrg")
+			gt.Expect(err).To(MatchError("removing orderer org OrdererOrg would remove every consenter"))
+			gt.Expect(c.Orderer().Organization("OrdererOrg")).NotTo(BeNil())
+		})
+	}
+}
+
+func TestRemoveOrdererOrgAndConsentersLegacyAddresses(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	ordererGroup, err := newOrdererGroup(twoOrgOrderer(t, orderer.ConsensusTypeEtcdRaft))
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup := newConfigGroup()
+	channelGroup.Groups[OrdererGroupKey] = ordererGroup
+	channelGroup.Values[OrdererAddressesKey] = &cb.ConfigValue{
+		Value: marshalOrPanic(&cb.OrdererAddresses{
+			Addresses: []string{"orderer2.example.com:7050"},
+		}),
+	}
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	err = c.Apply([]Change{RemoveOrdererOrg{Name: "OrdererOrg2", RemoveConsenters: true}})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.updated.ChannelGroup.Values).NotTo(HaveKey(OrdererAddressesKey))
+
+	err = c.Orderer().RemoveOrganizationAndConsenters("OrdererOrg2")
+	gt.Expect(err).To(MatchError("orderer org OrdererOrg2 does not exist"))
+}
+
 func TestSetOrdererModPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -3893,6 +4190,43 @@ func TestSetOrdererOrgPoliciesFailures(t *testing.T) {
 	gt.Expect(err).To(MatchError("unknown policy type: "))
 }
 
+func TestSetOrdererOrgDefaultPolicies(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseSoloOrderer(t)
+	c := ordererConfigTx(t, baseOrdererConf)
+	ordererOrg := c.Orderer().Organization("OrdererOrg")
+
+	err := ordererOrg.SetDefaultPolicies(true)
+	gt.Expect(err).To(MatchError("NodeOUs are not enabled in MSP MSPID"))
+
+	err = ordererOrg.SetDefaultPolicies(false)
+	gt.Expect(err).NotTo(HaveOccurred())
+	policies, err := ordererOrg.Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(Equal(map[string]Policy{
+		ReadersPolicyKey: {Type: SignaturePolicyType, Rule: "AND('MSPID.member')", ModPolicy: AdminsPolicyKey},
+		WritersPolicyKey: {Type: SignaturePolicyType, Rule: "AND('MSPID.member')", ModPolicy: AdminsPolicyKey},
+		AdminsPolicyKey:  {Type: SignaturePolicyType, Rule: "AND('MSPID.admin')", ModPolicy: AdminsPolicyKey},
+		// orderer orgs have no default Endorsement policy
+		EndorsementPolicyKey: {Type: ImplicitMetaPolicyType, Rule: "MAJORITY Endorsement", ModPolicy: AdminsPolicyKey},
+	}))
+
+	err = ordererOrg.MSP().SetEnableNodeOUs(true)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ordererOrg.SetDefaultPolicies(true)
+	gt.Expect(err).NotTo(HaveOccurred())
+	policies, err = ordererOrg.Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(Equal(map[string]Policy{
+		ReadersPolicyKey:     {Type: SignaturePolicyType, Rule: "OR('MSPID.admin', 'MSPID.orderer', 'MSPID.client')", ModPolicy: AdminsPolicyKey},
+		WritersPolicyKey:     {Type: SignaturePolicyType, Rule: "OR('MSPID.admin', 'MSPID.orderer', 'MSPID.client')", ModPolicy: AdminsPolicyKey},
+		AdminsPolicyKey:      {Type: SignaturePolicyType, Rule: "AND('MSPID.admin')", ModPolicy: AdminsPolicyKey},
+		EndorsementPolicyKey: {Type: ImplicitMetaPolicyType, Rule: "MAJORITY Endorsement", ModPolicy: AdminsPolicyKey},
+	}))
+}
+
 func TestRemoveOrdererOrgPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -4711,6 +5045,54 @@ func TestSetPreferredMaxBytesFailures(t *testing.T) {
 	gt.Expect(err).To(MatchError("unexpected EOF"))
 }
 
+func TestBatchSizeStrings(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseSoloOrderer(t)
+	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	}
+
+	c := New(config)
+
+	err = c.Orderer().BatchSize().SetAbsoluteMaxBytesString("10 MB")
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().BatchSize().SetPreferredMaxBytesString("1536KiB")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererConf, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConf.BatchSize.AbsoluteMaxBytes).To(Equal(uint32(10 * 1024 * 1024)))
+	gt.Expect(ordererConf.BatchSize.PreferredMaxBytes).To(Equal(uint32(1536 * 1024)))
+
+	absoluteMaxBytes, err := c.Orderer().BatchSize().AbsoluteMaxBytesString()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(absoluteMaxBytes).To(Equal("10 MiB"))
+	preferredMaxBytes, err := c.Orderer().BatchSize().PreferredMaxBytesString()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(preferredMaxBytes).To(Equal("1536 KiB"))
+
+	err = c.Orderer().BatchSize().SetAbsoluteMaxBytesString("1.5 MB")
+	gt.Expect(err).To(MatchError("invalid size '1.5 MB', expected an integer followed by an optional unit such as MB"))
+	err = c.Orderer().BatchSize().SetPreferredMaxBytesString("4 GB")
+	gt.Expect(err).To(MatchError("invalid size '4 GB', sizes must not exceed 4294967295 bytes"))
+
+	c.updated.ChannelGroup.Groups[OrdererGroupKey].Values[orderer.BatchSizeKey] = &cb.ConfigValue{Value: []byte("{")}
+	_, err = c.Orderer().BatchSize().AbsoluteMaxBytesString()
+	gt.Expect(err).To(MatchError("unexpected EOF"))
+	_, err = c.Orderer().BatchSize().PreferredMaxBytesString()
+	gt.Expect(err).To(MatchError("unexpected EOF"))
+}
+
 func TestSetBatchTimeout(t *testing.T) {
 	t.Parallel()
 
@@ -4988,6 +5370,39 @@ func TestSetBatchTimeout(t *testing.T) {
 	gt.Expect(buf.String()).To(Equal(expectedConfigGroupJSON))
 }
 
+func TestSetBatchTimeoutString(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseSoloOrderer(t)
+	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	}
+
+	c := New(config)
+
+	err = c.Orderer().SetBatchTimeoutString("1500ms")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	batchTimeout := &ob.BatchTimeout{}
+	err = unmarshalConfigValueAtKey(c.updated.ChannelGroup.Groups[OrdererGroupKey], orderer.BatchTimeoutKey, batchTimeout)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(batchTimeout.Timeout).To(Equal("1.5s"))
+
+	err = c.Orderer().SetBatchTimeoutString("2")
+	gt.Expect(err).To(MatchError(`invalid batch timeout '2': time: missing unit in duration "2"`))
+	err = c.Orderer().SetBatchTimeoutString("0s")
+	gt.Expect(err).To(MatchError("invalid batch timeout '0s': batch timeout must be positive, got 0s"))
+}
+
 func TestSetMaxChannels(t *testing.T) {
 	t.Parallel()
 
@@ -5915,6 +6330,104 @@ func TestSetConsensusStateFailures(t *testing.T) {
 	}
 }
 
+func TestConsensusMetadata(t *testing.T) {
+	t.Parallel()
+
+	gt := NewGomegaWithT(t)
+
+	baseOrdererConf, _ := baseSoloOrderer(t)
+	ordererGroup, err := newOrdererGroup(baseOrdererConf)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(ordererGroup, consensusTypeValue("custom", []byte("custom metadata"), 0), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	config := &cb.Config{
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				OrdererGroupKey: ordererGroup,
+			},
+		},
+	}
+
+	c := New(config)
+
+	ordererConf, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConf.OrdererType).To(Equal("custom"))
+
+	metadata, err := c.Orderer().ConsensusMetadata()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(metadata).To(Equal([]byte("custom metadata")))
+
+	err = c.Orderer().SetConsensusMetadata([]byte("updated metadata"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().AddCapability("V2_0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().SetBatchTimeout(time.Second)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	metadata, err = c.Orderer().ConsensusMetadata()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(metadata).To(Equal([]byte("updated metadata")))
+
+	consensusType := &ob.ConsensusType{}
+	err = unmarshalConfigValueAtKey(c.updated.ChannelGroup.Groups[OrdererGroupKey], orderer.ConsensusTypeKey, consensusType)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(consensusType.Type).To(Equal("custom"))
+	gt.Expect(consensusType.State).To(Equal(ob.ConsensusType_STATE_NORMAL))
+}
+
+func TestSetConsensusMetadataFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName     string
+		ordererGroup func(og *cb.ConfigGroup)
+		expectedErr  string
+	}{
+		{
+			testName: "when the consensus type is missing",
+			ordererGroup: func(og *cb.ConfigGroup) {
+				delete(og.Values, orderer.ConsensusTypeKey)
+			},
+			expectedErr: "config does not contain value for ConsensusType",
+		},
+		{
+			testName: "when the metadata is not etcdraft metadata",
+			ordererGroup: func(og *cb.ConfigGroup) {
+				_ = setValue(og, consensusTypeValue(orderer.ConsensusTypeEtcdRaft, nil, 0), AdminsPolicyKey)
+			},
+			expectedErr: "unmarshaling etcd raft metadata: missing etcdraft metadata options in config",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+
+			gt := NewGomegaWithT(t)
+
+			baseOrdererConf, _ := baseSoloOrderer(t)
+			ordererGroup, err := newOrdererGroup(baseOrdererConf)
+			gt.Expect(err).NotTo(HaveOccurred())
+			tt.ordererGroup(ordererGroup)
+
+			config := &cb.Config{
+				ChannelGroup: &cb.ConfigGroup{
+					Groups: map[string]*cb.ConfigGroup{
+						OrdererGroupKey: ordererGroup,
+					},
+				},
+			}
+
+			c := New(config)
+			err = c.Orderer().SetConsensusMetadata(nil)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
+
 func TestSetEtcdRaftOptions(t *testing.T) {
 	t.Parallel()
 
@@ -6210,6 +6723,8 @@ func baseOrdererOfType(t *testing.T, ordererType string) (Orderer, []*ecdsa.Priv
 		return baseKafkaOrderer(t)
 	case orderer.ConsensusTypeEtcdRaft:
 		return baseEtcdRaftOrderer(t)
+	case orderer.ConsensusTypeSmartBFT:
+		return baseSmartBFTOrderer(t)
 	default:
 		return baseSoloOrderer(t)
 	}
@@ -6290,6 +6805,40 @@ func baseEtcdRaftOrderer(t *testing.T) (Orderer, []*ecdsa.PrivateKey) {
 	return soloOrderer, privKeys
 }
 
+func baseSmartBFTOrderer(t *testing.T) (Orderer, []*ecdsa.PrivateKey) {
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	cert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", caCert, caPrivKey)
+
+	soloOrderer, privKeys := baseSoloOrderer(t)
+	soloOrderer.OrdererType = orderer.ConsensusTypeSmartBFT
+
+	var consenters []orderer.SmartBFTConsenter
+	for i := 1; i <= 4; i++ {
+		consenters = append(consenters, orderer.SmartBFTConsenter{
+			ID: uint64(i),
+			Address: orderer.EtcdAddress{
+				Host: fmt.Sprintf("node-%d.example.com", i),
+				Port: 7050,
+			},
+			MSPID:         "MSPID",
+			Identity:      cert,
+			ClientTLSCert: cert,
+			ServerTLSCert: cert,
+		})
+	}
+	soloOrderer.SmartBFT = orderer.SmartBFT{
+		Consenters: consenters,
+		Options: orderer.SmartBFTOptions{
+			RequestBatchMaxCount:    100,
+			RequestBatchMaxInterval: "50ms",
+			RequestPoolSize:         400,
+			LeaderRotation:          orderer.SmartBFTLeaderRotationOff,
+		},
+	}
+
+	return soloOrderer, privKeys
+}
+
 // baseOrdererChannelGroup creates a channel config group
 // that only contains an Orderer group.
 func baseOrdererChannelGroup(t *testing.T, ordererType string) (*cb.ConfigGroup, []*ecdsa.PrivateKey, error) {
@@ -6314,3 +6863,191 @@ func marshalOrPanic(pb proto.Message) []byte {
 
 	return data
 }
+
+func TestOrdererValidate(t *testing.T) {
+	t.Parallel()
+
+	for _, ordererType := range []string{orderer.ConsensusTypeSolo, orderer.ConsensusTypeKafka, orderer.ConsensusTypeEtcdRaft, orderer.ConsensusTypeSmartBFT} {
+		ordererType := ordererType
+		t.Run(ordererType, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			o, _ := baseOrdererOfType(t, ordererType)
+			gt.Expect(o.Validate()).To(Succeed())
+		})
+	}
+}
+
+func TestOrdererValidateFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName   string
+		ordererMod func(*Orderer)
+		err        string
+	}{
+		{
+			testName: "When orderer type is unknown",
+			ordererMod: func(o *Orderer) {
+				o.OrdererType = "ConsensusTypeGreen"
+			},
+			err: "unknown orderer type 'ConsensusTypeGreen'",
+		},
+		{
+			testName: "When consensus state is unknown",
+			ordererMod: func(o *Orderer) {
+				o.State = "STATE_CONFUSED"
+			},
+			err: "unknown consensus state 'STATE_CONFUSED'",
+		},
+		{
+			testName: "When batch timeout is negative",
+			ordererMod: func(o *Orderer) {
+				o.BatchTimeout = -time.Second
+			},
+			err: "batch timeout -1s cannot be negative",
+		},
+		{
+			testName: "When batch size max message count is zero",
+			ordererMod: func(o *Orderer) {
+				o.BatchSize.MaxMessageCount = 0
+			},
+			err: "batch size max message count must be greater than zero",
+		},
+		{
+			testName: "When batch size preferred max bytes exceeds absolute max bytes",
+			ordererMod: func(o *Orderer) {
+				o.BatchSize.PreferredMaxBytes = 200
+			},
+			err: "batch size preferred max bytes (200) must not exceed absolute max bytes (100)",
+		},
+		{
+			testName: "When the BlockValidation policy is missing",
+			ordererMod: func(o *Orderer) {
+				delete(o.Policies, BlockValidationPolicyKey)
+			},
+			err: "no BlockValidation policy defined",
+		},
+		{
+			testName: "When kafka brokers are missing",
+			ordererMod: func(o *Orderer) {
+				o.OrdererType = orderer.ConsensusTypeKafka
+			},
+			err: "kafka brokers are required",
+		},
+		{
+			testName: "When etcdraft consenters are missing",
+			ordererMod: func(o *Orderer) {
+				o.OrdererType = orderer.ConsensusTypeEtcdRaft
+			},
+			err: "invalid etcdraft configuration: consenters are required",
+		},
+		{
+			testName: "When an etcdraft consenter has an invalid port",
+			ordererMod: func(o *Orderer) {
+				o.OrdererType = orderer.ConsensusTypeEtcdRaft
+				o.EtcdRaft.Consenters = []orderer.Consenter{{Address: orderer.EtcdAddress{Host: "host1", Port: 0}}}
+			},
+			err: "invalid etcdraft configuration: invalid port 0 for consenter host1",
+		},
+		{
+			testName: "When an etcdraft consenter is missing a client tls cert",
+			ordererMod: func(o *Orderer) {
+				o.OrdererType = orderer.ConsensusTypeEtcdRaft
+				o.EtcdRaft.Consenters = []orderer.Consenter{{Address: orderer.EtcdAddress{Host: "host1", Port: 123}}}
+			},
+			err: "invalid etcdraft configuration: client tls cert for consenter host1:123 is required",
+		},
+		{
+			testName: "When etcdraft election tick does not exceed heartbeat tick",
+			ordererMod: func(o *Orderer) {
+				cert := o.Organizations[0].MSP.RootCerts[0]
+				o.OrdererType = orderer.ConsensusTypeEtcdRaft
+				o.EtcdRaft.Consenters = []orderer.Consenter{{
+					Address:       orderer.EtcdAddress{Host: "host1", Port: 123},
+					ClientTLSCert: cert,
+					ServerTLSCert: cert,
+				}}
+				o.EtcdRaft.Options = orderer.EtcdRaftOptions{ElectionTick: 1, HeartbeatTick: 1}
+			},
+			err: "invalid etcdraft configuration: election tick (1) must be greater than heartbeat tick (1)",
+		},
+		{
+			testName: "When smartbft consenters are missing",
+			ordererMod: func(o *Orderer) {
+				o.OrdererType = orderer.ConsensusTypeSmartBFT
+			},
+			err: "invalid smartbft configuration: consenters are required",
+		},
+		{
+			testName: "When a smartbft consenter has no identity",
+			ordererMod: func(o *Orderer) {
+				o.OrdererType = orderer.ConsensusTypeSmartBFT
+				o.SmartBFT.Consenters = []orderer.SmartBFTConsenter{{
+					ID:      1,
+					Address: orderer.EtcdAddress{Host: "host1", Port: 123},
+					MSPID:   "MSPID",
+				}}
+			},
+			err: "invalid smartbft configuration: identity for consenter host1:123 is required",
+		},
+		{
+			testName: "When smartbft consenter IDs are duplicated",
+			ordererMod: func(o *Orderer) {
+				cert := o.Organizations[0].MSP.RootCerts[0]
+				consenter := orderer.SmartBFTConsenter{
+					ID:            1,
+					Address:       orderer.EtcdAddress{Host: "host1", Port: 123},
+					MSPID:         "MSPID",
+					Identity:      cert,
+					ClientTLSCert: cert,
+					ServerTLSCert: cert,
+				}
+				other := consenter
+				other.Address.Host = "host2"
+				o.OrdererType = orderer.ConsensusTypeSmartBFT
+				o.SmartBFT.Consenters = []orderer.SmartBFTConsenter{consenter, other}
+			},
+			err: "invalid smartbft configuration: duplicate consenter ID 1",
+		},
+		{
+			testName: "When a smartbft timeout is invalid",
+			ordererMod: func(o *Orderer) {
+				smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+				o.OrdererType = orderer.ConsensusTypeSmartBFT
+				o.SmartBFT = smartBFTOrderer.SmartBFT
+				o.SmartBFT.Options.ViewChangeTimeout = "soon"
+			},
+			err: `invalid smartbft configuration: invalid view change timeout 'soon': time: invalid duration "soon"`,
+		},
+		{
+			testName: "When an orderer org has no endpoints",
+			ordererMod: func(o *Orderer) {
+				o.Organizations[0].OrdererEndpoints = nil
+			},
+			err: "orderer endpoints are not defined for org OrdererOrg",
+		},
+		{
+			testName: "When orderer orgs are duplicated",
+			ordererMod: func(o *Orderer) {
+				o.Organizations = append(o.Organizations, o.Organizations[0])
+			},
+			err: "duplicate orderer org OrdererOrg",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			o, _ := baseSoloOrderer(t)
+			tt.ordererMod(&o)
+
+			err := o.Validate()
+			gt.Expect(err).To(MatchError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// MigrateLegacyOrdererAddresses moves the addresses of the deprecated channel
+// level OrdererAddresses value to the Endpoints values of the orderer orgs and
+// removes the legacy value.
+//
+// orgByAddress explicitly maps addresses in host:port form to the names of
+// orderer orgs and may be nil. Addresses which are not mapped are attributed
+// to the org which already lists the address among its endpoints, or else to
+// the org whose TLS root certs verify the server TLS cert of the consenter
+// listening on the address. If an address cannot be attributed to an org, an
+// error is returned and the config is left untouched.
+func (c *ConfigTx) MigrateLegacyOrdererAddresses(orgByAddress map[string]string) (err error) {
+	defer c.observe("ConfigTx.MigrateLegacyOrdererAddresses", time.Now(), &err)
+
+	channelGroup := c.updated.ChannelGroup
+
+	if _, ok := channelGroup.Values[OrdererAddressesKey]; !ok {
+		return errors.New("config does not contain legacy orderer addresses")
+	}
+
+	legacyAddresses := &cb.OrdererAddresses{}
+	err = unmarshalConfigValueAtKey(channelGroup, OrdererAddressesKey, legacyAddresses)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := channelGroup.Groups[OrdererGroupKey]; !ok {
+		return errors.New("orderer group does not exist")
+	}
+
+	ordererConfig, err := c.Orderer().Configuration()
+	if err != nil {
+		return fmt.Errorf("retrieving orderer config: %v", err)
+	}
+
+	consenterOrgs := consenterOrgsByAddress(ordererConfig)
+	endpointOrgs := map[string]string{}
+	for _, org := range ordererConfig.Organizations {
+		for _, endpoint := range org.OrdererEndpoints {
+			endpointOrgs[endpoint] = org.Name
+		}
+	}
+
+	endpoints := map[string][]Address{}
+	var orgNames []string
+	for _, address := range legacyAddresses.Addresses {
+		host, port, err := splitEndpoint(address)
+		if err != nil {
+			return fmt.Errorf("invalid legacy orderer address '%s': %v", address, err)
+		}
+
+		orgName, ok := orgByAddress[address]
+		if !ok {
+			orgName = endpointOrgs[address]
+		}
+		if !ok && orgName == "" {
+			orgName = consenterOrgs[address]
+			if orgName != "" {
+				c.observed().warn("legacy orderer address attributed by consenter cert", "address", address, "org", orgName)
+			}
+		}
+		if orgName == "" {
+			return fmt.Errorf("cannot attribute legacy orderer address %s to an orderer org", address)
+		}
+		if c.Orderer().Organization(orgName) == nil {
+			return fmt.Errorf("orderer org %s does not exist", orgName)
+		}
+
+		if _, ok := endpoints[orgName]; !ok {
+			orgNames = append(orgNames, orgName)
+		}
+		endpoints[orgName] = append(endpoints[orgName], Address{Host: host, Port: port})
+	}
+
+	for _, orgName := range orgNames {
+		org := c.Orderer().Organization(orgName)
+		for _, endpoint := range endpoints[orgName] {
+			err = org.SetEndpoint(endpoint)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	c.Channel().RemoveLegacyOrdererAddresses()
+
+	return nil
+}
+
+// consenterOrgsByAddress returns the names of the orgs of the etcdraft or
+// SmartBFT consenters keyed by their host:port address. Consenters whose org
+// cannot be determined are omitted.
+func consenterOrgsByAddress(o Orderer) map[string]string {
+	orgs := map[string]string{}
+
+	switch o.OrdererType {
+	case orderer.ConsensusTypeEtcdRaft:
+		for _, consenter := range o.EtcdRaft.Consenters {
+			if org := etcdRaftConsenterOrg(o.Organizations, consenter); org != "" {
+				orgs[consenterAddress(consenter.Address)] = org
+			}
+		}
+	case orderer.ConsensusTypeSmartBFT:
+		for _, consenter := range o.SmartBFT.Consenters {
+			if org := smartBFTConsenterOrg(o.Organizations, consenter); org != "" {
+				orgs[consenterAddress(consenter.Address)] = org
+			}
+		}
+	}
+
+	return orgs
+}
+
+// etcdRaftConsenterOrg returns the name of the org whose TLS CA issued the
+// server TLS cert of the consenter, or "" if there is none.
+func etcdRaftConsenterOrg(orgs []Organization, consenter orderer.Consenter) string {
+	return tlsCertOrg(orgs, consenter.ServerTLSCert)
+}
+
+// smartBFTConsenterOrg returns the name of the org whose TLS CA issued the
+// server TLS cert of the consenter or, failing that, whose MSP ID is the one
+// of the consenter, or "" if there is none.
+func smartBFTConsenterOrg(orgs []Organization, consenter orderer.SmartBFTConsenter) string {
+	if org := tlsCertOrg(orgs, consenter.ServerTLSCert); org != "" {
+		return org
+	}
+
+	return mspIDOrg(orgs, consenter.MSPID)
+}
@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	. "github.com/onsi/gomega"
+)
+
+func legacyOrdererAddressesConfig(t *testing.T, addresses ...string) ConfigTx {
+	gt := NewGomegaWithT(t)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	serverCert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", caCert, caPrivKey)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	etcdRaftOrderer.Organizations[0].MSP.TLSRootCerts = []*x509.Certificate{caCert}
+	etcdRaftOrderer.EtcdRaft.Consenters[0].ServerTLSCert = serverCert
+
+	ordererGroup, err := newOrdererGroup(etcdRaftOrderer)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup := newConfigGroup()
+	channelGroup.Groups[OrdererGroupKey] = ordererGroup
+	channelGroup.Values[OrdererAddressesKey] = &cb.ConfigValue{
+		Value: marshalOrPanic(&cb.OrdererAddresses{
+			Addresses: addresses,
+		}),
+	}
+
+	return New(&cb.Config{ChannelGroup: channelGroup})
+}
+
+func TestMigrateLegacyOrdererAddresses(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := legacyOrdererAddressesConfig(t, "node-1.example.com:7050", "legacy.example.com:7050", "localhost:123")
+
+	err := c.MigrateLegacyOrdererAddresses(map[string]string{
+		"legacy.example.com:7050": "OrdererOrg",
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	org, err := c.Orderer().Organization("OrdererOrg").Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(org.OrdererEndpoints).To(Equal([]string{
+		"localhost:123",
+		"node-1.example.com:7050",
+		"legacy.example.com:7050",
+	}))
+
+	_, ok := c.updated.ChannelGroup.Values[OrdererAddressesKey]
+	gt.Expect(ok).To(BeFalse())
+}
+
+func TestMigrateLegacyOrdererAddressesFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName     string
+		addresses    []string
+		orgByAddress map[string]string
+		expectedErr  string
+	}{
+		{
+			testName:    "when an address cannot be attributed",
+			addresses:   []string{"node-1.example.com:7050", "node-2.example.com:7050"},
+			expectedErr: "cannot attribute legacy orderer address node-2.example.com:7050 to an orderer org",
+		},
+		{
+			testName:     "when an address is mapped to an unknown org",
+			addresses:    []string{"node-2.example.com:7050"},
+			orgByAddress: map[string]string{"node-2.example.com:7050": "UnknownOrg"},
+			expectedErr:  "orderer org UnknownOrg does not exist",
+		},
+		{
+			testName:    "when an address is invalid",
+			addresses:   []string{"node-2.example.com"},
+			expectedErr: "invalid legacy orderer address 'node-2.example.com': address node-2.example.com: missing port in address",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := legacyOrdererAddressesConfig(t, tt.addresses...)
+			err := c.MigrateLegacyOrdererAddresses(tt.orgByAddress)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+
+			org, err := c.Orderer().Organization("OrdererOrg").Configuration()
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(org.OrdererEndpoints).To(Equal([]string{"localhost:123"}))
+			_, ok := c.updated.ChannelGroup.Values[OrdererAddressesKey]
+			gt.Expect(ok).To(BeTrue())
+		})
+	}
+
+	gt := NewGomegaWithT(t)
+	channelGroup, _, err := baseOrdererChannelGroup(t, "solo")
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	err = c.MigrateLegacyOrdererAddresses(nil)
+	gt.Expect(err).To(MatchError("config does not contain legacy orderer addresses"))
+}
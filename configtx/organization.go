@@ -7,14 +7,123 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	pb "github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-config/protolator/protoext/ordererext"
+	"github.com/hyperledger/fabric-config/protolator/protoext/peerext"
 )
 
+// Validate checks that the organization is named, defines the standard
+// policies, has a valid MSP and valid anchor peer addresses.
+func (o Organization) Validate() error {
+	if o.Name == "" {
+		return errors.New("organization name is required")
+	}
+
+	if err := validatePolicies(o.Policies); err != nil {
+		return fmt.Errorf("org %s: %v", o.Name, err)
+	}
+
+	if err := o.MSP.Validate(); err != nil {
+		return fmt.Errorf("org %s: %v", o.Name, err)
+	}
+
+	for _, anchorPeer := range o.AnchorPeers {
+		if err := anchorPeer.Validate(); err != nil {
+			return fmt.Errorf("org %s: invalid anchor peer: %v", o.Name, err)
+		}
+	}
+
+	for _, endpoint := range o.OrdererEndpoints {
+		if endpoint == "" {
+			return fmt.Errorf("org %s: orderer endpoint cannot be empty", o.Name)
+		}
+		if _, err := ParseAddress(endpoint); err != nil {
+			return fmt.Errorf("org %s: invalid orderer endpoint: %v", o.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// PrintOrgJSON returns the definition of the organization as JSON in the
+// format printed by configtxgen -printOrg, which add-org procedures merge
+// into the config of a channel. As with configtxgen, an organization with
+// orderer endpoints is exported as an orderer org and any other organization
+// as an application org.
+func (o Organization) PrintOrgJSON() ([]byte, error) {
+	var orgGroup proto.Message
+	if len(o.OrdererEndpoints) > 0 {
+		configGroup, err := newOrdererOrgConfigGroup(o)
+		if err != nil {
+			return nil, fmt.Errorf("bad org definition for org %s: %v", o.Name, err)
+		}
+		orgGroup = &ordererext.DynamicOrdererOrgGroup{ConfigGroup: configGroup}
+	} else {
+		configGroup, err := newApplicationOrgConfigGroup(o)
+		if err != nil {
+			return nil, fmt.Errorf("bad org definition for org %s: %v", o.Name, err)
+		}
+		orgGroup = &peerext.DynamicApplicationOrgGroup{ConfigGroup: configGroup}
+	}
+
+	buf := &bytes.Buffer{}
+	err := protolator.DeepMarshalJSON(buf, orgGroup)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling org %s: %v", o.Name, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// ParsePrintOrgJSON parses the definition of an organization in the format
+// printed by configtxgen -printOrg, see PrintOrgJSON, into an Organization
+// with the given name which can be added to a channel config. A definition
+// with an Endpoints value is parsed as an orderer org and any other
+// definition as an application org.
+func ParsePrintOrgJSON(name string, printOrg []byte) (Organization, error) {
+	values := struct {
+		Values map[string]json.RawMessage `json:"values"`
+	}{}
+	err := json.Unmarshal(printOrg, &values)
+	if err != nil {
+		return Organization{}, fmt.Errorf("unmarshaling org %s: %v", name, err)
+	}
+
+	orgGroup := &cb.ConfigGroup{}
+	var msg proto.Message = &peerext.DynamicApplicationOrgGroup{ConfigGroup: orgGroup}
+	_, isOrdererOrg := values.Values[EndpointsKey]
+	if isOrdererOrg {
+		msg = &ordererext.DynamicOrdererOrgGroup{ConfigGroup: orgGroup}
+	}
+
+	err = protolator.DeepUnmarshalJSON(bytes.NewReader(printOrg), msg)
+	if err != nil {
+		return Organization{}, fmt.Errorf("unmarshaling org %s: %v", name, err)
+	}
+
+	var org Organization
+	if isOrdererOrg {
+		org, err = (&OrdererOrg{orgGroup: orgGroup, name: name}).Configuration()
+	} else {
+		org, err = getOrganization(orgGroup, name)
+	}
+	if err != nil {
+		return Organization{}, fmt.Errorf("parsing org %s: %v", name, err)
+	}
+	org.ModPolicy = orgGroup.ModPolicy
+
+	return org, nil
+}
+
 // newOrgConfigGroup returns an config group for an organization.
 // It defines the crypto material for the organization (its MSP).
 // It sets the mod_policy of all elements to "Admins".
@@ -50,6 +159,11 @@ func newOrgConfigGroup(org Organization) (*cb.ConfigGroup, error) {
 		return nil, err
 	}
 
+	err = setCustomValues(orgGroup, org.CustomValues)
+	if err != nil {
+		return nil, err
+	}
+
 	return orgGroup, nil
 }
 
@@ -127,10 +241,16 @@ func getOrganization(orgGroup *cb.ConfigGroup, orgName string) (Organization, er
 		}
 	}
 
+	customValues, err := getCustomValues(orgGroup)
+	if err != nil {
+		return Organization{}, err
+	}
+
 	return Organization{
-		Name:        orgName,
-		Policies:    policies,
-		MSP:         msp,
-		AnchorPeers: anchorPeers,
+		Name:         orgName,
+		Policies:     policies,
+		MSP:          msp,
+		AnchorPeers:  anchorPeers,
+		CustomValues: customValues,
 	}, nil
 }
@@ -8,6 +8,7 @@ package configtx
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -191,6 +192,147 @@ func TestNewOrgConfigGroupFailure(t *testing.T) {
 	gt.Expect(err).To(MatchError("no policies defined"))
 }
 
+func TestOrganizationPrintOrgJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName       string
+		orgMod         func(*Organization)
+		expectedValues []string
+	}{
+		{
+			testName:       "When the organization is an application org",
+			orgMod:         func(o *Organization) {},
+			expectedValues: []string{AnchorPeersKey, MSPKey},
+		},
+		{
+			testName: "When the organization is an orderer org",
+			orgMod: func(o *Organization) {
+				o.AnchorPeers = nil
+				o.OrdererEndpoints = []string{"orderer1.example.com:7050"}
+			},
+			expectedValues: []string{EndpointsKey, MSPKey},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			org := baseApplicationOrg(t)
+			tt.orgMod(&org)
+
+			printOrg, err := org.PrintOrgJSON()
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			decoded := struct {
+				ModPolicy string                     `json:"mod_policy"`
+				Values    map[string]json.RawMessage `json:"values"`
+				Policies  map[string]json.RawMessage `json:"policies"`
+			}{}
+			err = json.Unmarshal(printOrg, &decoded)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(decoded.ModPolicy).To(Equal(AdminsPolicyKey))
+			gt.Expect(decoded.Values).To(HaveLen(len(tt.expectedValues)))
+			for _, key := range tt.expectedValues {
+				gt.Expect(decoded.Values).To(HaveKey(key))
+			}
+			gt.Expect(decoded.Policies).To(HaveLen(len(org.Policies)))
+			gt.Expect(string(printOrg)).To(ContainSubstring(`"MSPID"`))
+		})
+	}
+}
+
+func TestOrganizationPrintOrgJSONFailure(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	org := baseApplicationOrg(t)
+	org.Policies = nil
+
+	_, err := org.PrintOrgJSON()
+	gt.Expect(err).To(MatchError("bad org definition for org Org1: no policies defined"))
+}
+
+func TestParsePrintOrgJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName string
+		orgMod   func(*Organization)
+	}{
+		{
+			testName: "When the organization is an application org",
+			orgMod:   func(o *Organization) {},
+		},
+		{
+			testName: "When the organization is an orderer org",
+			orgMod: func(o *Organization) {
+				o.AnchorPeers = nil
+				o.OrdererEndpoints = []string{"orderer1.example.com:7050"}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			expectedOrg := baseApplicationOrg(t)
+			expectedOrg.ModPolicy = AdminsPolicyKey
+			tt.orgMod(&expectedOrg)
+
+			printOrg, err := expectedOrg.PrintOrgJSON()
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			org, err := ParsePrintOrgJSON("Org1", printOrg)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(org).To(Equal(expectedOrg))
+		})
+	}
+}
+
+func TestParsePrintOrgJSONFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		printOrg    string
+		expectedErr string
+	}{
+		{
+			testName:    "When the definition is not JSON",
+			printOrg:    "{",
+			expectedErr: "unmarshaling org Org1: unexpected end of JSON input",
+		},
+		{
+			testName:    "When a value cannot be decoded",
+			printOrg:    `{"values": {"MSP": {"value": {"config": "not base64"}}}}`,
+			expectedErr: "unmarshaling org Org1: ",
+		},
+		{
+			testName:    "When the definition has no MSP",
+			printOrg:    `{"mod_policy": "Admins"}`,
+			expectedErr: "parsing org Org1: ",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := ParsePrintOrgJSON("Org1", []byte(tt.printOrg))
+			gt.Expect(err).To(MatchError(ContainSubstring(tt.expectedErr)))
+		})
+	}
+}
+
 func baseApplicationOrg(t *testing.T) Organization {
 	msp, _ := baseMSP(t)
 	return Organization{
@@ -202,3 +344,71 @@ func baseApplicationOrg(t *testing.T) Organization {
 		},
 	}
 }
+
+func TestOrganizationValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName string
+		orgMod   func(*Organization)
+		err      string
+	}{
+		{
+			testName: "When the organization is valid",
+			orgMod:   func(o *Organization) {},
+		},
+		{
+			testName: "When the organization name is missing",
+			orgMod: func(o *Organization) {
+				o.Name = ""
+			},
+			err: "organization name is required",
+		},
+		{
+			testName: "When a policy is invalid",
+			orgMod: func(o *Organization) {
+				o.Policies[AdminsPolicyKey] = Policy{Type: ImplicitMetaPolicyType, Rule: "Admins"}
+			},
+			err: "org Org1: policy 'Admins': invalid implicit meta policy rule: 'Admins': expected two space separated tokens, but got 1",
+		},
+		{
+			testName: "When the MSP has no root certs",
+			orgMod: func(o *Organization) {
+				o.MSP.RootCerts = nil
+			},
+			err: "org Org1: MSP MSPID: root certs are required",
+		},
+		{
+			testName: "When an anchor peer has an invalid port",
+			orgMod: func(o *Organization) {
+				o.AnchorPeers = []Address{{Host: "host1", Port: 70000}}
+			},
+			err: "org Org1: invalid anchor peer: invalid port 70000 for host host1",
+		},
+		{
+			testName: "When an anchor peer has no host",
+			orgMod: func(o *Organization) {
+				o.AnchorPeers = []Address{{Port: 7051}}
+			},
+			err: "org Org1: invalid anchor peer: host is required",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			org := baseApplicationOrg(t)
+			tt.orgMod(&org)
+
+			err := org.Validate()
+			if tt.err == "" {
+				gt.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			gt.Expect(err).To(MatchError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// ConsenterOwner attributes an etcdraft or SmartBFT consenter to the orderer
+// organization owning it.
+type ConsenterOwner struct {
+	Host string
+	Port int
+	// ConsenterID is the identifier of a SmartBFT consenter.
+	ConsenterID uint64
+	// ClientTLSOrg and ServerTLSOrg are the names of the organizations whose
+	// TLS CAs issued the TLS certificates of the consenter. They are empty if
+	// no orderer organization issued the certificate.
+	ClientTLSOrg string
+	ServerTLSOrg string
+	// IdentityOrg is the name of the organization whose CA issued the
+	// identity certificate of a SmartBFT consenter.
+	IdentityOrg string
+	// MSPIDOrg is the name of the organization with the MSP ID of a SmartBFT
+	// consenter.
+	MSPIDOrg string
+	// Org is the name of the organization owning the consenter. It is empty
+	// if the consenter belongs to no orderer organization or to several.
+	Org string
+	// Problems describes why the consenter could not be attributed to a
+	// single organization, or which of its certificates were not issued by
+	// the owning organization.
+	Problems []string
+}
+
+// ConsenterOwnership attributes every consenter of the etcdraft or SmartBFT
+// consensus metadata of the updated config to the orderer organization whose
+// CAs issued its certificates. Consenters belonging to no configured
+// organization are a frequent cause of consensus failures and are reported
+// with an empty Org.
+func (o *OrdererGroup) ConsenterOwnership() ([]ConsenterOwner, error) {
+	ordererConfig, err := o.Configuration()
+	if err != nil {
+		return nil, err
+	}
+
+	return ordererConfig.ConsenterOwnership(), nil
+}
+
+// ConsenterOwnership attributes every consenter of the etcdraft or SmartBFT
+// consensus metadata of the orderer configuration to the orderer organization
+// whose CAs issued its certificates.
+func (o Orderer) ConsenterOwnership() []ConsenterOwner {
+	var owners []ConsenterOwner
+
+	switch o.OrdererType {
+	case orderer.ConsensusTypeEtcdRaft:
+		for _, consenter := range o.EtcdRaft.Consenters {
+			owner := ConsenterOwner{
+				Host:         consenter.Address.Host,
+				Port:         consenter.Address.Port,
+				ClientTLSOrg: tlsCertOrg(o.Organizations, consenter.ClientTLSCert),
+				ServerTLSOrg: tlsCertOrg(o.Organizations, consenter.ServerTLSCert),
+			}
+			owner.attribute(map[string]string{
+				"client tls cert": owner.ClientTLSOrg,
+				"server tls cert": owner.ServerTLSOrg,
+			})
+			owners = append(owners, owner)
+		}
+	case orderer.ConsensusTypeSmartBFT:
+		for _, consenter := range o.SmartBFT.Consenters {
+			owner := ConsenterOwner{
+				Host:         consenter.Address.Host,
+				Port:         consenter.Address.Port,
+				ConsenterID:  consenter.ID,
+				ClientTLSOrg: tlsCertOrg(o.Organizations, consenter.ClientTLSCert),
+				ServerTLSOrg: tlsCertOrg(o.Organizations, consenter.ServerTLSCert),
+				IdentityOrg:  identityCertOrg(o.Organizations, consenter.Identity),
+				MSPIDOrg:     mspIDOrg(o.Organizations, consenter.MSPID),
+			}
+			owner.attribute(map[string]string{
+				"client tls cert":           owner.ClientTLSOrg,
+				"server tls cert":           owner.ServerTLSOrg,
+				"identity cert":             owner.IdentityOrg,
+				"msp id " + consenter.MSPID: owner.MSPIDOrg,
+			})
+			owners = append(owners, owner)
+		}
+	}
+
+	return owners
+}
+
+// attribute sets the owning org from the orgs the credentials of the
+// consenter were attributed to, and records the credentials which were not
+// attributed to the owning org.
+func (c *ConsenterOwner) attribute(orgByCredential map[string]string) {
+	credentials := make([]string, 0, len(orgByCredential))
+	credentialsByOrg := map[string][]string{}
+	for credential, org := range orgByCredential {
+		credentials = append(credentials, credential)
+		if org != "" {
+			credentialsByOrg[org] = append(credentialsByOrg[org], credential)
+		}
+	}
+	sort.Strings(credentials)
+
+	switch len(credentialsByOrg) {
+	case 0:
+		c.Problems = append(c.Problems, "consenter belongs to no orderer org")
+		return
+	case 1:
+		for org := range credentialsByOrg {
+			c.Org = org
+		}
+	default:
+		orgs := make([]string, 0, len(credentialsByOrg))
+		for org := range credentialsByOrg {
+			orgs = append(orgs, org)
+		}
+		sort.Strings(orgs)
+		c.Problems = append(c.Problems, fmt.Sprintf("consenter credentials belong to several orderer orgs: %s", strings.Join(orgs, ", ")))
+	}
+
+	for _, credential := range credentials {
+		switch org := orgByCredential[credential]; {
+		case org == "":
+			c.Problems = append(c.Problems, fmt.Sprintf("%s does not belong to any orderer org", credential))
+		case c.Org == "":
+			c.Problems = append(c.Problems, fmt.Sprintf("%s belongs to org %s", credential, org))
+		}
+	}
+}
+
+// OrgConsenters are the consenters of an orderer organization. Only the
+// consenters of the consensus type of the orderer are set.
+type OrgConsenters struct {
+	EtcdRaft []orderer.Consenter
+	SmartBFT []orderer.SmartBFTConsenter
+	// Ambiguous are the owners of the consenters some of whose credentials
+	// belong to the organization while others belong to other organizations
+	// or to none. They are not attributed to the organization and must be
+	// reviewed before retiring it.
+	Ambiguous []ConsenterOwner
+}
+
+// ConsentersForOrg returns the etcdraft or SmartBFT consenters of the updated
+// config belonging to the orderer org, which are the ordering nodes to retire
+// along with the org. A consenter belongs to the org if ConsenterOwnership
+// attributes it to the org. Consenters whose credentials belong to several
+// orgs are reported as ambiguous.
+func (o *OrdererGroup) ConsentersForOrg(name string) (OrgConsenters, error) {
+	if o.Organization(name) == nil {
+		return OrgConsenters{}, fmt.Errorf("orderer org %s does not exist", name)
+	}
+
+	cfg, err := o.Configuration()
+	if err != nil {
+		return OrgConsenters{}, err
+	}
+
+	var consenters OrgConsenters
+	for i, owner := range cfg.ConsenterOwnership() {
+		switch {
+		case owner.Org == name:
+			switch cfg.OrdererType {
+			case orderer.ConsensusTypeEtcdRaft:
+				consenters.EtcdRaft = append(consenters.EtcdRaft, cfg.EtcdRaft.Consenters[i])
+			case orderer.ConsensusTypeSmartBFT:
+				consenters.SmartBFT = append(consenters.SmartBFT, cfg.SmartBFT.Consenters[i])
+			}
+		case owner.Org == "" && owner.credentialOf(name):
+			consenters.Ambiguous = append(consenters.Ambiguous, owner)
+		}
+	}
+
+	return consenters, nil
+}
+
+// credentialOf returns whether any credential of the consenter belongs to the
+// org.
+func (c ConsenterOwner) credentialOf(org string) bool {
+	for _, credentialOrg := range []string{c.ClientTLSOrg, c.ServerTLSOrg, c.IdentityOrg, c.MSPIDOrg} {
+		if credentialOrg == org {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,241 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestConsenterOwnershipEtcdRaft(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	tlsCA, tlsCAPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	tlsCert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", tlsCA, tlsCAPrivKey)
+	otherCA, otherCAPrivKey := generateCACertAndPrivateKey(t, "other-org")
+	otherCert, _ := generateCertAndPrivateKeyFromCACert(t, "other-org", otherCA, otherCAPrivKey)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	etcdRaftOrderer.Organizations[0].MSP.TLSRootCerts = []*x509.Certificate{tlsCA}
+	etcdRaftOrderer.EtcdRaft.Consenters[0].ClientTLSCert = tlsCert
+	etcdRaftOrderer.EtcdRaft.Consenters[0].ServerTLSCert = tlsCert
+	etcdRaftOrderer.EtcdRaft.Consenters[2].ClientTLSCert = otherCert
+	etcdRaftOrderer.EtcdRaft.Consenters[2].ServerTLSCert = tlsCert
+
+	c := ordererConfigTx(t, etcdRaftOrderer)
+	owners, err := c.Orderer().ConsenterOwnership()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(owners).To(Equal([]ConsenterOwner{
+		{
+			Host:         "node-1.example.com",
+			Port:         7050,
+			ClientTLSOrg: "OrdererOrg",
+			ServerTLSOrg: "OrdererOrg",
+			Org:          "OrdererOrg",
+		},
+		{
+			Host:     "node-2.example.com",
+			Port:     7050,
+			Problems: []string{"consenter belongs to no orderer org"},
+		},
+		{
+			Host:         "node-3.example.com",
+			Port:         7050,
+			ServerTLSOrg: "OrdererOrg",
+			Org:          "OrdererOrg",
+			Problems:     []string{"client tls cert does not belong to any orderer org"},
+		},
+	}))
+}
+
+func TestConsenterOwnershipSmartBFT(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+	secondOrg := smartBFTOrderer.Organizations[0]
+	secondOrg.Name = "OrdererOrg2"
+	secondMSP, _ := baseMSP(t)
+	secondMSP.Name = "MSPID2"
+	secondOrg.MSP = secondMSP
+	smartBFTOrderer.Organizations = append(smartBFTOrderer.Organizations, secondOrg)
+
+	ca, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	cert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", ca, caPrivKey)
+	smartBFTOrderer.Organizations[0].MSP.RootCerts = []*x509.Certificate{ca}
+	smartBFTOrderer.Organizations[0].MSP.IntermediateCerts = nil
+	smartBFTOrderer.Organizations[0].MSP.TLSRootCerts = []*x509.Certificate{ca}
+	smartBFTOrderer.Organizations[0].MSP.TLSIntermediateCerts = nil
+	for i := range smartBFTOrderer.SmartBFT.Consenters {
+		smartBFTOrderer.SmartBFT.Consenters[i].Identity = cert
+		smartBFTOrderer.SmartBFT.Consenters[i].ClientTLSCert = cert
+		smartBFTOrderer.SmartBFT.Consenters[i].ServerTLSCert = cert
+	}
+	smartBFTOrderer.SmartBFT.Consenters[1].MSPID = "MSPID2"
+	smartBFTOrderer.SmartBFT.Consenters[2].MSPID = "UnknownMSP"
+
+	owners := smartBFTOrderer.ConsenterOwnership()
+	gt.Expect(owners).To(HaveLen(4))
+	gt.Expect(owners[0]).To(Equal(ConsenterOwner{
+		Host:         "node-1.example.com",
+		Port:         7050,
+		ConsenterID:  1,
+		ClientTLSOrg: "OrdererOrg",
+		ServerTLSOrg: "OrdererOrg",
+		IdentityOrg:  "OrdererOrg",
+		MSPIDOrg:     "OrdererOrg",
+		Org:          "OrdererOrg",
+	}))
+	gt.Expect(owners[1].Org).To(BeEmpty())
+	gt.Expect(owners[1].MSPIDOrg).To(Equal("OrdererOrg2"))
+	gt.Expect(owners[1].Problems).To(Equal([]string{
+		"consenter credentials belong to several orderer orgs: OrdererOrg, OrdererOrg2",
+		"client tls cert belongs to org OrdererOrg",
+		"identity cert belongs to org OrdererOrg",
+		"msp id MSPID2 belongs to org OrdererOrg2",
+		"server tls cert belongs to org OrdererOrg",
+	}))
+	gt.Expect(owners[2].Org).To(Equal("OrdererOrg"))
+	gt.Expect(owners[2].Problems).To(Equal([]string{"msp id UnknownMSP does not belong to any orderer org"}))
+}
+
+func TestConsenterOwnershipSolo(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	owners, err := c.Orderer().ConsenterOwnership()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(owners).To(BeEmpty())
+}
+
+func TestConsentersForOrgEtcdRaft(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	tlsCA, tlsCAPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	tlsCert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", tlsCA, tlsCAPrivKey)
+	otherCA, otherCAPrivKey := generateCACertAndPrivateKey(t, "other-org")
+	otherCert, _ := generateCertAndPrivateKeyFromCACert(t, "other-org", otherCA, otherCAPrivKey)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	etcdRaftOrderer.Organizations[0].MSP.TLSRootCerts = []*x509.Certificate{tlsCA}
+	for i := range etcdRaftOrderer.EtcdRaft.Consenters {
+		etcdRaftOrderer.EtcdRaft.Consenters[i].ClientTLSCert = otherCert
+		etcdRaftOrderer.EtcdRaft.Consenters[i].ServerTLSCert = otherCert
+	}
+	etcdRaftOrderer.EtcdRaft.Consenters[0].ServerTLSCert = tlsCert
+	etcdRaftOrderer.EtcdRaft.Consenters[2].ServerTLSCert = tlsCert
+
+	c := ordererConfigTx(t, etcdRaftOrderer)
+	consenters, err := c.Orderer().ConsentersForOrg("OrdererOrg")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(consenters.SmartBFT).To(BeEmpty())
+	gt.Expect(consenters.EtcdRaft).To(Equal([]orderer.Consenter{
+		etcdRaftOrderer.EtcdRaft.Consenters[0],
+		etcdRaftOrderer.EtcdRaft.Consenters[2],
+	}))
+}
+
+func TestConsentersForOrgSmartBFT(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+	secondOrg := smartBFTOrderer.Organizations[0]
+	secondOrg.Name = "OrdererOrg2"
+	secondMSP, _ := baseMSP(t)
+	secondMSP.Name = "MSPID2"
+	secondOrg.MSP = secondMSP
+	smartBFTOrderer.Organizations = append(smartBFTOrderer.Organizations, secondOrg)
+
+	otherCA, otherCAPrivKey := generateCACertAndPrivateKey(t, "other-org")
+	otherCert, _ := generateCertAndPrivateKeyFromCACert(t, "other-org", otherCA, otherCAPrivKey)
+	for i := range smartBFTOrderer.SmartBFT.Consenters {
+		smartBFTOrderer.SmartBFT.Consenters[i].ServerTLSCert = otherCert
+	}
+	smartBFTOrderer.SmartBFT.Consenters[1].MSPID = "MSPID2"
+
+	c := ordererConfigTx(t, smartBFTOrderer)
+	consenters, err := c.Orderer().ConsentersForOrg("OrdererOrg2")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(consenters.EtcdRaft).To(BeEmpty())
+	gt.Expect(consenters.SmartBFT).To(HaveLen(1))
+	gt.Expect(consenters.SmartBFT[0].ID).To(Equal(smartBFTOrderer.SmartBFT.Consenters[1].ID))
+
+	consenters, err = c.Orderer().ConsentersForOrg("OrdererOrg")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(consenters.SmartBFT).To(HaveLen(len(smartBFTOrderer.SmartBFT.Consenters) - 1))
+	for _, consenter := range consenters.SmartBFT {
+		gt.Expect(consenter.MSPID).To(Equal("MSPID"))
+	}
+}
+
+func TestConsentersForOrgAmbiguous(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	tlsCA, tlsCAPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	tlsCert, _ := generateCertAndPrivateKeyFromCACert(t, "orderer-org", tlsCA, tlsCAPrivKey)
+	otherCA, otherCAPrivKey := generateCACertAndPrivateKey(t, "other-org")
+	otherCert, _ := generateCertAndPrivateKeyFromCACert(t, "other-org", otherCA, otherCAPrivKey)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	etcdRaftOrderer.Organizations[0].MSP.TLSRootCerts = []*x509.Certificate{tlsCA}
+	secondOrg := etcdRaftOrderer.Organizations[0]
+	secondOrg.Name = "OrdererOrg2"
+	secondMSP, _ := baseMSP(t)
+	secondMSP.Name = "MSPID2"
+	secondMSP.TLSRootCerts = []*x509.Certificate{otherCA}
+	secondOrg.MSP = secondMSP
+	etcdRaftOrderer.Organizations = append(etcdRaftOrderer.Organizations, secondOrg)
+	for i := range etcdRaftOrderer.EtcdRaft.Consenters {
+		etcdRaftOrderer.EtcdRaft.Consenters[i].ClientTLSCert = tlsCert
+		etcdRaftOrderer.EtcdRaft.Consenters[i].ServerTLSCert = tlsCert
+	}
+	etcdRaftOrderer.EtcdRaft.Consenters[1].ClientTLSCert = otherCert
+
+	c := ordererConfigTx(t, etcdRaftOrderer)
+	consenters, err := c.Orderer().ConsentersForOrg("OrdererOrg")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(consenters.EtcdRaft).To(Equal([]orderer.Consenter{
+		etcdRaftOrderer.EtcdRaft.Consenters[0],
+		etcdRaftOrderer.EtcdRaft.Consenters[2],
+	}))
+	gt.Expect(consenters.Ambiguous).To(HaveLen(1))
+	gt.Expect(consenters.Ambiguous[0].Host).To(Equal(etcdRaftOrderer.EtcdRaft.Consenters[1].Address.Host))
+	gt.Expect(consenters.Ambiguous[0].Problems).To(ContainElement("consenter credentials belong to several orderer orgs: OrdererOrg, OrdererOrg2"))
+
+	consenters, err = c.Orderer().ConsentersForOrg("OrdererOrg2")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(consenters.EtcdRaft).To(BeEmpty())
+	gt.Expect(consenters.Ambiguous).To(HaveLen(1))
+}
+
+func TestConsentersForOrgFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	_, err = c.Orderer().ConsentersForOrg("UnknownOrg")
+	gt.Expect(err).To(MatchError("orderer org UnknownOrg does not exist"))
+
+	consenters, err := c.Orderer().ConsentersForOrg("OrdererOrg")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(consenters).To(Equal(OrgConsenters{}))
+}
@@ -0,0 +1,258 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pemutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+const encryptedPrivateKeyType = "ENCRYPTED PRIVATE KEY"
+
+// ErrIncorrectPassphrase is returned when an encrypted private key cannot be
+// decrypted with the given passphrase.
+var ErrIncorrectPassphrase = errors.New("incorrect passphrase for encrypted private key")
+
+var (
+	oidPBES2  = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2 = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidHMACWithSHA384 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 10}
+	oidHMACWithSHA512 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 11}
+
+	oidAES128CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC  = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+	oidDESEDE3CBC = asn1.ObjectIdentifier{1, 2, 840, 113549, 3, 7}
+)
+
+// encryptedPrivateKeyInfo is the PKCS#8 EncryptedPrivateKeyInfo structure.
+type encryptedPrivateKeyInfo struct {
+	EncryptionAlgorithm pkix.AlgorithmIdentifier
+	EncryptedData       []byte
+}
+
+// pbes2Params are the parameters of the PBES2 encryption scheme of RFC 8018.
+type pbes2Params struct {
+	KeyDerivationFunc pkix.AlgorithmIdentifier
+	EncryptionScheme  pkix.AlgorithmIdentifier
+}
+
+// pbkdf2Params are the parameters of the PBKDF2 key derivation function of
+// RFC 8018.
+type pbkdf2Params struct {
+	Salt           []byte
+	IterationCount int
+	KeyLength      int                      `asn1:"optional"`
+	PRF            pkix.AlgorithmIdentifier `asn1:"optional"`
+}
+
+// IsEncryptedPrivateKey reports whether the first PEM block of pemBytes is a
+// passphrase protected private key.
+func IsEncryptedPrivateKey(pemBytes []byte) bool {
+	pemBlock, _ := pem.Decode(pemBytes)
+	if pemBlock == nil {
+		return false
+	}
+
+	return pemBlock.Type == encryptedPrivateKeyType || x509.IsEncryptedPEMBlock(pemBlock)
+}
+
+// ParseEncryptedPrivateKey parses the first PEM block of pemBytes as a private
+// key protected by the passphrase. Both PKCS#8 keys encrypted with PBES2, as
+// written by `openssl pkcs8 -topk8`, and legacy PEM blocks encrypted as
+// specified by RFC 1423, as written by `openssl ec -aes256`, are supported.
+// Unencrypted PKCS#8, SEC 1 EC and PKCS#1 RSA keys are parsed as well, so
+// callers need not know whether a key is encrypted.
+func ParseEncryptedPrivateKey(pemBytes, passphrase []byte) (crypto.PrivateKey, error) {
+	pemBlock, _ := pem.Decode(pemBytes)
+	if pemBlock == nil {
+		return nil, fmt.Errorf("no PEM data found in private key[% x]", pemBytes)
+	}
+
+	der := pemBlock.Bytes
+	keyType := pemBlock.Type
+
+	if x509.IsEncryptedPEMBlock(pemBlock) {
+		var err error
+		der, err = x509.DecryptPEMBlock(pemBlock, passphrase)
+		if err == x509.IncorrectPasswordError {
+			return nil, ErrIncorrectPassphrase
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decrypting private key: %v", err)
+		}
+	}
+
+	if keyType == encryptedPrivateKeyType {
+		der, err := decryptPKCS8(der, passphrase)
+		if err != nil {
+			return nil, err
+		}
+
+		privateKey, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			// an incorrect passphrase may yield valid padding by chance,
+			// but not a valid key
+			return nil, ErrIncorrectPassphrase
+		}
+		return privateKey, nil
+	}
+
+	privateKey, err := parsePrivateKeyDER(keyType, der)
+	if err != nil && x509.IsEncryptedPEMBlock(pemBlock) {
+		// an incorrect passphrase may yield valid padding by chance, but not
+		// a valid key
+		return nil, ErrIncorrectPassphrase
+	}
+
+	return privateKey, err
+}
+
+// parsePrivateKeyDER parses the DER encoded private key of a PEM block of the
+// key type.
+func parsePrivateKeyDER(keyType string, der []byte) (crypto.PrivateKey, error) {
+	switch keyType {
+	case privateKeyType:
+		privateKey, err := x509.ParsePKCS8PrivateKey(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing PKCS#8 private key: %v", err)
+		}
+		return privateKey, nil
+	case "EC PRIVATE KEY":
+		privateKey, err := x509.ParseECPrivateKey(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing EC private key: %v", err)
+		}
+		return privateKey, nil
+	case "RSA PRIVATE KEY":
+		privateKey, err := x509.ParsePKCS1PrivateKey(der)
+		if err != nil {
+			return nil, fmt.Errorf("failed parsing PKCS#1 private key: %v", err)
+		}
+		return privateKey, nil
+	default:
+		return nil, fmt.Errorf("unsupported private key PEM block type %s", keyType)
+	}
+}
+
+// decryptPKCS8 decrypts the DER encoded PKCS#8 EncryptedPrivateKeyInfo and
+// returns the DER encoded PKCS#8 PrivateKeyInfo.
+func decryptPKCS8(der, passphrase []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("parsing encrypted private key: %v", err)
+	}
+	if !info.EncryptionAlgorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("unsupported private key encryption algorithm %s, only PBES2 is supported", info.EncryptionAlgorithm.Algorithm)
+	}
+
+	return decryptPBES2(info.EncryptionAlgorithm.Parameters.FullBytes, info.EncryptedData, passphrase)
+}
+
+// decryptPBES2 decrypts the ciphertext with the key derived from the
+// passphrase as specified by the DER encoded PBES2 parameters.
+func decryptPBES2(der, ciphertext, passphrase []byte) ([]byte, error) {
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(der, &params); err != nil {
+		return nil, fmt.Errorf("parsing PBES2 parameters: %v", err)
+	}
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("unsupported key derivation function %s, only PBKDF2 is supported", params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, fmt.Errorf("parsing PBKDF2 parameters: %v", err)
+	}
+	if kdfParams.IterationCount < 1 {
+		return nil, fmt.Errorf("invalid PBKDF2 iteration count %d", kdfParams.IterationCount)
+	}
+
+	prf := sha1.New
+	switch {
+	case len(kdfParams.PRF.Algorithm) == 0, kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA1):
+	case kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	case kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA384):
+		prf = sha512.New384
+	case kdfParams.PRF.Algorithm.Equal(oidHMACWithSHA512):
+		prf = sha512.New
+	default:
+		return nil, fmt.Errorf("unsupported PBKDF2 pseudorandom function %s", kdfParams.PRF.Algorithm)
+	}
+
+	var keyLen int
+	var newCipher func([]byte) (cipher.Block, error)
+	switch scheme := params.EncryptionScheme.Algorithm; {
+	case scheme.Equal(oidAES128CBC):
+		keyLen, newCipher = 16, aes.NewCipher
+	case scheme.Equal(oidAES192CBC):
+		keyLen, newCipher = 24, aes.NewCipher
+	case scheme.Equal(oidAES256CBC):
+		keyLen, newCipher = 32, aes.NewCipher
+	case scheme.Equal(oidDESEDE3CBC):
+		keyLen, newCipher = 24, des.NewTripleDESCipher
+	default:
+		return nil, fmt.Errorf("unsupported private key encryption scheme %s", scheme)
+	}
+	if kdfParams.KeyLength != 0 && kdfParams.KeyLength != keyLen {
+		return nil, fmt.Errorf("PBKDF2 key length %d does not match the encryption scheme", kdfParams.KeyLength)
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("parsing encryption scheme parameters: %v", err)
+	}
+
+	block, err := newCipher(pbkdf2.Key(passphrase, kdfParams.Salt, kdfParams.IterationCount, keyLen, prf))
+	if err != nil {
+		return nil, err
+	}
+
+	return decryptCBC(block, iv, ciphertext)
+}
+
+// decryptCBC decrypts the PKCS#7 padded ciphertext in CBC mode.
+func decryptCBC(block cipher.Block, iv, ciphertext []byte) ([]byte, error) {
+	if len(iv) != block.BlockSize() {
+		return nil, fmt.Errorf("invalid IV length %d", len(iv))
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%block.BlockSize() != 0 {
+		return nil, errors.New("ciphertext is not a multiple of the block size")
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+
+	// an incorrect passphrase almost always results in invalid padding
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > block.BlockSize() {
+		return nil, ErrIncorrectPassphrase
+	}
+	if !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, ErrIncorrectPassphrase
+	}
+
+	return plaintext[:len(plaintext)-padding], nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package pemutil parses and encodes the PEM encoded certificates, CRLs and
+// private keys consumed by the MSP APIs of configtx. It also parses the
+// PKCS#12 bundles of a private key and its certificate.
+package pemutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+const (
+	certificateType = "CERTIFICATE"
+	crlType         = "X509 CRL"
+	privateKeyType  = "PRIVATE KEY"
+)
+
+// ParseCertificate parses the first PEM block of pemBytes as a certificate.
+func ParseCertificate(pemBytes []byte) (*x509.Certificate, error) {
+	pemBlock, _ := pem.Decode(pemBytes)
+	if pemBlock == nil {
+		return nil, fmt.Errorf("no PEM data found in cert[% x]", pemBytes)
+	}
+
+	return x509.ParseCertificate(pemBlock.Bytes)
+}
+
+// ParseCertificates parses a bundle of PEM encoded certificates, as emitted
+// for CA chains by fabric-ca and cryptogen. Every PEM block of the bundle
+// must be a certificate, and the bundle must contain at least one.
+func ParseCertificates(bundle []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+
+	rest := bundle
+	for {
+		var pemBlock *pem.Block
+		pemBlock, rest = pem.Decode(rest)
+		if pemBlock == nil {
+			break
+		}
+
+		if pemBlock.Type != certificateType {
+			return nil, fmt.Errorf("PEM block %d is a %s, not a certificate", len(certs), pemBlock.Type)
+		}
+
+		cert, err := x509.ParseCertificate(pemBlock.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing certificate %d: %v", len(certs), err)
+		}
+
+		certs = append(certs, cert)
+	}
+
+	if len(certs) == 0 {
+		return nil, errors.New("no PEM encoded certificates found")
+	}
+
+	if len(bytes.TrimSpace(rest)) != 0 {
+		return nil, fmt.Errorf("unexpected data after certificate %d", len(certs)-1)
+	}
+
+	return certs, nil
+}
+
+// ParseCRL parses the first PEM block of pemBytes as a CRL.
+func ParseCRL(pemBytes []byte) (*pkix.CertificateList, error) {
+	pemBlock, _ := pem.Decode(pemBytes)
+	if pemBlock == nil {
+		return nil, fmt.Errorf("no PEM data found in CRL[% x]", pemBytes)
+	}
+
+	crl, err := x509.ParseCRL(pemBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing crl: %v", err)
+	}
+
+	return crl, nil
+}
+
+// ParsePrivateKey parses the first PEM block of pemBytes as a PKCS#8 private
+// key.
+func ParsePrivateKey(pemBytes []byte) (crypto.PrivateKey, error) {
+	pemBlock, _ := pem.Decode(pemBytes)
+	if pemBlock == nil {
+		return nil, fmt.Errorf("no PEM data found in private key[% x]", pemBytes)
+	}
+
+	privateKey, err := x509.ParsePKCS8PrivateKey(pemBlock.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed parsing PKCS#8 private key: %v", err)
+	}
+
+	return privateKey, nil
+}
+
+// EncodeCertificate returns the PEM encoding of the certificate.
+func EncodeCertificate(cert *x509.Certificate) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: certificateType, Bytes: cert.Raw})
+}
+
+// EncodeCertificates returns a bundle of the PEM encodings of the
+// certificates.
+func EncodeCertificates(certs []*x509.Certificate) []byte {
+	var bundle bytes.Buffer
+	for _, cert := range certs {
+		bundle.Write(EncodeCertificate(cert))
+	}
+
+	return bundle.Bytes()
+}
+
+// EncodeCRL returns the PEM encoding of the CRL.
+func EncodeCRL(crl *pkix.CertificateList) ([]byte, error) {
+	asn1MarshalledBytes, err := asn1.Marshal(*crl)
+	if err != nil {
+		return nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: crlType, Bytes: asn1MarshalledBytes}), nil
+}
+
+// EncodePrivateKey returns the PEM encoding of the private key in PKCS#8
+// form.
+func EncodePrivateKey(priv crypto.PrivateKey) ([]byte, error) {
+	privBytes, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling PKCS#8 private key: %v", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: privateKeyType, Bytes: privBytes}), nil
+}
@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pemutil
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// ParsePKCS12 parses the DER encoded PKCS#12 bundle protected by the
+// password, as exported by `openssl pkcs12 -export` or by the certificate
+// stores of Windows and macOS. The bundle must hold exactly one private key
+// and its certificate, which are returned along with the other certificates
+// of the bundle, such as the CA chain. Bundles encrypted with PBES2 and with
+// the legacy 3DES and 40 bit RC2 schemes are supported.
+func ParsePKCS12(data []byte, password string) (crypto.PrivateKey, *x509.Certificate, []*x509.Certificate, error) {
+	privateKey, leaf, caCerts, err := pkcs12.DecodeChain(data, password)
+	if err == pkcs12.ErrIncorrectPassword {
+		return nil, nil, nil, ErrIncorrectPassphrase
+	}
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("parsing PKCS#12 bundle: %v", err)
+	}
+
+	signer, ok := privateKey.(crypto.Signer)
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("unsupported private key type %T", privateKey)
+	}
+	publicKey, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("marshaling public key: %v", err)
+	}
+
+	// the bundle does not necessarily list the certificate of the key first
+	var cert *x509.Certificate
+	var chain []*x509.Certificate
+	for _, c := range append([]*x509.Certificate{leaf}, caCerts...) {
+		if cert == nil && bytes.Equal(c.RawSubjectPublicKeyInfo, publicKey) {
+			cert = c
+			continue
+		}
+		chain = append(chain, c)
+	}
+	if cert == nil {
+		return nil, nil, nil, errors.New("no certificate in PKCS#12 bundle matches the private key")
+	}
+
+	return privateKey, cert, chain, nil
+}
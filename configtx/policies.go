@@ -7,8 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -18,6 +21,167 @@ import (
 	"github.com/hyperledger/fabric-config/configtx/internal/policydsl"
 )
 
+// Validate checks that the policy has a known type and a rule which can be
+// parsed for that type.
+func (p Policy) Validate() error {
+	switch p.Type {
+	case ImplicitMetaPolicyType:
+		if _, err := implicitMetaFromString(p.Rule); err != nil {
+			return fmt.Errorf("invalid implicit meta policy rule: '%s': %v", p.Rule, err)
+		}
+	case SignaturePolicyType:
+		if _, err := policydsl.FromString(p.Rule); err != nil {
+			return fmt.Errorf("invalid signature policy rule: '%s': %v", p.Rule, err)
+		}
+	default:
+		return fmt.Errorf("unknown policy type: %s", p.Type)
+	}
+
+	return nil
+}
+
+// ImplicitMetaRuleType is the rule combining the sub-policies of the child
+// groups of an ImplicitMeta policy.
+type ImplicitMetaRuleType string
+
+const (
+	// ImplicitMetaAny is satisfied if any sub-policy is satisfied.
+	ImplicitMetaAny ImplicitMetaRuleType = "ANY"
+
+	// ImplicitMetaAll is satisfied if all sub-policies are satisfied.
+	ImplicitMetaAll ImplicitMetaRuleType = "ALL"
+
+	// ImplicitMetaMajority is satisfied if a strict majority of the
+	// sub-policies is satisfied.
+	ImplicitMetaMajority ImplicitMetaRuleType = "MAJORITY"
+)
+
+// ImplicitMetaRule is the parsed rule of an ImplicitMeta policy, such as
+// "MAJORITY Admins".
+type ImplicitMetaRule struct {
+	Type ImplicitMetaRuleType
+	// SubPolicy is the name of the policy evaluated in every child group.
+	SubPolicy string
+}
+
+// ParseImplicitMetaRule parses an ImplicitMeta policy rule of the form
+// "ANY|ALL|MAJORITY <subPolicyName>".
+func ParseImplicitMetaRule(rule string) (ImplicitMetaRule, error) {
+	args := strings.Split(rule, " ")
+	if len(args) != 2 {
+		return ImplicitMetaRule{}, fmt.Errorf("expected two space separated tokens, but got %d", len(args))
+	}
+
+	ruleType := ImplicitMetaRuleType(args[0])
+	switch ruleType {
+	case ImplicitMetaAny, ImplicitMetaAll, ImplicitMetaMajority:
+	default:
+		return ImplicitMetaRule{}, fmt.Errorf("unknown rule type '%s', expected ALL, ANY, or MAJORITY", args[0])
+	}
+
+	if args[1] == "" {
+		return ImplicitMetaRule{}, errors.New("sub-policy name is required")
+	}
+	if strings.Contains(args[1], "/") {
+		return ImplicitMetaRule{}, fmt.Errorf("sub-policy '%s' must be a policy name, not a path", args[1])
+	}
+
+	return ImplicitMetaRule{
+		Type:      ruleType,
+		SubPolicy: args[1],
+	}, nil
+}
+
+// String returns the rule in the form accepted by ParseImplicitMetaRule.
+func (r ImplicitMetaRule) String() string {
+	return string(r.Type) + " " + r.SubPolicy
+}
+
+// checkSubPolicies checks the sub-policies of the ImplicitMeta policies among
+// policies against the child groups of the config group. The sub-policy must
+// be defined in at least one child group, and for ALL and MAJORITY rules in
+// every child group defining policies, as a child group lacking it counts as
+// unsatisfied. Child groups without any policies are not checked so that
+// groups can be assembled in any order.
+func checkSubPolicies(cg *cb.ConfigGroup, policies map[string]Policy) error {
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		policy := policies[name]
+		if policy.Type != ImplicitMetaPolicyType {
+			continue
+		}
+
+		rule, err := ParseImplicitMetaRule(policy.Rule)
+		if err != nil {
+			// reported when the policy is set
+			continue
+		}
+
+		err = checkSubPolicy(cg, rule)
+		if err != nil {
+			return fmt.Errorf("invalid implicit meta policy rule: '%s': %v", policy.Rule, err)
+		}
+	}
+
+	return nil
+}
+
+// checkSubPolicy checks the sub-policy of an ImplicitMeta rule of the config
+// group as described by checkSubPolicies.
+func checkSubPolicy(cg *cb.ConfigGroup, rule ImplicitMetaRule) error {
+	var checked, defined int
+	for _, name := range sortedGroupKeys(cg) {
+		childGroup := cg.Groups[name]
+		if len(childGroup.Policies) == 0 {
+			continue
+		}
+		checked++
+
+		if _, ok := childGroup.Policies[rule.SubPolicy]; ok {
+			defined++
+			continue
+		}
+		if rule.Type != ImplicitMetaAny {
+			return fmt.Errorf("sub-policy '%s' is not defined in child group %s", rule.SubPolicy, name)
+		}
+	}
+
+	if checked > 0 && defined == 0 {
+		return fmt.Errorf("sub-policy '%s' is not defined in any child group", rule.SubPolicy)
+	}
+
+	return nil
+}
+
+// validatePolicies checks that the standard Admins, Readers and Writers
+// policies as well as any additional required policies are defined, and
+// that every policy is valid.
+func validatePolicies(policyMap map[string]Policy, required ...string) error {
+	if policyMap == nil {
+		return errors.New("no policies defined")
+	}
+
+	required = append([]string{AdminsPolicyKey, ReadersPolicyKey, WritersPolicyKey}, required...)
+	for _, name := range required {
+		if _, ok := policyMap[name]; !ok {
+			return fmt.Errorf("no %s policy defined", name)
+		}
+	}
+
+	for name, policy := range policyMap {
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("policy '%s': %v", name, err)
+		}
+	}
+
+	return nil
+}
+
 // getPolicies returns a map of Policy from given map of ConfigPolicy in organization config group.
 func getPolicies(policies map[string]*cb.ConfigPolicy) (map[string]Policy, error) {
 	p := map[string]Policy{}
@@ -86,6 +250,25 @@ func implicitMetaToString(imp *cb.ImplicitMetaPolicy) (string, error) {
 	return args, nil
 }
 
+// FormatSignaturePolicy renders a signature policy in the policy DSL, such
+// as "OR('Org1MSP.admin', 'Org2MSP.admin')". Principals are rendered as
+// described by FormatMSPPrincipal.
+func FormatSignaturePolicy(policy *cb.SignaturePolicyEnvelope) (string, error) {
+	return signatureMetaToString(policy)
+}
+
+// FormatMSPPrincipal renders an MSP principal as a readable, quoted string.
+// Role principals are rendered as in the policy DSL, such as
+// 'Org1MSP.admin'. Organizational unit principals are rendered as
+// 'Org1MSP.OU(department1)', identity principals with the subject and serial
+// number of their certificate, such as 'Org1MSP.identity(CN=peer0, serial
+// 1234)', anonymity principals as 'anonymous' or 'nominal', and combined
+// principals as COMBINED('Org1MSP.admin', 'Org1MSP.OU(department1)'). Only
+// role principals can be parsed back by the policy DSL.
+func FormatMSPPrincipal(principal *mb.MSPPrincipal) (string, error) {
+	return mspPrincipalToString(principal)
+}
+
 // signatureMetaToString converts a *cb.SignaturePolicyEnvelope to a string representation.
 func signatureMetaToString(sig *cb.SignaturePolicyEnvelope) (string, error) {
 	var roles []string
@@ -122,17 +305,64 @@ func mspPrincipalToString(principal *mb.MSPPrincipal) (string, error) {
 		res.WriteString("'")
 
 		return res.String(), nil
-		// TODO: currently fabric only support string to principle convertion for
-		// type ROLE. Implement MSPPrinciple to String for types ORGANIZATION_UNIT,
-		// IDENTITY, ANONYMITY, and GOMBINED once we have support from fabric.
 	case mb.MSPPrincipal_ORGANIZATION_UNIT:
-		return "", nil
+		ou := &mb.OrganizationUnit{}
+
+		err := proto.Unmarshal(principal.Principal, ou)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("'%s.OU(%s)'", ou.MspIdentifier, ou.OrganizationalUnitIdentifier), nil
 	case mb.MSPPrincipal_IDENTITY:
-		return "", nil
+		identity := &mb.SerializedIdentity{}
+
+		err := proto.Unmarshal(principal.Principal, identity)
+		if err != nil {
+			return "", err
+		}
+
+		// identities which are not X.509 certificates, such as idemix
+		// identities, are rendered without a summary
+		block, _ := pem.Decode(identity.IdBytes)
+		if block == nil {
+			return fmt.Sprintf("'%s.identity'", identity.Mspid), nil
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return "", fmt.Errorf("parsing identity certificate: %v", err)
+		}
+
+		return fmt.Sprintf("'%s.identity(%s, serial %s)'", identity.Mspid, cert.Subject, cert.SerialNumber), nil
 	case mb.MSPPrincipal_ANONYMITY:
-		return "", nil
+		anonymity := &mb.MSPIdentityAnonymity{}
+
+		err := proto.Unmarshal(principal.Principal, anonymity)
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprintf("'%s'", strings.ToLower(anonymity.AnonymityType.String())), nil
 	case mb.MSPPrincipal_COMBINED:
-		return "", nil
+		combined := &mb.CombinedPrincipal{}
+
+		err := proto.Unmarshal(principal.Principal, combined)
+		if err != nil {
+			return "", err
+		}
+
+		var principals []string
+		for _, p := range combined.Principals {
+			rendered, err := mspPrincipalToString(p)
+			if err != nil {
+				return "", err
+			}
+
+			principals = append(principals, rendered)
+		}
+
+		return fmt.Sprintf("COMBINED(%s)", strings.Join(principals, ", ")), nil
 	default:
 		return "", fmt.Errorf("unknown MSP principal classiciation %v", principal.PrincipalClassification)
 	}
@@ -248,22 +478,7 @@ func setPolicy(cg *cb.ConfigGroup, policyName string, policy Policy) error {
 			return fmt.Errorf("invalid signature policy rule: '%s': %v", policy.Rule, err)
 		}
 
-		signaturePolicy, err := proto.Marshal(sp)
-		if err != nil {
-			return fmt.Errorf("marshaling signature policy: %v", err)
-		}
-
-		if policy.ModPolicy == "" {
-			policy.ModPolicy = AdminsPolicyKey
-		}
-
-		cg.Policies[policyName] = &cb.ConfigPolicy{
-			ModPolicy: policy.ModPolicy,
-			Policy: &cb.Policy{
-				Type:  int32(cb.Policy_SIGNATURE),
-				Value: signaturePolicy,
-			},
-		}
+		return setSignaturePolicyEnvelope(cg, policyName, sp, policy.ModPolicy)
 	default:
 		return fmt.Errorf("unknown policy type: %s", policy.Type)
 	}
@@ -271,7 +486,89 @@ func setPolicy(cg *cb.ConfigGroup, policyName string, policy Policy) error {
 	return nil
 }
 
+// setSignaturePolicyEnvelope sets the signature policy of the envelope in the
+// config group. Unlike the policy DSL, the envelope may hold any principal,
+// such as the OU and identity principals built with SignatureRule.
+func setSignaturePolicyEnvelope(cg *cb.ConfigGroup, policyName string, envelope *cb.SignaturePolicyEnvelope, modPolicy string) error {
+	if envelope == nil || envelope.Rule == nil {
+		return errors.New("signature policy has no rule")
+	}
+
+	err := checkSignaturePolicyRule(envelope.Rule, len(envelope.Identities))
+	if err != nil {
+		return err
+	}
+
+	signaturePolicy, err := proto.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("marshaling signature policy: %v", err)
+	}
+
+	if cg.Policies == nil {
+		cg.Policies = make(map[string]*cb.ConfigPolicy)
+	}
+
+	if modPolicy == "" {
+		modPolicy = AdminsPolicyKey
+	}
+
+	cg.Policies[policyName] = &cb.ConfigPolicy{
+		ModPolicy: modPolicy,
+		Policy: &cb.Policy{
+			Type:  int32(cb.Policy_SIGNATURE),
+			Value: signaturePolicy,
+		},
+	}
+
+	return nil
+}
+
 // removePolicy removes an existing policy from an group key organization.
 func removePolicy(configGroup *cb.ConfigGroup, policyName string, policies map[string]Policy) {
 	delete(configGroup.Policies, policyName)
 }
+
+// setDefaultOrgPolicies sets the policies returned by defaults for the MSP of
+// the org group, see applicationOrgPolicies and ordererOrgPolicies. Other
+// policies of the org are left untouched.
+func setDefaultOrgPolicies(orgGroup *cb.ConfigGroup, nodeOUsEnabled bool, defaults func(mspID string, nodeOUsEnabled bool) map[string]Policy) error {
+	msp, err := getMSPConfig(orgGroup)
+	if err != nil {
+		return err
+	}
+
+	if nodeOUsEnabled && !msp.NodeOUs.Enable {
+		return fmt.Errorf("NodeOUs are not enabled in MSP %s", msp.Name)
+	}
+
+	for name, policy := range defaults(msp.Name, nodeOUsEnabled) {
+		err := setPolicy(orgGroup, name, policy)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applicationOrgPolicies returns the Readers, Writers, Admins and Endorsement
+// signature policies configtxgen's sample configuration defines for an
+// application org with the MSP ID, with or without NodeOUs.
+func applicationOrgPolicies(mspID string, nodeOUsEnabled bool) map[string]Policy {
+	if nodeOUsEnabled {
+		return defaultApplicationOrgPolicies(mspID)
+	}
+
+	return memberApplicationOrgPolicies(mspID)
+}
+
+// ordererOrgPolicies returns the Readers, Writers and Admins signature
+// policies configtxgen's sample configuration defines for an orderer org with
+// the MSP ID, with or without NodeOUs.
+func ordererOrgPolicies(mspID string, nodeOUsEnabled bool) map[string]Policy {
+	if nodeOUsEnabled {
+		return nodeOUOrdererOrgPolicies(mspID)
+	}
+
+	return defaultOrdererOrgPolicies(mspID)
+}
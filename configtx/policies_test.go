@@ -7,9 +7,11 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
+	"fmt"
 	"testing"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	"github.com/golang/protobuf/proto"
 	. "github.com/onsi/gomega"
 )
@@ -53,6 +55,71 @@ func TestPolicies(t *testing.T) {
 	gt.Expect(map[string]Policy{}).To(Equal(policies))
 }
 
+func TestFormatSignaturePolicy(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	cert, _ := generateCACertAndPrivateKey(t, "org1.example.com")
+
+	principal := func(classification mb.MSPPrincipal_Classification, msg proto.Message) *mb.MSPPrincipal {
+		b, err := proto.Marshal(msg)
+		gt.Expect(err).NotTo(HaveOccurred())
+		return &mb.MSPPrincipal{PrincipalClassification: classification, Principal: b}
+	}
+
+	role := principal(mb.MSPPrincipal_ROLE, &mb.MSPRole{MspIdentifier: "Org1MSP", Role: mb.MSPRole_ADMIN})
+	ou := principal(mb.MSPPrincipal_ORGANIZATION_UNIT, &mb.OrganizationUnit{MspIdentifier: "Org1MSP", OrganizationalUnitIdentifier: "department1"})
+	identity := principal(mb.MSPPrincipal_IDENTITY, &mb.SerializedIdentity{Mspid: "Org1MSP", IdBytes: pemEncodeX509Certificate(cert)})
+	idemix := principal(mb.MSPPrincipal_IDENTITY, &mb.SerializedIdentity{Mspid: "IdemixMSP", IdBytes: []byte("idemix")})
+	anonymity := principal(mb.MSPPrincipal_ANONYMITY, &mb.MSPIdentityAnonymity{AnonymityType: mb.MSPIdentityAnonymity_ANONYMOUS})
+	combined := principal(mb.MSPPrincipal_COMBINED, &mb.CombinedPrincipal{Principals: []*mb.MSPPrincipal{role, ou}})
+
+	tests := []struct {
+		testName  string
+		principal *mb.MSPPrincipal
+		expected  string
+	}{
+		{testName: "role", principal: role, expected: "'Org1MSP.admin'"},
+		{testName: "organizational unit", principal: ou, expected: "'Org1MSP.OU(department1)'"},
+		{testName: "identity", principal: identity, expected: fmt.Sprintf("'Org1MSP.identity(%s, serial %s)'", cert.Subject, cert.SerialNumber)},
+		{testName: "non X.509 identity", principal: idemix, expected: "'IdemixMSP.identity'"},
+		{testName: "anonymity", principal: anonymity, expected: "'anonymous'"},
+		{testName: "combined", principal: combined, expected: "COMBINED('Org1MSP.admin', 'Org1MSP.OU(department1)')"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			formatted, err := FormatMSPPrincipal(tt.principal)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(formatted).To(Equal(tt.expected))
+		})
+	}
+
+	formatted, err := FormatSignaturePolicy(&cb.SignaturePolicyEnvelope{
+		Rule: &cb.SignaturePolicy{
+			Type: &cb.SignaturePolicy_NOutOf_{
+				NOutOf: &cb.SignaturePolicy_NOutOf{
+					N: 1,
+					Rules: []*cb.SignaturePolicy{
+						{Type: &cb.SignaturePolicy_SignedBy{SignedBy: 0}},
+						{Type: &cb.SignaturePolicy_SignedBy{SignedBy: 1}},
+					},
+				},
+			},
+		},
+		Identities: []*mb.MSPPrincipal{role, ou},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(formatted).To(Equal("OR('Org1MSP.admin', 'Org1MSP.OU(department1)')"))
+
+	_, err = FormatMSPPrincipal(&mb.MSPPrincipal{PrincipalClassification: mb.MSPPrincipal_IDENTITY, Principal: []byte("garbage")})
+	gt.Expect(err).To(HaveOccurred())
+}
+
 func TestSetConsortiumChannelCreationPolicy(t *testing.T) {
 	t.Parallel()
 
@@ -133,3 +200,218 @@ func TestSetConsortiumChannelCreationPolicyFailures(t *testing.T) {
 		})
 	}
 }
+
+func TestPolicyValidate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName string
+		policy   Policy
+		err      string
+	}{
+		{
+			testName: "When the policy is a valid implicit meta policy",
+			policy:   Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Admins"},
+		},
+		{
+			testName: "When the policy is a valid signature policy",
+			policy:   Policy{Type: SignaturePolicyType, Rule: "OR('Org1MSP.member')"},
+		},
+		{
+			testName: "When the implicit meta policy rule is invalid",
+			policy:   Policy{Type: ImplicitMetaPolicyType, Rule: "SOME Admins"},
+			err:      "invalid implicit meta policy rule: 'SOME Admins': unknown rule type 'SOME', expected ALL, ANY, or MAJORITY",
+		},
+		{
+			testName: "When the policy type is unknown",
+			policy:   Policy{Type: "GreenPolicy", Rule: "ANY Admins"},
+			err:      "unknown policy type: GreenPolicy",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			err := tt.policy.Validate()
+			if tt.err == "" {
+				gt.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			gt.Expect(err).To(MatchError(tt.err))
+		})
+	}
+}
+
+func TestParseImplicitMetaRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		rule         string
+		expectedRule ImplicitMetaRule
+		expectedErr  string
+	}{
+		{
+			rule:         "MAJORITY Admins",
+			expectedRule: ImplicitMetaRule{Type: ImplicitMetaMajority, SubPolicy: AdminsPolicyKey},
+		},
+		{
+			rule:         "ANY Readers",
+			expectedRule: ImplicitMetaRule{Type: ImplicitMetaAny, SubPolicy: ReadersPolicyKey},
+		},
+		{
+			rule:         "ALL Endorsement",
+			expectedRule: ImplicitMetaRule{Type: ImplicitMetaAll, SubPolicy: EndorsementPolicyKey},
+		},
+		{
+			rule:        "MAJORTY Admins",
+			expectedErr: "unknown rule type 'MAJORTY', expected ALL, ANY, or MAJORITY",
+		},
+		{
+			rule:        "MAJORITY  Admins",
+			expectedErr: "expected two space separated tokens, but got 3",
+		},
+		{
+			rule:        "MAJORITY ",
+			expectedErr: "sub-policy name is required",
+		},
+		{
+			rule:        "MAJORITY /Channel/Admins",
+			expectedErr: "sub-policy '/Channel/Admins' must be a policy name, not a path",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.rule, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			rule, err := ParseImplicitMetaRule(tt.rule)
+			if tt.expectedErr != "" {
+				gt.Expect(err).To(MatchError(tt.expectedErr))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(rule).To(Equal(tt.expectedRule))
+			gt.Expect(rule.String()).To(Equal(tt.rule))
+		})
+	}
+}
+
+func TestImplicitMetaSubPolicyChecks(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		setPolicy   func(c *ConfigTx) error
+		expectedErr string
+	}{
+		{
+			testName: "When setting a channel policy",
+			setPolicy: func(c *ConfigTx) error {
+				return c.Channel().SetPolicy("Custom", Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Admins"})
+			},
+		},
+		{
+			testName: "When the sub-policy of a channel policy is misspelled",
+			setPolicy: func(c *ConfigTx) error {
+				return c.Channel().SetPolicy(AdminsPolicyKey, Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Admns"})
+			},
+			expectedErr: "invalid implicit meta policy rule: 'MAJORITY Admns': sub-policy 'Admns' is not defined in child group Application",
+		},
+		{
+			testName: "When an ANY sub-policy of an application policy is misspelled",
+			setPolicy: func(c *ConfigTx) error {
+				return c.Application().SetPolicy(ReadersPolicyKey, Policy{Type: ImplicitMetaPolicyType, Rule: "ANY Readrs"})
+			},
+			expectedErr: "failed to set policy 'Readers': invalid implicit meta policy rule: 'ANY Readrs': sub-policy 'Readrs' is not defined in any child group",
+		},
+		{
+			testName: "When an ANY sub-policy is defined by one application org",
+			setPolicy: func(c *ConfigTx) error {
+				err := c.Application().Organization("Org1").SetPolicy("Custom", Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.member')"})
+				if err != nil {
+					return err
+				}
+				return c.Application().SetPolicy("Custom", Policy{Type: ImplicitMetaPolicyType, Rule: "ANY Custom"})
+			},
+		},
+		{
+			testName: "When a MAJORITY sub-policy is missing in an application org",
+			setPolicy: func(c *ConfigTx) error {
+				err := c.Application().Organization("Org1").SetPolicy("Custom", Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.member')"})
+				if err != nil {
+					return err
+				}
+				return c.Application().SetPolicies(map[string]Policy{
+					ReadersPolicyKey: {Type: ImplicitMetaPolicyType, Rule: "ANY Readers"},
+					WritersPolicyKey: {Type: ImplicitMetaPolicyType, Rule: "ANY Writers"},
+					AdminsPolicyKey:  {Type: ImplicitMetaPolicyType, Rule: "MAJORITY Admins"},
+					"Custom":         {Type: ImplicitMetaPolicyType, Rule: "MAJORITY Custom"},
+				})
+			},
+			expectedErr: "failed to set policies: invalid implicit meta policy rule: 'MAJORITY Custom': sub-policy 'Custom' is not defined in child group Org2",
+		},
+		{
+			testName: "When the sub-policy checks are disabled",
+			setPolicy: func(c *ConfigTx) error {
+				return c.Application().WithoutSubPolicyChecks().SetPolicy("Custom", Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Custom"})
+			},
+		},
+		{
+			testName: "When the application orgs do not define policies",
+			setPolicy: func(c *ConfigTx) error {
+				for _, orgGroup := range c.updated.ChannelGroup.Groups[ApplicationGroupKey].Groups {
+					orgGroup.Policies = nil
+				}
+				return c.Application().SetPolicy("Custom", Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Custom"})
+			},
+		},
+		{
+			testName: "When the sub-policy of a signature policy is not checked",
+			setPolicy: func(c *ConfigTx) error {
+				return c.Application().SetPolicy("Custom", Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.member')"})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseApplicationChannelGroup(t)
+			gt.Expect(err).NotTo(HaveOccurred())
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			err = tt.setPolicy(&c)
+			if tt.expectedErr != "" {
+				gt.Expect(err).To(MatchError(tt.expectedErr))
+				return
+			}
+			gt.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestChannelCreationPolicySubPolicyCheck(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseConsortiumChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	err = c.Consortium("Consortium1").SetChannelCreationPolicy(Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Admns"})
+	gt.Expect(err).To(MatchError("invalid implicit meta policy rule 'MAJORITY Admns': sub-policy 'Admns' is not defined in child group Org1"))
+
+	err = c.Consortium("Consortium1").WithoutSubPolicyChecks().SetChannelCreationPolicy(Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Admns"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = c.Consortium("Consortium1").SetChannelCreationPolicy(Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Admins"})
+	gt.Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,229 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+)
+
+// PolicyReferenceKind is the kind of config element referencing a policy.
+type PolicyReferenceKind string
+
+const (
+	// ImplicitMetaReference is the reference of an ImplicitMeta policy to the
+	// sub-policy of the same name in each child group.
+	ImplicitMetaReference PolicyReferenceKind = "implicit-meta"
+
+	// ModPolicyReference is the reference of a group, value or policy to its
+	// mod policy.
+	ModPolicyReference PolicyReferenceKind = "mod-policy"
+
+	// ACLReference is the reference of an application ACL to its policy.
+	ACLReference PolicyReferenceKind = "acl"
+)
+
+// PolicyReference is an edge of the policy graph.
+type PolicyReference struct {
+	Kind PolicyReferenceKind
+	// From is the path of the referencing ImplicitMeta policy, group, value
+	// or policy, or the API resource of the referencing ACL.
+	From string
+	// To is the path of the referenced policy, such as
+	// /Channel/Application/Org1/Readers.
+	To string
+}
+
+// PolicyGraph is the graph of the references between the policies of a
+// channel config.
+type PolicyGraph struct {
+	// Policies are the paths of every policy defined in the config.
+	Policies []string
+	// References are the references which resolve to a defined policy.
+	References []PolicyReference
+	// Unresolved are the references to a policy which is not defined.
+	Unresolved []PolicyReference
+	// Orphaned are the paths of the policies which are defined but never
+	// referenced by another element of the config, except for the policies
+	// Fabric evaluates by their path.
+	Orphaned []string
+}
+
+// PolicyGraph returns the graph of the references between the policies of
+// the updated config: ImplicitMeta policies to the sub-policies of the child
+// groups, mod policies and application ACLs.
+func (c *ConfigTx) PolicyGraph() (PolicyGraph, error) {
+	return newPolicyGraph(c.updated.ChannelGroup)
+}
+
+// implicitlyReferencedPolicies are the policies Fabric evaluates by their
+// path rather than through a reference in the config.
+var implicitlyReferencedPolicies = map[string]bool{
+	"/" + ChannelGroupKey + "/" + ReadersPolicyKey:                                          true,
+	"/" + ChannelGroupKey + "/" + WritersPolicyKey:                                          true,
+	"/" + ChannelGroupKey + "/" + AdminsPolicyKey:                                           true,
+	"/" + ChannelGroupKey + "/" + OrdererGroupKey + "/" + BlockValidationPolicyKey:          true,
+	"/" + ChannelGroupKey + "/" + ApplicationGroupKey + "/" + EndorsementPolicyKey:          true,
+	"/" + ChannelGroupKey + "/" + ApplicationGroupKey + "/" + LifecycleEndorsementPolicyKey: true,
+}
+
+// newPolicyGraph builds the policy graph of the channel group.
+func newPolicyGraph(channelGroup *cb.ConfigGroup) (PolicyGraph, error) {
+	var references []PolicyReference
+	defined := map[string]bool{}
+
+	err := walkPolicyReferences("/"+ChannelGroupKey, channelGroup, defined, &references)
+	if err != nil {
+		return PolicyGraph{}, err
+	}
+
+	if applicationGroup, ok := channelGroup.Groups[ApplicationGroupKey]; ok {
+		acls, err := (&ApplicationGroup{applicationGroup: applicationGroup}).ACLs()
+		if err != nil {
+			return PolicyGraph{}, err
+		}
+
+		apiResources := make([]string, 0, len(acls))
+		for apiResource := range acls {
+			apiResources = append(apiResources, apiResource)
+		}
+		sort.Strings(apiResources)
+
+		for _, apiResource := range apiResources {
+			references = append(references, PolicyReference{
+				Kind: ACLReference,
+				From: apiResource,
+				To:   policyPath("/"+ChannelGroupKey+"/"+ApplicationGroupKey, acls[apiResource]),
+			})
+		}
+	}
+
+	graph := PolicyGraph{}
+	referenced := map[string]bool{}
+	for _, reference := range references {
+		if !defined[reference.To] {
+			graph.Unresolved = append(graph.Unresolved, reference)
+			continue
+		}
+
+		graph.References = append(graph.References, reference)
+		// a policy being its own mod policy does not keep it in use
+		if reference.From != reference.To {
+			referenced[reference.To] = true
+		}
+	}
+
+	for path := range defined {
+		graph.Policies = append(graph.Policies, path)
+		if !referenced[path] && !implicitlyReferencedPolicies[path] {
+			graph.Orphaned = append(graph.Orphaned, path)
+		}
+	}
+	sort.Strings(graph.Policies)
+	sort.Strings(graph.Orphaned)
+
+	return graph, nil
+}
+
+// walkPolicyReferences records the policies of the group at path and of its
+// child groups in defined, and appends the references of their ImplicitMeta
+// policies and mod policies to references.
+func walkPolicyReferences(path string, group *cb.ConfigGroup, defined map[string]bool, references *[]PolicyReference) error {
+	if group.ModPolicy != "" {
+		*references = append(*references, PolicyReference{
+			Kind: ModPolicyReference,
+			From: path,
+			To:   policyPath(path, group.ModPolicy),
+		})
+	}
+
+	for _, valueName := range sortedValueKeys(group) {
+		if modPolicy := group.Values[valueName].ModPolicy; modPolicy != "" {
+			*references = append(*references, PolicyReference{
+				Kind: ModPolicyReference,
+				From: fmt.Sprintf("%s/Values/%s", path, valueName),
+				To:   policyPath(path, modPolicy),
+			})
+		}
+	}
+
+	for _, key := range sortedPolicyKeys(group) {
+		configPolicy := group.Policies[key]
+		policyName := path + "/" + key
+		defined[policyName] = true
+
+		if configPolicy.ModPolicy != "" {
+			*references = append(*references, PolicyReference{
+				Kind: ModPolicyReference,
+				From: policyName,
+				To:   policyPath(path, configPolicy.ModPolicy),
+			})
+		}
+
+		if configPolicy.Policy == nil || cb.Policy_PolicyType(configPolicy.Policy.Type) != cb.Policy_IMPLICIT_META {
+			continue
+		}
+
+		imp := &cb.ImplicitMetaPolicy{}
+		err := proto.Unmarshal(configPolicy.Policy.Value, imp)
+		if err != nil {
+			return fmt.Errorf("unmarshaling implicit meta policy %s: %v", policyName, err)
+		}
+
+		for _, childName := range sortedGroupKeys(group) {
+			*references = append(*references, PolicyReference{
+				Kind: ImplicitMetaReference,
+				From: policyName,
+				To:   fmt.Sprintf("%s/%s/%s", path, childName, imp.SubPolicy),
+			})
+		}
+	}
+
+	for _, childName := range sortedGroupKeys(group) {
+		err := walkPolicyReferences(path+"/"+childName, group.Groups[childName], defined, references)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// policyPath resolves the policy reference relative to the group at path.
+func policyPath(path, policyRef string) string {
+	if strings.HasPrefix(policyRef, "/") {
+		return policyRef
+	}
+
+	return path + "/" + policyRef
+}
+
+// sortedValueKeys returns the names of the values of the group in order.
+func sortedValueKeys(group *cb.ConfigGroup) []string {
+	keys := make([]string, 0, len(group.Values))
+	for key := range group.Values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// sortedPolicyKeys returns the names of the policies of the group in order.
+func sortedPolicyKeys(group *cb.ConfigGroup) []string {
+	keys := make([]string, 0, len(group.Policies))
+	for key := range group.Policies {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
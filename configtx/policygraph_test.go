@@ -0,0 +1,87 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	. "github.com/onsi/gomega"
+)
+
+func TestPolicyGraph(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	graph, err := c.PolicyGraph()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(graph.Policies).To(HaveLen(13))
+	gt.Expect(graph.References).To(ContainElements(
+		PolicyReference{
+			Kind: ImplicitMetaReference,
+			From: "/Channel/Application/Readers",
+			To:   "/Channel/Application/Org1/Readers",
+		},
+		PolicyReference{
+			Kind: ModPolicyReference,
+			From: "/Channel/Application/Org2",
+			To:   "/Channel/Application/Org2/Admins",
+		},
+		PolicyReference{
+			Kind: ModPolicyReference,
+			From: "/Channel/Application/Values/ACLs",
+			To:   "/Channel/Application/Admins",
+		},
+	))
+	gt.Expect(graph.Unresolved).To(Equal([]PolicyReference{
+		{
+			Kind: ACLReference,
+			From: "acl1",
+			To:   "/Channel/Application/hi",
+		},
+	}))
+	gt.Expect(graph.Orphaned).To(Equal([]string{
+		"/Channel/Application/Org1/Endorsement",
+		"/Channel/Application/Org1/LifecycleEndorsement",
+		"/Channel/Application/Org2/Endorsement",
+		"/Channel/Application/Org2/LifecycleEndorsement",
+		"/Channel/Application/Readers",
+		"/Channel/Application/Writers",
+	}))
+
+	err = c.Channel().SetPolicies(standardPolicies())
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().SetACLs(map[string]string{"acl1": "/Channel/Application/Org1/Endorsement"})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	graph, err = c.PolicyGraph()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(graph.Policies).To(HaveLen(16))
+	gt.Expect(graph.Unresolved).To(BeEmpty())
+	gt.Expect(graph.Orphaned).To(Equal([]string{
+		"/Channel/Application/Org1/LifecycleEndorsement",
+		"/Channel/Application/Org2/Endorsement",
+		"/Channel/Application/Org2/LifecycleEndorsement",
+	}))
+}
+
+func TestPolicyGraphFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	channelGroup.Groups[ApplicationGroupKey].Policies[ReadersPolicyKey].Policy.Value = []byte("another little fire")
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	_, err = c.PolicyGraph()
+	gt.Expect(err).To(MatchError("unmarshaling implicit meta policy /Channel/Application/Readers: unexpected EOF"))
+}
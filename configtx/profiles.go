@@ -0,0 +1,165 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// FabricVersion identifies a Fabric release targeted by a channel profile.
+type FabricVersion string
+
+const (
+	// FabricV2_2 targets Fabric v2.2.
+	FabricV2_2 FabricVersion = "2.2"
+
+	// FabricV2_5 targets Fabric v2.5.
+	FabricV2_5 FabricVersion = "2.5"
+
+	// FabricV3_0 targets Fabric v3.0.
+	FabricV3_0 FabricVersion = "3.0"
+)
+
+// CapabilitySet is the set of capabilities of the channel, orderer and
+// application groups.
+type CapabilitySet struct {
+	Channel     []string
+	Orderer     []string
+	Application []string
+}
+
+// CapabilitiesFor returns the capabilities enabled by the sample configtx.yaml
+// of the given Fabric version.
+func CapabilitiesFor(version FabricVersion) (CapabilitySet, error) {
+	switch version {
+	case FabricV2_2:
+		return CapabilitySet{
+			Channel:     []string{"V2_0"},
+			Orderer:     []string{"V2_0"},
+			Application: []string{"V2_0"},
+		}, nil
+	case FabricV2_5:
+		return CapabilitySet{
+			Channel:     []string{"V2_0"},
+			Orderer:     []string{"V2_0"},
+			Application: []string{"V2_5"},
+		}, nil
+	case FabricV3_0:
+		return CapabilitySet{
+			Channel:     []string{"V3_0"},
+			Orderer:     []string{"V2_0"},
+			Application: []string{"V2_5"},
+		}, nil
+	default:
+		return CapabilitySet{}, fmt.Errorf("unknown fabric version '%s'", version)
+	}
+}
+
+// DefaultACLs returns the application ACLs of the sample configtx.yaml.
+func DefaultACLs() map[string]string {
+	return map[string]string{
+		"_lifecycle/CheckCommitReadiness":      "/Channel/Application/Writers",
+		"_lifecycle/CommitChaincodeDefinition": "/Channel/Application/Writers",
+		"_lifecycle/QueryChaincodeDefinition":  "/Channel/Application/Writers",
+		"_lifecycle/QueryChaincodeDefinitions": "/Channel/Application/Writers",
+		"lscc/ChaincodeExists":                 "/Channel/Application/Readers",
+		"lscc/GetDeploymentSpec":               "/Channel/Application/Readers",
+		"lscc/GetChaincodeData":                "/Channel/Application/Readers",
+		"lscc/GetInstantiatedChaincodes":       "/Channel/Application/Readers",
+		"qscc/GetChainInfo":                    "/Channel/Application/Readers",
+		"qscc/GetBlockByNumber":                "/Channel/Application/Readers",
+		"qscc/GetBlockByHash":                  "/Channel/Application/Readers",
+		"qscc/GetTransactionByID":              "/Channel/Application/Readers",
+		"qscc/GetBlockByTxID":                  "/Channel/Application/Readers",
+		"cscc/GetConfigBlock":                  "/Channel/Application/Readers",
+		"cscc/GetChannelConfig":                "/Channel/Application/Readers",
+		"peer/Propose":                         "/Channel/Application/Writers",
+		"peer/ChaincodeToChaincode":            "/Channel/Application/Writers",
+		"event/Block":                          "/Channel/Application/Readers",
+		"event/FilteredBlock":                  "/Channel/Application/Readers",
+		"snapshot/submitrequest":               "/Channel/Application/Admins",
+		"snapshot/cancelrequest":               "/Channel/Application/Admins",
+		"snapshot/listpending":                 "/Channel/Application/Admins",
+	}
+}
+
+// DefaultEtcdRaftOptions returns the etcdraft options of the sample
+// configtx.yaml.
+func DefaultEtcdRaftOptions() orderer.EtcdRaftOptions {
+	return orderer.EtcdRaftOptions{
+		TickInterval:         "500ms",
+		ElectionTick:         10,
+		HeartbeatTick:        1,
+		MaxInflightBlocks:    5,
+		SnapshotIntervalSize: 16 * 1024 * 1024,
+	}
+}
+
+// DefaultSmartBFTOptions returns the SmartBFT options of the sample
+// configtx.yaml.
+func DefaultSmartBFTOptions() orderer.SmartBFTOptions {
+	return orderer.SmartBFTOptions{
+		RequestBatchMaxCount:      100,
+		RequestBatchMaxBytes:      10 * 1024 * 1024,
+		RequestBatchMaxInterval:   "50ms",
+		IncomingMessageBufferSize: 200,
+		RequestPoolSize:           100000,
+		RequestForwardTimeout:     "2s",
+		RequestComplainTimeout:    "20s",
+		RequestAutoRemoveTimeout:  "3m0s",
+		ViewChangeResendInterval:  "5s",
+		ViewChangeTimeout:         "20s",
+		LeaderHeartbeatTimeout:    "1m0s",
+		LeaderHeartbeatCount:      10,
+		CollectTimeout:            "1s",
+		LeaderRotation:            orderer.SmartBFTLeaderRotationUnspecified,
+	}
+}
+
+// DefaultEtcdRaftAppChannel returns an application channel ordered by the
+// given etcdraft consenters, matching the ChannelUsingRaft profile of the
+// sample configtx.yaml for the given Fabric version.
+func DefaultEtcdRaftAppChannel(version FabricVersion, consenters []orderer.Consenter, ordererOrgs, applicationOrgs []Organization) (Channel, error) {
+	b := defaultAppChannelBuilder(version, ordererOrgs, applicationOrgs).
+		WithEtcdRaft(orderer.EtcdRaft{
+			Consenters: consenters,
+			Options:    DefaultEtcdRaftOptions(),
+		})
+
+	return b.buildChannel()
+}
+
+// DefaultSmartBFTAppChannel returns an application channel ordered by the
+// given SmartBFT consenters, matching the ChannelUsingBFT profile of the
+// sample configtx.yaml for the given Fabric version.
+func DefaultSmartBFTAppChannel(version FabricVersion, consenters []orderer.SmartBFTConsenter, ordererOrgs, applicationOrgs []Organization) (Channel, error) {
+	b := defaultAppChannelBuilder(version, ordererOrgs, applicationOrgs).
+		WithSmartBFT(orderer.SmartBFT{
+			Consenters: consenters,
+			Options:    DefaultSmartBFTOptions(),
+		})
+
+	return b.buildChannel()
+}
+
+func defaultAppChannelBuilder(version FabricVersion, ordererOrgs, applicationOrgs []Organization) *ChannelBuilder {
+	b := NewChannelBuilder("").
+		WithFabricVersion(version).
+		WithACLs(DefaultACLs())
+
+	for _, org := range ordererOrgs {
+		b.WithOrdererOrg(org)
+	}
+
+	for _, org := range applicationOrgs {
+		b.WithApplicationOrg(org)
+	}
+
+	return b
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestCapabilitiesFor(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	capabilities, err := CapabilitiesFor(FabricV2_2)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(capabilities).To(Equal(CapabilitySet{
+		Channel:     []string{"V2_0"},
+		Orderer:     []string{"V2_0"},
+		Application: []string{"V2_0"},
+	}))
+
+	capabilities, err = CapabilitiesFor(FabricV3_0)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(capabilities.Channel).To(Equal([]string{"V3_0"}))
+	gt.Expect(capabilities.Application).To(Equal([]string{"V2_5"}))
+
+	_, err = CapabilitiesFor("1.4")
+	gt.Expect(err).To(MatchError("unknown fabric version '1.4'"))
+}
+
+func TestDefaultEtcdRaftAppChannel(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	application, _ := baseApplication(t)
+	distinctMSPIDs(application.Organizations, "")
+
+	channel, err := DefaultEtcdRaftAppChannel(FabricV2_5, etcdRaftOrderer.EtcdRaft.Consenters, etcdRaftOrderer.Organizations, application.Organizations)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channel.Orderer.OrdererType).To(Equal(orderer.ConsensusTypeEtcdRaft))
+	gt.Expect(channel.Orderer.EtcdRaft.Options).To(Equal(DefaultEtcdRaftOptions()))
+	gt.Expect(channel.Application.Capabilities).To(Equal([]string{"V2_5"}))
+	gt.Expect(channel.Application.ACLs).To(Equal(DefaultACLs()))
+	gt.Expect(channel.Application.Organizations).To(HaveLen(2))
+
+	_, err = NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestDefaultSmartBFTAppChannel(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+	application, _ := baseApplication(t)
+	distinctMSPIDs(application.Organizations, "")
+
+	channel, err := DefaultSmartBFTAppChannel(FabricV3_0, smartBFTOrderer.SmartBFT.Consenters, smartBFTOrderer.Organizations, application.Organizations)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channel.Capabilities).To(Equal([]string{"V3_0"}))
+	gt.Expect(channel.Orderer.OrdererType).To(Equal(orderer.ConsensusTypeSmartBFT))
+	gt.Expect(channel.Orderer.SmartBFT.Options).To(Equal(DefaultSmartBFTOptions()))
+
+	_, err = NewApplicationChannelGenesisBlock(channel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = DefaultSmartBFTAppChannel("1.4", smartBFTOrderer.SmartBFT.Consenters, smartBFTOrderer.Organizations, application.Organizations)
+	gt.Expect(err).To(MatchError("unknown fabric version '1.4'"))
+
+	_, err = DefaultSmartBFTAppChannel(FabricV3_0, nil, smartBFTOrderer.Organizations, application.Organizations)
+	gt.Expect(err).To(MatchError("invalid orderer: invalid smartbft configuration: consenters are required"))
+}
@@ -0,0 +1,278 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// EndpointState is the outcome of checking the reachability of an endpoint.
+type EndpointState string
+
+const (
+	// EndpointReachable indicates that the endpoint accepted a connection
+	// and, when TLS was checked, presented the expected certificate.
+	EndpointReachable EndpointState = "reachable"
+
+	// EndpointUnreachable indicates that no connection could be established.
+	EndpointUnreachable EndpointState = "unreachable"
+
+	// EndpointTLSMismatch indicates that the endpoint accepted a connection
+	// but presented a TLS certificate which is not the expected one.
+	EndpointTLSMismatch EndpointState = "tls-mismatch"
+)
+
+// EndpointStatus reports the reachability of an endpoint.
+type EndpointStatus struct {
+	// Address is the host:port of the endpoint.
+	Address string
+	// Org is the name of the organization owning the endpoint, if known.
+	Org   string
+	State EndpointState
+	// Err describes why the endpoint is not reachable.
+	Err error
+}
+
+// CheckEndpoint dials the endpoint at address within the timeout. If TLS root
+// certificates are given, a TLS handshake is performed and the certificate
+// presented by the endpoint must chain to one of them. Intermediate
+// certificates are used to build the chain.
+func CheckEndpoint(address string, tlsRootCerts, tlsIntermediateCerts []*x509.Certificate, timeout time.Duration) EndpointStatus {
+	if len(tlsRootCerts) == 0 {
+		return dialEndpoint(address, nil, timeout)
+	}
+
+	return dialEndpoint(address, verifyChain(tlsRootCerts, tlsIntermediateCerts, false), timeout)
+}
+
+// CheckConsenter performs a TLS handshake with the etcdraft consenter and
+// verifies that it presents its configured server TLS certificate.
+func CheckConsenter(consenter orderer.Consenter, timeout time.Duration) EndpointStatus {
+	address := net.JoinHostPort(consenter.Address.Host, strconv.Itoa(consenter.Address.Port))
+	return dialEndpoint(address, expectCertificate(consenter.ServerTLSCert), timeout)
+}
+
+// CheckSmartBFTConsenter performs a TLS handshake with the SmartBFT consenter
+// and verifies that it presents its configured server TLS certificate.
+func CheckSmartBFTConsenter(consenter orderer.SmartBFTConsenter, timeout time.Duration) EndpointStatus {
+	address := net.JoinHostPort(consenter.Address.Host, strconv.Itoa(consenter.Address.Port))
+	status := dialEndpoint(address, expectCertificate(consenter.ServerTLSCert), timeout)
+	status.Org = consenter.MSPID
+	return status
+}
+
+// CheckReachability checks the orderer endpoints of every orderer organization
+// against the TLS CA certificates of its MSP, and every consenter against its
+// server TLS certificate. This dials every endpoint and should only be used
+// when the ordering service is reachable from the caller.
+func (o Orderer) CheckReachability(timeout time.Duration) []EndpointStatus {
+	var statuses []EndpointStatus
+
+	for _, org := range o.Organizations {
+		for _, endpoint := range org.OrdererEndpoints {
+			status := CheckEndpoint(endpoint, org.MSP.TLSRootCerts, org.MSP.TLSIntermediateCerts, timeout)
+			status.Org = org.Name
+			statuses = append(statuses, status)
+		}
+	}
+
+	switch o.OrdererType {
+	case orderer.ConsensusTypeEtcdRaft:
+		for _, consenter := range o.EtcdRaft.Consenters {
+			statuses = append(statuses, CheckConsenter(consenter, timeout))
+		}
+	case orderer.ConsensusTypeSmartBFT:
+		for _, consenter := range o.SmartBFT.Consenters {
+			statuses = append(statuses, CheckSmartBFTConsenter(consenter, timeout))
+		}
+	}
+
+	return statuses
+}
+
+// ValidateReachability returns an error listing every orderer endpoint and
+// consenter which is unreachable or presents an unexpected TLS certificate.
+func (o Orderer) ValidateReachability(timeout time.Duration) error {
+	var failures []string
+	for _, status := range o.CheckReachability(timeout) {
+		if status.State != EndpointReachable {
+			failures = append(failures, fmt.Sprintf("%s (%s): %v", status.Address, status.State, status.Err))
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("unreachable orderer endpoints: %s", strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// reachabilityChecks holds the checks of WithReachabilityChecks.
+type reachabilityChecks struct {
+	enabled bool
+	timeout time.Duration
+}
+
+// WithReachabilityChecks returns a copy of the orderer group whose
+// AddConsenter and AddSmartBFTConsenter dial a consenter they add, within the
+// timeout, and check that it presents its configured server TLS certificate,
+// see CheckConsenter. The SetEndpoint of the orderer orgs retrieved from the
+// copy likewise dials an endpoint it adds and checks it against the TLS CA
+// certificates of the org MSP, see CheckEndpoint. A consenter or endpoint
+// which cannot be reached is not added. This dials the ordering service and
+// should only be used when it is reachable from the caller.
+func (o *OrdererGroup) WithReachabilityChecks(timeout time.Duration) *OrdererGroup {
+	checked := *o
+	checked.reachability = reachabilityChecks{enabled: true, timeout: timeout}
+
+	return &checked
+}
+
+// checkConsenterReachability dials the etcdraft consenter if reachability
+// checks are enabled.
+func (o *OrdererGroup) checkConsenterReachability(consenter orderer.Consenter) error {
+	if !o.reachability.enabled {
+		return nil
+	}
+
+	status := CheckConsenter(consenter, o.reachability.timeout)
+	return reachabilityError("consenter "+status.Address, status)
+}
+
+// checkSmartBFTConsenterReachability dials the SmartBFT consenter if
+// reachability checks are enabled.
+func (o *OrdererGroup) checkSmartBFTConsenterReachability(consenter orderer.SmartBFTConsenter) error {
+	if !o.reachability.enabled {
+		return nil
+	}
+
+	status := CheckSmartBFTConsenter(consenter, o.reachability.timeout)
+	return reachabilityError(fmt.Sprintf("consenter %d at %s", consenter.ID, status.Address), status)
+}
+
+// checkEndpointReachability dials the normalized endpoint if reachability
+// checks are enabled. Endpoints already dialed by WithEndpointTLSProbe are
+// not dialed again.
+func (o *OrdererOrg) checkEndpointReachability(endpoint Address) error {
+	if !o.reachability.enabled || o.endpointChecks.probe {
+		return nil
+	}
+
+	address := hostPort(endpoint.Host, endpoint.Port)
+	msp, err := o.MSP().Configuration()
+	if err != nil {
+		return fmt.Errorf("checking endpoint %s of orderer org %s: %v", address, o.name, err)
+	}
+
+	status := CheckEndpoint(address, msp.TLSRootCerts, msp.TLSIntermediateCerts, o.reachability.timeout)
+
+	return reachabilityError(fmt.Sprintf("endpoint %s of orderer org %s", address, o.name), status)
+}
+
+// reachabilityError returns an error describing the status of the named
+// endpoint unless it is reachable.
+func reachabilityError(name string, status EndpointStatus) error {
+	if status.State == EndpointReachable {
+		return nil
+	}
+
+	return fmt.Errorf("%s is %s: %v", name, status.State, status.Err)
+}
+
+// expectCertificate returns a verification function which checks that the
+// presented certificate is the expected one.
+func expectCertificate(expected *x509.Certificate) func(*x509.Certificate) error {
+	return func(leaf *x509.Certificate) error {
+		if expected == nil {
+			return errors.New("no server tls cert is configured")
+		}
+		if !bytes.Equal(leaf.Raw, expected.Raw) {
+			return fmt.Errorf("presented certificate with serial number %d does not match configured certificate with serial number %d", leaf.SerialNumber, expected.SerialNumber)
+		}
+		return nil
+	}
+}
+
+// dialEndpoint connects to the address. If verify is not nil, a TLS handshake
+// is performed and verify is called with the certificate presented by the
+// endpoint. A handshake which fails after the certificate was verified, for
+// example because the endpoint requires a client certificate, still counts as
+// reachable.
+func dialEndpoint(address string, verify func(*x509.Certificate) error, timeout time.Duration) EndpointStatus {
+	status := EndpointStatus{Address: address}
+
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		status.State = EndpointUnreachable
+		status.Err = fmt.Errorf("invalid address: %v", err)
+		return status
+	}
+
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.Dial("tcp", address)
+	if err != nil {
+		status.State = EndpointUnreachable
+		status.Err = err
+		return status
+	}
+	defer conn.Close()
+
+	if verify == nil {
+		status.State = EndpointReachable
+		return status
+	}
+
+	var verifyErr error
+	verified := false
+	tlsConn := tls.Client(conn, &tls.Config{
+		// the presented certificate is verified by verify below
+		InsecureSkipVerify: true,
+		VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+			if len(rawCerts) == 0 {
+				verifyErr = errors.New("no certificate presented")
+				return verifyErr
+			}
+			leaf, err := x509.ParseCertificate(rawCerts[0])
+			if err != nil {
+				verifyErr = fmt.Errorf("parsing presented certificate: %v", err)
+				return verifyErr
+			}
+			verifyErr = verify(leaf)
+			verified = verifyErr == nil
+			return verifyErr
+		},
+	})
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		status.State = EndpointUnreachable
+		status.Err = err
+		return status
+	}
+
+	err = tlsConn.Handshake()
+	switch {
+	case verified:
+		status.State = EndpointReachable
+	case verifyErr != nil:
+		status.State = EndpointTLSMismatch
+		status.Err = verifyErr
+	default:
+		status.State = EndpointTLSMismatch
+		status.Err = fmt.Errorf("tls handshake: %v", err)
+	}
+
+	return status
+}
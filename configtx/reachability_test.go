@@ -0,0 +1,291 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/ecdsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestCheckEndpoint(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	otherCACert, _ := generateCACertAndPrivateKey(t, "other-org")
+	serverCert, serverPrivKey := generateTLSServerCert(t, caCert, caPrivKey)
+	address := startTLSServer(t, serverCert, serverPrivKey)
+	closedAddress := closedEndpoint(t)
+
+	tests := []struct {
+		testName      string
+		address       string
+		rootCerts     []*x509.Certificate
+		expectedState EndpointState
+	}{
+		{
+			testName:      "when the certificate chains to the root",
+			address:       address,
+			rootCerts:     []*x509.Certificate{caCert},
+			expectedState: EndpointReachable,
+		},
+		{
+			testName:      "when no roots are given",
+			address:       address,
+			expectedState: EndpointReachable,
+		},
+		{
+			testName:      "when the certificate does not chain to the root",
+			address:       address,
+			rootCerts:     []*x509.Certificate{otherCACert},
+			expectedState: EndpointTLSMismatch,
+		},
+		{
+			testName:      "when nothing is listening",
+			address:       closedAddress,
+			rootCerts:     []*x509.Certificate{caCert},
+			expectedState: EndpointUnreachable,
+		},
+		{
+			testName:      "when the address is invalid",
+			address:       "127.0.0.1",
+			expectedState: EndpointUnreachable,
+		},
+	}
+
+	for _, tc := range tests {
+		status := CheckEndpoint(tc.address, tc.rootCerts, nil, time.Second)
+		gt.Expect(status.Address).To(Equal(tc.address), tc.testName)
+		gt.Expect(status.State).To(Equal(tc.expectedState), tc.testName)
+		if tc.expectedState == EndpointReachable {
+			gt.Expect(status.Err).NotTo(HaveOccurred(), tc.testName)
+		} else {
+			gt.Expect(status.Err).To(HaveOccurred(), tc.testName)
+		}
+	}
+}
+
+func TestCheckReachability(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "orderer-org")
+	serverCert, serverPrivKey := generateTLSServerCert(t, caCert, caPrivKey)
+	otherCert, _ := generateTLSServerCert(t, caCert, caPrivKey)
+	address := startTLSServer(t, serverCert, serverPrivKey)
+	host, portStr, err := net.SplitHostPort(address)
+	gt.Expect(err).NotTo(HaveOccurred())
+	port, err := strconv.Atoi(portStr)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+	etcdRaftOrderer.Organizations[0].MSP.TLSRootCerts = []*x509.Certificate{caCert}
+	etcdRaftOrderer.Organizations[0].OrdererEndpoints = []string{address}
+	etcdRaftOrderer.EtcdRaft.Consenters = []orderer.Consenter{
+		{
+			Address:       orderer.EtcdAddress{Host: host, Port: port},
+			ClientTLSCert: serverCert,
+			ServerTLSCert: serverCert,
+		},
+		{
+			Address:       orderer.EtcdAddress{Host: host, Port: port},
+			ClientTLSCert: otherCert,
+			ServerTLSCert: otherCert,
+		},
+	}
+
+	statuses := etcdRaftOrderer.CheckReachability(time.Second)
+	gt.Expect(statuses).To(HaveLen(3))
+	gt.Expect(statuses[0].Org).To(Equal(etcdRaftOrderer.Organizations[0].Name))
+	gt.Expect(statuses[0].State).To(Equal(EndpointReachable))
+	gt.Expect(statuses[1].State).To(Equal(EndpointReachable))
+	gt.Expect(statuses[2].State).To(Equal(EndpointTLSMismatch))
+	gt.Expect(statuses[2].Err).To(MatchError(ContainSubstring("does not match configured certificate")))
+
+	err = etcdRaftOrderer.ValidateReachability(time.Second)
+	gt.Expect(err).To(MatchError(ContainSubstring("unreachable orderer endpoints: " + address + " (tls-mismatch)")))
+
+	etcdRaftOrderer.EtcdRaft.Consenters = etcdRaftOrderer.EtcdRaft.Consenters[:1]
+	err = etcdRaftOrderer.ValidateReachability(time.Second)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	smartBFTOrderer, _ := baseSmartBFTOrderer(t)
+	smartBFTOrderer.Organizations[0].OrdererEndpoints = nil
+	smartBFTOrderer.SmartBFT.Consenters = smartBFTOrderer.SmartBFT.Consenters[:1]
+	smartBFTOrderer.SmartBFT.Consenters[0].Address = orderer.EtcdAddress{Host: host, Port: port}
+	smartBFTOrderer.SmartBFT.Consenters[0].ServerTLSCert = serverCert
+
+	statuses = smartBFTOrderer.CheckReachability(time.Second)
+	gt.Expect(statuses).To(Equal([]EndpointStatus{
+		{
+			Address: address,
+			Org:     "MSPID",
+			State:   EndpointReachable,
+		},
+	}))
+}
+
+func TestReachabilityChecks(t *testing.T) {
+	t.Parallel()
+
+	// etcdAddress splits a host:port address into an etcdraft address.
+	etcdAddress := func(gt *GomegaWithT, address string) orderer.EtcdAddress {
+		host, portStr, err := net.SplitHostPort(address)
+		gt.Expect(err).NotTo(HaveOccurred())
+		port, err := strconv.Atoi(portStr)
+		gt.Expect(err).NotTo(HaveOccurred())
+		return orderer.EtcdAddress{Host: host, Port: port}
+	}
+
+	t.Run("etcdraft consenters and endpoints", func(t *testing.T) {
+		t.Parallel()
+		gt := NewGomegaWithT(t)
+
+		channelGroup, privKeys, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeEtcdRaft)
+		gt.Expect(err).NotTo(HaveOccurred())
+		c := New(&cb.Config{ChannelGroup: channelGroup})
+
+		msp, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+		gt.Expect(err).NotTo(HaveOccurred())
+		serverCert, serverPrivKey := generateTLSServerCert(t, msp.TLSRootCerts[0], privKeys[0])
+		openAddress := etcdAddress(gt, startTLSServer(t, serverCert, serverPrivKey))
+		closedAddress := etcdAddress(gt, closedEndpoint(t))
+
+		consenterAt := func(address orderer.EtcdAddress) orderer.Consenter {
+			return orderer.Consenter{
+				Address:       address,
+				ClientTLSCert: serverCert,
+				ServerTLSCert: serverCert,
+			}
+		}
+
+		checked := c.Orderer().WithReachabilityChecks(time.Second)
+		err = checked.AddConsenter(consenterAt(openAddress))
+		gt.Expect(err).NotTo(HaveOccurred())
+		err = checked.AddConsenter(consenterAt(closedAddress))
+		gt.Expect(err).To(MatchError(HavePrefix("consenter " + consenterAddress(closedAddress) + " is unreachable: ")))
+
+		err = checked.Organization("OrdererOrg").SetEndpoint(Address{Host: openAddress.Host, Port: openAddress.Port})
+		gt.Expect(err).NotTo(HaveOccurred())
+		err = checked.Organization("OrdererOrg").SetEndpoint(Address{Host: closedAddress.Host, Port: closedAddress.Port})
+		gt.Expect(err).To(MatchError(HavePrefix("endpoint " + consenterAddress(closedAddress) + " of orderer org OrdererOrg is unreachable: ")))
+
+		ordererConfig, err := c.Orderer().Configuration()
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(ordererConfig.EtcdRaft.Consenters).To(ContainElement(consenterAt(openAddress)))
+		gt.Expect(ordererConfig.EtcdRaft.Consenters).NotTo(ContainElement(consenterAt(closedAddress)))
+		gt.Expect(ordererConfig.Organizations[0].OrdererEndpoints).To(ContainElement(consenterAddress(openAddress)))
+		gt.Expect(ordererConfig.Organizations[0].OrdererEndpoints).NotTo(ContainElement(consenterAddress(closedAddress)))
+
+		// the checks are opt-in
+		err = c.Orderer().AddConsenter(consenterAt(closedAddress))
+		gt.Expect(err).NotTo(HaveOccurred())
+		err = c.Orderer().Organization("OrdererOrg").SetEndpoint(Address{Host: closedAddress.Host, Port: closedAddress.Port})
+		gt.Expect(err).NotTo(HaveOccurred())
+	})
+
+	t.Run("SmartBFT consenters", func(t *testing.T) {
+		t.Parallel()
+		gt := NewGomegaWithT(t)
+
+		channelGroup, privKeys, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSmartBFT)
+		gt.Expect(err).NotTo(HaveOccurred())
+		c := New(&cb.Config{ChannelGroup: channelGroup})
+
+		ordererConfig, err := c.Orderer().Configuration()
+		gt.Expect(err).NotTo(HaveOccurred())
+		serverCert, serverPrivKey := generateTLSServerCert(t, ordererConfig.Organizations[0].MSP.TLSRootCerts[0], privKeys[0])
+		openAddress := etcdAddress(gt, startTLSServer(t, serverCert, serverPrivKey))
+		closedAddress := etcdAddress(gt, closedEndpoint(t))
+
+		consenter := ordererConfig.SmartBFT.Consenters[0]
+		consenter.ServerTLSCert = serverCert
+
+		checked := c.Orderer().WithReachabilityChecks(time.Second)
+		consenter.ID = 10
+		consenter.Address = closedAddress
+		err = checked.AddSmartBFTConsenter(consenter)
+		gt.Expect(err).To(MatchError(HavePrefix("consenter 10 at " + consenterAddress(closedAddress) + " is unreachable: ")))
+
+		consenter.ID = 11
+		consenter.Address = openAddress
+		err = checked.AddSmartBFTConsenter(consenter)
+		gt.Expect(err).NotTo(HaveOccurred())
+	})
+}
+
+// generateTLSServerCert returns a TLS server certificate for 127.0.0.1 signed
+// by the given CA.
+func generateTLSServerCert(t *testing.T, caCert *x509.Certificate, caPrivKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	template := &x509.Certificate{
+		SerialNumber: generateSerialNumber(t),
+		Subject: pkix.Name{
+			CommonName:   "orderer.orderer-org",
+			Organization: []string{"orderer-org"},
+		},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:   time.Now(),
+		NotAfter:    time.Now().Add(365 * 24 * time.Hour),
+		KeyUsage:    x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	return generateCertAndPrivateKey(t, template, caCert, caPrivKey)
+}
+
+// startTLSServer starts a TLS listener presenting the given certificate and
+// returns its address.
+func startTLSServer(t *testing.T, cert *x509.Certificate, privKey *ecdsa.PrivateKey) string {
+	gt := NewGomegaWithT(t)
+
+	listener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{
+		Certificates: []tls.Certificate{
+			{
+				Certificate: [][]byte{cert.Raw},
+				PrivateKey:  privKey,
+			},
+		},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				_ = conn.(*tls.Conn).Handshake()
+			}()
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+// closedEndpoint returns the address of a port nothing listens on.
+func closedEndpoint(t *testing.T) string {
+	gt := NewGomegaWithT(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	gt.Expect(err).NotTo(HaveOccurred())
+	address := listener.Addr().String()
+	listener.Close()
+
+	return address
+}
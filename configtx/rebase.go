@@ -0,0 +1,189 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+)
+
+// RebaseMarshaledUpdate recomputes a config update against a newer config, so
+// that an update which became stale while its signatures were collected does
+// not have to be prepared again. originalBytes is the marshaled Config the
+// update was computed against, marshaledUpdate the marshaled ConfigUpdate and
+// latestBytes the marshaled newer Config. The changes of the update are
+// re-applied to the newer config and the marshaled ConfigUpdate of the result
+// is returned. The rebased update must be signed again.
+//
+// A change of the update conflicts with the newer config if the newer config
+// changed the same element differently, removed an element the update
+// modifies or modified a group the update removes. The update is not rebased
+// if any of its changes conflict, and the error names the conflicting
+// elements.
+func RebaseMarshaledUpdate(originalBytes, marshaledUpdate, latestBytes []byte, channelID string) ([]byte, error) {
+	original := &cb.Config{}
+	err := proto.Unmarshal(originalBytes, original)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling original config: %v", err)
+	}
+
+	latest := &cb.Config{}
+	err = proto.Unmarshal(latestBytes, latest)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling latest config: %v", err)
+	}
+
+	return rebaseMarshaledUpdate(original, marshaledUpdate, latest, channelID)
+}
+
+// RebaseMarshaledUpdateFromConfigBlocks is like RebaseMarshaledUpdate, but
+// takes the configs of the marshaled original and latest config blocks.
+func RebaseMarshaledUpdateFromConfigBlocks(originalBlockBytes, marshaledUpdate, latestBlockBytes []byte, channelID string) ([]byte, error) {
+	original, err := unmarshalConfigFromBlockBytes(originalBlockBytes)
+	if err != nil {
+		return nil, fmt.Errorf("original config block: %v", err)
+	}
+
+	latest, err := unmarshalConfigFromBlockBytes(latestBlockBytes)
+	if err != nil {
+		return nil, fmt.Errorf("latest config block: %v", err)
+	}
+
+	return rebaseMarshaledUpdate(original, marshaledUpdate, latest, channelID)
+}
+
+// rebaseMarshaledUpdate applies the update to the original config, re-applies
+// the resulting changes to the latest config and computes the update from the
+// latest config to the result.
+func rebaseMarshaledUpdate(original *cb.Config, marshaledUpdate []byte, latest *cb.Config, channelID string) ([]byte, error) {
+	update := &cb.ConfigUpdate{}
+	err := proto.Unmarshal(marshaledUpdate, update)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config update: %v", err)
+	}
+
+	intended, err := applyConfigUpdate(original, update)
+	if err != nil {
+		return nil, fmt.Errorf("applying config update: %v", err)
+	}
+
+	if latest.ChannelGroup == nil {
+		return nil, errors.New("no channel group included for latest config")
+	}
+
+	rebased := proto.Clone(latest).(*cb.Config)
+	var conflicts []string
+	rebaseGroup("/"+ChannelGroupKey, original.ChannelGroup, intended.ChannelGroup, rebased.ChannelGroup, &conflicts)
+	if len(conflicts) != 0 {
+		return nil, fmt.Errorf("config update conflicts with the latest config at %s", strings.Join(conflicts, ", "))
+	}
+
+	return computeMarshaledUpdate(latest, rebased, channelID)
+}
+
+// rebaseGroup re-applies the changes between the original and intended group
+// at path to the latest group, and appends the paths of the changes which
+// conflict with the changes between the original and latest group to
+// conflicts.
+func rebaseGroup(path string, original, intended, latest *cb.ConfigGroup, conflicts *[]string) {
+	if original.ModPolicy != intended.ModPolicy {
+		if latest.ModPolicy != original.ModPolicy && latest.ModPolicy != intended.ModPolicy {
+			*conflicts = append(*conflicts, path)
+		} else {
+			latest.ModPolicy = intended.ModPolicy
+		}
+	}
+
+	for _, key := range sortedMemberKeys(original, intended, sortedValueKeys) {
+		o, i, l := original.Values[key], intended.Values[key], latest.Values[key]
+		switch {
+		case equalValues(o, i):
+			continue
+		case !equalValues(o, l) && !equalValues(i, l):
+			*conflicts = append(*conflicts, path+"/Values/"+key)
+		case i == nil:
+			delete(latest.Values, key)
+		default:
+			if latest.Values == nil {
+				latest.Values = map[string]*cb.ConfigValue{}
+			}
+			latest.Values[key] = proto.Clone(i).(*cb.ConfigValue)
+		}
+	}
+
+	for _, key := range sortedMemberKeys(original, intended, sortedPolicyKeys) {
+		o, i, l := original.Policies[key], intended.Policies[key], latest.Policies[key]
+		switch {
+		case equalPolicies(o, i):
+			continue
+		case !equalPolicies(o, l) && !equalPolicies(i, l):
+			*conflicts = append(*conflicts, path+"/Policies/"+key)
+		case i == nil:
+			delete(latest.Policies, key)
+		default:
+			if latest.Policies == nil {
+				latest.Policies = map[string]*cb.ConfigPolicy{}
+			}
+			latest.Policies[key] = proto.Clone(i).(*cb.ConfigPolicy)
+		}
+	}
+
+	for _, key := range sortedMemberKeys(original, intended, sortedGroupKeys) {
+		groupPath := path + "/" + key
+		o, i, l := original.Groups[key], intended.Groups[key], latest.Groups[key]
+		switch {
+		case equalGroups(groupPath, o, i):
+			continue
+		case o != nil && i != nil && l != nil:
+			rebaseGroup(groupPath, o, i, l, conflicts)
+		case !equalGroups(groupPath, o, l) && !equalGroups(groupPath, i, l):
+			*conflicts = append(*conflicts, groupPath)
+		case i == nil:
+			delete(latest.Groups, key)
+		default:
+			if latest.Groups == nil {
+				latest.Groups = map[string]*cb.ConfigGroup{}
+			}
+			latest.Groups[key] = proto.Clone(i).(*cb.ConfigGroup)
+		}
+	}
+}
+
+// equalValues reports whether the values have the same content and mod
+// policy, ignoring their versions. A nil value equals only a nil value.
+func equalValues(a, b *cb.ConfigValue) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return bytes.Equal(a.Value, b.Value) && a.ModPolicy == b.ModPolicy
+}
+
+// equalPolicies reports whether the policies have the same content and mod
+// policy, ignoring their versions. A nil policy equals only a nil policy.
+func equalPolicies(a, b *cb.ConfigPolicy) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return proto.Equal(a.Policy, b.Policy) && a.ModPolicy == b.ModPolicy
+}
+
+// equalGroups reports whether the groups at path have the same members and
+// mod policies, ignoring their versions. A nil group equals only a nil group.
+func equalGroups(path string, a, b *cb.ConfigGroup) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return len(diffConfigGroup(path, a, b)) == 0
+}
@@ -0,0 +1,156 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestRebaseMarshaledUpdate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	original := c.OriginalConfig()
+	customPolicy := Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.admin')", ModPolicy: AdminsPolicyKey}
+	pending := staleUpdate(t, original, SetBatchTimeout{Timeout: 5 * time.Second})
+	latestBlock := staleConfigBlock(t, original, SetPolicy{Group: "Application/Org1", Name: "Custom", Policy: customPolicy})
+
+	originalBlock := staleConfigBlock(t, original)
+	rebased, err := RebaseMarshaledUpdateFromConfigBlocks(originalBlock, pending, latestBlock, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	latest, err := unmarshalConfigFromBlockBytes(latestBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+	stale, err := ComputeStaleElements(rebased, latestBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(stale).To(BeEmpty())
+
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(rebased, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(update.ChannelId).To(Equal("testchannel"))
+	updated, err := applyConfigUpdate(latest, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c = New(updated)
+	ordererConf, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConf.BatchTimeout).To(Equal(5 * time.Second))
+	policies, err := c.Application().Organization("Org1").Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(HaveKey("Custom"))
+
+	originalBytes, err := proto.Marshal(original)
+	gt.Expect(err).NotTo(HaveOccurred())
+	latestBytes, err := proto.Marshal(latest)
+	gt.Expect(err).NotTo(HaveOccurred())
+	fromConfigs, err := RebaseMarshaledUpdate(originalBytes, pending, latestBytes, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(fromConfigs).To(Equal(rebased))
+}
+
+func TestRebaseMarshaledUpdateConflicts(t *testing.T) {
+	t.Parallel()
+
+	c := applyConfigTx(t)
+	original := c.OriginalConfig()
+	customPolicy := Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.admin')", ModPolicy: AdminsPolicyKey}
+
+	tests := []struct {
+		testName    string
+		pending     []Change
+		committed   []Change
+		expectedErr string
+	}{
+		{
+			testName:    "When a modified value was modified differently",
+			pending:     []Change{SetBatchTimeout{Timeout: 5 * time.Second}},
+			committed:   []Change{SetBatchTimeout{Timeout: 3 * time.Second}},
+			expectedErr: "config update conflicts with the latest config at /Channel/Orderer/Values/BatchTimeout",
+		},
+		{
+			testName:    "When a modified group was removed",
+			pending:     []Change{SetPolicy{Group: "Application/Org2", Name: "Custom", Policy: customPolicy}},
+			committed:   []Change{RemoveApplicationOrg{Name: "Org2"}},
+			expectedErr: "config update conflicts with the latest config at /Channel/Application/Org2",
+		},
+		{
+			testName:    "When a removed group was modified",
+			pending:     []Change{RemoveApplicationOrg{Name: "Org2"}},
+			committed:   []Change{SetPolicy{Group: "Application/Org2", Name: "Custom", Policy: customPolicy}},
+			expectedErr: "config update conflicts with the latest config at /Channel/Application/Org2",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := RebaseMarshaledUpdateFromConfigBlocks(
+				staleConfigBlock(t, original),
+				staleUpdate(t, original, tt.pending...),
+				staleConfigBlock(t, original, tt.committed...),
+				"testchannel",
+			)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
+
+func TestRebaseMarshaledUpdateIdenticalChange(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	original := c.OriginalConfig()
+	change := SetBatchTimeout{Timeout: 5 * time.Second}
+
+	_, err := RebaseMarshaledUpdateFromConfigBlocks(
+		staleConfigBlock(t, original),
+		staleUpdate(t, original, change),
+		staleConfigBlock(t, original, change),
+		"testchannel",
+	)
+	gt.Expect(err).To(MatchError("failed to compute update: no differences detected between original and updated config"))
+}
+
+func TestRebaseMarshaledUpdateFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	original := c.OriginalConfig()
+	block := staleConfigBlock(t, original)
+	pending := staleUpdate(t, original, SetBatchTimeout{Timeout: 5 * time.Second})
+
+	_, err := RebaseMarshaledUpdate([]byte("another little fire"), pending, []byte{}, "testchannel")
+	gt.Expect(err).To(MatchError(ContainSubstring("unmarshaling original config: ")))
+
+	_, err = RebaseMarshaledUpdateFromConfigBlocks(block, []byte("another little fire"), block, "testchannel")
+	gt.Expect(err).To(MatchError(ContainSubstring("unmarshaling config update: ")))
+
+	_, err = RebaseMarshaledUpdateFromConfigBlocks([]byte("another little fire"), pending, block, "testchannel")
+	gt.Expect(err).To(MatchError(ContainSubstring("original config block: ")))
+
+	_, err = RebaseMarshaledUpdateFromConfigBlocks(block, pending, []byte("another little fire"), "testchannel")
+	gt.Expect(err).To(MatchError(ContainSubstring("latest config block: ")))
+
+	emptyConfig, err := proto.Marshal(&cb.Config{})
+	gt.Expect(err).NotTo(HaveOccurred())
+	originalBytes, err := proto.Marshal(original)
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = RebaseMarshaledUpdate(originalBytes, pending, emptyConfig, "testchannel")
+	gt.Expect(err).To(MatchError("no channel group included for latest config"))
+}
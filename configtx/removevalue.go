@@ -0,0 +1,89 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// RemoveValue removes the config value with the key from the group at the
+// path of the updated config, so that obsolete or experimental values can be
+// cleaned out of long-lived channels. The path is empty for the channel
+// group, Application or Orderer for those groups, or Application/<org> or
+// Orderer/<org> for an org. Values Fabric requires, such as the MSP of an org
+// or the ConsensusType of the orderer, are not removed, see ForceRemoveValue.
+func (c *ConfigTx) RemoveValue(path, key string) (err error) {
+	defer c.observe("ConfigTx.RemoveValue", time.Now(), &err)
+
+	return c.removeValue(path, key, false)
+}
+
+// ForceRemoveValue removes the config value with the key from the group at
+// the path of the updated config like RemoveValue, including the values
+// Fabric requires. The resulting config is rejected by the orderers unless
+// the value is set again.
+func (c *ConfigTx) ForceRemoveValue(path, key string) (err error) {
+	defer c.observe("ConfigTx.ForceRemoveValue", time.Now(), &err)
+
+	return c.removeValue(path, key, true)
+}
+
+func (c *ConfigTx) removeValue(path, key string, force bool) error {
+	groupKey, orgName, err := splitGroupPath(path)
+	if err != nil {
+		return err
+	}
+
+	group := c.updated.ChannelGroup
+	groupName := ChannelGroupKey
+	if groupKey != "" {
+		if err := c.requireGroup(groupKey); err != nil {
+			return err
+		}
+		group = group.Groups[groupKey]
+		groupName = path
+	}
+	if orgName != "" {
+		org, ok := group.Groups[orgName]
+		if !ok {
+			return fmt.Errorf("%s org %s does not exist", strings.ToLower(groupKey), orgName)
+		}
+		group = org
+	}
+
+	if _, ok := group.Values[key]; !ok {
+		return fmt.Errorf("value %s does not exist in group %s", key, groupName)
+	}
+
+	if !force && requiredValue(groupKey, orgName, key) {
+		return fmt.Errorf("value %s is required in group %s", key, groupName)
+	}
+
+	delete(group.Values, key)
+
+	return nil
+}
+
+// requiredValue reports whether Fabric requires the value with the key in the
+// channel group, the application or orderer group with the given key or an
+// org within it.
+func requiredValue(groupKey, orgName, key string) bool {
+	switch {
+	case orgName != "":
+		return key == MSPKey
+	case groupKey == OrdererGroupKey:
+		return key == orderer.ConsensusTypeKey || key == orderer.BatchSizeKey || key == orderer.BatchTimeoutKey
+	case groupKey == "":
+		return key == HashingAlgorithmKey || key == BlockDataHashingStructureKey
+	default:
+		return false
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestRemoveValue(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(channelGroup, capabilitiesValue([]string{"V2_0"}), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(channelGroup.Groups[OrdererGroupKey].Groups["OrdererOrg"], &standardConfigValue{key: "Experimental", value: &cb.Capabilities{}}, AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	err = c.RemoveValue("", CapabilitiesKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.updated.ChannelGroup.Values).NotTo(HaveKey(CapabilitiesKey))
+
+	err = c.RemoveValue("Orderer", orderer.ChannelRestrictionsKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.updated.ChannelGroup.Groups[OrdererGroupKey].Values).NotTo(HaveKey(orderer.ChannelRestrictionsKey))
+
+	err = c.RemoveValue("Orderer/OrdererOrg", "Experimental")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(c.updated.ChannelGroup.Groups[OrdererGroupKey].Groups["OrdererOrg"].Values).NotTo(HaveKey("Experimental"))
+	gt.Expect(c.original.ChannelGroup.Groups[OrdererGroupKey].Groups["OrdererOrg"].Values).To(HaveKey("Experimental"))
+}
+
+func TestRemoveValueRequired(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{path: "Orderer", key: orderer.ConsensusTypeKey},
+		{path: "Orderer", key: orderer.BatchSizeKey},
+		{path: "Orderer", key: orderer.BatchTimeoutKey},
+		{path: "Orderer/OrdererOrg", key: MSPKey},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.path+"/"+tt.key, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+			gt.Expect(err).NotTo(HaveOccurred())
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			err = c.RemoveValue(tt.path, tt.key)
+			gt.Expect(err).To(MatchError("value " + tt.key + " is required in group " + tt.path))
+
+			err = c.ForceRemoveValue(tt.path, tt.key)
+			gt.Expect(err).NotTo(HaveOccurred())
+			_, err = c.ComputeMarshaledUpdate("testchannel")
+			gt.Expect(err).NotTo(HaveOccurred())
+		})
+	}
+}
+
+func TestRemoveValueFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		path        string
+		key         string
+		expectedErr string
+	}{
+		{
+			testName:    "when the path is invalid",
+			path:        "Consortiums",
+			key:         MSPKey,
+			expectedErr: "invalid group 'Consortiums'",
+		},
+		{
+			testName:    "when the group does not exist",
+			path:        "Application",
+			key:         ACLsKey,
+			expectedErr: "application group does not exist",
+		},
+		{
+			testName:    "when the org does not exist",
+			path:        "Orderer/UnknownOrg",
+			key:         MSPKey,
+			expectedErr: "orderer org UnknownOrg does not exist",
+		},
+		{
+			testName:    "when the value does not exist in the channel group",
+			path:        "",
+			key:         "Unknown",
+			expectedErr: "value Unknown does not exist in group Channel",
+		},
+		{
+			testName:    "when the value does not exist in an org",
+			path:        "Orderer/OrdererOrg",
+			key:         AnchorPeersKey,
+			expectedErr: "value AnchorPeers does not exist in group Orderer/OrdererOrg",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			channelGroup, _, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+			gt.Expect(err).NotTo(HaveOccurred())
+			c := New(&cb.Config{ChannelGroup: channelGroup})
+
+			err = c.RemoveValue(tt.path, tt.key)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+			err = c.ForceRemoveValue(tt.path, tt.key)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
+
+func TestRequiredValue(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	gt.Expect(requiredValue("", "", HashingAlgorithmKey)).To(BeTrue())
+	gt.Expect(requiredValue("", "", BlockDataHashingStructureKey)).To(BeTrue())
+	gt.Expect(requiredValue("", "", OrdererAddressesKey)).To(BeFalse())
+	gt.Expect(requiredValue(ApplicationGroupKey, "", ACLsKey)).To(BeFalse())
+	gt.Expect(requiredValue(ApplicationGroupKey, "Org1", MSPKey)).To(BeTrue())
+	gt.Expect(requiredValue(ApplicationGroupKey, "Org1", AnchorPeersKey)).To(BeFalse())
+}
@@ -0,0 +1,54 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"time"
+)
+
+// CRLStatus describes the freshness of a CRL in the revocation list of an
+// org MSP.
+type CRLStatus struct {
+	Org string
+	// Path identifies the CRL within the config, e.g.
+	// /Channel/Application/Org1/Values/MSP/RevocationList[0].
+	Path       string
+	Issuer     string
+	ThisUpdate time.Time
+	NextUpdate time.Time
+	// Stale is true if the CRL is past its NextUpdate time, meaning the
+	// issuer should have published a newer CRL by now.
+	Stale bool
+}
+
+// CRLReport returns the freshness of every CRL configured in the MSPs of the
+// application, orderer and consortium orgs of the updated config.
+func (c *ConfigTx) CRLReport() ([]CRLStatus, error) {
+	var report []CRLStatus
+	now := time.Now()
+
+	err := walkOrgMSPs(c.updated.ChannelGroup, func(orgName, path string, msp *MSP) bool {
+		for i, crl := range msp.RevocationList {
+			tbs := crl.TBSCertList
+			report = append(report, CRLStatus{
+				Org:        orgName,
+				Path:       fmt.Sprintf("%s/RevocationList[%d]", path, i),
+				Issuer:     tbs.Issuer.String(),
+				ThisUpdate: tbs.ThisUpdate,
+				NextUpdate: tbs.NextUpdate,
+				Stale:      !tbs.NextUpdate.IsZero() && now.After(tbs.NextUpdate),
+			})
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
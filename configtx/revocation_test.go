@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestCRLReport(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, privKeys, err := baseOrdererChannelGroup(t, orderer.ConsensusTypeSolo)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	ordererMSP, err := c.Orderer().Organization("OrdererOrg").MSP().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	caCert := ordererMSP.RootCerts[0]
+
+	thisUpdate := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+	nextUpdate := thisUpdate.Add(time.Hour)
+	crlBytes, err := caCert.CreateCRL(rand.Reader, privKeys[0], nil, thisUpdate, nextUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+	staleCRL, err := x509.ParseCRL(crlBytes)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	ordererMSP.RevocationList = append(ordererMSP.RevocationList, staleCRL)
+	err = c.Orderer().Organization("OrdererOrg").SetMSP(ordererMSP)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	report, err := c.CRLReport()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(report).To(HaveLen(2))
+
+	gt.Expect(report[0].Org).To(Equal("OrdererOrg"))
+	gt.Expect(report[0].Path).To(Equal("/Channel/Orderer/OrdererOrg/Values/MSP/RevocationList[0]"))
+	gt.Expect(report[0].Issuer).To(Equal(caCert.Subject.String()))
+	gt.Expect(report[0].Stale).To(BeFalse())
+
+	gt.Expect(report[1]).To(Equal(CRLStatus{
+		Org:        "OrdererOrg",
+		Path:       "/Channel/Orderer/OrdererOrg/Values/MSP/RevocationList[1]",
+		Issuer:     caCert.Subject.String(),
+		ThisUpdate: thisUpdate,
+		NextUpdate: nextUpdate,
+		Stale:      true,
+	}))
+}
+
+func TestCRLReportFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	c.updated.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Values[MSPKey].Value = []byte("garbage")
+
+	_, err = c.CRLReport()
+	gt.Expect(err).To(MatchError(ContainSubstring("retrieving msp of org Org1")))
+}
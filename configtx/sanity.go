@@ -0,0 +1,215 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+)
+
+// SanityFindingKind is the kind of problem found by SanityCheck.
+type SanityFindingKind string
+
+const (
+	// PolicyFinding is a policy which cannot be parsed, or an ImplicitMeta
+	// policy whose sub-policy is missing in a child group.
+	PolicyFinding SanityFindingKind = "policy"
+
+	// ModPolicyFinding is a mod policy of a group, value or policy which
+	// does not refer to a defined policy.
+	ModPolicyFinding SanityFindingKind = "mod-policy"
+
+	// MSPFinding is an MSP which cannot be parsed or is invalid.
+	MSPFinding SanityFindingKind = "msp"
+)
+
+// SanityFinding is a problem found by SanityCheck.
+type SanityFinding struct {
+	Kind SanityFindingKind
+	// Path is the path of the config element with the problem, such as
+	// /Channel/Application/Org1/Admins for a policy or
+	// /Channel/Application/Org1/Values/MSP for an MSP.
+	Path    string
+	Message string
+}
+
+// String returns the path and message of the finding.
+func (f SanityFinding) String() string {
+	return fmt.Sprintf("%s: %s", f.Path, f.Message)
+}
+
+// SanityCheck checks that a config would be accepted by Fabric, as the sanity
+// check of configtxlator does: every policy can be parsed, every mod policy
+// and ImplicitMeta sub-policy refers to a defined policy and every MSP can be
+// parsed and is valid. It returns every problem found, in path order, so the
+// result can be used to gate config artifacts. An error is returned only if
+// the config has no channel group.
+func SanityCheck(config *cb.Config) ([]SanityFinding, error) {
+	if config == nil || config.ChannelGroup == nil {
+		return nil, errors.New("config has no channel group")
+	}
+
+	var findings []SanityFinding
+	sanityCheckGroup("/"+ChannelGroupKey, config.ChannelGroup, &findings)
+
+	graph, err := newPolicyGraph(config.ChannelGroup)
+	if err != nil {
+		findings = append(findings, SanityFinding{
+			Kind:    PolicyFinding,
+			Path:    "/" + ChannelGroupKey,
+			Message: fmt.Sprintf("resolving policy references: %v", err),
+		})
+		return findings, nil
+	}
+
+	for _, reference := range graph.Unresolved {
+		switch reference.Kind {
+		case ModPolicyReference:
+			findings = append(findings, SanityFinding{
+				Kind:    ModPolicyFinding,
+				Path:    reference.From,
+				Message: fmt.Sprintf("mod policy %s is not defined", reference.To),
+			})
+		case ImplicitMetaReference:
+			findings = append(findings, SanityFinding{
+				Kind:    PolicyFinding,
+				Path:    reference.From,
+				Message: fmt.Sprintf("sub-policy %s is not defined", reference.To),
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// sanityCheckGroup appends the problems of the policies and MSP of the group
+// at path and of its child groups to findings.
+func sanityCheckGroup(path string, group *cb.ConfigGroup, findings *[]SanityFinding) {
+	for _, key := range sortedPolicyKeys(group) {
+		err := checkConfigPolicy(group.Policies[key])
+		if err != nil {
+			*findings = append(*findings, SanityFinding{
+				Kind:    PolicyFinding,
+				Path:    path + "/" + key,
+				Message: err.Error(),
+			})
+		}
+	}
+
+	if _, ok := group.Values[MSPKey]; ok {
+		err := checkMSP(group)
+		if err != nil {
+			*findings = append(*findings, SanityFinding{
+				Kind:    MSPFinding,
+				Path:    fmt.Sprintf("%s/Values/%s", path, MSPKey),
+				Message: err.Error(),
+			})
+		}
+	}
+
+	for _, childName := range sortedGroupKeys(group) {
+		sanityCheckGroup(path+"/"+childName, group.Groups[childName], findings)
+	}
+}
+
+// checkConfigPolicy checks that the policy can be parsed.
+func checkConfigPolicy(configPolicy *cb.ConfigPolicy) error {
+	if configPolicy.Policy == nil {
+		return errors.New("policy is missing")
+	}
+
+	switch cb.Policy_PolicyType(configPolicy.Policy.Type) {
+	case cb.Policy_IMPLICIT_META:
+		imp := &cb.ImplicitMetaPolicy{}
+		err := proto.Unmarshal(configPolicy.Policy.Value, imp)
+		if err != nil {
+			return fmt.Errorf("unmarshaling implicit meta policy: %v", err)
+		}
+
+		if _, err := implicitMetaToString(imp); err != nil {
+			return err
+		}
+
+		if imp.SubPolicy == "" {
+			return errors.New("implicit meta policy has no sub-policy")
+		}
+	case cb.Policy_SIGNATURE:
+		sp := &cb.SignaturePolicyEnvelope{}
+		err := proto.Unmarshal(configPolicy.Policy.Value, sp)
+		if err != nil {
+			return fmt.Errorf("unmarshaling signature policy: %v", err)
+		}
+
+		if sp.Rule == nil {
+			return errors.New("signature policy has no rule")
+		}
+
+		for _, identity := range sp.Identities {
+			if _, err := mspPrincipalToString(identity); err != nil {
+				return fmt.Errorf("parsing signature policy principal: %v", err)
+			}
+		}
+
+		return checkSignaturePolicyRule(sp.Rule, len(sp.Identities))
+	default:
+		return fmt.Errorf("unknown policy type: %v", configPolicy.Policy.Type)
+	}
+
+	return nil
+}
+
+// checkSignaturePolicyRule checks that the rule only refers to the identities
+// of its envelope and that its thresholds can be met.
+func checkSignaturePolicyRule(rule *cb.SignaturePolicy, identities int) error {
+	switch t := rule.Type.(type) {
+	case *cb.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= identities {
+			return fmt.Errorf("signature policy refers to identity %d, but has %d identities", t.SignedBy, identities)
+		}
+	case *cb.SignaturePolicy_NOutOf_:
+		if t.NOutOf.N < 0 || int(t.NOutOf.N) > len(t.NOutOf.Rules) {
+			return fmt.Errorf("signature policy requires %d out of %d rules", t.NOutOf.N, len(t.NOutOf.Rules))
+		}
+
+		for _, subRule := range t.NOutOf.Rules {
+			if err := checkSignaturePolicyRule(subRule, identities); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unknown signature policy type %v", rule.Type)
+	}
+
+	return nil
+}
+
+// checkMSP checks that the MSP of the group can be parsed and is valid. MSPs
+// which are not X.509 based, such as idemix MSPs, are only checked to be
+// parseable.
+func checkMSP(group *cb.ConfigGroup) error {
+	mspConfig := &mb.MSPConfig{}
+	err := unmarshalConfigValueAtKey(group, MSPKey, mspConfig)
+	if err != nil {
+		return err
+	}
+
+	// 0 is the FABRIC MSP type
+	if mspConfig.Type != 0 {
+		return nil
+	}
+
+	msp, err := getMSPConfig(group)
+	if err != nil {
+		return err
+	}
+
+	return msp.Validate()
+}
@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func sanityConfig(t *testing.T) *cb.Config {
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	err = c.Channel().SetPolicies(standardPolicies())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return c.UpdatedConfig()
+}
+
+func TestSanityCheck(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	findings, err := SanityCheck(sanityConfig(t))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(findings).To(BeEmpty())
+
+	_, err = SanityCheck(&cb.Config{})
+	gt.Expect(err).To(MatchError("config has no channel group"))
+}
+
+func TestSanityCheckFindings(t *testing.T) {
+	t.Parallel()
+
+	signaturePolicy := func(t *testing.T, envelope *cb.SignaturePolicyEnvelope) *cb.Policy {
+		value, err := proto.Marshal(envelope)
+		NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+		return &cb.Policy{Type: int32(cb.Policy_SIGNATURE), Value: value}
+	}
+
+	tests := []struct {
+		testName        string
+		configMod       func(*testing.T, *cb.Config)
+		expectedKind    SanityFindingKind
+		expectedPath    string
+		expectedMessage string
+	}{
+		{
+			testName: "When a signature policy cannot be unmarshaled",
+			configMod: func(t *testing.T, config *cb.Config) {
+				policy := config.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Policies[EndorsementPolicyKey]
+				policy.Policy = &cb.Policy{Type: int32(cb.Policy_SIGNATURE), Value: []byte("another little fire")}
+			},
+			expectedKind:    PolicyFinding,
+			expectedPath:    "/Channel/Application/Org1/Endorsement",
+			expectedMessage: "unmarshaling signature policy: ",
+		},
+		{
+			testName: "When a signature policy refers to a missing identity",
+			configMod: func(t *testing.T, config *cb.Config) {
+				policy := config.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Policies[EndorsementPolicyKey]
+				policy.Policy = signaturePolicy(t, &cb.SignaturePolicyEnvelope{
+					Rule: &cb.SignaturePolicy{Type: &cb.SignaturePolicy_SignedBy{SignedBy: 1}},
+				})
+			},
+			expectedKind:    PolicyFinding,
+			expectedPath:    "/Channel/Application/Org1/Endorsement",
+			expectedMessage: "signature policy refers to identity 1, but has 0 identities",
+		},
+		{
+			testName: "When a policy has an unknown type",
+			configMod: func(t *testing.T, config *cb.Config) {
+				policy := config.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Policies[EndorsementPolicyKey]
+				policy.Policy = &cb.Policy{Type: int32(cb.Policy_MSP)}
+			},
+			expectedKind:    PolicyFinding,
+			expectedPath:    "/Channel/Application/Org1/Endorsement",
+			expectedMessage: "unknown policy type: 2",
+		},
+		{
+			testName: "When a mod policy is not defined",
+			configMod: func(t *testing.T, config *cb.Config) {
+				config.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org2"].ModPolicy = "Missing"
+			},
+			expectedKind:    ModPolicyFinding,
+			expectedPath:    "/Channel/Application/Org2",
+			expectedMessage: "mod policy /Channel/Application/Org2/Missing is not defined",
+		},
+		{
+			testName: "When an ImplicitMeta sub-policy is not defined in a child group",
+			configMod: func(t *testing.T, config *cb.Config) {
+				delete(config.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Policies, ReadersPolicyKey)
+			},
+			expectedKind:    PolicyFinding,
+			expectedPath:    "/Channel/Application/Readers",
+			expectedMessage: "sub-policy /Channel/Application/Org1/Readers is not defined",
+		},
+		{
+			testName: "When an MSP cannot be unmarshaled",
+			configMod: func(t *testing.T, config *cb.Config) {
+				config.ChannelGroup.Groups[ApplicationGroupKey].Groups["Org1"].Values[MSPKey].Value = []byte("another little fire")
+			},
+			expectedKind:    MSPFinding,
+			expectedPath:    "/Channel/Application/Org1/Values/MSP",
+			expectedMessage: "unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			config := sanityConfig(t)
+			tt.configMod(t, config)
+
+			findings, err := SanityCheck(config)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(findings).To(HaveLen(1))
+			gt.Expect(findings[0].Kind).To(Equal(tt.expectedKind))
+			gt.Expect(findings[0].Path).To(Equal(tt.expectedPath))
+			gt.Expect(findings[0].Message).To(ContainSubstring(tt.expectedMessage))
+		})
+	}
+}
@@ -0,0 +1,427 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+)
+
+// UnsatisfiablePolicy is a policy of a channel config which cannot be
+// satisfied by the members of the channel.
+type UnsatisfiablePolicy struct {
+	// Path is the path of the policy, such as /Channel/Application/Admins.
+	Path   string
+	Reason string
+}
+
+// String returns the path and reason of the unsatisfiable policy.
+func (p UnsatisfiablePolicy) String() string {
+	return fmt.Sprintf("%s: %s", p.Path, p.Reason)
+}
+
+// UnsatisfiablePolicies returns the policies of the updated config which
+// cannot be satisfied by the members of the channel, in path order.
+//
+// A signature policy is satisfiable if enough of its principals belong to an
+// MSP defined by an org of the channel. An ImplicitMeta policy is satisfiable
+// if enough child groups define a satisfiable sub-policy for its ANY, ALL or
+// MAJORITY rule; as in Fabric, an ImplicitMeta policy of a group without
+// child groups is always satisfied.
+func (c *ConfigTx) UnsatisfiablePolicies() ([]UnsatisfiablePolicy, error) {
+	return unsatisfiablePolicies(c.updated.ChannelGroup)
+}
+
+// ValidatePolicySatisfiability returns an error listing the policies which
+// are satisfiable in the original config but not in the updated config, see
+// UnsatisfiablePolicies. It should be called before computing the update of
+// an org or consenter removal, as a removal leaving a policy unsatisfiable
+// can deadlock the future governance of the channel, for example when the
+// channel Admins policy can no longer be met. Policies which were already
+// unsatisfiable are not reported. The removal methods of the groups log a
+// warning for such policies as they are removed.
+func (c *ConfigTx) ValidatePolicySatisfiability() error {
+	original, err := unsatisfiablePolicies(c.original.ChannelGroup)
+	if err != nil {
+		return err
+	}
+
+	updated, err := unsatisfiablePolicies(c.updated.ChannelGroup)
+	if err != nil {
+		return err
+	}
+
+	var failures []string
+	for _, policy := range newlyUnsatisfiable(original, updated) {
+		failures = append(failures, fmt.Sprintf("%s (%s)", policy.Path, policy.Reason))
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("policies would become unsatisfiable: %s", strings.Join(failures, "; "))
+	}
+
+	return nil
+}
+
+// watchSatisfiability returns a function which logs a warning for every
+// policy of the channel group which became unsatisfiable since
+// watchSatisfiability was called. Removals defer it so that the warning is
+// emitted when the removal leaves a policy unsatisfiable. Nothing is
+// evaluated when no logger is set.
+func (o observed) watchSatisfiability(channelGroup *cb.ConfigGroup) func() {
+	if o.logger == nil {
+		return func() {}
+	}
+
+	before, err := unsatisfiablePolicies(channelGroup)
+	if err != nil {
+		return func() {}
+	}
+
+	return func() {
+		after, err := unsatisfiablePolicies(channelGroup)
+		if err != nil {
+			return
+		}
+
+		for _, policy := range newlyUnsatisfiable(before, after) {
+			o.warn("policy left unsatisfiable", "policy", policy.Path, "reason", policy.Reason)
+		}
+	}
+}
+
+// satisfiabilityChecker evaluates the satisfiability of the policies of a
+// channel group.
+type satisfiabilityChecker struct {
+	// mspIDs are the IDs of the MSPs defined in the channel.
+	mspIDs map[string]bool
+	// checked holds the result of every policy evaluated so far by path.
+	checked map[string]bool
+	// unsatisfiable holds the reason of every unsatisfiable policy by path.
+	unsatisfiable map[string]string
+}
+
+// unsatisfiablePolicies returns the unsatisfiable policies of the channel
+// group, in path order, as if the MSPs with the excluded IDs were not defined.
+func unsatisfiablePolicies(channelGroup *cb.ConfigGroup, excludedMSPIDs ...string) ([]UnsatisfiablePolicy, error) {
+	s := &satisfiabilityChecker{
+		mspIDs:        map[string]bool{},
+		checked:       map[string]bool{},
+		unsatisfiable: map[string]string{},
+	}
+
+	err := collectMSPIDs(channelGroup, s.mspIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, mspID := range excludedMSPIDs {
+		delete(s.mspIDs, mspID)
+	}
+
+	err = s.checkGroup("/"+ChannelGroupKey, channelGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make([]UnsatisfiablePolicy, 0, len(s.unsatisfiable))
+	for path, reason := range s.unsatisfiable {
+		policies = append(policies, UnsatisfiablePolicy{Path: path, Reason: reason})
+	}
+	sort.Slice(policies, func(i, j int) bool {
+		return policies[i].Path < policies[j].Path
+	})
+
+	return policies, nil
+}
+
+// newlyUnsatisfiable returns the policies of after which are not part of
+// before.
+func newlyUnsatisfiable(before, after []UnsatisfiablePolicy) []UnsatisfiablePolicy {
+	unsatisfiable := map[string]bool{}
+	for _, policy := range before {
+		unsatisfiable[policy.Path] = true
+	}
+
+	var policies []UnsatisfiablePolicy
+	for _, policy := range after {
+		if !unsatisfiable[policy.Path] {
+			policies = append(policies, policy)
+		}
+	}
+
+	return policies
+}
+
+// collectMSPIDs records the IDs of the MSPs of the group and of its child
+// groups in mspIDs.
+func collectMSPIDs(group *cb.ConfigGroup, mspIDs map[string]bool) error {
+	if _, ok := group.Values[MSPKey]; ok {
+		mspConfig := &mb.MSPConfig{}
+		err := unmarshalConfigValueAtKey(group, MSPKey, mspConfig)
+		if err != nil {
+			return err
+		}
+
+		// 0 is the FABRIC MSP type, any other type is treated as idemix
+		var name string
+		if mspConfig.Type == 0 {
+			fabricMSPConfig := &mb.FabricMSPConfig{}
+			err = proto.Unmarshal(mspConfig.Config, fabricMSPConfig)
+			name = fabricMSPConfig.Name
+		} else {
+			idemixMSPConfig := &mb.IdemixMSPConfig{}
+			err = proto.Unmarshal(mspConfig.Config, idemixMSPConfig)
+			name = idemixMSPConfig.Name
+		}
+		if err != nil {
+			return fmt.Errorf("unmarshaling msp config: %v", err)
+		}
+		mspIDs[name] = true
+	}
+
+	for _, childName := range sortedGroupKeys(group) {
+		err := collectMSPIDs(group.Groups[childName], mspIDs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkGroup evaluates the policies of the group at path and of its child
+// groups.
+func (s *satisfiabilityChecker) checkGroup(path string, group *cb.ConfigGroup) error {
+	for _, key := range sortedPolicyKeys(group) {
+		_, err := s.satisfiable(path, group, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, childName := range sortedGroupKeys(group) {
+		err := s.checkGroup(path+"/"+childName, group.Groups[childName])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// satisfiable reports whether the policy with the given name of the group at
+// path exists and can be satisfied.
+func (s *satisfiabilityChecker) satisfiable(path string, group *cb.ConfigGroup, name string) (bool, error) {
+	policyName := path + "/" + name
+	if satisfiable, ok := s.checked[policyName]; ok {
+		return satisfiable, nil
+	}
+
+	configPolicy, ok := group.Policies[name]
+	if !ok {
+		return false, nil
+	}
+
+	reason, err := s.unsatisfiableReason(path, group, configPolicy)
+	if err != nil {
+		return false, fmt.Errorf("evaluating policy %s: %v", policyName, err)
+	}
+
+	s.checked[policyName] = reason == ""
+	if reason != "" {
+		s.unsatisfiable[policyName] = reason
+	}
+
+	return reason == "", nil
+}
+
+// unsatisfiableReason returns why the policy of the group at path cannot be
+// satisfied, or an empty string if it can.
+func (s *satisfiabilityChecker) unsatisfiableReason(path string, group *cb.ConfigGroup, configPolicy *cb.ConfigPolicy) (string, error) {
+	if configPolicy.Policy == nil {
+		return "policy is missing", nil
+	}
+
+	switch cb.Policy_PolicyType(configPolicy.Policy.Type) {
+	case cb.Policy_IMPLICIT_META:
+		imp := &cb.ImplicitMetaPolicy{}
+		err := proto.Unmarshal(configPolicy.Policy.Value, imp)
+		if err != nil {
+			return "", fmt.Errorf("unmarshaling implicit meta policy: %v", err)
+		}
+
+		rule, err := implicitMetaToString(imp)
+		if err != nil {
+			return "", err
+		}
+
+		satisfied := 0
+		for _, childName := range sortedGroupKeys(group) {
+			ok, err := s.satisfiable(path+"/"+childName, group.Groups[childName], imp.SubPolicy)
+			if err != nil {
+				return "", err
+			}
+			if ok {
+				satisfied++
+			}
+		}
+
+		children := len(group.Groups)
+		threshold := implicitMetaThreshold(imp.Rule, children)
+		if satisfied < threshold {
+			return fmt.Sprintf("%s requires %d satisfiable sub-policies, but only %d of %d are", rule, threshold, satisfied, children), nil
+		}
+	case cb.Policy_SIGNATURE:
+		sp := &cb.SignaturePolicyEnvelope{}
+		err := proto.Unmarshal(configPolicy.Policy.Value, sp)
+		if err != nil {
+			return "", fmt.Errorf("unmarshaling signature policy: %v", err)
+		}
+		if sp.Rule == nil {
+			return "signature policy has no rule", nil
+		}
+
+		undefined := map[string]bool{}
+		ok, err := s.satisfiableRule(sp.Rule, sp.Identities, undefined)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return "", nil
+		}
+
+		if len(undefined) == 0 {
+			return "signature policy cannot be satisfied", nil
+		}
+
+		mspIDs := make([]string, 0, len(undefined))
+		for mspID := range undefined {
+			mspIDs = append(mspIDs, mspID)
+		}
+		sort.Strings(mspIDs)
+
+		return fmt.Sprintf("signature policy requires MSPs which are not defined in the channel: %s", strings.Join(mspIDs, ", ")), nil
+	}
+
+	return "", nil
+}
+
+// implicitMetaThreshold returns the number of satisfied sub-policies an
+// ImplicitMeta policy with the rule and number of sub-policies requires.
+func implicitMetaThreshold(rule cb.ImplicitMetaPolicy_Rule, subPolicies int) int {
+	// Fabric considers a policy without sub-policies to be satisfied
+	if subPolicies == 0 {
+		return 0
+	}
+
+	switch rule {
+	case cb.ImplicitMetaPolicy_ALL:
+		return subPolicies
+	case cb.ImplicitMetaPolicy_MAJORITY:
+		return subPolicies/2 + 1
+	default:
+		return 1
+	}
+}
+
+// satisfiableRule reports whether the signature policy rule can be satisfied
+// by the members of the channel and records the undefined MSPs its principals
+// belong to in undefined.
+func (s *satisfiabilityChecker) satisfiableRule(rule *cb.SignaturePolicy, identities []*mb.MSPPrincipal, undefined map[string]bool) (bool, error) {
+	switch t := rule.Type.(type) {
+	case *cb.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(identities) {
+			return false, nil
+		}
+
+		mspIDs, err := principalMSPIDs(identities[t.SignedBy])
+		if err != nil {
+			return false, err
+		}
+
+		satisfiable := true
+		for _, mspID := range mspIDs {
+			if !s.mspIDs[mspID] {
+				undefined[mspID] = true
+				satisfiable = false
+			}
+		}
+
+		return satisfiable, nil
+	case *cb.SignaturePolicy_NOutOf_:
+		satisfied := 0
+		for _, subRule := range t.NOutOf.Rules {
+			ok, err := s.satisfiableRule(subRule, identities, undefined)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				satisfied++
+			}
+		}
+
+		return satisfied >= int(t.NOutOf.N), nil
+	default:
+		return false, nil
+	}
+}
+
+// principalMSPIDs returns the IDs of the MSPs an identity must belong to in
+// order to satisfy the principal.
+func principalMSPIDs(principal *mb.MSPPrincipal) ([]string, error) {
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		role := &mb.MSPRole{}
+		err := proto.Unmarshal(principal.Principal, role)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling role principal: %v", err)
+		}
+
+		return []string{role.MspIdentifier}, nil
+	case mb.MSPPrincipal_ORGANIZATION_UNIT:
+		ou := &mb.OrganizationUnit{}
+		err := proto.Unmarshal(principal.Principal, ou)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling organization unit principal: %v", err)
+		}
+
+		return []string{ou.MspIdentifier}, nil
+	case mb.MSPPrincipal_IDENTITY:
+		identity := &mb.SerializedIdentity{}
+		err := proto.Unmarshal(principal.Principal, identity)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling identity principal: %v", err)
+		}
+
+		return []string{identity.Mspid}, nil
+	case mb.MSPPrincipal_COMBINED:
+		combined := &mb.CombinedPrincipal{}
+		err := proto.Unmarshal(principal.Principal, combined)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshaling combined principal: %v", err)
+		}
+
+		var mspIDs []string
+		for _, p := range combined.Principals {
+			ids, err := principalMSPIDs(p)
+			if err != nil {
+				return nil, err
+			}
+			mspIDs = append(mspIDs, ids...)
+		}
+
+		return mspIDs, nil
+	default:
+		// anonymity principals do not depend on a specific MSP
+		return nil, nil
+	}
+}
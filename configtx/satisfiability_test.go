@@ -0,0 +1,207 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+// org3ConfigTx returns a ConfigTx whose original config has an application
+// org with MSP Org3MSP, which is the only signer of the application Admins
+// policy, and a channel Admins policy requiring a majority of the application
+// and orderer Admins.
+func org3ConfigTx(t *testing.T) ConfigTx {
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	err := c.Channel().SetPolicy(AdminsPolicyKey, Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Admins", ModPolicy: AdminsPolicyKey})
+	gt.Expect(err).NotTo(HaveOccurred())
+	org3 := baseApplicationOrg(t)
+	org3.Name = "Org3"
+	org3.MSP.Name = "Org3MSP"
+	err = c.Application().SetOrganization(org3)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Application().SetPolicy(AdminsPolicyKey, Policy{Type: SignaturePolicyType, Rule: "OR('Org3MSP.admin')", ModPolicy: AdminsPolicyKey})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return New(c.UpdatedConfig())
+}
+
+func TestUnsatisfiablePolicies(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := org3ConfigTx(t)
+	policies, err := c.UnsatisfiablePolicies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(BeEmpty())
+
+	c.Application().RemoveOrganization("Org3")
+	err = c.Orderer().SetPolicy("Custom", Policy{Type: SignaturePolicyType, Rule: "AND('MSPID.admin', 'Org4MSP.admin')", ModPolicy: AdminsPolicyKey})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	policies, err = c.UnsatisfiablePolicies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(Equal([]UnsatisfiablePolicy{
+		{
+			Path:   "/Channel/Admins",
+			Reason: "MAJORITY Admins requires 2 satisfiable sub-policies, but only 1 of 2 are",
+		},
+		{
+			Path:   "/Channel/Application/Admins",
+			Reason: "signature policy requires MSPs which are not defined in the channel: Org3MSP",
+		},
+		{
+			Path:   "/Channel/Orderer/Custom",
+			Reason: "signature policy requires MSPs which are not defined in the channel: Org4MSP",
+		},
+	}))
+	gt.Expect(policies[1].String()).To(Equal("/Channel/Application/Admins: signature policy requires MSPs which are not defined in the channel: Org3MSP"))
+}
+
+func TestValidatePolicySatisfiability(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		configMod   func(*testing.T, *ConfigTx)
+		expectedErr string
+	}{
+		{
+			testName: "When an org not referenced by a signature policy is removed",
+			configMod: func(t *testing.T, c *ConfigTx) {
+				c.Application().RemoveOrganization("Org2")
+			},
+		},
+		{
+			testName: "When the only org of a signature policy is removed",
+			configMod: func(t *testing.T, c *ConfigTx) {
+				c.Application().RemoveOrganization("Org3")
+			},
+			expectedErr: "policies would become unsatisfiable: " +
+				"/Channel/Admins (MAJORITY Admins requires 2 satisfiable sub-policies, but only 1 of 2 are); " +
+				"/Channel/Application/Admins (signature policy requires MSPs which are not defined in the channel: Org3MSP)",
+		},
+		{
+			testName: "When the sub-policies of an ALL policy are no longer satisfiable",
+			configMod: func(t *testing.T, c *ConfigTx) {
+				gt := NewGomegaWithT(t)
+				err := c.Application().SetPolicy(AdminsPolicyKey, Policy{Type: ImplicitMetaPolicyType, Rule: "ALL Admins", ModPolicy: AdminsPolicyKey})
+				gt.Expect(err).NotTo(HaveOccurred())
+				err = c.Application().Organization("Org1").SetPolicy(AdminsPolicyKey, Policy{Type: SignaturePolicyType, Rule: "OR('Org4MSP.admin')", ModPolicy: AdminsPolicyKey})
+				gt.Expect(err).NotTo(HaveOccurred())
+			},
+			expectedErr: "/Channel/Application/Admins (ALL Admins requires 3 satisfiable sub-policies, but only 2 of 3 are); " +
+				"/Channel/Application/Org1/Admins (signature policy requires MSPs which are not defined in the channel: Org4MSP)",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := org3ConfigTx(t)
+			tt.configMod(t, &c)
+
+			err := c.ValidatePolicySatisfiability()
+			if tt.expectedErr == "" {
+				gt.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+			gt.Expect(err).To(MatchError(ContainSubstring(tt.expectedErr)))
+		})
+	}
+}
+
+func TestValidatePolicySatisfiabilityIgnoresUnsatisfiablePolicies(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	err := c.Orderer().SetPolicy("Custom", Policy{Type: SignaturePolicyType, Rule: "OR('Org4MSP.admin')", ModPolicy: AdminsPolicyKey})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c = New(c.UpdatedConfig())
+	c.Application().RemoveOrganization("Org2")
+	gt.Expect(c.ValidatePolicySatisfiability()).To(Succeed())
+}
+
+func TestRemovalWarnsOfUnsatisfiablePolicies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName string
+		remove   func(*testing.T, *ConfigTx)
+	}{
+		{
+			testName: "When removing the org",
+			remove: func(t *testing.T, c *ConfigTx) {
+				c.Application().RemoveOrganization("Org3")
+			},
+		},
+		{
+			testName: "When applying the removal of the org",
+			remove: func(t *testing.T, c *ConfigTx) {
+				gt := NewGomegaWithT(t)
+				err := c.Apply([]Change{RemoveApplicationOrg{Name: "Org3"}})
+				gt.Expect(err).NotTo(HaveOccurred())
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := org3ConfigTx(t)
+			logger := &recordingLogger{}
+			c.SetLogger(logger)
+
+			tt.remove(t, &c)
+			gt.Expect(logger.warnings).To(Equal([]warning{
+				{
+					msg: "policy left unsatisfiable",
+					keyvals: []interface{}{
+						"path", "/Channel/Application",
+						"policy", "/Channel/Admins",
+						"reason", "MAJORITY Admins requires 2 satisfiable sub-policies, but only 1 of 2 are",
+					},
+				},
+				{
+					msg: "policy left unsatisfiable",
+					keyvals: []interface{}{
+						"path", "/Channel/Application",
+						"policy", "/Channel/Application/Admins",
+						"reason", "signature policy requires MSPs which are not defined in the channel: Org3MSP",
+					},
+				},
+			}))
+		})
+	}
+}
+
+func TestRemovalWithSatisfiablePolicies(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := org3ConfigTx(t)
+	logger := &recordingLogger{}
+	c.SetLogger(logger)
+
+	c.Application().RemoveOrganization("Org2")
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = c.Orderer().RemoveConsenter(ordererConfig.EtcdRaft.Consenters[0])
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(logger.warnings).To(BeEmpty())
+}
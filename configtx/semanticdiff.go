@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// decodedConfig is a config decoded to JSON by protolator. Versions are not
+// decoded, as they do not affect the behavior of the channel.
+type decodedConfig struct {
+	ChannelGroup *decodedGroup `json:"channel_group"`
+}
+
+type decodedGroup struct {
+	Groups    map[string]*decodedGroup  `json:"groups"`
+	Values    map[string]decodedElement `json:"values"`
+	Policies  map[string]decodedElement `json:"policies"`
+	ModPolicy string                    `json:"mod_policy"`
+}
+
+// decodedElement is a value or policy of a decoded group, which holds its
+// content in Value or Policy respectively.
+type decodedElement struct {
+	Value     interface{} `json:"value"`
+	Policy    interface{} `json:"policy"`
+	ModPolicy string      `json:"mod_policy"`
+}
+
+// SemanticDiff returns the changes between two configs decoded to JSON by
+// protolator, such as the output of configtxlator proto_decode, sorted by
+// path. Unlike a textual diff of the documents, it leaves out what does not
+// change the behavior of the channel: version increments, which every config
+// update causes for the elements it touches, and changes of a mod policy
+// between references to the same policy, such as from Admins to the absolute
+// path of the Admins policy of the same group. Values and policies are
+// compared by their decoded content, so the formatting of the documents does
+// not matter either.
+func SemanticDiff(originalJSON, updatedJSON []byte) ([]ConfigChange, error) {
+	original, err := unmarshalDecodedConfig(originalJSON)
+	if err != nil {
+		return nil, fmt.Errorf("original config: %v", err)
+	}
+
+	updated, err := unmarshalDecodedConfig(updatedJSON)
+	if err != nil {
+		return nil, fmt.Errorf("updated config: %v", err)
+	}
+
+	changes := semanticDiffGroup("/"+ChannelGroupKey, original.ChannelGroup, updated.ChannelGroup)
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Path < changes[j].Path
+	})
+
+	return changes, nil
+}
+
+func unmarshalDecodedConfig(b []byte) (*decodedConfig, error) {
+	config := &decodedConfig{}
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	err := d.Decode(config)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling decoded config: %v", err)
+	}
+
+	if config.ChannelGroup == nil {
+		return nil, errors.New("decoded config has no channel group")
+	}
+
+	return config, nil
+}
+
+// semanticDiffGroup returns the changes between the original and updated
+// decoded group at path.
+func semanticDiffGroup(path string, original, updated *decodedGroup) []ConfigChange {
+	var changes []ConfigChange
+
+	// a group's mod policy is relative to the group itself, as are those of
+	// its values and policies
+	if policyPath(path, original.ModPolicy) != policyPath(path, updated.ModPolicy) {
+		changes = append(changes, ConfigChange{Path: path, Kind: ConfigChangeModified})
+	}
+
+	for key, originalGroup := range original.Groups {
+		groupPath := path + "/" + key
+		updatedGroup, ok := updated.Groups[key]
+		if !ok {
+			changes = append(changes, ConfigChange{Path: groupPath, Kind: ConfigChangeRemoved})
+			continue
+		}
+		changes = append(changes, semanticDiffGroup(groupPath, originalGroup, updatedGroup)...)
+	}
+	for key := range updated.Groups {
+		if _, ok := original.Groups[key]; !ok {
+			changes = append(changes, ConfigChange{Path: path + "/" + key, Kind: ConfigChangeAdded})
+		}
+	}
+
+	changes = append(changes, semanticDiffElements(path, path+"/Values/", original.Values, updated.Values)...)
+	changes = append(changes, semanticDiffElements(path, path+"/Policies/", original.Policies, updated.Policies)...)
+
+	return changes
+}
+
+// semanticDiffElements returns the changes between the original and updated
+// values or policies of the decoded group at path, whose paths start with
+// prefix.
+func semanticDiffElements(path, prefix string, original, updated map[string]decodedElement) []ConfigChange {
+	var changes []ConfigChange
+
+	for key, originalElement := range original {
+		updatedElement, ok := updated[key]
+		switch {
+		case !ok:
+			changes = append(changes, ConfigChange{Path: prefix + key, Kind: ConfigChangeRemoved})
+		case !reflect.DeepEqual(originalElement.Value, updatedElement.Value),
+			!reflect.DeepEqual(originalElement.Policy, updatedElement.Policy),
+			policyPath(path, originalElement.ModPolicy) != policyPath(path, updatedElement.ModPolicy):
+			changes = append(changes, ConfigChange{Path: prefix + key, Kind: ConfigChangeModified})
+		}
+	}
+	for key := range updated {
+		if _, ok := original[key]; !ok {
+			changes = append(changes, ConfigChange{Path: prefix + key, Kind: ConfigChangeAdded})
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	. "github.com/onsi/gomega"
+)
+
+// decodeConfig returns the config decoded by protolator as read from a block.
+// The config is round-tripped through its wire format first, as a clone holds
+// nil rather than empty bytes for values of empty messages, which decode
+// differently.
+func decodeConfig(t *testing.T, config *cb.Config) []byte {
+	gt := NewGomegaWithT(t)
+
+	marshaledConfig, err := proto.Marshal(config)
+	gt.Expect(err).NotTo(HaveOccurred())
+	config = &cb.Config{}
+	err = proto.Unmarshal(marshaledConfig, config)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var buffer bytes.Buffer
+	err = protolator.DeepMarshalJSON(&buffer, config)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return buffer.Bytes()
+}
+
+func TestSemanticDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName        string
+		configMod       func(*testing.T, *cb.Config) *cb.Config
+		expectedChanges []ConfigChange
+	}{
+		{
+			testName: "When a value is modified by a config update",
+			configMod: func(t *testing.T, config *cb.Config) *cb.Config {
+				gt := NewGomegaWithT(t)
+				update := &cb.ConfigUpdate{}
+				err := proto.Unmarshal(staleUpdate(t, config, SetBatchTimeout{Timeout: 5 * time.Second}), update)
+				gt.Expect(err).NotTo(HaveOccurred())
+				updated, err := applyConfigUpdate(config, update)
+				gt.Expect(err).NotTo(HaveOccurred())
+				return updated
+			},
+			expectedChanges: []ConfigChange{
+				{Path: "/Channel/Orderer/Values/BatchTimeout", Kind: ConfigChangeModified},
+			},
+		},
+		{
+			testName: "When only versions change",
+			configMod: func(t *testing.T, config *cb.Config) *cb.Config {
+				config.Sequence++
+				config.ChannelGroup.Version++
+				config.ChannelGroup.Groups[OrdererGroupKey].Version++
+				config.ChannelGroup.Groups[OrdererGroupKey].Values["BatchTimeout"].Version++
+				config.ChannelGroup.Groups[ApplicationGroupKey].Policies[AdminsPolicyKey].Version++
+				return config
+			},
+		},
+		{
+			testName: "When mod policies refer to the same policies",
+			configMod: func(t *testing.T, config *cb.Config) *cb.Config {
+				ordererGroup := config.ChannelGroup.Groups[OrdererGroupKey]
+				ordererGroup.ModPolicy = "/Channel/Orderer/Admins"
+				ordererGroup.Values["BatchTimeout"].ModPolicy = "/Channel/Orderer/Admins"
+				ordererGroup.Policies[ReadersPolicyKey].ModPolicy = "/Channel/Orderer/Admins"
+				return config
+			},
+		},
+		{
+			testName: "When mod policies refer to other policies",
+			configMod: func(t *testing.T, config *cb.Config) *cb.Config {
+				ordererGroup := config.ChannelGroup.Groups[OrdererGroupKey]
+				ordererGroup.ModPolicy = "/Channel/Admins"
+				ordererGroup.Values["BatchTimeout"].ModPolicy = WritersPolicyKey
+				return config
+			},
+			expectedChanges: []ConfigChange{
+				{Path: "/Channel/Orderer", Kind: ConfigChangeModified},
+				{Path: "/Channel/Orderer/Values/BatchTimeout", Kind: ConfigChangeModified},
+			},
+		},
+		{
+			testName: "When groups, values and policies are added and removed",
+			configMod: func(t *testing.T, config *cb.Config) *cb.Config {
+				applicationGroup := config.ChannelGroup.Groups[ApplicationGroupKey]
+				delete(applicationGroup.Groups, "Org2")
+				applicationGroup.Groups["Org3"] = proto.Clone(applicationGroup.Groups["Org1"]).(*cb.ConfigGroup)
+				delete(applicationGroup.Groups["Org1"].Policies, EndorsementPolicyKey)
+				ordererGroup := config.ChannelGroup.Groups[OrdererGroupKey]
+				delete(ordererGroup.Values, "BatchTimeout")
+				ordererGroup.Policies["Custom"] = proto.Clone(ordererGroup.Policies[AdminsPolicyKey]).(*cb.ConfigPolicy)
+				return config
+			},
+			expectedChanges: []ConfigChange{
+				{Path: "/Channel/Application/Org1/Policies/Endorsement", Kind: ConfigChangeRemoved},
+				{Path: "/Channel/Application/Org2", Kind: ConfigChangeRemoved},
+				{Path: "/Channel/Application/Org3", Kind: ConfigChangeAdded},
+				{Path: "/Channel/Orderer/Policies/Custom", Kind: ConfigChangeAdded},
+				{Path: "/Channel/Orderer/Values/BatchTimeout", Kind: ConfigChangeRemoved},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := applyConfigTx(t)
+			original := c.OriginalConfig()
+			updated := tt.configMod(t, proto.Clone(original).(*cb.Config))
+
+			changes, err := SemanticDiff(decodeConfig(t, original), decodeConfig(t, updated))
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(changes).To(Equal(tt.expectedChanges))
+		})
+	}
+}
+
+func TestSemanticDiffFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	decoded := decodeConfig(t, c.OriginalConfig())
+
+	_, err := SemanticDiff([]byte("{"), decoded)
+	gt.Expect(err).To(MatchError("original config: unmarshaling decoded config: unexpected EOF"))
+
+	_, err = SemanticDiff(decoded, []byte(`{"sequence": "1"}`))
+	gt.Expect(err).To(MatchError("updated config: decoded config has no channel group"))
+}
@@ -0,0 +1,212 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/internal/policydsl"
+)
+
+// Principal is an MSP principal of a signature policy built with SignedBy.
+// Errors constructing a principal are reported by SignatureRule.Envelope.
+type Principal struct {
+	principal *mb.MSPPrincipal
+	err       error
+}
+
+// RolePrincipal returns the principal of the identities of the MSP with the
+// given role, such as mb.MSPRole_ADMIN. It is written 'Org1MSP.admin' in the
+// policy DSL.
+func RolePrincipal(mspID string, role mb.MSPRole_MSPRoleType) Principal {
+	return newPrincipal(mb.MSPPrincipal_ROLE, &mb.MSPRole{
+		MspIdentifier: mspID,
+		Role:          role,
+	})
+}
+
+// OUPrincipal returns the principal of the identities of the MSP carrying
+// the organizational unit.
+func OUPrincipal(mspID, organizationalUnit string) Principal {
+	return newPrincipal(mb.MSPPrincipal_ORGANIZATION_UNIT, &mb.OrganizationUnit{
+		MspIdentifier:                mspID,
+		OrganizationalUnitIdentifier: organizationalUnit,
+	})
+}
+
+// IdentityPrincipal returns the principal of the identity of the MSP with
+// the given certificate.
+func IdentityPrincipal(mspID string, cert *x509.Certificate) Principal {
+	if cert == nil {
+		return Principal{err: fmt.Errorf("certificate of identity principal of MSP %s is required", mspID)}
+	}
+
+	return newPrincipal(mb.MSPPrincipal_IDENTITY, &mb.SerializedIdentity{
+		Mspid:   mspID,
+		IdBytes: pemEncodeX509Certificate(cert),
+	})
+}
+
+// CombinedPrincipal returns the principal of the identities matching all of
+// the principals, such as the admins of an MSP carrying an organizational
+// unit.
+func CombinedPrincipal(principals ...Principal) Principal {
+	if len(principals) == 0 {
+		return Principal{err: errors.New("combined principal requires at least one principal")}
+	}
+
+	combined := &mb.CombinedPrincipal{}
+	for _, p := range principals {
+		if p.err != nil {
+			return p
+		}
+		combined.Principals = append(combined.Principals, p.principal)
+	}
+
+	return newPrincipal(mb.MSPPrincipal_COMBINED, combined)
+}
+
+// MSPPrincipal returns the MSPPrincipal protobuf of the principal.
+func (p Principal) MSPPrincipal() (*mb.MSPPrincipal, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return proto.Clone(p.principal).(*mb.MSPPrincipal), nil
+}
+
+func newPrincipal(classification mb.MSPPrincipal_Classification, msg proto.Message) Principal {
+	principal, err := proto.Marshal(msg)
+	if err != nil {
+		return Principal{err: fmt.Errorf("marshaling %s principal: %v", classification, err)}
+	}
+
+	return Principal{
+		principal: &mb.MSPPrincipal{
+			PrincipalClassification: classification,
+			Principal:               principal,
+		},
+	}
+}
+
+// SignatureRule is a rule of a signature policy: either the signature of a
+// principal, see SignedBy, or a threshold of rules, see NOutOf. It is the
+// typed alternative to the policy DSL, e.g.
+//
+//	AnyOf(
+//		SignedBy(RolePrincipal("Org1MSP", mb.MSPRole_ADMIN)),
+//		AllOf(
+//			SignedBy(RolePrincipal("Org2MSP", mb.MSPRole_PEER)),
+//			SignedBy(RolePrincipal("Org3MSP", mb.MSPRole_PEER)),
+//		),
+//	)
+//
+// is the rule "OR('Org1MSP.admin', AND('Org2MSP.peer', 'Org3MSP.peer'))".
+type SignatureRule struct {
+	principal *Principal
+	n         int
+	rules     []SignatureRule
+}
+
+// SignedBy returns the rule requiring a signature of the principal.
+func SignedBy(principal Principal) SignatureRule {
+	return SignatureRule{principal: &principal}
+}
+
+// NOutOf returns the rule requiring n of the rules to be satisfied.
+func NOutOf(n int, rules ...SignatureRule) SignatureRule {
+	return SignatureRule{n: n, rules: rules}
+}
+
+// AnyOf returns the rule requiring one of the rules to be satisfied.
+func AnyOf(rules ...SignatureRule) SignatureRule {
+	return NOutOf(1, rules...)
+}
+
+// AllOf returns the rule requiring all of the rules to be satisfied.
+func AllOf(rules ...SignatureRule) SignatureRule {
+	return NOutOf(len(rules), rules...)
+}
+
+// Envelope returns the SignaturePolicyEnvelope of the rule. The principals
+// are numbered in the order of the rules requiring their signatures. A
+// signature of a single principal is wrapped in a one out of one rule, as
+// done by the policy DSL.
+func (r SignatureRule) Envelope() (*cb.SignaturePolicyEnvelope, error) {
+	if r.principal != nil {
+		r = AllOf(r)
+	}
+
+	envelope := &cb.SignaturePolicyEnvelope{}
+	rule, err := r.signaturePolicy(envelope)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Rule = rule
+
+	return envelope, nil
+}
+
+// Policy returns the rule as a signature Policy in the policy DSL, which
+// only expresses role principals. Rules with other principals are set from
+// their envelope with the SetSignaturePolicy method of an org.
+func (r SignatureRule) Policy() (Policy, error) {
+	envelope, err := r.Envelope()
+	if err != nil {
+		return Policy{}, err
+	}
+
+	for _, principal := range envelope.Identities {
+		if principal.PrincipalClassification != mb.MSPPrincipal_ROLE {
+			return Policy{}, fmt.Errorf("%s principals cannot be expressed in the policy DSL", principal.PrincipalClassification)
+		}
+	}
+
+	rule, err := FormatSignaturePolicy(envelope)
+	if err != nil {
+		return Policy{}, err
+	}
+
+	return Policy{Type: SignaturePolicyType, Rule: rule}, nil
+}
+
+// signaturePolicy returns the SignaturePolicy of the rule and adds the
+// principals of its signatures to the identities of the envelope.
+func (r SignatureRule) signaturePolicy(envelope *cb.SignaturePolicyEnvelope) (*cb.SignaturePolicy, error) {
+	if r.principal != nil {
+		principal, err := r.principal.MSPPrincipal()
+		if err != nil {
+			return nil, err
+		}
+		envelope.Identities = append(envelope.Identities, principal)
+
+		return policydsl.SignedBy(int32(len(envelope.Identities) - 1)), nil
+	}
+
+	if len(r.rules) == 0 {
+		return nil, errors.New("n out of rule requires at least one rule")
+	}
+	if r.n < 1 || r.n > len(r.rules) {
+		return nil, fmt.Errorf("n out of rule requires n between 1 and %d, got %d", len(r.rules), r.n)
+	}
+
+	rules := make([]*cb.SignaturePolicy, len(r.rules))
+	for i, rule := range r.rules {
+		policy, err := rule.signaturePolicy(envelope)
+		if err != nil {
+			return nil, err
+		}
+		rules[i] = policy
+	}
+
+	return policydsl.NOutOf(int32(r.n), rules), nil
+}
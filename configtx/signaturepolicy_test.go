@@ -0,0 +1,262 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/internal/policydsl"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestSignatureRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName     string
+		rule         SignatureRule
+		expectedRule string
+	}{
+		{
+			testName:     "when the rule is a single signature",
+			rule:         SignedBy(RolePrincipal("Org1MSP", mb.MSPRole_ADMIN)),
+			expectedRule: "AND('Org1MSP.admin')",
+		},
+		{
+			testName: "when the rule is any of the signatures",
+			rule: AnyOf(
+				SignedBy(RolePrincipal("Org1MSP", mb.MSPRole_PEER)),
+				SignedBy(RolePrincipal("Org2MSP", mb.MSPRole_CLIENT)),
+			),
+			expectedRule: "OR('Org1MSP.peer', 'Org2MSP.client')",
+		},
+		{
+			testName: "when the rules are nested",
+			rule: AnyOf(
+				SignedBy(RolePrincipal("Org1MSP", mb.MSPRole_ADMIN)),
+				AllOf(
+					SignedBy(RolePrincipal("Org2MSP", mb.MSPRole_MEMBER)),
+					SignedBy(RolePrincipal("Org3MSP", mb.MSPRole_ORDERER)),
+				),
+			),
+			expectedRule: "OR('Org1MSP.admin', AND('Org2MSP.member', 'Org3MSP.orderer'))",
+		},
+		{
+			testName: "when the rule is a threshold",
+			rule: NOutOf(2,
+				SignedBy(RolePrincipal("Org1MSP", mb.MSPRole_ADMIN)),
+				SignedBy(RolePrincipal("Org2MSP", mb.MSPRole_ADMIN)),
+				SignedBy(RolePrincipal("Org3MSP", mb.MSPRole_ADMIN)),
+			),
+			expectedRule: "OUTOF(2, 'Org1MSP.admin', 'Org2MSP.admin', 'Org3MSP.admin')",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			envelope, err := tt.rule.Envelope()
+			gt.Expect(err).NotTo(HaveOccurred())
+			rule, err := FormatSignaturePolicy(envelope)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(rule).To(Equal(tt.expectedRule))
+
+			// the envelope is equivalent to the one of the policy DSL
+			parsed, err := policydsl.FromString(tt.expectedRule)
+			gt.Expect(err).NotTo(HaveOccurred())
+			parsedRule, err := FormatSignaturePolicy(parsed)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(parsedRule).To(Equal(rule))
+
+			policy, err := tt.rule.Policy()
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(policy).To(Equal(Policy{Type: SignaturePolicyType, Rule: tt.expectedRule}))
+			gt.Expect(policy.Validate()).To(Succeed())
+		})
+	}
+}
+
+func TestSignatureRulePrincipals(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	cert := generateCert(t, "peer0.org1.example.com")
+	rule := AllOf(
+		SignedBy(IdentityPrincipal("Org1MSP", cert)),
+		SignedBy(OUPrincipal("Org1MSP", "department1")),
+		SignedBy(CombinedPrincipal(
+			RolePrincipal("Org2MSP", mb.MSPRole_ADMIN),
+			OUPrincipal("Org2MSP", "department2"),
+		)),
+	)
+
+	envelope, err := rule.Envelope()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(envelope.Identities).To(HaveLen(3))
+	gt.Expect(envelope.Rule).To(Equal(policydsl.NOutOf(3, []*cb.SignaturePolicy{
+		policydsl.SignedBy(0),
+		policydsl.SignedBy(1),
+		policydsl.SignedBy(2),
+	})))
+
+	identity := &mb.SerializedIdentity{}
+	gt.Expect(envelope.Identities[0].PrincipalClassification).To(Equal(mb.MSPPrincipal_IDENTITY))
+	err = proto.Unmarshal(envelope.Identities[0].Principal, identity)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(identity.Mspid).To(Equal("Org1MSP"))
+	gt.Expect(identity.IdBytes).To(Equal(pemEncodeX509Certificate(cert)))
+
+	rendered, err := FormatSignaturePolicy(envelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(rendered).To(Equal(fmt.Sprintf(
+		"AND('Org1MSP.identity(%s, serial %s)', 'Org1MSP.OU(department1)', COMBINED('Org2MSP.admin', 'Org2MSP.OU(department2)'))",
+		cert.Subject, cert.SerialNumber,
+	)))
+
+	principal, err := OUPrincipal("Org1MSP", "department1").MSPPrincipal()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(principal, envelope.Identities[1])).To(BeTrue())
+
+	_, err = rule.Policy()
+	gt.Expect(err).To(MatchError("IDENTITY principals cannot be expressed in the policy DSL"))
+}
+
+func TestSignatureRuleFailures(t *testing.T) {
+	t.Parallel()
+
+	admin := SignedBy(RolePrincipal("Org1MSP", mb.MSPRole_ADMIN))
+
+	tests := []struct {
+		testName    string
+		rule        SignatureRule
+		expectedErr string
+	}{
+		{
+			testName:    "when a rule has no sub-rules",
+			rule:        AnyOf(),
+			expectedErr: "n out of rule requires at least one rule",
+		},
+		{
+			testName:    "when n is zero",
+			rule:        NOutOf(0, admin),
+			expectedErr: "n out of rule requires n between 1 and 1, got 0",
+		},
+		{
+			testName:    "when n exceeds the count of rules",
+			rule:        AnyOf(admin, NOutOf(3, admin, admin)),
+			expectedErr: "n out of rule requires n between 1 and 2, got 3",
+		},
+		{
+			testName:    "when the certificate of an identity principal is missing",
+			rule:        AnyOf(admin, SignedBy(IdentityPrincipal("Org1MSP", nil))),
+			expectedErr: "certificate of identity principal of MSP Org1MSP is required",
+		},
+		{
+			testName:    "when a combined principal has no principals",
+			rule:        SignedBy(CombinedPrincipal()),
+			expectedErr: "combined principal requires at least one principal",
+		},
+		{
+			testName:    "when a principal of a combined principal is invalid",
+			rule:        SignedBy(CombinedPrincipal(IdentityPrincipal("Org2MSP", nil))),
+			expectedErr: "certificate of identity principal of MSP Org2MSP is required",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := tt.rule.Envelope()
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+
+			_, err = tt.rule.Policy()
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
+
+func TestSetSignaturePolicy(t *testing.T) {
+	t.Parallel()
+
+	cert := generateCert(t, "peer0.org1.example.com")
+	envelope, err := AnyOf(
+		SignedBy(IdentityPrincipal("MSPID", cert)),
+		SignedBy(OUPrincipal("MSPID", "department1")),
+	).Envelope()
+	NewGomegaWithT(t).Expect(err).NotTo(HaveOccurred())
+
+	tests := []struct {
+		testName string
+		set      func(c *ConfigTx, envelope *cb.SignaturePolicyEnvelope) error
+		policies func(c *ConfigTx) map[string]*cb.ConfigPolicy
+	}{
+		{
+			testName: "application org",
+			set: func(c *ConfigTx, envelope *cb.SignaturePolicyEnvelope) error {
+				return c.Application().Organization("Org1").SetSignaturePolicy("Custom", envelope, "")
+			},
+			policies: func(c *ConfigTx) map[string]*cb.ConfigPolicy {
+				return c.Application().Organization("Org1").orgGroup.Policies
+			},
+		},
+		{
+			testName: "consortium org",
+			set: func(c *ConfigTx, envelope *cb.SignaturePolicyEnvelope) error {
+				return c.Consortium("Consortium1").Organization("Org1").SetSignaturePolicy("Custom", envelope, "")
+			},
+			policies: func(c *ConfigTx) map[string]*cb.ConfigPolicy {
+				return c.Consortium("Consortium1").Organization("Org1").orgGroup.Policies
+			},
+		},
+		{
+			testName: "orderer org",
+			set: func(c *ConfigTx, envelope *cb.SignaturePolicyEnvelope) error {
+				return c.Orderer().Organization("OrdererOrg").SetSignaturePolicy("Custom", envelope, "")
+			},
+			policies: func(c *ConfigTx) map[string]*cb.ConfigPolicy {
+				return c.Orderer().Organization("OrdererOrg").orgGroup.Policies
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c, _ := journalConfigTx(t, orderer.ConsensusTypeSolo)
+
+			err := tt.set(&c, envelope)
+			gt.Expect(err).NotTo(HaveOccurred())
+			policy := tt.policies(&c)["Custom"]
+			gt.Expect(policy.ModPolicy).To(Equal(AdminsPolicyKey))
+			gt.Expect(policy.Policy.Type).To(Equal(int32(cb.Policy_SIGNATURE)))
+			installed := &cb.SignaturePolicyEnvelope{}
+			err = proto.Unmarshal(policy.Policy.Value, installed)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(proto.Equal(installed, envelope)).To(BeTrue())
+
+			err = tt.set(&c, nil)
+			gt.Expect(err).To(MatchError(ContainSubstring("signature policy has no rule")))
+
+			err = tt.set(&c, &cb.SignaturePolicyEnvelope{Rule: policydsl.SignedBy(0)})
+			gt.Expect(err).To(MatchError(ContainSubstring("signature policy refers to identity 0, but has 0 identities")))
+		})
+	}
+}
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package configtx
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/ecdsa"
 	"crypto/rand"
@@ -14,13 +15,19 @@ import (
 	"crypto/x509"
 	"encoding/asn1"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
 )
 
 // SigningIdentity is an MSP Identity that can be used to sign configuration
@@ -31,6 +38,174 @@ type SigningIdentity struct {
 	MSPID       string
 }
 
+// PassphraseFunc returns the passphrase of the encrypted private key stored
+// in the file at path.
+type PassphraseFunc func(path string) ([]byte, error)
+
+// NewSigningIdentityFromMSPDir loads the signing identity of the local MSP
+// directory at path, as written by cryptogen or the fabric-ca client for an
+// admin or user. The certificate is read from the signcerts directory and the
+// private key is the one of the keystore directory matching it. Encrypted
+// private keys are not loaded, see NewSigningIdentityFromEncryptedMSPDir.
+func NewSigningIdentityFromMSPDir(path, mspID string) (SigningIdentity, error) {
+	return NewSigningIdentityFromEncryptedMSPDir(path, mspID, nil)
+}
+
+// NewSigningIdentityFromEncryptedMSPDir loads the signing identity of the
+// local MSP directory at path like NewSigningIdentityFromMSPDir, decrypting
+// passphrase protected private keys of the keystore with the passphrase
+// returned by the passphrase function. Both encrypted PKCS#8 keys and legacy
+// encrypted EC keys are supported.
+func NewSigningIdentityFromEncryptedMSPDir(path, mspID string, passphrase PassphraseFunc) (SigningIdentity, error) {
+	if mspID == "" {
+		return SigningIdentity{}, errors.New("msp id is required")
+	}
+
+	signcertsDir := filepath.Join(path, "signcerts")
+	certFiles, err := readDirFiles(signcertsDir)
+	if err != nil {
+		return SigningIdentity{}, err
+	}
+	if len(certFiles) != 1 {
+		return SigningIdentity{}, fmt.Errorf("expected one certificate in %s, found %d files", signcertsDir, len(certFiles))
+	}
+
+	cert, err := pemutil.ParseCertificate(certFiles[0].content)
+	if err != nil {
+		return SigningIdentity{}, fmt.Errorf("parsing certificate %s: %v", certFiles[0].name, err)
+	}
+
+	keystoreDir := filepath.Join(path, "keystore")
+	keyFiles, err := readDirFiles(keystoreDir)
+	if err != nil {
+		return SigningIdentity{}, err
+	}
+
+	// the keystore of fabric-ca may hold keys of earlier enrollments, so the
+	// key is matched against the public key of the certificate
+	var encryptedKeyErr error
+	for _, keyFile := range keyFiles {
+		var privateKey crypto.PrivateKey
+		if pemutil.IsEncryptedPrivateKey(keyFile.content) {
+			privateKey, err = decryptKeystoreKey(keyFile, passphrase)
+			if err != nil {
+				encryptedKeyErr = err
+				continue
+			}
+		} else {
+			privateKey, err = pemutil.ParsePrivateKey(keyFile.content)
+			if err != nil {
+				continue
+			}
+		}
+
+		signer, ok := privateKey.(crypto.Signer)
+		if !ok {
+			continue
+		}
+
+		publicKey, err := x509.MarshalPKIXPublicKey(signer.Public())
+		if err != nil || !bytes.Equal(publicKey, cert.RawSubjectPublicKeyInfo) {
+			continue
+		}
+
+		return SigningIdentity{
+			Certificate: cert,
+			PrivateKey:  privateKey,
+			MSPID:       mspID,
+		}, nil
+	}
+
+	if encryptedKeyErr != nil {
+		return SigningIdentity{}, fmt.Errorf("no private key in %s matches the certificate %s: %v", keystoreDir, certFiles[0].name, encryptedKeyErr)
+	}
+
+	return SigningIdentity{}, fmt.Errorf("no private key in %s matches the certificate %s", keystoreDir, certFiles[0].name)
+}
+
+// NewSigningIdentityFromPKCS12 loads the signing identity of the PKCS#12
+// bundle, such as a .p12 or .pfx file exported from a keystore, protected by
+// the password. The bundle must hold exactly one private key and its
+// certificate, other certificates of the bundle are ignored.
+func NewSigningIdentityFromPKCS12(data []byte, password, mspID string) (SigningIdentity, error) {
+	if mspID == "" {
+		return SigningIdentity{}, errors.New("msp id is required")
+	}
+
+	privateKey, cert, _, err := pemutil.ParsePKCS12(data, password)
+	if err != nil {
+		return SigningIdentity{}, fmt.Errorf("parsing PKCS#12 bundle: %v", err)
+	}
+
+	return SigningIdentity{
+		Certificate: cert,
+		PrivateKey:  privateKey,
+		MSPID:       mspID,
+	}, nil
+}
+
+// decryptKeystoreKey decrypts the encrypted private key of the keystore file
+// with the passphrase returned for it.
+func decryptKeystoreKey(keyFile dirFile, passphrase PassphraseFunc) (crypto.PrivateKey, error) {
+	if passphrase == nil {
+		return nil, fmt.Errorf("private key %s is encrypted and no passphrase is given", keyFile.name)
+	}
+
+	secret, err := passphrase(keyFile.name)
+	if err != nil {
+		return nil, fmt.Errorf("passphrase for private key %s: %v", keyFile.name, err)
+	}
+
+	privateKey, err := pemutil.ParseEncryptedPrivateKey(keyFile.content, secret)
+	if err != nil {
+		return nil, fmt.Errorf("private key %s: %v", keyFile.name, err)
+	}
+
+	return privateKey, nil
+}
+
+// dirFile is a regular file of a directory.
+type dirFile struct {
+	name    string
+	content []byte
+}
+
+// readDirFiles reads the regular files of the directory in name order,
+// skipping hidden files.
+func readDirFiles(dir string) ([]dirFile, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading directory: %v", err)
+	}
+
+	var files []dirFile
+	for _, info := range infos {
+		if strings.HasPrefix(info.Name(), ".") {
+			continue
+		}
+
+		name := filepath.Join(dir, info.Name())
+
+		// follow symlinks, such as those of mounted kubernetes secrets
+		stat, err := os.Stat(name)
+		if err != nil {
+			return nil, fmt.Errorf("reading file: %v", err)
+		}
+		if !stat.Mode().IsRegular() {
+			continue
+		}
+
+		content, err := ioutil.ReadFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("reading file: %v", err)
+		}
+
+		files = append(files, dirFile{name: name, content: content})
+	}
+
+	return files, nil
+}
+
 type ecdsaSignature struct {
 	R, S *big.Int
 }
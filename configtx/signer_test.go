@@ -16,13 +16,19 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
 	"io"
+	"io/ioutil"
 	"math/big"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
 	. "github.com/onsi/gomega"
 )
 
@@ -97,6 +103,145 @@ func TestPublic(t *testing.T) {
 	gt.Expect(signingIdentity.Public()).To(Equal(cert.PublicKey))
 }
 
+func TestNewSigningIdentityFromMSPDir(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "org1.example.com")
+	cert, privKey := generateCertAndPrivateKeyFromCACert(t, "org1.example.com", caCert, caPrivKey)
+	_, otherPrivKey := generateCACertAndPrivateKey(t, "org1.example.com")
+
+	dir, err := ioutil.TempDir("", "msp")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	gt.Expect(os.MkdirAll(filepath.Join(dir, "signcerts"), 0755)).To(Succeed())
+	gt.Expect(os.MkdirAll(filepath.Join(dir, "keystore"), 0755)).To(Succeed())
+
+	_, err = NewSigningIdentityFromMSPDir(dir, "Org1MSP")
+	gt.Expect(err).To(MatchError(ContainSubstring("expected one certificate in")))
+
+	err = ioutil.WriteFile(filepath.Join(dir, "signcerts", "Admin@org1.example.com-cert.pem"), pemutil.EncodeCertificate(cert), 0644)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// a key of an earlier enrollment and a file which is no key
+	otherKeyPEM, err := pemutil.EncodePrivateKey(otherPrivKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ioutil.WriteFile(filepath.Join(dir, "keystore", "a_sk"), otherKeyPEM, 0600)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ioutil.WriteFile(filepath.Join(dir, "keystore", "b_sk"), []byte("not a key"), 0600)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = NewSigningIdentityFromMSPDir(dir, "Org1MSP")
+	gt.Expect(err).To(MatchError(ContainSubstring("no private key in")))
+
+	keyPEM, err := pemutil.EncodePrivateKey(privKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = ioutil.WriteFile(filepath.Join(dir, "keystore", "priv_sk"), keyPEM, 0600)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signingIdentity, err := NewSigningIdentityFromMSPDir(dir, "Org1MSP")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(signingIdentity.Certificate).To(Equal(cert))
+	gt.Expect(signingIdentity.PrivateKey).To(Equal(privKey))
+	gt.Expect(signingIdentity.MSPID).To(Equal("Org1MSP"))
+
+	_, err = NewSigningIdentityFromMSPDir(dir, "")
+	gt.Expect(err).To(MatchError("msp id is required"))
+
+	_, err = NewSigningIdentityFromMSPDir(filepath.Join(dir, "missing"), "Org1MSP")
+	gt.Expect(err).To(MatchError(ContainSubstring("reading directory")))
+}
+
+func TestNewSigningIdentityFromEncryptedMSPDir(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	caCert, caPrivKey := generateCACertAndPrivateKey(t, "org1.example.com")
+	cert, privKey := generateCertAndPrivateKeyFromCACert(t, "org1.example.com", caCert, caPrivKey)
+
+	dir, err := ioutil.TempDir("", "msp")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	gt.Expect(os.MkdirAll(filepath.Join(dir, "signcerts"), 0755)).To(Succeed())
+	gt.Expect(os.MkdirAll(filepath.Join(dir, "keystore"), 0755)).To(Succeed())
+
+	err = ioutil.WriteFile(filepath.Join(dir, "signcerts", "cert.pem"), pemutil.EncodeCertificate(cert), 0644)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	der, err := x509.MarshalECPrivateKey(privKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	block, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", der, []byte("secret"), x509.PEMCipherAES256)
+	gt.Expect(err).NotTo(HaveOccurred())
+	keyPath := filepath.Join(dir, "keystore", "priv_sk")
+	err = ioutil.WriteFile(keyPath, pem.EncodeToMemory(block), 0600)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = NewSigningIdentityFromMSPDir(dir, "Org1MSP")
+	gt.Expect(err).To(MatchError(ContainSubstring("private key " + keyPath + " is encrypted and no passphrase is given")))
+
+	_, err = NewSigningIdentityFromEncryptedMSPDir(dir, "Org1MSP", func(string) ([]byte, error) {
+		return []byte("wrong"), nil
+	})
+	gt.Expect(err).To(MatchError(ContainSubstring(pemutil.ErrIncorrectPassphrase.Error())))
+
+	var requested string
+	signingIdentity, err := NewSigningIdentityFromEncryptedMSPDir(dir, "Org1MSP", func(path string) ([]byte, error) {
+		requested = path
+		return []byte("secret"), nil
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(requested).To(Equal(keyPath))
+	gt.Expect(signingIdentity.Certificate).To(Equal(cert))
+	gt.Expect(signingIdentity.PrivateKey).To(Equal(privKey))
+}
+
+// pkcs12Bundle holds a P-256 key and its certificate for
+// Admin@org1.example.com, with the password "secret", written by openssl.
+const pkcs12Bundle = `
+MIIDOgIBAzCCAwAGCSqGSIb3DQEHAaCCAvEEggLtMIIC6TCCAd8GCSqGSIb3DQEH
+BqCCAdAwggHMAgEAMIIBxQYJKoZIhvcNAQcBMBwGCiqGSIb3DQEMAQYwDgQI9Duh
+cj+49hQCAggAgIIBmLYsCmXXBYoqeFn1WIbxMOodjVMdqDDTG6VITkY7RWCNGv7v
+gDl437lAVb6X5Ca3xeFFBrtYjHiWN+oJPjy+hdn+FsjihzMo7c5Glkc3cFFP4jKS
+ysfmCIGELQYLjW/IjakN/LTuPXTuxq+n0YCStSYZkrRXiffFUWXXsmKNTxWrTqw7
+1uz/mBhpzzSHc3UeukKpwpWxRJlm5P4ctSVf6bQ0UvZlVPgeKdq4CUuRl85++cTI
+5P9dbNRven2m9P9yi0lfIZT2LwQj9IiHANhmkRtzDYMSgqQw3RsiIKf/Z3G7167o
+/KwHJ+b48Bx7LtX4Th28DqrZBK9+5veCMLnm7qSd1eypmUfD6S9pJgzgIDp0ZmQS
+kWVwOIstDm+zwK6oTnSRBYJjfjOinyvHRXLfpowmmGHuVgyp8jXO0jUMuvRkm+Sw
+SJAQ83zBRi/Lfeu3GWmqoOF1n48bwit3T+0zj4ZL+8lrn1bpD1kI/Nm8/IBiGqVp
+HeCp/57TBL+UUfoE/jx4UfEyzX5wW+KSqseq83hbDHMXeY1OiTCCAQIGCSqGSIb3
+DQEHAaCB9ASB8TCB7jCB6wYLKoZIhvcNAQwKAQKggbQwgbEwHAYKKoZIhvcNAQwB
+AzAOBAjt0Km/kO80OQICCAAEgZBaye1elUHVWlSucdw6H5ozsZ8OKtXTYUT876U+
+Sg4lMtpk7wWa1gpbgv+wafHCJaEtz0M3rYmZtmhF7yB9a9idaT/MaIddAVpyR+6s
+Y+IoVCGQ0egrQrm6dvuts2doBv2IjI6Ho9jVITMTMNCtF4TpR/Nqj2p6oQ28aMF2
++Y3UPh2WN7y65TkfQwKKjAPSndoxJTAjBgkqhkiG9w0BCRUxFgQUQAVCXL7hUIiZ
+oxK1UWrHez0RF6YwMTAhMAkGBSsOAwIaBQAEFPu0Vow4MtVZSBGlh2REBH/9365l
+BAgqLa1LguyCIwICCAA=`
+
+func TestNewSigningIdentityFromPKCS12(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	bundle, err := base64.StdEncoding.DecodeString(pkcs12Bundle)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signingIdentity, err := NewSigningIdentityFromPKCS12(bundle, "secret", "Org1MSP")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(signingIdentity.MSPID).To(Equal("Org1MSP"))
+	gt.Expect(signingIdentity.Certificate.Subject.CommonName).To(Equal("Admin@org1.example.com"))
+	gt.Expect(signingIdentity.PrivateKey.(*ecdsa.PrivateKey).Public()).To(Equal(signingIdentity.Public()))
+
+	_, err = signingIdentity.CreateConfigSignature([]byte("config"))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = NewSigningIdentityFromPKCS12(bundle, "wrong", "Org1MSP")
+	gt.Expect(err).To(MatchError("parsing PKCS#12 bundle: " + pemutil.ErrIncorrectPassphrase.Error()))
+
+	_, err = NewSigningIdentityFromPKCS12(bundle, "secret", "")
+	gt.Expect(err).To(MatchError("msp id is required"))
+}
+
 func TestCreateSignature(t *testing.T) {
 	t.Parallel()
 
@@ -383,6 +528,14 @@ func generateCertAndPrivateKey(t *testing.T, template, parent *x509.Certificate,
 		// create self-signed cert
 		parentPriv = priv
 	}
+	if !template.IsCA && len(template.SubjectKeyId) == 0 {
+		// x509 only derives the subject key identifier of CA certs, give
+		// leaf certs one as well so they can be looked up by it.
+		ski := sha256.Sum256(elliptic.Marshal(priv.Curve, priv.X, priv.Y))
+		leafTemplate := *template
+		leafTemplate.SubjectKeyId = ski[:]
+		template = &leafTemplate
+	}
 	derBytes, err := x509.CreateCertificate(rand.Reader, template, parent, &priv.PublicKey, parentPriv)
 	gt.Expect(err).NotTo(HaveOccurred())
 
@@ -0,0 +1,120 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	ob "github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// SizeWarningRatio is the share of the AbsoluteMaxBytes of the channel above
+// which the estimated size of a config update is logged as approaching the
+// limit.
+const SizeWarningRatio = 0.9
+
+// envelopeSignatureSize is the allowance for the creator, nonce and signature
+// of a signed envelope, which the unsigned envelopes measured by EstimateSize
+// lack.
+const envelopeSignatureSize = 1024
+
+// SizeEstimate is the estimated size of a config update and of the config
+// transaction the orderer produces from it. The orderer rejects both if they
+// exceed the AbsoluteMaxBytes of the channel.
+type SizeEstimate struct {
+	// ConfigBytes is the marshaled size of the updated config.
+	ConfigBytes int
+	// UpdateEnvelopeBytes is the estimated size of the signed config update
+	// envelope submitted to the orderer.
+	UpdateEnvelopeBytes int
+	// ConfigEnvelopeBytes is the estimated size of the signed config
+	// transaction holding the updated config and the config update envelope.
+	ConfigEnvelopeBytes int
+	// AbsoluteMaxBytes is the batch size limit of the original config, which
+	// the orderer enforces when processing the update. It is zero if the
+	// original config has no orderer batch size.
+	AbsoluteMaxBytes uint32
+}
+
+// ExceedsLimit reports whether the config update or the config transaction
+// exceed the AbsoluteMaxBytes of the channel.
+func (s SizeEstimate) ExceedsLimit() bool {
+	return s.AbsoluteMaxBytes != 0 && uint32(s.ConfigEnvelopeBytes) > s.AbsoluteMaxBytes
+}
+
+// EstimateSize estimates the size of the config update between the original
+// and the updated config, signed with the given config signatures, and of the
+// config transaction the orderer produces from it. A warning is logged if the
+// config transaction, the larger of both, exceeds SizeWarningRatio of the
+// AbsoluteMaxBytes of the channel, as oversized updates are rejected by the
+// orderer with an error which does not reveal the cause.
+func (c *ConfigTx) EstimateSize(channelID string, signatures ...*cb.ConfigSignature) (estimate SizeEstimate, err error) {
+	defer c.observe("ConfigTx.EstimateSize", time.Now(), &err)
+
+	marshaledUpdate, err := computeScopedMarshaledUpdate(c.original, c.updated, c.journal.scope(), channelID)
+	if err != nil {
+		return SizeEstimate{}, err
+	}
+
+	updateEnvelope, err := NewEnvelope(marshaledUpdate, signatures...)
+	if err != nil {
+		return SizeEstimate{}, err
+	}
+
+	configEnvelope, err := newEnvelope(cb.HeaderType_CONFIG, channelID, &cb.ConfigEnvelope{
+		Config:     c.updated,
+		LastUpdate: updateEnvelope,
+	})
+	if err != nil {
+		return SizeEstimate{}, err
+	}
+
+	estimate = SizeEstimate{
+		ConfigBytes:         proto.Size(c.updated),
+		UpdateEnvelopeBytes: proto.Size(updateEnvelope) + envelopeSignatureSize,
+		ConfigEnvelopeBytes: proto.Size(configEnvelope) + 2*envelopeSignatureSize,
+	}
+
+	if ordererGroup, ok := c.original.ChannelGroup.Groups[OrdererGroupKey]; ok {
+		if _, ok := ordererGroup.Values[orderer.BatchSizeKey]; ok {
+			batchSize := &ob.BatchSize{}
+			err = unmarshalConfigValueAtKey(ordererGroup, orderer.BatchSizeKey, batchSize)
+			if err != nil {
+				return SizeEstimate{}, err
+			}
+			estimate.AbsoluteMaxBytes = batchSize.AbsoluteMaxBytes
+		}
+	}
+
+	c.warnSize(estimate)
+
+	return estimate, nil
+}
+
+// warnSize warns if the config transaction of the estimate approaches or
+// exceeds the AbsoluteMaxBytes of the channel.
+func (c *ConfigTx) warnSize(estimate SizeEstimate) {
+	limit := estimate.AbsoluteMaxBytes
+	if limit == 0 || float64(estimate.ConfigEnvelopeBytes) <= SizeWarningRatio*float64(limit) {
+		return
+	}
+
+	msg := "config update approaches the absolute max bytes of the channel"
+	if estimate.ExceedsLimit() {
+		msg = "config update exceeds the absolute max bytes of the channel"
+	}
+
+	c.observed().child(fmt.Sprintf("%s/Values/%s", OrdererGroupKey, orderer.BatchSizeKey)).warn(msg,
+		"config_envelope_bytes", estimate.ConfigEnvelopeBytes,
+		"update_envelope_bytes", estimate.UpdateEnvelopeBytes,
+		"absolute_max_bytes", orderer.FormatByteSize(limit),
+	)
+}
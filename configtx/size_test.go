@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	. "github.com/onsi/gomega"
+)
+
+func sizeConfigTx(t *testing.T, absoluteMaxBytes uint32) ConfigTx {
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	err := setValue(c.original.ChannelGroup.Groups[OrdererGroupKey], batchSizeValue(100, absoluteMaxBytes, 1), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c = New(c.original)
+
+	err = c.Orderer().SetBatchTimeout(5 * time.Second)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return c
+}
+
+func TestEstimateSize(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := sizeConfigTx(t, 10*1024*1024)
+	logger := &recordingLogger{}
+	c.SetLogger(logger)
+
+	estimate, err := c.EstimateSize("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(estimate.AbsoluteMaxBytes).To(Equal(uint32(10 * 1024 * 1024)))
+	gt.Expect(estimate.ConfigBytes).To(BeNumerically(">", 0))
+	gt.Expect(estimate.UpdateEnvelopeBytes).To(BeNumerically(">", envelopeSignatureSize))
+	gt.Expect(estimate.ConfigEnvelopeBytes).To(BeNumerically(">", estimate.ConfigBytes+estimate.UpdateEnvelopeBytes))
+	gt.Expect(estimate.ExceedsLimit()).To(BeFalse())
+	gt.Expect(logger.warnings).To(BeEmpty())
+
+	cert, privateKey := generateCACertAndPrivateKey(t, "org1.example.com")
+	signingIdentity := SigningIdentity{Certificate: cert, PrivateKey: privateKey, MSPID: "Org1MSP"}
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	signature, err := signingIdentity.CreateConfigSignature(marshaledUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signed, err := c.EstimateSize("testchannel", signature)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(signed.UpdateEnvelopeBytes).To(BeNumerically(">", estimate.UpdateEnvelopeBytes))
+	gt.Expect(signed.ConfigEnvelopeBytes - estimate.ConfigEnvelopeBytes).To(BeNumerically(">=", signed.UpdateEnvelopeBytes-estimate.UpdateEnvelopeBytes))
+}
+
+func TestEstimateSizeWarnings(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := sizeConfigTx(t, 10*1024*1024)
+	estimate, err := c.EstimateSize("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	// The certificates of each config are generated anew and vary slightly
+	// in size, so the limits keep a margin around the estimate.
+	tests := []struct {
+		testName         string
+		absoluteMaxBytes uint32
+		expectedMsg      string
+	}{
+		{
+			testName:         "when the limit is approached",
+			absoluteMaxBytes: uint32(estimate.ConfigEnvelopeBytes) * 105 / 100,
+			expectedMsg:      "config update approaches the absolute max bytes of the channel",
+		},
+		{
+			testName:         "when the limit is exceeded",
+			absoluteMaxBytes: uint32(estimate.ConfigEnvelopeBytes) * 95 / 100,
+			expectedMsg:      "config update exceeds the absolute max bytes of the channel",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := sizeConfigTx(t, tt.absoluteMaxBytes)
+			logger := &recordingLogger{}
+			c.SetLogger(logger)
+
+			estimate, err := c.EstimateSize("testchannel")
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(estimate.ExceedsLimit()).To(Equal(estimate.ConfigEnvelopeBytes > int(tt.absoluteMaxBytes)))
+			gt.Expect(logger.warnings).To(HaveLen(1))
+			gt.Expect(logger.warnings[0].msg).To(Equal(tt.expectedMsg))
+			gt.Expect(logger.warnings[0].keyvals[:2]).To(Equal([]interface{}{"path", "/Channel/Orderer/Values/BatchSize"}))
+		})
+	}
+}
+
+func TestEstimateSizeFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := sizeConfigTx(t, 10*1024*1024)
+	_, err := c.EstimateSize("")
+	gt.Expect(err).To(MatchError("channel ID is required"))
+
+	c.original.ChannelGroup.Groups[OrdererGroupKey].Values["BatchSize"] = &cb.ConfigValue{Value: []byte("{")}
+	_, err = c.EstimateSize("testchannel")
+	gt.Expect(err).To(MatchError("unmarshaling BatchSize: unexpected EOF"))
+}
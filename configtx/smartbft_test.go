@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx_test
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx"
+	"github.com/hyperledger/fabric-config/configtx/configtxtest"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	"github.com/hyperledger/fabric-config/configtx/pemutil"
+	"github.com/hyperledger/fabric-config/configtx/smartbfttest"
+	. "github.com/onsi/gomega"
+)
+
+// newSmartBFTConsenter returns a consenter of the orderer org of the fixture
+// whose identity carries the given OU and whose TLS cert is issued for
+// tlsHost.
+func newSmartBFTConsenter(t *testing.T, g *configtxtest.Generator, f *smartbfttest.Fixture, id uint64, host, ou, tlsHost string) orderer.SmartBFTConsenter {
+	gt := NewGomegaWithT(t)
+
+	identity, _, err := g.NodeCert(host, ou, f.OrdererOrg.CA, f.OrdererOrg.CAKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+	tlsCert, _, err := g.Cert(tlsHost, f.OrdererOrg.CA, f.OrdererOrg.CAKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return orderer.SmartBFTConsenter{
+		ID:            id,
+		Address:       orderer.EtcdAddress{Host: host, Port: smartbfttest.ConsenterPort},
+		MSPID:         smartbfttest.OrdererMSPID,
+		Identity:      identity,
+		ClientTLSCert: tlsCert,
+		ServerTLSCert: tlsCert,
+	}
+}
+
+func TestSmartBFTConsentersAndBlockValidationPolicy(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	g := &configtxtest.Generator{}
+	f, err := smartbfttest.New(g, 4)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := f.ConfigTx()
+	o := c.Orderer()
+
+	blockValidationPolicy := func() *cb.SignaturePolicyEnvelope {
+		configPolicy := c.UpdatedConfig().ChannelGroup.Groups[configtx.OrdererGroupKey].Policies[configtx.BlockValidationPolicyKey]
+		gt.Expect(configPolicy.ModPolicy).To(Equal(configtx.AdminsPolicyKey))
+		gt.Expect(configPolicy.Policy.Type).To(Equal(int32(cb.Policy_SIGNATURE)))
+		envelope := &cb.SignaturePolicyEnvelope{}
+		gt.Expect(proto.Unmarshal(configPolicy.Policy.Value, envelope)).To(Succeed())
+		return envelope
+	}
+
+	policy := blockValidationPolicy()
+	gt.Expect(policy.Rule.GetNOutOf().N).To(Equal(int32(3)))
+	gt.Expect(policy.Rule.GetNOutOf().Rules).To(HaveLen(4))
+	gt.Expect(policy.Identities).To(HaveLen(4))
+	gt.Expect(policy.Identities[0].PrincipalClassification).To(Equal(mb.MSPPrincipal_IDENTITY))
+	serializedIdentity := &mb.SerializedIdentity{}
+	gt.Expect(proto.Unmarshal(policy.Identities[0].Principal, serializedIdentity)).To(Succeed())
+	gt.Expect(serializedIdentity.Mspid).To(Equal(smartbfttest.OrdererMSPID))
+	gt.Expect(serializedIdentity.IdBytes).To(Equal(pemutil.EncodeCertificate(f.Consenters[0].Identity)))
+
+	consenter := newSmartBFTConsenter(t, g, f, 5, "orderer4.example.com", "orderer", "orderer4.example.com")
+	err = o.AddSmartBFTConsenter(consenter)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = o.AddSmartBFTConsenter(consenter)
+	gt.Expect(err).To(MatchError("consenter with id 5 already exists"))
+
+	ordererConfig, err := o.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.SmartBFT.Consenters).To(HaveLen(5))
+	gt.Expect(ordererConfig.SmartBFT.Consenters[4]).To(Equal(consenter))
+	policy = blockValidationPolicy()
+	gt.Expect(policy.Rule.GetNOutOf().N).To(Equal(int32(4)))
+	gt.Expect(policy.Identities).To(HaveLen(5))
+
+	for _, id := range []uint64{1, 2, 3} {
+		err = o.RemoveSmartBFTConsenter(id)
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	err = o.RemoveSmartBFTConsenter(1)
+	gt.Expect(err).To(MatchError("consenter with id 1 does not exist"))
+
+	ordererConfig, err = o.Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.SmartBFT.Consenters).To(HaveLen(2))
+	policy = blockValidationPolicy()
+	gt.Expect(policy.Rule.GetNOutOf().N).To(Equal(int32(2)))
+	gt.Expect(policy.Identities).To(HaveLen(2))
+}
+
+func TestSmartBFTConsenterChecks(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	g := &configtxtest.Generator{}
+	f, err := smartbfttest.New(g, 4)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := f.ConfigTx()
+
+	consenter := newSmartBFTConsenter(t, g, f, 5, "orderer4.example.com", "client", "orderer4.example.com")
+	err = c.Orderer().WithConsenterChecks().AddSmartBFTConsenter(consenter)
+	gt.Expect(err).To(MatchError("identity cert of consenter 5 does not carry the orderer OU orderer of MSP OrdererMSP"))
+
+	consenter = newSmartBFTConsenter(t, g, f, 5, "orderer5.example.com", "orderer", "orderer4.example.com")
+	err = c.Orderer().WithConsenterChecks().AddSmartBFTConsenter(consenter)
+	gt.Expect(err).To(MatchError("server tls cert of consenter orderer5.example.com:7050 does not match its host: x509: certificate is valid for orderer4.example.com, not orderer5.example.com"))
+
+	consenter = newSmartBFTConsenter(t, g, f, 5, "orderer4.example.com", "orderer", "orderer4.example.com")
+	err = c.Orderer().WithConsenterChecks().AddSmartBFTConsenter(consenter)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	cfg, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(cfg.SmartBFT.Consenters).To(HaveLen(5))
+}
@@ -0,0 +1,294 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package smartbfttest provides the fixture of a baseline SmartBFT application
+// channel, so that tests can exercise BFT paths without assembling consenter
+// identities, consensus metadata and the BlockValidation policy by hand.
+package smartbfttest
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx"
+	"github.com/hyperledger/fabric-config/configtx/configtxtest"
+	"github.com/hyperledger/fabric-config/configtx/membership"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+const (
+	// ChannelID is the ID of the channel of the fixture.
+	ChannelID = "bftchannel"
+
+	// OrdererOrgName and OrdererMSPID identify the orderer org of the
+	// fixture, to which all consenters belong.
+	OrdererOrgName = "OrdererOrg"
+	OrdererMSPID   = "OrdererMSP"
+
+	// ApplicationOrgName and ApplicationMSPID identify the application org of
+	// the fixture.
+	ApplicationOrgName = "Org1"
+	ApplicationMSPID   = "Org1MSP"
+
+	// ConsenterPort is the port of the consenters and orderer endpoints.
+	ConsenterPort = 7050
+)
+
+// Org is an org of the fixture along with the CA issuing its certificates
+// and the certificate and private key of an admin of the org.
+type Org struct {
+	configtx.Organization
+	CA       *x509.Certificate
+	CAKey    *ecdsa.PrivateKey
+	Admin    *x509.Certificate
+	AdminKey *ecdsa.PrivateKey
+}
+
+// Consenter is a consenter of the fixture along with the private keys of its
+// identity and TLS certificates. Its identity carries the orderer OU of the
+// orderer org MSP.
+type Consenter struct {
+	orderer.SmartBFTConsenter
+	IdentityKey *ecdsa.PrivateKey
+	TLSKey      *ecdsa.PrivateKey
+}
+
+// Fixture is a baseline SmartBFT application channel. Both orgs enable
+// NodeOUs and the BlockValidation policy of Config requires the signatures of
+// a BFT quorum of the consenters.
+type Fixture struct {
+	// Profile is the definition Config is built from. Since its
+	// BlockValidation policy cannot refer to the consenter identities, it is
+	// a placeholder which is replaced in Config.
+	Profile        configtx.Channel
+	Config         *cb.Config
+	OrdererOrg     Org
+	ApplicationOrg Org
+	Consenters     []Consenter
+}
+
+// New returns a fixture with the given count of consenters whose
+// certificates and keys are generated by g. Using a seeded generator makes
+// the fixture reproducible.
+func New(g *configtxtest.Generator, consenterCount int) (*Fixture, error) {
+	if consenterCount < 1 {
+		return nil, errors.New("at least one consenter is required")
+	}
+
+	ordererOrg, err := newOrg(g, OrdererOrgName, OrdererMSPID)
+	if err != nil {
+		return nil, err
+	}
+
+	applicationOrg, err := newOrg(g, ApplicationOrgName, ApplicationMSPID)
+	if err != nil {
+		return nil, err
+	}
+	applicationOrg.Policies[configtx.EndorsementPolicyKey] = configtx.Policy{
+		Type: configtx.SignaturePolicyType,
+		Rule: fmt.Sprintf("OR('%s.peer')", ApplicationMSPID),
+	}
+
+	consenters := make([]Consenter, consenterCount)
+	smartBFTConsenters := make([]orderer.SmartBFTConsenter, consenterCount)
+	for i := range consenters {
+		host := fmt.Sprintf("orderer%d.example.com", i)
+
+		identity, identityKey, err := g.NodeCert(host, "orderer", ordererOrg.CA, ordererOrg.CAKey)
+		if err != nil {
+			return nil, fmt.Errorf("generating identity of consenter %d: %v", i+1, err)
+		}
+
+		tlsCert, tlsKey, err := g.Cert(host, ordererOrg.CA, ordererOrg.CAKey)
+		if err != nil {
+			return nil, fmt.Errorf("generating tls cert of consenter %d: %v", i+1, err)
+		}
+
+		consenters[i] = Consenter{
+			SmartBFTConsenter: orderer.SmartBFTConsenter{
+				ID:            uint64(i + 1),
+				Address:       orderer.EtcdAddress{Host: host, Port: ConsenterPort},
+				MSPID:         OrdererMSPID,
+				Identity:      identity,
+				ClientTLSCert: tlsCert,
+				ServerTLSCert: tlsCert,
+			},
+			IdentityKey: identityKey,
+			TLSKey:      tlsKey,
+		}
+		smartBFTConsenters[i] = consenters[i].SmartBFTConsenter
+		ordererOrg.OrdererEndpoints = append(ordererOrg.OrdererEndpoints, fmt.Sprintf("%s:%d", host, ConsenterPort))
+	}
+
+	profile := configtx.Channel{
+		Application: configtx.Application{
+			Organizations: []configtx.Organization{applicationOrg.Organization},
+			Capabilities:  []string{"V2_0"},
+			Policies: implicitMetaPolicies(map[string]string{
+				configtx.EndorsementPolicyKey:          "MAJORITY Endorsement",
+				configtx.LifecycleEndorsementPolicyKey: "MAJORITY Endorsement",
+			}),
+		},
+		Orderer: configtx.Orderer{
+			OrdererType:  orderer.ConsensusTypeSmartBFT,
+			BatchTimeout: 2 * time.Second,
+			BatchSize: orderer.BatchSize{
+				MaxMessageCount:   500,
+				AbsoluteMaxBytes:  10 * 1024 * 1024,
+				PreferredMaxBytes: 2 * 1024 * 1024,
+			},
+			SmartBFT: orderer.SmartBFT{
+				Consenters: smartBFTConsenters,
+				Options:    Options(),
+			},
+			Organizations: []configtx.Organization{ordererOrg.Organization},
+			Capabilities:  []string{"V2_0"},
+			Policies: implicitMetaPolicies(map[string]string{
+				configtx.BlockValidationPolicyKey: "ANY Writers",
+			}),
+			State: orderer.ConsensusStateNormal,
+		},
+		Capabilities: []string{"V3_0"},
+		Policies:     implicitMetaPolicies(nil),
+	}
+
+	config, err := newConfig(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Fixture{
+		Profile:        profile,
+		Config:         config,
+		OrdererOrg:     ordererOrg,
+		ApplicationOrg: applicationOrg,
+		Consenters:     consenters,
+	}, nil
+}
+
+// ConfigTx returns a config transaction modifying a copy of the config of
+// the fixture.
+func (f *Fixture) ConfigTx() configtx.ConfigTx {
+	return configtx.New(proto.Clone(f.Config).(*cb.Config))
+}
+
+// Options returns the SmartBFT options of the fixture. They are the
+// configtx.DefaultSmartBFTOptions, except that leader rotation is enabled
+// every 3 decisions, as in the SmartBFT section of the Fabric sample
+// configuration, so that the fixture exercises the rotation options rather
+// than leaving them unspecified.
+func Options() orderer.SmartBFTOptions {
+	options := configtx.DefaultSmartBFTOptions()
+	options.LeaderRotation = orderer.SmartBFTLeaderRotationOn
+	options.DecisionsPerLeader = 3
+
+	return options
+}
+
+// newOrg returns an org whose MSP enables NodeOUs, along with its CA and an
+// admin identity.
+func newOrg(g *configtxtest.Generator, name, mspID string) (Org, error) {
+	ca, caKey, err := g.CA(name)
+	if err != nil {
+		return Org{}, fmt.Errorf("generating CA of org %s: %v", name, err)
+	}
+
+	admin, adminKey, err := g.NodeCert("admin."+name, "admin", ca, caKey)
+	if err != nil {
+		return Org{}, fmt.Errorf("generating admin of org %s: %v", name, err)
+	}
+
+	return Org{
+		Organization: configtx.Organization{
+			Name: name,
+			Policies: map[string]configtx.Policy{
+				configtx.ReadersPolicyKey: {Type: configtx.SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.member')", mspID)},
+				configtx.WritersPolicyKey: {Type: configtx.SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.member')", mspID)},
+				configtx.AdminsPolicyKey:  {Type: configtx.SignaturePolicyType, Rule: fmt.Sprintf("OR('%s.admin')", mspID)},
+			},
+			MSP: configtx.MSP{
+				Name:         mspID,
+				RootCerts:    []*x509.Certificate{ca},
+				TLSRootCerts: []*x509.Certificate{ca},
+				CryptoConfig: membership.CryptoConfig{
+					SignatureHashFamily:            "SHA2",
+					IdentityIdentifierHashFunction: "SHA256",
+				},
+				NodeOUs: membership.NodeOUs{
+					Enable:              true,
+					ClientOUIdentifier:  membership.OUIdentifier{Certificate: ca, OrganizationalUnitIdentifier: "client"},
+					PeerOUIdentifier:    membership.OUIdentifier{Certificate: ca, OrganizationalUnitIdentifier: "peer"},
+					AdminOUIdentifier:   membership.OUIdentifier{Certificate: ca, OrganizationalUnitIdentifier: "admin"},
+					OrdererOUIdentifier: membership.OUIdentifier{Certificate: ca, OrganizationalUnitIdentifier: "orderer"},
+				},
+			},
+		},
+		CA:       ca,
+		CAKey:    caKey,
+		Admin:    admin,
+		AdminKey: adminKey,
+	}, nil
+}
+
+// implicitMetaPolicies returns the standard Readers, Writers and Admins
+// implicit meta policies along with the given additional ones.
+func implicitMetaPolicies(additional map[string]string) map[string]configtx.Policy {
+	rules := map[string]string{
+		configtx.ReadersPolicyKey: "ANY Readers",
+		configtx.WritersPolicyKey: "ANY Writers",
+		configtx.AdminsPolicyKey:  "MAJORITY Admins",
+	}
+	for name, rule := range additional {
+		rules[name] = rule
+	}
+
+	policies := map[string]configtx.Policy{}
+	for name, rule := range rules {
+		policies[name] = configtx.Policy{Type: configtx.ImplicitMetaPolicyType, Rule: rule}
+	}
+
+	return policies
+}
+
+// newConfig builds the config of the profile and replaces its placeholder
+// BlockValidation policy with the BFT policy of the consenters.
+func newConfig(profile configtx.Channel) (*cb.Config, error) {
+	block, err := configtx.NewApplicationChannelGenesisBlock(profile, ChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("creating genesis block: %v", err)
+	}
+
+	envelope := &cb.Envelope{}
+	err = proto.Unmarshal(block.Data.Data[0], envelope)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling envelope: %v", err)
+	}
+
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(envelope.Payload, payload)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling payload: %v", err)
+	}
+
+	configEnvelope := &cb.ConfigEnvelope{}
+	err = proto.Unmarshal(payload.Data, configEnvelope)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config envelope: %v", err)
+	}
+
+	c := configtx.New(configEnvelope.Config)
+	err = c.Orderer().SetBFTBlockValidationPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("setting BlockValidation policy: %v", err)
+	}
+
+	return c.UpdatedConfig(), nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package smartbfttest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx"
+	"github.com/hyperledger/fabric-config/configtx/configtxtest"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+	. "github.com/onsi/gomega"
+)
+
+func TestFixture(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	f, err := New(&configtxtest.Generator{}, 4)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(f.Consenters).To(HaveLen(4))
+
+	c := f.ConfigTx()
+	ordererConfig, err := c.Orderer().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererConfig.OrdererType).To(Equal(orderer.ConsensusTypeSmartBFT))
+	gt.Expect(ordererConfig.SmartBFT.Options).To(Equal(Options()))
+	gt.Expect(ordererConfig.SmartBFT.Consenters).To(HaveLen(4))
+	for i, consenter := range f.Consenters {
+		gt.Expect(ordererConfig.SmartBFT.Consenters[i]).To(Equal(consenter.SmartBFTConsenter))
+	}
+	gt.Expect(ordererConfig.Policies[configtx.BlockValidationPolicyKey].Type).To(Equal(configtx.SignaturePolicyType))
+
+	resolved, err := c.ResolveIdentity(f.Consenters[0].Identity)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(resolved).To(HaveLen(1))
+	gt.Expect(resolved[0].MSPID).To(Equal(OrdererMSPID))
+	gt.Expect(resolved[0].Roles).To(Equal([]string{"member", "orderer"}))
+	gt.Expect(resolved[0].Problems).To(BeEmpty())
+
+	// The BlockValidation policy of the config is already the BFT policy of
+	// the consenters.
+	err = c.Orderer().SetBFTBlockValidationPolicy()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(c.UpdatedConfig(), f.Config)).To(BeTrue())
+
+	err = c.Orderer().WithConsenterChecks().SetSmartBFTConsensusType(ordererConfig.SmartBFT, ordererConfig.State)
+	gt.Expect(err).NotTo(HaveOccurred())
+}
+
+func TestFixtureIsReproducible(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	now := time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)
+	f1, err := New(configtxtest.NewSeededGenerator(42, now), 4)
+	gt.Expect(err).NotTo(HaveOccurred())
+	f2, err := New(configtxtest.NewSeededGenerator(42, now), 4)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	gt.Expect(proto.Equal(f1.Config, f2.Config)).To(BeTrue())
+}
+
+func TestFixtureFailure(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	_, err := New(&configtxtest.Generator{}, 0)
+	gt.Expect(err).To(MatchError("at least one consenter is required"))
+}
@@ -0,0 +1,213 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+)
+
+// StaleElement is an element of a config update which changed in a newer
+// config after the update was computed. The orderer rejects the update with a
+// version mismatch because of it.
+type StaleElement struct {
+	// Path identifies the element as in ConfigChange, e.g. /Channel/Orderer
+	// or /Channel/Orderer/Values/BatchTimeout.
+	Path string
+	// Kind is how the element changed underneath the update: added if the
+	// update adds an element which now exists, removed if the update reads or
+	// modifies an element which no longer exists and modified if the version
+	// of the element changed.
+	Kind ConfigChangeKind
+	// ExpectedVersion is the version the update expects the element to have.
+	// It is zero for added elements.
+	ExpectedVersion uint64
+	// CurrentVersion is the version of the element in the newer config. It is
+	// zero for removed elements.
+	CurrentVersion uint64
+}
+
+// ComputeStaleElements compares the versions the marshaled ConfigUpdate was
+// computed against, as recorded in its read set and write set, with the config
+// of a newer config block and returns every element which changed underneath
+// it, in path order. If none are returned, the update still applies to the
+// newer config as far as versions are concerned.
+func ComputeStaleElements(marshaledUpdate, configBlockBytes []byte) ([]StaleElement, error) {
+	update := &cb.ConfigUpdate{}
+	err := proto.Unmarshal(marshaledUpdate, update)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshaling config update: %v", err)
+	}
+
+	config, err := unmarshalConfigFromBlockBytes(configBlockBytes)
+	if err != nil {
+		return nil, fmt.Errorf("config block: %v", err)
+	}
+
+	return staleElements(update, config)
+}
+
+// staleElements returns the elements of the update which changed in the
+// config.
+func staleElements(update *cb.ConfigUpdate, config *cb.Config) ([]StaleElement, error) {
+	if config.ChannelGroup == nil {
+		return nil, errors.New("no channel group included for config")
+	}
+
+	if update.WriteSet == nil {
+		return nil, errors.New("config update does not contain a write set")
+	}
+
+	var stale []StaleElement
+	staleGroupElements("/"+ChannelGroupKey, config.ChannelGroup, update.ReadSet, update.WriteSet, &stale)
+
+	return stale, nil
+}
+
+// staleGroupElements appends the elements of the read set and write set of
+// the group at path which changed in the current group to stale. Elements
+// which are part of the read set are checked against their read version, the
+// others against the version preceding their write version, as the orderer
+// does. current is nil if the group does not exist in the config.
+func staleGroupElements(path string, current, readSet, writeSet *cb.ConfigGroup, stale *[]StaleElement) {
+	var element *StaleElement
+	switch {
+	case readSet != nil:
+		element = staleRead(path, current != nil, readSet.Version, currentGroupVersion(current))
+	case writeSet != nil:
+		element = staleWrite(path, current != nil, writeSet.Version, currentGroupVersion(current))
+	}
+	appendStale(stale, element)
+	if current == nil || (element != nil && element.Kind == ConfigChangeAdded) {
+		// the members of removed and added groups are covered by the finding
+		// of the group, and those of new groups cannot be stale
+		return
+	}
+
+	for _, name := range sortedMemberKeys(readSet, writeSet, sortedValueKeys) {
+		existing, ok := current.Values[name]
+		memberPath := fmt.Sprintf("%s/Values/%s", path, name)
+		if read, inReadSet := memberValue(readSet, name); inReadSet {
+			appendStale(stale, staleRead(memberPath, ok, read.Version, existing.GetVersion()))
+			continue
+		}
+		written, _ := memberValue(writeSet, name)
+		appendStale(stale, staleWrite(memberPath, ok, written.Version, existing.GetVersion()))
+	}
+
+	for _, name := range sortedMemberKeys(readSet, writeSet, sortedPolicyKeys) {
+		existing, ok := current.Policies[name]
+		memberPath := fmt.Sprintf("%s/Policies/%s", path, name)
+		if read, inReadSet := memberPolicy(readSet, name); inReadSet {
+			appendStale(stale, staleRead(memberPath, ok, read.Version, existing.GetVersion()))
+			continue
+		}
+		written, _ := memberPolicy(writeSet, name)
+		appendStale(stale, staleWrite(memberPath, ok, written.Version, existing.GetVersion()))
+	}
+
+	for _, name := range sortedMemberKeys(readSet, writeSet, sortedGroupKeys) {
+		var subReadSet, subWriteSet *cb.ConfigGroup
+		if readSet != nil {
+			subReadSet = readSet.Groups[name]
+		}
+		if writeSet != nil {
+			subWriteSet = writeSet.Groups[name]
+		}
+		staleGroupElements(path+"/"+name, current.Groups[name], subReadSet, subWriteSet, stale)
+	}
+}
+
+// staleRead returns the finding for an element read at readVersion, or nil if
+// it is unchanged.
+func staleRead(path string, exists bool, readVersion, currentVersion uint64) *StaleElement {
+	switch {
+	case !exists:
+		return &StaleElement{Path: path, Kind: ConfigChangeRemoved, ExpectedVersion: readVersion}
+	case currentVersion != readVersion:
+		return &StaleElement{Path: path, Kind: ConfigChangeModified, ExpectedVersion: readVersion, CurrentVersion: currentVersion}
+	default:
+		return nil
+	}
+}
+
+// staleWrite returns the finding for an element written at writeVersion
+// without being read, or nil if the write still applies. An element written
+// at version zero is added by the update, any other element must be at the
+// preceding version.
+func staleWrite(path string, exists bool, writeVersion, currentVersion uint64) *StaleElement {
+	switch {
+	case writeVersion == 0 && exists:
+		return &StaleElement{Path: path, Kind: ConfigChangeAdded, CurrentVersion: currentVersion}
+	case writeVersion == 0:
+		return nil
+	case !exists:
+		return &StaleElement{Path: path, Kind: ConfigChangeRemoved, ExpectedVersion: writeVersion - 1}
+	case currentVersion+1 != writeVersion:
+		return &StaleElement{Path: path, Kind: ConfigChangeModified, ExpectedVersion: writeVersion - 1, CurrentVersion: currentVersion}
+	default:
+		return nil
+	}
+}
+
+// appendStale appends the finding to stale unless it is nil.
+func appendStale(stale *[]StaleElement, element *StaleElement) {
+	if element != nil {
+		*stale = append(*stale, *element)
+	}
+}
+
+func currentGroupVersion(group *cb.ConfigGroup) uint64 {
+	if group == nil {
+		return 0
+	}
+
+	return group.Version
+}
+
+func memberValue(group *cb.ConfigGroup, name string) (*cb.ConfigValue, bool) {
+	if group == nil {
+		return nil, false
+	}
+
+	value, ok := group.Values[name]
+	return value, ok
+}
+
+func memberPolicy(group *cb.ConfigGroup, name string) (*cb.ConfigPolicy, bool) {
+	if group == nil {
+		return nil, false
+	}
+
+	policy, ok := group.Policies[name]
+	return policy, ok
+}
+
+// sortedMemberKeys returns the union of the keys of both groups returned by
+// keys, in order. Either group may be nil.
+func sortedMemberKeys(a, b *cb.ConfigGroup, keys func(*cb.ConfigGroup) []string) []string {
+	seen := map[string]bool{}
+	var union []string
+	for _, group := range []*cb.ConfigGroup{a, b} {
+		if group == nil {
+			continue
+		}
+		for _, key := range keys(group) {
+			if !seen[key] {
+				seen[key] = true
+				union = append(union, key)
+			}
+		}
+	}
+	sort.Strings(union)
+
+	return union
+}
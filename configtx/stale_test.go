@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+// staleUpdate returns the marshaled update applying the changes to the
+// original config.
+func staleUpdate(t *testing.T, original *cb.Config, changes ...Change) []byte {
+	gt := NewGomegaWithT(t)
+
+	c := New(proto.Clone(original).(*cb.Config))
+	err := c.Apply(changes)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return marshaledUpdate
+}
+
+// staleConfigBlock returns the marshaled config block committing the config
+// resulting from applying the changes to the original config.
+func staleConfigBlock(t *testing.T, original *cb.Config, changes ...Change) []byte {
+	gt := NewGomegaWithT(t)
+
+	config := original
+	if len(changes) != 0 {
+		update := &cb.ConfigUpdate{}
+		err := proto.Unmarshal(staleUpdate(t, original, changes...), update)
+		gt.Expect(err).NotTo(HaveOccurred())
+		config, err = applyConfigUpdate(original, update)
+		gt.Expect(err).NotTo(HaveOccurred())
+	}
+
+	block, err := proto.Marshal(newTestConfigBlock(t, newBlock(0, nil), config, nil))
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return block
+}
+
+func TestComputeStaleElements(t *testing.T) {
+	t.Parallel()
+
+	c := applyConfigTx(t)
+	original := c.OriginalConfig()
+	org3 := baseApplicationOrg(t)
+	org3.Name = "Org3"
+
+	tests := []struct {
+		testName      string
+		pending       []Change
+		committed     []Change
+		expectedStale []StaleElement
+	}{
+		{
+			testName:  "When the config did not change",
+			pending:   []Change{SetBatchTimeout{Timeout: 5 * time.Second}},
+			committed: nil,
+		},
+		{
+			testName:  "When unrelated elements changed",
+			pending:   []Change{SetBatchTimeout{Timeout: 5 * time.Second}},
+			committed: []Change{SetPolicy{Group: "Application/Org1", Name: "Custom", Policy: Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.admin')", ModPolicy: AdminsPolicyKey}}},
+		},
+		{
+			testName:  "When a modified value changed",
+			pending:   []Change{SetBatchTimeout{Timeout: 5 * time.Second}},
+			committed: []Change{SetBatchTimeout{Timeout: 3 * time.Second}},
+			expectedStale: []StaleElement{
+				{Path: "/Channel/Orderer/Values/BatchTimeout", Kind: ConfigChangeModified, ExpectedVersion: 0, CurrentVersion: 1},
+			},
+		},
+		{
+			testName:  "When an added group was added",
+			pending:   []Change{AddApplicationOrg{Org: org3}},
+			committed: []Change{AddApplicationOrg{Org: org3}},
+			expectedStale: []StaleElement{
+				{Path: "/Channel/Application", Kind: ConfigChangeModified, ExpectedVersion: 0, CurrentVersion: 1},
+				{Path: "/Channel/Application/Org3", Kind: ConfigChangeAdded, CurrentVersion: 0},
+			},
+		},
+		{
+			testName:  "When a modified group was removed",
+			pending:   []Change{SetPolicy{Group: "Application/Org2", Name: "Custom", Policy: Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.admin')", ModPolicy: AdminsPolicyKey}}},
+			committed: []Change{RemoveApplicationOrg{Name: "Org2"}},
+			expectedStale: []StaleElement{
+				{Path: "/Channel/Application", Kind: ConfigChangeModified, ExpectedVersion: 0, CurrentVersion: 1},
+				{Path: "/Channel/Application/Org2", Kind: ConfigChangeRemoved, ExpectedVersion: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			stale, err := ComputeStaleElements(staleUpdate(t, original, tt.pending...), staleConfigBlock(t, original, tt.committed...))
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(stale).To(Equal(tt.expectedStale))
+		})
+	}
+}
+
+func TestComputeStaleElementsFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	original := c.OriginalConfig()
+	marshaledUpdate := staleUpdate(t, original, SetBatchTimeout{Timeout: 5 * time.Second})
+	configBlock := staleConfigBlock(t, original)
+
+	_, err := ComputeStaleElements([]byte("garbage"), configBlock)
+	gt.Expect(err).To(MatchError(HavePrefix("unmarshaling config update: ")))
+
+	_, err = ComputeStaleElements(marshaledUpdate, []byte("garbage"))
+	gt.Expect(err).To(MatchError(HavePrefix("config block: unmarshaling block: ")))
+
+	emptyUpdate, err := proto.Marshal(&cb.ConfigUpdate{ChannelId: "testchannel"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	_, err = ComputeStaleElements(emptyUpdate, configBlock)
+	gt.Expect(err).To(MatchError("config update does not contain a write set"))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+)
+
+// DefaultMaxConfigSize is a suggested limit in bytes for ReadConfigFrom and
+// WriteUpdateTo. It matches the default maximum gRPC message size of a
+// Fabric orderer.
+const DefaultMaxConfigSize = 100 * 1024 * 1024
+
+// ReadConfigFrom reads a marshaled Config protobuf from r until EOF and
+// returns a ConfigTx for it. An error is returned if more than maxBytes
+// bytes are available.
+func ReadConfigFrom(r io.Reader, maxBytes int64) (ConfigTx, error) {
+	if r == nil {
+		return ConfigTx{}, errors.New("reader is required")
+	}
+	if maxBytes <= 0 {
+		return ConfigTx{}, fmt.Errorf("maximum size must be positive, got %d", maxBytes)
+	}
+
+	configBytes, err := ioutil.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return ConfigTx{}, fmt.Errorf("reading config: %v", err)
+	}
+	if int64(len(configBytes)) > maxBytes {
+		return ConfigTx{}, fmt.Errorf("config exceeds the maximum size of %d bytes", maxBytes)
+	}
+
+	config := &cb.Config{}
+	err = proto.Unmarshal(configBytes, config)
+	if err != nil {
+		return ConfigTx{}, fmt.Errorf("unmarshaling config: %v", err)
+	}
+	if config.ChannelGroup == nil {
+		return ConfigTx{}, errors.New("config does not contain a channel group")
+	}
+
+	return New(config), nil
+}
+
+// WriteUpdateTo computes the ConfigUpdate from the original and updated config
+// and writes the marshaled bytes to w. It returns the number of bytes written.
+// An error is returned without writing anything if the marshaled update
+// exceeds maxBytes bytes.
+func (c *ConfigTx) WriteUpdateTo(w io.Writer, channelID string, maxBytes int64) (int64, error) {
+	if w == nil {
+		return 0, errors.New("writer is required")
+	}
+	if maxBytes <= 0 {
+		return 0, fmt.Errorf("maximum size must be positive, got %d", maxBytes)
+	}
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate(channelID)
+	if err != nil {
+		return 0, err
+	}
+	if int64(len(marshaledUpdate)) > maxBytes {
+		return 0, fmt.Errorf("config update exceeds the maximum size of %d bytes", maxBytes)
+	}
+
+	n, err := w.Write(marshaledUpdate)
+	if err != nil {
+		return int64(n), fmt.Errorf("writing config update: %v", err)
+	}
+
+	return int64(n), nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestReadConfigFromAndWriteUpdateTo(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	configBytes, err := proto.Marshal(&cb.Config{ChannelGroup: channelGroup})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	c, err := ReadConfigFrom(bytes.NewReader(configBytes), DefaultMaxConfigSize)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(c.OriginalConfig().ChannelGroup, channelGroup)).To(BeTrue())
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	expectedUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var buf bytes.Buffer
+	n, err := c.WriteUpdateTo(&buf, "testchannel", DefaultMaxConfigSize)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(n).To(Equal(int64(len(expectedUpdate))))
+	gt.Expect(proto.Equal(unmarshalConfigUpdate(gt, buf.Bytes()), unmarshalConfigUpdate(gt, expectedUpdate))).To(BeTrue())
+}
+
+func TestReadConfigFromFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		reader      io.Reader
+		maxBytes    int64
+		expectedErr string
+	}{
+		{
+			testName:    "When the reader is nil",
+			reader:      nil,
+			maxBytes:    DefaultMaxConfigSize,
+			expectedErr: "reader is required",
+		},
+		{
+			testName:    "When reading fails",
+			reader:      &failingReadWriter{},
+			maxBytes:    DefaultMaxConfigSize,
+			expectedErr: "reading config: stream closed",
+		},
+		{
+			testName:    "When the config exceeds the maximum size",
+			reader:      io.LimitReader(zeroReader{}, 1025),
+			maxBytes:    1024,
+			expectedErr: "config exceeds the maximum size of 1024 bytes",
+		},
+		{
+			testName:    "When the maximum size is not positive",
+			reader:      strings.NewReader(""),
+			maxBytes:    0,
+			expectedErr: "maximum size must be positive, got 0",
+		},
+		{
+			testName:    "When the config does not contain a channel group",
+			reader:      strings.NewReader(""),
+			maxBytes:    DefaultMaxConfigSize,
+			expectedErr: "config does not contain a channel group",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := ReadConfigFrom(tt.reader, tt.maxBytes)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
+
+func TestWriteUpdateToFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	_, err = c.WriteUpdateTo(nil, "testchannel", DefaultMaxConfigSize)
+	gt.Expect(err).To(MatchError("writer is required"))
+
+	_, err = c.WriteUpdateTo(&bytes.Buffer{}, "testchannel", 0)
+	gt.Expect(err).To(MatchError("maximum size must be positive, got 0"))
+
+	_, err = c.WriteUpdateTo(&bytes.Buffer{}, "testchannel", DefaultMaxConfigSize)
+	gt.Expect(err).To(MatchError("failed to compute update: no differences detected between original and updated config"))
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	_, err = c.WriteUpdateTo(&bytes.Buffer{}, "testchannel", 16)
+	gt.Expect(err).To(MatchError("config update exceeds the maximum size of 16 bytes"))
+
+	_, err = c.WriteUpdateTo(&failingReadWriter{}, "testchannel", DefaultMaxConfigSize)
+	gt.Expect(err).To(MatchError("writing config update: stream closed"))
+}
+
+type failingReadWriter struct{}
+
+func (failingReadWriter) Read([]byte) (int, error) {
+	return 0, errors.New("stream closed")
+}
+
+func (failingReadWriter) Write([]byte) (int, error) {
+	return 0, errors.New("stream closed")
+}
+
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+
+	return len(p), nil
+}
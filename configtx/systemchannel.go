@@ -0,0 +1,133 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+)
+
+// channelParticipationCapability is the orderer capability required on every
+// channel before orderers can be restarted without the system channel and
+// managed through the channel participation API.
+const channelParticipationCapability = "V2_0"
+
+// RemoveConsortiums removes the Consortiums group from the updated config of
+// the system channel. Once removed, no application channel can be created
+// through the system channel any longer.
+func (c *ConfigTx) RemoveConsortiums() (err error) {
+	defer c.observe("ConfigTx.RemoveConsortiums", time.Now(), &err)
+
+	if _, ok := c.updated.ChannelGroup.Groups[ConsortiumsGroupKey]; !ok {
+		return errors.New("consortiums group does not exist")
+	}
+
+	delete(c.updated.ChannelGroup.Groups, ConsortiumsGroupKey)
+
+	return nil
+}
+
+// RemoveConsortium removes the name of the consortium the channel was
+// created by from the channel config. The value is only used by the system
+// channel and is not needed by channels joined through the channel
+// participation API.
+func (c *ChannelGroup) RemoveConsortium() {
+	defer c.observe("ChannelGroup.RemoveConsortium", time.Now(), nil)
+
+	delete(c.channelGroup.Values, ConsortiumKey)
+}
+
+// CheckChannelParticipation checks that the orderer capability of the
+// channel allows it to be managed through the channel participation API
+// once the system channel is retired.
+func (c *ConfigTx) CheckChannelParticipation() error {
+	if _, ok := c.updated.ChannelGroup.Groups[OrdererGroupKey]; !ok {
+		return errors.New("orderer group does not exist")
+	}
+
+	capabilities, err := c.Orderer().Capabilities()
+	if err != nil {
+		return err
+	}
+
+	if !hasCapabilityAtLeast(capabilities, channelParticipationCapability) {
+		return fmt.Errorf("orderer capability %s or later is required for channel participation", channelParticipationCapability)
+	}
+
+	return nil
+}
+
+// RetireSystemChannel computes the config updates decommissioning the system
+// channel, as documented for the migration to the channel participation API.
+// It checks the orderer capability of the system channel and of every
+// application channel, and returns the marshaled ConfigUpdates keyed by
+// channel ID: the update of the system channel removes its Consortiums group
+// and the update of each application channel removes its Consortium value.
+// Application channels without a Consortium value need no update and are
+// omitted.
+//
+// Once the updates are committed, the orderers can be restarted without the
+// system channel and the system channel removed from them.
+func RetireSystemChannel(systemChannelID string, systemChannel *cb.Config, appChannels map[string]*cb.Config) (map[string][]byte, error) {
+	if systemChannel == nil {
+		return nil, errors.New("system channel config is required")
+	}
+
+	channelIDs := make([]string, 0, len(appChannels))
+	for channelID := range appChannels {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+
+	updates := map[string][]byte{}
+
+	c := New(proto.Clone(systemChannel).(*cb.Config))
+	err := c.CheckChannelParticipation()
+	if err != nil {
+		return nil, fmt.Errorf("system channel %s: %v", systemChannelID, err)
+	}
+
+	err = c.RemoveConsortiums()
+	if err != nil {
+		return nil, fmt.Errorf("system channel %s: %v", systemChannelID, err)
+	}
+
+	updates[systemChannelID], err = c.ComputeMarshaledUpdate(systemChannelID)
+	if err != nil {
+		return nil, fmt.Errorf("system channel %s: %v", systemChannelID, err)
+	}
+
+	for _, channelID := range channelIDs {
+		if appChannels[channelID] == nil {
+			return nil, fmt.Errorf("channel %s: config is required", channelID)
+		}
+
+		c := New(proto.Clone(appChannels[channelID]).(*cb.Config))
+		err := c.CheckChannelParticipation()
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: %v", channelID, err)
+		}
+
+		if _, ok := c.updated.ChannelGroup.Values[ConsortiumKey]; !ok {
+			continue
+		}
+
+		c.Channel().RemoveConsortium()
+
+		updates[channelID], err = c.ComputeMarshaledUpdate(channelID)
+		if err != nil {
+			return nil, fmt.Errorf("channel %s: %v", channelID, err)
+		}
+	}
+
+	return updates, nil
+}
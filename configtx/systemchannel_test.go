@@ -0,0 +1,151 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+// retirementConfigs returns the configs of a system channel and of an
+// application channel created through it, with the given orderer capability.
+func retirementConfigs(t *testing.T, ordererCapability string) (*cb.Config, *cb.Config) {
+	gt := NewGomegaWithT(t)
+
+	systemChannel, _, _ := baseSystemChannelProfile(t)
+	systemChannel.Orderer.Capabilities = []string{ordererCapability}
+	systemBlock, err := NewSystemChannelGenesisBlock(systemChannel, "testsystemchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	systemEnvelope, _, err := unmarshalConfigBlock(systemBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	appChannel, _, _ := baseApplicationChannelProfile(t)
+	appChannel.Orderer.Capabilities = []string{ordererCapability}
+	appBlock, err := NewApplicationChannelGenesisBlock(appChannel, "testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	appEnvelope, _, err := unmarshalConfigBlock(appBlock)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = setValue(appEnvelope.Config.ChannelGroup, consortiumValue("SampleConsortium"), AdminsPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return systemEnvelope.Config, appEnvelope.Config
+}
+
+func TestRetireSystemChannel(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	systemConfig, appConfig := retirementConfigs(t, "V2_0")
+	noConsortiumConfig := proto.Clone(appConfig).(*cb.Config)
+	delete(noConsortiumConfig.ChannelGroup.Values, ConsortiumKey)
+
+	updates, err := RetireSystemChannel("testsystemchannel", systemConfig, map[string]*cb.Config{
+		"testchannel":  appConfig,
+		"otherchannel": noConsortiumConfig,
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(updates).To(HaveLen(2))
+	gt.Expect(systemConfig.ChannelGroup.Groups).To(HaveKey(ConsortiumsGroupKey))
+
+	systemUpdate := &cb.ConfigUpdate{}
+	gt.Expect(proto.Unmarshal(updates["testsystemchannel"], systemUpdate)).To(Succeed())
+	gt.Expect(systemUpdate.ChannelId).To(Equal("testsystemchannel"))
+	gt.Expect(systemUpdate.WriteSet.Version).To(Equal(uint64(1)))
+	gt.Expect(systemUpdate.WriteSet.Groups).NotTo(HaveKey(ConsortiumsGroupKey))
+
+	appUpdate := &cb.ConfigUpdate{}
+	gt.Expect(proto.Unmarshal(updates["testchannel"], appUpdate)).To(Succeed())
+	gt.Expect(appUpdate.ChannelId).To(Equal("testchannel"))
+	gt.Expect(appUpdate.WriteSet.Version).To(Equal(uint64(1)))
+	gt.Expect(appUpdate.WriteSet.Values).NotTo(HaveKey(ConsortiumKey))
+}
+
+func TestRemoveConsortiums(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	systemConfig, appConfig := retirementConfigs(t, "V2_0")
+
+	c := New(systemConfig)
+	gt.Expect(c.RemoveConsortiums()).To(Succeed())
+	gt.Expect(c.UpdatedConfig().ChannelGroup.Groups).NotTo(HaveKey(ConsortiumsGroupKey))
+	gt.Expect(c.RemoveConsortiums()).To(MatchError("consortiums group does not exist"))
+
+	c = New(appConfig)
+	c.Channel().RemoveConsortium()
+	channel, err := c.Channel().Configuration()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channel.Consortium).To(BeEmpty())
+}
+
+func TestRetireSystemChannelFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		configs     func(t *testing.T) (*cb.Config, map[string]*cb.Config)
+		expectedErr string
+	}{
+		{
+			testName: "when the system channel config is missing",
+			configs: func(t *testing.T) (*cb.Config, map[string]*cb.Config) {
+				return nil, nil
+			},
+			expectedErr: "system channel config is required",
+		},
+		{
+			testName: "when the system channel orderer capability is too old",
+			configs: func(t *testing.T) (*cb.Config, map[string]*cb.Config) {
+				systemConfig, _ := retirementConfigs(t, "V1_4_2")
+				return systemConfig, nil
+			},
+			expectedErr: "system channel testsystemchannel: orderer capability V2_0 or later is required for channel participation",
+		},
+		{
+			testName: "when the system channel has no consortiums",
+			configs: func(t *testing.T) (*cb.Config, map[string]*cb.Config) {
+				systemConfig, _ := retirementConfigs(t, "V2_0")
+				delete(systemConfig.ChannelGroup.Groups, ConsortiumsGroupKey)
+				return systemConfig, nil
+			},
+			expectedErr: "system channel testsystemchannel: consortiums group does not exist",
+		},
+		{
+			testName: "when an application channel orderer capability is too old",
+			configs: func(t *testing.T) (*cb.Config, map[string]*cb.Config) {
+				systemConfig, _ := retirementConfigs(t, "V2_0")
+				_, appConfig := retirementConfigs(t, "V1_3")
+				return systemConfig, map[string]*cb.Config{"testchannel": appConfig}
+			},
+			expectedErr: "channel testchannel: orderer capability V2_0 or later is required for channel participation",
+		},
+		{
+			testName: "when an application channel has no orderer group",
+			configs: func(t *testing.T) (*cb.Config, map[string]*cb.Config) {
+				systemConfig, appConfig := retirementConfigs(t, "V2_0")
+				delete(appConfig.ChannelGroup.Groups, OrdererGroupKey)
+				return systemConfig, map[string]*cb.Config{"testchannel": appConfig}
+			},
+			expectedErr: "channel testchannel: orderer group does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			systemConfig, appConfigs := tt.configs(t)
+			_, err := RetireSystemChannel("testsystemchannel", systemConfig, appConfigs)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
@@ -0,0 +1,117 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// placeholderPattern matches the ${name} placeholders of a template along
+// with the $$ escape of a literal $.
+var placeholderPattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z0-9_.-]+)\}`)
+
+// TemplateVars are the values of the placeholders of a channel spec or change
+// document template, keyed by placeholder name. They let a single template
+// stamp out channels of several environments, such as dev, stage and prod,
+// each with its own channel name, endpoint hosts and MSP directories.
+type TemplateVars map[string]string
+
+// Expand replaces every ${name} placeholder of the template with the value
+// of the variable of that name. A literal $ is written as $$. The
+// substitution is textual, so values are meant for scalars such as names,
+// hosts and paths; crypto material is referenced by file, e.g. with the
+// msp_dir of an org. Placeholders of undefined variables are an error, as
+// are values that would change the structure of the YAML document, such as
+// values with line breaks, quotes, comments, flow collections or ": ".
+func (v TemplateVars) Expand(template []byte) ([]byte, error) {
+	undefined := map[string]bool{}
+	invalid := map[string]bool{}
+	expanded := placeholderPattern.ReplaceAllFunc(template, func(match []byte) []byte {
+		if string(match) == "$$" {
+			return []byte("$")
+		}
+
+		name := string(match[2 : len(match)-1])
+		value, ok := v[name]
+		if !ok {
+			undefined[name] = true
+			return match
+		}
+		if !isPlainYAMLScalar(value) {
+			invalid[name] = true
+			return match
+		}
+
+		return []byte(value)
+	})
+
+	if len(undefined) > 0 {
+		return nil, fmt.Errorf("undefined template variables: %s", sortedNames(undefined))
+	}
+
+	if len(invalid) > 0 {
+		return nil, fmt.Errorf("template variables with YAML-significant characters: %s", sortedNames(invalid))
+	}
+
+	return expanded, nil
+}
+
+// isPlainYAMLScalar reports whether the value can be substituted into a
+// plain YAML scalar without changing the structure of the document.
+func isPlainYAMLScalar(value string) bool {
+	if strings.ContainsAny(value, "\n\r\t#{}[],&*!|>'\"%@`") {
+		return false
+	}
+
+	if strings.Contains(value, ": ") || strings.HasSuffix(value, ":") {
+		return false
+	}
+
+	if value == "-" || strings.HasPrefix(value, "- ") {
+		return false
+	}
+
+	return strings.TrimSpace(value) == value
+}
+
+// sortedNames returns the names of the set in order, separated by commas.
+func sortedNames(set map[string]bool) string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
+
+// ParseChangeDocumentTemplate expands the placeholders of a change document
+// template with the variables and parses the resulting change document, see
+// ParseChangeDocument.
+func ParseChangeDocumentTemplate(template []byte, vars TemplateVars) (ChangeDocument, error) {
+	data, err := vars.Expand(template)
+	if err != nil {
+		return ChangeDocument{}, err
+	}
+
+	return ParseChangeDocument(data)
+}
+
+// ParseChannelTemplate expands the placeholders of a channel spec template
+// with the variables and parses the resulting channel spec, see
+// ParseChannelSpec.
+func ParseChannelTemplate(template []byte, vars TemplateVars) (ChannelSpec, error) {
+	data, err := vars.Expand(template)
+	if err != nil {
+		return ChannelSpec{}, err
+	}
+
+	return ParseChannelSpec(data)
+}
@@ -0,0 +1,93 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestTemplateVarsExpand(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	vars := TemplateVars{
+		"channel":   "prod-channel",
+		"domain":    "prod.example.com",
+		"empty":     "",
+		"org1.name": "Org1",
+	}
+
+	expanded, err := vars.Expand([]byte("channel_id: ${channel}\nhost: peer0.${domain}${empty}\nname: ${org1.name}\nprice: $$5 ${ not a placeholder }"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(string(expanded)).To(Equal("channel_id: prod-channel\nhost: peer0.prod.example.com\nname: Org1\nprice: $5 ${ not a placeholder }"))
+
+	_, err = vars.Expand([]byte("${missing} ${channel} ${also_missing} ${missing}"))
+	gt.Expect(err).To(MatchError("undefined template variables: also_missing, missing"))
+
+	expanded, err = TemplateVars{"endpoint": "orderer.example.com:7050", "path": "/etc/msp dir"}.Expand([]byte("address: ${endpoint}\nmsp_dir: ${path}"))
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(string(expanded)).To(Equal("address: orderer.example.com:7050\nmsp_dir: /etc/msp dir"))
+}
+
+func TestTemplateVarsExpandYAMLSignificantValues(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{
+		"prod\nadmins: []",
+		"prod # comment",
+		"'quoted'",
+		"\"quoted\"",
+		"[Org1, Org2]",
+		"{name: Org1}",
+		"name: Org1",
+		"Org1:",
+		"- Org1",
+		"-",
+		"*alias",
+		"&anchor",
+		"!!binary",
+		"|",
+		" padded",
+		"padded ",
+	}
+
+	for _, value := range tests {
+		value := value
+		t.Run(value, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			_, err := TemplateVars{"org": value, "channel": "prod-channel"}.Expand([]byte("channel_id: ${channel}\nname: ${org}"))
+			gt.Expect(err).To(MatchError("template variables with YAML-significant characters: org"))
+		})
+	}
+}
+
+func TestParseChangeDocumentTemplate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	template := []byte(`
+changes:
+- op: remove-application-org
+  name: ${org}
+- op: set-batch-timeout
+  timeout: ${timeout}
+`)
+
+	doc, err := ParseChangeDocumentTemplate(template, TemplateVars{"org": "Org2", "timeout": "3s"})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(doc.Changes).To(Equal([]ChangeSpec{
+		{Op: OpRemoveApplicationOrg, Name: "Org2"},
+		{Op: OpSetBatchTimeout, Timeout: "3s"},
+	}))
+
+	_, err = ParseChangeDocumentTemplate(template, TemplateVars{"org": "Org2"})
+	gt.Expect(err).To(MatchError("undefined template variables: timeout"))
+}
@@ -17,6 +17,13 @@ import (
 // Compute computes the difference between two *cb.Configs and returns the
 // ReadSet and WriteSet diff as a *cb.ConfigUpdate
 func computeConfigUpdate(original, updated *cb.Config) (*cb.ConfigUpdate, error) {
+	return computeScopedConfigUpdate(original, updated, nil)
+}
+
+// computeScopedConfigUpdate computes the config update like
+// computeConfigUpdate, but only compares the child groups within the scope.
+// The groups outside of the scope must be unmodified.
+func computeScopedConfigUpdate(original, updated *cb.Config, scope *updateScope) (*cb.ConfigUpdate, error) {
 	if original.ChannelGroup == nil {
 		return nil, fmt.Errorf("no channel group included for original config")
 	}
@@ -25,7 +32,7 @@ func computeConfigUpdate(original, updated *cb.Config) (*cb.ConfigUpdate, error)
 		return nil, fmt.Errorf("no channel group included for updated config")
 	}
 
-	readSet, writeSet, groupUpdated := computeGroupUpdate(original.ChannelGroup, updated.ChannelGroup)
+	readSet, writeSet, groupUpdated := computeGroupUpdate(original.ChannelGroup, updated.ChannelGroup, scope)
 	if !groupUpdated {
 		return nil, fmt.Errorf("no differences detected between original and updated config")
 	}
@@ -130,7 +137,7 @@ func computeValuesMapUpdate(original, updated map[string]*cb.ConfigValue) (readS
 	return
 }
 
-func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup) (readSet, writeSet, sameSet map[string]*cb.ConfigGroup, updatedMembers bool) {
+func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup, scope *updateScope) (readSet, writeSet, sameSet map[string]*cb.ConfigGroup, updatedMembers bool) {
 	readSet = make(map[string]*cb.ConfigGroup)
 	writeSet = make(map[string]*cb.ConfigGroup)
 
@@ -145,7 +152,15 @@ func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup) (readS
 			continue
 		}
 
-		groupReadSet, groupWriteSet, groupUpdated := computeGroupUpdate(originalGroup, updatedGroup)
+		groupScope, inScope := scope.child(groupName)
+		if !inScope {
+			sameSet[groupName] = &cb.ConfigGroup{
+				Version: originalGroup.Version,
+			}
+			continue
+		}
+
+		groupReadSet, groupWriteSet, groupUpdated := computeGroupUpdate(originalGroup, updatedGroup, groupScope)
 		if !groupUpdated {
 			sameSet[groupName] = groupReadSet
 			continue
@@ -162,7 +177,7 @@ func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup) (readS
 			continue
 		}
 		updatedMembers = true
-		_, groupWriteSet, _ := computeGroupUpdate(newConfigGroup(), updatedGroup)
+		_, groupWriteSet, _ := computeGroupUpdate(newConfigGroup(), updatedGroup, nil)
 		writeSet[groupName] = &cb.ConfigGroup{
 			Version:   0,
 			ModPolicy: updatedGroup.ModPolicy,
@@ -175,10 +190,10 @@ func computeGroupsMapUpdate(original, updated map[string]*cb.ConfigGroup) (readS
 	return
 }
 
-func computeGroupUpdate(original, updated *cb.ConfigGroup) (readSet, writeSet *cb.ConfigGroup, updatedGroup bool) {
+func computeGroupUpdate(original, updated *cb.ConfigGroup, scope *updateScope) (readSet, writeSet *cb.ConfigGroup, updatedGroup bool) {
 	readSetPolicies, writeSetPolicies, sameSetPolicies, policiesMembersUpdated := computePoliciesMapUpdate(original.Policies, updated.Policies)
 	readSetValues, writeSetValues, sameSetValues, valuesMembersUpdated := computeValuesMapUpdate(original.Values, updated.Values)
-	readSetGroups, writeSetGroups, sameSetGroups, groupsMembersUpdated := computeGroupsMapUpdate(original.Groups, updated.Groups)
+	readSetGroups, writeSetGroups, sameSetGroups, groupsMembersUpdated := computeGroupsMapUpdate(original.Groups, updated.Groups, scope)
 
 	// If the updated group is 'Equal' to the updated group (none of the members nor the mod policy changed)
 	if !(policiesMembersUpdated || valuesMembersUpdated || groupsMembersUpdated || original.ModPolicy != updated.ModPolicy) {
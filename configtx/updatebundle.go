@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+)
+
+// The files written by UpdateBundle.WriteFiles, named as in the Fabric
+// documentation of channel config updates.
+const (
+	UpdateFile         = "update.pb"
+	UpdateJSONFile     = "update.json"
+	UpdateEnvelopeFile = "update_in_envelope.pb"
+)
+
+// UpdateBundle holds the artifacts of a config update submitted for the
+// other orgs of the channel to sign: the config update, its JSON decoding
+// for review and the envelope carrying the update signed by the submitting
+// org admin. It is stored as a single JSON document, see JSON, or as
+// separate files, see WriteFiles.
+type UpdateBundle struct {
+	ChannelID string `json:"channel_id"`
+	// Update is the marshaled ConfigUpdate protobuf.
+	Update []byte `json:"update"`
+	// UpdateJSON is the config update decoded to JSON by protolator, as
+	// configtxlator proto_decode does.
+	UpdateJSON json.RawMessage `json:"update_json"`
+	// Envelope is the marshaled Envelope carrying the update along with the
+	// config signature of the submitting admin, who also signed the
+	// envelope. The other orgs add their config signatures to it.
+	Envelope []byte `json:"envelope"`
+}
+
+// NewSignedUpdateBundle computes the config update of the pending change to
+// the channel with the given ID, wraps it in an envelope and signs both the
+// update and the envelope with the signing identity of an org admin. It
+// replaces computing, decoding, wrapping and signing the update step by
+// step.
+func (c *ConfigTx) NewSignedUpdateBundle(channelID string, signer *SigningIdentity) (UpdateBundle, error) {
+	if signer == nil {
+		return UpdateBundle{}, errors.New("signing identity is required")
+	}
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate(channelID)
+	if err != nil {
+		return UpdateBundle{}, err
+	}
+
+	configUpdate := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, configUpdate)
+	if err != nil {
+		return UpdateBundle{}, fmt.Errorf("unmarshaling config update: %v", err)
+	}
+
+	var updateJSON bytes.Buffer
+	err = protolator.DeepMarshalJSON(&updateJSON, configUpdate)
+	if err != nil {
+		return UpdateBundle{}, fmt.Errorf("decoding config update: %v", err)
+	}
+
+	signature, err := signer.CreateConfigSignature(marshaledUpdate)
+	if err != nil {
+		return UpdateBundle{}, err
+	}
+
+	envelope, err := NewEnvelope(marshaledUpdate, signature)
+	if err != nil {
+		return UpdateBundle{}, err
+	}
+
+	err = signer.SignEnvelope(envelope)
+	if err != nil {
+		return UpdateBundle{}, err
+	}
+
+	marshaledEnvelope, err := proto.Marshal(envelope)
+	if err != nil {
+		return UpdateBundle{}, fmt.Errorf("marshaling envelope: %v", err)
+	}
+
+	return UpdateBundle{
+		ChannelID:  channelID,
+		Update:     marshaledUpdate,
+		UpdateJSON: updateJSON.Bytes(),
+		Envelope:   marshaledEnvelope,
+	}, nil
+}
+
+// JSON returns the bundle encoded as JSON.
+func (b UpdateBundle) JSON() ([]byte, error) {
+	return json.MarshalIndent(b, "", "\t")
+}
+
+// WriteFiles writes the update, its JSON decoding and the envelope of the
+// bundle to the files UpdateFile, UpdateJSONFile and UpdateEnvelopeFile of
+// the existing directory dir.
+func (b UpdateBundle) WriteFiles(dir string) error {
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{name: UpdateFile, content: b.Update},
+		{name: UpdateJSONFile, content: b.UpdateJSON},
+		{name: UpdateEnvelopeFile, content: b.Envelope},
+	}
+
+	for _, file := range files {
+		err := ioutil.WriteFile(filepath.Join(dir, file.name), file.content, 0644)
+		if err != nil {
+			return fmt.Errorf("writing %s: %v", file.name, err)
+		}
+	}
+
+	return nil
+}
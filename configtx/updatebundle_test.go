@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestNewSignedUpdateBundle(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	signingMSP, signingKey := baseMSP(t)
+	signingIdentity := &SigningIdentity{
+		Certificate: signingMSP.Admins[0],
+		PrivateKey:  signingKey,
+		MSPID:       "MSPID",
+	}
+
+	bundle, err := c.NewSignedUpdateBundle("testchannel", signingIdentity)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(bundle.ChannelID).To(Equal("testchannel"))
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	expectedUpdate := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, expectedUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+	update := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(bundle.Update, update)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(update, expectedUpdate)).To(BeTrue())
+
+	decoded := struct {
+		ChannelID string `json:"channel_id"`
+	}{}
+	err = json.Unmarshal(bundle.UpdateJSON, &decoded)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(decoded.ChannelID).To(Equal("testchannel"))
+
+	envelope := &cb.Envelope{}
+	err = proto.Unmarshal(bundle.Envelope, envelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(envelope.Signature).NotTo(BeEmpty())
+	info, err := Classify(envelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(info.HeaderType).To(Equal(cb.HeaderType_CONFIG_UPDATE))
+	gt.Expect(info.ChannelID).To(Equal("testchannel"))
+	gt.Expect(info.Creator.MSPID).To(Equal("MSPID"))
+
+	payload := &cb.Payload{}
+	err = proto.Unmarshal(envelope.Payload, payload)
+	gt.Expect(err).NotTo(HaveOccurred())
+	configUpdateEnvelope := &cb.ConfigUpdateEnvelope{}
+	err = proto.Unmarshal(payload.Data, configUpdateEnvelope)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(configUpdateEnvelope.ConfigUpdate).To(Equal(bundle.Update))
+	gt.Expect(configUpdateEnvelope.Signatures).To(HaveLen(1))
+
+	bundleJSON, err := bundle.JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	parsed := UpdateBundle{}
+	err = json.Unmarshal(bundleJSON, &parsed)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(parsed.Update).To(Equal(bundle.Update))
+	gt.Expect(parsed.Envelope).To(Equal(bundle.Envelope))
+	gt.Expect(parsed.UpdateJSON).To(MatchJSON(bundle.UpdateJSON))
+
+	dir, err := ioutil.TempDir("", "update")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	err = bundle.WriteFiles(dir)
+	gt.Expect(err).NotTo(HaveOccurred())
+	for name, content := range map[string][]byte{
+		UpdateFile:         bundle.Update,
+		UpdateJSONFile:     bundle.UpdateJSON,
+		UpdateEnvelopeFile: bundle.Envelope,
+	} {
+		written, err := ioutil.ReadFile(filepath.Join(dir, name))
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(written).To(Equal(content), name)
+	}
+
+	err = bundle.WriteFiles(filepath.Join(dir, "missing"))
+	gt.Expect(err).To(MatchError(ContainSubstring("writing update.pb: ")))
+}
+
+func TestNewSignedUpdateBundleFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{ChannelGroup: channelGroup})
+
+	_, err = c.NewSignedUpdateBundle("testchannel", nil)
+	gt.Expect(err).To(MatchError("signing identity is required"))
+
+	signingMSP, signingKey := baseMSP(t)
+	signingIdentity := &SigningIdentity{
+		Certificate: signingMSP.Admins[0],
+		PrivateKey:  signingKey,
+		MSPID:       "MSPID",
+	}
+	_, err = c.NewSignedUpdateBundle("", signingIdentity)
+	gt.Expect(err).To(MatchError("channel ID is required"))
+}
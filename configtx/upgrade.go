@@ -0,0 +1,152 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/hyperledger/fabric-config/configtx/orderer"
+)
+
+// UpgradeChanges returns the changes bringing the channel to the baseline
+// recommended for the given Fabric version by its sample configtx.yaml:
+//   - the capabilities of the channel, orderer and application groups are
+//     raised to those of CapabilitiesFor, unless they are already at least as
+//     recent;
+//   - the Endorsement and LifecycleEndorsement policies of the application
+//     group and the Endorsement policies of the application orgs required by
+//     the V2_0 lifecycle are added if missing;
+//   - the deprecated channel level orderer addresses are moved to the
+//     endpoints of the orderer orgs.
+//
+// Fabric 3.0 removed the solo and kafka consensus types, so an orderer using
+// them must be migrated to etcdraft before upgrading to it. No changes are
+// returned for a channel already at the baseline.
+func (c *ConfigTx) UpgradeChanges(version FabricVersion) ([]Change, error) {
+	capabilities, err := CapabilitiesFor(version)
+	if err != nil {
+		return nil, err
+	}
+
+	channelGroup := c.updated.ChannelGroup
+	var changes []Change
+
+	channelCapabilities, err := c.Channel().Capabilities()
+	if err != nil {
+		return nil, err
+	}
+	if !hasCapabilityAtLeast(channelCapabilities, capabilities.Channel[0]) {
+		changes = append(changes, SetCapabilities{Capabilities: capabilities.Channel})
+	}
+
+	if ordererGroup, ok := channelGroup.Groups[OrdererGroupKey]; ok {
+		consensusType, err := ordererConsensusType(ordererGroup)
+		if err != nil {
+			return nil, err
+		}
+		if version == FabricV3_0 && (consensusType == orderer.ConsensusTypeSolo || consensusType == orderer.ConsensusTypeKafka) {
+			return nil, fmt.Errorf("fabric %s does not support consensus type %s, migrate to etcdraft first", version, consensusType)
+		}
+
+		ordererCapabilities, err := c.Orderer().Capabilities()
+		if err != nil {
+			return nil, err
+		}
+		if !hasCapabilityAtLeast(ordererCapabilities, capabilities.Orderer[0]) {
+			changes = append(changes, SetCapabilities{Group: OrdererGroupKey, Capabilities: capabilities.Orderer})
+		}
+	}
+
+	if applicationGroup, ok := channelGroup.Groups[ApplicationGroupKey]; ok {
+		applicationChanges, err := c.applicationUpgradeChanges(applicationGroup, capabilities.Application)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, applicationChanges...)
+	}
+
+	if _, ok := channelGroup.Values[OrdererAddressesKey]; ok {
+		changes = append(changes, MigrateOrdererAddresses{})
+	}
+
+	return changes, nil
+}
+
+// applicationUpgradeChanges returns the capability and lifecycle policy
+// changes of the application group.
+func (c *ConfigTx) applicationUpgradeChanges(applicationGroup *cb.ConfigGroup, capabilities []string) ([]Change, error) {
+	var changes []Change
+
+	applicationCapabilities, err := c.Application().Capabilities()
+	if err != nil {
+		return nil, err
+	}
+	if !hasCapabilityAtLeast(applicationCapabilities, capabilities[0]) {
+		changes = append(changes, SetCapabilities{Group: ApplicationGroupKey, Capabilities: capabilities})
+	}
+
+	orgNames := make([]string, 0, len(applicationGroup.Groups))
+	for name := range applicationGroup.Groups {
+		orgNames = append(orgNames, name)
+	}
+	sort.Strings(orgNames)
+
+	for _, name := range orgNames {
+		org := c.Application().Organization(name)
+
+		orgPolicies, err := org.Policies()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := orgPolicies[EndorsementPolicyKey]; ok {
+			continue
+		}
+
+		msp, err := org.MSP().Configuration()
+		if err != nil {
+			return nil, err
+		}
+
+		changes = append(changes, SetPolicy{
+			Group:  ApplicationGroupKey + "/" + name,
+			Name:   EndorsementPolicyKey,
+			Policy: defaultApplicationOrgPolicies(msp.Name)[EndorsementPolicyKey],
+		})
+	}
+
+	// the org policies are set first so that the sub-policies of the
+	// ImplicitMeta policies of the application group are defined
+	policies, err := c.Application().Policies()
+	if err != nil {
+		return nil, err
+	}
+	defaultPolicies := defaultApplicationPolicies()
+	for _, name := range []string{EndorsementPolicyKey, LifecycleEndorsementPolicyKey} {
+		if _, ok := policies[name]; !ok {
+			changes = append(changes, SetPolicy{Group: ApplicationGroupKey, Name: name, Policy: defaultPolicies[name]})
+		}
+	}
+
+	return changes, nil
+}
+
+// Upgrade applies the changes returned by UpgradeChanges to bring the channel
+// to the baseline recommended for the given Fabric version. Either all changes
+// are applied or the updated config is left untouched.
+func (c *ConfigTx) Upgrade(version FabricVersion) (err error) {
+	defer c.observe("ConfigTx.Upgrade", time.Now(), &err)
+
+	changes, err := c.UpgradeChanges(version)
+	if err != nil {
+		return err
+	}
+
+	return c.Apply(changes)
+}
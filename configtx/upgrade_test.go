@@ -0,0 +1,139 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestUpgrade(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	err := c.Application().Organization("Org1").RemovePolicy(EndorsementPolicyKey)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	changes, err := c.UpgradeChanges(FabricV3_0)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(changes).To(Equal([]Change{
+		SetCapabilities{Capabilities: []string{"V3_0"}},
+		SetCapabilities{Group: OrdererGroupKey, Capabilities: []string{"V2_0"}},
+		SetCapabilities{Group: ApplicationGroupKey, Capabilities: []string{"V2_5"}},
+		SetPolicy{
+			Group:  "Application/Org1",
+			Name:   EndorsementPolicyKey,
+			Policy: Policy{Type: SignaturePolicyType, Rule: "OR('MSPID.peer')"},
+		},
+		SetPolicy{
+			Group:  ApplicationGroupKey,
+			Name:   EndorsementPolicyKey,
+			Policy: Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Endorsement"},
+		},
+		SetPolicy{
+			Group:  ApplicationGroupKey,
+			Name:   LifecycleEndorsementPolicyKey,
+			Policy: Policy{Type: ImplicitMetaPolicyType, Rule: "MAJORITY Endorsement"},
+		},
+	}))
+
+	err = c.Upgrade(FabricV3_0)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	channelCapabilities, err := c.Channel().Capabilities()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(channelCapabilities).To(Equal([]string{"V3_0"}))
+
+	ordererCapabilities, err := c.Orderer().Capabilities()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(ordererCapabilities).To(Equal([]string{"V2_0"}))
+
+	applicationCapabilities, err := c.Application().Capabilities()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(applicationCapabilities).To(Equal([]string{"V2_5"}))
+
+	policies, err := c.Application().Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(HaveKey(EndorsementPolicyKey))
+	gt.Expect(policies).To(HaveKey(LifecycleEndorsementPolicyKey))
+
+	orgPolicies, err := c.Application().Organization("Org1").Policies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(orgPolicies[EndorsementPolicyKey].Rule).To(Equal("AND('MSPID.peer')"))
+
+	changes, err = c.UpgradeChanges(FabricV2_5)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(changes).To(BeEmpty())
+}
+
+func TestUpgradeLegacyOrdererAddresses(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := applyConfigTx(t)
+	c.updated.ChannelGroup.Values = map[string]*cb.ConfigValue{
+		OrdererAddressesKey: {
+			ModPolicy: AdminsPolicyKey,
+			Value: marshalOrPanic(&cb.OrdererAddresses{
+				Addresses: []string{"node-1.example.com:7050"},
+			}),
+		},
+	}
+
+	changes, err := c.UpgradeChanges(FabricV2_2)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(changes).To(ContainElement(MigrateOrdererAddresses{}))
+}
+
+func TestUpgradeFailures(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName    string
+		version     FabricVersion
+		orderer     func(t *testing.T) Orderer
+		expectedErr string
+	}{
+		{
+			testName: "when the fabric version is unknown",
+			version:  "1.4",
+			orderer: func(t *testing.T) Orderer {
+				etcdRaftOrderer, _ := baseEtcdRaftOrderer(t)
+				return etcdRaftOrderer
+			},
+			expectedErr: "unknown fabric version '1.4'",
+		},
+		{
+			testName: "when the consensus type was removed from the fabric version",
+			version:  FabricV3_0,
+			orderer: func(t *testing.T) Orderer {
+				soloOrderer, _ := baseSoloOrderer(t)
+				return soloOrderer
+			},
+			expectedErr: "fabric 3.0 does not support consensus type solo, migrate to etcdraft first",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := ordererConfigTx(t, tt.orderer(t))
+			expected := proto.Clone(c.updated.ChannelGroup)
+
+			err := c.Upgrade(tt.version)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+			gt.Expect(proto.Equal(c.updated.ChannelGroup, expected)).To(BeTrue())
+		})
+	}
+}
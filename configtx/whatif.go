@@ -0,0 +1,292 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+)
+
+// PolicySigner is a hypothetical signer of a policy what-if analysis: an
+// identity of the MSP with the given ID.
+type PolicySigner struct {
+	MSPID string
+	// Role is the role of the identity: member, admin, client, peer or
+	// orderer. Every identity is a member of its MSP, so a signer with any
+	// role satisfies member principals.
+	Role string
+}
+
+// SatisfiedPolicies returns the paths of the policies of the updated config,
+// in path order, which pass if exactly the given signers sign, answering
+// questions such as which policies the admins of an org can satisfy on their
+// own. As in Fabric, a signature is only counted once per signature policy
+// and signers of MSPs not defined in the channel are ignored. Principals
+// identifying an organizational unit or a specific identity are never
+// satisfied by a simulated signer.
+func (c *ConfigTx) SatisfiedPolicies(signers []PolicySigner) ([]string, error) {
+	e := &signerEvaluator{
+		checked: map[string]bool{},
+	}
+
+	mspIDs := map[string]bool{}
+	err := collectMSPIDs(c.updated.ChannelGroup, mspIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, signer := range signers {
+		if !validSignerRole(signer.Role) {
+			return nil, fmt.Errorf("unknown role '%s' of signer of MSP %s", signer.Role, signer.MSPID)
+		}
+		if mspIDs[signer.MSPID] && !containsSigner(e.signers, signer) {
+			e.signers = append(e.signers, signer)
+		}
+	}
+
+	var satisfied []string
+	err = e.checkGroup("/"+ChannelGroupKey, c.updated.ChannelGroup, &satisfied)
+	if err != nil {
+		return nil, err
+	}
+
+	return satisfied, nil
+}
+
+// PoliciesBrokenWithout returns the policies of the updated config which
+// become unsatisfiable if the MSPs with the given IDs leave the channel, in
+// path order, see UnsatisfiablePolicies. It answers questions such as what
+// breaks if an org leaves without modifying the config.
+func (c *ConfigTx) PoliciesBrokenWithout(mspIDs ...string) ([]UnsatisfiablePolicy, error) {
+	if len(mspIDs) == 0 {
+		return nil, errors.New("at least one MSP ID is required")
+	}
+
+	defined := map[string]bool{}
+	err := collectMSPIDs(c.updated.ChannelGroup, defined)
+	if err != nil {
+		return nil, err
+	}
+	for _, mspID := range mspIDs {
+		if !defined[mspID] {
+			return nil, fmt.Errorf("MSP %s is not defined in the channel", mspID)
+		}
+	}
+
+	before, err := unsatisfiablePolicies(c.updated.ChannelGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := unsatisfiablePolicies(c.updated.ChannelGroup, mspIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newlyUnsatisfiable(before, after), nil
+}
+
+// signerEvaluator evaluates the policies of a channel group against a set of
+// simulated signers.
+type signerEvaluator struct {
+	signers []PolicySigner
+	// checked holds the result of every policy evaluated so far by path.
+	checked map[string]bool
+}
+
+// checkGroup appends the paths of the satisfied policies of the group at path
+// and of its child groups to satisfied.
+func (e *signerEvaluator) checkGroup(path string, group *cb.ConfigGroup, satisfied *[]string) error {
+	for _, key := range sortedPolicyKeys(group) {
+		ok, err := e.satisfied(path, group, key)
+		if err != nil {
+			return err
+		}
+		if ok {
+			*satisfied = append(*satisfied, path+"/"+key)
+		}
+	}
+
+	for _, childName := range sortedGroupKeys(group) {
+		err := e.checkGroup(path+"/"+childName, group.Groups[childName], satisfied)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// satisfied reports whether the policy with the given name of the group at
+// path exists and is satisfied by the signers.
+func (e *signerEvaluator) satisfied(path string, group *cb.ConfigGroup, name string) (bool, error) {
+	policyName := path + "/" + name
+	if satisfied, ok := e.checked[policyName]; ok {
+		return satisfied, nil
+	}
+
+	configPolicy, ok := group.Policies[name]
+	if !ok || configPolicy.Policy == nil {
+		return false, nil
+	}
+
+	var satisfied bool
+	switch cb.Policy_PolicyType(configPolicy.Policy.Type) {
+	case cb.Policy_IMPLICIT_META:
+		imp := &cb.ImplicitMetaPolicy{}
+		err := proto.Unmarshal(configPolicy.Policy.Value, imp)
+		if err != nil {
+			return false, fmt.Errorf("unmarshaling implicit meta policy %s: %v", policyName, err)
+		}
+
+		count := 0
+		for _, childName := range sortedGroupKeys(group) {
+			ok, err := e.satisfied(path+"/"+childName, group.Groups[childName], imp.SubPolicy)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				count++
+			}
+		}
+		satisfied = count >= implicitMetaThreshold(imp.Rule, len(group.Groups))
+	case cb.Policy_SIGNATURE:
+		sp := &cb.SignaturePolicyEnvelope{}
+		err := proto.Unmarshal(configPolicy.Policy.Value, sp)
+		if err != nil {
+			return false, fmt.Errorf("unmarshaling signature policy %s: %v", policyName, err)
+		}
+
+		if sp.Rule != nil {
+			satisfied, err = e.satisfiedRule(sp.Rule, sp.Identities, make([]bool, len(e.signers)))
+			if err != nil {
+				return false, fmt.Errorf("evaluating policy %s: %v", policyName, err)
+			}
+		}
+	}
+
+	e.checked[policyName] = satisfied
+	return satisfied, nil
+}
+
+// satisfiedRule reports whether the signature policy rule is satisfied by the
+// signers which are not used yet, in the way Fabric evaluates signature
+// policies: a signer used to satisfy a principal is not available for the
+// other principals of the rule.
+func (e *signerEvaluator) satisfiedRule(rule *cb.SignaturePolicy, identities []*mb.MSPPrincipal, used []bool) (bool, error) {
+	switch t := rule.Type.(type) {
+	case *cb.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(identities) {
+			return false, nil
+		}
+
+		for i, signer := range e.signers {
+			if used[i] {
+				continue
+			}
+			ok, err := signerSatisfiesPrincipal(signer, identities[t.SignedBy])
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				used[i] = true
+				return true, nil
+			}
+		}
+
+		return false, nil
+	case *cb.SignaturePolicy_NOutOf_:
+		verified := 0
+		ruleUsed := make([]bool, len(used))
+		for _, subRule := range t.NOutOf.Rules {
+			copy(ruleUsed, used)
+			ok, err := e.satisfiedRule(subRule, identities, ruleUsed)
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				verified++
+				copy(used, ruleUsed)
+			}
+		}
+
+		return verified >= int(t.NOutOf.N), nil
+	default:
+		return false, nil
+	}
+}
+
+// signerSatisfiesPrincipal reports whether the simulated signer satisfies the
+// principal.
+func signerSatisfiesPrincipal(signer PolicySigner, principal *mb.MSPPrincipal) (bool, error) {
+	switch principal.PrincipalClassification {
+	case mb.MSPPrincipal_ROLE:
+		role := &mb.MSPRole{}
+		err := proto.Unmarshal(principal.Principal, role)
+		if err != nil {
+			return false, fmt.Errorf("unmarshaling role principal: %v", err)
+		}
+
+		if role.MspIdentifier != signer.MSPID {
+			return false, nil
+		}
+
+		return role.Role == mb.MSPRole_MEMBER || strings.ToLower(role.Role.String()) == signer.Role, nil
+	case mb.MSPPrincipal_COMBINED:
+		combined := &mb.CombinedPrincipal{}
+		err := proto.Unmarshal(principal.Principal, combined)
+		if err != nil {
+			return false, fmt.Errorf("unmarshaling combined principal: %v", err)
+		}
+
+		for _, p := range combined.Principals {
+			ok, err := signerSatisfiesPrincipal(signer, p)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+
+		return len(combined.Principals) > 0, nil
+	case mb.MSPPrincipal_ANONYMITY:
+		anonymity := &mb.MSPIdentityAnonymity{}
+		err := proto.Unmarshal(principal.Principal, anonymity)
+		if err != nil {
+			return false, fmt.Errorf("unmarshaling anonymity principal: %v", err)
+		}
+
+		// simulated signers are X.509 identities, which are nominal
+		return anonymity.AnonymityType == mb.MSPIdentityAnonymity_NOMINAL, nil
+	default:
+		return false, nil
+	}
+}
+
+// validSignerRole reports whether the role is one of the MSP roles.
+func validSignerRole(role string) bool {
+	for _, r := range []mb.MSPRole_MSPRoleType{mb.MSPRole_MEMBER, mb.MSPRole_ADMIN, mb.MSPRole_CLIENT, mb.MSPRole_PEER, mb.MSPRole_ORDERER} {
+		if role == strings.ToLower(r.String()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsSigner(signers []PolicySigner, signer PolicySigner) bool {
+	for _, s := range signers {
+		if s == signer {
+			return true
+		}
+	}
+
+	return false
+}
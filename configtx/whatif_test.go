@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSatisfiedPolicies(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		testName         string
+		signers          []PolicySigner
+		expectedPolicies []string
+		missingPolicies  []string
+	}{
+		{
+			testName:        "When nobody signs",
+			missingPolicies: []string{"/Channel/Application/Admins", "/Channel/Application/Custom"},
+		},
+		{
+			testName:         "When an admin of the org signs",
+			signers:          []PolicySigner{{MSPID: "Org3MSP", Role: "admin"}},
+			expectedPolicies: []string{"/Channel/Admins", "/Channel/Application/Admins"},
+			missingPolicies:  []string{"/Channel/Application/Custom"},
+		},
+		{
+			testName:         "When a client of the org signs",
+			signers:          []PolicySigner{{MSPID: "Org3MSP", Role: "client"}},
+			expectedPolicies: []string{"/Channel/Orderer/Admins"},
+			missingPolicies:  []string{"/Channel/Admins", "/Channel/Application/Admins", "/Channel/Application/Custom"},
+		},
+		{
+			testName: "When an admin and a client of the org sign",
+			signers: []PolicySigner{
+				{MSPID: "Org3MSP", Role: "admin"},
+				{MSPID: "Org3MSP", Role: "client"},
+			},
+			expectedPolicies: []string{"/Channel/Application/Admins", "/Channel/Application/Custom"},
+		},
+		{
+			testName: "When the same signer is given twice",
+			signers: []PolicySigner{
+				{MSPID: "Org3MSP", Role: "admin"},
+				{MSPID: "Org3MSP", Role: "admin"},
+			},
+			missingPolicies: []string{"/Channel/Application/Custom"},
+		},
+		{
+			testName: "When a signer of an undefined MSP signs",
+			signers: []PolicySigner{
+				{MSPID: "Org3MSP", Role: "admin"},
+				{MSPID: "Org4MSP", Role: "admin"},
+			},
+			missingPolicies: []string{"/Channel/Application/Custom"},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			c := org3ConfigTx(t)
+			err := c.Application().SetPolicy("Custom", Policy{Type: SignaturePolicyType, Rule: "AND('Org3MSP.admin', 'Org3MSP.member')", ModPolicy: AdminsPolicyKey})
+			gt.Expect(err).NotTo(HaveOccurred())
+
+			policies, err := c.SatisfiedPolicies(tt.signers)
+			gt.Expect(err).NotTo(HaveOccurred())
+			for _, policy := range tt.expectedPolicies {
+				gt.Expect(policies).To(ContainElement(policy))
+			}
+			for _, policy := range tt.missingPolicies {
+				gt.Expect(policies).NotTo(ContainElement(policy))
+			}
+		})
+	}
+}
+
+func TestSatisfiedPoliciesFailures(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := org3ConfigTx(t)
+	_, err := c.SatisfiedPolicies([]PolicySigner{{MSPID: "Org3MSP", Role: "owner"}})
+	gt.Expect(err).To(MatchError("unknown role 'owner' of signer of MSP Org3MSP"))
+}
+
+func TestPoliciesBrokenWithout(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	c := org3ConfigTx(t)
+
+	policies, err := c.PoliciesBrokenWithout("Org3MSP")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(Equal([]UnsatisfiablePolicy{
+		{
+			Path:   "/Channel/Admins",
+			Reason: "MAJORITY Admins requires 2 satisfiable sub-policies, but only 1 of 2 are",
+		},
+		{
+			Path:   "/Channel/Application/Admins",
+			Reason: "signature policy requires MSPs which are not defined in the channel: Org3MSP",
+		},
+	}))
+
+	// the simulation leaves the config untouched
+	gt.Expect(c.Application().Organization("Org3")).NotTo(BeNil())
+	policies, err = c.UnsatisfiablePolicies()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(policies).To(BeEmpty())
+
+	_, err = c.PoliciesBrokenWithout()
+	gt.Expect(err).To(MatchError("at least one MSP ID is required"))
+
+	_, err = c.PoliciesBrokenWithout("Org4MSP")
+	gt.Expect(err).To(MatchError("MSP Org4MSP is not defined in the channel"))
+}
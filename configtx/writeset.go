@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+)
+
+// WriteSetExport is the write set of the config update of a pending change.
+// It lets approvers review exactly the groups, values and policies the change
+// modifies without being handed the entire channel config. It is stored as a
+// single JSON document, see JSON.
+type WriteSetExport struct {
+	ChannelID string `json:"channel_id"`
+	// Sequence is the sequence of the original config the change applies to.
+	Sequence uint64 `json:"sequence"`
+	// WriteSet is the write set decoded to JSON by protolator, as
+	// configtxlator proto_decode does for the write set of a config update.
+	WriteSet json.RawMessage `json:"write_set"`
+	// MarshaledWriteSet is the marshaled ConfigGroup protobuf of the write
+	// set.
+	MarshaledWriteSet []byte `json:"marshaled_write_set"`
+}
+
+// ExportWriteSet computes the config update of the pending change to the
+// channel with the given ID and returns its write set.
+func (c *ConfigTx) ExportWriteSet(channelID string) (WriteSetExport, error) {
+	if channelID == "" {
+		return WriteSetExport{}, errors.New("channel ID is required")
+	}
+
+	update, err := computeScopedConfigUpdate(c.original, c.updated, c.journal.scope())
+	if err != nil {
+		return WriteSetExport{}, fmt.Errorf("failed to compute update: %v", err)
+	}
+
+	marshaledWriteSet, err := proto.Marshal(update.WriteSet)
+	if err != nil {
+		return WriteSetExport{}, fmt.Errorf("marshaling write set: %v", err)
+	}
+
+	// The values of a bare config group cannot be decoded, as their types
+	// depend on the position of the group in the channel config. A config
+	// update provides that context for its write set.
+	var decoded bytes.Buffer
+	err = protolator.DeepMarshalJSON(&decoded, &cb.ConfigUpdate{
+		ChannelId: channelID,
+		WriteSet:  update.WriteSet,
+	})
+	if err != nil {
+		return WriteSetExport{}, fmt.Errorf("decoding write set: %v", err)
+	}
+
+	decodedUpdate := struct {
+		WriteSet json.RawMessage `json:"write_set"`
+	}{}
+	err = json.Unmarshal(decoded.Bytes(), &decodedUpdate)
+	if err != nil {
+		return WriteSetExport{}, fmt.Errorf("unmarshaling decoded write set: %v", err)
+	}
+
+	return WriteSetExport{
+		ChannelID:         channelID,
+		Sequence:          c.original.Sequence,
+		WriteSet:          decodedUpdate.WriteSet,
+		MarshaledWriteSet: marshaledWriteSet,
+	}, nil
+}
+
+// JSON returns the export encoded as JSON.
+func (e WriteSetExport) JSON() ([]byte, error) {
+	return json.MarshalIndent(e, "", "\t")
+}
@@ -0,0 +1,79 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtx
+
+import (
+	"encoding/json"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+)
+
+func TestExportWriteSet(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	channelGroup, _, err := baseApplicationChannelGroup(t)
+	gt.Expect(err).NotTo(HaveOccurred())
+	c := New(&cb.Config{Sequence: 7, ChannelGroup: channelGroup})
+
+	err = c.Application().Organization("Org1").AddAnchorPeer(Address{Host: "peer0.org1.example.com", Port: 7051})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	export, err := c.ExportWriteSet("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(export.ChannelID).To(Equal("testchannel"))
+	gt.Expect(export.Sequence).To(Equal(uint64(7)))
+
+	marshaledUpdate, err := c.ComputeMarshaledUpdate("testchannel")
+	gt.Expect(err).NotTo(HaveOccurred())
+	configUpdate := &cb.ConfigUpdate{}
+	err = proto.Unmarshal(marshaledUpdate, configUpdate)
+	gt.Expect(err).NotTo(HaveOccurred())
+	writeSet := &cb.ConfigGroup{}
+	err = proto.Unmarshal(export.MarshaledWriteSet, writeSet)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(writeSet, configUpdate.WriteSet)).To(BeTrue())
+
+	// Only the modified org is part of the write set, with its anchor peers
+	// decoded.
+	decoded := struct {
+		Groups map[string]struct {
+			Groups map[string]struct {
+				Values map[string]struct {
+					Value json.RawMessage `json:"value"`
+				} `json:"values"`
+			} `json:"groups"`
+		} `json:"groups"`
+	}{}
+	err = json.Unmarshal(export.WriteSet, &decoded)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(decoded.Groups).To(HaveLen(1))
+	gt.Expect(decoded.Groups[ApplicationGroupKey].Groups).To(HaveLen(1))
+	gt.Expect(decoded.Groups[ApplicationGroupKey].Groups["Org1"].Values).To(HaveKey(AnchorPeersKey))
+	gt.Expect(decoded.Groups[ApplicationGroupKey].Groups["Org1"].Values[AnchorPeersKey].Value).To(MatchJSON(`{
+	"anchor_peers": [
+		{
+			"host": "peer0.org1.example.com",
+			"port": 7051
+		}
+	]
+}`))
+
+	exportJSON, err := export.JSON()
+	gt.Expect(err).NotTo(HaveOccurred())
+	parsed := WriteSetExport{}
+	err = json.Unmarshal(exportJSON, &parsed)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(parsed.MarshaledWriteSet).To(Equal(export.MarshaledWriteSet))
+	gt.Expect(parsed.WriteSet).To(MatchJSON(export.WriteSet))
+
+	_, err = c.ExportWriteSet("")
+	gt.Expect(err).To(MatchError("channel ID is required"))
+}
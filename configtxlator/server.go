@@ -0,0 +1,105 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package configtxlator provides a gRPC service exposing the deep JSON
+// encoding and decoding of protolator and the config update computation of
+// configtx. The service is defined in configtxlator.proto, from which clients
+// in other languages can be generated.
+package configtxlator
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. configtxlator.proto
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+
+	_ "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	_ "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	_ "github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
+	_ "github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/etcdraft"
+	_ "github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/smartbft"
+	_ "github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/configtx"
+	"github.com/hyperledger/fabric-config/protolator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the Configtxlator service. Message types are resolved by
+// their fully qualified name among the registered protobuf messages, which
+// include every Fabric message. Invalid requests fail with the
+// InvalidArgument code.
+type Server struct{}
+
+// NewServer returns a Configtxlator server.
+func NewServer() *Server {
+	return &Server{}
+}
+
+// Encode converts the deep JSON representation of a message into the
+// marshaled message.
+func (s *Server) Encode(ctx context.Context, req *EncodeRequest) (*EncodeResponse, error) {
+	msg, err := newMessage(req.MessageType)
+	if err != nil {
+		return nil, err
+	}
+
+	err = protolator.DeepUnmarshalJSON(bytes.NewReader(req.Json), msg)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error decoding input: %v", err)
+	}
+
+	marshaled, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "error marshaling message: %v", err)
+	}
+
+	return &EncodeResponse{Message: marshaled}, nil
+}
+
+// Decode converts a marshaled message into its deep JSON representation.
+func (s *Server) Decode(ctx context.Context, req *DecodeRequest) (*DecodeResponse, error) {
+	msg, err := newMessage(req.MessageType)
+	if err != nil {
+		return nil, err
+	}
+
+	err = proto.Unmarshal(req.Message, msg)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error unmarshaling message: %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = protolator.DeepMarshalJSON(&buf, msg)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error encoding output: %v", err)
+	}
+
+	return &DecodeResponse{Json: buf.Bytes()}, nil
+}
+
+// ComputeUpdate computes the config update between two marshaled configs.
+func (s *Server) ComputeUpdate(ctx context.Context, req *ComputeUpdateRequest) (*ComputeUpdateResponse, error) {
+	configUpdate, err := configtx.ComputeMarshaledUpdateFromConfigs(req.Original, req.Updated, req.ChannelId)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "error computing config update: %v", err)
+	}
+
+	return &ComputeUpdateResponse{ConfigUpdate: configUpdate}, nil
+}
+
+// newMessage returns a new message of the registered type with the given
+// fully qualified name.
+func newMessage(messageType string) (proto.Message, error) {
+	msgType := proto.MessageType(messageType)
+	if msgType == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "message type %s not found", messageType)
+	}
+
+	return reflect.New(msgType.Elem()).Interface().(proto.Message), nil
+}
@@ -0,0 +1,197 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package configtxlator
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	. "github.com/onsi/gomega"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// startServer serves the Configtxlator service on a local port and returns a
+// client connected to it along with a function stopping both.
+func startServer(t *testing.T) (ConfigtxlatorClient, func()) {
+	gt := NewGomegaWithT(t)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	s := grpc.NewServer()
+	RegisterConfigtxlatorServer(s, NewServer())
+	go s.Serve(lis)
+
+	cc, err := grpc.Dial(lis.Addr().String(), grpc.WithInsecure())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return NewConfigtxlatorClient(cc), func() {
+		cc.Close()
+		s.Stop()
+	}
+}
+
+func baseConfig() *cb.Config {
+	return &cb.Config{
+		Sequence: 1,
+		ChannelGroup: &cb.ConfigGroup{
+			Groups:    map[string]*cb.ConfigGroup{},
+			Values:    map[string]*cb.ConfigValue{},
+			Policies:  map[string]*cb.ConfigPolicy{},
+			ModPolicy: "Admins",
+		},
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	client, stop := startServer(t)
+	defer stop()
+
+	config := baseConfig()
+	config.ChannelGroup.Values["Consortium"] = &cb.ConfigValue{
+		Value:     marshalOrPanic(&cb.Consortium{Name: "SampleConsortium"}),
+		ModPolicy: "Admins",
+	}
+
+	decoded, err := client.Decode(context.Background(), &DecodeRequest{
+		MessageType: "common.Config",
+		Message:     marshalOrPanic(config),
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	tree := map[string]interface{}{}
+	gt.Expect(json.Unmarshal(decoded.Json, &tree)).To(Succeed())
+	gt.Expect(tree).To(HaveKeyWithValue("channel_group", HaveKeyWithValue("values", HaveKeyWithValue("Consortium",
+		HaveKeyWithValue("value", HaveKeyWithValue("name", "SampleConsortium"))))))
+
+	encoded, err := client.Encode(context.Background(), &EncodeRequest{
+		MessageType: "common.Config",
+		Json:        decoded.Json,
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	roundTripped := &cb.Config{}
+	gt.Expect(proto.Unmarshal(encoded.Message, roundTripped)).To(Succeed())
+	gt.Expect(proto.Equal(roundTripped, config)).To(BeTrue())
+}
+
+func TestComputeUpdate(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	client, stop := startServer(t)
+	defer stop()
+
+	original := baseConfig()
+	updated := baseConfig()
+	updated.ChannelGroup.Values["Consortium"] = &cb.ConfigValue{
+		Value:     marshalOrPanic(&cb.Consortium{Name: "SampleConsortium"}),
+		ModPolicy: "Admins",
+	}
+
+	resp, err := client.ComputeUpdate(context.Background(), &ComputeUpdateRequest{
+		ChannelId: "testchannel",
+		Original:  marshalOrPanic(original),
+		Updated:   marshalOrPanic(updated),
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	configUpdate := &cb.ConfigUpdate{}
+	gt.Expect(proto.Unmarshal(resp.ConfigUpdate, configUpdate)).To(Succeed())
+	gt.Expect(configUpdate.ChannelId).To(Equal("testchannel"))
+	gt.Expect(configUpdate.WriteSet.Values).To(HaveKey("Consortium"))
+	gt.Expect(configUpdate.WriteSet.Version).To(Equal(uint64(1)))
+}
+
+func TestServerFailures(t *testing.T) {
+	t.Parallel()
+
+	client, stop := startServer(t)
+	defer stop()
+
+	tests := []struct {
+		testName    string
+		call        func() error
+		expectedErr string
+	}{
+		{
+			testName: "When the message type of an encode request is unknown",
+			call: func() error {
+				_, err := client.Encode(context.Background(), &EncodeRequest{MessageType: "common.Unknown", Json: []byte("{}")})
+				return err
+			},
+			expectedErr: "message type common.Unknown not found",
+		},
+		{
+			testName: "When the JSON of an encode request is invalid",
+			call: func() error {
+				_, err := client.Encode(context.Background(), &EncodeRequest{MessageType: "common.Config", Json: []byte("{")})
+				return err
+			},
+			expectedErr: "error decoding input: ",
+		},
+		{
+			testName: "When the message of a decode request is invalid",
+			call: func() error {
+				_, err := client.Decode(context.Background(), &DecodeRequest{MessageType: "common.Config", Message: []byte("garbage")})
+				return err
+			},
+			expectedErr: "error unmarshaling message: ",
+		},
+		{
+			testName: "When the configs do not differ",
+			call: func() error {
+				_, err := client.ComputeUpdate(context.Background(), &ComputeUpdateRequest{
+					ChannelId: "testchannel",
+					Original:  marshalOrPanic(baseConfig()),
+					Updated:   marshalOrPanic(baseConfig()),
+				})
+				return err
+			},
+			expectedErr: "error computing config update: failed to compute update: ",
+		},
+		{
+			testName: "When the channel ID is missing",
+			call: func() error {
+				_, err := client.ComputeUpdate(context.Background(), &ComputeUpdateRequest{
+					Original: marshalOrPanic(baseConfig()),
+					Updated:  marshalOrPanic(baseConfig()),
+				})
+				return err
+			},
+			expectedErr: "error computing config update: channel ID is required",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			err := tt.call()
+			gt.Expect(status.Code(err)).To(Equal(codes.InvalidArgument))
+			gt.Expect(status.Convert(err).Message()).To(ContainSubstring(tt.expectedErr))
+		})
+	}
+}
+
+func marshalOrPanic(msg proto.Message) []byte {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -1,10 +1,27 @@
 module github.com/SmartBFT-Go/fabric-config
 
-go 1.14
+// The minimum Go version is set by the dependencies:
+//   - golang.org/x/crypto v0.14.0, whose pbkdf2 package decrypts PKCS#8
+//     encrypted private keys, requires Go 1.17.
+//   - software.sslmate.com/src/go-pkcs12 v0.4.0, which decodes PKCS#12
+//     bundles, requires Go 1.19.
+go 1.19
 
 require (
 	github.com/Knetic/govaluate v3.0.0+incompatible
 	github.com/SmartBFT-Go/fabric-protos-go/v2 v2.3.0
 	github.com/golang/protobuf v1.3.3
 	github.com/onsi/gomega v1.9.0
+	golang.org/x/crypto v0.14.0
+	google.golang.org/grpc v1.27.0
+	gopkg.in/yaml.v2 v2.2.4
+	software.sslmate.com/src/go-pkcs12 v0.4.0
+)
+
+require (
+	golang.org/x/net v0.10.0 // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	golang.org/x/text v0.13.0 // indirect
+	golang.org/x/xerrors v0.0.0-20190717185122-a985d3407aa7 // indirect
+	google.golang.org/genproto v0.0.0-20190819201941-24fa4b261c55 // indirect
 )
@@ -79,7 +79,9 @@ type VariablyOpaqueFieldProto interface {
 	VariablyOpaqueFields() []string
 
 	// VariablyOpaqueFieldProto returns a newly allocated proto message of the correct
-	// type for the field name.
+	// type for the field name.  If the type cannot be determined but the field is
+	// valid, it may return a nil message and nil error, in which case the field is
+	// left opaque and encoded as base64.
 	VariablyOpaqueFieldProto(name string) (proto.Message, error)
 }
 
@@ -101,7 +103,9 @@ type VariablyOpaqueSliceFieldProto interface {
 	VariablyOpaqueSliceFields() []string
 
 	// VariablyOpaqueFieldProto returns a newly allocated proto message of the correct
-	// type for the field name.
+	// type for the field name.  As for VariablyOpaqueFieldProto, it may return a nil
+	// message and nil error, in which case the element is left opaque and encoded as
+	// base64.
 	VariablyOpaqueSliceFieldProto(name string, index int) (proto.Message, error)
 }
 
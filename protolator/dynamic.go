@@ -22,25 +22,25 @@ import (
 	"github.com/golang/protobuf/proto"
 )
 
-func dynamicFrom(dynamicMsg func(underlying proto.Message) (proto.Message, error), value interface{}, destType reflect.Type) (reflect.Value, error) {
+func dynamicFrom(dynamicMsg func(underlying proto.Message) (proto.Message, error), value interface{}, destType reflect.Type, o *options) (reflect.Value, error) {
 	tree := value.(map[string]interface{}) // Safe, already checked
 	uMsg := reflect.New(destType.Elem())
 	nMsg, err := dynamicMsg(uMsg.Interface().(proto.Message)) // Safe, already checked
 	if err != nil {
 		return reflect.Value{}, err
 	}
-	if err := recursivelyPopulateMessageFromTree(tree, nMsg); err != nil {
+	if err := recursivelyPopulateMessageFromTree(tree, nMsg, o); err != nil {
 		return reflect.Value{}, err
 	}
 	return uMsg, nil
 }
 
-func dynamicTo(dynamicMsg func(underlying proto.Message) (proto.Message, error), value reflect.Value) (interface{}, error) {
+func dynamicTo(dynamicMsg func(underlying proto.Message) (proto.Message, error), value reflect.Value, o *options) (interface{}, error) {
 	nMsg, err := dynamicMsg(value.Interface().(proto.Message)) // Safe, already checked
 	if err != nil {
 		return nil, err
 	}
-	return recursivelyCreateTreeFromMessage(nMsg)
+	return recursivelyCreateTreeFromMessage(nMsg, o)
 }
 
 type dynamicFieldFactory struct{}
@@ -54,7 +54,7 @@ func (dff dynamicFieldFactory) Handles(msg proto.Message, fieldName string, fiel
 	return stringInSlice(fieldName, dynamicProto.DynamicFields())
 }
 
-func (dff dynamicFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (dff dynamicFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	dynamicProto, _ := msg.(DynamicFieldProto) // Type checked in Handles
 
 	return &plainField{
@@ -68,12 +68,12 @@ func (dff dynamicFieldFactory) NewProtoField(msg proto.Message, fieldName string
 		populateFrom: func(v interface{}, dT reflect.Type) (reflect.Value, error) {
 			return dynamicFrom(func(underlying proto.Message) (proto.Message, error) {
 				return dynamicProto.DynamicFieldProto(fieldName, underlying)
-			}, v, dT)
+			}, v, dT, o)
 		},
 		populateTo: func(v reflect.Value) (interface{}, error) {
 			return dynamicTo(func(underlying proto.Message) (proto.Message, error) {
 				return dynamicProto.DynamicFieldProto(fieldName, underlying)
-			}, v)
+			}, v, o)
 		},
 	}, nil
 }
@@ -89,7 +89,7 @@ func (dmff dynamicMapFieldFactory) Handles(msg proto.Message, fieldName string,
 	return stringInSlice(fieldName, dynamicProto.DynamicMapFields())
 }
 
-func (dmff dynamicMapFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (dmff dynamicMapFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	dynamicProto := msg.(DynamicMapFieldProto) // Type checked by Handles
 
 	return &mapField{
@@ -103,12 +103,12 @@ func (dmff dynamicMapFieldFactory) NewProtoField(msg proto.Message, fieldName st
 		populateFrom: func(k string, v interface{}, dT reflect.Type) (reflect.Value, error) {
 			return dynamicFrom(func(underlying proto.Message) (proto.Message, error) {
 				return dynamicProto.DynamicMapFieldProto(fieldName, k, underlying)
-			}, v, dT)
+			}, v, dT, o)
 		},
 		populateTo: func(k string, v reflect.Value) (interface{}, error) {
 			return dynamicTo(func(underlying proto.Message) (proto.Message, error) {
 				return dynamicProto.DynamicMapFieldProto(fieldName, k, underlying)
-			}, v)
+			}, v, o)
 		},
 	}, nil
 }
@@ -124,7 +124,7 @@ func (dmff dynamicSliceFieldFactory) Handles(msg proto.Message, fieldName string
 	return stringInSlice(fieldName, dynamicProto.DynamicSliceFields())
 }
 
-func (dmff dynamicSliceFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (dmff dynamicSliceFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	dynamicProto := msg.(DynamicSliceFieldProto) // Type checked by Handles
 
 	return &sliceField{
@@ -138,12 +138,12 @@ func (dmff dynamicSliceFieldFactory) NewProtoField(msg proto.Message, fieldName
 		populateFrom: func(i int, v interface{}, dT reflect.Type) (reflect.Value, error) {
 			return dynamicFrom(func(underlying proto.Message) (proto.Message, error) {
 				return dynamicProto.DynamicSliceFieldProto(fieldName, i, underlying)
-			}, v, dT)
+			}, v, dT, o)
 		},
 		populateTo: func(i int, v reflect.Value) (interface{}, error) {
 			return dynamicTo(func(underlying proto.Message) (proto.Message, error) {
 				return dynamicProto.DynamicSliceFieldProto(fieldName, i, underlying)
-			}, v)
+			}, v, o)
 		},
 	}, nil
 }
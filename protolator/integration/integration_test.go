@@ -8,39 +8,28 @@ package integration
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"io/ioutil"
 	"testing"
 
 	cb "github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/ledger/rwset"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/ledger/rwset/kvrwset"
 	mb "github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	pb "github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/any"
 	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-config/protolator/protoext/customext"
+	"github.com/hyperledger/fabric-config/protolator/protolatortest"
 	. "github.com/onsi/gomega"
 )
 
 func bidirectionalMarshal(t *testing.T, doc proto.Message) {
-	gt := NewGomegaWithT(t)
-
-	var buffer bytes.Buffer
-
-	err := protolator.DeepMarshalJSON(&buffer, doc)
-	gt.Expect(err).NotTo(HaveOccurred())
-
-	newRoot := proto.Clone(doc)
-	newRoot.Reset()
-	err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), newRoot)
-	gt.Expect(err).NotTo(HaveOccurred())
-
-	// Note, we cannot do an equality check between newRoot and sampleDoc
-	// because of the nondeterministic nature of binary proto marshaling
-	// So instead we re-marshal to JSON which is a deterministic marshaling
-	// and compare equality there instead
-
-	var remarshaled bytes.Buffer
-	err = protolator.DeepMarshalJSON(&remarshaled, newRoot)
-	gt.Expect(err).NotTo(HaveOccurred())
-	gt.Expect(remarshaled.String()).To(MatchJSON(buffer.String()))
+	protolatortest.AssertRoundTrip(t, doc)
 }
 
 func TestConfigUpdate(t *testing.T) {
@@ -290,6 +279,64 @@ func TestChannelCreationPolicy(t *testing.T) {
 	bidirectionalMarshal(t, cu)
 }
 
+func TestCustomConfigValues(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	vendorSettings, err := ptypes.MarshalAny(&cb.Capabilities{
+		Capabilities: map[string]*cb.Capability{"VendorFeature": {}},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	cu := &cb.ConfigUpdate{
+		WriteSet: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				"Orderer": {
+					Groups: map[string]*cb.ConfigGroup{
+						"OrdererOrg": {
+							Values: map[string]*cb.ConfigValue{
+								"VendorSettings": {
+									Value: protoMarshalOrPanic(vendorSettings),
+								},
+							},
+						},
+					},
+				},
+				"Application": {
+					Values: map[string]*cb.ConfigValue{
+						"VendorLimits": {
+							Value: protoMarshalOrPanic(&pb.AnchorPeers{
+								AnchorPeers: []*pb.AnchorPeer{{Host: "vendor.example.com", Port: 7051}},
+							}),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	var buffer bytes.Buffer
+	err = protolator.DeepMarshalJSON(&buffer, &cb.ConfigUpdateEnvelope{ConfigUpdate: protoMarshalOrPanic(cu)})
+	// either unregistered value may be reached first, depending on map order
+	gt.Expect(err).To(MatchError(Or(
+		ContainSubstring("unknown Orderer Org ConfigValue name: VendorSettings"),
+		ContainSubstring("Unknown Application ConfigValue name: VendorLimits"),
+	)))
+
+	err = customext.RegisterConfigValue("VendorSettings", func() proto.Message { return &any.Any{} })
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = customext.RegisterConfigValue("VendorLimits", func() proto.Message { return &pb.AnchorPeers{} })
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	buffer.Reset()
+	err = protolator.DeepMarshalJSON(&buffer, &cb.ConfigUpdateEnvelope{ConfigUpdate: protoMarshalOrPanic(cu)})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(buffer.String()).To(ContainSubstring(`"@type": "type.googleapis.com/common.Capabilities"`))
+	gt.Expect(buffer.String()).To(ContainSubstring(`"VendorFeature": {}`))
+	gt.Expect(buffer.String()).To(ContainSubstring(`"host": "vendor.example.com"`))
+
+	bidirectionalMarshal(t, &cb.ConfigUpdateEnvelope{ConfigUpdate: protoMarshalOrPanic(cu)})
+}
+
 func TestStaticMarshal(t *testing.T) {
 	gt := NewGomegaWithT(t)
 
@@ -306,13 +353,7 @@ func TestStaticMarshal(t *testing.T) {
 	err = proto.Unmarshal(blockBin, block)
 	gt.Expect(err).NotTo(HaveOccurred())
 
-	jsonBin, err := ioutil.ReadFile("testdata/block.json")
-	gt.Expect(err).NotTo(HaveOccurred())
-
-	buf := &bytes.Buffer{}
-	err = protolator.DeepMarshalJSON(buf, block)
-	gt.Expect(err).NotTo(HaveOccurred())
-	gt.Expect(buf).To(MatchJSON(jsonBin))
+	protolatortest.AssertGoldenFile(t, block, "testdata/block.json")
 }
 
 // protoMarshalOrPanic serializes a protobuf message and panics if this
@@ -325,3 +366,235 @@ func protoMarshalOrPanic(pb proto.Message) []byte {
 
 	return data
 }
+
+func TestProtoJSON(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	blockBin, err := ioutil.ReadFile("testdata/block.pb")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	block := &cb.Block{}
+	err = proto.Unmarshal(blockBin, block)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	var deepJSON, protoJSON bytes.Buffer
+	err = protolator.DeepMarshalJSON(&deepJSON, block)
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = protolator.MarshalProtoJSON(&protoJSON, block)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(protoJSON.String()).NotTo(ContainSubstring("channel_group"))
+
+	// the standard mapping leaves opaque fields encoded, so the binary
+	// message is restored exactly
+	decoded := &cb.Block{}
+	err = protolator.UnmarshalProtoJSON(bytes.NewReader(protoJSON.Bytes()), decoded)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(decoded, block)).To(BeTrue())
+
+	var converted bytes.Buffer
+	err = protolator.ProtoJSONToDeepJSON(&converted, bytes.NewReader(protoJSON.Bytes()), &cb.Block{})
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(converted.Bytes()).To(MatchJSON(deepJSON.Bytes()))
+
+	converted.Reset()
+	err = protolator.DeepJSONToProtoJSON(&converted, bytes.NewReader(deepJSON.Bytes()), &cb.Block{})
+	gt.Expect(err).NotTo(HaveOccurred())
+	decoded = &cb.Block{}
+	err = protolator.UnmarshalProtoJSON(bytes.NewReader(converted.Bytes()), decoded)
+	gt.Expect(err).NotTo(HaveOccurred())
+	protolatortest.AssertGoldenFile(t, decoded, "testdata/block.json")
+
+	for _, doc := range [][]byte{deepJSON.Bytes(), protoJSON.Bytes()} {
+		decoded := &cb.Block{}
+		err = protolator.UnmarshalAnyJSON(bytes.NewReader(doc), decoded)
+		gt.Expect(err).NotTo(HaveOccurred())
+		protolatortest.AssertGoldenFile(t, decoded, "testdata/block.json")
+	}
+
+	err = protolator.UnmarshalAnyJSON(bytes.NewReader([]byte("{")), &cb.Block{})
+	gt.Expect(err).To(HaveOccurred())
+}
+
+func TestRedactedJSON(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	payload, err := proto.Marshal(&pb.ChaincodeProposalPayload{
+		Input: protoMarshalOrPanic(&pb.ChaincodeInvocationSpec{
+			ChaincodeSpec: &pb.ChaincodeSpec{
+				ChaincodeId: &pb.ChaincodeID{Name: "asset"},
+				Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte("CreateAsset")}},
+			},
+		}),
+		TransientMap: map[string][]byte{"asset_properties": []byte("secret")},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	proposal := &pb.SignedProposal{
+		ProposalBytes: protoMarshalOrPanic(&pb.Proposal{
+			Header: protoMarshalOrPanic(&cb.Header{
+				ChannelHeader: protoMarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_ENDORSER_TRANSACTION),
+					ChannelId: "mychannel",
+				}),
+			}),
+			Payload: payload,
+		}),
+		Signature: []byte("signature"),
+	}
+
+	pvtRWSet := &rwset.TxPvtReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+			{
+				Namespace: "asset",
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+					{
+						CollectionName: "Org1MSPPrivateCollection",
+						Rwset: protoMarshalOrPanic(&kvrwset.KVRWSet{
+							Reads:  []*kvrwset.KVRead{{Key: "asset1"}},
+							Writes: []*kvrwset.KVWrite{{Key: "asset2", Value: []byte("secret")}},
+						}),
+					},
+				},
+			},
+		},
+	}
+
+	var decoded bytes.Buffer
+	err = protolator.DeepMarshalJSON(&decoded, proposal)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(decoded.String()).To(ContainSubstring(base64.StdEncoding.EncodeToString([]byte("CreateAsset"))))
+	gt.Expect(decoded.String()).To(ContainSubstring(base64.StdEncoding.EncodeToString([]byte("secret"))))
+
+	hash := func(b []byte) string {
+		h := sha256.Sum256(b)
+		return base64.StdEncoding.EncodeToString(h[:])
+	}
+
+	tests := []struct {
+		mode      protolator.RedactionMode
+		secret    string
+		asset1Key string
+		asset2Key string
+	}{
+		{
+			mode:      protolator.RedactPrivateData,
+			secret:    protolator.RedactedValue,
+			asset1Key: protolator.RedactedValue,
+			asset2Key: protolator.RedactedValue,
+		},
+		{
+			mode:      protolator.HashPrivateData,
+			secret:    hash([]byte("secret")),
+			asset1Key: hash([]byte("asset1")),
+			asset2Key: hash([]byte("asset2")),
+		},
+	}
+
+	for _, tt := range tests {
+		var redacted bytes.Buffer
+		err = protolator.DeepMarshalRedactedJSON(&redacted, proposal, tt.mode)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(redacted.String()).NotTo(ContainSubstring(base64.StdEncoding.EncodeToString([]byte("secret"))))
+
+		var tree struct {
+			ProposalBytes struct {
+				Payload struct {
+					Input struct {
+						ChaincodeSpec struct {
+							Input struct {
+								Args []string `json:"args"`
+							} `json:"input"`
+						} `json:"chaincode_spec"`
+					} `json:"input"`
+					TransientMap map[string]string `json:"TransientMap"`
+				} `json:"payload"`
+			} `json:"proposal_bytes"`
+		}
+		err = json.Unmarshal(redacted.Bytes(), &tree)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(tree.ProposalBytes.Payload.TransientMap).To(Equal(map[string]string{"asset_properties": tt.secret}))
+		gt.Expect(tree.ProposalBytes.Payload.Input.ChaincodeSpec.Input.Args).To(Equal([]string{base64.StdEncoding.EncodeToString([]byte("CreateAsset"))}))
+
+		redacted.Reset()
+		err = protolator.DeepMarshalRedactedJSON(&redacted, pvtRWSet, tt.mode)
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		var pvtTree struct {
+			NsPvtRwset []struct {
+				CollectionPvtRwset []struct {
+					CollectionName string `json:"collection_name"`
+					Rwset          struct {
+						Reads []struct {
+							Key string `json:"key"`
+						} `json:"reads"`
+						Writes []struct {
+							Key   string `json:"key"`
+							Value string `json:"value"`
+						} `json:"writes"`
+					} `json:"rwset"`
+				} `json:"collection_pvt_rwset"`
+			} `json:"ns_pvt_rwset"`
+		}
+		err = json.Unmarshal(redacted.Bytes(), &pvtTree)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(pvtTree.NsPvtRwset).To(HaveLen(1))
+		gt.Expect(pvtTree.NsPvtRwset[0].CollectionPvtRwset).To(HaveLen(1))
+		collection := pvtTree.NsPvtRwset[0].CollectionPvtRwset[0]
+		gt.Expect(collection.CollectionName).To(Equal("Org1MSPPrivateCollection"))
+		gt.Expect(collection.Rwset.Reads).To(HaveLen(1))
+		gt.Expect(collection.Rwset.Reads[0].Key).To(Equal(tt.asset1Key))
+		gt.Expect(collection.Rwset.Writes).To(HaveLen(1))
+		gt.Expect(collection.Rwset.Writes[0].Key).To(Equal(tt.asset2Key))
+		gt.Expect(collection.Rwset.Writes[0].Value).To(Equal(tt.secret))
+	}
+}
+
+func TestRedactedJSONUndecodedRWSet(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	rwsetBytes := protoMarshalOrPanic(&kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{{Key: "asset2", Value: []byte("secret")}},
+	})
+	// rwsets of data models other than KV are not decoded
+	pvtRWSet := &rwset.TxPvtReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_DataModel(1),
+		NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+			{
+				Namespace: "asset",
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+					{CollectionName: "Org1MSPPrivateCollection", Rwset: rwsetBytes},
+				},
+			},
+		},
+	}
+
+	var decoded bytes.Buffer
+	err := protolator.DeepMarshalJSON(&decoded, pvtRWSet)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(decoded.String()).To(ContainSubstring(base64.StdEncoding.EncodeToString(rwsetBytes)))
+
+	hash := sha256.Sum256(rwsetBytes)
+	for mode, expected := range map[protolator.RedactionMode]string{
+		protolator.RedactPrivateData: protolator.RedactedValue,
+		protolator.HashPrivateData:   base64.StdEncoding.EncodeToString(hash[:]),
+	} {
+		var redacted bytes.Buffer
+		err = protolator.DeepMarshalRedactedJSON(&redacted, pvtRWSet, mode)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(redacted.String()).NotTo(ContainSubstring(base64.StdEncoding.EncodeToString(rwsetBytes)))
+
+		var pvtTree struct {
+			NsPvtRwset []struct {
+				CollectionPvtRwset []struct {
+					CollectionName string `json:"collection_name"`
+					Rwset          string `json:"rwset"`
+				} `json:"collection_pvt_rwset"`
+			} `json:"ns_pvt_rwset"`
+		}
+		err = json.Unmarshal(redacted.Bytes(), &pvtTree)
+		gt.Expect(err).NotTo(HaveOccurred())
+		collection := pvtTree.NsPvtRwset[0].CollectionPvtRwset[0]
+		gt.Expect(collection.CollectionName).To(Equal("Org1MSPPrivateCollection"))
+		gt.Expect(collection.Rwset).To(Equal(expected))
+	}
+}
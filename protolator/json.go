@@ -16,7 +16,6 @@ import (
 
 	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
-	"github.com/hyperledger/fabric-config/protolator/protoext"
 )
 
 // MostlyDeterministicMarshal is _NOT_ the function you are looking for.
@@ -41,8 +40,9 @@ type protoFieldFactory interface {
 
 	// NewProtoField should create a backing protoField implementor
 	// Note that the fieldValue may represent nil, so the fieldType is also
-	// included (as reflecting the type of a nil value causes a panic)
-	NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error)
+	// included (as reflecting the type of a nil value causes a panic).
+	// The options are those of the call converting the message.
+	NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error)
 }
 
 type protoField interface {
@@ -297,7 +297,7 @@ var fieldFactories = []protoFieldFactory{
 	nestedFieldFactory{},
 }
 
-func protoFields(msg proto.Message, uMsg proto.Message) ([]protoField, error) {
+func protoFields(msg proto.Message, uMsg proto.Message, o *options) ([]protoField, error) {
 	var result []protoField
 
 	pmVal := reflect.ValueOf(uMsg)
@@ -333,7 +333,7 @@ func protoFields(msg proto.Message, uMsg proto.Message) ([]protoField, error) {
 				continue
 			}
 
-			field, err := factory.NewProtoField(msg, fieldName, fieldType, fieldValue)
+			field, err := factory.NewProtoField(msg, fieldName, fieldType, fieldValue, o)
 			if err != nil {
 				return nil, err
 			}
@@ -351,7 +351,7 @@ func protoFields(msg proto.Message, uMsg proto.Message) ([]protoField, error) {
 	return result, nil
 }
 
-func recursivelyCreateTreeFromMessage(msg proto.Message) (tree map[string]interface{}, err error) {
+func recursivelyCreateTreeFromMessage(msg proto.Message, o *options) (tree map[string]interface{}, err error) {
 	defer func() {
 		// Because this function is recursive, it's difficult to determine which level
 		// of the proto the error originated from, this wrapper leaves breadcrumbs for debugging
@@ -360,13 +360,13 @@ func recursivelyCreateTreeFromMessage(msg proto.Message) (tree map[string]interf
 		}
 	}()
 
-	msg = protoext.Decorate(msg)
+	msg = o.decorate(msg)
 	uMsg := msg
 	if decorated, ok := msg.(DecoratedProto); ok {
 		uMsg = decorated.Underlying()
 	}
 
-	fields, err := protoFields(msg, uMsg)
+	fields, err := protoFields(msg, uMsg, o)
 	if err != nil {
 		return nil, err
 	}
@@ -399,8 +399,8 @@ func recursivelyCreateTreeFromMessage(msg proto.Message) (tree map[string]interf
 // marshaled messages as base64 (like the standard proto encoding), these nested messages are remarshaled
 // as the JSON representation of those messages.  This is done so that the JSON representation is as non-binary
 // and human readable as possible.
-func DeepMarshalJSON(w io.Writer, msg proto.Message) error {
-	root, err := recursivelyCreateTreeFromMessage(msg)
+func DeepMarshalJSON(w io.Writer, msg proto.Message, opts ...Option) error {
+	root, err := recursivelyCreateTreeFromMessage(msg, newOptions(opts))
 	if err != nil {
 		return err
 	}
@@ -410,7 +410,7 @@ func DeepMarshalJSON(w io.Writer, msg proto.Message) error {
 	return encoder.Encode(root)
 }
 
-func recursivelyPopulateMessageFromTree(tree map[string]interface{}, msg proto.Message) (err error) {
+func recursivelyPopulateMessageFromTree(tree map[string]interface{}, msg proto.Message, o *options) (err error) {
 	defer func() {
 		// Because this function is recursive, it's difficult to determine which level
 		// of the proto the error orginated from, this wrapper leaves breadcrumbs for debugging
@@ -419,13 +419,13 @@ func recursivelyPopulateMessageFromTree(tree map[string]interface{}, msg proto.M
 		}
 	}()
 
-	msg = protoext.Decorate(msg)
+	msg = o.decorate(msg)
 	uMsg := msg
 	if decorated, ok := msg.(DecoratedProto); ok {
 		uMsg = decorated.Underlying()
 	}
 
-	fields, err := protoFields(msg, uMsg)
+	fields, err := protoFields(msg, uMsg, o)
 	if err != nil {
 		return err
 	}
@@ -460,7 +460,7 @@ func recursivelyPopulateMessageFromTree(tree map[string]interface{}, msg proto.M
 
 // DeepUnmarshalJSON takes JSON output as generated by DeepMarshalJSON and decodes it into msg
 // This includes re-marshaling the expanded nested elements to binary form
-func DeepUnmarshalJSON(r io.Reader, msg proto.Message) error {
+func DeepUnmarshalJSON(r io.Reader, msg proto.Message, opts ...Option) error {
 	b, err := ioutil.ReadAll(r)
 	if err != nil {
 		return err
@@ -471,5 +471,5 @@ func DeepUnmarshalJSON(r io.Reader, msg proto.Message) error {
 		return err
 	}
 
-	return recursivelyPopulateMessageFromTree(root, msg)
+	return recursivelyPopulateMessageFromTree(root, msg, newOptions(opts))
 }
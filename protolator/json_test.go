@@ -41,7 +41,7 @@ func (tpff *testProtoPlainFieldFactory) Handles(msg proto.Message, fieldName str
 	return fieldName == "plain_field"
 }
 
-func (tpff *testProtoPlainFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (tpff *testProtoPlainFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	return &plainField{
 		baseField: baseField{
 			msg:   msg,
@@ -111,7 +111,7 @@ func (tpff *testProtoMapFieldFactory) Handles(msg proto.Message, fieldName strin
 	return fieldName == "map_field"
 }
 
-func (tpff *testProtoMapFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (tpff *testProtoMapFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	return &mapField{
 		baseField: baseField{
 			msg:   msg,
@@ -181,7 +181,7 @@ func (tpff *testProtoSliceFieldFactory) Handles(msg proto.Message, fieldName str
 	return fieldName == "slice_field"
 }
 
-func (tpff *testProtoSliceFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (tpff *testProtoSliceFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	return &sliceField{
 		baseField: baseField{
 			msg:   msg,
@@ -245,7 +245,7 @@ func (tpff testProtoFailFactory) Handles(msg proto.Message, fieldName string, fi
 	return true
 }
 
-func (tpff testProtoFailFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (tpff testProtoFailFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	return nil, fmt.Errorf("Intentionally failing")
 }
 
@@ -23,19 +23,19 @@ import (
 	"github.com/golang/protobuf/ptypes/timestamp"
 )
 
-func nestedFrom(value interface{}, destType reflect.Type) (reflect.Value, error) {
+func nestedFrom(value interface{}, destType reflect.Type, o *options) (reflect.Value, error) {
 	tree := value.(map[string]interface{}) // Safe, already checked
 	result := reflect.New(destType.Elem())
 	nMsg := result.Interface().(proto.Message) // Safe, already checked
-	if err := recursivelyPopulateMessageFromTree(tree, nMsg); err != nil {
+	if err := recursivelyPopulateMessageFromTree(tree, nMsg, o); err != nil {
 		return reflect.Value{}, err
 	}
 	return result, nil
 }
 
-func nestedTo(value reflect.Value) (interface{}, error) {
+func nestedTo(value reflect.Value, o *options) (interface{}, error) {
 	nMsg := value.Interface().(proto.Message) // Safe, already checked
-	return recursivelyCreateTreeFromMessage(nMsg)
+	return recursivelyCreateTreeFromMessage(nMsg, o)
 }
 
 var timestampType = reflect.TypeOf(&timestamp.Timestamp{})
@@ -48,7 +48,7 @@ func (nff nestedFieldFactory) Handles(msg proto.Message, fieldName string, field
 	return fieldType.Kind() == reflect.Ptr && fieldType.AssignableTo(protoMsgType) && !fieldType.AssignableTo(timestampType)
 }
 
-func (nff nestedFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (nff nestedFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	return &plainField{
 		baseField: baseField{
 			msg:   msg,
@@ -57,8 +57,12 @@ func (nff nestedFieldFactory) NewProtoField(msg proto.Message, fieldName string,
 			vType: fieldType,
 			value: fieldValue,
 		},
-		populateFrom: nestedFrom,
-		populateTo:   nestedTo,
+		populateFrom: func(v interface{}, dT reflect.Type) (reflect.Value, error) {
+			return nestedFrom(v, dT, o)
+		},
+		populateTo: func(v reflect.Value) (interface{}, error) {
+			return nestedTo(v, o)
+		},
 	}, nil
 }
 
@@ -68,7 +72,7 @@ func (nmff nestedMapFieldFactory) Handles(msg proto.Message, fieldName string, f
 	return fieldType.Kind() == reflect.Map && fieldType.Elem().AssignableTo(protoMsgType) && !fieldType.Elem().AssignableTo(timestampType) && fieldType.Key().Kind() == reflect.String
 }
 
-func (nmff nestedMapFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (nmff nestedMapFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	return &mapField{
 		baseField: baseField{
 			msg:   msg,
@@ -78,10 +82,10 @@ func (nmff nestedMapFieldFactory) NewProtoField(msg proto.Message, fieldName str
 			value: fieldValue,
 		},
 		populateFrom: func(k string, v interface{}, dT reflect.Type) (reflect.Value, error) {
-			return nestedFrom(v, dT)
+			return nestedFrom(v, dT, o)
 		},
 		populateTo: func(k string, v reflect.Value) (interface{}, error) {
-			return nestedTo(v)
+			return nestedTo(v, o)
 		},
 	}, nil
 }
@@ -92,7 +96,7 @@ func (nmff nestedSliceFieldFactory) Handles(msg proto.Message, fieldName string,
 	return fieldType.Kind() == reflect.Slice && fieldType.Elem().AssignableTo(protoMsgType) && !fieldType.Elem().AssignableTo(timestampType)
 }
 
-func (nmff nestedSliceFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (nmff nestedSliceFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	return &sliceField{
 		baseField: baseField{
 			msg:   msg,
@@ -102,10 +106,10 @@ func (nmff nestedSliceFieldFactory) NewProtoField(msg proto.Message, fieldName s
 			value: fieldValue,
 		},
 		populateFrom: func(i int, v interface{}, dT reflect.Type) (reflect.Value, error) {
-			return nestedFrom(v, dT)
+			return nestedFrom(v, dT, o)
 		},
 		populateTo: func(i int, v reflect.Value) (interface{}, error) {
-			return nestedTo(v)
+			return nestedTo(v, o)
 		},
 	}, nil
 }
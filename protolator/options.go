@@ -0,0 +1,53 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protolator
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator/protoext"
+)
+
+// Option configures a single call of DeepMarshalJSON, DeepUnmarshalJSON or
+// DeepMarshalRedactedJSON. Options only apply to the call they are passed to,
+// so concurrent callers may convert messages differently.
+type Option func(*options)
+
+type options struct {
+	decorators []func(proto.Message) proto.Message
+}
+
+// WithDecorator applies decorate to every message of the call once it is
+// decorated by protoext.Decorate, so that the caller may change how it is
+// converted, for instance with protoext.ResolveIdentities. The decorator
+// returns the message to convert, which is usually the given message when
+// the decorator does not apply to it. A document marshaled with a decorator
+// must be unmarshaled with the same decorator.
+func WithDecorator(decorate func(proto.Message) proto.Message) Option {
+	return func(o *options) {
+		o.decorators = append(o.decorators, decorate)
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// decorate decorates the message with protoext.Decorate and then with the
+// decorators of the call.
+func (o *options) decorate(msg proto.Message) proto.Message {
+	msg = protoext.Decorate(msg)
+	for _, decorate := range o.decorators {
+		msg = decorate(msg)
+	}
+
+	return msg
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
 	"github.com/golang/protobuf/proto"
 )
@@ -66,7 +67,12 @@ func (p *Payload) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
 		return &common.ConfigValue{}, nil
 	case int32(common.HeaderType_ENDORSER_TRANSACTION):
 		return &peer.Transaction{}, nil
+	case int32(common.HeaderType_DELIVER_SEEK_INFO):
+		return &orderer.SeekInfo{}, nil
 	default:
+		if msg, ok := registeredPayloadType(ch.Type); ok {
+			return msg, nil
+		}
 		return nil, fmt.Errorf("decoding type %v is unimplemented", ch.Type)
 	}
 }
@@ -90,6 +96,9 @@ func (ch *ChannelHeader) VariablyOpaqueFieldProto(name string) (proto.Message, e
 	case int32(common.HeaderType_ENDORSER_TRANSACTION):
 		return &peer.ChaincodeHeaderExtension{}, nil
 	default:
+		if msg, ok := registeredChannelHeaderExtension(ch.Type); ok {
+			return msg, nil
+		}
 		return nil, fmt.Errorf("channel header extension only valid for endorser transactions")
 	}
 }
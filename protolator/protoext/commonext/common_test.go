@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
 	"github.com/golang/protobuf/proto"
 
 	. "github.com/onsi/gomega"
@@ -79,6 +80,21 @@ func TestCommonProtolator(t *testing.T) {
 	gt.Expect(msg).To(Equal(&common.ConfigUpdateEnvelope{}))
 	gt.Expect(err).NotTo(HaveOccurred())
 
+	ch = &common.ChannelHeader{
+		Type: int32(common.HeaderType_DELIVER_SEEK_INFO),
+	}
+	chbytes, _ = proto.Marshal(ch)
+	payload = &Payload{
+		Payload: &common.Payload{
+			Header: &common.Header{
+				ChannelHeader: chbytes,
+			},
+		},
+	}
+	msg, err = payload.VariablyOpaqueFieldProto("data")
+	gt.Expect(msg).To(Equal(&orderer.SeekInfo{}))
+	gt.Expect(err).NotTo(HaveOccurred())
+
 	ch = &common.ChannelHeader{
 		Type: int32(common.HeaderType_CHAINCODE_PACKAGE),
 	}
@@ -7,8 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package commonext_test
 
 import (
+	"bytes"
+	"testing"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/protolator"
 	"github.com/hyperledger/fabric-config/protolator/protoext/commonext"
+	. "github.com/onsi/gomega"
 )
 
 // ensure structs implement expected interfaces
@@ -48,3 +55,66 @@ var (
 	_ protolator.StaticallyOpaqueFieldProto = &commonext.DynamicConsortiumOrgConfigValue{}
 	_ protolator.DecoratedProto             = &commonext.DynamicConsortiumOrgConfigValue{}
 )
+
+func TestDeliverDecoding(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	channelHeader, err := proto.Marshal(&common.ChannelHeader{
+		Type:      int32(common.HeaderType_DELIVER_SEEK_INFO),
+		ChannelId: "mychannel",
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	seekInfo, err := proto.Marshal(&orderer.SeekInfo{
+		Start:    &orderer.SeekPosition{Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: 5}}},
+		Stop:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+		Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	payload, err := proto.Marshal(&common.Payload{
+		Header: &common.Header{ChannelHeader: channelHeader},
+		Data:   seekInfo,
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	tests := []struct {
+		testName        string
+		msg             proto.Message
+		expectedOutputs []string
+	}{
+		{
+			testName:        "seek envelope",
+			msg:             &common.Envelope{Payload: payload, Signature: []byte("signature")},
+			expectedOutputs: []string{`"number": "5"`, `"newest": {}`, `"behavior": "BLOCK_UNTIL_READY"`},
+		},
+		{
+			testName: "blockchain info",
+			msg: &common.BlockchainInfo{
+				Height:                    10,
+				CurrentBlockHash:          []byte("current"),
+				PreviousBlockHash:         []byte("previous"),
+				BootstrappingSnapshotInfo: &common.BootstrappingSnapshotInfo{LastBlockInSnapshot: 7},
+			},
+			expectedOutputs: []string{`"height": "10"`, `"lastBlockInSnapshot": "7"`},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			var buffer bytes.Buffer
+			err := protolator.DeepMarshalJSON(&buffer, tt.msg)
+			gt.Expect(err).NotTo(HaveOccurred())
+			for _, expectedOutput := range tt.expectedOutputs {
+				gt.Expect(buffer.String()).To(ContainSubstring(expectedOutput))
+			}
+
+			decoded := proto.Clone(tt.msg)
+			decoded.Reset()
+			err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(proto.Equal(decoded, tt.msg)).To(BeTrue())
+		})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator/protoext/customext"
 	"github.com/hyperledger/fabric-config/protolator/protoext/ordererext"
 	"github.com/hyperledger/fabric-config/protolator/protoext/peerext"
 )
@@ -89,6 +90,9 @@ func (dccv *DynamicChannelConfigValue) StaticallyOpaqueFieldProto(name string) (
 	case "Capabilities":
 		return &common.Capabilities{}, nil
 	default:
+		if msg, ok := customext.ConfigValue(dccv.name); ok {
+			return msg, nil
+		}
 		return nil, fmt.Errorf("unknown Channel ConfigValue name: %s", dccv.name)
 	}
 }
@@ -181,6 +185,9 @@ func (dccv *DynamicConsortiumConfigValue) VariablyOpaqueFieldProto(name string)
 	case "ChannelCreationPolicy":
 		return &common.Policy{}, nil
 	default:
+		if msg, ok := customext.ConfigValue(dccv.name); ok {
+			return msg, nil
+		}
 		return nil, fmt.Errorf("unknown Consortium ConfigValue name: %s", dccv.name)
 	}
 }
@@ -237,6 +244,9 @@ func (dcocv *DynamicConsortiumOrgConfigValue) StaticallyOpaqueFieldProto(name st
 	case "MSP":
 		return &msp.MSPConfig{}, nil
 	default:
+		if msg, ok := customext.ConfigValue(dcocv.name); ok {
+			return msg, nil
+		}
 		return nil, fmt.Errorf("unknown Consortium Org ConfigValue name: %s", dcocv.name)
 	}
 }
@@ -0,0 +1,114 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commonext
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+)
+
+// builtinPayloadTypes are the header types whose payload data is decoded
+// natively and therefore cannot be registered.
+var builtinPayloadTypes = map[int32]struct{}{
+	int32(common.HeaderType_CONFIG):               {},
+	int32(common.HeaderType_ORDERER_TRANSACTION):  {},
+	int32(common.HeaderType_CONFIG_UPDATE):        {},
+	int32(common.HeaderType_MESSAGE):              {},
+	int32(common.HeaderType_ENDORSER_TRANSACTION): {},
+	int32(common.HeaderType_DELIVER_SEEK_INFO):    {},
+}
+
+// builtinExtensionTypes are the header types whose channel header extension
+// is decoded natively and therefore cannot be registered.
+var builtinExtensionTypes = map[int32]struct{}{
+	int32(common.HeaderType_ENDORSER_TRANSACTION): {},
+}
+
+var headerTypeRegistry = struct {
+	sync.RWMutex
+	payloads   map[int32]func() proto.Message
+	extensions map[int32]func() proto.Message
+}{
+	payloads:   map[int32]func() proto.Message{},
+	extensions: map[int32]func() proto.Message{},
+}
+
+// RegisterPayloadType registers a constructor for the message carried in the
+// data field of payloads whose channel header has the given type. This allows
+// blocks containing non-standard transaction types to be deeply decoded.
+// Registering a header type which is already known returns an error.
+func RegisterPayloadType(headerType int32, newMsg func() proto.Message) error {
+	if newMsg == nil {
+		return fmt.Errorf("payload constructor for header type %d is required", headerType)
+	}
+
+	if _, ok := builtinPayloadTypes[headerType]; ok {
+		return fmt.Errorf("header type %d is decoded natively and cannot be registered", headerType)
+	}
+
+	headerTypeRegistry.Lock()
+	defer headerTypeRegistry.Unlock()
+
+	if _, ok := headerTypeRegistry.payloads[headerType]; ok {
+		return fmt.Errorf("payload type for header type %d is already registered", headerType)
+	}
+	headerTypeRegistry.payloads[headerType] = newMsg
+
+	return nil
+}
+
+// RegisterChannelHeaderExtension registers a constructor for the message
+// carried in the extension field of channel headers with the given type.
+// Registering a header type which is already known returns an error.
+func RegisterChannelHeaderExtension(headerType int32, newMsg func() proto.Message) error {
+	if newMsg == nil {
+		return fmt.Errorf("extension constructor for header type %d is required", headerType)
+	}
+
+	if _, ok := builtinExtensionTypes[headerType]; ok {
+		return fmt.Errorf("header type %d is decoded natively and cannot be registered", headerType)
+	}
+
+	headerTypeRegistry.Lock()
+	defer headerTypeRegistry.Unlock()
+
+	if _, ok := headerTypeRegistry.extensions[headerType]; ok {
+		return fmt.Errorf("channel header extension for header type %d is already registered", headerType)
+	}
+	headerTypeRegistry.extensions[headerType] = newMsg
+
+	return nil
+}
+
+// registeredPayloadType returns a newly allocated message for a registered
+// payload header type.
+func registeredPayloadType(headerType int32) (proto.Message, bool) {
+	headerTypeRegistry.RLock()
+	newMsg, ok := headerTypeRegistry.payloads[headerType]
+	headerTypeRegistry.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	return newMsg(), true
+}
+
+// registeredChannelHeaderExtension returns a newly allocated message for a
+// registered channel header extension type.
+func registeredChannelHeaderExtension(headerType int32) (proto.Message, bool) {
+	headerTypeRegistry.RLock()
+	newMsg, ok := headerTypeRegistry.extensions[headerType]
+	headerTypeRegistry.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	return newMsg(), true
+}
@@ -0,0 +1,78 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package commonext
+
+import (
+	"testing"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
+	"github.com/golang/protobuf/proto"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRegisterPayloadType(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	customType := int32(1001)
+
+	ch := &common.ChannelHeader{Type: customType}
+	chbytes, err := proto.Marshal(ch)
+	gt.Expect(err).NotTo(HaveOccurred())
+	payload := &Payload{
+		Payload: &common.Payload{
+			Header: &common.Header{
+				ChannelHeader: chbytes,
+			},
+		},
+	}
+
+	msg, err := payload.VariablyOpaqueFieldProto("data")
+	gt.Expect(msg).To(BeNil())
+	gt.Expect(err).To(MatchError("decoding type 1001 is unimplemented"))
+
+	err = RegisterPayloadType(customType, func() proto.Message { return &peer.ChaincodeEvent{} })
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	msg, err = payload.VariablyOpaqueFieldProto("data")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msg).To(Equal(&peer.ChaincodeEvent{}))
+
+	err = RegisterPayloadType(customType, func() proto.Message { return &peer.ChaincodeEvent{} })
+	gt.Expect(err).To(MatchError("payload type for header type 1001 is already registered"))
+
+	err = RegisterPayloadType(int32(common.HeaderType_CONFIG), func() proto.Message { return &peer.ChaincodeEvent{} })
+	gt.Expect(err).To(MatchError("header type 1 is decoded natively and cannot be registered"))
+
+	err = RegisterPayloadType(1002, nil)
+	gt.Expect(err).To(MatchError("payload constructor for header type 1002 is required"))
+}
+
+func TestRegisterChannelHeaderExtension(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	customType := int32(1003)
+
+	ch := &ChannelHeader{ChannelHeader: &common.ChannelHeader{Type: customType}}
+	msg, err := ch.VariablyOpaqueFieldProto("extension")
+	gt.Expect(msg).To(BeNil())
+	gt.Expect(err).To(MatchError("channel header extension only valid for endorser transactions"))
+
+	err = RegisterChannelHeaderExtension(customType, func() proto.Message { return &peer.ChaincodeID{} })
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	msg, err = ch.VariablyOpaqueFieldProto("extension")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msg).To(Equal(&peer.ChaincodeID{}))
+
+	err = RegisterChannelHeaderExtension(customType, func() proto.Message { return &peer.ChaincodeID{} })
+	gt.Expect(err).To(MatchError("channel header extension for header type 1003 is already registered"))
+
+	err = RegisterChannelHeaderExtension(int32(common.HeaderType_ENDORSER_TRANSACTION), func() proto.Message { return &peer.ChaincodeID{} })
+	gt.Expect(err).To(MatchError("header type 3 is decoded natively and cannot be registered"))
+}
@@ -0,0 +1,100 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package customext holds the registry of config values which are not part of
+// the standard Fabric config, such as values containing a google.protobuf.Any
+// or vendor-defined messages.
+package customext
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// builtinConfigValues are the keys of the config values which are decoded
+// natively and therefore cannot be registered.
+var builtinConfigValues = map[string]struct{}{
+	"HashingAlgorithm":          {},
+	"BlockDataHashingStructure": {},
+	"OrdererAddresses":          {},
+	"Consortium":                {},
+	"Capabilities":              {},
+	"ConsensusType":             {},
+	"BatchSize":                 {},
+	"BatchTimeout":              {},
+	"KafkaBrokers":              {},
+	"ChannelRestrictions":       {},
+	"MSP":                       {},
+	"Endpoints":                 {},
+	"ACLs":                      {},
+	"AnchorPeers":               {},
+	"ChannelCreationPolicy":     {},
+}
+
+var configValueRegistry = struct {
+	sync.RWMutex
+	values map[string]func() proto.Message
+}{
+	values: map[string]func() proto.Message{},
+}
+
+// RegisterConfigValue registers a constructor for the message contained in
+// config values with the given key, in whichever config group they appear.
+// Registering a key which is already known returns an error.
+func RegisterConfigValue(key string, newMsg func() proto.Message) error {
+	if key == "" {
+		return fmt.Errorf("config value key is required")
+	}
+
+	if newMsg == nil {
+		return fmt.Errorf("constructor for config value %s is required", key)
+	}
+
+	if IsBuiltinConfigValue(key) {
+		return fmt.Errorf("config value %s is decoded natively and cannot be registered", key)
+	}
+
+	configValueRegistry.Lock()
+	defer configValueRegistry.Unlock()
+
+	if _, ok := configValueRegistry.values[key]; ok {
+		return fmt.Errorf("config value %s is already registered", key)
+	}
+	configValueRegistry.values[key] = newMsg
+
+	return nil
+}
+
+// UnregisterConfigValue removes the registration of the config value key, if
+// any, so that the key may be registered again.
+func UnregisterConfigValue(key string) {
+	configValueRegistry.Lock()
+	defer configValueRegistry.Unlock()
+
+	delete(configValueRegistry.values, key)
+}
+
+// ConfigValue returns a newly allocated message for a registered config value
+// key.
+func ConfigValue(key string) (proto.Message, bool) {
+	configValueRegistry.RLock()
+	newMsg, ok := configValueRegistry.values[key]
+	configValueRegistry.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	return newMsg(), true
+}
+
+// IsBuiltinConfigValue returns whether config values with the given key are
+// part of the standard Fabric config.
+func IsBuiltinConfigValue(key string) bool {
+	_, ok := builtinConfigValues[key]
+	return ok
+}
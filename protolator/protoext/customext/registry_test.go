@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package customext
+
+import (
+	"testing"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/any"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRegisterConfigValue(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	msg, ok := ConfigValue("VendorSettings")
+	gt.Expect(ok).To(BeFalse())
+	gt.Expect(msg).To(BeNil())
+
+	err := RegisterConfigValue("VendorSettings", func() proto.Message { return &any.Any{} })
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	msg, ok = ConfigValue("VendorSettings")
+	gt.Expect(ok).To(BeTrue())
+	gt.Expect(msg).To(Equal(&any.Any{}))
+
+	err = RegisterConfigValue("VendorSettings", func() proto.Message { return &common.Capabilities{} })
+	gt.Expect(err).To(MatchError("config value VendorSettings is already registered"))
+
+	UnregisterConfigValue("VendorSettings")
+	_, ok = ConfigValue("VendorSettings")
+	gt.Expect(ok).To(BeFalse())
+
+	err = RegisterConfigValue("VendorSettings", func() proto.Message { return &common.Capabilities{} })
+	gt.Expect(err).NotTo(HaveOccurred())
+	msg, ok = ConfigValue("VendorSettings")
+	gt.Expect(ok).To(BeTrue())
+	gt.Expect(msg).To(Equal(&common.Capabilities{}))
+	UnregisterConfigValue("VendorSettings")
+
+	err = RegisterConfigValue("MSP", func() proto.Message { return &common.Capabilities{} })
+	gt.Expect(err).To(MatchError("config value MSP is decoded natively and cannot be registered"))
+
+	err = RegisterConfigValue("VendorLimits", nil)
+	gt.Expect(err).To(MatchError("constructor for config value VendorLimits is required"))
+
+	err = RegisterConfigValue("", func() proto.Message { return &common.Capabilities{} })
+	gt.Expect(err).To(MatchError("config value key is required"))
+
+	gt.Expect(IsBuiltinConfigValue("AnchorPeers")).To(BeTrue())
+	gt.Expect(IsBuiltinConfigValue("VendorSettings")).To(BeFalse())
+}
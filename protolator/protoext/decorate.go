@@ -11,6 +11,8 @@ import (
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/ledger/rwset"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/etcdraft"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/smartbft"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/protolator/protoext/commonext"
@@ -47,31 +49,114 @@ func Decorate(msg proto.Message) proto.Message {
 	case *common.Policy:
 		return &commonext.Policy{Policy: m}
 
+	case *msp.FabricMSPConfig:
+		return &mspext.FabricMSPConfig{FabricMSPConfig: m}
+	case *msp.FabricOUIdentifier:
+		return &mspext.FabricOUIdentifier{FabricOUIdentifier: m}
+	case *msp.IdemixMSPConfig:
+		return &mspext.IdemixMSPConfig{IdemixMSPConfig: m}
 	case *msp.MSPConfig:
 		return &mspext.MSPConfig{MSPConfig: m}
 	case *msp.MSPPrincipal:
 		return &mspext.MSPPrincipal{MSPPrincipal: m}
+	case *msp.SerializedIdentity:
+		return &mspext.SerializedIdentity{SerializedIdentity: m}
 
 	case *orderer.ConsensusType:
 		return &ordererext.ConsensusType{ConsensusType: m}
+	case *etcdraft.Consenter:
+		return &ordererext.EtcdRaftConsenter{Consenter: m}
+	case *smartbft.Consenter:
+		return &ordererext.SmartBFTConsenter{Consenter: m}
 
 	case *peer.ChaincodeAction:
 		return &peerext.ChaincodeAction{ChaincodeAction: m}
 	case *peer.ChaincodeActionPayload:
 		return &peerext.ChaincodeActionPayload{ChaincodeActionPayload: m}
+	case *peer.ChaincodeEvent:
+		return &peerext.ChaincodeEvent{ChaincodeEvent: m}
 	case *peer.ChaincodeEndorsedAction:
 		return &peerext.ChaincodeEndorsedAction{ChaincodeEndorsedAction: m}
 	case *peer.ChaincodeProposalPayload:
 		return &peerext.ChaincodeProposalPayload{ChaincodeProposalPayload: m}
+	case *peer.Endorsement:
+		return &peerext.Endorsement{Endorsement: m}
+	case *peer.Proposal:
+		return &peerext.Proposal{Proposal: m}
+	case *peer.ProposalResponse:
+		return &peerext.ProposalResponse{ProposalResponse: m}
 	case *peer.ProposalResponsePayload:
 		return &peerext.ProposalResponsePayload{ProposalResponsePayload: m}
+	case *peer.SignedProposal:
+		return &peerext.SignedProposal{SignedProposal: m}
+	case *peer.SignedSnapshotRequest:
+		return &peerext.SignedSnapshotRequest{SignedSnapshotRequest: m}
 	case *peer.TransactionAction:
 		return &peerext.TransactionAction{TransactionAction: m}
 
 	case *rwset.TxReadWriteSet:
 		return &rwsetext.TxReadWriteSet{TxReadWriteSet: m}
+	case *rwset.TxPvtReadWriteSet:
+		return &rwsetext.TxPvtReadWriteSet{TxPvtReadWriteSet: m}
 
 	default:
 		return msg
 	}
 }
+
+// ResolveIdentities is a decorator for protolator.WithDecorator which
+// resolves the serialized identities of the decorated messages, such as the
+// creator of a transaction or the endorser of a proposal response, into the
+// subject, issuer and serial number of their X.509 certificate, and annotates
+// the ECDSA signatures of endorsements.
+func ResolveIdentities(msg proto.Message) proto.Message {
+	switch m := msg.(type) {
+	case *mspext.SerializedIdentity:
+		return &mspext.SerializedIdentity{SerializedIdentity: m.SerializedIdentity, Resolve: true}
+	case *peerext.Endorsement:
+		return &peerext.Endorsement{Endorsement: m.Endorsement, Resolve: true}
+	default:
+		return msg
+	}
+}
+
+// AnnotateCertificates is a decorator for protolator.WithDecorator which
+// annotates the PEM encoded certificates of MSP and consenter configs with
+// their subject, issuer, serial number, subject alternative names and
+// validity period.
+func AnnotateCertificates(msg proto.Message) proto.Message {
+	return decorateCertificates(msg, func(r *mspext.CertificateRendering) { r.Annotate = true })
+}
+
+// RenderPEM is a decorator for protolator.WithDecorator which renders the
+// PEM encoded certificates and CRLs of MSP and consenter configs as the lines
+// of their PEM encoding rather than base64 encoded. AnnotateCertificates
+// takes precedence over RenderPEM for certificates.
+func RenderPEM(msg proto.Message) proto.Message {
+	return decorateCertificates(msg, func(r *mspext.CertificateRendering) { r.PEM = true })
+}
+
+// decorateCertificates returns a copy of the decorated message, if it holds
+// certificates, whose certificate rendering is modified by set.
+func decorateCertificates(msg proto.Message, set func(*mspext.CertificateRendering)) proto.Message {
+	switch m := msg.(type) {
+	case *mspext.FabricMSPConfig:
+		decorated := *m
+		set(&decorated.Certificates)
+		return &decorated
+	case *mspext.FabricOUIdentifier:
+		decorated := *m
+		set(&decorated.Certificates)
+		return &decorated
+	case *ordererext.EtcdRaftConsenter:
+		decorated := *m
+		set(&decorated.Certificates)
+		return &decorated
+	case *ordererext.SmartBFTConsenter:
+		decorated := *m
+		set(&decorated.Certificates)
+		return &decorated
+	default:
+		return msg
+	}
+}
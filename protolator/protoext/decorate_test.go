@@ -15,6 +15,8 @@ import (
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/ledger/rwset"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/etcdraft"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/smartbft"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
 	"github.com/hyperledger/fabric-config/protolator/protoext/commonext"
 	"github.com/hyperledger/fabric-config/protolator/protoext/ledger/rwsetext"
@@ -172,6 +174,39 @@ func TestDecorate(t *testing.T) {
 				},
 			},
 		},
+		{
+			testSpec: "msp.IdemixMSPConfig",
+			msg: &msp.IdemixMSPConfig{
+				Ipk: []byte("ipk-bytes"),
+			},
+			expectedReturn: &mspext.IdemixMSPConfig{
+				IdemixMSPConfig: &msp.IdemixMSPConfig{
+					Ipk: []byte("ipk-bytes"),
+				},
+			},
+		},
+		{
+			testSpec: "msp.FabricMSPConfig",
+			msg: &msp.FabricMSPConfig{
+				Name: "Org1MSP",
+			},
+			expectedReturn: &mspext.FabricMSPConfig{
+				FabricMSPConfig: &msp.FabricMSPConfig{
+					Name: "Org1MSP",
+				},
+			},
+		},
+		{
+			testSpec: "msp.FabricOUIdentifier",
+			msg: &msp.FabricOUIdentifier{
+				OrganizationalUnitIdentifier: "peer",
+			},
+			expectedReturn: &mspext.FabricOUIdentifier{
+				FabricOUIdentifier: &msp.FabricOUIdentifier{
+					OrganizationalUnitIdentifier: "peer",
+				},
+			},
+		},
 		{
 			testSpec: "msp.MSPConfig",
 			msg: &msp.MSPConfig{
@@ -194,6 +229,17 @@ func TestDecorate(t *testing.T) {
 				},
 			},
 		},
+		{
+			testSpec: "msp.SerializedIdentity",
+			msg: &msp.SerializedIdentity{
+				Mspid: "mspid",
+			},
+			expectedReturn: &mspext.SerializedIdentity{
+				SerializedIdentity: &msp.SerializedIdentity{
+					Mspid: "mspid",
+				},
+			},
+		},
 		{
 			testSpec: "orderer.ConsensusType",
 			msg: &orderer.ConsensusType{
@@ -205,6 +251,28 @@ func TestDecorate(t *testing.T) {
 				},
 			},
 		},
+		{
+			testSpec: "etcdraft.Consenter",
+			msg: &etcdraft.Consenter{
+				Host: "orderer1.example.com",
+			},
+			expectedReturn: &ordererext.EtcdRaftConsenter{
+				Consenter: &etcdraft.Consenter{
+					Host: "orderer1.example.com",
+				},
+			},
+		},
+		{
+			testSpec: "smartbft.Consenter",
+			msg: &smartbft.Consenter{
+				Host: "orderer1.example.com",
+			},
+			expectedReturn: &ordererext.SmartBFTConsenter{
+				Consenter: &smartbft.Consenter{
+					Host: "orderer1.example.com",
+				},
+			},
+		},
 		{
 			testSpec: "peer.ChaincodeAction",
 			msg: &peer.ChaincodeAction{
@@ -227,6 +295,17 @@ func TestDecorate(t *testing.T) {
 				},
 			},
 		},
+		{
+			testSpec: "peer.ChaincodeEvent",
+			msg: &peer.ChaincodeEvent{
+				Payload: []byte("payload-bytes"),
+			},
+			expectedReturn: &peerext.ChaincodeEvent{
+				ChaincodeEvent: &peer.ChaincodeEvent{
+					Payload: []byte("payload-bytes"),
+				},
+			},
+		},
 		{
 			testSpec: "peer.ChaincodeEndorsedAction",
 			msg: &peer.ChaincodeEndorsedAction{
@@ -249,6 +328,28 @@ func TestDecorate(t *testing.T) {
 				},
 			},
 		},
+		{
+			testSpec: "peer.Endorsement",
+			msg: &peer.Endorsement{
+				Endorser: []byte("endorser"),
+			},
+			expectedReturn: &peerext.Endorsement{
+				Endorsement: &peer.Endorsement{
+					Endorser: []byte("endorser"),
+				},
+			},
+		},
+		{
+			testSpec: "peer.ProposalResponse",
+			msg: &peer.ProposalResponse{
+				Payload: []byte("payload-bytes"),
+			},
+			expectedReturn: &peerext.ProposalResponse{
+				ProposalResponse: &peer.ProposalResponse{
+					Payload: []byte("payload-bytes"),
+				},
+			},
+		},
 		{
 			testSpec: "peer.ProposalResponsePayload",
 			msg: &peer.ProposalResponsePayload{
@@ -260,6 +361,39 @@ func TestDecorate(t *testing.T) {
 				},
 			},
 		},
+		{
+			testSpec: "peer.Proposal",
+			msg: &peer.Proposal{
+				Payload: []byte("payload-bytes"),
+			},
+			expectedReturn: &peerext.Proposal{
+				Proposal: &peer.Proposal{
+					Payload: []byte("payload-bytes"),
+				},
+			},
+		},
+		{
+			testSpec: "peer.SignedProposal",
+			msg: &peer.SignedProposal{
+				ProposalBytes: []byte("proposal-bytes"),
+			},
+			expectedReturn: &peerext.SignedProposal{
+				SignedProposal: &peer.SignedProposal{
+					ProposalBytes: []byte("proposal-bytes"),
+				},
+			},
+		},
+		{
+			testSpec: "peer.SignedSnapshotRequest",
+			msg: &peer.SignedSnapshotRequest{
+				Request: []byte("request-bytes"),
+			},
+			expectedReturn: &peerext.SignedSnapshotRequest{
+				SignedSnapshotRequest: &peer.SignedSnapshotRequest{
+					Request: []byte("request-bytes"),
+				},
+			},
+		},
 		{
 			testSpec: "peer.TransactionAction",
 			msg: &peer.TransactionAction{
@@ -290,6 +424,25 @@ func TestDecorate(t *testing.T) {
 				},
 			},
 		},
+		{
+			testSpec: "rwset.TxPvtReadWriteSet",
+			msg: &rwset.TxPvtReadWriteSet{
+				NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+					{
+						Namespace: "namespace",
+					},
+				},
+			},
+			expectedReturn: &rwsetext.TxPvtReadWriteSet{
+				TxPvtReadWriteSet: &rwset.TxPvtReadWriteSet{
+					NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+						{
+							Namespace: "namespace",
+						},
+					},
+				},
+			},
+		},
 		{
 			testSpec: "default",
 			msg: &GenericProtoMessage{
@@ -309,3 +462,60 @@ func TestDecorate(t *testing.T) {
 		})
 	}
 }
+
+func TestResolveIdentities(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	identity := &msp.SerializedIdentity{Mspid: "Org1MSP"}
+	resolved := ResolveIdentities(Decorate(identity))
+	gt.Expect(resolved).To(Equal(&mspext.SerializedIdentity{SerializedIdentity: identity, Resolve: true}))
+
+	endorsement := &peer.Endorsement{Signature: []byte("signature")}
+	resolved = ResolveIdentities(Decorate(endorsement))
+	gt.Expect(resolved).To(Equal(&peerext.Endorsement{Endorsement: endorsement, Resolve: true}))
+
+	envelope := &common.Envelope{}
+	gt.Expect(ResolveIdentities(Decorate(envelope))).To(Equal(&commonext.Envelope{Envelope: envelope}))
+}
+
+func TestAnnotateCertificates(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	fabricMSPConfig := &msp.FabricMSPConfig{Name: "Org1MSP"}
+	annotated := AnnotateCertificates(Decorate(fabricMSPConfig))
+	gt.Expect(annotated).To(Equal(&mspext.FabricMSPConfig{FabricMSPConfig: fabricMSPConfig, Certificates: mspext.CertificateRendering{Annotate: true}}))
+
+	ouIdentifier := &msp.FabricOUIdentifier{OrganizationalUnitIdentifier: "peer"}
+	annotated = AnnotateCertificates(Decorate(ouIdentifier))
+	gt.Expect(annotated).To(Equal(&mspext.FabricOUIdentifier{FabricOUIdentifier: ouIdentifier, Certificates: mspext.CertificateRendering{Annotate: true}}))
+
+	etcdRaftConsenter := &etcdraft.Consenter{Host: "orderer1.example.com"}
+	annotated = AnnotateCertificates(Decorate(etcdRaftConsenter))
+	gt.Expect(annotated).To(Equal(&ordererext.EtcdRaftConsenter{Consenter: etcdRaftConsenter, Certificates: mspext.CertificateRendering{Annotate: true}}))
+
+	smartBFTConsenter := &smartbft.Consenter{ConsenterId: 1}
+	annotated = AnnotateCertificates(Decorate(smartBFTConsenter))
+	gt.Expect(annotated).To(Equal(&ordererext.SmartBFTConsenter{Consenter: smartBFTConsenter, Certificates: mspext.CertificateRendering{Annotate: true}}))
+
+	decorated := Decorate(fabricMSPConfig)
+	AnnotateCertificates(decorated)
+	gt.Expect(decorated).To(Equal(&mspext.FabricMSPConfig{FabricMSPConfig: fabricMSPConfig}))
+
+	envelope := &common.Envelope{}
+	gt.Expect(AnnotateCertificates(Decorate(envelope))).To(Equal(&commonext.Envelope{Envelope: envelope}))
+}
+
+func TestRenderPEM(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	fabricMSPConfig := &msp.FabricMSPConfig{Name: "Org1MSP"}
+	rendered := RenderPEM(Decorate(fabricMSPConfig))
+	gt.Expect(rendered).To(Equal(&mspext.FabricMSPConfig{FabricMSPConfig: fabricMSPConfig, Certificates: mspext.CertificateRendering{PEM: true}}))
+
+	rendered = AnnotateCertificates(RenderPEM(Decorate(fabricMSPConfig)))
+	gt.Expect(rendered).To(Equal(&mspext.FabricMSPConfig{FabricMSPConfig: fabricMSPConfig, Certificates: mspext.CertificateRendering{Annotate: true, PEM: true}}))
+
+	smartBFTConsenter := &smartbft.Consenter{ConsenterId: 1}
+	rendered = RenderPEM(Decorate(smartBFTConsenter))
+	gt.Expect(rendered).To(Equal(&ordererext.SmartBFTConsenter{Consenter: smartBFTConsenter, Certificates: mspext.CertificateRendering{PEM: true}}))
+}
@@ -123,3 +123,90 @@ func (dchrws *DynamicCollectionHashedReadWriteSet) StaticallyOpaqueFieldProto(na
 		return nil, fmt.Errorf("not a marshaled field: %s", name)
 	}
 }
+
+type TxPvtReadWriteSet struct{ *rwset.TxPvtReadWriteSet }
+
+func (txpvtrws *TxPvtReadWriteSet) Underlying() proto.Message {
+	return txpvtrws.TxPvtReadWriteSet
+}
+
+func (txpvtrws *TxPvtReadWriteSet) DynamicSliceFields() []string {
+	if txpvtrws.DataModel != rwset.TxReadWriteSet_KV {
+		// We only know how to handle TxReadWriteSet_KV types
+		return []string{}
+	}
+
+	return []string{"ns_pvt_rwset"}
+}
+
+func (txpvtrws *TxPvtReadWriteSet) DynamicSliceFieldProto(name string, index int, base proto.Message) (proto.Message, error) {
+	if name != txpvtrws.DynamicSliceFields()[0] {
+		return nil, fmt.Errorf("Not a dynamic field: %s", name)
+	}
+
+	nspvtrws, ok := base.(*rwset.NsPvtReadWriteSet)
+	if !ok {
+		return nil, fmt.Errorf("TxPvtReadWriteSet must embed a NsPvtReadWriteSet its dynamic field")
+	}
+
+	return &DynamicNsPvtReadWriteSet{
+		NsPvtReadWriteSet: nspvtrws,
+		DataModel:         txpvtrws.DataModel,
+	}, nil
+}
+
+type DynamicNsPvtReadWriteSet struct {
+	*rwset.NsPvtReadWriteSet
+	DataModel rwset.TxReadWriteSet_DataModel
+}
+
+func (dnpvtrws *DynamicNsPvtReadWriteSet) Underlying() proto.Message {
+	return dnpvtrws.NsPvtReadWriteSet
+}
+
+func (dnpvtrws *DynamicNsPvtReadWriteSet) DynamicSliceFields() []string {
+	return []string{"collection_pvt_rwset"}
+}
+
+func (dnpvtrws *DynamicNsPvtReadWriteSet) DynamicSliceFieldProto(name string, index int, base proto.Message) (proto.Message, error) {
+	if name != dnpvtrws.DynamicSliceFields()[0] {
+		return nil, fmt.Errorf("Not a dynamic field: %s", name)
+	}
+
+	cpvtrws, ok := base.(*rwset.CollectionPvtReadWriteSet)
+	if !ok {
+		return nil, fmt.Errorf("NsPvtReadWriteSet must embed a *CollectionPvtReadWriteSet its dynamic field")
+	}
+
+	return &DynamicCollectionPvtReadWriteSet{
+		CollectionPvtReadWriteSet: cpvtrws,
+		DataModel:                 dnpvtrws.DataModel,
+	}, nil
+}
+
+type DynamicCollectionPvtReadWriteSet struct {
+	*rwset.CollectionPvtReadWriteSet
+	DataModel rwset.TxReadWriteSet_DataModel
+}
+
+func (dcpvtrws *DynamicCollectionPvtReadWriteSet) Underlying() proto.Message {
+	return dcpvtrws.CollectionPvtReadWriteSet
+}
+
+func (dcpvtrws *DynamicCollectionPvtReadWriteSet) StaticallyOpaqueFields() []string {
+	return []string{"rwset"}
+}
+
+func (dcpvtrws *DynamicCollectionPvtReadWriteSet) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case "rwset":
+		switch dcpvtrws.DataModel {
+		case rwset.TxReadWriteSet_KV:
+			return &kvrwset.KVRWSet{}, nil
+		default:
+			return nil, fmt.Errorf("unknown data model type: %v", dcpvtrws.DataModel)
+		}
+	default:
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+}
@@ -19,4 +19,10 @@ var (
 	_ protolator.DecoratedProto             = &rwsetext.DynamicNsReadWriteSet{}
 	_ protolator.StaticallyOpaqueFieldProto = &rwsetext.DynamicCollectionHashedReadWriteSet{}
 	_ protolator.DecoratedProto             = &rwsetext.DynamicCollectionHashedReadWriteSet{}
+	_ protolator.DynamicSliceFieldProto     = &rwsetext.TxPvtReadWriteSet{}
+	_ protolator.DecoratedProto             = &rwsetext.TxPvtReadWriteSet{}
+	_ protolator.DynamicSliceFieldProto     = &rwsetext.DynamicNsPvtReadWriteSet{}
+	_ protolator.DecoratedProto             = &rwsetext.DynamicNsPvtReadWriteSet{}
+	_ protolator.StaticallyOpaqueFieldProto = &rwsetext.DynamicCollectionPvtReadWriteSet{}
+	_ protolator.DecoratedProto             = &rwsetext.DynamicCollectionPvtReadWriteSet{}
 )
@@ -0,0 +1,284 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mspext
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// CertificateRendering determines how the PEM encoded certificates and CRLs
+// of the MSP and consenter configs of a decorated message are decoded. By
+// default they are left base64 encoded.
+type CertificateRendering struct {
+	// Annotate decodes certificates into their subject, issuer, serial
+	// number, subject alternative names and validity period, see
+	// protoext.AnnotateCertificates. The certificate itself is kept
+	// alongside its annotation, so annotated output can be encoded again
+	// with the same decorator.
+	Annotate bool
+
+	// PEM decodes certificates and CRLs into the lines of their PEM
+	// encoding, so that changes to them can be read in diffs of decoded
+	// configs, see protoext.RenderPEM. Annotate takes precedence over PEM
+	// for certificates.
+	PEM bool
+}
+
+// CertificateProto returns the message used to decode a certificate field
+// holding the given bytes, or nil if the field should be left opaque because
+// neither certificate annotation nor PEM rendering is enabled or the bytes
+// are not a PEM encoded certificate.
+func (r CertificateRendering) CertificateProto(cert []byte) proto.Message {
+	switch {
+	case r.Annotate:
+		if !isPEMBlock(cert, "CERTIFICATE") {
+			return nil
+		}
+		return &X509Certificate{}
+	case r.PEM:
+		if !isPEMBlock(cert, "CERTIFICATE") || !isRenderablePEM(cert) {
+			return nil
+		}
+		return &PEMLines{}
+	default:
+		return nil
+	}
+}
+
+// CRLProto returns the message used to decode a CRL field holding the given
+// bytes, or nil if the field should be left opaque because PEM rendering is
+// disabled or the bytes are not a PEM encoded CRL.
+func (r CertificateRendering) CRLProto(crl []byte) proto.Message {
+	if !r.PEM || !isPEMBlock(crl, "X509 CRL") || !isRenderablePEM(crl) {
+		return nil
+	}
+
+	return &PEMLines{}
+}
+
+// isPEMBlock reports whether the bytes start with a PEM block of the given
+// type. The field is empty when a decoded field is encoded again, so only
+// non-empty fields can be checked.
+func isPEMBlock(b []byte, blockType string) bool {
+	if len(b) == 0 {
+		return true
+	}
+
+	block, _ := pem.Decode(b)
+	return block != nil && block.Type == blockType
+}
+
+// isRenderablePEM reports whether the bytes are restored exactly from their
+// lines, i.e. they end with a newline and do not use carriage returns.
+func isRenderablePEM(b []byte) bool {
+	if len(b) == 0 {
+		return true
+	}
+
+	return b[len(b)-1] == '\n' && !bytes.ContainsRune(b, '\r')
+}
+
+// PEMLines is a proto message whose binary encoding is PEM encoded data, such
+// as a certificate, a bundle of certificates or a CRL. It holds the lines of
+// the PEM encoding so that each of them shows up on its own in decoded JSON.
+type PEMLines struct {
+	PEM []string `json:"pem"`
+}
+
+func (pl *PEMLines) Reset()         { *pl = PEMLines{} }
+func (pl *PEMLines) String() string { return strings.Join(pl.PEM, "\n") }
+func (*PEMLines) ProtoMessage()     {}
+
+// Marshal returns the PEM encoded data.
+func (pl *PEMLines) Marshal() ([]byte, error) {
+	if len(pl.PEM) == 0 {
+		return nil, nil
+	}
+
+	return []byte(strings.Join(pl.PEM, "\n") + "\n"), nil
+}
+
+// Unmarshal splits PEM encoded data into its lines.
+func (pl *PEMLines) Unmarshal(b []byte) error {
+	if len(b) == 0 {
+		*pl = PEMLines{}
+		return nil
+	}
+
+	if block, _ := pem.Decode(b); block == nil {
+		return fmt.Errorf("not PEM encoded data")
+	}
+
+	*pl = PEMLines{PEM: strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")}
+
+	return nil
+}
+
+func (pl *PEMLines) MarshalJSONPB(*jsonpb.Marshaler) ([]byte, error) {
+	return json.Marshal(pl)
+}
+
+func (pl *PEMLines) UnmarshalJSONPB(_ *jsonpb.Unmarshaler, b []byte) error {
+	return json.Unmarshal(b, pl)
+}
+
+// X509Certificate is a proto message whose binary encoding is a PEM encoded
+// X.509 certificate. It annotates the certificate with its decoded fields so
+// that humans reviewing a config can read it.
+type X509Certificate struct {
+	Subject      string   `json:"subject"`
+	Issuer       string   `json:"issuer"`
+	SerialNumber string   `json:"serial_number"`
+	SANs         []string `json:"sans,omitempty"`
+	NotBefore    string   `json:"not_before"`
+	NotAfter     string   `json:"not_after"`
+	Certificate  string   `json:"certificate"`
+}
+
+func (xc *X509Certificate) Reset()         { *xc = X509Certificate{} }
+func (xc *X509Certificate) String() string { return xc.Subject }
+func (*X509Certificate) ProtoMessage()     {}
+
+// Marshal returns the PEM encoded certificate.
+func (xc *X509Certificate) Marshal() ([]byte, error) {
+	return []byte(xc.Certificate), nil
+}
+
+// Unmarshal parses a PEM encoded certificate. Only the first certificate of
+// a PEM bundle is annotated, but the whole bundle is retained.
+func (xc *X509Certificate) Unmarshal(b []byte) error {
+	if len(b) == 0 {
+		*xc = X509Certificate{}
+		return nil
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return fmt.Errorf("not a PEM encoded certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("parsing certificate: %v", err)
+	}
+
+	var sans []string
+	sans = append(sans, cert.DNSNames...)
+	sans = append(sans, cert.EmailAddresses...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		sans = append(sans, uri.String())
+	}
+
+	*xc = X509Certificate{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		SANs:         sans,
+		NotBefore:    cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:     cert.NotAfter.UTC().Format(time.RFC3339),
+		Certificate:  string(b),
+	}
+
+	return nil
+}
+
+func (xc *X509Certificate) MarshalJSONPB(*jsonpb.Marshaler) ([]byte, error) {
+	return json.Marshal(xc)
+}
+
+// UnmarshalJSONPB restores the certificate; the annotations are derived from
+// the certificate and are ignored.
+func (xc *X509Certificate) UnmarshalJSONPB(_ *jsonpb.Unmarshaler, b []byte) error {
+	decoded := struct {
+		Certificate string `json:"certificate"`
+	}{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+
+	return xc.Unmarshal([]byte(decoded.Certificate))
+}
+
+// FabricMSPConfig decorates an MSP config, whose certificates are decoded
+// according to Certificates.
+type FabricMSPConfig struct {
+	*msp.FabricMSPConfig
+	Certificates CertificateRendering
+}
+
+func (fmc *FabricMSPConfig) Underlying() proto.Message {
+	return fmc.FabricMSPConfig
+}
+
+func (fmc *FabricMSPConfig) VariablyOpaqueSliceFields() []string {
+	return []string{"root_certs", "intermediate_certs", "admins", "revocation_list", "tls_root_certs", "tls_intermediate_certs"}
+}
+
+func (fmc *FabricMSPConfig) VariablyOpaqueSliceFieldProto(name string, index int) (proto.Message, error) {
+	var certs [][]byte
+	switch name {
+	case "root_certs":
+		certs = fmc.RootCerts
+	case "intermediate_certs":
+		certs = fmc.IntermediateCerts
+	case "admins":
+		certs = fmc.Admins
+	case "revocation_list":
+		if index >= len(fmc.RevocationList) {
+			return fmc.Certificates.CRLProto(nil), nil
+		}
+		return fmc.Certificates.CRLProto(fmc.RevocationList[index]), nil
+	case "tls_root_certs":
+		certs = fmc.TlsRootCerts
+	case "tls_intermediate_certs":
+		certs = fmc.TlsIntermediateCerts
+	default:
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+
+	if index >= len(certs) {
+		return fmc.Certificates.CertificateProto(nil), nil
+	}
+
+	return fmc.Certificates.CertificateProto(certs[index]), nil
+}
+
+// FabricOUIdentifier decorates an OU identifier, whose certificate is
+// decoded according to Certificates.
+type FabricOUIdentifier struct {
+	*msp.FabricOUIdentifier
+	Certificates CertificateRendering
+}
+
+func (foi *FabricOUIdentifier) Underlying() proto.Message {
+	return foi.FabricOUIdentifier
+}
+
+func (foi *FabricOUIdentifier) VariablyOpaqueFields() []string {
+	return []string{"certificate"}
+}
+
+func (foi *FabricOUIdentifier) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != foi.VariablyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+
+	return foi.Certificates.CertificateProto(foi.Certificate), nil
+}
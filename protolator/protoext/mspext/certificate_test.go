@@ -0,0 +1,171 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mspext_test
+
+import (
+	"bytes"
+	"encoding/pem"
+	"testing"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-config/protolator/protoext"
+	"github.com/hyperledger/fabric-config/protolator/protoext/mspext"
+	. "github.com/onsi/gomega"
+)
+
+func TestCertificateAnnotation(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	cert := generateCertificate(gt)
+	fabricMSPConfig, err := proto.Marshal(&msp.FabricMSPConfig{
+		Name:         "Org1MSP",
+		RootCerts:    [][]byte{cert},
+		TlsRootCerts: [][]byte{cert},
+		Admins:       [][]byte{[]byte("not-a-certificate")},
+		FabricNodeOus: &msp.FabricNodeOUs{
+			Enable:            true,
+			PeerOuIdentifier:  &msp.FabricOUIdentifier{Certificate: cert, OrganizationalUnitIdentifier: "peer"},
+			AdminOuIdentifier: &msp.FabricOUIdentifier{OrganizationalUnitIdentifier: "admin"},
+		},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	mspConfig := &msp.MSPConfig{Config: fabricMSPConfig}
+	annotate := protolator.WithDecorator(protoext.AnnotateCertificates)
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		gt := NewGomegaWithT(t)
+
+		var buffer bytes.Buffer
+		err := protolator.DeepMarshalJSON(&buffer, mspConfig)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(buffer.String()).NotTo(ContainSubstring("subject"))
+
+		decoded := &msp.MSPConfig{}
+		err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(proto.Equal(decoded, mspConfig)).To(BeTrue())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+		gt := NewGomegaWithT(t)
+
+		var buffer bytes.Buffer
+		err := protolator.DeepMarshalJSON(&buffer, mspConfig, annotate)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(buffer.String()).To(ContainSubstring(`"subject": "CN=peer0.org1.example.com,O=Org1"`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"serial_number": "1234"`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"not_after": "2030-01-01T00:00:00Z"`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"sans": [`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"bm90LWEtY2VydGlmaWNhdGU="`))
+
+		decoded := &msp.MSPConfig{}
+		err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded, annotate)
+		gt.Expect(err).NotTo(HaveOccurred())
+
+		decodedFabricMSPConfig := &msp.FabricMSPConfig{}
+		err = proto.Unmarshal(decoded.Config, decodedFabricMSPConfig)
+		gt.Expect(err).NotTo(HaveOccurred())
+		expectedFabricMSPConfig := &msp.FabricMSPConfig{}
+		err = proto.Unmarshal(mspConfig.Config, expectedFabricMSPConfig)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(proto.Equal(decodedFabricMSPConfig, expectedFabricMSPConfig)).To(BeTrue())
+	})
+}
+
+func TestX509Certificate(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	cert := generateCertificate(gt)
+
+	xc := &mspext.X509Certificate{}
+	err := xc.Unmarshal(cert)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(xc.Issuer).To(Equal("CN=peer0.org1.example.com,O=Org1"))
+	gt.Expect(xc.SANs).To(Equal([]string{"peer0.org1.example.com", "127.0.0.1"}))
+	gt.Expect(xc.NotBefore).To(Equal("2020-01-01T00:00:00Z"))
+
+	marshaled, err := xc.Marshal()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(marshaled).To(Equal(cert))
+
+	err = xc.Unmarshal([]byte("not-a-certificate"))
+	gt.Expect(err).To(MatchError("not a PEM encoded certificate"))
+}
+
+func TestPEMRendering(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	cert := generateCertificate(gt)
+	crl := pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: []byte("revoked")})
+	fabricMSPConfig, err := proto.Marshal(&msp.FabricMSPConfig{
+		Name:           "Org1MSP",
+		RootCerts:      [][]byte{cert},
+		Admins:         [][]byte{[]byte("not-a-certificate")},
+		RevocationList: [][]byte{crl},
+		FabricNodeOus: &msp.FabricNodeOUs{
+			Enable:           true,
+			PeerOuIdentifier: &msp.FabricOUIdentifier{Certificate: cert, OrganizationalUnitIdentifier: "peer"},
+		},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	mspConfig := &msp.MSPConfig{Config: fabricMSPConfig}
+
+	renderPEM := protolator.WithDecorator(protoext.RenderPEM)
+
+	var buffer bytes.Buffer
+	err = protolator.DeepMarshalJSON(&buffer, mspConfig, renderPEM)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(buffer.String()).To(ContainSubstring(`"-----BEGIN CERTIFICATE-----",`))
+	gt.Expect(buffer.String()).To(ContainSubstring(`"-----END CERTIFICATE-----"`))
+	gt.Expect(buffer.String()).To(ContainSubstring(`"-----BEGIN X509 CRL-----",`))
+	gt.Expect(buffer.String()).To(ContainSubstring(`"bm90LWEtY2VydGlmaWNhdGU="`))
+	gt.Expect(buffer.String()).NotTo(ContainSubstring("subject"))
+
+	decoded := &msp.MSPConfig{}
+	err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded, renderPEM)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(decoded.Config).To(Equal(mspConfig.Config))
+
+	t.Run("with certificate annotation", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		var buffer bytes.Buffer
+		err := protolator.DeepMarshalJSON(&buffer, mspConfig, renderPEM, protolator.WithDecorator(protoext.AnnotateCertificates))
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(buffer.String()).To(ContainSubstring(`"subject": "CN=peer0.org1.example.com,O=Org1"`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"-----BEGIN X509 CRL-----",`))
+	})
+}
+
+func TestPEMLines(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	cert := generateCertificate(gt)
+
+	pl := &mspext.PEMLines{}
+	err := pl.Unmarshal(cert)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(pl.PEM[0]).To(Equal("-----BEGIN CERTIFICATE-----"))
+	gt.Expect(pl.PEM[len(pl.PEM)-1]).To(Equal("-----END CERTIFICATE-----"))
+
+	marshaled, err := pl.Marshal()
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(marshaled).To(Equal(cert))
+
+	err = pl.Unmarshal([]byte("not-pem"))
+	gt.Expect(err).To(MatchError("not PEM encoded data"))
+
+	gt.Expect(mspext.CertificateRendering{}.CertificateProto(cert)).To(BeNil())
+	rendering := mspext.CertificateRendering{PEM: true}
+	gt.Expect(rendering.CertificateProto(cert)).To(Equal(&mspext.PEMLines{}))
+	gt.Expect(rendering.CertificateProto(bytes.TrimSuffix(cert, []byte("\n")))).To(BeNil())
+	gt.Expect(rendering.CRLProto(cert)).To(BeNil())
+}
@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mspext
+
+import (
+	"fmt"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+)
+
+type IdemixMSPConfig struct{ *msp.IdemixMSPConfig }
+
+func (imc *IdemixMSPConfig) Underlying() proto.Message {
+	return imc.IdemixMSPConfig
+}
+
+func (imc *IdemixMSPConfig) StaticallyOpaqueFields() []string {
+	return []string{"ipk"}
+}
+
+func (imc *IdemixMSPConfig) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != imc.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &IssuerPublicKey{}, nil
+}
+
+// The messages below mirror the idemix protos used by Fabric to marshal the
+// issuer public key of an Idemix MSP. They are not part of fabric-protos-go,
+// so they are defined here to allow the key to be decoded.
+
+// ECP is an elliptic curve point specified by its coordinates.
+type ECP struct {
+	X []byte `protobuf:"bytes,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y []byte `protobuf:"bytes,2,opt,name=y,proto3" json:"y,omitempty"`
+}
+
+func (m *ECP) Reset()         { *m = ECP{} }
+func (m *ECP) String() string { return proto.CompactTextString(m) }
+func (*ECP) ProtoMessage()    {}
+
+// ECP2 is an elliptic curve point specified by its coordinates in the
+// quadratic extension field.
+type ECP2 struct {
+	Xa []byte `protobuf:"bytes,1,opt,name=xa,proto3" json:"xa,omitempty"`
+	Xb []byte `protobuf:"bytes,2,opt,name=xb,proto3" json:"xb,omitempty"`
+	Ya []byte `protobuf:"bytes,3,opt,name=ya,proto3" json:"ya,omitempty"`
+	Yb []byte `protobuf:"bytes,4,opt,name=yb,proto3" json:"yb,omitempty"`
+}
+
+func (m *ECP2) Reset()         { *m = ECP2{} }
+func (m *ECP2) String() string { return proto.CompactTextString(m) }
+func (*ECP2) ProtoMessage()    {}
+
+// IssuerPublicKey is the public key of an Idemix credential issuer.
+type IssuerPublicKey struct {
+	AttributeNames []string `protobuf:"bytes,1,rep,name=attribute_names,json=attributeNames,proto3" json:"attribute_names,omitempty"`
+	HSk            *ECP     `protobuf:"bytes,2,opt,name=h_sk,json=hSk,proto3" json:"h_sk,omitempty"`
+	HRand          *ECP     `protobuf:"bytes,3,opt,name=h_rand,json=hRand,proto3" json:"h_rand,omitempty"`
+	HAttrs         []*ECP   `protobuf:"bytes,4,rep,name=h_attrs,json=hAttrs,proto3" json:"h_attrs,omitempty"`
+	W              *ECP2    `protobuf:"bytes,5,opt,name=w,proto3" json:"w,omitempty"`
+	BarG1          *ECP     `protobuf:"bytes,6,opt,name=bar_g1,json=barG1,proto3" json:"bar_g1,omitempty"`
+	BarG2          *ECP     `protobuf:"bytes,7,opt,name=bar_g2,json=barG2,proto3" json:"bar_g2,omitempty"`
+	ProofC         []byte   `protobuf:"bytes,8,opt,name=proof_c,json=proofC,proto3" json:"proof_c,omitempty"`
+	ProofS         []byte   `protobuf:"bytes,9,opt,name=proof_s,json=proofS,proto3" json:"proof_s,omitempty"`
+	Hash           []byte   `protobuf:"bytes,10,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *IssuerPublicKey) Reset()         { *m = IssuerPublicKey{} }
+func (m *IssuerPublicKey) String() string { return proto.CompactTextString(m) }
+func (*IssuerPublicKey) ProtoMessage()    {}
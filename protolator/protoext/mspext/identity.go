@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mspext
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"time"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// SerializedIdentity decorates a serialized identity, such as the creator of
+// a transaction or the endorser of a proposal response. If Resolve is set,
+// its X.509 certificate is decoded into its subject, issuer and serial
+// number, see protoext.ResolveIdentities. Otherwise the certificate is left
+// base64 encoded.
+type SerializedIdentity struct {
+	*msp.SerializedIdentity
+	Resolve bool
+}
+
+func (si *SerializedIdentity) Underlying() proto.Message {
+	return si.SerializedIdentity
+}
+
+func (si *SerializedIdentity) VariablyOpaqueFields() []string {
+	return []string{"id_bytes"}
+}
+
+func (si *SerializedIdentity) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != si.VariablyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+
+	if !si.Resolve {
+		return nil, nil
+	}
+
+	// identities which are not X.509 certificates, such as idemix
+	// identities, are left opaque
+	if len(si.IdBytes) != 0 {
+		if block, _ := pem.Decode(si.IdBytes); block == nil || block.Type != "CERTIFICATE" {
+			return nil, nil
+		}
+	}
+
+	return &X509Identity{}, nil
+}
+
+// X509Identity is a proto message whose binary encoding is a PEM encoded X.509
+// certificate. It renders the certificate of an identity in an audit friendly
+// form.
+type X509Identity struct {
+	Subject      string `json:"subject"`
+	Issuer       string `json:"issuer"`
+	SerialNumber string `json:"serial_number"`
+	NotBefore    string `json:"not_before"`
+	NotAfter     string `json:"not_after"`
+	Certificate  string `json:"certificate"`
+}
+
+func (xi *X509Identity) Reset()         { *xi = X509Identity{} }
+func (xi *X509Identity) String() string { return xi.Subject }
+func (*X509Identity) ProtoMessage()     {}
+
+// Marshal returns the PEM encoded certificate.
+func (xi *X509Identity) Marshal() ([]byte, error) {
+	return []byte(xi.Certificate), nil
+}
+
+// Unmarshal parses a PEM encoded certificate.
+func (xi *X509Identity) Unmarshal(b []byte) error {
+	block, _ := pem.Decode(b)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return fmt.Errorf("identity is not a PEM encoded certificate")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return fmt.Errorf("parsing identity certificate: %v", err)
+	}
+
+	*xi = X509Identity{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		NotBefore:    cert.NotBefore.UTC().Format(time.RFC3339),
+		NotAfter:     cert.NotAfter.UTC().Format(time.RFC3339),
+		Certificate:  string(b),
+	}
+
+	return nil
+}
+
+func (xi *X509Identity) MarshalJSONPB(*jsonpb.Marshaler) ([]byte, error) {
+	return json.Marshal(xi)
+}
+
+// UnmarshalJSONPB restores the identity from its certificate; the remaining
+// fields are derived from the certificate and are ignored.
+func (xi *X509Identity) UnmarshalJSONPB(_ *jsonpb.Unmarshaler, b []byte) error {
+	decoded := struct {
+		Certificate string `json:"certificate"`
+	}{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+
+	return xi.Unmarshal([]byte(decoded.Certificate))
+}
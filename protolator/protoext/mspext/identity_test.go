@@ -0,0 +1,108 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package mspext_test
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-config/protolator/protoext"
+	. "github.com/onsi/gomega"
+)
+
+func generateCertificate(gt *GomegaWithT) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1234),
+		Subject:      pkix.Name{CommonName: "peer0.org1.example.com", Organization: []string{"Org1"}},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		DNSNames:     []string{"peer0.org1.example.com"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestSerializedIdentityResolution(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	identity := &msp.SerializedIdentity{
+		Mspid:   "Org1MSP",
+		IdBytes: generateCertificate(gt),
+	}
+	resolve := protolator.WithDecorator(protoext.ResolveIdentities)
+
+	// the option applies to a single call, so conversions with and without
+	// identity resolution may run concurrently
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		gt := NewGomegaWithT(t)
+
+		var buffer bytes.Buffer
+		err := protolator.DeepMarshalJSON(&buffer, identity)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(buffer.String()).NotTo(ContainSubstring("subject"))
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		t.Parallel()
+
+		gt := NewGomegaWithT(t)
+
+		var buffer bytes.Buffer
+		err := protolator.DeepMarshalJSON(&buffer, identity, resolve)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(buffer.String()).To(ContainSubstring(`"mspid": "Org1MSP"`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"subject": "CN=peer0.org1.example.com,O=Org1"`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"issuer": "CN=peer0.org1.example.com,O=Org1"`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"serial_number": "1234"`))
+
+		decoded := &msp.SerializedIdentity{}
+		err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded, resolve)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(proto.Equal(decoded, identity)).To(BeTrue())
+	})
+
+	t.Run("enabled with non X.509 identity", func(t *testing.T) {
+		t.Parallel()
+
+		gt := NewGomegaWithT(t)
+
+		idemixIdentity := &msp.SerializedIdentity{
+			Mspid:   "IdemixMSP",
+			IdBytes: []byte("idemix-identity"),
+		}
+
+		var buffer bytes.Buffer
+		err := protolator.DeepMarshalJSON(&buffer, idemixIdentity, resolve)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(buffer.String()).To(ContainSubstring(`"id_bytes": "aWRlbWl4LWlkZW50aXR5"`))
+
+		decoded := &msp.SerializedIdentity{}
+		err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded, resolve)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(proto.Equal(decoded, idemixIdentity)).To(BeTrue())
+	})
+}
@@ -7,8 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package mspext_test
 
 import (
+	"bytes"
+	"testing"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/protolator"
 	"github.com/hyperledger/fabric-config/protolator/protoext/mspext"
+	. "github.com/onsi/gomega"
 )
 
 // ensure structs implement expected interfaces
@@ -16,6 +22,65 @@ var (
 	_ protolator.VariablyOpaqueFieldProto = &mspext.MSPConfig{}
 	_ protolator.DecoratedProto           = &mspext.MSPConfig{}
 
+	_ protolator.StaticallyOpaqueFieldProto = &mspext.IdemixMSPConfig{}
+	_ protolator.DecoratedProto             = &mspext.IdemixMSPConfig{}
+
 	_ protolator.VariablyOpaqueFieldProto = &mspext.MSPPrincipal{}
 	_ protolator.DecoratedProto           = &mspext.MSPPrincipal{}
+
+	_ protolator.VariablyOpaqueFieldProto = &mspext.SerializedIdentity{}
+	_ protolator.DecoratedProto           = &mspext.SerializedIdentity{}
+
+	_ protolator.VariablyOpaqueSliceFieldProto = &mspext.FabricMSPConfig{}
+	_ protolator.DecoratedProto                = &mspext.FabricMSPConfig{}
+
+	_ protolator.VariablyOpaqueFieldProto = &mspext.FabricOUIdentifier{}
+	_ protolator.DecoratedProto           = &mspext.FabricOUIdentifier{}
 )
+
+func TestIdemixMSPConfig(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	ipk, err := proto.Marshal(&mspext.IssuerPublicKey{
+		AttributeNames: []string{"OU", "Role", "EnrollmentID", "RevocationHandle"},
+		HSk:            &mspext.ECP{X: []byte("x"), Y: []byte("y")},
+		W:              &mspext.ECP2{Xa: []byte("xa"), Xb: []byte("xb"), Ya: []byte("ya"), Yb: []byte("yb")},
+		Hash:           []byte("hash"),
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	idemixConfig, err := proto.Marshal(&msp.IdemixMSPConfig{
+		Name:         "IdemixOrg",
+		Ipk:          ipk,
+		RevocationPk: []byte("revocation-pk"),
+		Epoch:        7,
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	mspConfig := &msp.MSPConfig{
+		Type:   1,
+		Config: idemixConfig,
+	}
+
+	var buffer bytes.Buffer
+	err = protolator.DeepMarshalJSON(&buffer, mspConfig)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(buffer.String()).To(ContainSubstring(`"attribute_names": [`))
+	gt.Expect(buffer.String()).To(ContainSubstring(`"epoch": "7"`))
+
+	decoded := &msp.MSPConfig{}
+	err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	decodedIdemix := &msp.IdemixMSPConfig{}
+	err = proto.Unmarshal(decoded.Config, decodedIdemix)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(decodedIdemix.Name).To(Equal("IdemixOrg"))
+	gt.Expect(decodedIdemix.Epoch).To(Equal(int64(7)))
+
+	decodedIpk := &mspext.IssuerPublicKey{}
+	err = proto.Unmarshal(decodedIdemix.Ipk, decodedIpk)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(decodedIpk.AttributeNames).To(Equal([]string{"OU", "Role", "EnrollmentID", "RevocationHandle"}))
+	gt.Expect(decodedIpk.W.Yb).To(Equal([]byte("yb")))
+}
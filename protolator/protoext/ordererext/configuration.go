@@ -13,8 +13,11 @@ import (
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/etcdraft"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/smartbft"
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes/empty"
+	"github.com/hyperledger/fabric-config/protolator/protoext/customext"
+	"github.com/hyperledger/fabric-config/protolator/protoext/mspext"
 )
 
 type DynamicOrdererGroup struct {
@@ -80,6 +83,8 @@ func (ct *ConsensusType) VariablyOpaqueFieldProto(name string) (proto.Message, e
 	switch ct.Type {
 	case "etcdraft":
 		return &etcdraft.ConfigMetadata{}, nil
+	case "smartbft":
+		return &smartbft.ConfigMetadata{}, nil
 	default:
 		return &empty.Empty{}, nil
 	}
@@ -147,6 +152,9 @@ func (docv *DynamicOrdererConfigValue) StaticallyOpaqueFieldProto(name string) (
 	case "Capabilities":
 		return &common.Capabilities{}, nil
 	default:
+		if msg, ok := customext.ConfigValue(docv.name); ok {
+			return msg, nil
+		}
 		return nil, fmt.Errorf("unknown Orderer ConfigValue name: %s", docv.name)
 	}
 }
@@ -174,6 +182,63 @@ func (doocv *DynamicOrdererOrgConfigValue) StaticallyOpaqueFieldProto(name strin
 	case "Endpoints":
 		return &common.OrdererAddresses{}, nil
 	default:
+		if msg, ok := customext.ConfigValue(doocv.name); ok {
+			return msg, nil
+		}
 		return nil, fmt.Errorf("unknown Orderer Org ConfigValue name: %s", doocv.name)
 	}
 }
+
+// EtcdRaftConsenter decorates an etcdraft consenter, whose TLS certificates
+// are decoded according to Certificates.
+type EtcdRaftConsenter struct {
+	*etcdraft.Consenter
+	Certificates mspext.CertificateRendering
+}
+
+func (erc *EtcdRaftConsenter) Underlying() proto.Message {
+	return erc.Consenter
+}
+
+func (erc *EtcdRaftConsenter) VariablyOpaqueFields() []string {
+	return []string{"client_tls_cert", "server_tls_cert"}
+}
+
+func (erc *EtcdRaftConsenter) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case "client_tls_cert":
+		return erc.Certificates.CertificateProto(erc.ClientTlsCert), nil
+	case "server_tls_cert":
+		return erc.Certificates.CertificateProto(erc.ServerTlsCert), nil
+	default:
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+}
+
+// SmartBFTConsenter decorates a SmartBFT consenter, whose identity and TLS
+// certificates are decoded according to Certificates.
+type SmartBFTConsenter struct {
+	*smartbft.Consenter
+	Certificates mspext.CertificateRendering
+}
+
+func (sbc *SmartBFTConsenter) Underlying() proto.Message {
+	return sbc.Consenter
+}
+
+func (sbc *SmartBFTConsenter) VariablyOpaqueFields() []string {
+	return []string{"identity", "client_tls_cert", "server_tls_cert"}
+}
+
+func (sbc *SmartBFTConsenter) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case "identity":
+		return sbc.Certificates.CertificateProto(sbc.Identity), nil
+	case "client_tls_cert":
+		return sbc.Certificates.CertificateProto(sbc.ClientTlsCert), nil
+	case "server_tls_cert":
+		return sbc.Certificates.CertificateProto(sbc.ServerTlsCert), nil
+	default:
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+}
@@ -7,8 +7,25 @@ SPDX-License-Identifier: Apache-2.0
 package ordererext_test
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/etcdraft"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/orderer/smartbft"
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-config/protolator/protoext"
 	"github.com/hyperledger/fabric-config/protolator/protoext/ordererext"
+	. "github.com/onsi/gomega"
 )
 
 // ensure structs implement expected interfaces
@@ -23,4 +40,56 @@ var (
 	_ protolator.DecoratedProto             = &ordererext.DynamicOrdererConfigValue{}
 	_ protolator.StaticallyOpaqueFieldProto = &ordererext.DynamicOrdererOrgConfigValue{}
 	_ protolator.DecoratedProto             = &ordererext.DynamicOrdererOrgConfigValue{}
+	_ protolator.VariablyOpaqueFieldProto   = &ordererext.EtcdRaftConsenter{}
+	_ protolator.DecoratedProto             = &ordererext.EtcdRaftConsenter{}
+	_ protolator.VariablyOpaqueFieldProto   = &ordererext.SmartBFTConsenter{}
+	_ protolator.DecoratedProto             = &ordererext.SmartBFTConsenter{}
 )
+
+func TestConsenterCertificateAnnotation(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	gt.Expect(err).NotTo(HaveOccurred())
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "orderer1.example.com"},
+		NotBefore:    time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		NotAfter:     time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		DNSNames:     []string{"orderer1.example.com"},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	gt.Expect(err).NotTo(HaveOccurred())
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	etcdRaftMetadata, err := proto.Marshal(&etcdraft.ConfigMetadata{
+		Consenters: []*etcdraft.Consenter{
+			{Host: "orderer1.example.com", Port: 7050, ClientTlsCert: cert, ServerTlsCert: cert},
+		},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	smartBFTMetadata, err := proto.Marshal(&smartbft.ConfigMetadata{
+		Consenters: []*smartbft.Consenter{
+			{ConsenterId: 1, Host: "orderer1.example.com", Port: 7050, MspId: "OrdererMSP", Identity: cert, ClientTlsCert: cert, ServerTlsCert: cert},
+		},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	annotate := protolator.WithDecorator(protoext.AnnotateCertificates)
+
+	for _, consensusType := range []*orderer.ConsensusType{
+		{Type: "etcdraft", Metadata: etcdRaftMetadata},
+		{Type: "smartbft", Metadata: smartBFTMetadata},
+	} {
+		var buffer bytes.Buffer
+		err = protolator.DeepMarshalJSON(&buffer, consensusType, annotate)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(buffer.String()).To(ContainSubstring(`"subject": "CN=orderer1.example.com"`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"serial_number": "42"`))
+
+		decoded := &orderer.ConsensusType{}
+		err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded, annotate)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(proto.Equal(decoded, consensusType)).To(BeTrue())
+	}
+}
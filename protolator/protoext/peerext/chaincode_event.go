@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peerext
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+type chaincodeEventKey struct {
+	chaincodeName string
+	eventName     string
+}
+
+var chaincodeEventRegistry = struct {
+	sync.RWMutex
+	payloads map[chaincodeEventKey]func() proto.Message
+}{
+	payloads: map[chaincodeEventKey]func() proto.Message{},
+}
+
+// RegisterChaincodeEventPayload registers a constructor for the proto message
+// carried in the payload of events named eventName which are emitted by the
+// chaincode chaincodeName. Payloads of events which are not registered are
+// left opaque.
+func RegisterChaincodeEventPayload(chaincodeName, eventName string, newMsg func() proto.Message) error {
+	if chaincodeName == "" || eventName == "" {
+		return fmt.Errorf("chaincode name and event name are required")
+	}
+
+	if newMsg == nil {
+		return fmt.Errorf("payload constructor for event %s of chaincode %s is required", eventName, chaincodeName)
+	}
+
+	chaincodeEventRegistry.Lock()
+	defer chaincodeEventRegistry.Unlock()
+
+	key := chaincodeEventKey{chaincodeName: chaincodeName, eventName: eventName}
+	if _, ok := chaincodeEventRegistry.payloads[key]; ok {
+		return fmt.Errorf("payload for event %s of chaincode %s is already registered", eventName, chaincodeName)
+	}
+	chaincodeEventRegistry.payloads[key] = newMsg
+
+	return nil
+}
+
+// RegisterChaincodeEventJSONPayload registers the payload of events named
+// eventName which are emitted by the chaincode chaincodeName as a JSON object.
+// Payloads of such events which are not a JSON object are left opaque.
+func RegisterChaincodeEventJSONPayload(chaincodeName, eventName string) error {
+	return RegisterChaincodeEventPayload(chaincodeName, eventName, func() proto.Message { return &JSONPayload{} })
+}
+
+func chaincodeEventPayload(chaincodeName, eventName string) (proto.Message, bool) {
+	chaincodeEventRegistry.RLock()
+	newMsg, ok := chaincodeEventRegistry.payloads[chaincodeEventKey{chaincodeName: chaincodeName, eventName: eventName}]
+	chaincodeEventRegistry.RUnlock()
+	if !ok {
+		return nil, false
+	}
+
+	return newMsg(), true
+}
+
+type ChaincodeEvent struct {
+	*peer.ChaincodeEvent
+}
+
+func (ce *ChaincodeEvent) Underlying() proto.Message {
+	return ce.ChaincodeEvent
+}
+
+func (ce *ChaincodeEvent) VariablyOpaqueFields() []string {
+	return []string{"payload"}
+}
+
+func (ce *ChaincodeEvent) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != ce.VariablyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+
+	msg, ok := chaincodeEventPayload(ce.ChaincodeId, ce.EventName)
+	if !ok {
+		// leave payloads of unregistered events opaque
+		return nil, nil
+	}
+
+	if _, ok := msg.(*JSONPayload); ok && len(ce.Payload) > 0 && !isJSONObject(ce.Payload) {
+		// leave payloads which are not the registered JSON object opaque,
+		// rather than failing to decode the whole block of the event
+		return nil, nil
+	}
+
+	return msg, nil
+}
+
+// isJSONObject reports whether b is a JSON encoded object.
+func isJSONObject(b []byte) bool {
+	var object map[string]json.RawMessage
+	return json.Unmarshal(b, &object) == nil && object != nil
+}
+
+// JSONPayload is a proto message whose binary encoding is a JSON object. It
+// allows JSON encoded payloads to be rendered as structured data.
+type JSONPayload struct {
+	raw []byte
+}
+
+func (jp *JSONPayload) Reset()         { jp.raw = nil }
+func (jp *JSONPayload) String() string { return string(jp.raw) }
+func (*JSONPayload) ProtoMessage()     {}
+
+// Marshal returns the JSON encoded payload.
+func (jp *JSONPayload) Marshal() ([]byte, error) {
+	return jp.raw, nil
+}
+
+// Unmarshal stores the JSON encoded payload.
+func (jp *JSONPayload) Unmarshal(b []byte) error {
+	if !json.Valid(b) {
+		return fmt.Errorf("payload is not valid JSON")
+	}
+	jp.raw = append([]byte(nil), b...)
+	return nil
+}
+
+func (jp *JSONPayload) MarshalJSONPB(*jsonpb.Marshaler) ([]byte, error) {
+	return jp.raw, nil
+}
+
+func (jp *JSONPayload) UnmarshalJSONPB(_ *jsonpb.Unmarshaler, b []byte) error {
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, b); err != nil {
+		return err
+	}
+	jp.raw = compacted.Bytes()
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peerext_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-config/protolator/protoext/peerext"
+	. "github.com/onsi/gomega"
+)
+
+func deepRoundTrip(gt *GomegaWithT, msg proto.Message) (string, *peer.ChaincodeEvent) {
+	var buffer bytes.Buffer
+	err := protolator.DeepMarshalJSON(&buffer, msg)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	decoded := &peer.ChaincodeEvent{}
+	err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	return buffer.String(), decoded
+}
+
+func TestChaincodeEventPayload(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	err := peerext.RegisterChaincodeEventPayload("mycc", "proto-event", func() proto.Message { return &peer.ChaincodeID{} })
+	gt.Expect(err).NotTo(HaveOccurred())
+	err = peerext.RegisterChaincodeEventJSONPayload("mycc", "json-event")
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = peerext.RegisterChaincodeEventJSONPayload("mycc", "json-event")
+	gt.Expect(err).To(MatchError("payload for event json-event of chaincode mycc is already registered"))
+	err = peerext.RegisterChaincodeEventJSONPayload("", "json-event")
+	gt.Expect(err).To(MatchError("chaincode name and event name are required"))
+
+	t.Run("registered proto payload", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		payload, err := proto.Marshal(&peer.ChaincodeID{Name: "asset", Version: "1.0"})
+		gt.Expect(err).NotTo(HaveOccurred())
+		event := &peer.ChaincodeEvent{ChaincodeId: "mycc", EventName: "proto-event", Payload: payload}
+
+		output, decoded := deepRoundTrip(gt, event)
+		gt.Expect(output).To(ContainSubstring(`"name": "asset"`))
+		gt.Expect(proto.Equal(decoded, event)).To(BeTrue())
+	})
+
+	t.Run("registered JSON payload", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		event := &peer.ChaincodeEvent{ChaincodeId: "mycc", EventName: "json-event", Payload: []byte(`{"asset":"a1","owner":"alice"}`)}
+
+		output, decoded := deepRoundTrip(gt, event)
+		gt.Expect(output).To(ContainSubstring(`"owner": "alice"`))
+		gt.Expect(decoded.Payload).To(MatchJSON(event.Payload))
+	})
+
+	t.Run("registered JSON payload which is not JSON", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		event := &peer.ChaincodeEvent{ChaincodeId: "mycc", EventName: "json-event", Payload: []byte{0xff, 0x00, '{'}}
+
+		output, decoded := deepRoundTrip(gt, event)
+		gt.Expect(output).To(ContainSubstring(`"payload": "/wB7"`))
+		gt.Expect(proto.Equal(decoded, event)).To(BeTrue())
+	})
+
+	t.Run("registered JSON payload which is not an object", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		event := &peer.ChaincodeEvent{ChaincodeId: "mycc", EventName: "json-event", Payload: []byte(`["a1"]`)}
+
+		output, decoded := deepRoundTrip(gt, event)
+		gt.Expect(output).To(ContainSubstring(`"payload": "WyJhMSJd"`))
+		gt.Expect(proto.Equal(decoded, event)).To(BeTrue())
+	})
+
+	t.Run("unregistered payload", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		event := &peer.ChaincodeEvent{ChaincodeId: "othercc", EventName: "json-event", Payload: []byte("opaque")}
+
+		output, decoded := deepRoundTrip(gt, event)
+		gt.Expect(output).To(ContainSubstring(`"payload": "b3BhcXVl"`))
+		gt.Expect(proto.Equal(decoded, event)).To(BeTrue())
+	})
+}
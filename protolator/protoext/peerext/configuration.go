@@ -13,6 +13,7 @@ import (
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator/protoext/customext"
 )
 
 type DynamicApplicationGroup struct {
@@ -106,6 +107,9 @@ func (ccv *DynamicApplicationConfigValue) StaticallyOpaqueFieldProto(name string
 	case "ACLs":
 		return &peer.ACLs{}, nil
 	default:
+		if msg, ok := customext.ConfigValue(ccv.name); ok {
+			return msg, nil
+		}
 		return nil, fmt.Errorf("Unknown Application ConfigValue name: %s", ccv.name)
 	}
 }
@@ -133,6 +137,9 @@ func (daocv *DynamicApplicationOrgConfigValue) StaticallyOpaqueFieldProto(name s
 	case "AnchorPeers":
 		return &peer.AnchorPeers{}, nil
 	default:
+		if msg, ok := customext.ConfigValue(daocv.name); ok {
+			return msg, nil
+		}
 		return nil, fmt.Errorf("Unknown Application Org ConfigValue name: %s", daocv.name)
 	}
 }
@@ -7,8 +7,23 @@ SPDX-License-Identifier: Apache-2.0
 package peerext_test
 
 import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+	"testing"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/ledger/rwset"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-config/protolator/protoext"
 	"github.com/hyperledger/fabric-config/protolator/protoext/peerext"
+	. "github.com/onsi/gomega"
 )
 
 // ensure structs implement expected interfaces
@@ -27,9 +42,25 @@ var (
 	_ protolator.StaticallyOpaqueFieldProto = &peerext.ChaincodeAction{}
 	_ protolator.DecoratedProto             = &peerext.ChaincodeAction{}
 
+	_ protolator.VariablyOpaqueFieldProto = &peerext.ChaincodeEvent{}
+	_ protolator.DecoratedProto           = &peerext.ChaincodeEvent{}
+
+	_ protolator.VariablyOpaqueFieldProto = &peerext.Endorsement{}
+	_ protolator.DecoratedProto           = &peerext.Endorsement{}
+
+	_ protolator.StaticallyOpaqueFieldProto = &peerext.SignedProposal{}
+	_ protolator.DecoratedProto             = &peerext.SignedProposal{}
+	_ protolator.StaticallyOpaqueFieldProto = &peerext.Proposal{}
+	_ protolator.DecoratedProto             = &peerext.Proposal{}
+
+	_ protolator.StaticallyOpaqueFieldProto = &peerext.ProposalResponse{}
+	_ protolator.DecoratedProto             = &peerext.ProposalResponse{}
 	_ protolator.StaticallyOpaqueFieldProto = &peerext.ProposalResponsePayload{}
 	_ protolator.DecoratedProto             = &peerext.ProposalResponsePayload{}
 
+	_ protolator.StaticallyOpaqueFieldProto = &peerext.SignedSnapshotRequest{}
+	_ protolator.DecoratedProto             = &peerext.SignedSnapshotRequest{}
+
 	_ protolator.StaticallyOpaqueFieldProto = &peerext.TransactionAction{}
 	_ protolator.DecoratedProto             = &peerext.TransactionAction{}
 	_ protolator.StaticallyOpaqueFieldProto = &peerext.ChaincodeActionPayload{}
@@ -37,3 +68,135 @@ var (
 	_ protolator.StaticallyOpaqueFieldProto = &peerext.ChaincodeEndorsedAction{}
 	_ protolator.DecoratedProto             = &peerext.ChaincodeEndorsedAction{}
 )
+
+func TestEndorsementIdentityResolution(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	endorser, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("identity")})
+	gt.Expect(err).NotTo(HaveOccurred())
+	endorsement := &peer.Endorsement{Endorser: endorser, Signature: []byte("signature")}
+
+	e := &peerext.Endorsement{Endorsement: endorsement}
+	msg, err := e.VariablyOpaqueFieldProto("endorser")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msg).To(BeNil())
+
+	e.Resolve = true
+	msg, err = e.VariablyOpaqueFieldProto("endorser")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msg).To(Equal(&msp.SerializedIdentity{}))
+
+	resolve := protolator.WithDecorator(protoext.ResolveIdentities)
+	var buffer bytes.Buffer
+	err = protolator.DeepMarshalJSON(&buffer, endorsement, resolve)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(buffer.String()).To(ContainSubstring(`"mspid": "Org1MSP"`))
+
+	decoded := &peer.Endorsement{}
+	err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded, resolve)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(proto.Equal(decoded, endorsement)).To(BeTrue())
+
+	_, err = e.VariablyOpaqueFieldProto("payload")
+	gt.Expect(err).To(MatchError("not a marshaled field: payload"))
+}
+
+func TestProposalResponseDecoding(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	results, err := proto.Marshal(&rwset.TxReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsRwset:   []*rwset.NsReadWriteSet{{Namespace: "mycc"}},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	extension, err := proto.Marshal(&peer.ChaincodeAction{
+		Results:     results,
+		Response:    &peer.Response{Status: 200},
+		ChaincodeId: &peer.ChaincodeID{Name: "mycc", Version: "1.0"},
+	})
+	gt.Expect(err).NotTo(HaveOccurred())
+	payload, err := proto.Marshal(&peer.ProposalResponsePayload{ProposalHash: []byte("proposal-hash"), Extension: extension})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	endorser, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("identity")})
+	gt.Expect(err).NotTo(HaveOccurred())
+	digest := sha256.Sum256(append(payload, endorser...))
+	r, s, err := ecdsa.Sign(rand.Reader, key, digest[:])
+	gt.Expect(err).NotTo(HaveOccurred())
+	signature, err := asn1.Marshal(struct{ R, S *big.Int }{R: r, S: s})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	proposalResponse := &peer.ProposalResponse{
+		Version:     1,
+		Response:    &peer.Response{Status: 200, Message: "OK"},
+		Payload:     payload,
+		Endorsement: &peer.Endorsement{Endorser: endorser, Signature: signature},
+	}
+
+	t.Run("without identity resolution", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		var buffer bytes.Buffer
+		err := protolator.DeepMarshalJSON(&buffer, proposalResponse)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(buffer.String()).To(ContainSubstring(`"namespace": "mycc"`))
+		gt.Expect(buffer.String()).NotTo(ContainSubstring(`"mspid": "Org1MSP"`))
+		gt.Expect(buffer.String()).NotTo(ContainSubstring(`"algorithm": "ECDSA"`))
+
+		decoded := &peer.ProposalResponse{}
+		err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(proto.Equal(decoded, proposalResponse)).To(BeTrue())
+	})
+
+	t.Run("with identity resolution", func(t *testing.T) {
+		gt := NewGomegaWithT(t)
+
+		resolve := protolator.WithDecorator(protoext.ResolveIdentities)
+		var buffer bytes.Buffer
+		err := protolator.DeepMarshalJSON(&buffer, proposalResponse, resolve)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(buffer.String()).To(ContainSubstring(`"namespace": "mycc"`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"mspid": "Org1MSP"`))
+		gt.Expect(buffer.String()).To(ContainSubstring(`"algorithm": "ECDSA"`))
+
+		decoded := &peer.ProposalResponse{}
+		err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded, resolve)
+		gt.Expect(err).NotTo(HaveOccurred())
+		gt.Expect(proto.Equal(decoded, proposalResponse)).To(BeTrue())
+	})
+}
+
+func TestECDSASignature(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	signature, err := asn1.Marshal(struct{ R, S *big.Int }{R: big.NewInt(1234), S: big.NewInt(5678)})
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	es := &peerext.ECDSASignature{}
+	err = proto.Unmarshal(signature, es)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(es.Algorithm).To(Equal("ECDSA"))
+	gt.Expect(es.R).To(Equal("1234"))
+	gt.Expect(es.S).To(Equal("5678"))
+
+	marshaled, err := proto.Marshal(es)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(marshaled).To(Equal(signature))
+
+	err = proto.Unmarshal([]byte("signature"), es)
+	gt.Expect(err).To(MatchError(ContainSubstring("parsing ECDSA signature")))
+
+	e := &peerext.Endorsement{Endorsement: &peer.Endorsement{Signature: []byte("signature")}, Resolve: true}
+	msg, err := e.VariablyOpaqueFieldProto("signature")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msg).To(BeNil())
+
+	e.Signature = signature
+	msg, err = e.VariablyOpaqueFieldProto("signature")
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(msg).To(Equal(&peerext.ECDSASignature{}))
+}
@@ -9,6 +9,7 @@ package peerext
 import (
 	"fmt"
 
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/common"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/ledger/rwset"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
 	"github.com/golang/protobuf/proto"
@@ -55,3 +56,45 @@ func (ca *ChaincodeAction) StaticallyOpaqueFieldProto(name string) (proto.Messag
 		return nil, fmt.Errorf("not a marshaled field: %s", name)
 	}
 }
+
+type SignedProposal struct {
+	*peer.SignedProposal
+}
+
+func (sp *SignedProposal) Underlying() proto.Message {
+	return sp.SignedProposal
+}
+
+func (sp *SignedProposal) StaticallyOpaqueFields() []string {
+	return []string{"proposal_bytes"}
+}
+
+func (sp *SignedProposal) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != sp.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &peer.Proposal{}, nil
+}
+
+type Proposal struct {
+	*peer.Proposal
+}
+
+func (p *Proposal) Underlying() proto.Message {
+	return p.Proposal
+}
+
+func (p *Proposal) StaticallyOpaqueFields() []string {
+	return []string{"header", "payload"}
+}
+
+func (p *Proposal) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case "header":
+		return &common.Header{}, nil
+	case "payload":
+		return &peer.ChaincodeProposalPayload{}, nil
+	default:
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+}
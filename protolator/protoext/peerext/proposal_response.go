@@ -7,12 +7,40 @@ SPDX-License-Identifier: Apache-2.0
 package peerext
 
 import (
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"math/big"
 
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
 	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
+	"github.com/golang/protobuf/jsonpb"
 	"github.com/golang/protobuf/proto"
 )
 
+// ProposalResponse decorates the response of an endorser to a proposal. Its
+// payload is decoded down to the chaincode action, including the read-write
+// set and chaincode event, and its endorsement is decoded by Endorsement.
+type ProposalResponse struct {
+	*peer.ProposalResponse
+}
+
+func (pr *ProposalResponse) Underlying() proto.Message {
+	return pr.ProposalResponse
+}
+
+func (pr *ProposalResponse) StaticallyOpaqueFields() []string {
+	return []string{"payload"}
+}
+
+func (pr *ProposalResponse) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != pr.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &peer.ProposalResponsePayload{}, nil
+}
+
 type ProposalResponsePayload struct {
 	*peer.ProposalResponsePayload
 }
@@ -31,3 +59,122 @@ func (ppr *ProposalResponsePayload) StaticallyOpaqueFieldProto(name string) (pro
 	}
 	return &peer.ChaincodeAction{}, nil
 }
+
+// Endorsement decorates the endorsement of a proposal response. If Resolve is
+// set, its endorser is decoded as a serialized identity and ECDSA signatures
+// are annotated, see protoext.ResolveIdentities.
+type Endorsement struct {
+	*peer.Endorsement
+	Resolve bool
+}
+
+func (e *Endorsement) Underlying() proto.Message {
+	return e.Endorsement
+}
+
+func (e *Endorsement) VariablyOpaqueFields() []string {
+	return []string{"endorser", "signature"}
+}
+
+// VariablyOpaqueFieldProto resolves the endorser and annotates ECDSA
+// signatures if Resolve is set. Otherwise both are left base64 encoded.
+func (e *Endorsement) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	switch name {
+	case e.VariablyOpaqueFields()[0]: // endorser
+		if !e.Resolve {
+			return nil, nil
+		}
+		return &msp.SerializedIdentity{}, nil
+	case e.VariablyOpaqueFields()[1]: // signature
+		if !e.Resolve {
+			return nil, nil
+		}
+		// signatures which are not ASN.1 encoded ECDSA signatures are left
+		// opaque
+		if len(e.Signature) != 0 {
+			if _, err := unmarshalECDSASignature(e.Signature); err != nil {
+				return nil, nil
+			}
+		}
+		return &ECDSASignature{}, nil
+	default:
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+}
+
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+func unmarshalECDSASignature(b []byte) (*ecdsaSignature, error) {
+	sig := &ecdsaSignature{}
+	rest, err := asn1.Unmarshal(b, sig)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("trailing data after signature")
+	}
+	if sig.R == nil || sig.S == nil || sig.R.Sign() <= 0 || sig.S.Sign() <= 0 {
+		return nil, fmt.Errorf("signature values must be positive")
+	}
+	return sig, nil
+}
+
+// ECDSASignature is a proto message whose binary encoding is an ASN.1 encoded
+// ECDSA signature. It annotates the signature of an endorsement with its R
+// and S values.
+type ECDSASignature struct {
+	Algorithm string `json:"algorithm"`
+	R         string `json:"r"`
+	S         string `json:"s"`
+	Signature string `json:"signature"`
+}
+
+func (es *ECDSASignature) Reset()         { *es = ECDSASignature{} }
+func (es *ECDSASignature) String() string { return es.Signature }
+func (*ECDSASignature) ProtoMessage()     {}
+
+// Marshal returns the ASN.1 encoded signature.
+func (es *ECDSASignature) Marshal() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(es.Signature)
+}
+
+// Unmarshal parses an ASN.1 encoded ECDSA signature.
+func (es *ECDSASignature) Unmarshal(b []byte) error {
+	sig, err := unmarshalECDSASignature(b)
+	if err != nil {
+		return fmt.Errorf("parsing ECDSA signature: %v", err)
+	}
+
+	*es = ECDSASignature{
+		Algorithm: "ECDSA",
+		R:         sig.R.String(),
+		S:         sig.S.String(),
+		Signature: base64.StdEncoding.EncodeToString(b),
+	}
+
+	return nil
+}
+
+func (es *ECDSASignature) MarshalJSONPB(*jsonpb.Marshaler) ([]byte, error) {
+	return json.Marshal(es)
+}
+
+// UnmarshalJSONPB restores the signature from its encoding; the remaining
+// fields are derived from the signature and are ignored.
+func (es *ECDSASignature) UnmarshalJSONPB(_ *jsonpb.Unmarshaler, b []byte) error {
+	decoded := struct {
+		Signature string `json:"signature"`
+	}{}
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return err
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(decoded.Signature)
+	if err != nil {
+		return fmt.Errorf("decoding signature: %v", err)
+	}
+
+	return es.Unmarshal(signature)
+}
@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peerext
+
+import (
+	"fmt"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
+	"github.com/golang/protobuf/proto"
+)
+
+// SignedSnapshotRequest decorates the signed requests of the snapshot service
+// of the peer. The request is decoded as a SnapshotRequest, which is also
+// how the SnapshotQuery of pending snapshot queries is decoded, as both share
+// the same leading fields. The snapshot metadata files written by the peer
+// are JSON documents and need no decoration.
+type SignedSnapshotRequest struct {
+	*peer.SignedSnapshotRequest
+}
+
+func (ssr *SignedSnapshotRequest) Underlying() proto.Message {
+	return ssr.SignedSnapshotRequest
+}
+
+func (ssr *SignedSnapshotRequest) StaticallyOpaqueFields() []string {
+	return []string{"request"}
+}
+
+func (ssr *SignedSnapshotRequest) StaticallyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != ssr.StaticallyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	return &peer.SnapshotRequest{}, nil
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package peerext_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/common"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/peer"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+	"github.com/hyperledger/fabric-config/protolator/protoext/peerext"
+	. "github.com/onsi/gomega"
+)
+
+func TestSignedSnapshotRequest(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	creator, err := proto.Marshal(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("identity")})
+	gt.Expect(err).NotTo(HaveOccurred())
+	signatureHeader := &common.SignatureHeader{Creator: creator, Nonce: []byte("nonce")}
+
+	tests := []struct {
+		testName        string
+		request         proto.Message
+		expectedOutputs []string
+	}{
+		{
+			testName:        "snapshot request",
+			request:         &peer.SnapshotRequest{SignatureHeader: signatureHeader, ChannelId: "mychannel", BlockNumber: 42},
+			expectedOutputs: []string{`"channel_id": "mychannel"`, `"block_number": "42"`, `"mspid": "Org1MSP"`},
+		},
+		{
+			testName:        "snapshot query",
+			request:         &peer.SnapshotQuery{SignatureHeader: signatureHeader, ChannelId: "mychannel"},
+			expectedOutputs: []string{`"channel_id": "mychannel"`, `"mspid": "Org1MSP"`},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.testName, func(t *testing.T) {
+			gt := NewGomegaWithT(t)
+
+			request, err := proto.Marshal(tt.request)
+			gt.Expect(err).NotTo(HaveOccurred())
+			signed := &peer.SignedSnapshotRequest{Request: request, Signature: []byte("signature")}
+
+			var buffer bytes.Buffer
+			err = protolator.DeepMarshalJSON(&buffer, signed)
+			gt.Expect(err).NotTo(HaveOccurred())
+			for _, expectedOutput := range tt.expectedOutputs {
+				gt.Expect(buffer.String()).To(ContainSubstring(expectedOutput))
+			}
+
+			decoded := &peer.SignedSnapshotRequest{}
+			err = protolator.DeepUnmarshalJSON(bytes.NewReader(buffer.Bytes()), decoded)
+			gt.Expect(err).NotTo(HaveOccurred())
+			gt.Expect(proto.Equal(decoded, signed)).To(BeTrue())
+		})
+	}
+
+	_, err = (&peerext.SignedSnapshotRequest{}).StaticallyOpaqueFieldProto("signature")
+	gt.Expect(err).To(MatchError("not a marshaled field: signature"))
+}
@@ -0,0 +1,84 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protolator
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+)
+
+// The functions below handle the standard JSON mapping of protocol buffers,
+// as emitted by protojson, jsonpb and other stock protobuf tooling. Unlike
+// the deep format of DeepMarshalJSON, the standard mapping leaves opaque byte
+// fields, such as config values and envelope payloads, base64 encoded and
+// names fields in lowerCamelCase. Both formats describe the same binary
+// message, so artifacts such as a common.Config, common.ConfigUpdate or
+// common.Block can be converted from one to the other.
+
+// MarshalProtoJSON writes msg to w in the standard JSON mapping of protocol
+// buffers.
+func MarshalProtoJSON(w io.Writer, msg proto.Message) error {
+	return (&jsonpb.Marshaler{Indent: "\t"}).Marshal(w, msg)
+}
+
+// UnmarshalProtoJSON decodes JSON in the standard mapping of protocol
+// buffers into msg. Both lowerCamelCase and original field names are
+// accepted.
+func UnmarshalProtoJSON(r io.Reader, msg proto.Message) error {
+	return jsonpb.Unmarshal(r, msg)
+}
+
+// DeepJSONToProtoJSON converts JSON in the deep format of DeepMarshalJSON
+// into the standard JSON mapping of protocol buffers. The message msg
+// determines the type of the document, e.g. &common.Config{}, and holds the
+// decoded message afterwards.
+func DeepJSONToProtoJSON(w io.Writer, r io.Reader, msg proto.Message) error {
+	err := DeepUnmarshalJSON(r, msg)
+	if err != nil {
+		return err
+	}
+
+	return MarshalProtoJSON(w, msg)
+}
+
+// ProtoJSONToDeepJSON converts JSON in the standard mapping of protocol
+// buffers into the deep format of DeepMarshalJSON. The message msg determines
+// the type of the document, e.g. &common.Block{}, and holds the decoded
+// message afterwards.
+func ProtoJSONToDeepJSON(w io.Writer, r io.Reader, msg proto.Message) error {
+	err := UnmarshalProtoJSON(r, msg)
+	if err != nil {
+		return err
+	}
+
+	return DeepMarshalJSON(w, msg)
+}
+
+// UnmarshalAnyJSON decodes JSON in either the deep format of DeepMarshalJSON
+// or the standard mapping of protocol buffers into msg, for tools accepting
+// the artifacts of both this package and stock protobuf tooling.
+func UnmarshalAnyJSON(r io.Reader, msg proto.Message) error {
+	var buf bytes.Buffer
+	_, err := buf.ReadFrom(r)
+	if err != nil {
+		return err
+	}
+
+	// Opaque fields are base64 strings in the standard mapping but objects in
+	// the deep format, which the standard unmarshaler rejects. Documents
+	// without opaque fields are the same in both formats.
+	protoErr := UnmarshalProtoJSON(bytes.NewReader(buf.Bytes()), msg)
+	if protoErr == nil {
+		return nil
+	}
+
+	msg.Reset()
+	return DeepUnmarshalJSON(bytes.NewReader(buf.Bytes()), msg)
+}
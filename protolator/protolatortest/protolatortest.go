@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package protolatortest provides helpers for verifying that messages,
+// including messages handled by custom decorators, are encoded to and decoded
+// from JSON by protolator without loss.
+package protolatortest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator"
+)
+
+// CheckRoundTrip encodes msg to JSON, decodes the JSON into a new message of
+// the same type and encodes that message again. It returns an error if the two
+// JSON documents differ.
+//
+// Note, the decoded message is not compared with msg directly because binary
+// proto marshaling is nondeterministic, so the nested opaque bytes may differ
+// even though the messages are equivalent.
+func CheckRoundTrip(msg proto.Message) error {
+	var encoded bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&encoded, msg); err != nil {
+		return fmt.Errorf("encoding message: %v", err)
+	}
+
+	return checkDecodeStable(msg, encoded.Bytes())
+}
+
+// CheckGolden encodes msg to JSON and returns an error if the result differs
+// from the golden JSON document. The golden document must also decode into a
+// message which encodes back to the same document.
+func CheckGolden(msg proto.Message, golden []byte) error {
+	var encoded bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&encoded, msg); err != nil {
+		return fmt.Errorf("encoding message: %v", err)
+	}
+
+	if err := compareJSON(encoded.Bytes(), golden); err != nil {
+		return fmt.Errorf("encoded message does not match golden: %v", err)
+	}
+
+	return checkDecodeStable(msg, golden)
+}
+
+// CheckGoldenFile behaves like CheckGolden with the golden JSON document read
+// from the file at path.
+func CheckGoldenFile(msg proto.Message, path string) error {
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading golden file: %v", err)
+	}
+
+	return CheckGolden(msg, golden)
+}
+
+// WriteGoldenFile encodes msg to JSON and writes it to the file at path. It is
+// intended to regenerate golden files after an intentional change.
+func WriteGoldenFile(msg proto.Message, path string) error {
+	var encoded bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&encoded, msg); err != nil {
+		return fmt.Errorf("encoding message: %v", err)
+	}
+
+	return ioutil.WriteFile(path, encoded.Bytes(), 0644)
+}
+
+// AssertRoundTrip fails the test if CheckRoundTrip returns an error.
+func AssertRoundTrip(t testing.TB, msg proto.Message) {
+	t.Helper()
+	if err := CheckRoundTrip(msg); err != nil {
+		t.Fatalf("round trip of %T is not stable: %v", msg, err)
+	}
+}
+
+// AssertGoldenFile fails the test if CheckGoldenFile returns an error.
+func AssertGoldenFile(t testing.TB, msg proto.Message, path string) {
+	t.Helper()
+	if err := CheckGoldenFile(msg, path); err != nil {
+		t.Fatalf("golden file %s check of %T failed: %v", path, msg, err)
+	}
+}
+
+// checkDecodeStable decodes doc into a new message of the same type as msg
+// and verifies that it encodes back to an equivalent document.
+func checkDecodeStable(msg proto.Message, doc []byte) error {
+	decoded := proto.Clone(msg)
+	decoded.Reset()
+	if err := protolator.DeepUnmarshalJSON(bytes.NewReader(doc), decoded); err != nil {
+		return fmt.Errorf("decoding message: %v", err)
+	}
+
+	var reencoded bytes.Buffer
+	if err := protolator.DeepMarshalJSON(&reencoded, decoded); err != nil {
+		return fmt.Errorf("re-encoding message: %v", err)
+	}
+
+	if err := compareJSON(reencoded.Bytes(), doc); err != nil {
+		return fmt.Errorf("re-encoded message differs: %v", err)
+	}
+
+	return nil
+}
+
+func compareJSON(actual, expected []byte) error {
+	var a, e interface{}
+	if err := json.Unmarshal(actual, &a); err != nil {
+		return fmt.Errorf("invalid JSON: %v", err)
+	}
+	if err := json.Unmarshal(expected, &e); err != nil {
+		return fmt.Errorf("invalid expected JSON: %v", err)
+	}
+
+	if !reflect.DeepEqual(a, e) {
+		return fmt.Errorf("got\n%s\nexpected\n%s", actual, expected)
+	}
+
+	return nil
+}
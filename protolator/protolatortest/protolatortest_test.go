@@ -0,0 +1,94 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protolatortest_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SmartBFT-Go/fabric-protos-go/v2/msp"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-config/protolator/protolatortest"
+	. "github.com/onsi/gomega"
+)
+
+func samplePrincipal() *msp.MSPPrincipal {
+	return &msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ROLE,
+		Principal: protoMarshalOrPanic(&msp.MSPRole{
+			MspIdentifier: "Org1MSP",
+			Role:          msp.MSPRole_ADMIN,
+		}),
+	}
+}
+
+func TestCheckRoundTrip(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	err := protolatortest.CheckRoundTrip(samplePrincipal())
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	err = protolatortest.CheckRoundTrip(&msp.MSPPrincipal{
+		PrincipalClassification: msp.MSPPrincipal_ROLE,
+		Principal:               []byte("garbage"),
+	})
+	gt.Expect(err).To(MatchError(ContainSubstring("encoding message")))
+
+	protolatortest.AssertRoundTrip(t, samplePrincipal())
+}
+
+func TestCheckGolden(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	golden := []byte(`{
+		"principal": {
+			"msp_identifier": "Org1MSP",
+			"role": "ADMIN"
+		},
+		"principal_classification": "ROLE"
+	}`)
+
+	err := protolatortest.CheckGolden(samplePrincipal(), golden)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	other := samplePrincipal()
+	other.Principal = protoMarshalOrPanic(&msp.MSPRole{MspIdentifier: "Org2MSP"})
+	err = protolatortest.CheckGolden(other, golden)
+	gt.Expect(err).To(MatchError(ContainSubstring("encoded message does not match golden")))
+
+	err = protolatortest.CheckGolden(samplePrincipal(), []byte("{"))
+	gt.Expect(err).To(MatchError(ContainSubstring("invalid expected JSON")))
+}
+
+func TestGoldenFile(t *testing.T) {
+	gt := NewGomegaWithT(t)
+
+	dir, err := ioutil.TempDir("", "protolatortest")
+	gt.Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "principal.json")
+
+	err = protolatortest.CheckGoldenFile(samplePrincipal(), path)
+	gt.Expect(err).To(MatchError(ContainSubstring("reading golden file")))
+
+	err = protolatortest.WriteGoldenFile(samplePrincipal(), path)
+	gt.Expect(err).NotTo(HaveOccurred())
+
+	protolatortest.AssertGoldenFile(t, samplePrincipal(), path)
+}
+
+func protoMarshalOrPanic(pb proto.Message) []byte {
+	data, err := proto.Marshal(pb)
+	if err != nil {
+		panic(err)
+	}
+
+	return data
+}
@@ -0,0 +1,251 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protolator
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/golang/protobuf/proto"
+)
+
+// RedactionMode selects how DeepMarshalRedactedJSON hides private data.
+type RedactionMode int
+
+const (
+	// RedactPrivateData replaces private data with RedactedValue.
+	RedactPrivateData RedactionMode = iota
+
+	// HashPrivateData replaces private data with its base64 encoded SHA-256
+	// hash. Private keys and values are hashed as in the hashed read-write
+	// sets of the collections of a transaction, so that auditors can match
+	// private writes against the block without seeing them.
+	HashPrivateData
+)
+
+// RedactedValue replaces the private data redacted by RedactPrivateData.
+const RedactedValue = "REDACTED"
+
+// DeepMarshalRedactedJSON marshals msg to w as JSON like DeepMarshalJSON, but
+// hides the private data of the decoded proposals and read-write sets so that
+// the output can be shared outside of the collection members: the values of
+// the transient map of chaincode proposal payloads, and the keys and values
+// of the private read-write sets of collections. Private read-write sets
+// which are not decoded, such as those of a data model other than KV, are
+// redacted as a whole. The output cannot be unmarshaled back into the
+// original message.
+func DeepMarshalRedactedJSON(w io.Writer, msg proto.Message, mode RedactionMode, opts ...Option) error {
+	root, err := recursivelyCreateTreeFromMessage(msg, newOptions(opts))
+	if err != nil {
+		return err
+	}
+
+	r := &redactor{mode: mode}
+	err = r.redactTree(root)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "\t")
+	return encoder.Encode(root)
+}
+
+type redactor struct {
+	mode RedactionMode
+}
+
+// redactTree walks a tree created by recursivelyCreateTreeFromMessage and
+// redacts the transient maps and private read-write sets found in it.
+func (r *redactor) redactTree(node interface{}) error {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for key, value := range n {
+			var err error
+			switch key {
+			case "TransientMap":
+				err = r.redactTransientMap(value)
+			case "collection_pvt_rwset":
+				err = r.redactCollectionPvtRWSets(value)
+			default:
+				err = r.redactTree(value)
+			}
+			if err != nil {
+				return fmt.Errorf("redacting %s: %v", key, err)
+			}
+		}
+	case []interface{}:
+		for _, value := range n {
+			err := r.redactTree(value)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// redactTransientMap redacts the values of a transient map. Private data is
+// never left in the output, so a transient map which is not an object is an
+// error.
+func (r *redactor) redactTransientMap(node interface{}) error {
+	if node == nil {
+		return nil
+	}
+
+	transientMap, ok := node.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("expected an object but got %T", node)
+	}
+
+	for key := range transientMap {
+		err := r.redactBytes(transientMap, key)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// redactCollectionPvtRWSets redacts the KVRWSets decoded from the rwset
+// field of CollectionPvtReadWriteSet messages. An rwset which is not decoded,
+// such as that of a data model other than KV, is redacted as a whole.
+func (r *redactor) redactCollectionPvtRWSets(node interface{}) error {
+	collections, err := objectArray(node)
+	if err != nil {
+		return err
+	}
+
+	for _, collection := range collections {
+		rwset, ok := collection["rwset"].(map[string]interface{})
+		if !ok {
+			err := r.redactBytes(collection, "rwset")
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		for _, read := range objects(rwset["reads"]) {
+			r.redactString(read, "key")
+		}
+		for _, rangeQuery := range objects(rwset["range_queries_info"]) {
+			r.redactString(rangeQuery, "start_key")
+			r.redactString(rangeQuery, "end_key")
+			if rawReads, ok := rangeQuery["raw_reads"].(map[string]interface{}); ok {
+				for _, read := range objects(rawReads["kv_reads"]) {
+					r.redactString(read, "key")
+				}
+			}
+		}
+		for _, write := range objects(rwset["writes"]) {
+			r.redactString(write, "key")
+			err := r.redactBytes(write, "value")
+			if err != nil {
+				return err
+			}
+		}
+		for _, metadataWrite := range objects(rwset["metadata_writes"]) {
+			r.redactString(metadataWrite, "key")
+			for _, entry := range objects(metadataWrite["entries"]) {
+				err := r.redactBytes(entry, "value")
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// redactString redacts the non-empty string field of the object.
+func (r *redactor) redactString(object map[string]interface{}, field string) {
+	if s, ok := object[field].(string); ok && s != "" {
+		object[field] = r.redact([]byte(s))
+	}
+}
+
+// redactBytes redacts the non-empty bytes field of the object, which is base64
+// encoded in the tree.
+func (r *redactor) redactBytes(object map[string]interface{}, field string) error {
+	value, ok := object[field]
+	if !ok || value == nil {
+		return nil
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("expected base64 encoded %s but got %T", field, value)
+	}
+	if s == "" {
+		return nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("decoding %s: %v", field, err)
+	}
+	object[field] = r.redact(data)
+
+	return nil
+}
+
+func (r *redactor) redact(data []byte) string {
+	if r.mode == HashPrivateData {
+		hash := sha256.Sum256(data)
+		return base64.StdEncoding.EncodeToString(hash[:])
+	}
+
+	return RedactedValue
+}
+
+// objectArray returns the objects of a JSON array of the tree, failing if
+// the node is neither absent nor an array of objects.
+func objectArray(node interface{}) ([]map[string]interface{}, error) {
+	if node == nil {
+		return nil, nil
+	}
+
+	values, ok := node.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected an array but got %T", node)
+	}
+
+	var result []map[string]interface{}
+	for _, value := range values {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an array of objects but got %T", value)
+		}
+		result = append(result, object)
+	}
+
+	return result, nil
+}
+
+// objects returns the objects of a JSON array of the tree.
+func objects(node interface{}) []map[string]interface{} {
+	values, ok := node.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var result []map[string]interface{}
+	for _, value := range values {
+		if object, ok := value.(map[string]interface{}); ok {
+			result = append(result, object)
+		}
+	}
+
+	return result
+}
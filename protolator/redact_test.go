@@ -0,0 +1,76 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package protolator
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestRedactTreeFailsClosed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		tree        map[string]interface{}
+		expectedErr string
+	}{
+		{
+			name:        "transient map which is not an object",
+			tree:        map[string]interface{}{"TransientMap": "c2VjcmV0"},
+			expectedErr: "redacting TransientMap: expected an object but got string",
+		},
+		{
+			name:        "transient map value which is not bytes",
+			tree:        map[string]interface{}{"TransientMap": map[string]interface{}{"key": map[string]interface{}{}}},
+			expectedErr: "redacting TransientMap: expected base64 encoded key but got map[string]interface {}",
+		},
+		{
+			name:        "collections which are not an array",
+			tree:        map[string]interface{}{"collection_pvt_rwset": "c2VjcmV0"},
+			expectedErr: "redacting collection_pvt_rwset: expected an array but got string",
+		},
+		{
+			name:        "collection which is not an object",
+			tree:        map[string]interface{}{"collection_pvt_rwset": []interface{}{"c2VjcmV0"}},
+			expectedErr: "redacting collection_pvt_rwset: expected an array of objects but got string",
+		},
+		{
+			name: "rwset which is neither decoded nor bytes",
+			tree: map[string]interface{}{"collection_pvt_rwset": []interface{}{
+				map[string]interface{}{"rwset": []interface{}{"c2VjcmV0"}},
+			}},
+			expectedErr: "redacting collection_pvt_rwset: expected base64 encoded rwset but got []interface {}",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gt := NewGomegaWithT(t)
+
+			r := &redactor{mode: RedactPrivateData}
+			err := r.redactTree(tt.tree)
+			gt.Expect(err).To(MatchError(tt.expectedErr))
+		})
+	}
+}
+
+func TestRedactUndecodedRWSet(t *testing.T) {
+	t.Parallel()
+	gt := NewGomegaWithT(t)
+
+	collection := map[string]interface{}{"collection_name": "collection", "rwset": "c2VjcmV0"}
+	tree := map[string]interface{}{"collection_pvt_rwset": []interface{}{collection}}
+
+	r := &redactor{mode: RedactPrivateData}
+	err := r.redactTree(tree)
+	gt.Expect(err).NotTo(HaveOccurred())
+	gt.Expect(collection).To(Equal(map[string]interface{}{"collection_name": "collection", "rwset": RedactedValue}))
+}
@@ -17,18 +17,23 @@ limitations under the License.
 package protolator
 
 import (
+	"encoding/base64"
+	"fmt"
 	"reflect"
 
 	"github.com/golang/protobuf/proto"
 )
 
-func opaqueFrom(opaqueType func() (proto.Message, error), value interface{}, destType reflect.Type) (reflect.Value, error) {
+func opaqueFrom(opaqueType func() (proto.Message, error), value interface{}, destType reflect.Type, o *options) (reflect.Value, error) {
 	tree := value.(map[string]interface{}) // Safe, already checked
 	nMsg, err := opaqueType()
 	if err != nil {
 		return reflect.Value{}, err
 	}
-	if err := recursivelyPopulateMessageFromTree(tree, nMsg); err != nil {
+	if nMsg == nil {
+		return reflect.Value{}, fmt.Errorf("opaque field has no message type but was given a decoded value")
+	}
+	if err := recursivelyPopulateMessageFromTree(tree, nMsg, o); err != nil {
 		return reflect.Value{}, err
 	}
 	mMsg, err := MostlyDeterministicMarshal(nMsg)
@@ -38,16 +43,20 @@ func opaqueFrom(opaqueType func() (proto.Message, error), value interface{}, des
 	return reflect.ValueOf(mMsg), nil
 }
 
-func opaqueTo(opaqueType func() (proto.Message, error), value reflect.Value) (interface{}, error) {
+func opaqueTo(opaqueType func() (proto.Message, error), value reflect.Value, o *options) (interface{}, error) {
 	nMsg, err := opaqueType()
 	if err != nil {
 		return nil, err
 	}
 	mMsg := value.Interface().([]byte) // Safe, already checked
+	if nMsg == nil {
+		// The message type is not known, so leave the field opaque
+		return base64.StdEncoding.EncodeToString(mMsg), nil
+	}
 	if err = proto.Unmarshal(mMsg, nMsg); err != nil {
 		return nil, err
 	}
-	return recursivelyCreateTreeFromMessage(nMsg)
+	return recursivelyCreateTreeFromMessage(nMsg, o)
 }
 
 type staticallyOpaqueFieldFactory struct{}
@@ -61,7 +70,7 @@ func (soff staticallyOpaqueFieldFactory) Handles(msg proto.Message, fieldName st
 	return stringInSlice(fieldName, opaqueProto.StaticallyOpaqueFields())
 }
 
-func (soff staticallyOpaqueFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (soff staticallyOpaqueFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	opaqueProto := msg.(StaticallyOpaqueFieldProto) // Type checked in Handles
 
 	return &plainField{
@@ -73,10 +82,10 @@ func (soff staticallyOpaqueFieldFactory) NewProtoField(msg proto.Message, fieldN
 			value: fieldValue,
 		},
 		populateFrom: func(v interface{}, dT reflect.Type) (reflect.Value, error) {
-			return opaqueFrom(func() (proto.Message, error) { return opaqueProto.StaticallyOpaqueFieldProto(fieldName) }, v, dT)
+			return opaqueFrom(func() (proto.Message, error) { return opaqueProto.StaticallyOpaqueFieldProto(fieldName) }, v, dT, o)
 		},
 		populateTo: func(v reflect.Value) (interface{}, error) {
-			return opaqueTo(func() (proto.Message, error) { return opaqueProto.StaticallyOpaqueFieldProto(fieldName) }, v)
+			return opaqueTo(func() (proto.Message, error) { return opaqueProto.StaticallyOpaqueFieldProto(fieldName) }, v, o)
 		},
 	}, nil
 }
@@ -92,7 +101,7 @@ func (soff staticallyOpaqueMapFieldFactory) Handles(msg proto.Message, fieldName
 	return stringInSlice(fieldName, opaqueProto.StaticallyOpaqueMapFields())
 }
 
-func (soff staticallyOpaqueMapFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (soff staticallyOpaqueMapFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	opaqueProto := msg.(StaticallyOpaqueMapFieldProto) // Type checked in Handles
 
 	return &mapField{
@@ -106,12 +115,12 @@ func (soff staticallyOpaqueMapFieldFactory) NewProtoField(msg proto.Message, fie
 		populateFrom: func(key string, v interface{}, dT reflect.Type) (reflect.Value, error) {
 			return opaqueFrom(func() (proto.Message, error) {
 				return opaqueProto.StaticallyOpaqueMapFieldProto(fieldName, key)
-			}, v, dT)
+			}, v, dT, o)
 		},
 		populateTo: func(key string, v reflect.Value) (interface{}, error) {
 			return opaqueTo(func() (proto.Message, error) {
 				return opaqueProto.StaticallyOpaqueMapFieldProto(fieldName, key)
-			}, v)
+			}, v, o)
 		},
 	}, nil
 }
@@ -127,7 +136,7 @@ func (soff staticallyOpaqueSliceFieldFactory) Handles(msg proto.Message, fieldNa
 	return stringInSlice(fieldName, opaqueProto.StaticallyOpaqueSliceFields())
 }
 
-func (soff staticallyOpaqueSliceFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (soff staticallyOpaqueSliceFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	opaqueProto := msg.(StaticallyOpaqueSliceFieldProto) // Type checked in Handles
 
 	return &sliceField{
@@ -141,12 +150,12 @@ func (soff staticallyOpaqueSliceFieldFactory) NewProtoField(msg proto.Message, f
 		populateFrom: func(index int, v interface{}, dT reflect.Type) (reflect.Value, error) {
 			return opaqueFrom(func() (proto.Message, error) {
 				return opaqueProto.StaticallyOpaqueSliceFieldProto(fieldName, index)
-			}, v, dT)
+			}, v, dT, o)
 		},
 		populateTo: func(index int, v reflect.Value) (interface{}, error) {
 			return opaqueTo(func() (proto.Message, error) {
 				return opaqueProto.StaticallyOpaqueSliceFieldProto(fieldName, index)
-			}, v)
+			}, v, o)
 		},
 	}, nil
 }
@@ -17,11 +17,34 @@ limitations under the License.
 package protolator
 
 import (
+	"encoding/base64"
+	"fmt"
 	"reflect"
 
 	"github.com/golang/protobuf/proto"
 )
 
+var interfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+
+// variablyOpaqueFrom behaves like opaqueFrom, except that the field may also be
+// given in its opaque base64 encoded form. This is the case when the message
+// type of the field could not be determined while encoding, which may depend on
+// the contents of the field itself and so cannot be known while decoding.
+func variablyOpaqueFrom(opaqueType func() (proto.Message, error), value interface{}, destType reflect.Type, o *options) (reflect.Value, error) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return opaqueFrom(opaqueType, v, destType, o)
+	case string:
+		b, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return reflect.ValueOf(b), nil
+	default:
+		return reflect.Value{}, fmt.Errorf("expected map[string]interface{} or string but got %T", value)
+	}
+}
+
 type variablyOpaqueFieldFactory struct{}
 
 func (soff variablyOpaqueFieldFactory) Handles(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) bool {
@@ -33,22 +56,22 @@ func (soff variablyOpaqueFieldFactory) Handles(msg proto.Message, fieldName stri
 	return stringInSlice(fieldName, opaqueProto.VariablyOpaqueFields())
 }
 
-func (soff variablyOpaqueFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (soff variablyOpaqueFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	opaqueProto := msg.(VariablyOpaqueFieldProto) // Type checked in Handles
 
 	return &plainField{
 		baseField: baseField{
 			msg:   msg,
 			name:  fieldName,
-			fType: mapStringInterfaceType,
+			fType: interfaceType,
 			vType: bytesType,
 			value: fieldValue,
 		},
 		populateFrom: func(v interface{}, dT reflect.Type) (reflect.Value, error) {
-			return opaqueFrom(func() (proto.Message, error) { return opaqueProto.VariablyOpaqueFieldProto(fieldName) }, v, dT)
+			return variablyOpaqueFrom(func() (proto.Message, error) { return opaqueProto.VariablyOpaqueFieldProto(fieldName) }, v, dT, o)
 		},
 		populateTo: func(v reflect.Value) (interface{}, error) {
-			return opaqueTo(func() (proto.Message, error) { return opaqueProto.VariablyOpaqueFieldProto(fieldName) }, v)
+			return opaqueTo(func() (proto.Message, error) { return opaqueProto.VariablyOpaqueFieldProto(fieldName) }, v, o)
 		},
 	}, nil
 }
@@ -64,7 +87,7 @@ func (soff variablyOpaqueMapFieldFactory) Handles(msg proto.Message, fieldName s
 	return stringInSlice(fieldName, opaqueProto.VariablyOpaqueMapFields())
 }
 
-func (soff variablyOpaqueMapFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (soff variablyOpaqueMapFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	opaqueProto := msg.(VariablyOpaqueMapFieldProto) // Type checked in Handles
 
 	return &mapField{
@@ -78,12 +101,12 @@ func (soff variablyOpaqueMapFieldFactory) NewProtoField(msg proto.Message, field
 		populateFrom: func(key string, v interface{}, dT reflect.Type) (reflect.Value, error) {
 			return opaqueFrom(func() (proto.Message, error) {
 				return opaqueProto.VariablyOpaqueMapFieldProto(fieldName, key)
-			}, v, dT)
+			}, v, dT, o)
 		},
 		populateTo: func(key string, v reflect.Value) (interface{}, error) {
 			return opaqueTo(func() (proto.Message, error) {
 				return opaqueProto.VariablyOpaqueMapFieldProto(fieldName, key)
-			}, v)
+			}, v, o)
 		},
 	}, nil
 }
@@ -99,26 +122,26 @@ func (soff variablyOpaqueSliceFieldFactory) Handles(msg proto.Message, fieldName
 	return stringInSlice(fieldName, opaqueProto.VariablyOpaqueSliceFields())
 }
 
-func (soff variablyOpaqueSliceFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value) (protoField, error) {
+func (soff variablyOpaqueSliceFieldFactory) NewProtoField(msg proto.Message, fieldName string, fieldType reflect.Type, fieldValue reflect.Value, o *options) (protoField, error) {
 	opaqueProto := msg.(VariablyOpaqueSliceFieldProto) // Type checked in Handles
 
 	return &sliceField{
 		baseField: baseField{
 			msg:   msg,
 			name:  fieldName,
-			fType: mapStringInterfaceType,
+			fType: interfaceType,
 			vType: fieldType,
 			value: fieldValue,
 		},
 		populateFrom: func(index int, v interface{}, dT reflect.Type) (reflect.Value, error) {
-			return opaqueFrom(func() (proto.Message, error) {
+			return variablyOpaqueFrom(func() (proto.Message, error) {
 				return opaqueProto.VariablyOpaqueSliceFieldProto(fieldName, index)
-			}, v, dT)
+			}, v, dT, o)
 		},
 		populateTo: func(index int, v reflect.Value) (interface{}, error) {
 			return opaqueTo(func() (proto.Message, error) {
 				return opaqueProto.VariablyOpaqueSliceFieldProto(fieldName, index)
-			}, v)
+			}, v, o)
 		},
 	}, nil
 }